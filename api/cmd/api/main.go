@@ -0,0 +1,743 @@
+// Package main is the entry point for the Raisin Protect API server.
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+	"github.com/half-paul/raisin-protect/api/internal/auth"
+	"github.com/half-paul/raisin-protect/api/internal/config"
+	"github.com/half-paul/raisin-protect/api/internal/db"
+	"github.com/half-paul/raisin-protect/api/internal/handlers"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/half-paul/raisin-protect/api/internal/workers"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	// Logger
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	if os.Getenv("RP_LOG_LEVEL") == "debug" {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	} else {
+		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	}
+
+	// Config
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+
+	// JWT
+	jwtManager := auth.NewJWTManager(auth.JWTConfig{
+		Secret:        cfg.JWTSecret,
+		AccessExpiry:  cfg.JWTAccessExpiry,
+		RefreshExpiry: cfg.JWTRefreshExpiry,
+		Issuer:        cfg.JWTIssuer,
+	})
+	middleware.SetJWTManager(jwtManager)
+	handlers.SetJWTManager(jwtManager)
+	handlers.SetBcryptCost(cfg.BcryptCost)
+	log.Info().
+		Dur("access_expiry", cfg.JWTAccessExpiry).
+		Dur("refresh_expiry", cfg.JWTRefreshExpiry).
+		Msg("JWT authentication configured")
+
+	// Database
+	database, err := db.Connect(db.Config{
+		URL:          cfg.DatabaseURL,
+		MaxOpenConns: 25,
+		MaxIdleConns: 5,
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to connect to database — running in limited mode")
+	} else {
+		defer database.Close()
+		handlers.SetDB(database)
+		middleware.SetAuditDB(database.DB)
+		middleware.SetAPIKeyDB(database.DB)
+		log.Info().Msg("Database connected successfully")
+	}
+
+	// Redis
+	redisClient, err := db.ConnectRedis(cfg.RedisURL)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to connect to Redis — rate limiting may be degraded")
+	} else {
+		defer redisClient.Close()
+		handlers.SetRedis(redisClient)
+		log.Info().Msg("Redis connected successfully")
+	}
+
+	// MinIO
+	minioSvc, err := services.NewMinIOService(services.MinIOConfig{
+		Endpoint:  cfg.MinIOEndpoint,
+		AccessKey: cfg.MinIOAccessKey,
+		SecretKey: cfg.MinIOSecretKey,
+		Bucket:    cfg.MinIOBucket,
+		UseSSL:    cfg.MinIOUseSSL,
+	})
+	var evidenceStore services.ObjectStore
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to connect to MinIO — evidence uploads may be degraded")
+	} else {
+		evidenceStore = minioSvc
+		if err := minioSvc.EnsureBucket(context.Background()); err != nil {
+			log.Warn().Err(err).Msg("Failed to ensure MinIO bucket")
+		}
+		handlers.SetMinIO(minioSvc)
+		log.Info().Str("bucket", cfg.MinIOBucket).Msg("MinIO connected successfully")
+	}
+
+	// Audit export signing
+	exportSigner, err := services.NewExportSigner(cfg.ExportSigningKey)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid export signing key")
+	}
+	handlers.SetExportSigner(exportSigner)
+
+	// Malware scanner
+	var scanner services.Scanner
+	if cfg.ClamAVAddress != "" {
+		clam, err := services.NewClamAVScanner(cfg.ClamAVAddress)
+		if err != nil {
+			log.Warn().Err(err).Msg("Invalid ClamAV address — evidence will only be content-type validated")
+		} else {
+			scanner = clam
+		}
+	} else {
+		log.Warn().Msg("RP_CLAMAV_ADDRESS not set — evidence will only be content-type validated")
+	}
+	models.AllowUnscannedEvidence = cfg.AllowUnscannedEvidence
+	if scanner == nil && !cfg.AllowUnscannedEvidence {
+		log.Warn().Msg("RP_EVIDENCE_ALLOW_UNSCANNED not set — evidence downloads are blocked until a scanner is configured")
+	}
+
+	// Gin
+	if cfg.Environment == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.CORS(cfg.CORSOrigins))
+
+	// Health endpoints (public, no auth)
+	router.GET("/health", handlers.HealthCheck)
+	router.GET("/ready", handlers.ReadyCheck)
+
+	// API v1
+	v1 := router.Group("/api/v1")
+	{
+		// Public auth routes with rate limiting
+		authRoutes := v1.Group("/auth")
+		authRoutes.Use(middleware.RateLimitPublic())
+		{
+			authRoutes.POST("/register", handlers.Register)
+			authRoutes.POST("/login", handlers.Login)
+			authRoutes.POST("/refresh", handlers.RefreshToken)
+		}
+
+		// Machine ingestion (org API key auth)
+		ingest := v1.Group("/ingest")
+		ingest.Use(middleware.RateLimitPublic())
+		{
+			ingest.POST("/evidence", middleware.APIKeyRequired(models.ScopeEvidenceIngest), handlers.IngestEvidence)
+		}
+
+		// Protected routes
+		protected := v1.Group("")
+		protected.Use(middleware.AuthRequired())
+		protected.Use(middleware.RateLimitAuth())
+		{
+			// Auth (any authenticated user)
+			protected.POST("/auth/logout", handlers.Logout)
+			protected.POST("/auth/change-password", handlers.ChangePassword)
+
+			// Organizations (all roles can read; admin roles can update)
+			protected.GET("/organizations/current", handlers.GetCurrentOrganization)
+			protected.PUT("/organizations/current", middleware.RequireAdmin(), handlers.UpdateCurrentOrganization)
+
+			// Users (all roles can list/get)
+			protected.GET("/users", handlers.ListUsers)
+			protected.GET("/users/:id", handlers.GetUser)
+
+			// Users (restricted roles can create/update)
+			protected.POST("/users", middleware.RequireRoles(models.UserCreateRoles...), handlers.CreateUser)
+			protected.PUT("/users/:id", handlers.UpdateUser) // self-edit + admin handled inside handler
+
+			// User lifecycle (admin roles only)
+			protected.POST("/users/:id/deactivate", middleware.RequireAdmin(), handlers.DeactivateUser)
+			protected.POST("/users/:id/reactivate", middleware.RequireAdmin(), handlers.ReactivateUser)
+			protected.PUT("/users/:id/role", middleware.RequireAdmin(), handlers.ChangeUserRole)
+
+			// Audit log (admin + auditor)
+			protected.GET("/audit-log", middleware.RequireRoles(models.AuditViewRoles...), handlers.ListAuditLogs)
+
+			// === Sprint 2: Frameworks & Controls ===
+
+			// Framework catalog (system-level, read-only)
+			fw := protected.Group("/frameworks")
+			{
+				fw.GET("", handlers.ListFrameworks)
+				fw.GET("/:id", handlers.GetFramework)
+				fw.GET("/:id/versions/:vid", handlers.GetFrameworkVersion)
+				fw.GET("/:id/versions/:vid/requirements", handlers.ListRequirements)
+			}
+
+			// Org frameworks (per-org activation)
+			of := protected.Group("/org-frameworks")
+			{
+				of.GET("", handlers.ListOrgFrameworks)
+				of.POST("", middleware.RequireRoles(models.OrgFrameworkRoles...), handlers.ActivateFramework)
+				of.PUT("/:id", middleware.RequireRoles(models.OrgFrameworkRoles...), handlers.UpdateOrgFramework)
+				of.DELETE("/:id", middleware.RequireRoles(models.OrgFrameworkRoles...), handlers.DeactivateFramework)
+				of.GET("/:id/coverage", handlers.GetCoverage)
+				of.GET("/:id/scoping", handlers.ListScoping)
+				of.PUT("/:id/requirements/:rid/scope", middleware.RequireRoles(models.OrgFrameworkRoles...), handlers.SetScope)
+				of.DELETE("/:id/requirements/:rid/scope", middleware.RequireRoles(models.OrgFrameworkRoles...), handlers.ResetScope)
+			}
+
+			// Controls (per-org library)
+			ctrl := protected.Group("/controls")
+			{
+				ctrl.GET("", handlers.ListControls)
+				ctrl.POST("", middleware.RequireRoles(models.ControlCreateRoles...), handlers.CreateControl)
+				ctrl.GET("/stats", handlers.GetControlStats)
+				ctrl.POST("/bulk-status", middleware.RequireRoles(models.AdminRoles...), handlers.BulkControlStatus)
+				ctrl.GET("/:id", handlers.GetControl)
+				ctrl.PUT("/:id", handlers.UpdateControl) // owner check in handler
+				ctrl.PUT("/:id/owner", middleware.RequireRoles(models.AdminRoles...), handlers.ChangeControlOwner)
+				ctrl.PUT("/:id/status", middleware.RequireRoles(models.ControlStatusRoles...), handlers.ChangeControlStatus)
+				ctrl.DELETE("/:id", middleware.RequireRoles(models.AdminRoles...), handlers.DeprecateControl)
+				ctrl.GET("/:id/mappings", handlers.ListControlMappings)
+				ctrl.POST("/:id/mappings", middleware.RequireRoles(models.ControlMappingRoles...), handlers.CreateControlMappings)
+				ctrl.DELETE("/:id/mappings/:mid", middleware.RequireRoles(models.ControlMappingRoles...), handlers.DeleteControlMapping)
+
+				// Effectiveness scoring
+				ctrl.GET("/:id/effectiveness", handlers.GetControlEffectiveness)
+				ctrl.POST("/:id/effectiveness/recalculate", middleware.RequireRoles(models.ControlStatusRoles...), handlers.RecalculateControlEffectiveness)
+
+				// Inheritance (shared responsibility)
+				ctrl.GET("/:id/inheritance", handlers.GetControlInheritance)
+				ctrl.PUT("/:id/inheritance", middleware.RequireRoles(models.ControlCreateRoles...), handlers.SetControlInheritance)
+				ctrl.DELETE("/:id/inheritance", middleware.RequireRoles(models.ControlCreateRoles...), handlers.DeleteControlInheritance)
+			}
+
+			// Org API keys (CI/CD ingestion)
+			apiKeys := protected.Group("/api-keys")
+			{
+				apiKeys.GET("", middleware.RequireRoles(models.APIKeyRoles...), handlers.ListAPIKeys)
+				apiKeys.POST("", middleware.RequireRoles(models.APIKeyRoles...), handlers.CreateAPIKey)
+				apiKeys.DELETE("/:id", middleware.RequireRoles(models.APIKeyRoles...), handlers.RevokeAPIKey)
+			}
+
+			// Org-to-org control inheritance grants
+			inhGrants := protected.Group("/org-inheritance-grants")
+			{
+				inhGrants.GET("", handlers.ListInheritanceGrants)
+				inhGrants.POST("", middleware.RequireRoles(models.AdminRoles...), handlers.CreateInheritanceGrant)
+				inhGrants.DELETE("/:id", middleware.RequireRoles(models.AdminRoles...), handlers.RevokeInheritanceGrant)
+				inhGrants.GET("/:id/controls", middleware.RequireRoles(models.ControlCreateRoles...), handlers.ListInheritableControls)
+			}
+
+			// Control ownership attestation campaigns
+			attCampaigns := protected.Group("/attestation-campaigns")
+			{
+				attCampaigns.GET("", middleware.RequireRoles(models.AttestationViewRoles...), handlers.ListAttestationCampaigns)
+				attCampaigns.POST("", middleware.RequireRoles(models.AttestationCampaignRoles...), handlers.CreateAttestationCampaign)
+				attCampaigns.GET("/:id", middleware.RequireRoles(models.AttestationViewRoles...), handlers.GetAttestationCampaign)
+				attCampaigns.POST("/:id/cancel", middleware.RequireRoles(models.AttestationCampaignRoles...), handlers.CancelAttestationCampaign)
+				attCampaigns.POST("/:id/remind", middleware.RequireRoles(models.AttestationCampaignRoles...), handlers.RemindAttestations)
+			}
+
+			// Attestations (per-owner)
+			protected.GET("/attestations/pending", handlers.ListPendingAttestations)
+			protected.POST("/attestations/:id/submit", handlers.SubmitAttestation) // owner check in handler
+
+			// Mapping matrix
+			protected.GET("/mapping-matrix", handlers.GetMappingMatrix)
+
+			// === Sprint 3: Evidence Management ===
+
+			ev := protected.Group("/evidence")
+			{
+				ev.GET("", handlers.ListEvidence)
+				ev.POST("", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.CreateEvidence)
+				ev.GET("/staleness", handlers.GetStalenessAlerts)
+				ev.GET("/freshness-summary", handlers.GetFreshnessSummary)
+				ev.GET("/search", handlers.SearchEvidence)
+				ev.GET("/retention-report", middleware.RequireRoles(models.AuditViewRoles...), handlers.GetEvidenceRetentionReport)
+
+				ev.GET("/:id", handlers.GetEvidence)
+				ev.PUT("/:id", handlers.UpdateEvidence) // uploader check in handler
+				ev.DELETE("/:id", middleware.RequireRoles(models.EvidenceStatusRoles...), handlers.DeleteEvidence)
+				ev.PUT("/:id/status", middleware.RequireRoles(models.EvidenceStatusRoles...), handlers.ChangeEvidenceStatus)
+				ev.PUT("/:id/legal-hold", middleware.RequireRoles(models.EvidenceStatusRoles...), handlers.SetEvidenceLegalHold)
+
+				// Upload flow
+				ev.POST("/:id/confirm", handlers.ConfirmEvidenceUpload) // uploader check in handler
+				ev.POST("/:id/upload", handlers.GetUploadURL)          // uploader check in handler
+				ev.GET("/:id/download", handlers.GetDownloadURL)
+
+				// Versioning
+				ev.POST("/:id/versions", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.CreateEvidenceVersion)
+				ev.GET("/:id/versions", handlers.ListEvidenceVersions)
+
+				// Screenshot redaction
+				ev.POST("/:id/redactions", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.RedactEvidence)
+				ev.GET("/:id/redactions", handlers.ListEvidenceRedactions)
+
+				// Links
+				ev.GET("/:id/links", handlers.ListEvidenceLinks)
+				ev.POST("/:id/links", middleware.RequireRoles(models.EvidenceLinkRoles...), handlers.CreateEvidenceLinks)
+				ev.DELETE("/:id/links/:lid", middleware.RequireRoles(models.EvidenceLinkRoles...), handlers.DeleteEvidenceLink)
+
+				// Evaluations
+				ev.GET("/:id/evaluations", handlers.ListEvidenceEvaluations)
+				ev.POST("/:id/evaluations", middleware.RequireRoles(models.EvidenceEvalRoles...), handlers.CreateEvidenceEvaluation)
+
+				// Integrity
+				ev.GET("/:id/integrity", handlers.ListEvidenceIntegrityChecks)
+				ev.POST("/:id/integrity/verify", middleware.RequireRoles(models.EvidenceEvalRoles...), handlers.VerifyEvidenceIntegrity)
+			}
+
+			// Evidence renewal tasks
+			renewals := protected.Group("/evidence-renewal-tasks")
+			{
+				renewals.GET("", handlers.ListEvidenceRenewalTasks)
+				renewals.PUT("/:id", middleware.RequireRoles(models.EvidenceStatusRoles...), handlers.UpdateEvidenceRenewalTask)
+			}
+
+			// Evidence retention rules
+			retention := protected.Group("/evidence-retention-rules")
+			{
+				retention.GET("", middleware.RequireRoles(models.AdminRoles...), handlers.ListEvidenceRetentionRules)
+				retention.POST("", middleware.RequireRoles(models.AdminRoles...), handlers.CreateEvidenceRetentionRule)
+				retention.PUT("/:id", middleware.RequireRoles(models.AdminRoles...), handlers.UpdateEvidenceRetentionRule)
+				retention.DELETE("/:id", middleware.RequireRoles(models.AdminRoles...), handlers.DeleteEvidenceRetentionRule)
+			}
+
+			// Evidence collection jobs
+			ecj := protected.Group("/evidence-collection-jobs")
+			{
+				ecj.GET("", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.ListEvidenceCollectionJobs)
+				ecj.POST("", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.CreateEvidenceCollectionJob)
+				ecj.GET("/:id", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.GetEvidenceCollectionJob)
+				ecj.PUT("/:id", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.UpdateEvidenceCollectionJob)
+				ecj.DELETE("/:id", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.DeleteEvidenceCollectionJob)
+				ecj.POST("/:id/run", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.RunEvidenceCollectionJob)
+				ecj.POST("/:id/agent-output", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.UploadEvidenceCollectionOutput)
+				ecj.GET("/:id/runs", middleware.RequireRoles(models.EvidenceUploadRoles...), handlers.ListEvidenceCollectionRuns)
+			}
+
+			// Evidence on existing resources
+			ctrl.GET("/:id/evidence", handlers.ListControlEvidence)
+
+			// Requirements evidence
+			req := protected.Group("/requirements")
+			{
+				req.GET("/:id/evidence", handlers.ListRequirementEvidence)
+			}
+
+			// === Sprint 4: Continuous Monitoring Engine ===
+
+			// Tests (test definitions)
+			tests := protected.Group("/tests")
+			{
+				tests.GET("", handlers.ListTests)
+				tests.POST("", middleware.RequireRoles(models.TestCreateRoles...), handlers.CreateTest)
+				tests.GET("/:id", handlers.GetTest)
+				tests.PUT("/:id", middleware.RequireRoles(models.TestCreateRoles...), handlers.UpdateTest)
+				tests.PUT("/:id/status", middleware.RequireRoles(models.TestStatusRoles...), handlers.ChangeTestStatus)
+				tests.DELETE("/:id", middleware.RequireRoles(models.TestDeleteRoles...), handlers.DeleteTest)
+				tests.GET("/:id/results", handlers.ListTestResultsByTest)
+			}
+
+			// Test Runs (execution sweeps)
+			runs := protected.Group("/test-runs")
+			{
+				runs.POST("", middleware.RequireRoles(models.TestRunCreateRoles...), handlers.CreateTestRun)
+				runs.GET("", handlers.ListTestRuns)
+				runs.GET("/:id", handlers.GetTestRun)
+				runs.POST("/:id/cancel", middleware.RequireRoles(models.TestRunCancelRoles...), handlers.CancelTestRun)
+				runs.GET("/:id/results", handlers.ListTestRunResults)
+				runs.GET("/:id/results/:rid", handlers.GetTestRunResult)
+			}
+
+			// Control test results (cross-resource query)
+			ctrl.GET("/:id/test-results", handlers.ListControlTestResults)
+
+			// Alerts
+			alerts := protected.Group("/alerts")
+			{
+				alerts.GET("", handlers.ListAlerts)
+				alerts.GET("/:id", handlers.GetAlert)
+				alerts.PUT("/:id/status", middleware.RequireRoles(models.AlertStatusRoles...), handlers.ChangeAlertStatus)
+				alerts.PUT("/:id/assign", middleware.RequireRoles(models.AlertAssignRoles...), handlers.AssignAlert)
+				alerts.PUT("/:id/resolve", middleware.RequireRoles(models.AlertResolveRoles...), handlers.ResolveAlert)
+				alerts.PUT("/:id/suppress", middleware.RequireRoles(models.AlertSuppressRoles...), handlers.SuppressAlert)
+				alerts.PUT("/:id/close", middleware.RequireRoles(models.AlertSuppressRoles...), handlers.CloseAlert)
+				alerts.POST("/:id/deliver", middleware.RequireRoles(models.AlertDeliveryRoles...), handlers.RedeliverAlert)
+				alerts.POST("/test-delivery", middleware.RequireRoles(models.AlertSuppressRoles...), handlers.TestAlertDelivery)
+			}
+
+			// Alert Rules
+			rules := protected.Group("/alert-rules")
+			{
+				rules.GET("", middleware.RequireRoles(models.AlertRuleViewRoles...), handlers.ListAlertRules)
+				rules.POST("", middleware.RequireRoles(models.AlertRuleCreateRoles...), handlers.CreateAlertRule)
+				rules.GET("/:id", middleware.RequireRoles(models.AlertRuleViewRoles...), handlers.GetAlertRule)
+				rules.PUT("/:id", middleware.RequireRoles(models.AlertRuleCreateRoles...), handlers.UpdateAlertRule)
+				rules.DELETE("/:id", middleware.RequireRoles(models.AlertRuleCreateRoles...), handlers.DeleteAlertRule)
+			}
+
+			// Monitoring Dashboard
+			monitoring := protected.Group("/monitoring")
+			{
+				monitoring.GET("/heatmap", handlers.GetControlHealthHeatmap)
+				monitoring.GET("/posture", handlers.GetCompliancePosture)
+				monitoring.GET("/summary", handlers.GetMonitoringSummary)
+				monitoring.GET("/alert-queue", handlers.GetAlertQueue)
+			}
+
+			// === Sprint 5: Policy Management ===
+
+			// Policies (CRUD + status transitions)
+			policies := protected.Group("/policies")
+			{
+				policies.GET("", handlers.ListPolicies)
+				policies.POST("", middleware.RequireRoles(models.PolicyCreateRoles...), handlers.CreatePolicy)
+				policies.POST("/import", middleware.RequireRoles(models.PolicyCreateRoles...), handlers.ImportPolicies)
+				policies.GET("/search", handlers.SearchPolicies)
+				policies.GET("/stats", handlers.GetPolicyStats)
+
+				policies.GET("/:id", handlers.GetPolicy)
+				policies.PUT("/:id", handlers.UpdatePolicy) // owner check in handler
+				policies.POST("/:id/archive", middleware.RequireRoles(models.PolicyArchiveRoles...), handlers.ArchivePolicy)
+				policies.POST("/:id/submit-for-review", handlers.SubmitForReview) // owner check in handler
+				policies.POST("/:id/publish", middleware.RequireRoles(models.PolicyPublishRoles...), handlers.PublishPolicy)
+
+				// Policy Versions
+				policies.GET("/:id/versions", handlers.ListPolicyVersions)
+				policies.GET("/:id/versions/compare", handlers.CompareVersions)
+				policies.GET("/:id/versions/:version_number", handlers.GetPolicyVersion)
+				policies.GET("/:id/versions/:version_number/render", handlers.RenderPolicyVersion)
+				policies.POST("/:id/versions", handlers.CreatePolicyVersion) // owner check in handler
+
+				// Rendered documents
+				policies.GET("/:id/documents", handlers.ListPolicyDocuments)
+				policies.POST("/:id/documents", handlers.CreatePolicyDocuments) // owner check in handler
+
+				// Template Variables
+				policies.GET("/:id/variables", handlers.ListPolicyVariables)
+
+				// Policy Exceptions
+				policies.GET("/:id/exceptions", handlers.ListExceptionsForPolicy)
+				policies.POST("/:id/exceptions", handlers.CreatePolicyException)
+
+				// Policy Sign-offs
+				policies.GET("/:id/signoffs", handlers.ListPolicySignoffs)
+				policies.POST("/:id/signoffs/remind", handlers.RemindSignoffs) // owner check in handler
+				policies.POST("/:id/signoffs/:signoff_id/approve", handlers.ApproveSignoff) // signer check in handler
+				policies.POST("/:id/signoffs/:signoff_id/reject", handlers.RejectSignoff)   // signer check in handler
+				policies.POST("/:id/signoffs/:signoff_id/withdraw", handlers.WithdrawSignoff) // requester check in handler
+
+				// Policy-to-Control Mapping
+				policies.GET("/:id/controls", handlers.ListPolicyControls)
+				policies.POST("/:id/controls", handlers.LinkPolicyControl) // owner check in handler
+				policies.POST("/:id/controls/bulk", handlers.BulkLinkPolicyControls) // owner check in handler
+				policies.DELETE("/:id/controls/:control_id", handlers.UnlinkPolicyControl) // owner check in handler
+
+				// Acknowledgement campaigns
+				policies.GET("/:id/acknowledgement-campaigns", middleware.RequireRoles(models.PolicyAckViewRoles...), handlers.ListPolicyAckCampaigns)
+				policies.POST("/:id/acknowledgement-campaigns", middleware.RequireRoles(models.PolicyAckCampaignRoles...), handlers.CreatePolicyAckCampaign)
+			}
+
+			policyAckCampaigns := protected.Group("/policy-ack-campaigns")
+			{
+				policyAckCampaigns.GET("/:id", middleware.RequireRoles(models.PolicyAckViewRoles...), handlers.GetPolicyAckCampaign)
+				policyAckCampaigns.POST("/:id/cancel", middleware.RequireRoles(models.PolicyAckCampaignRoles...), handlers.CancelPolicyAckCampaign)
+				policyAckCampaigns.POST("/:id/remind", middleware.RequireRoles(models.PolicyAckCampaignRoles...), handlers.RemindPolicyAcknowledgements)
+				policyAckCampaigns.POST("/:id/evidence", middleware.RequireRoles(models.PolicyAckCampaignRoles...), handlers.GeneratePolicyAckEvidence)
+			}
+
+			// Policy acknowledgements (per-user)
+			protected.GET("/policy-acknowledgements/pending", handlers.ListPendingPolicyAcknowledgements)
+			protected.POST("/policy-acknowledgements/:id/acknowledge", handlers.AcknowledgePolicy) // assignee check in handler
+			protected.GET("/users/:id/policy-acknowledgements", handlers.ListUserPolicyAcknowledgements) // self or viewer roles in handler
+
+			// Scheduled policy reviews (owner or policy create roles checked in handler)
+			policyReviews := protected.Group("/policy-reviews")
+			{
+				policyReviews.GET("", handlers.ListPolicyReviews)
+				policyReviews.PUT("/:id", middleware.RequireRoles(models.PolicyPublishRoles...), handlers.UpdatePolicyReview)
+				policyReviews.POST("/:id/no-changes", handlers.ConfirmPolicyReview)
+				policyReviews.POST("/:id/revise", handlers.RevisePolicyReview)
+			}
+
+			// Policy exception register (approver checks in handler)
+			policyExceptions := protected.Group("/policy-exceptions")
+			{
+				policyExceptions.GET("", handlers.ListPolicyExceptions)
+				policyExceptions.GET("/:id", handlers.GetPolicyException)
+				policyExceptions.PUT("/:id", handlers.UpdatePolicyException)
+				policyExceptions.POST("/:id/approve", handlers.ApprovePolicyException)
+				policyExceptions.POST("/:id/reject", handlers.RejectPolicyException)
+				policyExceptions.POST("/:id/revoke", handlers.RevokePolicyException)
+			}
+
+			// Pending sign-offs (cross-policy, per-user)
+			protected.GET("/signoffs/pending", handlers.ListPendingSignoffs)
+
+			// Policy Templates
+			templates := protected.Group("/policy-templates")
+			{
+				templates.GET("", handlers.ListPolicyTemplates)
+				templates.POST("/:id/clone", middleware.RequireRoles(models.PolicyCreateRoles...), handlers.ClonePolicyTemplate)
+				templates.GET("/:id/variables", handlers.GetTemplateVariables)
+				templates.PUT("/:id/variables", middleware.RequireRoles(models.PolicyCreateRoles...), handlers.UpdateTemplateVariables)
+			}
+
+			// Re-render template-based drafts after org-wide variable values change
+			protected.POST("/policy-variables/regenerate", middleware.RequireRoles(models.PolicyPublishRoles...), handlers.RegeneratePolicyDrafts)
+
+			// Policy Gap Detection
+			policyGap := protected.Group("/policy-gap")
+			{
+				policyGap.GET("", middleware.RequireRoles(models.PolicyGapRoles...), handlers.GetPolicyGap)
+				policyGap.GET("/by-framework", middleware.RequireRoles(models.PolicyGapRoles...), handlers.GetPolicyGapByFramework)
+			}
+
+			// === Sprint 6: Risk Register ===
+
+			// Risks (CRUD + status transitions)
+			risks := protected.Group("/risks")
+			{
+				risks.GET("", handlers.ListRisks)
+				risks.POST("", middleware.RequireRoles(models.RiskCreateRoles...), handlers.CreateRisk)
+				risks.GET("/heat-map", handlers.GetRiskHeatMap)
+				risks.GET("/gaps", middleware.RequireRoles(models.RiskGapRoles...), handlers.GetRiskGaps)
+				risks.GET("/search", handlers.SearchRisks)
+				risks.GET("/stats", handlers.GetRiskStats)
+				risks.GET("/quantification/summary", handlers.GetRiskQuantificationSummary)
+				risks.GET("/residual-review", middleware.RequireRoles(models.RiskGapRoles...), handlers.ListResidualReview)
+
+				risks.GET("/:id", handlers.GetRisk)
+				risks.PUT("/:id", handlers.UpdateRisk) // owner check in handler
+				risks.POST("/:id/archive", middleware.RequireRoles(models.RiskArchiveRoles...), handlers.ArchiveRisk)
+				risks.PUT("/:id/status", handlers.ChangeRiskStatus) // owner + role check in handler
+
+				// Acceptance history and renewals
+				risks.GET("/:id/acceptances", handlers.ListRiskAcceptances)
+				risks.POST("/:id/acceptances", handlers.RequestRiskAcceptanceRenewal)                       // owner + role check in handler
+				risks.POST("/:id/acceptances/:acceptanceId/approve", handlers.ApproveRiskAcceptanceRenewal) // role check in handler
+				risks.POST("/:id/acceptances/:acceptanceId/reject", handlers.RejectRiskAcceptanceRenewal)   // role check in handler
+				risks.POST("/:id/recalculate", middleware.RequireRoles(models.RiskRecalcRoles...), handlers.RecalculateRiskScores)
+
+				// Risk Assessments
+				risks.GET("/:id/assessments", handlers.ListRiskAssessments)
+				risks.POST("/:id/assessments", handlers.CreateRiskAssessment) // owner + role check in handler
+
+				// Risk Treatments
+				risks.GET("/:id/treatments", handlers.ListRiskTreatments)
+				risks.POST("/:id/treatments", handlers.CreateRiskTreatment) // owner + role check in handler
+				risks.PUT("/:id/treatments/:treatment_id", handlers.UpdateRiskTreatment) // owner check in handler
+				risks.POST("/:id/treatments/:treatment_id/complete", handlers.CompleteTreatment) // owner check in handler
+
+				// Risk-to-Control Linkage
+				risks.GET("/:id/controls", handlers.ListRiskControls)
+				risks.POST("/:id/controls", handlers.LinkRiskControl) // owner + role check in handler
+				risks.PUT("/:id/controls/:control_id", handlers.UpdateRiskControl) // owner + role check in handler
+				risks.DELETE("/:id/controls/:control_id", handlers.UnlinkRiskControl) // owner + role check in handler
+
+				// Quantitative risk analysis (FAIR Monte Carlo)
+				risks.GET("/:id/quantification", handlers.GetRiskQuantification)
+				risks.PUT("/:id/quantification", handlers.SetRiskQuantification)       // owner + role check in handler
+				risks.DELETE("/:id/quantification", handlers.DeleteRiskQuantification) // owner + role check in handler
+
+				// Residual derivation
+				risks.GET("/:id/residual-derivation", handlers.GetRiskResidualDerivation)
+			}
+
+			// Risk appetite and tolerance (org-wide and per category)
+			riskAppetite := protected.Group("/risk-appetite")
+			{
+				riskAppetite.GET("", handlers.ListRiskAppetite)
+				riskAppetite.PUT("", middleware.RequireRoles(models.RiskAppetiteManageRoles...), handlers.SetRiskAppetite)
+				riskAppetite.GET("/utilization", handlers.GetRiskAppetiteUtilization)
+				riskAppetite.DELETE("/:id", middleware.RequireRoles(models.RiskAppetiteManageRoles...), handlers.DeleteRiskAppetite)
+			}
+
+			// Risk scoring methodologies (versioned per org)
+			riskMethods := protected.Group("/risk-methodologies")
+			{
+				riskMethods.GET("", handlers.ListRiskMethodologies)
+				riskMethods.POST("", middleware.RequireRoles(models.RiskMethodologyManageRoles...), handlers.CreateRiskMethodology)
+				riskMethods.GET("/active", handlers.GetActiveRiskMethodology)
+				riskMethods.GET("/:id", handlers.GetRiskMethodology)
+				riskMethods.POST("/:id/activate", middleware.RequireRoles(models.RiskMethodologyManageRoles...), handlers.ActivateRiskMethodology)
+			}
+
+			// === Sprint 7: Audit Hub ===
+
+			// Audit Request Templates (PBC list, global)
+			auditTemplates := protected.Group("/audit-request-templates")
+			{
+				auditTemplates.GET("", middleware.RequireRoles(models.AuditRequestCreateRoles...), handlers.ListAuditRequestTemplates)
+			}
+
+			// Audits (CRUD + status + auditor management)
+			audits := protected.Group("/audits")
+			{
+				audits.GET("", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.ListAudits)
+				audits.POST("", middleware.RequireRoles(models.AuditCreateRoles...), handlers.CreateAudit)
+				audits.GET("/dashboard", middleware.RequireRoles(models.AuditDashboardRoles...), handlers.GetAuditDashboard)
+				audits.GET("/export-signing-key", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.GetExportSigningKey)
+
+				audits.GET("/:id", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.GetAudit)
+				audits.PUT("/:id", middleware.RequireRoles(models.AuditCreateRoles...), handlers.UpdateAudit)
+				audits.PUT("/:id/status", middleware.RequireRoles(models.AuditCreateRoles...), handlers.ChangeAuditStatus)
+				audits.POST("/:id/auditors", middleware.RequireRoles(models.AuditCreateRoles...), handlers.AddAuditAuditor)
+				audits.DELETE("/:id/auditors/:user_id", middleware.RequireRoles(models.AuditCreateRoles...), handlers.RemoveAuditAuditor)
+
+				// Per-audit stats and readiness
+				audits.GET("/:id/stats", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.GetAuditStats)
+				audits.GET("/:id/readiness", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.GetAuditReadiness)
+
+				// Evidence exports
+				audits.GET("/:id/evidence-exports", middleware.RequireRoles(models.AuditExportRoles...), handlers.ListAuditEvidenceExports)
+				audits.POST("/:id/evidence-exports", middleware.RequireRoles(models.AuditExportRoles...), handlers.RequestAuditEvidenceExport)
+				audits.GET("/:id/evidence-exports/:eid", middleware.RequireRoles(models.AuditExportRoles...), handlers.GetAuditEvidenceExport)
+				audits.GET("/:id/evidence-exports/:eid/download", middleware.RequireRoles(models.AuditExportRoles...), handlers.GetAuditEvidenceExportDownload)
+
+				// Audit Requests (evidence request/response workflow)
+				audits.GET("/:id/requests", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.ListAuditRequests)
+				audits.GET("/:id/requests/:rid", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.GetAuditRequest)
+				audits.POST("/:id/requests", middleware.RequireRoles(models.AuditRequestCreateRoles...), handlers.CreateAuditRequest)
+				audits.PUT("/:id/requests/:rid", middleware.RequireRoles(models.AuditRequestCreateRoles...), handlers.UpdateAuditRequest)
+				audits.PUT("/:id/requests/:rid/assign", middleware.RequireRoles(models.AuditRequestAssignRoles...), handlers.AssignAuditRequest)
+				audits.PUT("/:id/requests/:rid/submit", middleware.RequireRoles(models.AuditEvidenceSubmitRoles...), handlers.SubmitAuditRequest)
+				audits.PUT("/:id/requests/:rid/review", middleware.RequireRoles(models.AuditEvidenceReviewRoles...), handlers.ReviewAuditRequest)
+				audits.PUT("/:id/requests/:rid/close", middleware.RequireRoles(models.AuditRequestCreateRoles...), handlers.CloseAuditRequest)
+				audits.POST("/:id/requests/bulk", middleware.RequireRoles(models.AuditRequestCreateRoles...), handlers.BulkCreateAuditRequests)
+				audits.POST("/:id/requests/from-template", middleware.RequireRoles(models.AuditRequestCreateRoles...), handlers.CreateFromTemplate)
+
+				// Evidence submission for requests
+				audits.GET("/:id/requests/:rid/evidence", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.ListRequestEvidence)
+				audits.POST("/:id/requests/:rid/evidence", middleware.RequireRoles(models.AuditEvidenceSubmitRoles...), handlers.SubmitRequestEvidence)
+				audits.PUT("/:id/requests/:rid/evidence/:lid/review", middleware.RequireRoles(models.AuditEvidenceReviewRoles...), handlers.ReviewRequestEvidence)
+				audits.DELETE("/:id/requests/:rid/evidence/:lid", handlers.RemoveRequestEvidence) // auth check in handler
+				audits.GET("/:id/requests/:rid/evidence-suggestions", middleware.RequireRoles(models.AuditEvidenceSubmitRoles...), handlers.ListRequestEvidenceSuggestions)
+
+				// Populations and sampling
+				audits.PUT("/:id/requests/:rid/population", middleware.RequireRoles(models.AuditEvidenceSubmitRoles...), handlers.UploadAuditPopulation)
+				audits.GET("/:id/requests/:rid/population", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.GetAuditPopulation)
+				audits.POST("/:id/requests/:rid/sample", middleware.RequireRoles(models.AuditSampleSelectRoles...), handlers.SelectAuditSample)
+				audits.GET("/:id/requests/:rid/sample", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.GetAuditSample)
+
+				// Audit Findings
+				audits.GET("/:id/findings", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.ListAuditFindings)
+				audits.GET("/:id/findings/:fid", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.GetAuditFinding)
+				audits.POST("/:id/findings", middleware.RequireRoles(models.AuditFindingCreateRoles...), handlers.CreateAuditFinding)
+				audits.PUT("/:id/findings/:fid", middleware.RequireRoles(models.AuditFindingCreateRoles...), handlers.UpdateAuditFinding)
+				audits.PUT("/:id/findings/:fid/status", handlers.ChangeFindingStatus) // role check in handler per transition
+				audits.PUT("/:id/findings/:fid/management-response", middleware.RequireRoles(models.AuditManagementResponseRoles...), handlers.SubmitManagementResponse)
+
+				// Audit Comments
+				audits.GET("/:id/comments", middleware.RequireRoles(models.AuditHubViewRoles...), handlers.ListAuditComments)
+				audits.POST("/:id/comments", middleware.RequireRoles(models.AuditCommentCreateRoles...), handlers.CreateAuditComment)
+				audits.PUT("/:id/comments/:cid", handlers.UpdateAuditComment) // author check in handler
+				audits.DELETE("/:id/comments/:cid", handlers.DeleteAuditComment) // author + admin check in handler
+			}
+		}
+	}
+
+	// Start monitoring worker (background)
+	if database != nil {
+		workerCtx, workerCancel := context.WithCancel(context.Background())
+		defer workerCancel()
+		monitoringWorker := workers.NewMonitoringWorker(database.DB, 30*time.Second)
+		go monitoringWorker.Run(workerCtx)
+		log.Info().Msg("Monitoring worker started in background")
+
+		freshnessWorker := workers.NewEvidenceFreshnessWorker(database.DB, 15*time.Minute)
+		go freshnessWorker.Run(workerCtx)
+
+		policyAckWorker := workers.NewPolicyAckWorker(database.DB, evidenceStore, time.Hour)
+		go policyAckWorker.Run(workerCtx)
+
+		policyReviewWorker := workers.NewPolicyReviewWorker(database.DB, time.Hour)
+		go policyReviewWorker.Run(workerCtx)
+
+		policyExceptionWorker := workers.NewPolicyExceptionWorker(database.DB, time.Hour)
+		go policyExceptionWorker.Run(workerCtx)
+
+		riskAppetiteWorker := workers.NewRiskAppetiteWorker(database.DB, time.Hour)
+		go riskAppetiteWorker.Run(workerCtx)
+
+		riskAcceptanceWorker := workers.NewRiskAcceptanceWorker(database.DB, time.Hour)
+		go riskAcceptanceWorker.Run(workerCtx)
+
+		if evidenceStore != nil {
+			collectionWorker := workers.NewEvidenceCollectionWorker(database.DB, evidenceStore, time.Minute)
+			go collectionWorker.Run(workerCtx)
+
+			processingWorker := workers.NewEvidenceProcessingWorker(database.DB, minioSvc, scanner, 30*time.Second)
+			go processingWorker.Run(workerCtx)
+
+			extractionWorker := workers.NewEvidenceExtractionWorker(database.DB, minioSvc, time.Minute)
+			go extractionWorker.Run(workerCtx)
+
+			retentionWorker := workers.NewEvidenceRetentionWorker(database.DB, minioSvc, time.Hour)
+			go retentionWorker.Run(workerCtx)
+
+			exportWorker := workers.NewAuditExportWorker(database.DB, minioSvc, exportSigner, 30*time.Second)
+			go exportWorker.Run(workerCtx)
+		}
+	}
+
+	// HTTP server
+	addr := ":" + cfg.Port
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      router,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+
+	go func() {
+		log.Info().Str("addr", addr).Str("env", cfg.Environment).Msg("Starting Raisin Protect API server")
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal().Err(err).Msg("Failed to start server")
+		}
+	}()
+
+	// Graceful shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Info().Msg("Shutting down server...")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Server forced to shutdown")
+	}
+	log.Info().Msg("Server stopped")
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// apiKeyRandomBytes is the entropy of a generated API key.
+const apiKeyRandomBytes = 32
+
+// apiKeyPrefixLen is how many characters of the key are kept for display.
+const apiKeyPrefixLen = 12
+
+// GenerateAPIKey creates a random API key with the given prefix. It returns the raw key
+// (shown to the user once), a short display prefix, and the SHA-256 hash to store.
+func GenerateAPIKey(prefix string) (raw, displayPrefix, hash string, err error) {
+	b := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	raw = prefix + base64.RawURLEncoding.EncodeToString(b)
+	return raw, raw[:apiKeyPrefixLen], HashAPIKey(raw), nil
+}
+
+// HashAPIKey returns the hex SHA-256 of a raw API key.
+func HashAPIKey(raw string) string {
+	h := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(h[:])
+}
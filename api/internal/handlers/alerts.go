@@ -63,6 +63,11 @@ func ListAlerts(c *gin.Context) {
 		args = append(args, v)
 		argN++
 	}
+	if v := c.Query("evidence_id"); v != "" {
+		where = append(where, fmt.Sprintf("a.evidence_artifact_id = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
 	if v := c.Query("assigned_to"); v != "" {
 		if v == "unassigned" {
 			where = append(where, "a.assigned_to IS NULL")
@@ -147,7 +152,8 @@ func ListAlerts(c *gin.Context) {
 	results := []gin.H{}
 	for rows.Next() {
 		var (
-			aID, aTitle, aSeverity, aStatus, controlID, controlIdentifier, controlTitle string
+			aID, aTitle, aSeverity, aStatus                                              string
+			controlID, controlIdentifier, controlTitle                                   *string
 			aDescription                                                                 *string
 			alertNumber                                                                  int
 			testID, testIdentifier, testTitle                                            *string
@@ -174,11 +180,7 @@ func ListAlerts(c *gin.Context) {
 			"description":  aDescription,
 			"severity":     aSeverity,
 			"status":       aStatus,
-			"control": gin.H{
-				"id":         controlID,
-				"identifier": controlIdentifier,
-				"title":      controlTitle,
-			},
+			"control":      nil,
 			"assigned_to":  assignedTo,
 			"sla_deadline":  slaDeadline,
 			"sla_breached":  slaBreached,
@@ -186,6 +188,14 @@ func ListAlerts(c *gin.Context) {
 			"updated_at":    updatedAt,
 		}
 
+		// Risk appetite alerts on risks without linked controls have no control
+		if controlID != nil {
+			item["control"] = gin.H{
+				"id":         *controlID,
+				"identifier": *controlIdentifier,
+				"title":      *controlTitle,
+			}
+		}
 		if testID != nil {
 			item["test"] = gin.H{
 				"id":         *testID,
@@ -212,7 +222,8 @@ func GetAlert(c *gin.Context) {
 	alertID := c.Param("id")
 
 	var (
-		aID, aTitle, aSeverity, aStatus, controlID, controlIdentifier, controlTitle, controlCategory string
+		aID, aTitle, aSeverity, aStatus                                                              string
+		controlID, controlIdentifier, controlTitle, controlCategory                                  *string
 		aDescription                                                                                 *string
 		alertNumber                                                                                  int
 		testID, testIdentifier, testTitle, testType                                                  *string
@@ -280,12 +291,7 @@ func GetAlert(c *gin.Context) {
 		"description":  aDescription,
 		"severity":     aSeverity,
 		"status":       aStatus,
-		"control": gin.H{
-			"id":         controlID,
-			"identifier": controlIdentifier,
-			"title":      controlTitle,
-			"category":   controlCategory,
-		},
+		"control":      nil,
 		"test_result_id":     testResultID,
 		"assigned_to":        assignedTo,
 		"assigned_at":        assignedAt,
@@ -303,6 +309,14 @@ func GetAlert(c *gin.Context) {
 		"updated_at":         updatedAt,
 	}
 
+	if controlID != nil {
+		result["control"] = gin.H{
+			"id":         *controlID,
+			"identifier": *controlIdentifier,
+			"title":      *controlTitle,
+			"category":   *controlCategory,
+		}
+	}
 	if testID != nil {
 		result["test"] = gin.H{
 			"id":         *testID,
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/auth"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// maxAPIKeyExpiryDays caps API key lifetime at two years.
+const maxAPIKeyExpiryDays = 730
+
+// ListAPIKeys lists the org's API keys. Key material is never returned.
+func ListAPIKeys(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	rows, err := database.Query(`
+		SELECT k.id, k.name, k.key_prefix, k.scopes, k.expires_at, k.last_used_at, k.revoked_at,
+			k.created_by, COALESCE(u.first_name || ' ' || u.last_name, ''), k.created_at
+		FROM org_api_keys k
+		LEFT JOIN users u ON u.id = k.created_by
+		WHERE k.org_id = $1
+		ORDER BY k.revoked_at IS NOT NULL, k.created_at DESC
+	`, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list API keys")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []gin.H{}
+	for rows.Next() {
+		var (
+			id, name, prefix               string
+			scopes                         pq.StringArray
+			expiresAt, lastUsed, revokedAt *time.Time
+			createdByID                    *string
+			createdByName                  string
+			createdAt                      time.Time
+		)
+		if err := rows.Scan(&id, &name, &prefix, &scopes, &expiresAt, &lastUsed, &revokedAt,
+			&createdByID, &createdByName, &createdAt); err != nil {
+			log.Error().Err(err).Msg("Failed to scan API key")
+			continue
+		}
+		item := gin.H{
+			"id":           id,
+			"name":         name,
+			"key_prefix":   prefix,
+			"scopes":       []string(scopes),
+			"expires_at":   expiresAt,
+			"last_used_at": lastUsed,
+			"revoked_at":   revokedAt,
+			"created_at":   createdAt,
+		}
+		if createdByID != nil {
+			item["created_by"] = gin.H{"id": *createdByID, "name": createdByName}
+		} else {
+			item["created_by"] = nil
+		}
+		results = append(results, item)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, results))
+}
+
+// CreateAPIKey creates a scoped org API key. The raw key is returned only in this response.
+func CreateAPIKey(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+	if len(req.Name) > 255 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Name must be at most 255 characters"))
+		return
+	}
+	if len(req.Scopes) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "At least one scope is required"))
+		return
+	}
+	for _, s := range req.Scopes {
+		if !models.IsValidAPIKeyScope(s) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid scope: "+s))
+			return
+		}
+	}
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		if *req.ExpiresInDays < 1 || *req.ExpiresInDays > maxAPIKeyExpiryDays {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "expires_in_days must be between 1 and 730"))
+			return
+		}
+		exp := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		expiresAt = &exp
+	}
+
+	raw, prefix, hash, err := auth.GenerateAPIKey(models.APIKeyPrefix)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate API key")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	keyID := uuid.New().String()
+	_, err = database.Exec(`
+		INSERT INTO org_api_keys (id, org_id, name, key_prefix, key_hash, scopes, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, keyID, orgID, req.Name, prefix, hash, pq.Array(req.Scopes), expiresAt, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create API key")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "api_key.created", "api_key", &keyID, map[string]interface{}{
+		"name": req.Name, "scopes": req.Scopes, "key_prefix": prefix,
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, gin.H{
+		"id":         keyID,
+		"name":       req.Name,
+		"key":        raw,
+		"key_prefix": prefix,
+		"scopes":     req.Scopes,
+		"expires_at": expiresAt,
+	}))
+}
+
+// RevokeAPIKey revokes an org API key.
+func RevokeAPIKey(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	keyID := c.Param("id")
+
+	var revokedAt *time.Time
+	err := database.QueryRow(`SELECT revoked_at FROM org_api_keys WHERE id = $1 AND org_id = $2`,
+		keyID, orgID).Scan(&revokedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "API key not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get API key")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if revokedAt != nil {
+		c.JSON(http.StatusConflict, errorResponse("CONFLICT", "API key is already revoked"))
+		return
+	}
+
+	if _, err := database.Exec(`UPDATE org_api_keys SET revoked_at = NOW(), revoked_by = $1 WHERE id = $2`,
+		userID, keyID); err != nil {
+		log.Error().Err(err).Msg("Failed to revoke API key")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "api_key.revoked", "api_key", &keyID, nil)
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{"id": keyID, "revoked": true}))
+}
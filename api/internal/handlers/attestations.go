@@ -0,0 +1,876 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// ListAttestationCampaigns lists attestation campaigns with completion progress.
+func ListAttestationCampaigns(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	where := []string{"ac.org_id = $1"}
+	args := []interface{}{orgID}
+	argN := 2
+
+	if v := c.Query("status"); v != "" {
+		where = append(where, fmt.Sprintf("ac.status = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+
+	whereClause := joinWhere(where)
+
+	var total int
+	database.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM attestation_campaigns ac WHERE %s`, whereClause), args...).Scan(&total)
+
+	offset := (page - 1) * perPage
+	query := fmt.Sprintf(`
+		SELECT ac.id, ac.name, ac.description, ac.status, ac.due_date, ac.reminder_interval_days,
+			ac.created_by, COALESCE(u.first_name || ' ' || u.last_name, ''),
+			ac.completed_at, ac.created_at,
+			COUNT(ca.id) FILTER (WHERE ca.status <> 'cancelled'),
+			COUNT(ca.id) FILTER (WHERE ca.status = 'attested'),
+			COUNT(ca.id) FILTER (WHERE ca.status = 'exception'),
+			COUNT(ca.id) FILTER (WHERE ca.status = 'pending')
+		FROM attestation_campaigns ac
+		LEFT JOIN users u ON u.id = ac.created_by
+		LEFT JOIN control_attestations ca ON ca.campaign_id = ac.id
+		WHERE %s
+		GROUP BY ac.id, u.first_name, u.last_name
+		ORDER BY ac.created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argN, argN+1)
+	args = append(args, perPage, offset)
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list attestation campaigns")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []gin.H{}
+	for rows.Next() {
+		var (
+			id, name, status                          string
+			description                               *string
+			dueDate, completedAt                      *time.Time
+			reminderInterval                          *int
+			createdByID                               *string
+			createdByName                             string
+			createdAt                                 time.Time
+			totalCount, attested, exceptions, pending int
+		)
+		if err := rows.Scan(&id, &name, &description, &status, &dueDate, &reminderInterval,
+			&createdByID, &createdByName, &completedAt, &createdAt,
+			&totalCount, &attested, &exceptions, &pending); err != nil {
+			log.Error().Err(err).Msg("Failed to scan attestation campaign")
+			continue
+		}
+
+		item := gin.H{
+			"id":                     id,
+			"name":                   name,
+			"description":            description,
+			"status":                 status,
+			"due_date":               dueDate,
+			"reminder_interval_days": reminderInterval,
+			"completed_at":           completedAt,
+			"created_at":             createdAt,
+			"progress":               attestationProgress(totalCount, attested, exceptions, pending),
+		}
+		if createdByID != nil {
+			item["created_by"] = gin.H{"id": *createdByID, "name": createdByName}
+		} else {
+			item["created_by"] = nil
+		}
+		results = append(results, item)
+	}
+
+	c.JSON(http.StatusOK, listResponse(c, results, total, page, perPage))
+}
+
+// CreateAttestationCampaign creates a campaign and an attestation request per control owner.
+func CreateAttestationCampaign(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	var req models.CreateAttestationCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 255 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Name is required and must be at most 255 characters"))
+		return
+	}
+	if req.ReminderIntervalDays != nil && (*req.ReminderIntervalDays < 1 || *req.ReminderIntervalDays > 90) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "reminder_interval_days must be between 1 and 90"))
+		return
+	}
+	if req.Category != nil && !models.IsValidControlCategory(*req.Category) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid control category"))
+		return
+	}
+	if len(req.ControlIDs) > 500 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Maximum 500 controls per campaign"))
+		return
+	}
+
+	var dueDate *time.Time
+	if req.DueDate != nil {
+		parsed, err := time.Parse("2006-01-02", *req.DueDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "due_date must be YYYY-MM-DD"))
+			return
+		}
+		dueDate = &parsed
+	}
+
+	// Resolve controls in scope; each is attested by its owner (or secondary owner)
+	where := []string{"c.org_id = $1"}
+	args := []interface{}{orgID}
+	argN := 2
+	if len(req.ControlIDs) > 0 {
+		where = append(where, fmt.Sprintf("c.id = ANY($%d::uuid[])", argN), "c.status <> 'deprecated'")
+		args = append(args, pq.Array(req.ControlIDs))
+		argN++
+	} else {
+		where = append(where, "c.status = 'active'")
+	}
+	if req.Category != nil {
+		where = append(where, fmt.Sprintf("c.category = $%d", argN))
+		args = append(args, *req.Category)
+		argN++
+	}
+
+	rows, err := database.Query(fmt.Sprintf(`
+		SELECT c.id, c.identifier, COALESCE(c.owner_id, c.secondary_owner_id)
+		FROM controls c
+		WHERE %s
+		ORDER BY c.identifier
+	`, joinWhere(where)), args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to resolve campaign controls")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	type scopedControl struct {
+		ID, Identifier string
+		AttesterID     *string
+	}
+	var inScope []scopedControl
+	skipped := []gin.H{}
+	for rows.Next() {
+		var sc scopedControl
+		if err := rows.Scan(&sc.ID, &sc.Identifier, &sc.AttesterID); err != nil {
+			continue
+		}
+		if sc.AttesterID == nil {
+			skipped = append(skipped, gin.H{"id": sc.ID, "identifier": sc.Identifier, "reason": "no_owner"})
+			continue
+		}
+		inScope = append(inScope, sc)
+	}
+	rows.Close()
+
+	if len(inScope) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, errorResponseWithDetails("UNPROCESSABLE",
+			"No owned controls in scope for attestation", skipped))
+		return
+	}
+
+	tx, err := database.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer tx.Rollback()
+
+	campaignID := uuid.New().String()
+	_, err = tx.Exec(`
+		INSERT INTO attestation_campaigns (id, org_id, name, description, status, due_date,
+			reminder_interval_days, created_by)
+		VALUES ($1, $2, $3, $4, 'active', $5, $6, $7)
+	`, campaignID, orgID, req.Name, req.Description, dueDate, req.ReminderIntervalDays, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create attestation campaign")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	for _, sc := range inScope {
+		_, err := tx.Exec(`
+			INSERT INTO control_attestations (id, org_id, campaign_id, control_id, attester_id, status)
+			VALUES ($1, $2, $3, $4, $5, 'pending')
+		`, uuid.New().String(), orgID, campaignID, sc.ID, *sc.AttesterID)
+		if err != nil {
+			log.Error().Err(err).Str("control_id", sc.ID).Msg("Failed to create control attestation")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit attestation campaign")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "attestation_campaign.created", "attestation_campaign", &campaignID, map[string]interface{}{
+		"name": req.Name, "controls": len(inScope), "skipped": len(skipped),
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, gin.H{
+		"id":                     campaignID,
+		"name":                   req.Name,
+		"description":            req.Description,
+		"status":                 models.AttestationCampaignActive,
+		"due_date":               dueDate,
+		"reminder_interval_days": req.ReminderIntervalDays,
+		"attestations_created":   len(inScope),
+		"skipped_controls":       skipped,
+	}))
+}
+
+// GetAttestationCampaign returns a campaign with its per-control attestations.
+func GetAttestationCampaign(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	campaignID := c.Param("id")
+
+	var (
+		name, status         string
+		description          *string
+		dueDate, completedAt *time.Time
+		cancelledAt          *time.Time
+		reminderInterval     *int
+		createdAt            time.Time
+	)
+	err := database.QueryRow(`
+		SELECT name, description, status, due_date, reminder_interval_days,
+			completed_at, cancelled_at, created_at
+		FROM attestation_campaigns WHERE id = $1 AND org_id = $2
+	`, campaignID, orgID).Scan(&name, &description, &status, &dueDate, &reminderInterval,
+		&completedAt, &cancelledAt, &createdAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Attestation campaign not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get attestation campaign")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	where := []string{"ca.campaign_id = $1", "ca.org_id = $2"}
+	args := []interface{}{campaignID, orgID}
+	if v := c.Query("status"); v != "" {
+		where = append(where, "ca.status = $3")
+		args = append(args, v)
+	}
+
+	rows, err := database.Query(fmt.Sprintf(`
+		SELECT ca.id, ca.status, ca.statement, ca.supporting_artifact_ids, ca.attested_at,
+			ca.evidence_artifact_id, ca.reminder_sent_at, ca.reminder_count,
+			ctrl.id, ctrl.identifier, ctrl.title,
+			ca.attester_id, u.first_name, u.last_name
+		FROM control_attestations ca
+		JOIN controls ctrl ON ctrl.id = ca.control_id
+		LEFT JOIN users u ON u.id = ca.attester_id
+		WHERE %s
+		ORDER BY ctrl.identifier
+	`, joinWhere(where)), args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list control attestations")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	attestations := []gin.H{}
+	counts := map[string]int{}
+	for rows.Next() {
+		var (
+			id, aStatus                       string
+			statement                         *string
+			supporting                        []string
+			attestedAt, reminderSentAt        *time.Time
+			evidenceID                        *string
+			reminderCount                     int
+			ctrlID, ctrlIdentifier, ctrlTitle string
+			attesterID, attFirst, attLast     string
+		)
+		if err := rows.Scan(&id, &aStatus, &statement, pq.Array(&supporting), &attestedAt,
+			&evidenceID, &reminderSentAt, &reminderCount,
+			&ctrlID, &ctrlIdentifier, &ctrlTitle,
+			&attesterID, &attFirst, &attLast); err != nil {
+			log.Error().Err(err).Msg("Failed to scan control attestation")
+			continue
+		}
+		counts[aStatus]++
+		if supporting == nil {
+			supporting = []string{}
+		}
+		attestations = append(attestations, gin.H{
+			"id":                      id,
+			"status":                  aStatus,
+			"control":                 gin.H{"id": ctrlID, "identifier": ctrlIdentifier, "title": ctrlTitle},
+			"attester":                gin.H{"id": attesterID, "name": attFirst + " " + attLast},
+			"statement":               statement,
+			"supporting_artifact_ids": supporting,
+			"attested_at":             attestedAt,
+			"evidence_artifact_id":    evidenceID,
+			"reminder_sent_at":        reminderSentAt,
+			"reminder_count":          reminderCount,
+		})
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"id":                     campaignID,
+		"name":                   name,
+		"description":            description,
+		"status":                 status,
+		"due_date":               dueDate,
+		"reminder_interval_days": reminderInterval,
+		"completed_at":           completedAt,
+		"cancelled_at":           cancelledAt,
+		"created_at":             createdAt,
+		"progress": attestationProgress(
+			len(attestations)-counts[models.AttestationStatusCancelled],
+			counts[models.AttestationStatusAttested],
+			counts[models.AttestationStatusException],
+			counts[models.AttestationStatusPending],
+		),
+		"attestations": attestations,
+	}))
+}
+
+// CancelAttestationCampaign cancels an active campaign and its pending attestations.
+func CancelAttestationCampaign(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	campaignID := c.Param("id")
+
+	var status string
+	err := database.QueryRow(`SELECT status FROM attestation_campaigns WHERE id = $1 AND org_id = $2`,
+		campaignID, orgID).Scan(&status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Attestation campaign not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get attestation campaign")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if status != models.AttestationCampaignActive {
+		c.JSON(http.StatusConflict, errorResponse("CONFLICT", "Only active campaigns can be cancelled"))
+		return
+	}
+
+	tx, err := database.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer tx.Rollback()
+
+	tx.Exec(`UPDATE attestation_campaigns SET status = 'cancelled', cancelled_at = NOW() WHERE id = $1`, campaignID)
+	res, _ := tx.Exec(`UPDATE control_attestations SET status = 'cancelled' WHERE campaign_id = $1 AND status = 'pending'`, campaignID)
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to cancel attestation campaign")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	var cancelled int64
+	if res != nil {
+		cancelled, _ = res.RowsAffected()
+	}
+
+	middleware.LogAudit(c, "attestation_campaign.cancelled", "attestation_campaign", &campaignID, map[string]interface{}{
+		"pending_cancelled": cancelled,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"id":                     campaignID,
+		"status":                 models.AttestationCampaignCancelled,
+		"attestations_cancelled": cancelled,
+	}))
+}
+
+// RemindAttestations sends reminders to owners with pending attestations.
+func RemindAttestations(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	campaignID := c.Param("id")
+
+	var status string
+	err := database.QueryRow(`SELECT status FROM attestation_campaigns WHERE id = $1 AND org_id = $2`,
+		campaignID, orgID).Scan(&status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Attestation campaign not found"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to send reminders"))
+		return
+	}
+	if status != models.AttestationCampaignActive {
+		c.JSON(http.StatusConflict, errorResponse("CONFLICT", "Reminders can only be sent for active campaigns"))
+		return
+	}
+
+	var req models.RemindAttestationsRequest
+	c.ShouldBindJSON(&req)
+
+	where := "ca.campaign_id = $1 AND ca.org_id = $2 AND ca.status = 'pending'"
+	args := []interface{}{campaignID, orgID}
+	if len(req.AttestationIDs) > 0 {
+		where += " AND ca.id = ANY($3::uuid[])"
+		args = append(args, pq.Array(req.AttestationIDs))
+	}
+
+	rows, err := database.Query(fmt.Sprintf(`
+		SELECT ca.id, ca.attester_id, u.first_name, u.last_name, ca.reminder_sent_at, ca.reminder_count
+		FROM control_attestations ca
+		LEFT JOIN users u ON u.id = ca.attester_id
+		WHERE %s
+	`, where), args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to find pending attestations"))
+		return
+	}
+	defer rows.Close()
+
+	type pendingAttestation struct {
+		ID             string
+		AttesterID     string
+		AttesterName   string
+		ReminderSentAt *time.Time
+		ReminderCount  int
+	}
+
+	var pending []pendingAttestation
+	for rows.Next() {
+		var pa pendingAttestation
+		var first, last string
+		if err := rows.Scan(&pa.ID, &pa.AttesterID, &first, &last, &pa.ReminderSentAt, &pa.ReminderCount); err == nil {
+			pa.AttesterName = first + " " + last
+			pending = append(pending, pa)
+		}
+	}
+
+	if len(pending) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("NO_PENDING_ATTESTATIONS", "No pending attestations found"))
+		return
+	}
+
+	now := time.Now()
+	attesters := []gin.H{}
+	sent := 0
+
+	for _, pa := range pending {
+		// Rate limit: 1 reminder per 24h
+		if pa.ReminderSentAt != nil && now.Sub(*pa.ReminderSentAt) < 24*time.Hour {
+			continue
+		}
+
+		database.Exec(`
+			UPDATE control_attestations SET reminder_sent_at = $1, reminder_count = reminder_count + 1
+			WHERE id = $2
+		`, now, pa.ID)
+
+		attesters = append(attesters, gin.H{
+			"id":             pa.AttesterID,
+			"name":           pa.AttesterName,
+			"attestation_id": pa.ID,
+			"reminder_count": pa.ReminderCount + 1,
+		})
+		sent++
+	}
+
+	if sent == 0 {
+		c.JSON(http.StatusTooManyRequests, errorResponse("REMINDER_RATE_LIMITED", "Reminder already sent within the last 24 hours"))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"reminders_sent": sent,
+		"attesters":      attesters,
+	}))
+}
+
+// ListPendingAttestations lists the current user's pending attestations across campaigns.
+func ListPendingAttestations(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	var total int
+	database.QueryRow(`
+		SELECT COUNT(*) FROM control_attestations ca
+		JOIN attestation_campaigns ac ON ac.id = ca.campaign_id
+		WHERE ca.attester_id = $1 AND ca.org_id = $2 AND ca.status = 'pending' AND ac.status = 'active'
+	`, userID, orgID).Scan(&total)
+
+	offset := (page - 1) * perPage
+	rows, err := database.Query(`
+		SELECT ca.id, ac.id, ac.name, ac.due_date,
+			ctrl.id, ctrl.identifier, ctrl.title, ctrl.description,
+			ca.reminder_count, ca.created_at
+		FROM control_attestations ca
+		JOIN attestation_campaigns ac ON ac.id = ca.campaign_id
+		JOIN controls ctrl ON ctrl.id = ca.control_id
+		WHERE ca.attester_id = $1 AND ca.org_id = $2 AND ca.status = 'pending' AND ac.status = 'active'
+		ORDER BY COALESCE(ac.due_date, '2999-12-31'::date), ctrl.identifier
+		LIMIT $3 OFFSET $4
+	`, userID, orgID, perPage, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list pending attestations")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []gin.H{}
+	for rows.Next() {
+		var (
+			id, campaignID, campaignName                string
+			dueDate                                     *time.Time
+			ctrlID, ctrlIdentifier, ctrlTitle, ctrlDesc string
+			reminderCount                               int
+			createdAt                                   time.Time
+		)
+		if err := rows.Scan(&id, &campaignID, &campaignName, &dueDate,
+			&ctrlID, &ctrlIdentifier, &ctrlTitle, &ctrlDesc,
+			&reminderCount, &createdAt); err != nil {
+			continue
+		}
+
+		urgency := "on_time"
+		if dueDate != nil {
+			if dueDate.Before(time.Now()) {
+				urgency = "overdue"
+			} else if dueDate.Before(time.Now().Add(3 * 24 * time.Hour)) {
+				urgency = "due_soon"
+			}
+		}
+
+		results = append(results, gin.H{
+			"id":       id,
+			"campaign": gin.H{"id": campaignID, "name": campaignName, "due_date": dueDate},
+			"control": gin.H{
+				"id":          ctrlID,
+				"identifier":  ctrlIdentifier,
+				"title":       ctrlTitle,
+				"description": ctrlDesc,
+			},
+			"urgency":        urgency,
+			"reminder_count": reminderCount,
+			"requested_at":   createdAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, listResponse(c, results, total, page, perPage))
+}
+
+// SubmitAttestation records an owner's attestation for a control.
+// Attestations that the control operates as described are stored as evidence and linked to the control.
+func SubmitAttestation(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	attestationID := c.Param("id")
+
+	var req models.SubmitAttestationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+	if !models.IsValidAttestationResponse(req.Status) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "status must be one of: attested, exception"))
+		return
+	}
+	req.Statement = strings.TrimSpace(req.Statement)
+	if len(req.Statement) < 10 || len(req.Statement) > 10000 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Statement must be between 10 and 10000 characters"))
+		return
+	}
+	if len(req.SupportingArtifactIDs) > 20 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Maximum 20 supporting artifacts allowed"))
+		return
+	}
+
+	var (
+		status, attesterID, campaignID, campaignName, campaignStatus string
+		controlID, controlIdentifier, controlTitle                   string
+		ownerID, secondaryOwnerID                                    *string
+	)
+	err := database.QueryRow(`
+		SELECT ca.status, ca.attester_id, ac.id, ac.name, ac.status,
+			ctrl.id, ctrl.identifier, ctrl.title, ctrl.owner_id, ctrl.secondary_owner_id
+		FROM control_attestations ca
+		JOIN attestation_campaigns ac ON ac.id = ca.campaign_id
+		JOIN controls ctrl ON ctrl.id = ca.control_id
+		WHERE ca.id = $1 AND ca.org_id = $2
+	`, attestationID, orgID).Scan(&status, &attesterID, &campaignID, &campaignName, &campaignStatus,
+		&controlID, &controlIdentifier, &controlTitle, &ownerID, &secondaryOwnerID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Attestation not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get attestation")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	// Only the assigned attester or a current owner of the control may attest
+	isOwner := userID == attesterID ||
+		(ownerID != nil && *ownerID == userID) ||
+		(secondaryOwnerID != nil && *secondaryOwnerID == userID)
+	if !isOwner {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Only the control owner can submit this attestation"))
+		return
+	}
+	if status != models.AttestationStatusPending || campaignStatus != models.AttestationCampaignActive {
+		c.JSON(http.StatusConflict, errorResponse("CONFLICT", "Attestation is not pending"))
+		return
+	}
+
+	if len(req.SupportingArtifactIDs) > 0 {
+		var found int
+		database.QueryRow(`SELECT COUNT(*) FROM evidence_artifacts WHERE org_id = $1 AND id = ANY($2::uuid[])`,
+			orgID, pq.Array(req.SupportingArtifactIDs)).Scan(&found)
+		if found != len(req.SupportingArtifactIDs) {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "One or more supporting artifacts not found"))
+			return
+		}
+	} else {
+		req.SupportingArtifactIDs = []string{}
+	}
+
+	// Attesting produces an evidence record, which needs somewhere to live
+	if req.Status == models.AttestationStatusAttested && objectStore == nil {
+		c.JSON(http.StatusServiceUnavailable, errorResponse("SERVICE_UNAVAILABLE", "Storage service not available"))
+		return
+	}
+
+	now := time.Now()
+
+	tx, err := database.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer tx.Rollback()
+
+	// The evidence object is written before the transaction commits; remove it again if the
+	// attestation does not commit so no object is left without an artifact.
+	var evidenceObjectKey string
+	committed := false
+	defer func() {
+		if evidenceObjectKey != "" && !committed {
+			if err := objectStore.RemoveObject(context.Background(), evidenceObjectKey); err != nil {
+				log.Warn().Err(err).Str("object_key", evidenceObjectKey).Msg("Failed to remove uncommitted attestation evidence")
+			}
+		}
+	}()
+
+	var evidenceArtifactID *string
+	if req.Status == models.AttestationStatusAttested {
+		id, key, err := storeAttestationEvidence(c.Request.Context(), tx, objectStore, attestationEvidence{
+			OrgID:                 orgID,
+			AttestationID:         attestationID,
+			CampaignID:            campaignID,
+			CampaignName:          campaignName,
+			ControlID:             controlID,
+			ControlIdentifier:     controlIdentifier,
+			ControlTitle:          controlTitle,
+			AttestedBy:            userID,
+			AttestedAt:            now,
+			Statement:             req.Statement,
+			SupportingArtifactIDs: req.SupportingArtifactIDs,
+		})
+		evidenceObjectKey = key
+		if err != nil {
+			log.Error().Err(err).Str("attestation_id", attestationID).Msg("Failed to store attestation evidence")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to store attestation evidence"))
+			return
+		}
+		evidenceArtifactID = &id
+	}
+
+	_, err = tx.Exec(`
+		UPDATE control_attestations
+		SET status = $1, statement = $2, supporting_artifact_ids = $3, attested_by = $4,
+			attested_at = $5, evidence_artifact_id = $6
+		WHERE id = $7
+	`, req.Status, req.Statement, pq.Array(req.SupportingArtifactIDs), userID, now, evidenceArtifactID, attestationID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update attestation")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	// Close the campaign once no attestations remain pending
+	var remaining int
+	tx.QueryRow(`SELECT COUNT(*) FROM control_attestations WHERE campaign_id = $1 AND status = 'pending'`, campaignID).Scan(&remaining)
+	campaignCompleted := false
+	if remaining == 0 {
+		if _, err := tx.Exec(`UPDATE attestation_campaigns SET status = 'completed', completed_at = NOW() WHERE id = $1 AND status = 'active'`, campaignID); err == nil {
+			campaignCompleted = true
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit attestation")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	committed = true
+
+	middleware.LogAudit(c, "control_attestation.submitted", "control_attestation", &attestationID, map[string]interface{}{
+		"campaign_id": campaignID, "control_id": controlID, "status": req.Status,
+		"evidence_artifact_id": evidenceArtifactID,
+	})
+	if campaignCompleted {
+		middleware.LogAudit(c, "attestation_campaign.completed", "attestation_campaign", &campaignID, nil)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"id":                      attestationID,
+		"status":                  req.Status,
+		"control":                 gin.H{"id": controlID, "identifier": controlIdentifier, "title": controlTitle},
+		"statement":               req.Statement,
+		"supporting_artifact_ids": req.SupportingArtifactIDs,
+		"attested_at":             now,
+		"evidence_artifact_id":    evidenceArtifactID,
+		"campaign_completed":      campaignCompleted,
+	}))
+}
+
+// attestationEvidence is the content of a generated attestation evidence record.
+type attestationEvidence struct {
+	OrgID                 string    `json:"-"`
+	AttestationID         string    `json:"attestation_id"`
+	CampaignID            string    `json:"campaign_id"`
+	CampaignName          string    `json:"campaign_name"`
+	ControlID             string    `json:"control_id"`
+	ControlIdentifier     string    `json:"control_identifier"`
+	ControlTitle          string    `json:"control_title"`
+	AttestedBy            string    `json:"attested_by"`
+	AttestedAt            time.Time `json:"attested_at"`
+	Statement             string    `json:"statement"`
+	SupportingArtifactIDs []string  `json:"supporting_artifact_ids"`
+}
+
+// storeAttestationEvidence registers the attestation record as an evidence artifact linked to the
+// attested control and writes it to object storage. Returns the new artifact ID and, once the
+// object has been written, its key so the caller can remove it if tx does not commit.
+func storeAttestationEvidence(ctx context.Context, tx *sql.Tx, store services.ObjectStore, att attestationEvidence) (string, string, error) {
+	if store == nil {
+		return "", "", services.ErrNoObjectStore
+	}
+	content, err := json.MarshalIndent(att, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	artifactID := uuid.New().String()
+	fileName := fmt.Sprintf("attestation-%s.json", att.ControlIdentifier)
+	objectKey := fmt.Sprintf("%s/%s/1/%s", att.OrgID, artifactID, fileName)
+
+	expiresAt := att.AttestedAt.AddDate(0, 0, models.AttestationEvidenceFreshnessDays)
+	metadata, _ := json.Marshal(map[string]string{
+		"attestation_id": att.AttestationID,
+		"campaign_id":    att.CampaignID,
+	})
+
+	_, err = tx.Exec(`
+		INSERT INTO evidence_artifacts (id, org_id, title, description, evidence_type, status,
+			collection_method, file_name, file_size, mime_type, object_key, checksum_sha256,
+			version, is_current, collection_date, expires_at, freshness_period_days,
+			source_system, uploaded_by, tags, metadata,
+			checksum_verified_at, previous_chain_hash, chain_hash)
+		VALUES ($1, $2, $3, $4, 'attestation', 'pending_review', 'system_export', $5, $6,
+			'application/json', $7, $8, 1, TRUE, $9, $10, $11, 'attestation_campaign', $12, $13, $14,
+			NOW(), '', $15)
+	`, artifactID, att.OrgID,
+		fmt.Sprintf("Owner attestation: %s %s", att.ControlIdentifier, att.ControlTitle),
+		fmt.Sprintf("Control owner attested the control operates as described (%s)", att.CampaignName),
+		fileName, len(content), objectKey, checksum,
+		att.AttestedAt.Format("2006-01-02"), expiresAt, models.AttestationEvidenceFreshnessDays,
+		att.AttestedBy, pq.Array([]string{"attestation"}), string(metadata),
+		services.EvidenceChainHash("", artifactID, 1, checksum))
+	if err != nil {
+		return "", "", fmt.Errorf("insert attestation artifact: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO evidence_links (org_id, artifact_id, target_type, control_id, strength, linked_by, notes)
+		VALUES ($1, $2, 'control', $3, 'primary', $4, $5)
+	`, att.OrgID, artifactID, att.ControlID, att.AttestedBy, "Generated from attestation campaign "+att.CampaignName)
+	if err != nil {
+		return "", "", fmt.Errorf("link attestation artifact: %w", err)
+	}
+
+	if err := store.PutObject(ctx, objectKey, "application/json", content); err != nil {
+		return "", "", err
+	}
+	return artifactID, objectKey, nil
+}
+
+// attestationProgress summarizes campaign completion.
+func attestationProgress(total, attested, exceptions, pending int) gin.H {
+	completionPct := 0.0
+	if total > 0 {
+		completionPct = float64(int(float64(attested+exceptions)/float64(total)*1000)) / 10
+	}
+	return gin.H{
+		"total":          total,
+		"attested":       attested,
+		"exceptions":     exceptions,
+		"pending":        pending,
+		"completion_pct": completionPct,
+	}
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryObjectStore is an in-memory object store for handlers that write evidence.
+type memoryObjectStore struct {
+	objects     map[string][]byte
+	types       map[string]string
+	quarantined []string
+}
+
+func (s *memoryObjectStore) PutObject(_ context.Context, key, contentType string, data []byte) error {
+	s.objects[key] = data
+	s.types[key] = contentType
+	return nil
+}
+
+func (s *memoryObjectStore) RemoveObject(_ context.Context, key string) error {
+	delete(s.objects, key)
+	delete(s.types, key)
+	return nil
+}
+
+func (s *memoryObjectStore) OpenObject(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("object %s not found", key)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryObjectStore) QuarantineObject(_ context.Context, key string) (string, error) {
+	s.quarantined = append(s.quarantined, key)
+	return "quarantine/" + key, nil
+}
+
+// useMemoryObjectStore points handlers at an in-memory object store for the rest of the test.
+func useMemoryObjectStore(t *testing.T) *memoryObjectStore {
+	s := &memoryObjectStore{objects: map[string][]byte{}, types: map[string]string{}}
+	prev := objectStore
+	objectStore = s
+	t.Cleanup(func() { objectStore = prev })
+	return s
+}
+
+func TestCreateAttestationCampaign_Success(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/attestation-campaigns", CreateAttestationCampaign)
+
+	mock.ExpectQuery("SELECT c.id, c.identifier, COALESCE").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "identifier", "attester_id"}).
+			AddRow("c001", "CTRL-AC-001", "u002").
+			AddRow("c002", "CTRL-AC-002", nil),
+	)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO attestation_campaigns").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO control_attestations").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":     "FY26 ISO 27001 owner attestation",
+		"due_date": "2026-12-01",
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/attestation-campaigns", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "active", data["status"])
+	assert.Equal(t, float64(1), data["attestations_created"])
+	assert.Len(t, data["skipped_controls"].([]interface{}), 1)
+}
+
+func TestCreateAttestationCampaign_NoOwnedControls(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/attestation-campaigns", CreateAttestationCampaign)
+
+	mock.ExpectQuery("SELECT c.id, c.identifier, COALESCE").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "identifier", "attester_id"}).AddRow("c002", "CTRL-AC-002", nil),
+	)
+
+	body, _ := json.Marshal(map[string]interface{}{"name": "Q3 attestation"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/attestation-campaigns", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestCreateAttestationCampaign_InvalidReminderInterval(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/attestation-campaigns", CreateAttestationCampaign)
+
+	body, _ := json.Marshal(map[string]interface{}{"name": "Q3 attestation", "reminder_interval_days": 0})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/attestation-campaigns", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetAttestationCampaign_NotFound(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/attestation-campaigns/:id", GetAttestationCampaign)
+
+	mock.ExpectQuery("SELECT name, description, status").WillReturnRows(sqlmock.NewRows(nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/attestation-campaigns/nonexistent", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func attestationLookupRows(attesterID string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"status", "attester_id", "campaign_id", "campaign_name", "campaign_status",
+		"control_id", "identifier", "title", "owner_id", "secondary_owner_id",
+	}).AddRow(
+		"pending", attesterID, "ac001", "FY26 attestation", "active",
+		"c001", "CTRL-AC-001", "Multi-Factor Authentication", attesterID, nil,
+	)
+}
+
+func TestSubmitAttestation_Attested(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/attestations/:id/submit", SubmitAttestation)
+	store := useMemoryObjectStore(t)
+
+	mock.ExpectQuery("SELECT ca.status, ca.attester_id").WillReturnRows(
+		attestationLookupRows("u0000000-0000-0000-0000-000000000001"),
+	)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO evidence_artifacts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_links").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE control_attestations").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec("UPDATE attestation_campaigns SET status = 'completed'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"status":    "attested",
+		"statement": "MFA is enforced for all workforce accounts via the IdP conditional access policy.",
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/attestations/ca001/submit", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "attested", data["status"])
+	assert.NotNil(t, data["evidence_artifact_id"])
+	assert.Equal(t, true, data["campaign_completed"])
+	assert.Len(t, store.objects, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func attestRequest() *http.Request {
+	body, _ := json.Marshal(map[string]interface{}{
+		"status":    "attested",
+		"statement": "MFA is enforced for all workforce accounts via the IdP conditional access policy.",
+	})
+	req, _ := http.NewRequest("POST", "/api/v1/attestations/ca001/submit", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestSubmitAttestation_NoStorage(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/attestations/:id/submit", SubmitAttestation)
+
+	mock.ExpectQuery("SELECT ca.status, ca.attester_id").WillReturnRows(
+		attestationLookupRows("u0000000-0000-0000-0000-000000000001"),
+	)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, attestRequest())
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubmitAttestation_CommitFailureRemovesObject(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/attestations/:id/submit", SubmitAttestation)
+	store := useMemoryObjectStore(t)
+
+	mock.ExpectQuery("SELECT ca.status, ca.attester_id").WillReturnRows(
+		attestationLookupRows("u0000000-0000-0000-0000-000000000001"),
+	)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO evidence_artifacts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_links").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE control_attestations").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectCommit().WillReturnError(errors.New("serialization failure"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, attestRequest())
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, store.objects)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubmitAttestation_NotOwner(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/attestations/:id/submit", SubmitAttestation)
+
+	mock.ExpectQuery("SELECT ca.status, ca.attester_id").WillReturnRows(attestationLookupRows("u002"))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"status":    "attested",
+		"statement": "Operating as described for the period.",
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/attestations/ca001/submit", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestSubmitAttestation_InvalidStatus(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/attestations/:id/submit", SubmitAttestation)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"status":    "pending",
+		"statement": "Operating as described for the period.",
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/attestations/ca001/submit", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRemindAttestations_RateLimited(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/attestation-campaigns/:id/remind", RemindAttestations)
+
+	mock.ExpectQuery("SELECT status FROM attestation_campaigns").WillReturnRows(
+		sqlmock.NewRows([]string{"status"}).AddRow("active"),
+	)
+	recent := time.Now().Add(-2 * time.Hour)
+	mock.ExpectQuery("SELECT ca.id, ca.attester_id").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "attester_id", "first_name", "last_name", "reminder_sent_at", "reminder_count"}).
+			AddRow("ca001", "u002", "Bob", "Owner", recent, 1),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/attestation-campaigns/ac001/remind", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
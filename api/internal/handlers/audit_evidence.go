@@ -39,7 +39,7 @@ func ListRequestEvidence(c *gin.Context) {
 		       ael.submitted_by, COALESCE(sub.first_name || ' ' || sub.last_name, ''),
 		       ael.submitted_at, ael.submission_notes, ael.status,
 		       ael.reviewed_by, COALESCE(rev.first_name || ' ' || rev.last_name, ''),
-		       ael.reviewed_at, ael.review_notes
+		       ael.reviewed_at, ael.review_notes, ael.sample_item_id
 		FROM audit_evidence_links ael
 		JOIN evidence_artifacts ea ON ael.artifact_id = ea.id
 		LEFT JOIN users sub ON ael.submitted_by = sub.id
@@ -68,12 +68,13 @@ func ListRequestEvidence(c *gin.Context) {
 			revBy, revByName                  *string
 			revAt                             *time.Time
 			revNotes                          *string
+			sampleItemID                      *string
 		)
 		if err := rows.Scan(
 			&linkID, &artifactID, &artifactTitle, &fileName, &fileSize, &mimeType,
 			&evidenceType, &evStatus,
 			&subBy, &subByName, &subAt, &subNotes, &linkStatus,
-			&revBy, &revByName, &revAt, &revNotes,
+			&revBy, &revByName, &revAt, &revNotes, &sampleItemID,
 		); err != nil {
 			log.Error().Err(err).Msg("Failed to scan evidence row")
 			continue
@@ -87,6 +88,7 @@ func ListRequestEvidence(c *gin.Context) {
 			"status": linkStatus,
 			"reviewed_by": revBy, "reviewed_by_name": revByName,
 			"reviewed_at": revAt, "review_notes": revNotes,
+			"sample_item_id": sampleItemID,
 		})
 	}
 
@@ -124,6 +126,18 @@ func SubmitRequestEvidence(c *gin.Context) {
 		return
 	}
 
+	// Evidence for a sampled item must reference an item in this request's sample
+	if req.SampleItemID != nil {
+		var inSample bool
+		database.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM audit_sample_items si JOIN audit_samples s ON s.id = si.sample_id
+			WHERE si.id = $1 AND s.request_id = $2 AND s.org_id = $3)`,
+			*req.SampleItemID, requestID, orgID).Scan(&inSample)
+		if !inSample {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Sample item not found in this request's sample"))
+			return
+		}
+	}
+
 	// Verify artifact exists in same org
 	var artifactExists bool
 	database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM evidence_artifacts WHERE id = $1 AND org_id = $2)",
@@ -148,9 +162,9 @@ func SubmitRequestEvidence(c *gin.Context) {
 	_, err := database.DB.Exec(`
 		INSERT INTO audit_evidence_links (id, org_id, audit_id, request_id, artifact_id,
 		                                  submitted_by, submitted_at, submission_notes, status,
-		                                  created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'pending_review', $7, $7)
-	`, linkID, orgID, auditID, requestID, req.ArtifactID, userID, now, req.Notes)
+		                                  sample_item_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'pending_review', $9, $7, $7)
+	`, linkID, orgID, auditID, requestID, req.ArtifactID, userID, now, req.Notes, req.SampleItemID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to submit evidence")
 		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to submit evidence"))
@@ -158,7 +172,7 @@ func SubmitRequestEvidence(c *gin.Context) {
 	}
 
 	middleware.LogAudit(c, "audit_evidence.submitted", "audit_evidence_link", &linkID, map[string]interface{}{
-		"request_id": requestID, "artifact_id": req.ArtifactID,
+		"request_id": requestID, "artifact_id": req.ArtifactID, "sample_item_id": req.SampleItemID,
 	})
 
 	// Get artifact title for response
@@ -172,6 +186,7 @@ func SubmitRequestEvidence(c *gin.Context) {
 		"submitted_by":     userID,
 		"submitted_at":     now,
 		"submission_notes": req.Notes,
+		"sample_item_id":   req.SampleItemID,
 		"status":           "pending_review",
 	}))
 }
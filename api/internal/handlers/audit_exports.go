@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+var exportSigner *services.ExportSigner
+
+// SetExportSigner sets the key used to sign audit evidence exports.
+func SetExportSigner(s *services.ExportSigner) {
+	exportSigner = s
+}
+
+const auditEvidenceExportColumns = `
+	x.id, x.audit_id, x.status, x.attempts, x.requested_by,
+	COALESCE(u.first_name || ' ' || u.last_name, ''),
+	x.file_size, x.checksum_sha256, x.manifest_sha256, x.signature, x.signing_key_id,
+	x.request_count, x.artifact_count, x.files_included, x.checksum_mismatches, x.error_message,
+	x.started_at, x.completed_at, x.created_at, x.updated_at`
+
+// scanAuditEvidenceExport scans auditEvidenceExportColumns followed by any extra columns.
+func scanAuditEvidenceExport(row rowScanner, extra ...interface{}) (*models.AuditEvidenceExport, error) {
+	var x models.AuditEvidenceExport
+	dest := append([]interface{}{&x.ID, &x.AuditID, &x.Status, &x.Attempts, &x.RequestedBy, &x.RequestedByName,
+		&x.FileSize, &x.ChecksumSHA256, &x.ManifestSHA256, &x.Signature, &x.SigningKeyID,
+		&x.RequestCount, &x.ArtifactCount, &x.FilesIncluded, &x.ChecksumMismatches, &x.ErrorMessage,
+		&x.StartedAt, &x.CompletedAt, &x.CreatedAt, &x.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+// RequestAuditEvidenceExport queues a ZIP export of the audit's accepted evidence.
+// The package is built in the background; poll the export until it is completed.
+func RequestAuditEvidenceExport(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	auditID := c.Param("id")
+
+	if _, ok := checkAuditAccess(c, auditID, orgID, userID, userRole); !ok {
+		return
+	}
+
+	var accepted int
+	err := database.DB.QueryRow(`
+		SELECT COUNT(*) FROM audit_evidence_links
+		WHERE audit_id = $1 AND org_id = $2 AND status = 'accepted'
+	`, auditID, orgID).Scan(&accepted)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to count accepted audit evidence")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if accepted == 0 {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("NO_ACCEPTED_EVIDENCE", "The audit has no accepted evidence to export"))
+		return
+	}
+
+	// uq_audit_evidence_exports_in_progress turns a concurrent second request into no row.
+	x, err := scanAuditEvidenceExport(database.DB.QueryRow(fmt.Sprintf(`
+		WITH x AS (
+			INSERT INTO audit_evidence_exports (org_id, audit_id, requested_by)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (audit_id) WHERE status IN ('pending', 'building') DO NOTHING
+			RETURNING *
+		)
+		SELECT %s FROM x LEFT JOIN users u ON u.id = x.requested_by
+	`, auditEvidenceExportColumns), orgID, auditID, userID))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, errorResponse("EXPORT_IN_PROGRESS", "An export of this audit is already being built"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create audit evidence export")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "audit.evidence_export_requested", "audit", &auditID, map[string]interface{}{
+		"export_id": x.ID, "accepted_evidence": accepted,
+	})
+
+	c.JSON(http.StatusAccepted, successResponse(c, x))
+}
+
+// ListAuditEvidenceExports lists an audit's evidence exports, newest first.
+func ListAuditEvidenceExports(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	auditID := c.Param("id")
+
+	if _, ok := checkAuditAccess(c, auditID, orgID, userID, userRole); !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	var total int
+	database.DB.QueryRow(`SELECT COUNT(*) FROM audit_evidence_exports WHERE audit_id = $1 AND org_id = $2`,
+		auditID, orgID).Scan(&total)
+
+	rows, err := database.DB.Query(fmt.Sprintf(`
+		SELECT %s
+		FROM audit_evidence_exports x
+		LEFT JOIN users u ON u.id = x.requested_by
+		WHERE x.audit_id = $1 AND x.org_id = $2
+		ORDER BY x.created_at DESC
+		LIMIT $3 OFFSET $4
+	`, auditEvidenceExportColumns), auditID, orgID, perPage, (page-1)*perPage)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list audit evidence exports")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []models.AuditEvidenceExport{}
+	for rows.Next() {
+		x, err := scanAuditEvidenceExport(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to scan audit evidence export")
+			continue
+		}
+		results = append(results, *x)
+	}
+
+	c.JSON(http.StatusOK, listResponse(c, results, total, page, perPage))
+}
+
+// getAuditEvidenceExport loads one export after checking audit access. It writes the error
+// response and returns nil when the export can't be returned.
+func getAuditEvidenceExport(c *gin.Context) (*models.AuditEvidenceExport, string) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	auditID := c.Param("id")
+	exportID := c.Param("eid")
+
+	if _, ok := checkAuditAccess(c, auditID, orgID, userID, userRole); !ok {
+		return nil, ""
+	}
+
+	var objectKey sql.NullString
+	x, err := scanAuditEvidenceExport(database.DB.QueryRow(fmt.Sprintf(`
+		SELECT %s, x.object_key
+		FROM audit_evidence_exports x
+		LEFT JOIN users u ON u.id = x.requested_by
+		WHERE x.id = $1 AND x.audit_id = $2 AND x.org_id = $3
+	`, auditEvidenceExportColumns), exportID, auditID, orgID), &objectKey)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence export not found"))
+		return nil, ""
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get audit evidence export")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return nil, ""
+	}
+	return x, objectKey.String
+}
+
+// GetAuditEvidenceExport returns the status and, once built, the checksums of an export.
+func GetAuditEvidenceExport(c *gin.Context) {
+	x, _ := getAuditEvidenceExport(c)
+	if x == nil {
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, x))
+}
+
+// GetAuditEvidenceExportDownload generates a presigned download URL for a completed export.
+func GetAuditEvidenceExportDownload(c *gin.Context) {
+	x, objectKey := getAuditEvidenceExport(c)
+	if x == nil {
+		return
+	}
+
+	if x.Status != models.AuditExportCompleted {
+		c.JSON(http.StatusConflict, errorResponse("EXPORT_NOT_READY", "Export is "+x.Status))
+		return
+	}
+	if minioService == nil {
+		c.JSON(http.StatusServiceUnavailable, errorResponse("SERVICE_UNAVAILABLE", "Storage service not available"))
+		return
+	}
+
+	fileName := fmt.Sprintf("audit-evidence-%s.zip", x.CompletedAt.Format("2006-01-02"))
+	url, err := minioService.GenerateDownloadURL(objectKey, fileName)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate export download URL")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to generate download URL"))
+		return
+	}
+
+	middleware.LogAudit(c, "audit.evidence_export_downloaded", "audit", &x.AuditID, map[string]interface{}{
+		"export_id": x.ID, "checksum_sha256": x.ChecksumSHA256,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"download_url":    url,
+		"file_name":       fileName,
+		"file_size":       x.FileSize,
+		"checksum_sha256": x.ChecksumSHA256,
+		"manifest_sha256": x.ManifestSHA256,
+		"signature":       x.Signature,
+		"signing_key_id":  x.SigningKeyID,
+		"expires_in":      minioService.DownloadTTLSeconds(),
+	}))
+}
+
+// GetExportSigningKey returns the public key that verifies export manifest signatures.
+func GetExportSigningKey(c *gin.Context) {
+	if exportSigner == nil {
+		c.JSON(http.StatusServiceUnavailable, errorResponse("SERVICE_UNAVAILABLE", "Export signing is not configured"))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"algorithm":      "Ed25519",
+		"key_id":         exportSigner.KeyID(),
+		"public_key":     base64.StdEncoding.EncodeToString(exportSigner.PublicKey()),
+		"public_key_pem": exportSigner.PublicKeyPEM(),
+		"signed_file":    services.AuditExportManifestJSON,
+		"signature_file": services.AuditExportSignature,
+	}))
+}
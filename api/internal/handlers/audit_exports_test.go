@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	exportTestOrg   = "a0000000-0000-0000-0000-000000000001"
+	exportTestAudit = "aud00000-0000-0000-0000-000000000001"
+)
+
+var auditEvidenceExportCols = []string{
+	"id", "audit_id", "status", "attempts", "requested_by", "requested_by_name",
+	"file_size", "checksum_sha256", "manifest_sha256", "signature", "signing_key_id",
+	"request_count", "artifact_count", "files_included", "checksum_mismatches", "error_message",
+	"started_at", "completed_at", "created_at", "updated_at",
+}
+
+func auditEvidenceExportRows(status string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(auditEvidenceExportCols).AddRow(
+		"x001", exportTestAudit, status, 0, "u0000000-0000-0000-0000-000000000001", "Alice Admin",
+		nil, nil, nil, nil, nil,
+		0, 0, 0, 0, nil,
+		nil, nil, now, now,
+	)
+}
+
+func expectAuditAccess(mock sqlmock.Sqlmock, auditorIDs []string) {
+	mock.ExpectQuery("SELECT status, auditor_ids FROM audits").
+		WithArgs(exportTestAudit, exportTestOrg).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "auditor_ids"}).
+			AddRow("fieldwork", pq.Array(auditorIDs)))
+}
+
+func TestRequestAuditEvidenceExport_Queued(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/audits/:id/evidence-exports", RequestAuditEvidenceExport)
+
+	expectAuditAccess(mock, nil)
+	mock.ExpectQuery("SELECT COUNT").WithArgs(exportTestAudit, exportTestOrg).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	mock.ExpectQuery("INSERT INTO audit_evidence_exports").
+		WithArgs(exportTestOrg, exportTestAudit, "u0000000-0000-0000-0000-000000000001").
+		WillReturnRows(auditEvidenceExportRows("pending"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/audits/"+exportTestAudit+"/evidence-exports", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "pending", resp["data"].(map[string]interface{})["status"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRequestAuditEvidenceExport_AlreadyInProgress(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/audits/:id/evidence-exports", RequestAuditEvidenceExport)
+
+	expectAuditAccess(mock, nil)
+	mock.ExpectQuery("SELECT COUNT").WithArgs(exportTestAudit, exportTestOrg).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	mock.ExpectQuery("ON CONFLICT \\(audit_id\\) WHERE status IN").WillReturnRows(sqlmock.NewRows(nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/audits/"+exportTestAudit+"/evidence-exports", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "EXPORT_IN_PROGRESS")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRequestAuditEvidenceExport_NothingAccepted(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/audits/:id/evidence-exports", RequestAuditEvidenceExport)
+
+	expectAuditAccess(mock, nil)
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/audits/"+exportTestAudit+"/evidence-exports", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "NO_ACCEPTED_EVIDENCE")
+}
+
+func TestRequestAuditEvidenceExport_AuditorNotAssigned(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "u0000000-0000-0000-0000-000000000009")
+		c.Set(middleware.ContextKeyOrgID, exportTestOrg)
+		c.Set(middleware.ContextKeyRole, "auditor")
+		c.Next()
+	})
+	r.POST("/api/v1/audits/:id/evidence-exports", RequestAuditEvidenceExport)
+
+	expectAuditAccess(mock, []string{"u0000000-0000-0000-0000-000000000002"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/audits/"+exportTestAudit+"/evidence-exports", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAuditEvidenceExportDownload_NotReady(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/audits/:id/evidence-exports/:eid/download", GetAuditEvidenceExportDownload)
+
+	expectAuditAccess(mock, nil)
+	mock.ExpectQuery("FROM audit_evidence_exports x").WithArgs("x001", exportTestAudit, exportTestOrg).
+		WillReturnRows(sqlmock.NewRows(append(auditEvidenceExportCols, "object_key")).AddRow(
+			"x001", exportTestAudit, "building", 1, nil, "",
+			nil, nil, nil, nil, nil,
+			0, 0, 0, 0, nil,
+			time.Now(), nil, time.Now(), time.Now(), nil,
+		))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/audits/"+exportTestAudit+"/evidence-exports/x001/download", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "EXPORT_NOT_READY")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,658 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// maxPopulationFileSize caps CSV population uploads.
+const maxPopulationFileSize = 20 << 20
+
+// checkAuditRequestExists reports whether the request belongs to the audit, writing a 404 if not.
+func checkAuditRequestExists(c *gin.Context, requestID, auditID, orgID string) bool {
+	var exists bool
+	database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM audit_requests WHERE id = $1 AND audit_id = $2 AND org_id = $3)",
+		requestID, auditID, orgID).Scan(&exists)
+	if !exists {
+		c.JSON(http.StatusNotFound, errorResponse("AUDIT_REQUEST_NOT_FOUND", "Request not found"))
+	}
+	return exists
+}
+
+// UploadAuditPopulation replaces the population a request is sampled from. Accepts a CSV file as
+// multipart (fields: file, name, key_column) or a JSON item list.
+func UploadAuditPopulation(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	auditID := c.Param("id")
+	requestID := c.Param("rid")
+
+	auditStatus, ok := checkAuditAccess(c, auditID, orgID, userID, userRole)
+	if !ok {
+		return
+	}
+	if !checkAuditNotTerminal(c, auditStatus) {
+		return
+	}
+
+	var (
+		name, keyColumn string
+		sourceFile      *string
+		columns         []string
+		items           []models.PopulationItemInput
+	)
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Multipart field 'file' is required"))
+			return
+		}
+		if fileHeader.Size <= 0 || fileHeader.Size > maxPopulationFileSize {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "File size must be between 1 byte and 20MB"))
+			return
+		}
+		f, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Failed to read uploaded file"))
+			return
+		}
+		parsed, err := services.ParsePopulationCSV(f, c.PostForm("key_column"))
+		f.Close()
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("INVALID_POPULATION", err.Error()))
+			return
+		}
+		fileName := sanitizeFileName(fileHeader.Filename)
+		sourceFile = &fileName
+		name = c.DefaultPostForm("name", strings.TrimSuffix(fileName, filepath.Ext(fileName)))
+		keyColumn = parsed.KeyColumn
+		columns = parsed.Columns
+		items = parsed.Items
+	} else {
+		var req models.UploadPopulationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request: "+err.Error()))
+			return
+		}
+		if len(req.Items) == 0 || len(req.Items) > models.MaxPopulationItems {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+				fmt.Sprintf("Population must have between 1 and %d items", models.MaxPopulationItems)))
+			return
+		}
+		seen := map[string]bool{}
+		seenCol := map[string]bool{}
+		for i := range req.Items {
+			key := strings.TrimSpace(req.Items[i].Key)
+			if key == "" || len(key) > 255 {
+				c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Each item needs a key of 1-255 characters"))
+				return
+			}
+			if seen[key] {
+				c.JSON(http.StatusUnprocessableEntity, errorResponse("INVALID_POPULATION", fmt.Sprintf("Duplicate item key %q", key)))
+				return
+			}
+			seen[key] = true
+			req.Items[i].Key = key
+			for col := range req.Items[i].Attributes {
+				if !seenCol[col] {
+					seenCol[col] = true
+					columns = append(columns, col)
+				}
+			}
+		}
+		name = req.Name
+		items = req.Items
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Population"
+	}
+	if len(name) > 255 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Name must be at most 255 characters"))
+		return
+	}
+	if columns == nil {
+		columns = []string{}
+	}
+
+	if !checkAuditRequestExists(c, requestID, auditID, orgID) {
+		return
+	}
+
+	// A sample is drawn from a fixed list; replacing the population underneath it would change
+	// which items a recorded seed selects.
+	var sampled bool
+	database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM audit_samples WHERE request_id = $1 AND org_id = $2)",
+		requestID, orgID).Scan(&sampled)
+	if sampled {
+		c.JSON(http.StatusConflict, errorResponse("SAMPLE_EXISTS", "A sample has already been selected from this population"))
+		return
+	}
+
+	keys := make([]string, len(items))
+	attrs := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+		a := item.Attributes
+		if a == nil {
+			a = map[string]interface{}{}
+		}
+		b, _ := json.Marshal(a)
+		attrs[i] = string(b)
+	}
+	checksum := services.PopulationChecksum(keys)
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin population upload")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM audit_request_populations WHERE request_id = $1 AND org_id = $2", requestID, orgID); err != nil {
+		log.Error().Err(err).Msg("Failed to replace population")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	var keyCol *string
+	if keyColumn != "" {
+		keyCol = &keyColumn
+	}
+	pop := models.AuditPopulation{
+		RequestID: requestID, Name: name, SourceFileName: sourceFile, KeyColumn: keyCol,
+		Columns: columns, ItemCount: len(items), ChecksumSHA256: checksum, UploadedBy: &userID,
+	}
+	err = tx.QueryRow(`
+		INSERT INTO audit_request_populations (org_id, audit_id, request_id, name, source_file_name, key_column,
+		                                       columns, item_count, checksum_sha256, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`, orgID, auditID, requestID, name, sourceFile, keyCol, pq.Array(columns), len(items), checksum, userID).
+		Scan(&pop.ID, &pop.CreatedAt, &pop.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create population")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO audit_population_items (org_id, population_id, position, item_key, attributes)
+		SELECT $1, $2, t.position, t.item_key, t.attributes::jsonb
+		FROM unnest($3::text[], $4::text[]) WITH ORDINALITY AS t(item_key, attributes, position)
+	`, orgID, pop.ID, pq.Array(keys), pq.Array(attrs)); err != nil {
+		log.Error().Err(err).Msg("Failed to insert population items")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit population upload")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "audit_request.population_uploaded", "audit_request", &requestID, map[string]interface{}{
+		"audit_id": auditID, "population_id": pop.ID, "item_count": len(items), "checksum_sha256": checksum,
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, pop))
+}
+
+// GetAuditPopulation returns a request's population with its items, paginated.
+func GetAuditPopulation(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	auditID := c.Param("id")
+	requestID := c.Param("rid")
+
+	if _, ok := checkAuditAccess(c, auditID, orgID, userID, userRole); !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 500 {
+		perPage = 100
+	}
+
+	var pop models.AuditPopulation
+	var columns pq.StringArray
+	err := database.DB.QueryRow(`
+		SELECT id, request_id, name, source_file_name, key_column, columns, item_count,
+		       checksum_sha256, uploaded_by, created_at, updated_at
+		FROM audit_request_populations
+		WHERE request_id = $1 AND audit_id = $2 AND org_id = $3
+	`, requestID, auditID, orgID).Scan(&pop.ID, &pop.RequestID, &pop.Name, &pop.SourceFileName, &pop.KeyColumn,
+		&columns, &pop.ItemCount, &pop.ChecksumSHA256, &pop.UploadedBy, &pop.CreatedAt, &pop.UpdatedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("POPULATION_NOT_FOUND", "No population has been uploaded for this request"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get population")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	pop.Columns = columns
+
+	rows, err := database.DB.Query(`
+		SELECT id, position, item_key, attributes
+		FROM audit_population_items
+		WHERE population_id = $1
+		ORDER BY position
+		LIMIT $2 OFFSET $3
+	`, pop.ID, perPage, (page-1)*perPage)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list population items")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	items := []models.AuditPopulationItem{}
+	for rows.Next() {
+		var item models.AuditPopulationItem
+		var attrs []byte
+		if err := rows.Scan(&item.ID, &item.Position, &item.ItemKey, &attrs); err != nil {
+			log.Error().Err(err).Msg("Failed to scan population item")
+			continue
+		}
+		json.Unmarshal(attrs, &item.Attributes)
+		items = append(items, item)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"population": pop,
+		"items":      items,
+		"pagination": gin.H{"page": page, "per_page": perPage, "total": pop.ItemCount, "total_pages": (pop.ItemCount + perPage - 1) / perPage},
+	}))
+}
+
+// SelectAuditSample draws a sample from the request population. Random samples record their seed
+// (generated when not supplied) so the selection can be reproduced with RandomSampleIndexes;
+// manual samples list the chosen item keys. A sample can be redrawn until evidence is linked to it.
+func SelectAuditSample(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	auditID := c.Param("id")
+	requestID := c.Param("rid")
+
+	auditStatus, ok := checkAuditAccess(c, auditID, orgID, userID, userRole)
+	if !ok {
+		return
+	}
+	if !checkAuditNotTerminal(c, auditStatus) {
+		return
+	}
+
+	var req models.SelectSampleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request: "+err.Error()))
+		return
+	}
+
+	var manualKeys []string
+	switch req.Method {
+	case models.SampleMethodRandom:
+		if req.SampleSize < 1 || req.SampleSize > models.MaxSampleSize {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+				fmt.Sprintf("sample_size must be between 1 and %d", models.MaxSampleSize)))
+			return
+		}
+	case models.SampleMethodManual:
+		seen := map[string]bool{}
+		for _, k := range req.ItemKeys {
+			k = strings.TrimSpace(k)
+			if k == "" || seen[k] {
+				continue
+			}
+			seen[k] = true
+			manualKeys = append(manualKeys, k)
+		}
+		if len(manualKeys) == 0 || len(manualKeys) > models.MaxSampleSize {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+				fmt.Sprintf("item_keys must list between 1 and %d items", models.MaxSampleSize)))
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Method must be 'random' or 'manual'"))
+		return
+	}
+
+	if !checkAuditRequestExists(c, requestID, auditID, orgID) {
+		return
+	}
+
+	var popID, checksum string
+	var popSize int
+	err := database.DB.QueryRow(`
+		SELECT id, item_count, checksum_sha256 FROM audit_request_populations
+		WHERE request_id = $1 AND org_id = $2
+	`, requestID, orgID).Scan(&popID, &popSize, &checksum)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("POPULATION_NOT_FOUND", "Upload a population before selecting a sample"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get population for sampling")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if req.Method == models.SampleMethodRandom && req.SampleSize > popSize {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR",
+			fmt.Sprintf("sample_size cannot exceed the population size (%d)", popSize)))
+		return
+	}
+
+	var inUse bool
+	database.DB.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM audit_evidence_links ael
+		              JOIN audit_sample_items si ON si.id = ael.sample_item_id
+		              JOIN audit_samples s ON s.id = si.sample_id
+		              WHERE s.request_id = $1 AND s.org_id = $2)
+	`, requestID, orgID).Scan(&inUse)
+	if inUse {
+		c.JSON(http.StatusConflict, errorResponse("SAMPLE_IN_USE", "Evidence has already been submitted against the current sample"))
+		return
+	}
+
+	var seed *int64
+	var itemsQuery string
+	var itemsArg interface{}
+	sampleSize := len(manualKeys)
+	if req.Method == models.SampleMethodRandom {
+		if req.Seed != nil {
+			seed = req.Seed
+		} else {
+			var b [8]byte
+			if _, err := rand.Read(b[:]); err != nil {
+				log.Error().Err(err).Msg("Failed to generate sample seed")
+				c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+				return
+			}
+			s := int64(binary.BigEndian.Uint64(b[:]) >> 1)
+			seed = &s
+		}
+		sampleSize = req.SampleSize
+		positions := services.RandomSampleIndexes(popSize, sampleSize, *seed)
+		for i := range positions {
+			positions[i]++
+		}
+		itemsQuery = `
+			INSERT INTO audit_sample_items (org_id, sample_id, population_item_id, sample_order)
+			SELECT $1, $2, pi.id, t.sample_order
+			FROM unnest($3::int[]) WITH ORDINALITY AS t(position, sample_order)
+			JOIN audit_population_items pi ON pi.population_id = $4 AND pi.position = t.position`
+		itemsArg = pq.Array(positions)
+	} else {
+		itemsQuery = `
+			INSERT INTO audit_sample_items (org_id, sample_id, population_item_id, sample_order)
+			SELECT $1, $2, pi.id, t.sample_order
+			FROM unnest($3::text[]) WITH ORDINALITY AS t(item_key, sample_order)
+			JOIN audit_population_items pi ON pi.population_id = $4 AND pi.item_key = t.item_key`
+		itemsArg = pq.Array(manualKeys)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin sample selection")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM audit_samples WHERE request_id = $1 AND org_id = $2", requestID, orgID); err != nil {
+		log.Error().Err(err).Msg("Failed to replace sample")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	var sampleID string
+	err = tx.QueryRow(`
+		INSERT INTO audit_samples (org_id, audit_id, request_id, population_id, method, seed, sample_size,
+		                           population_size, population_checksum, notes, selected_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`, orgID, auditID, requestID, popID, req.Method, seed, sampleSize, popSize, checksum, req.Notes, userID).Scan(&sampleID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create sample")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	res, err := tx.Exec(itemsQuery, orgID, sampleID, itemsArg, popID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to insert sample items")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if n, _ := res.RowsAffected(); int(n) != sampleSize {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR",
+			fmt.Sprintf("%d of the selected items are not in the population", sampleSize-int(n))))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit sample selection")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "audit_request.sample_selected", "audit_request", &requestID, map[string]interface{}{
+		"audit_id": auditID, "sample_id": sampleID, "method": req.Method, "seed": seed,
+		"sample_size": sampleSize, "population_size": popSize, "population_checksum": checksum,
+	})
+
+	sample, err := loadAuditSample(requestID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load sample")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	c.JSON(http.StatusCreated, successResponse(c, sample))
+}
+
+// GetAuditSample returns a request's sample with per-item evidence status.
+func GetAuditSample(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	auditID := c.Param("id")
+	requestID := c.Param("rid")
+
+	if _, ok := checkAuditAccess(c, auditID, orgID, userID, userRole); !ok {
+		return
+	}
+	if !checkAuditRequestExists(c, requestID, auditID, orgID) {
+		return
+	}
+
+	sample, err := loadAuditSample(requestID, orgID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("SAMPLE_NOT_FOUND", "No sample has been selected for this request"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get sample")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, sample))
+}
+
+// loadAuditSample loads a request's sample and derives each item's status from its evidence links.
+func loadAuditSample(requestID, orgID string) (*models.AuditSample, error) {
+	var s models.AuditSample
+	err := database.DB.QueryRow(`
+		SELECT id, request_id, population_id, method, seed, sample_size, population_size,
+		       population_checksum, notes, selected_by, created_at
+		FROM audit_samples
+		WHERE request_id = $1 AND org_id = $2
+	`, requestID, orgID).Scan(&s.ID, &s.RequestID, &s.PopulationID, &s.Method, &s.Seed, &s.SampleSize,
+		&s.PopulationSize, &s.PopulationChecksum, &s.Notes, &s.SelectedBy, &s.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT si.id, si.sample_order, pi.id, pi.position, pi.item_key, pi.attributes,
+		       COALESCE(array_agg(ael.status::text) FILTER (WHERE ael.id IS NOT NULL), '{}')
+		FROM audit_sample_items si
+		JOIN audit_population_items pi ON pi.id = si.population_item_id
+		LEFT JOIN audit_evidence_links ael ON ael.sample_item_id = si.id
+		WHERE si.sample_id = $1
+		GROUP BY si.id, pi.id
+		ORDER BY si.sample_order
+	`, s.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	s.Items = []models.AuditSampleItem{}
+	s.Summary = map[string]int{
+		models.SampleItemRequested: 0, models.SampleItemPendingReview: 0, models.SampleItemNeedsClarification: 0,
+		models.SampleItemRejected: 0, models.SampleItemAccepted: 0,
+	}
+	for rows.Next() {
+		var item models.AuditSampleItem
+		var attrs []byte
+		var statuses pq.StringArray
+		if err := rows.Scan(&item.ID, &item.SampleOrder, &item.PopulationItemID, &item.Position, &item.ItemKey,
+			&attrs, &statuses); err != nil {
+			return nil, err
+		}
+		json.Unmarshal(attrs, &item.Attributes)
+		item.EvidenceCount = len(statuses)
+		for _, st := range statuses {
+			if st == "accepted" {
+				item.AcceptedCount++
+			}
+		}
+		item.Status = services.SampleItemStatus(statuses)
+		s.Summary[item.Status]++
+		s.Items = append(s.Items, item)
+	}
+	return &s, rows.Err()
+}
+
+// ListRequestEvidenceSuggestions suggests existing evidence for a request: current artifacts
+// linked to the request's control or requirement, ranked by how often they were accepted in
+// other audits. Artifacts collected outside the audit period are flagged for refresh.
+func ListRequestEvidenceSuggestions(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	auditID := c.Param("id")
+	requestID := c.Param("rid")
+
+	if _, ok := checkAuditAccess(c, auditID, orgID, userID, userRole); !ok {
+		return
+	}
+
+	var controlID, requirementID *string
+	var periodStart, periodEnd *time.Time
+	err := database.DB.QueryRow(`
+		SELECT ar.control_id, ar.requirement_id, a.period_start, a.period_end
+		FROM audit_requests ar
+		JOIN audits a ON a.id = ar.audit_id
+		WHERE ar.id = $1 AND ar.audit_id = $2 AND ar.org_id = $3
+	`, requestID, auditID, orgID).Scan(&controlID, &requirementID, &periodStart, &periodEnd)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("AUDIT_REQUEST_NOT_FOUND", "Request not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get request for evidence suggestions")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	results := []gin.H{}
+	if controlID == nil && requirementID == nil {
+		c.JSON(http.StatusOK, successResponse(c, results))
+		return
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT id, title, evidence_type, status, file_name, version, collection_date,
+		       via_control, via_requirement, prior_accepted_audits, last_accepted_at
+		FROM (
+			SELECT ea.id, ea.title, ea.evidence_type, ea.status, ea.file_name, ea.version, ea.collection_date,
+			       EXISTS(SELECT 1 FROM evidence_links el WHERE el.artifact_id = ea.id AND el.control_id = $4) AS via_control,
+			       EXISTS(SELECT 1 FROM evidence_links el WHERE el.artifact_id = ea.id AND el.requirement_id = $5) AS via_requirement,
+			       (SELECT COUNT(DISTINCT p.audit_id) FROM audit_evidence_links p
+			        WHERE p.artifact_id = ea.id AND p.status = 'accepted' AND p.audit_id <> $2) AS prior_accepted_audits,
+			       (SELECT MAX(p.reviewed_at) FROM audit_evidence_links p
+			        WHERE p.artifact_id = ea.id AND p.status = 'accepted' AND p.audit_id <> $2) AS last_accepted_at
+			FROM evidence_artifacts ea
+			WHERE ea.org_id = $1 AND ea.is_current = TRUE AND ea.purged_at IS NULL AND NOT ea.access_restricted
+			  AND NOT EXISTS (SELECT 1 FROM audit_evidence_links x WHERE x.request_id = $3 AND x.artifact_id = ea.id)
+		) s
+		WHERE via_control OR via_requirement
+		ORDER BY prior_accepted_audits DESC, collection_date DESC
+		LIMIT 25
+	`, orgID, auditID, requestID, controlID, requirementID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list evidence suggestions")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id, title, evidenceType, status string
+			fileName                        *string
+			version, priorAccepted          int
+			collectionDate                  time.Time
+			viaControl, viaRequirement      bool
+			lastAccepted                    *time.Time
+		)
+		if err := rows.Scan(&id, &title, &evidenceType, &status, &fileName, &version, &collectionDate,
+			&viaControl, &viaRequirement, &priorAccepted, &lastAccepted); err != nil {
+			log.Error().Err(err).Msg("Failed to scan evidence suggestion")
+			continue
+		}
+		inPeriod := (periodStart == nil || !collectionDate.Before(*periodStart)) &&
+			(periodEnd == nil || !collectionDate.After(*periodEnd))
+		matched := []string{}
+		if viaControl {
+			matched = append(matched, "control")
+		}
+		if viaRequirement {
+			matched = append(matched, "requirement")
+		}
+		results = append(results, gin.H{
+			"artifact_id": id, "title": title, "evidence_type": evidenceType, "status": status,
+			"file_name": fileName, "version": version, "collection_date": collectionDate.Format("2006-01-02"),
+			"matched_by": matched, "prior_accepted_audits": priorAccepted, "last_accepted_at": lastAccepted,
+			"collected_in_period": inPeriod,
+		})
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, results))
+}
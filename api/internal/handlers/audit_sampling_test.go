@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectSamplingAuditAccess(mock sqlmock.Sqlmock, auditors ...string) {
+	mock.ExpectQuery("SELECT status, auditor_ids FROM audits").
+		WithArgs("audit-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "auditor_ids"}).
+			AddRow("fieldwork", pq.Array(auditors)))
+}
+
+func expectRequestExists(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("req-001", "audit-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+}
+
+func sendJSON(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUploadAuditPopulation_JSON(t *testing.T) {
+	router, mock := setupAuditRouter()
+
+	expectSamplingAuditAccess(mock)
+	expectRequestExists(mock)
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM audit_samples").
+		WithArgs("req-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM audit_request_populations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("INSERT INTO audit_request_populations").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow("pop-001", time.Now(), time.Now()))
+	mock.ExpectExec("INSERT INTO audit_population_items").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	w := sendJSON(router, "PUT", "/api/v1/audits/audit-001/requests/req-001/population", map[string]interface{}{
+		"name": "Q3 change tickets",
+		"items": []map[string]interface{}{
+			{"key": "CHG-1", "attributes": map[string]string{"summary": "Patch db"}},
+			{"key": "CHG-2"},
+		},
+	})
+
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "pop-001", data["id"])
+	assert.Equal(t, float64(2), data["item_count"])
+	assert.Len(t, data["checksum_sha256"], 64)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadAuditPopulation_DuplicateKey(t *testing.T) {
+	router, mock := setupAuditRouter()
+
+	expectSamplingAuditAccess(mock)
+
+	w := sendJSON(router, "PUT", "/api/v1/audits/audit-001/requests/req-001/population", map[string]interface{}{
+		"items": []map[string]interface{}{{"key": "CHG-1"}, {"key": " CHG-1 "}},
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_POPULATION")
+}
+
+func TestUploadAuditPopulation_SampleExists(t *testing.T) {
+	router, mock := setupAuditRouter()
+
+	expectSamplingAuditAccess(mock)
+	expectRequestExists(mock)
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM audit_samples").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	w := sendJSON(router, "PUT", "/api/v1/audits/audit-001/requests/req-001/population", map[string]interface{}{
+		"items": []map[string]interface{}{{"key": "CHG-1"}},
+	})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "SAMPLE_EXISTS")
+}
+
+func TestSelectAuditSample_Random(t *testing.T) {
+	router, mock := setupAuditRouterWithRole("auditor")
+
+	expectSamplingAuditAccess(mock, "user-001")
+	expectRequestExists(mock)
+	mock.ExpectQuery("SELECT id, item_count, checksum_sha256 FROM audit_request_populations").
+		WithArgs("req-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "item_count", "checksum_sha256"}).AddRow("pop-001", 400, "abc"))
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM audit_evidence_links").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM audit_samples").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("INSERT INTO audit_samples").
+		WithArgs("org-001", "audit-001", "req-001", "pop-001", "random", int64(42), 2, 400, "abc", nil, "user-001").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("smp-001"))
+	mock.ExpectExec("INSERT INTO audit_sample_items").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	mock.ExpectQuery("FROM audit_samples").WithArgs("req-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "request_id", "population_id", "method", "seed", "sample_size",
+			"population_size", "population_checksum", "notes", "selected_by", "created_at"}).
+			AddRow("smp-001", "req-001", "pop-001", "random", 42, 2, 400, "abc", nil, "user-001", time.Now()))
+	mock.ExpectQuery("FROM audit_sample_items si").WithArgs("smp-001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sample_order", "pi_id", "position", "item_key", "attributes", "statuses"}).
+			AddRow("si-1", 1, "pi-7", 7, "CHG-7", []byte(`{}`), pq.Array([]string{})).
+			AddRow("si-2", 2, "pi-3", 3, "CHG-3", []byte(`{}`), pq.Array([]string{})))
+
+	w := sendJSON(router, "POST", "/api/v1/audits/audit-001/requests/req-001/sample", map[string]interface{}{
+		"method": "random", "sample_size": 2, "seed": 42,
+	})
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(42), data["seed"])
+	assert.Len(t, data["items"], 2)
+	assert.Equal(t, float64(2), data["summary"].(map[string]interface{})["requested"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectAuditSample_ManualUnknownKey(t *testing.T) {
+	router, mock := setupAuditRouterWithRole("auditor")
+
+	expectSamplingAuditAccess(mock, "user-001")
+	expectRequestExists(mock)
+	mock.ExpectQuery("SELECT id, item_count, checksum_sha256 FROM audit_request_populations").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "item_count", "checksum_sha256"}).AddRow("pop-001", 400, "abc"))
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM audit_evidence_links").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM audit_samples").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("INSERT INTO audit_samples").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("smp-001"))
+	mock.ExpectExec("INSERT INTO audit_sample_items").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	w := sendJSON(router, "POST", "/api/v1/audits/audit-001/requests/req-001/sample", map[string]interface{}{
+		"method": "manual", "item_keys": []string{"CHG-1", "CHG-999"},
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectAuditSample_InUse(t *testing.T) {
+	router, mock := setupAuditRouterWithRole("auditor")
+
+	expectSamplingAuditAccess(mock, "user-001")
+	expectRequestExists(mock)
+	mock.ExpectQuery("SELECT id, item_count, checksum_sha256 FROM audit_request_populations").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "item_count", "checksum_sha256"}).AddRow("pop-001", 400, "abc"))
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM audit_evidence_links").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	w := sendJSON(router, "POST", "/api/v1/audits/audit-001/requests/req-001/sample", map[string]interface{}{
+		"method": "random", "sample_size": 25,
+	})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "SAMPLE_IN_USE")
+}
+
+func TestListRequestEvidenceSuggestions(t *testing.T) {
+	router, mock := setupAuditRouter()
+
+	expectSamplingAuditAccess(mock)
+	periodStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT ar.control_id, ar.requirement_id").
+		WithArgs("req-001", "audit-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"control_id", "requirement_id", "period_start", "period_end"}).
+			AddRow("ctl-001", nil, periodStart, periodEnd))
+	mock.ExpectQuery("FROM evidence_artifacts ea").
+		WithArgs("org-001", "audit-001", "req-001", "ctl-001", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "evidence_type", "status", "file_name", "version",
+			"collection_date", "via_control", "via_requirement", "prior_accepted_audits", "last_accepted_at"}).
+			AddRow("ev-1", "Change policy", "policy_document", "approved", "policy.pdf", 3,
+				time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), true, false, 2, time.Now()).
+			AddRow("ev-2", "2025 change log", "log_export", "approved", "log.csv", 1,
+				time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), true, false, 1, time.Now()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/audits/audit-001/requests/req-001/evidence-suggestions", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].([]interface{})
+	require.Len(t, data, 2)
+	assert.Equal(t, true, data[0].(map[string]interface{})["collected_in_period"])
+	assert.Equal(t, false, data[1].(map[string]interface{})["collected_in_period"])
+	assert.Equal(t, []interface{}{"control"}, data[0].(map[string]interface{})["matched_by"])
+}
+
+func TestSubmitRequestEvidence_SampleItemNotInSample(t *testing.T) {
+	router, mock := setupAuditRouter()
+
+	expectSamplingAuditAccess(mock)
+	expectRequestExists(mock)
+	mock.ExpectQuery("FROM audit_sample_items si").
+		WithArgs("si-404", "req-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	w := sendJSON(router, "POST", "/api/v1/audits/audit-001/requests/req-001/evidence", map[string]interface{}{
+		"artifact_id": "artifact-001", "sample_item_id": "si-404",
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
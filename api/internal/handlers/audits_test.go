@@ -64,6 +64,13 @@ func setupAuditRouterWithRole(role string) (*gin.Engine, sqlmock.Sqlmock) {
 	protected.POST("/audits/:id/requests/:rid/evidence", SubmitRequestEvidence)
 	protected.PUT("/audits/:id/requests/:rid/evidence/:lid/review", ReviewRequestEvidence)
 	protected.DELETE("/audits/:id/requests/:rid/evidence/:lid", RemoveRequestEvidence)
+	protected.GET("/audits/:id/requests/:rid/evidence-suggestions", ListRequestEvidenceSuggestions)
+
+	// Sampling
+	protected.PUT("/audits/:id/requests/:rid/population", UploadAuditPopulation)
+	protected.GET("/audits/:id/requests/:rid/population", GetAuditPopulation)
+	protected.POST("/audits/:id/requests/:rid/sample", SelectAuditSample)
+	protected.GET("/audits/:id/requests/:rid/sample", GetAuditSample)
 
 	// Findings
 	protected.GET("/audits/:id/findings", ListAuditFindings)
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// GetControlEffectiveness returns a control's current effectiveness breakdown and score history.
+func GetControlEffectiveness(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	controlID := c.Param("id")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("history_limit", "30"))
+	if limit < 1 || limit > 365 {
+		limit = 30
+	}
+
+	var identifier, rating string
+	var score *float64
+	var calculatedAt *time.Time
+	err := database.QueryRow(`
+		SELECT identifier, effectiveness_score, effectiveness_rating, effectiveness_calculated_at
+		FROM controls WHERE id = $1 AND org_id = $2
+	`, controlID, orgID).Scan(&identifier, &score, &rating, &calculatedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Control not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get control effectiveness")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	rows, err := database.Query(`
+		SELECT h.id, h.score, h.rating, h.components::text, h.trigger_type,
+			   h.calculated_by, COALESCE(u.first_name || ' ' || u.last_name, ''),
+			   h.calculated_at
+		FROM control_effectiveness_history h
+		LEFT JOIN users u ON u.id = h.calculated_by
+		WHERE h.control_id = $1 AND h.org_id = $2
+		ORDER BY h.calculated_at DESC
+		LIMIT $3
+	`, controlID, orgID, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list control effectiveness history")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	history := []gin.H{}
+	var components interface{} = []interface{}{}
+	for rows.Next() {
+		var hID, hRating, hComponents, hTrigger string
+		var hScore *float64
+		var hByID *string
+		var hByName string
+		var hAt time.Time
+		if err := rows.Scan(&hID, &hScore, &hRating, &hComponents, &hTrigger, &hByID, &hByName, &hAt); err != nil {
+			continue
+		}
+		// Components of the most recent calculation explain the current score
+		if len(history) == 0 {
+			json.Unmarshal([]byte(hComponents), &components)
+		}
+		item := gin.H{
+			"id":            hID,
+			"score":         hScore,
+			"rating":        hRating,
+			"trigger_type":  hTrigger,
+			"calculated_at": hAt,
+		}
+		if hByID != nil {
+			item["calculated_by"] = gin.H{"id": *hByID, "name": hByName}
+		} else {
+			item["calculated_by"] = nil
+		}
+		history = append(history, item)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"control_id":    controlID,
+		"identifier":    identifier,
+		"score":         score,
+		"rating":        rating,
+		"calculated_at": calculatedAt,
+		"components":    components,
+		"history":       history,
+	}))
+}
+
+// RecalculateControlEffectiveness recomputes a control's effectiveness on demand.
+func RecalculateControlEffectiveness(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	controlID := c.Param("id")
+
+	var identifier, previousRating string
+	var previousScore *float64
+	err := database.QueryRow(`
+		SELECT identifier, effectiveness_score, effectiveness_rating
+		FROM controls WHERE id = $1 AND org_id = $2
+	`, controlID, orgID).Scan(&identifier, &previousScore, &previousRating)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Control not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get control for effectiveness recalculation")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	result, err := services.RecalculateControlEffectiveness(c.Request.Context(), database.DB, orgID, controlID, "manual", &userID)
+	if err != nil {
+		log.Error().Err(err).Str("control_id", controlID).Msg("Failed to recalculate control effectiveness")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "control.effectiveness_recalculated", "control", &controlID, map[string]interface{}{
+		"previous_score": previousScore, "new_score": result.Score,
+		"previous_rating": previousRating, "new_rating": result.Rating,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"control_id":      controlID,
+		"identifier":      identifier,
+		"score":           result.Score,
+		"rating":          result.Rating,
+		"previous_score":  previousScore,
+		"previous_rating": previousRating,
+		"components":      result.Components,
+		"calculated_at":   result.CalculatedAt,
+	}))
+}
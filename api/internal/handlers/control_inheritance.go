@@ -0,0 +1,576 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// GetControlInheritance returns a control's inheritance and, for org sources, the
+// source control's status, test results and evidence (read-only).
+func GetControlInheritance(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	controlID := c.Param("id")
+
+	var identifier string
+	err := database.QueryRow(`SELECT identifier FROM controls WHERE id = $1 AND org_id = $2`,
+		controlID, orgID).Scan(&identifier)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Control not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get control")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	var (
+		inh           models.ControlInheritance
+		sourceOrgName *string
+	)
+	err = database.QueryRow(`
+		SELECT ci.id, ci.source_type, ci.provider_name, ci.provider_reference,
+			ci.source_org_id, o.name, ci.source_control_id,
+			ci.responsibility, ci.customer_responsibility, ci.created_at, ci.updated_at
+		FROM control_inheritances ci
+		LEFT JOIN organizations o ON o.id = ci.source_org_id
+		WHERE ci.control_id = $1 AND ci.org_id = $2
+	`, controlID, orgID).Scan(&inh.ID, &inh.SourceType, &inh.ProviderName, &inh.ProviderReference,
+		&inh.SourceOrgID, &sourceOrgName, &inh.SourceControlID,
+		&inh.Responsibility, &inh.CustomerResponsibility, &inh.CreatedAt, &inh.UpdatedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusOK, successResponse(c, gin.H{
+			"control_id": controlID,
+			"identifier": identifier,
+			"inherited":  false,
+		}))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get control inheritance")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	resp := gin.H{
+		"control_id":              controlID,
+		"identifier":              identifier,
+		"inherited":               true,
+		"id":                      inh.ID,
+		"source_type":             inh.SourceType,
+		"provider_name":           inh.ProviderName,
+		"provider_reference":      inh.ProviderReference,
+		"responsibility":          inh.Responsibility,
+		"customer_responsibility": inh.CustomerResponsibility,
+		"created_at":              inh.CreatedAt,
+		"updated_at":              inh.UpdatedAt,
+		"source_org":              nil,
+		"source_control":          nil,
+	}
+
+	if inh.SourceOrgID != nil {
+		srcOrg := gin.H{"id": *inh.SourceOrgID, "name": sourceOrgName}
+		granted := hasActiveInheritanceGrant(*inh.SourceOrgID, orgID)
+		srcOrg["access"] = "granted"
+		if !granted {
+			srcOrg["access"] = "revoked"
+		}
+		resp["source_org"] = srcOrg
+
+		// Source status is only visible while the source org's grant is active
+		if granted && inh.SourceControlID != nil {
+			resp["source_control"] = sourceControlStatus(*inh.SourceOrgID, *inh.SourceControlID)
+		}
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, resp))
+}
+
+// SetControlInheritance marks a control as inherited (creates or replaces the inheritance).
+func SetControlInheritance(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	controlID := c.Param("id")
+
+	var req models.SetControlInheritanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+
+	if !models.IsValidInheritanceSource(req.SourceType) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "source_type must be one of: provider, organization"))
+		return
+	}
+	responsibility := models.ResponsibilityShared
+	if req.Responsibility != nil {
+		if !models.IsValidResponsibility(*req.Responsibility) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "responsibility must be one of: customer, provider, shared"))
+			return
+		}
+		responsibility = *req.Responsibility
+	}
+	if req.CustomerResponsibility != nil && len(*req.CustomerResponsibility) > 5000 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "customer_responsibility must be at most 5000 characters"))
+		return
+	}
+	if req.ProviderReference != nil && len(*req.ProviderReference) > 1000 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "provider_reference must be at most 1000 characters"))
+		return
+	}
+
+	switch req.SourceType {
+	case models.InheritanceSourceProvider:
+		if req.ProviderName == nil || strings.TrimSpace(*req.ProviderName) == "" || len(*req.ProviderName) > 255 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "provider_name is required for provider inheritance (max 255 characters)"))
+			return
+		}
+		if req.SourceOrgID != nil || req.SourceControlID != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "source_org_id and source_control_id are only valid for organization inheritance"))
+			return
+		}
+	case models.InheritanceSourceOrganization:
+		if req.SourceOrgID == nil || *req.SourceOrgID == "" {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "source_org_id is required for organization inheritance"))
+			return
+		}
+		if *req.SourceOrgID == orgID {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "A control cannot inherit from its own org"))
+			return
+		}
+	}
+
+	var identifier string
+	err := database.QueryRow(`SELECT identifier FROM controls WHERE id = $1 AND org_id = $2`,
+		controlID, orgID).Scan(&identifier)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Control not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get control")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	if req.SourceType == models.InheritanceSourceOrganization {
+		if !hasActiveInheritanceGrant(*req.SourceOrgID, orgID) {
+			c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Source org has not granted inheritance to this org"))
+			return
+		}
+		if req.SourceControlID != nil {
+			var exists bool
+			database.QueryRow(`SELECT EXISTS(SELECT 1 FROM controls WHERE id = $1 AND org_id = $2)`,
+				*req.SourceControlID, *req.SourceOrgID).Scan(&exists)
+			if !exists {
+				c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "Source control not found in source org"))
+				return
+			}
+		}
+	}
+
+	inheritanceID := uuid.New().String()
+	err = database.QueryRow(`
+		INSERT INTO control_inheritances (id, org_id, control_id, source_type, provider_name,
+			provider_reference, source_org_id, source_control_id, responsibility,
+			customer_responsibility, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (control_id) DO UPDATE SET
+			source_type = EXCLUDED.source_type,
+			provider_name = EXCLUDED.provider_name,
+			provider_reference = EXCLUDED.provider_reference,
+			source_org_id = EXCLUDED.source_org_id,
+			source_control_id = EXCLUDED.source_control_id,
+			responsibility = EXCLUDED.responsibility,
+			customer_responsibility = EXCLUDED.customer_responsibility
+		RETURNING id
+	`, inheritanceID, orgID, controlID, req.SourceType, req.ProviderName,
+		req.ProviderReference, req.SourceOrgID, req.SourceControlID, responsibility,
+		req.CustomerResponsibility, userID).Scan(&inheritanceID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to set control inheritance")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "control.inheritance_set", "control", &controlID, map[string]interface{}{
+		"source_type": req.SourceType, "provider_name": req.ProviderName,
+		"source_org_id": req.SourceOrgID, "responsibility": responsibility,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"id":                      inheritanceID,
+		"control_id":              controlID,
+		"identifier":              identifier,
+		"inherited":               true,
+		"source_type":             req.SourceType,
+		"provider_name":           req.ProviderName,
+		"provider_reference":      req.ProviderReference,
+		"source_org_id":           req.SourceOrgID,
+		"source_control_id":       req.SourceControlID,
+		"responsibility":          responsibility,
+		"customer_responsibility": req.CustomerResponsibility,
+	}))
+}
+
+// DeleteControlInheritance removes a control's inheritance.
+func DeleteControlInheritance(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	controlID := c.Param("id")
+
+	result, err := database.Exec(`DELETE FROM control_inheritances WHERE control_id = $1 AND org_id = $2`,
+		controlID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete control inheritance")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Control inheritance not found"))
+		return
+	}
+
+	middleware.LogAudit(c, "control.inheritance_removed", "control", &controlID, nil)
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"control_id": controlID,
+		"inherited":  false,
+	}))
+}
+
+// ListInheritanceGrants lists grants made by this org and grants received from other orgs.
+func ListInheritanceGrants(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	where := "(g.org_id = $1 OR g.consumer_org_id = $1)"
+	if c.Query("include_revoked") != "true" {
+		where += " AND g.revoked_at IS NULL"
+	}
+
+	rows, err := database.Query(`
+		SELECT g.id, g.org_id, so.name, g.consumer_org_id, co.name, g.notes,
+			g.revoked_at, g.created_at,
+			(SELECT COUNT(*) FROM control_inheritances ci
+			 WHERE ci.org_id = g.consumer_org_id AND ci.source_org_id = g.org_id)
+		FROM org_inheritance_grants g
+		JOIN organizations so ON so.id = g.org_id
+		JOIN organizations co ON co.id = g.consumer_org_id
+		WHERE `+where+`
+		ORDER BY g.created_at DESC
+	`, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list inheritance grants")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	grants := []gin.H{}
+	for rows.Next() {
+		var (
+			id, sourceOrgID, sourceOrgName, consumerOrgID, consumerOrgName string
+			notes                                                          *string
+			revokedAt                                                      *time.Time
+			createdAt                                                      time.Time
+			inheritedControls                                              int
+		)
+		if err := rows.Scan(&id, &sourceOrgID, &sourceOrgName, &consumerOrgID, &consumerOrgName,
+			&notes, &revokedAt, &createdAt, &inheritedControls); err != nil {
+			continue
+		}
+		direction := "outgoing"
+		if consumerOrgID == orgID {
+			direction = "incoming"
+		}
+		grants = append(grants, gin.H{
+			"id":                 id,
+			"direction":          direction,
+			"source_org":         gin.H{"id": sourceOrgID, "name": sourceOrgName},
+			"consumer_org":       gin.H{"id": consumerOrgID, "name": consumerOrgName},
+			"notes":              notes,
+			"revoked_at":         revokedAt,
+			"created_at":         createdAt,
+			"inherited_controls": inheritedControls,
+		})
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, grants))
+}
+
+// CreateInheritanceGrant allows another org to inherit this org's controls.
+func CreateInheritanceGrant(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	var req models.CreateInheritanceGrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+	if req.ConsumerOrgID == orgID {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "An org cannot grant inheritance to itself"))
+		return
+	}
+	if req.Notes != nil && len(*req.Notes) > 2000 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Notes must be at most 2000 characters"))
+		return
+	}
+
+	var consumerName string
+	err := database.QueryRow(`SELECT name FROM organizations WHERE id = $1 AND status = 'active'`,
+		req.ConsumerOrgID).Scan(&consumerName)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Consumer org not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get consumer org")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	if hasActiveInheritanceGrant(orgID, req.ConsumerOrgID) {
+		c.JSON(http.StatusConflict, errorResponse("CONFLICT", "An active grant already exists for this org"))
+		return
+	}
+
+	grantID := uuid.New().String()
+	_, err = database.Exec(`
+		INSERT INTO org_inheritance_grants (id, org_id, consumer_org_id, notes, granted_by)
+		VALUES ($1, $2, $3, $4, $5)
+	`, grantID, orgID, req.ConsumerOrgID, req.Notes, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create inheritance grant")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "org.inheritance_granted", "org_inheritance_grant", &grantID, map[string]interface{}{
+		"consumer_org_id": req.ConsumerOrgID,
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, gin.H{
+		"id":           grantID,
+		"consumer_org": gin.H{"id": req.ConsumerOrgID, "name": consumerName},
+		"notes":        req.Notes,
+	}))
+}
+
+// RevokeInheritanceGrant revokes a grant made by this org. Existing inheritances remain
+// but the source status is no longer visible to the consumer org.
+func RevokeInheritanceGrant(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	grantID := c.Param("id")
+
+	result, err := database.Exec(`
+		UPDATE org_inheritance_grants SET revoked_at = NOW(), revoked_by = $1
+		WHERE id = $2 AND org_id = $3 AND revoked_at IS NULL
+	`, userID, grantID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to revoke inheritance grant")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Active grant not found"))
+		return
+	}
+
+	middleware.LogAudit(c, "org.inheritance_revoked", "org_inheritance_grant", &grantID, nil)
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"id":      grantID,
+		"revoked": true,
+	}))
+}
+
+// ListInheritableControls lists a granting org's controls available for inheritance.
+func ListInheritableControls(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	grantID := c.Param("id")
+
+	var sourceOrgID string
+	err := database.QueryRow(`
+		SELECT org_id FROM org_inheritance_grants
+		WHERE id = $1 AND consumer_org_id = $2 AND revoked_at IS NULL
+	`, grantID, orgID).Scan(&sourceOrgID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Active grant not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get inheritance grant")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	rows, err := database.Query(`
+		SELECT id, identifier, title, category, status, effectiveness_rating
+		FROM controls
+		WHERE org_id = $1 AND status <> 'deprecated'
+		ORDER BY identifier
+	`, sourceOrgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list inheritable controls")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	controls := []gin.H{}
+	for rows.Next() {
+		var id, identifier, title, category, status, rating string
+		if err := rows.Scan(&id, &identifier, &title, &category, &status, &rating); err != nil {
+			continue
+		}
+		controls = append(controls, gin.H{
+			"id":                   id,
+			"identifier":           identifier,
+			"title":                title,
+			"category":             category,
+			"status":               status,
+			"effectiveness_rating": rating,
+		})
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, controls))
+}
+
+// hasActiveInheritanceGrant reports whether sourceOrgID currently allows consumerOrgID to inherit.
+func hasActiveInheritanceGrant(sourceOrgID, consumerOrgID string) bool {
+	var granted bool
+	database.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM org_inheritance_grants
+			WHERE org_id = $1 AND consumer_org_id = $2 AND revoked_at IS NULL)
+	`, sourceOrgID, consumerOrgID).Scan(&granted)
+	return granted
+}
+
+// controlInheritedColumn selects whether the control aliased c is inherited. Inherited controls
+// are read-only: their status and details come from the provider or source org, and the
+// inheritance must be removed before they can be edited locally.
+const controlInheritedColumn = "EXISTS (SELECT 1 FROM control_inheritances ci WHERE ci.control_id = c.id)"
+
+const inheritedControlReadOnlyMessage = "Control is inherited and read-only; remove the inheritance to edit it"
+
+// controlInheritanceSummary returns the inheritance summary shown on a control, or nil.
+func controlInheritanceSummary(orgID, controlID string) interface{} {
+	var (
+		sourceType, responsibility string
+		providerName, sourceOrgID  *string
+		sourceOrgName              *string
+	)
+	err := database.QueryRow(`
+		SELECT ci.source_type, ci.provider_name, ci.source_org_id, o.name, ci.responsibility
+		FROM control_inheritances ci
+		LEFT JOIN organizations o ON o.id = ci.source_org_id
+		WHERE ci.control_id = $1 AND ci.org_id = $2
+	`, controlID, orgID).Scan(&sourceType, &providerName, &sourceOrgID, &sourceOrgName, &responsibility)
+	if err != nil {
+		return nil
+	}
+	summary := gin.H{
+		"source_type":    sourceType,
+		"provider_name":  providerName,
+		"source_org":     nil,
+		"responsibility": responsibility,
+		"read_only":      true,
+	}
+	if sourceOrgID != nil {
+		summary["source_org"] = gin.H{"id": *sourceOrgID, "name": sourceOrgName}
+	}
+	return summary
+}
+
+// sourceControlStatus loads a source org control's status, latest test results and
+// current evidence for read-only display to an inheriting org. No download URLs are exposed.
+func sourceControlStatus(sourceOrgID, sourceControlID string) interface{} {
+	var (
+		identifier, title, status, rating string
+		score                             *float64
+	)
+	err := database.QueryRow(`
+		SELECT identifier, title, status, effectiveness_score, effectiveness_rating
+		FROM controls WHERE id = $1 AND org_id = $2
+	`, sourceControlID, sourceOrgID).Scan(&identifier, &title, &status, &score, &rating)
+	if err != nil {
+		return nil
+	}
+
+	// Latest result per active test
+	tests := []gin.H{}
+	testSummary := map[string]int{}
+	testRows, err := database.Query(`
+		SELECT DISTINCT ON (t.id) t.identifier, t.title, tr.status, tr.started_at
+		FROM tests t
+		JOIN test_results tr ON tr.test_id = t.id
+		WHERE t.control_id = $1 AND t.org_id = $2 AND t.status = 'active'
+		ORDER BY t.id, tr.started_at DESC
+	`, sourceControlID, sourceOrgID)
+	if err == nil {
+		defer testRows.Close()
+		for testRows.Next() {
+			var tIdentifier, tTitle, trStatus string
+			var startedAt time.Time
+			if err := testRows.Scan(&tIdentifier, &tTitle, &trStatus, &startedAt); err != nil {
+				continue
+			}
+			testSummary[trStatus]++
+			tests = append(tests, gin.H{
+				"identifier":  tIdentifier,
+				"title":       tTitle,
+				"last_result": trStatus,
+				"last_run_at": startedAt,
+			})
+		}
+	}
+
+	evidence := []gin.H{}
+	evRows, err := database.Query(`
+		SELECT ea.title, ea.evidence_type, ea.status, ea.collection_date, ea.expires_at
+		FROM evidence_links el
+		JOIN evidence_artifacts ea ON ea.id = el.artifact_id AND ea.is_current = TRUE
+		WHERE el.control_id = $1 AND el.org_id = $2 AND ea.status NOT IN ('rejected', 'superseded')
+		ORDER BY ea.collection_date DESC
+	`, sourceControlID, sourceOrgID)
+	if err == nil {
+		defer evRows.Close()
+		for evRows.Next() {
+			var evTitle, evType, evStatus string
+			var collectionDate time.Time
+			var expiresAt *time.Time
+			if err := evRows.Scan(&evTitle, &evType, &evStatus, &collectionDate, &expiresAt); err != nil {
+				continue
+			}
+			evidence = append(evidence, gin.H{
+				"title":            evTitle,
+				"evidence_type":    evType,
+				"status":           evStatus,
+				"collection_date":  collectionDate.Format("2006-01-02"),
+				"expires_at":       expiresAt,
+				"freshness_status": computeFreshnessStatus(expiresAt),
+			})
+		}
+	}
+
+	return gin.H{
+		"id":            sourceControlID,
+		"identifier":    identifier,
+		"title":         title,
+		"status":        status,
+		"read_only":     true,
+		"effectiveness": gin.H{"score": score, "rating": rating},
+		"tests":         tests,
+		"test_summary":  testSummary,
+		"evidence":      evidence,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -37,10 +38,22 @@ func ListControls(c *gin.Context) {
 	isCustomFilter := c.Query("is_custom")
 	frameworkIDFilter := c.Query("framework_id")
 	unmappedFilter := c.Query("unmapped")
+	effectivenessFilter := c.Query("effectiveness_rating")
+	inheritedFilter := c.Query("inherited")
+	responsibilityFilter := c.Query("responsibility")
 	search := c.Query("search")
 	sortField := c.DefaultQuery("sort", "identifier")
 	order := c.DefaultQuery("order", "asc")
 
+	if effectivenessFilter != "" && !models.IsValidEffectiveness(effectivenessFilter) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid effectiveness_rating"))
+		return
+	}
+	if responsibilityFilter != "" && !models.IsValidResponsibility(responsibilityFilter) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid responsibility"))
+		return
+	}
+
 	allowedSort := map[string]string{
 		"identifier": "c.identifier",
 		"title":      "c.title",
@@ -48,6 +61,8 @@ func ListControls(c *gin.Context) {
 		"status":     "c.status",
 		"created_at": "c.created_at",
 		"updated_at": "c.updated_at",
+
+		"effectiveness_score": "c.effectiveness_score",
 	}
 	sortCol, ok := allowedSort[sortField]
 	if !ok {
@@ -56,6 +71,11 @@ func ListControls(c *gin.Context) {
 	if order != "asc" && order != "desc" {
 		order = "asc"
 	}
+	orderClause := sortCol + " " + order
+	if sortCol == "c.effectiveness_score" {
+		// Unassessed controls sort last in either direction
+		orderClause += " NULLS LAST"
+	}
 
 	where := []string{"c.org_id = $1"}
 	args := []interface{}{orgID}
@@ -96,6 +116,22 @@ func ListControls(c *gin.Context) {
 			SELECT 1 FROM control_mappings cm WHERE cm.control_id = c.id AND cm.org_id = c.org_id
 		)`)
 	}
+	if effectivenessFilter != "" {
+		where = append(where, fmt.Sprintf("c.effectiveness_rating = $%d", argN))
+		args = append(args, effectivenessFilter)
+		argN++
+	}
+	if inheritedFilter == "true" {
+		where = append(where, "EXISTS (SELECT 1 FROM control_inheritances ci WHERE ci.control_id = c.id)")
+	} else if inheritedFilter == "false" {
+		where = append(where, "NOT EXISTS (SELECT 1 FROM control_inheritances ci WHERE ci.control_id = c.id)")
+	}
+	if responsibilityFilter != "" {
+		where = append(where, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM control_inheritances ci WHERE ci.control_id = c.id AND ci.responsibility = $%d)", argN))
+		args = append(args, responsibilityFilter)
+		argN++
+	}
 	if search != "" {
 		where = append(where, fmt.Sprintf(
 			"to_tsvector('english', c.title || ' ' || COALESCE(c.description, '')) @@ plainto_tsquery('english', $%d)", argN))
@@ -117,13 +153,16 @@ func ListControls(c *gin.Context) {
 			   c.owner_id, COALESCE(u.first_name || ' ' || u.last_name, ''), COALESCE(u.email, ''),
 			   c.secondary_owner_id,
 			   COALESCE((SELECT COUNT(*) FROM control_mappings cm WHERE cm.control_id = c.id), 0) AS mappings_count,
+			   c.effectiveness_score, c.effectiveness_rating,
+			   ci.source_type, ci.responsibility,
 			   c.created_at, c.updated_at
 		FROM controls c
 		LEFT JOIN users u ON u.id = c.owner_id
+		LEFT JOIN control_inheritances ci ON ci.control_id = c.id
 		WHERE %s
-		ORDER BY %s %s
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, whereClause, sortCol, order, argN, argN+1)
+	`, whereClause, orderClause, argN, argN+1)
 	args = append(args, perPage, offset)
 
 	rows, err := database.Query(query, args...)
@@ -142,11 +181,16 @@ func ListControls(c *gin.Context) {
 			ownerID, secondaryOwnerID                                  *string
 			ownerName, ownerEmail                                      string
 			mappingsCount                                              int
+			effectivenessScore                                         *float64
+			effectivenessRating                                        string
+			inheritSource, inheritResponsibility                       *string
 			createdAt, updatedAt                                       interface{}
 		)
 		if err := rows.Scan(&cID, &cIdentifier, &cTitle, &cDescription, &cCategory, &cStatus,
 			&cIsCustom, &ownerID, &ownerName, &ownerEmail, &secondaryOwnerID,
-			&mappingsCount, &createdAt, &updatedAt); err != nil {
+			&mappingsCount, &effectivenessScore, &effectivenessRating,
+			&inheritSource, &inheritResponsibility,
+			&createdAt, &updatedAt); err != nil {
 			log.Error().Err(err).Msg("Failed to scan control row")
 			continue
 		}
@@ -162,6 +206,15 @@ func ListControls(c *gin.Context) {
 			"mappings_count": mappingsCount,
 			"created_at":     createdAt,
 			"updated_at":     updatedAt,
+
+			"effectiveness_score":  effectivenessScore,
+			"effectiveness_rating": effectivenessRating,
+		}
+
+		if inheritSource != nil {
+			ctrl["inheritance"] = gin.H{"source_type": *inheritSource, "responsibility": inheritResponsibility, "read_only": true}
+		} else {
+			ctrl["inheritance"] = nil
 		}
 
 		if ownerID != nil {
@@ -346,6 +399,7 @@ func GetControl(c *gin.Context) {
 			   c.owner_id, COALESCE(u.first_name || ' ' || u.last_name, ''), COALESCE(u.email, ''),
 			   c.secondary_owner_id,
 			   c.evidence_requirements, c.test_criteria, c.metadata::text,
+			   c.effectiveness_score, c.effectiveness_rating, c.effectiveness_calculated_at,
 			   c.created_at, c.updated_at
 		FROM controls c
 		LEFT JOIN users u ON u.id = c.owner_id
@@ -355,6 +409,7 @@ func GetControl(c *gin.Context) {
 		&ctrl.Category, &ctrl.Status, &ctrl.IsCustom, &ctrl.SourceTemplateID,
 		&ctrl.OwnerID, &ownerName, &ownerEmail, &ctrl.SecondaryOwnerID,
 		&ctrl.EvidenceRequirements, &ctrl.TestCriteria, &ctrl.Metadata,
+		&ctrl.EffectivenessScore, &ctrl.EffectivenessRating, &ctrl.EffectivenessCalcAt,
 		&ctrl.CreatedAt, &ctrl.UpdatedAt)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Control not found"))
@@ -382,8 +437,13 @@ func GetControl(c *gin.Context) {
 		"evidence_requirements":   ctrl.EvidenceRequirements,
 		"test_criteria":           ctrl.TestCriteria,
 		"metadata":                metadata,
-		"created_at":              ctrl.CreatedAt,
-		"updated_at":              ctrl.UpdatedAt,
+		"effectiveness": gin.H{
+			"score":         ctrl.EffectivenessScore,
+			"rating":        ctrl.EffectivenessRating,
+			"calculated_at": ctrl.EffectivenessCalcAt,
+		},
+		"created_at": ctrl.CreatedAt,
+		"updated_at": ctrl.UpdatedAt,
 	}
 
 	if ctrl.OwnerID != nil {
@@ -401,6 +461,8 @@ func GetControl(c *gin.Context) {
 		resp["secondary_owner"] = nil
 	}
 
+	resp["inheritance"] = controlInheritanceSummary(orgID, controlID)
+
 	// Get mappings
 	mappingRows, err := database.Query(`
 		SELECT cm.id, r.id, r.identifier, r.title, f.name, fv.version, cm.strength, cm.notes
@@ -453,9 +515,10 @@ func UpdateControl(c *gin.Context) {
 
 	// Get current control
 	var currentOwnerID *string
+	var inherited bool
 	err := database.QueryRow(`
-		SELECT owner_id FROM controls WHERE id = $1 AND org_id = $2
-	`, controlID, orgID).Scan(&currentOwnerID)
+		SELECT owner_id, `+controlInheritedColumn+` FROM controls c WHERE id = $1 AND org_id = $2
+	`, controlID, orgID).Scan(&currentOwnerID, &inherited)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Control not found"))
 		return
@@ -473,6 +536,10 @@ func UpdateControl(c *gin.Context) {
 		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to update this control"))
 		return
 	}
+	if inherited {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", inheritedControlReadOnlyMessage))
+		return
+	}
 
 	var req models.UpdateControlRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -626,13 +693,18 @@ func ChangeControlStatus(c *gin.Context) {
 	controlID := c.Param("id")
 
 	var currentStatus, cIdentifier string
+	var inherited bool
 	err := database.QueryRow(`
-		SELECT status, identifier FROM controls WHERE id = $1 AND org_id = $2
-	`, controlID, orgID).Scan(&currentStatus, &cIdentifier)
+		SELECT status, identifier, `+controlInheritedColumn+` FROM controls c WHERE id = $1 AND org_id = $2
+	`, controlID, orgID).Scan(&currentStatus, &cIdentifier, &inherited)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Control not found"))
 		return
 	}
+	if inherited {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", inheritedControlReadOnlyMessage))
+		return
+	}
 
 	var req models.ChangeStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -677,13 +749,18 @@ func DeprecateControl(c *gin.Context) {
 	controlID := c.Param("id")
 
 	var cIdentifier string
+	var inherited bool
 	err := database.QueryRow(`
-		SELECT identifier FROM controls WHERE id = $1 AND org_id = $2
-	`, controlID, orgID).Scan(&cIdentifier)
+		SELECT identifier, `+controlInheritedColumn+` FROM controls c WHERE id = $1 AND org_id = $2
+	`, controlID, orgID).Scan(&cIdentifier, &inherited)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Control not found"))
 		return
 	}
+	if inherited {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", inheritedControlReadOnlyMessage))
+		return
+	}
 
 	_, err = database.Exec("UPDATE controls SET status = 'deprecated' WHERE id = $1", controlID)
 	if err != nil {
@@ -726,14 +803,20 @@ func BulkControlStatus(c *gin.Context) {
 
 	for _, ctrlID := range req.ControlIDs {
 		var currentStatus, identifier string
+		var inherited bool
 		err := database.QueryRow(`
-			SELECT status, identifier FROM controls WHERE id = $1 AND org_id = $2
-		`, ctrlID, orgID).Scan(&currentStatus, &identifier)
+			SELECT status, identifier, `+controlInheritedColumn+` FROM controls c WHERE id = $1 AND org_id = $2
+		`, ctrlID, orgID).Scan(&currentStatus, &identifier, &inherited)
 		if err != nil {
 			results = append(results, gin.H{"id": ctrlID, "success": false, "error": "not found"})
 			failed++
 			continue
 		}
+		if inherited {
+			results = append(results, gin.H{"id": ctrlID, "identifier": identifier, "success": false, "error": "inherited controls are read-only"})
+			failed++
+			continue
+		}
 
 		if !models.IsValidStatusTransition(currentStatus, req.Status) {
 			results = append(results, gin.H{
@@ -840,6 +923,35 @@ func GetControlStats(c *gin.Context) {
 		}
 	}
 
+	// By effectiveness rating (deprecated controls are not scored)
+	byEffectiveness := gin.H{"effective": 0, "partially_effective": 0, "ineffective": 0, "not_assessed": 0}
+	var avgEffectiveness *float64
+	effRows, err := database.Query(`
+		SELECT effectiveness_rating, COUNT(*), AVG(effectiveness_score)
+		FROM controls WHERE org_id = $1 AND status <> 'deprecated'
+		GROUP BY effectiveness_rating
+	`, orgID)
+	if err == nil {
+		defer effRows.Close()
+		var scoreSum float64
+		var scored int
+		for effRows.Next() {
+			var rating string
+			var count int
+			var avg *float64
+			effRows.Scan(&rating, &count, &avg)
+			byEffectiveness[rating] = count
+			if avg != nil {
+				scoreSum += *avg * float64(count)
+				scored += count
+			}
+		}
+		if scored > 0 {
+			avg := math.Round(scoreSum/float64(scored)*100) / 100
+			avgEffectiveness = &avg
+		}
+	}
+
 	c.JSON(http.StatusOK, successResponse(c, gin.H{
 		"total":               total,
 		"by_status":           byStatus,
@@ -849,5 +961,7 @@ func GetControlStats(c *gin.Context) {
 		"unowned_count":       unownedCount,
 		"unmapped_count":      unmappedCount,
 		"frameworks_coverage": fwCoverage,
+		"by_effectiveness":    byEffectiveness,
+		"avg_effectiveness":   avgEffectiveness,
 	}))
 }
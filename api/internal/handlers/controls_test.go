@@ -11,6 +11,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
 	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -155,6 +156,7 @@ func TestGetControl_Success(t *testing.T) {
 		"category", "status", "is_custom", "source_template_id",
 		"owner_id", "owner_name", "owner_email", "secondary_owner_id",
 		"evidence_requirements", "test_criteria", "metadata",
+		"effectiveness_score", "effectiveness_rating", "effectiveness_calculated_at",
 		"created_at", "updated_at",
 	}).AddRow(
 		"c001", "CTRL-AC-001", "Multi-Factor Authentication",
@@ -162,6 +164,7 @@ func TestGetControl_Success(t *testing.T) {
 		"technical", "active", false, "TPL-AC-001",
 		nil, "", "", nil,
 		nil, nil, "{}",
+		87.5, "effective", now,
 		now, now,
 	)
 	mock.ExpectQuery("SELECT c.id, c.identifier, c.title").WillReturnRows(row)
@@ -193,6 +196,10 @@ func TestGetControl_Success(t *testing.T) {
 	assert.Len(t, mappings, 1)
 	m := mappings[0].(map[string]interface{})
 	assert.Equal(t, "primary", m["strength"])
+
+	eff := data["effectiveness"].(map[string]interface{})
+	assert.Equal(t, 87.5, eff["score"])
+	assert.Equal(t, "effective", eff["rating"])
 }
 
 func TestGetControl_NotFound(t *testing.T) {
@@ -216,7 +223,7 @@ func TestChangeControlStatus_Success(t *testing.T) {
 
 	// Get current status
 	mock.ExpectQuery("SELECT status, identifier").WillReturnRows(
-		sqlmock.NewRows([]string{"status", "identifier"}).AddRow("draft", "CTRL-AC-001"),
+		sqlmock.NewRows([]string{"status", "identifier", "inherited"}).AddRow("draft", "CTRL-AC-001", false),
 	)
 
 	// Update
@@ -243,7 +250,7 @@ func TestChangeControlStatus_InvalidTransition(t *testing.T) {
 	r.PUT("/api/v1/controls/:id/status", ChangeControlStatus)
 
 	mock.ExpectQuery("SELECT status, identifier").WillReturnRows(
-		sqlmock.NewRows([]string{"status", "identifier"}).AddRow("draft", "CTRL-AC-001"),
+		sqlmock.NewRows([]string{"status", "identifier", "inherited"}).AddRow("draft", "CTRL-AC-001", false),
 	)
 
 	body := `{"status": "under_review"}`
@@ -261,7 +268,7 @@ func TestDeprecateControl_Success(t *testing.T) {
 	r.DELETE("/api/v1/controls/:id", DeprecateControl)
 
 	mock.ExpectQuery("SELECT identifier").WillReturnRows(
-		sqlmock.NewRows([]string{"identifier"}).AddRow("CTRL-AC-001"),
+		sqlmock.NewRows([]string{"identifier", "inherited"}).AddRow("CTRL-AC-001", false),
 	)
 	mock.ExpectExec("UPDATE controls SET status").WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -298,13 +305,13 @@ func TestBulkControlStatus_Success(t *testing.T) {
 
 	// First control
 	mock.ExpectQuery("SELECT status, identifier").WillReturnRows(
-		sqlmock.NewRows([]string{"status", "identifier"}).AddRow("draft", "CTRL-AC-001"),
+		sqlmock.NewRows([]string{"status", "identifier", "inherited"}).AddRow("draft", "CTRL-AC-001", false),
 	)
 	mock.ExpectExec("UPDATE controls SET status").WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Second control
 	mock.ExpectQuery("SELECT status, identifier").WillReturnRows(
-		sqlmock.NewRows([]string{"status", "identifier"}).AddRow("draft", "CTRL-AC-002"),
+		sqlmock.NewRows([]string{"status", "identifier", "inherited"}).AddRow("draft", "CTRL-AC-002", false),
 	)
 	mock.ExpectExec("UPDATE controls SET status").WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -334,7 +341,7 @@ func TestBulkControlStatus_PartialFailure(t *testing.T) {
 
 	// First control succeeds
 	mock.ExpectQuery("SELECT status, identifier").WillReturnRows(
-		sqlmock.NewRows([]string{"status", "identifier"}).AddRow("draft", "CTRL-AC-001"),
+		sqlmock.NewRows([]string{"status", "identifier", "inherited"}).AddRow("draft", "CTRL-AC-001", false),
 	)
 	mock.ExpectExec("UPDATE controls SET status").WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -406,6 +413,13 @@ func TestGetControlStats(t *testing.T) {
 		sqlmock.NewRows([]string{"name", "version", "id"}),
 	)
 
+	// By effectiveness
+	mock.ExpectQuery("SELECT effectiveness_rating, COUNT").WillReturnRows(
+		sqlmock.NewRows([]string{"effectiveness_rating", "count", "avg"}).
+			AddRow("effective", 200, 90.0).AddRow("ineffective", 100, 30.0).
+			AddRow("not_assessed", 7, nil),
+	)
+
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/api/v1/controls/stats", nil)
 	r.ServeHTTP(w, req)
@@ -420,6 +434,11 @@ func TestGetControlStats(t *testing.T) {
 	assert.Equal(t, float64(312), data["total"])
 	assert.Equal(t, float64(18), data["custom_count"])
 	assert.Equal(t, float64(294), data["library_count"])
+
+	byEff := data["by_effectiveness"].(map[string]interface{})
+	assert.Equal(t, float64(200), byEff["effective"])
+	assert.Equal(t, float64(7), byEff["not_assessed"])
+	assert.Equal(t, float64(70), data["avg_effectiveness"])
 }
 
 func TestListControls_EmptyResult(t *testing.T) {
@@ -444,6 +463,19 @@ func TestListControls_EmptyResult(t *testing.T) {
 	assert.Len(t, data, 0)
 }
 
+func TestListControls_InvalidEffectivenessRating(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/controls", ListControls)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/controls?effectiveness_rating=excellent", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestControlMappings_DeleteNotFound(t *testing.T) {
 	_, mock := setupTestRouter()
 	r := setupAuthRouter(mock)
@@ -537,3 +569,248 @@ func isValidStatusTransitionTest(from, to string) bool {
 	}
 	return false
 }
+
+func TestGetControlEffectiveness_NotFound(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/controls/:id/effectiveness", GetControlEffectiveness)
+
+	mock.ExpectQuery("SELECT identifier, effectiveness_score").WillReturnRows(sqlmock.NewRows(nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/controls/nonexistent/effectiveness", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetControlEffectiveness_WithHistory(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/controls/:id/effectiveness", GetControlEffectiveness)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT identifier, effectiveness_score").WillReturnRows(
+		sqlmock.NewRows([]string{"identifier", "effectiveness_score", "effectiveness_rating", "effectiveness_calculated_at"}).
+			AddRow("CTRL-AC-001", 78.0, "partially_effective", now),
+	)
+	mock.ExpectQuery("SELECT h.id, h.score, h.rating").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "score", "rating", "components", "trigger_type", "calculated_by", "name", "calculated_at"}).
+			AddRow("h002", 78.0, "partially_effective", `[{"name":"test_pass_rate","weight":0.35,"score":90}]`, "scheduled", nil, "", now).
+			AddRow("h001", 85.0, "effective", `[]`, "manual", "u001", "Alice Admin", now.Add(-24*time.Hour)),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/controls/c001/effectiveness", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "partially_effective", data["rating"])
+	assert.Len(t, data["components"].([]interface{}), 1)
+	history := data["history"].([]interface{})
+	assert.Len(t, history, 2)
+	assert.Nil(t, history[0].(map[string]interface{})["calculated_by"])
+}
+
+func TestRecalculateControlEffectiveness_NotFound(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/controls/:id/effectiveness/recalculate", RecalculateControlEffectiveness)
+
+	mock.ExpectQuery("SELECT identifier, effectiveness_score").WillReturnRows(sqlmock.NewRows(nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/controls/nonexistent/effectiveness/recalculate", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestScoreControlEffectiveness(t *testing.T) {
+	result := services.ScoreControlEffectiveness(services.EffectivenessInputs{
+		TestResultCounts:      map[string]int{"pass": 9, "fail": 1, "skip": 4},
+		EvidenceFreshness:     []string{"fresh", "expired"},
+		EvaluationVerdicts:    []string{"sufficient"},
+		OpenFindingSeverities: []string{"high"},
+		OpenSLABreaches:       1,
+	})
+	require.NotNil(t, result.Score)
+	// 0.35*90 + 0.20*50 + 0.20*100 + 0.15*60 + 0.10*75
+	assert.Equal(t, 78.0, *result.Score)
+	assert.Equal(t, "partially_effective", result.Rating)
+
+	// Without tests, evidence or evaluations the control is not assessed
+	empty := services.ScoreControlEffectiveness(services.EffectivenessInputs{
+		TestResultCounts:      map[string]int{},
+		OpenFindingSeverities: []string{"critical"},
+	})
+	assert.Nil(t, empty.Score)
+	assert.Equal(t, "not_assessed", empty.Rating)
+
+	// Missing signals are dropped and remaining weights renormalized
+	testsOnly := services.ScoreControlEffectiveness(services.EffectivenessInputs{
+		TestResultCounts: map[string]int{"pass": 10},
+	})
+	require.NotNil(t, testsOnly.Score)
+	assert.Equal(t, 100.0, *testsOnly.Score)
+	assert.Equal(t, "effective", testsOnly.Rating)
+}
+
+func TestSetControlInheritance_ProviderRequiresName(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.PUT("/api/v1/controls/:id/inheritance", SetControlInheritance)
+
+	body, _ := json.Marshal(map[string]interface{}{"source_type": "provider", "responsibility": "provider"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/controls/c001/inheritance", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSetControlInheritance_Provider(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.PUT("/api/v1/controls/:id/inheritance", SetControlInheritance)
+
+	mock.ExpectQuery("SELECT identifier FROM controls").WillReturnRows(
+		sqlmock.NewRows([]string{"identifier"}).AddRow("CTRL-PE-001"),
+	)
+	mock.ExpectQuery("INSERT INTO control_inheritances").WillReturnRows(
+		sqlmock.NewRows([]string{"id"}).AddRow("ci001"),
+	)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"source_type":        "provider",
+		"provider_name":      "AWS",
+		"provider_reference": "AWS SOC 2 Type II report",
+		"responsibility":     "provider",
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/controls/c001/inheritance", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "ci001", data["id"])
+	assert.Equal(t, "provider", data["responsibility"])
+}
+
+func TestSetControlInheritance_OrgWithoutGrant(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.PUT("/api/v1/controls/:id/inheritance", SetControlInheritance)
+
+	mock.ExpectQuery("SELECT identifier FROM controls").WillReturnRows(
+		sqlmock.NewRows([]string{"identifier"}).AddRow("CTRL-AC-001"),
+	)
+	mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"source_type":   "organization",
+		"source_org_id": "b0000000-0000-0000-0000-000000000002",
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/controls/c001/inheritance", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestGetControlInheritance_NotInherited(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/controls/:id/inheritance", GetControlInheritance)
+
+	mock.ExpectQuery("SELECT identifier FROM controls").WillReturnRows(
+		sqlmock.NewRows([]string{"identifier"}).AddRow("CTRL-AC-001"),
+	)
+	mock.ExpectQuery("SELECT ci.id, ci.source_type").WillReturnRows(sqlmock.NewRows(nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/controls/c001/inheritance", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, false, data["inherited"])
+}
+
+func TestDeleteControlInheritance_NotFound(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.DELETE("/api/v1/controls/:id/inheritance", DeleteControlInheritance)
+
+	mock.ExpectExec("DELETE FROM control_inheritances").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/controls/c001/inheritance", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListControls_InvalidResponsibility(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/controls", ListControls)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/controls?responsibility=vendor", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChangeControlStatus_Inherited(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.PUT("/api/v1/controls/:id/status", ChangeControlStatus)
+
+	mock.ExpectQuery("SELECT status, identifier").WillReturnRows(
+		sqlmock.NewRows([]string{"status", "identifier", "inherited"}).AddRow("active", "CTRL-AC-001", true),
+	)
+
+	body := `{"status": "under_review"}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/controls/c001/status", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateControl_Inherited(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.PUT("/api/v1/controls/:id", UpdateControl)
+
+	mock.ExpectQuery("SELECT owner_id").WillReturnRows(
+		sqlmock.NewRows([]string{"owner_id", "inherited"}).AddRow(nil, true),
+	)
+
+	body := `{"title": "Renamed"}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/controls/c001", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,11 +3,14 @@ package handlers
 import (
 	"database/sql"
 	"fmt"
+	"html"
 	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -20,9 +23,17 @@ import (
 
 var minioService *services.MinIOService
 
+// objectStore is where handlers write server-produced evidence; nil when storage is not
+// configured. Tests replace it with an in-memory store.
+var objectStore services.ObjectStore
+
 // SetMinIO sets the MinIO service for handlers.
 func SetMinIO(s *services.MinIOService) {
 	minioService = s
+	objectStore = nil
+	if s != nil {
+		objectStore = s
+	}
 }
 
 var fileNameSanitizer = regexp.MustCompile(`[^\w\-. ]`)
@@ -35,17 +46,7 @@ func sanitizeFileName(name string) string {
 }
 
 func computeFreshnessStatus(expiresAt *time.Time) string {
-	if expiresAt == nil {
-		return "fresh"
-	}
-	now := time.Now()
-	if expiresAt.Before(now) {
-		return "expired"
-	}
-	if expiresAt.Before(now.Add(30 * 24 * time.Hour)) {
-		return "expiring_soon"
-	}
-	return "fresh"
+	return models.EvidenceFreshnessStatus(expiresAt, time.Now())
 }
 
 func daysUntilExpiry(expiresAt *time.Time) *int {
@@ -421,6 +422,12 @@ func GetEvidence(c *gin.Context) {
 		uploaderName, uploaderEmail                                     string
 		tags                                                            pq.StringArray
 		createdAt, updatedAt                                            time.Time
+		processingStatus                                                string
+		detectedMIME, scanEngine, scanSignature, processingError        *string
+		processedAt                                                     *time.Time
+		legalHold                                                       bool
+		legalHoldReason                                                 *string
+		purgedAt                                                        *time.Time
 	)
 
 	err := database.QueryRow(`
@@ -432,9 +439,13 @@ func GetEvidence(c *gin.Context) {
 			   ea.source_system, ea.uploaded_by,
 			   COALESCE(u.first_name || ' ' || u.last_name, ''),
 			   COALESCE(u.email, ''),
-			   ea.tags, ea.created_at, ea.updated_at
+			   ea.tags, ea.created_at, ea.updated_at,
+			   ea.processing_status, ea.detected_mime_type, ea.scan_engine, ea.scan_signature,
+			   ea.processing_error, ea.processed_at,
+			   COALESCE(lh.legal_hold, FALSE), lh.legal_hold_reason, ea.purged_at
 		FROM evidence_artifacts ea
 		LEFT JOIN users u ON u.id = ea.uploaded_by
+		LEFT JOIN evidence_artifacts lh ON lh.id = COALESCE(ea.parent_artifact_id, ea.id)
 		WHERE ea.id = $1 AND ea.org_id = $2
 	`, artifactID, orgID).Scan(
 		&id, &title, &desc, &eType, &status,
@@ -444,7 +455,10 @@ func GetEvidence(c *gin.Context) {
 		&collDate, &expiresAt, &freshDays,
 		&sourceSystem, &uploadedByID,
 		&uploaderName, &uploaderEmail,
-		&tags, &createdAt, &updatedAt)
+		&tags, &createdAt, &updatedAt,
+		&processingStatus, &detectedMIME, &scanEngine, &scanSignature,
+		&processingError, &processedAt,
+		&legalHold, &legalHoldReason, &purgedAt)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence artifact not found"))
 		return
@@ -489,8 +503,21 @@ func GetEvidence(c *gin.Context) {
 		"source_system":         sourceSystem,
 		"tags":                  []string(tags),
 		"metadata":              gin.H{},
-		"created_at":            createdAt,
-		"updated_at":            updatedAt,
+		"processing": gin.H{
+			"status":             processingStatus,
+			"detected_mime_type": detectedMIME,
+			"scan_engine":        scanEngine,
+			"scan_signature":     scanSignature,
+			"error":              processingError,
+			"processed_at":       processedAt,
+		},
+		"retention": gin.H{
+			"legal_hold":        legalHold,
+			"legal_hold_reason": legalHoldReason,
+			"purged_at":         purgedAt,
+		},
+		"created_at": createdAt,
+		"updated_at": updatedAt,
 	}
 
 	if uploadedByID != nil {
@@ -758,13 +785,21 @@ func DeleteEvidence(c *gin.Context) {
 	artifactID := c.Param("id")
 
 	var currentTitle string
-	err := database.QueryRow(
-		"SELECT title FROM evidence_artifacts WHERE id = $1 AND org_id = $2",
-		artifactID, orgID).Scan(&currentTitle)
+	var legalHold bool
+	err := database.QueryRow(`
+		SELECT ea.title, COALESCE(lh.legal_hold, FALSE)
+		FROM evidence_artifacts ea
+		LEFT JOIN evidence_artifacts lh ON lh.id = COALESCE(ea.parent_artifact_id, ea.id)
+		WHERE ea.id = $1 AND ea.org_id = $2
+	`, artifactID, orgID).Scan(&currentTitle, &legalHold)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence artifact not found"))
 		return
 	}
+	if legalHold {
+		c.JSON(http.StatusConflict, errorResponse("LEGAL_HOLD", "Evidence is under legal hold and cannot be deleted"))
+		return
+	}
 
 	_, err = database.Exec("UPDATE evidence_artifacts SET status = 'superseded', is_current = FALSE WHERE id = $1", artifactID)
 	if err != nil {
@@ -777,10 +812,17 @@ func DeleteEvidence(c *gin.Context) {
 		"title": currentTitle, "artifact_id": artifactID,
 	})
 
+	// The file itself is only purged by the retention job once every matching rule has lapsed.
+	retention, err := services.GetEvidenceRetention(c.Request.Context(), database.DB, artifactID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load evidence retention")
+	}
+
 	c.JSON(http.StatusOK, successResponse(c, gin.H{
-		"id":      artifactID,
-		"status":  "superseded",
-		"message": "Evidence artifact removed from active view. File retained for audit trail.",
+		"id":        artifactID,
+		"status":    "superseded",
+		"retention": retention,
+		"message":   "Evidence artifact removed from active view. File retained for audit trail.",
 	}))
 }
 
@@ -801,10 +843,21 @@ func SearchEvidence(c *gin.Context) {
 	args := []interface{}{orgID}
 	argN := 2
 
+	// Metadata and extracted file content are searched together; the match flags and
+	// content excerpt are only selected when there is a query.
 	searchQuery := c.Query("q")
+	metaMatchCol, contentMatchCol, contentCol := "FALSE", "FALSE", "''"
+	relevanceCol := ""
 	if searchQuery != "" {
+		metaVector := "to_tsvector('english', ea.title || ' ' || COALESCE(ea.description, '') || ' ' || COALESCE(ea.source_system, '') || ' ' || array_to_string(ea.tags, ' '))"
+		tsQuery := fmt.Sprintf("plainto_tsquery('english', $%d)", argN)
 		where = append(where, fmt.Sprintf(
-			"to_tsvector('english', ea.title || ' ' || COALESCE(ea.description, '') || ' ' || COALESCE(ea.source_system, '') || ' ' || array_to_string(ea.tags, ' ')) @@ plainto_tsquery('english', $%d)", argN))
+			"(%s @@ %s OR EXISTS (SELECT 1 FROM evidence_contents ctm WHERE ctm.artifact_id = ea.id AND ctm.content_tsv @@ %s))",
+			metaVector, tsQuery, tsQuery))
+		metaMatchCol = metaVector + " @@ " + tsQuery
+		contentMatchCol = fmt.Sprintf("COALESCE(ct.content_tsv @@ %s, FALSE)", tsQuery)
+		contentCol = fmt.Sprintf("COALESCE(LEFT(ct.content, %d), '')", maxSnippetSourceLen)
+		relevanceCol = fmt.Sprintf("ts_rank(%s, %s) + COALESCE(ts_rank(ct.content_tsv, %s), 0)", metaVector, tsQuery, tsQuery)
 		args = append(args, searchQuery)
 		argN++
 	}
@@ -909,15 +962,15 @@ func SearchEvidence(c *gin.Context) {
 
 	// Sort
 	sortField := c.DefaultQuery("sort", "created_at")
-	if searchQuery != "" && sortField == "relevance" {
-		// Will handle below
-	}
 	allowedSort := map[string]string{
 		"collection_date": "ea.collection_date",
 		"expires_at":      "ea.expires_at",
 		"created_at":      "ea.created_at",
 		"title":           "ea.title",
 	}
+	if relevanceCol != "" {
+		allowedSort["relevance"] = relevanceCol
+	}
 	sortCol, ok := allowedSort[sortField]
 	if !ok {
 		sortCol = "ea.created_at"
@@ -939,13 +992,15 @@ func SearchEvidence(c *gin.Context) {
 			   ea.uploaded_by, COALESCE(u.first_name || ' ' || u.last_name, ''),
 			   ea.tags, ea.created_at, ea.updated_at,
 			   COALESCE((SELECT COUNT(*) FROM evidence_links el WHERE el.artifact_id = ea.id), 0),
-			   COALESCE((SELECT COUNT(*) FROM evidence_evaluations ee WHERE ee.artifact_id = ea.id), 0)
+			   COALESCE((SELECT COUNT(*) FROM evidence_evaluations ee WHERE ee.artifact_id = ea.id), 0),
+			   %s, %s, %s
 		FROM evidence_artifacts ea
 		LEFT JOIN users u ON u.id = ea.uploaded_by
+		LEFT JOIN evidence_contents ct ON ct.artifact_id = ea.id
 		WHERE %s
 		ORDER BY %s %s
 		LIMIT $%d OFFSET $%d
-	`, whereClause, sortCol, order, argN, argN+1)
+	`, metaMatchCol, contentMatchCol, contentCol, whereClause, sortCol, order, argN, argN+1)
 	args = append(args, perPage, offset)
 
 	rows, err := database.Query(query, args...)
@@ -971,6 +1026,8 @@ func SearchEvidence(c *gin.Context) {
 			tags                                                 pq.StringArray
 			createdAt, updatedAt                                 time.Time
 			linksCount, evalsCount                               int
+			metaMatch, contentMatch                              bool
+			content                                              string
 		)
 		if err := rows.Scan(&id, &title, &desc, &eType, &status,
 			&method, &fileName, &fileSize, &mimeType,
@@ -978,7 +1035,8 @@ func SearchEvidence(c *gin.Context) {
 			&freshDays, &sourceSystem,
 			&uploadedByID, &uploaderName,
 			&tags, &createdAt, &updatedAt,
-			&linksCount, &evalsCount); err != nil {
+			&linksCount, &evalsCount,
+			&metaMatch, &contentMatch, &content); err != nil {
 			continue
 		}
 
@@ -1000,6 +1058,19 @@ func SearchEvidence(c *gin.Context) {
 		} else {
 			item["uploaded_by"] = nil
 		}
+		if searchQuery != "" {
+			matchedIn := []string{}
+			if metaMatch {
+				matchedIn = append(matchedIn, "metadata")
+			}
+			snippets := []string{}
+			if contentMatch {
+				matchedIn = append(matchedIn, "content")
+				snippets = generateContentSnippets(content, searchQuery, 3)
+			}
+			item["matched_in"] = matchedIn
+			item["content_snippets"] = snippets
+		}
 		results = append(results, item)
 	}
 
@@ -1007,10 +1078,116 @@ func SearchEvidence(c *gin.Context) {
 	if searchQuery != "" {
 		resp["search_meta"] = gin.H{
 			"query":          searchQuery,
-			"matched_fields": []string{"title", "description", "tags", "source_system"},
+			"matched_fields": []string{"title", "description", "tags", "source_system", "content"},
 			"suggestion":     nil,
 		}
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// maxSnippetSourceLen limits how much extracted content is loaded per search result for snippets.
+const maxSnippetSourceLen = 262144
+
+// generateContentSnippets returns up to max passages of extracted file content around matches of
+// the query, in the style of generateMatchContext. The whole phrase is preferred; otherwise each
+// query word of three or more characters is matched. Text is HTML-escaped and matches are
+// wrapped in <mark> tags.
+func generateContentSnippets(text, query string, max int) []string {
+	lower := asciiLower(text)
+	phrase := asciiLower(strings.TrimSpace(query))
+	terms := []string{phrase}
+	if !strings.Contains(lower, phrase) {
+		terms = nil
+		for _, w := range strings.Fields(phrase) {
+			if len(w) >= 3 {
+				terms = append(terms, w)
+			}
+		}
+	}
+
+	type span struct{ start, end int }
+	var hits []span
+	for _, t := range terms {
+		for off := 0; t != "" && len(hits) < 200; {
+			i := strings.Index(lower[off:], t)
+			if i < 0 {
+				break
+			}
+			hits = append(hits, span{off + i, off + i + len(t)})
+			off += i + len(t)
+		}
+	}
+	if len(hits) == 0 {
+		return []string{}
+	}
+	sort.Slice(hits, func(i, j int) bool { return hits[i].start < hits[j].start })
+
+	// Group hits into windows of 60 bytes either side, merging overlapping windows.
+	var windows []span
+	var windowHits [][]span
+	for _, h := range hits {
+		start := h.start - 60
+		if start < 0 {
+			start = 0
+		}
+		end := h.end + 60
+		if end > len(text) {
+			end = len(text)
+		}
+		if n := len(windows); n > 0 && start <= windows[n-1].end {
+			if end > windows[n-1].end {
+				windows[n-1].end = end
+			}
+			windowHits[n-1] = append(windowHits[n-1], h)
+			continue
+		}
+		if len(windows) == max {
+			break
+		}
+		windows = append(windows, span{start, end})
+		windowHits = append(windowHits, []span{h})
+	}
+
+	snippets := make([]string, 0, len(windows))
+	for i, w := range windows {
+		start, end := w.start, w.end
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start--
+		}
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+
+		var b strings.Builder
+		if start > 0 {
+			b.WriteString("...")
+		}
+		pos := start
+		for _, h := range windowHits[i] {
+			if h.start < pos {
+				continue
+			}
+			b.WriteString(html.EscapeString(text[pos:h.start]))
+			b.WriteString("<mark>" + html.EscapeString(text[h.start:h.end]) + "</mark>")
+			pos = h.end
+		}
+		b.WriteString(html.EscapeString(text[pos:end]))
+		if end < len(text) {
+			b.WriteString("...")
+		}
+		snippets = append(snippets, strings.Join(strings.Fields(b.String()), " "))
+	}
+	return snippets
+}
+
+// asciiLower lowercases ASCII letters only, so byte offsets match the original text.
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,605 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// maxCollectionIntervalMin caps collection schedules at 30 days.
+const maxCollectionIntervalMin = 43200
+
+// defaultCollectionEvidenceTypes is the evidence type a job produces when none is given.
+var defaultCollectionEvidenceTypes = map[string]string{
+	models.CollectionSourceHTTPJSON:    "api_response",
+	models.CollectionSourceS3Listing:   "configuration_export",
+	models.CollectionSourceAgentUpload: "log_sample",
+}
+
+const evidenceCollectionJobColumns = `
+	j.id, j.name, j.description, j.is_active, j.source_type, j.source_config::text,
+	j.artifact_title, j.evidence_type, j.freshness_period_days, j.tags, j.control_ids::text[],
+	j.current_artifact_id, j.schedule_interval_min, j.next_run_at, j.last_run_at,
+	j.last_run_status, j.consecutive_failures, j.created_by, j.created_at, j.updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanEvidenceCollectionJob scans evidenceCollectionJobColumns into a response with secrets redacted.
+func scanEvidenceCollectionJob(row rowScanner) (gin.H, error) {
+	var (
+		j            models.EvidenceCollectionJob
+		tags, ctrlID pq.StringArray
+	)
+	if err := row.Scan(&j.ID, &j.Name, &j.Description, &j.IsActive, &j.SourceType, &j.SourceConfig,
+		&j.ArtifactTitle, &j.EvidenceType, &j.FreshnessPeriodDays, &tags, &ctrlID,
+		&j.CurrentArtifactID, &j.ScheduleIntervalMin, &j.NextRunAt, &j.LastRunAt,
+		&j.LastRunStatus, &j.ConsecutiveFailures, &j.CreatedBy, &j.CreatedAt, &j.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = pq.StringArray{}
+	}
+	if ctrlID == nil {
+		ctrlID = pq.StringArray{}
+	}
+	return gin.H{
+		"id":                    j.ID,
+		"name":                  j.Name,
+		"description":           j.Description,
+		"is_active":             j.IsActive,
+		"source_type":           j.SourceType,
+		"source_config":         services.RedactSourceConfig([]byte(j.SourceConfig)),
+		"artifact_title":        j.ArtifactTitle,
+		"evidence_type":         j.EvidenceType,
+		"freshness_period_days": j.FreshnessPeriodDays,
+		"tags":                  []string(tags),
+		"control_ids":           []string(ctrlID),
+		"current_artifact_id":   j.CurrentArtifactID,
+		"schedule_interval_min": j.ScheduleIntervalMin,
+		"next_run_at":           j.NextRunAt,
+		"last_run_at":           j.LastRunAt,
+		"last_run_status":       j.LastRunStatus,
+		"consecutive_failures":  j.ConsecutiveFailures,
+		"created_by":            j.CreatedBy,
+		"created_at":            j.CreatedAt,
+		"updated_at":            j.UpdatedAt,
+	}, nil
+}
+
+// validateCollectionControls checks that all control IDs belong to the org.
+func validateCollectionControls(orgID string, controlIDs []string) bool {
+	if len(controlIDs) == 0 {
+		return true
+	}
+	var found int
+	database.QueryRow(`SELECT COUNT(*) FROM controls WHERE org_id = $1 AND id = ANY($2::uuid[])`,
+		orgID, pq.Array(controlIDs)).Scan(&found)
+	return found == len(controlIDs)
+}
+
+// ListEvidenceCollectionJobs lists evidence collection jobs.
+func ListEvidenceCollectionJobs(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	where := []string{"j.org_id = $1"}
+	args := []interface{}{orgID}
+	argN := 2
+
+	if v := c.Query("source_type"); v != "" {
+		where = append(where, fmt.Sprintf("j.source_type = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+	if v := c.Query("is_active"); v != "" {
+		where = append(where, fmt.Sprintf("j.is_active = $%d", argN))
+		args = append(args, v == "true")
+		argN++
+	}
+	if v := c.Query("last_run_status"); v != "" {
+		where = append(where, fmt.Sprintf("j.last_run_status = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+
+	whereClause := joinWhere(where)
+
+	var total int
+	database.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM evidence_collection_jobs j WHERE %s`, whereClause), args...).Scan(&total)
+
+	offset := (page - 1) * perPage
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM evidence_collection_jobs j
+		WHERE %s
+		ORDER BY j.name
+		LIMIT $%d OFFSET $%d
+	`, evidenceCollectionJobColumns, whereClause, argN, argN+1)
+	args = append(args, perPage, offset)
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list evidence collection jobs")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []gin.H{}
+	for rows.Next() {
+		item, err := scanEvidenceCollectionJob(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to scan evidence collection job")
+			continue
+		}
+		results = append(results, item)
+	}
+
+	c.JSON(http.StatusOK, listResponse(c, results, total, page, perPage))
+}
+
+// CreateEvidenceCollectionJob creates a scheduled evidence collection job.
+func CreateEvidenceCollectionJob(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	var req models.CreateEvidenceCollectionJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+	if len(req.Name) > 255 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Name must be at most 255 characters"))
+		return
+	}
+	if !models.IsValidCollectionSource(req.SourceType) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid source_type"))
+		return
+	}
+	if _, err := services.ParseCollectionSourceConfig(req.SourceType, req.SourceConfig); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", err.Error()))
+		return
+	}
+	if req.ScheduleIntervalMin < models.MinCollectionIntervalMin || req.ScheduleIntervalMin > maxCollectionIntervalMin {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+			fmt.Sprintf("schedule_interval_min must be between %d and %d", models.MinCollectionIntervalMin, maxCollectionIntervalMin)))
+		return
+	}
+
+	eType := defaultCollectionEvidenceTypes[req.SourceType]
+	if req.EvidenceType != nil {
+		if !models.IsValidEvidenceType(*req.EvidenceType) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid evidence type"))
+			return
+		}
+		eType = *req.EvidenceType
+	}
+	if req.FreshnessPeriodDays != nil && *req.FreshnessPeriodDays <= 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "freshness_period_days must be positive"))
+		return
+	}
+	title := req.Name
+	if req.ArtifactTitle != nil && *req.ArtifactTitle != "" {
+		if len(*req.ArtifactTitle) > 500 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "artifact_title must be at most 500 characters"))
+			return
+		}
+		title = *req.ArtifactTitle
+	}
+	if req.Tags == nil {
+		req.Tags = []string{}
+	}
+	if req.ControlIDs == nil {
+		req.ControlIDs = []string{}
+	}
+	if !validateCollectionControls(orgID, req.ControlIDs) {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "One or more controls not found"))
+		return
+	}
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	sourceConfig := string(req.SourceConfig)
+	if sourceConfig == "" {
+		sourceConfig = "{}"
+	}
+
+	// Pull sources run on the next worker tick; agent jobs are due one interval from now.
+	firstRunDelay := 0
+	if req.SourceType == models.CollectionSourceAgentUpload {
+		firstRunDelay = req.ScheduleIntervalMin
+	}
+
+	jobID := uuid.New().String()
+	row := database.QueryRow(fmt.Sprintf(`
+		INSERT INTO evidence_collection_jobs AS j (id, org_id, name, description, is_active,
+			source_type, source_config, artifact_title, evidence_type, freshness_period_days,
+			tags, control_ids, schedule_interval_min, next_run_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::uuid[], $13,
+			NOW() + make_interval(mins => $14), $15)
+		RETURNING %s
+	`, evidenceCollectionJobColumns), jobID, orgID, req.Name, req.Description, isActive,
+		req.SourceType, sourceConfig, title, eType, req.FreshnessPeriodDays,
+		pq.Array(req.Tags), pq.Array(req.ControlIDs), req.ScheduleIntervalMin,
+		firstRunDelay, userID)
+	job, err := scanEvidenceCollectionJob(row)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create evidence collection job")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "evidence_collection_job.created", "evidence_collection_job", &jobID, map[string]interface{}{
+		"name": req.Name, "source_type": req.SourceType, "schedule_interval_min": req.ScheduleIntervalMin,
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, job))
+}
+
+// GetEvidenceCollectionJob returns a collection job with its most recent runs.
+func GetEvidenceCollectionJob(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	jobID := c.Param("id")
+
+	job, err := scanEvidenceCollectionJob(database.QueryRow(fmt.Sprintf(`
+		SELECT %s FROM evidence_collection_jobs j WHERE j.id = $1 AND j.org_id = $2
+	`, evidenceCollectionJobColumns), jobID, orgID))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence collection job not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get evidence collection job")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	runs, err := queryEvidenceCollectionRuns(jobID, orgID, 10, 0)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list evidence collection runs")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	job["recent_runs"] = runs
+
+	c.JSON(http.StatusOK, successResponse(c, job))
+}
+
+// UpdateEvidenceCollectionJob updates a collection job. source_config is replaced as a whole,
+// so secrets must be resent when it is changed.
+func UpdateEvidenceCollectionJob(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	jobID := c.Param("id")
+
+	var sourceType string
+	err := database.QueryRow(`SELECT source_type FROM evidence_collection_jobs WHERE id = $1 AND org_id = $2`,
+		jobID, orgID).Scan(&sourceType)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence collection job not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get evidence collection job")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	var req models.UpdateEvidenceCollectionJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body"))
+		return
+	}
+
+	sets := []string{}
+	args := []interface{}{}
+	argN := 1
+	changed := []string{}
+	add := func(field, expr string, v interface{}) {
+		sets = append(sets, fmt.Sprintf(expr, argN))
+		args = append(args, v)
+		argN++
+		changed = append(changed, field)
+	}
+
+	if req.Name != nil {
+		if *req.Name == "" || len(*req.Name) > 255 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Name must be 1-255 characters"))
+			return
+		}
+		add("name", "name = $%d", *req.Name)
+	}
+	if req.Description != nil {
+		add("description", "description = $%d", *req.Description)
+	}
+	if req.SourceConfig != nil {
+		if _, err := services.ParseCollectionSourceConfig(sourceType, req.SourceConfig); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", err.Error()))
+			return
+		}
+		add("source_config", "source_config = $%d", string(req.SourceConfig))
+	}
+	if req.ArtifactTitle != nil {
+		if *req.ArtifactTitle == "" || len(*req.ArtifactTitle) > 500 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "artifact_title must be 1-500 characters"))
+			return
+		}
+		add("artifact_title", "artifact_title = $%d", *req.ArtifactTitle)
+	}
+	if req.EvidenceType != nil {
+		if !models.IsValidEvidenceType(*req.EvidenceType) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid evidence type"))
+			return
+		}
+		add("evidence_type", "evidence_type = $%d", *req.EvidenceType)
+	}
+	if req.FreshnessPeriodDays != nil {
+		if *req.FreshnessPeriodDays <= 0 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "freshness_period_days must be positive"))
+			return
+		}
+		add("freshness_period_days", "freshness_period_days = $%d", *req.FreshnessPeriodDays)
+	}
+	if req.Tags != nil {
+		add("tags", "tags = $%d", pq.Array(req.Tags))
+	}
+	if req.ControlIDs != nil {
+		if !validateCollectionControls(orgID, req.ControlIDs) {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "One or more controls not found"))
+			return
+		}
+		add("control_ids", "control_ids = $%d::uuid[]", pq.Array(req.ControlIDs))
+	}
+	interval := "j.schedule_interval_min"
+	if req.ScheduleIntervalMin != nil {
+		if *req.ScheduleIntervalMin < models.MinCollectionIntervalMin || *req.ScheduleIntervalMin > maxCollectionIntervalMin {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+				fmt.Sprintf("schedule_interval_min must be between %d and %d", models.MinCollectionIntervalMin, maxCollectionIntervalMin)))
+			return
+		}
+		add("schedule_interval_min", "schedule_interval_min = $%d", *req.ScheduleIntervalMin)
+		interval = fmt.Sprintf("$%d", argN-1)
+	}
+	if req.IsActive != nil {
+		add("is_active", "is_active = $%d", *req.IsActive)
+	}
+
+	// A new interval counts from the last run. Re-activating a paused job counts from now, since
+	// its old next_run_at has usually passed and would make it run straight away.
+	reactivate := req.IsActive != nil && *req.IsActive
+	if req.ScheduleIntervalMin != nil || reactivate {
+		nextRun := "j.next_run_at"
+		if req.ScheduleIntervalMin != nil {
+			nextRun = fmt.Sprintf("COALESCE(j.last_run_at, NOW()) + make_interval(mins => %s)", interval)
+		}
+		if reactivate {
+			nextRun = fmt.Sprintf("CASE WHEN j.is_active THEN %s ELSE NOW() + make_interval(mins => %s) END", nextRun, interval)
+		}
+		sets = append(sets, "next_run_at = "+nextRun)
+	}
+
+	if len(sets) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "No fields to update"))
+		return
+	}
+
+	args = append(args, jobID, orgID)
+	job, err := scanEvidenceCollectionJob(database.QueryRow(fmt.Sprintf(`
+		UPDATE evidence_collection_jobs AS j SET %s
+		WHERE j.id = $%d AND j.org_id = $%d
+		RETURNING %s
+	`, joinStrings(sets), argN, argN+1, evidenceCollectionJobColumns), args...))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update evidence collection job")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "evidence_collection_job.updated", "evidence_collection_job", &jobID, map[string]interface{}{
+		"changed": changed,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, job))
+}
+
+// DeleteEvidenceCollectionJob deletes a collection job. Collected evidence is kept.
+func DeleteEvidenceCollectionJob(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	jobID := c.Param("id")
+
+	res, err := database.Exec(`DELETE FROM evidence_collection_jobs WHERE id = $1 AND org_id = $2`, jobID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete evidence collection job")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence collection job not found"))
+		return
+	}
+
+	middleware.LogAudit(c, "evidence_collection_job.deleted", "evidence_collection_job", &jobID, nil)
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{"id": jobID, "deleted": true}))
+}
+
+// RunEvidenceCollectionJob runs an HTTP or S3 collection job immediately, including
+// inactive jobs so a source can be tested before the schedule is enabled.
+func RunEvidenceCollectionJob(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	jobID := c.Param("id")
+
+	var sourceType string
+	err := database.QueryRow(`SELECT source_type FROM evidence_collection_jobs WHERE id = $1 AND org_id = $2`,
+		jobID, orgID).Scan(&sourceType)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence collection job not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get evidence collection job")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if sourceType == models.CollectionSourceAgentUpload {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", "agent_upload jobs run when an agent uploads output"))
+		return
+	}
+
+	executeEvidenceCollection(c, orgID, jobID, models.CollectionTriggerManual, &userID, nil)
+}
+
+// UploadEvidenceCollectionOutput accepts command output from an agent for an agent_upload job.
+func UploadEvidenceCollectionOutput(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	jobID := c.Param("id")
+
+	var sourceType string
+	var isActive bool
+	err := database.QueryRow(`SELECT source_type, is_active FROM evidence_collection_jobs WHERE id = $1 AND org_id = $2`,
+		jobID, orgID).Scan(&sourceType, &isActive)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence collection job not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get evidence collection job")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if sourceType != models.CollectionSourceAgentUpload {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", "Only agent_upload jobs accept agent output"))
+		return
+	}
+	if !isActive {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", "Evidence collection job is inactive"))
+		return
+	}
+
+	var req models.AgentOutputRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+
+	executeEvidenceCollection(c, orgID, jobID, models.CollectionTriggerAgent, &userID, &req)
+}
+
+// executeEvidenceCollection runs a job and writes the run (201) or the collection error (422).
+func executeEvidenceCollection(c *gin.Context, orgID, jobID, trigger string, userID *string, agent *models.AgentOutputRequest) {
+	if objectStore == nil {
+		c.JSON(http.StatusServiceUnavailable, errorResponse("SERVICE_UNAVAILABLE", "Storage service not available"))
+		return
+	}
+	run, err := services.RunEvidenceCollectionJob(c.Request.Context(), database.DB, objectStore,
+		orgID, jobID, trigger, userID, agent)
+	if err == services.ErrCollectionJobNotFound {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence collection job not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID).Msg("Failed to run evidence collection job")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "evidence_collection_job.run", "evidence_collection_job", &jobID, map[string]interface{}{
+		"run_id": run.ID, "trigger": trigger, "status": run.Status, "artifact_id": run.ArtifactID,
+	})
+
+	if run.Status != models.CollectionRunSuccess {
+		c.JSON(http.StatusUnprocessableEntity, errorResponseWithDetails("COLLECTION_FAILED", *run.ErrorMessage,
+			[]gin.H{{"run_id": run.ID}}))
+		return
+	}
+	c.JSON(http.StatusCreated, successResponse(c, run))
+}
+
+// ListEvidenceCollectionRuns lists the run history of a collection job.
+func ListEvidenceCollectionRuns(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	jobID := c.Param("id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	var total int
+	err := database.QueryRow(`
+		SELECT COUNT(r.id) FROM evidence_collection_jobs j
+		LEFT JOIN evidence_collection_runs r ON r.job_id = j.id
+		WHERE j.id = $1 AND j.org_id = $2
+		GROUP BY j.id
+	`, jobID, orgID).Scan(&total)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence collection job not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to count evidence collection runs")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	runs, err := queryEvidenceCollectionRuns(jobID, orgID, perPage, (page-1)*perPage)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list evidence collection runs")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	c.JSON(http.StatusOK, listResponse(c, runs, total, page, perPage))
+}
+
+func queryEvidenceCollectionRuns(jobID, orgID string, limit, offset int) ([]models.EvidenceCollectionRun, error) {
+	rows, err := database.Query(`
+		SELECT id, job_id, status, trigger_type, artifact_id, artifact_version, file_size,
+			checksum_sha256, error_message, triggered_by, started_at, completed_at
+		FROM evidence_collection_runs
+		WHERE job_id = $1 AND org_id = $2
+		ORDER BY started_at DESC
+		LIMIT $3 OFFSET $4
+	`, jobID, orgID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []models.EvidenceCollectionRun{}
+	for rows.Next() {
+		var r models.EvidenceCollectionRun
+		if err := rows.Scan(&r.ID, &r.JobID, &r.Status, &r.TriggerType, &r.ArtifactID, &r.ArtifactVersion,
+			&r.FileSize, &r.ChecksumSHA256, &r.ErrorMessage, &r.TriggeredBy, &r.StartedAt, &r.CompletedAt); err != nil {
+			log.Error().Err(err).Msg("Failed to scan evidence collection run")
+			continue
+		}
+		runs = append(runs, r)
+	}
+	return runs, nil
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collectionJobRows(sourceType, sourceConfig string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{
+		"id", "name", "description", "is_active", "source_type", "source_config",
+		"artifact_title", "evidence_type", "freshness_period_days", "tags", "control_ids",
+		"current_artifact_id", "schedule_interval_min", "next_run_at", "last_run_at",
+		"last_run_status", "consecutive_failures", "created_by", "created_at", "updated_at",
+	}).AddRow(
+		"ecj001", "IdP MFA settings", nil, true, sourceType, sourceConfig,
+		"IdP MFA configuration", "api_response", 90, "{iam}", "{c001}",
+		nil, 1440, now, nil,
+		nil, 0, "u0000000-0000-0000-0000-000000000001", now, now,
+	)
+}
+
+func TestCreateEvidenceCollectionJob_Success(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence-collection-jobs", CreateEvidenceCollectionJob)
+
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("INSERT INTO evidence_collection_jobs").WillReturnRows(
+		collectionJobRows("http_json", `{"url": "https://idp.example.com/api/mfa", "headers": {"Authorization": "Bearer s3cret"}}`),
+	)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":        "IdP MFA settings",
+		"source_type": "http_json",
+		"source_config": map[string]interface{}{
+			"url":     "https://idp.example.com/api/mfa",
+			"headers": map[string]string{"Authorization": "Bearer s3cret"},
+		},
+		"control_ids":           []string{"c001"},
+		"schedule_interval_min": 1440,
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence-collection-jobs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	cfg := data["source_config"].(map[string]interface{})
+	assert.Equal(t, "********", cfg["headers"].(map[string]interface{})["Authorization"])
+	assert.Equal(t, []interface{}{"c001"}, data["control_ids"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateEvidenceCollectionJob_InvalidSourceConfig(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence-collection-jobs", CreateEvidenceCollectionJob)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":                  "Bad source",
+		"source_type":           "http_json",
+		"source_config":         map[string]interface{}{"url": "file:///etc/passwd"},
+		"schedule_interval_min": 60,
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence-collection-jobs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateEvidenceCollectionJob_IntervalTooShort(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence-collection-jobs", CreateEvidenceCollectionJob)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":                  "Bucket listing",
+		"source_type":           "s3_listing",
+		"source_config":         map[string]interface{}{"endpoint": "s3.amazonaws.com", "bucket": "logs"},
+		"schedule_interval_min": 5,
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence-collection-jobs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetEvidenceCollectionJob_NotFound(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/evidence-collection-jobs/:id", GetEvidenceCollectionJob)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence-collection-jobs/nonexistent", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUpdateEvidenceCollectionJob_ReactivateReschedules(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.PUT("/api/v1/evidence-collection-jobs/:id", UpdateEvidenceCollectionJob)
+
+	mock.ExpectQuery("SELECT source_type FROM evidence_collection_jobs").WillReturnRows(
+		sqlmock.NewRows([]string{"source_type"}).AddRow("http_json"),
+	)
+	mock.ExpectQuery(`UPDATE evidence_collection_jobs AS j SET is_active = \$1, ` +
+		`next_run_at = CASE WHEN j.is_active THEN j.next_run_at ELSE NOW\(\) \+ make_interval\(mins => j.schedule_interval_min\) END`).
+		WithArgs(true, "ecj001", sqlmock.AnyArg()).
+		WillReturnRows(collectionJobRows("http_json", `{"url": "https://idp.example.com/api/mfa"}`))
+
+	body, _ := json.Marshal(map[string]interface{}{"is_active": true})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/evidence-collection-jobs/ecj001", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateEvidenceCollectionJob_IntervalAndReactivate(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.PUT("/api/v1/evidence-collection-jobs/:id", UpdateEvidenceCollectionJob)
+
+	mock.ExpectQuery("SELECT source_type FROM evidence_collection_jobs").WillReturnRows(
+		sqlmock.NewRows([]string{"source_type"}).AddRow("http_json"),
+	)
+	mock.ExpectQuery(`next_run_at = CASE WHEN j.is_active ` +
+		`THEN COALESCE\(j.last_run_at, NOW\(\)\) \+ make_interval\(mins => \$1\) ` +
+		`ELSE NOW\(\) \+ make_interval\(mins => \$1\) END`).
+		WithArgs(60, true, "ecj001", sqlmock.AnyArg()).
+		WillReturnRows(collectionJobRows("http_json", `{"url": "https://idp.example.com/api/mfa"}`))
+
+	body, _ := json.Marshal(map[string]interface{}{"schedule_interval_min": 60, "is_active": true})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/evidence-collection-jobs/ecj001", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadEvidenceCollectionOutput_CreatesVersion(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence-collection-jobs/:id/agent-output", UploadEvidenceCollectionOutput)
+	store := useMemoryObjectStore(t)
+
+	mock.ExpectQuery("SELECT source_type, is_active").WillReturnRows(
+		sqlmock.NewRows([]string{"source_type", "is_active"}).AddRow("agent_upload", true),
+	)
+	mock.ExpectQuery("SELECT id, org_id, name, source_type").WillReturnRows(
+		sqlmock.NewRows([]string{
+			"id", "org_id", "name", "source_type", "source_config", "artifact_title", "evidence_type",
+			"freshness_period_days", "tags", "control_ids", "current_artifact_id", "created_by",
+		}).AddRow(
+			"ecj001", "a0000000-0000-0000-0000-000000000001", "sshd config", "agent_upload",
+			`{"command": "sshd -T"}`, "sshd effective configuration", "configuration_export",
+			30, "{linux}", "{c001}", "ea001", "u0000000-0000-0000-0000-000000000001",
+		),
+	)
+	mock.ExpectBegin()
+	mock.ExpectQuery("WITH root AS").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "version", "root", "chain_hash"}).AddRow("ea003", 3, "ea001", ""),
+	)
+	mock.ExpectExec("UPDATE evidence_artifacts SET is_current = FALSE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_artifacts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_links .* SELECT gen_random_uuid").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_links .* FROM controls").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectExec("INSERT INTO evidence_collection_runs").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE evidence_collection_jobs").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"command":   "sshd -T",
+		"exit_code": 0,
+		"host":      "bastion-01",
+		"output":    "permitrootlogin no\npasswordauthentication no\n",
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence-collection-jobs/ecj001/agent-output", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "success", data["status"])
+	assert.Equal(t, "agent", data["trigger_type"])
+	assert.Equal(t, float64(4), data["artifact_version"])
+	assert.Len(t, data["checksum_sha256"], 64)
+	assert.Len(t, store.objects, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadEvidenceCollectionOutput_CommandMismatch(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence-collection-jobs/:id/agent-output", UploadEvidenceCollectionOutput)
+	useMemoryObjectStore(t)
+
+	mock.ExpectQuery("SELECT source_type, is_active").WillReturnRows(
+		sqlmock.NewRows([]string{"source_type", "is_active"}).AddRow("agent_upload", true),
+	)
+	mock.ExpectQuery("SELECT id, org_id, name, source_type").WillReturnRows(
+		sqlmock.NewRows([]string{
+			"id", "org_id", "name", "source_type", "source_config", "artifact_title", "evidence_type",
+			"freshness_period_days", "tags", "control_ids", "current_artifact_id", "created_by",
+		}).AddRow(
+			"ecj001", "a0000000-0000-0000-0000-000000000001", "sshd config", "agent_upload",
+			`{"command": "sshd -T"}`, "sshd effective configuration", "configuration_export",
+			nil, "{}", "{}", nil, nil,
+		),
+	)
+	mock.ExpectExec("INSERT INTO evidence_collection_runs").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE evidence_collection_jobs").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	body, _ := json.Marshal(map[string]interface{}{"command": "cat /etc/shadow", "output": "root:..."})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence-collection-jobs/ecj001/agent-output", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "COLLECTION_FAILED")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadEvidenceCollectionOutput_WrongSource(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence-collection-jobs/:id/agent-output", UploadEvidenceCollectionOutput)
+
+	mock.ExpectQuery("SELECT source_type, is_active").WillReturnRows(
+		sqlmock.NewRows([]string{"source_type", "is_active"}).AddRow("http_json", true),
+	)
+
+	body, _ := json.Marshal(map[string]interface{}{"output": "ok"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence-collection-jobs/ecj001/agent-output", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestRunEvidenceCollectionJob_AgentJobRejected(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence-collection-jobs/:id/run", RunEvidenceCollectionJob)
+
+	mock.ExpectQuery("SELECT source_type FROM evidence_collection_jobs").WillReturnRows(
+		sqlmock.NewRows([]string{"source_type"}).AddRow("agent_upload"),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence-collection-jobs/ecj001/run", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
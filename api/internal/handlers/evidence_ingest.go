@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// ingestFormList reads a repeated or comma-separated multipart field.
+func ingestFormList(c *gin.Context, field string) []string {
+	out := []string{}
+	for _, v := range c.PostFormArray(field) {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// ingestMIMEType resolves the MIME type of an uploaded file. When the client sent a generic
+// type it falls back to the file extension, then to content sniffing.
+func ingestMIMEType(declared, fileName string, data []byte) string {
+	mt, _, err := mime.ParseMediaType(declared)
+	if err == nil && mt != "" && mt != "application/octet-stream" {
+		return mt
+	}
+	if mt, _, err = mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(fileName))); err == nil && mt != "" {
+		return mt
+	}
+	mt, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	return mt
+}
+
+// IngestEvidence accepts a file and metadata from a pipeline in a single multipart request,
+// authenticated by an org API key with the evidence:ingest scope. With an idempotency key the
+// upload versions the artifact previously created for that key; re-sending identical content
+// returns the current version without creating a new one.
+func IngestEvidence(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	apiKeyID := middleware.GetAPIKeyID(c)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Multipart field 'file' is required"))
+		return
+	}
+	if fileHeader.Size <= 0 || fileHeader.Size > models.MaxFileSize {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "File size must be between 1 byte and 100MB"))
+		return
+	}
+	fileName := sanitizeFileName(fileHeader.Filename)
+	if fileName == "" || len(fileName) > 255 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "File name must be 1-255 characters"))
+		return
+	}
+
+	eType := c.PostForm("evidence_type")
+	if !models.IsValidEvidenceType(eType) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid evidence type"))
+		return
+	}
+	title := c.DefaultPostForm("title", fileName)
+	if len(title) > 500 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Title must be at most 500 characters"))
+		return
+	}
+	var description *string
+	if v := c.PostForm("description"); v != "" {
+		description = &v
+	}
+	var freshDays *int
+	if v := c.PostForm("freshness_period_days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "freshness_period_days must be a positive integer"))
+			return
+		}
+		freshDays = &n
+	}
+	collDate := time.Now().UTC()
+	if v := c.PostForm("collection_date"); v != "" {
+		collDate, err = time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid collection_date format"))
+			return
+		}
+		if collDate.After(time.Now()) {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", "collection_date cannot be in the future"))
+			return
+		}
+	}
+	sourceSystem := c.DefaultPostForm("source_system", "ci_pipeline")
+	idemKey := c.GetHeader("Idempotency-Key")
+	if idemKey == "" {
+		idemKey = c.PostForm("idempotency_key")
+	}
+	if len(idemKey) > 255 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Idempotency key must be at most 255 characters"))
+		return
+	}
+	tags := ingestFormList(c, "tags")
+	controlIDs := ingestFormList(c, "control_ids")
+	if !validateCollectionControls(orgID, controlIDs) {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "One or more controls not found"))
+		return
+	}
+
+	f, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Could not read uploaded file"))
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(f, models.MaxFileSize+1))
+	f.Close()
+	if err != nil || len(data) == 0 || len(data) > models.MaxFileSize {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "File size must be between 1 byte and 100MB"))
+		return
+	}
+	mimeType := ingestMIMEType(fileHeader.Header.Get("Content-Type"), fileName, data)
+	if !models.IsValidMIMEType(mimeType) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "MIME type not allowed: "+mimeType))
+		return
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	if objectStore == nil {
+		c.JSON(http.StatusServiceUnavailable, errorResponse("SERVICE_UNAVAILABLE", "Storage service not available"))
+		return
+	}
+
+	var uploadedBy *string
+	if v, ok := c.Get(middleware.ContextKeyAPIKeyOwner); ok {
+		if s, ok := v.(string); ok {
+			uploadedBy = &s
+		}
+	}
+	metadata := map[string]interface{}{"api_key_id": apiKeyID}
+	if idemKey != "" {
+		metadata["idempotency_key"] = idemKey
+	}
+
+	spec := services.EvidenceVersionSpec{
+		OrgID:               orgID,
+		Title:               title,
+		Description:         description,
+		EvidenceType:        eType,
+		CollectionMethod:    "api_ingestion",
+		SourceSystem:        &sourceSystem,
+		FreshnessPeriodDays: freshDays,
+		Tags:                tags,
+		Metadata:            metadata,
+		UploadedBy:          uploadedBy,
+		ControlIDs:          controlIDs,
+		CollectionDate:      collDate,
+		FileName:            fileName,
+		MIMEType:            mimeType,
+		Data:                data,
+	}
+	var stored *services.StoredEvidenceVersion
+	created := true
+	if idemKey != "" {
+		var keyRef *string
+		if apiKeyID != "" {
+			keyRef = &apiKeyID
+		}
+		stored, created, err = services.IngestEvidenceVersion(c.Request.Context(), database.DB, objectStore, idemKey, keyRef, spec)
+	} else {
+		stored, err = services.StoreEvidenceVersion(c.Request.Context(), database.DB, objectStore, spec)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to store ingested evidence")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	// Same content under the same key: report the current version instead of adding one.
+	if !created {
+		c.JSON(http.StatusOK, successResponse(c, gin.H{
+			"id":                 stored.ID,
+			"parent_artifact_id": stored.RootArtifactID,
+			"version":            stored.Version,
+			"checksum_sha256":    checksum,
+			"idempotency_key":    idemKey,
+			"created":            false,
+		}))
+		return
+	}
+
+	middleware.LogAudit(c, "evidence.ingested", "evidence", &stored.ID, map[string]interface{}{
+		"api_key_id": apiKeyID, "version": stored.Version, "idempotency_key": idemKey,
+		"checksum_sha256": checksum,
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, gin.H{
+		"id":                  stored.ID,
+		"parent_artifact_id":  stored.RootArtifactID,
+		"version":             stored.Version,
+		"previous_version_id": stored.PreviousVersionID,
+		"status":              "pending_review",
+		"title":               title,
+		"evidence_type":       eType,
+		"file_name":           fileName,
+		"file_size":           stored.FileSize,
+		"mime_type":           mimeType,
+		"checksum_sha256":     stored.ChecksumSHA256,
+		"idempotency_key":     idemKey,
+		"linked_controls":     controlIDs,
+		"created":             true,
+	}))
+}
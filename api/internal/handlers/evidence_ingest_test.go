@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSBOM = `{"bomFormat": "CycloneDX", "specVersion": "1.5", "components": []}`
+
+func ingestRequest(t *testing.T, fields map[string]string, fileName, content string) *http.Request {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		require.NoError(t, mw.WriteField(k, v))
+	}
+	if fileName != "" {
+		fw, err := mw.CreateFormFile("file", fileName)
+		require.NoError(t, err)
+		fw.Write([]byte(content))
+	}
+	require.NoError(t, mw.Close())
+
+	req, _ := http.NewRequest("POST", "/api/v1/ingest/evidence", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestCreateAPIKey_Success(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/api-keys", CreateAPIKey)
+
+	mock.ExpectExec("INSERT INTO org_api_keys").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name": "GitHub Actions", "scopes": []string{"evidence:ingest"}, "expires_in_days": 90,
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/api-keys", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	key := data["key"].(string)
+	assert.True(t, strings.HasPrefix(key, models.APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(key, data["key_prefix"].(string)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAPIKey_InvalidScope(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/api-keys", CreateAPIKey)
+
+	body, _ := json.Marshal(map[string]interface{}{"name": "Too broad", "scopes": []string{"admin:*"}})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/api-keys", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAPIKeyRequired_MissingScope(t *testing.T) {
+	router, mock := setupTestRouter()
+	middleware.SetAPIKeyDB(database.DB)
+	defer middleware.SetAPIKeyDB(nil)
+	router.POST("/api/v1/ingest/evidence", middleware.APIKeyRequired(models.ScopeEvidenceIngest), IngestEvidence)
+
+	mock.ExpectQuery("SELECT k.id, k.org_id, k.created_by, k.scopes").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "org_id", "created_by", "scopes"}).
+			AddRow("k001", "a0000000-0000-0000-0000-000000000001", nil, "{}"),
+	)
+
+	w := httptest.NewRecorder()
+	req := ingestRequest(t, map[string]string{"evidence_type": "other"}, "sbom.json", testSBOM)
+	req.Header.Set("X-API-Key", "rpk_testkey")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAPIKeyRequired_NoKey(t *testing.T) {
+	router, _ := setupTestRouter()
+	middleware.SetAPIKeyDB(database.DB)
+	defer middleware.SetAPIKeyDB(nil)
+	router.POST("/api/v1/ingest/evidence", middleware.APIKeyRequired(models.ScopeEvidenceIngest), IngestEvidence)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, ingestRequest(t, map[string]string{"evidence_type": "other"}, "sbom.json", testSBOM))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestIngestEvidence_NewArtifact(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/ingest/evidence", IngestEvidence)
+	useMemoryObjectStore(t)
+
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WithArgs("a0000000-0000-0000-0000-000000000001", "api-service/sbom").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT ei.artifact_id").WillReturnRows(sqlmock.NewRows(nil))
+	mock.ExpectExec("INSERT INTO evidence_artifacts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_links").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_ingestions").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req := ingestRequest(t, map[string]string{
+		"evidence_type":         "vulnerability_report",
+		"control_ids":           "c001",
+		"tags":                  "sbom,release",
+		"freshness_period_days": "30",
+		"idempotency_key":       "api-service/sbom",
+	}, "sbom.json", testSBOM)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	sum := sha256.Sum256([]byte(testSBOM))
+	assert.Equal(t, hex.EncodeToString(sum[:]), data["checksum_sha256"])
+	assert.Equal(t, float64(1), data["version"])
+	assert.Equal(t, "application/json", data["mime_type"])
+	assert.Equal(t, true, data["created"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestEvidence_IdempotentReplay(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/ingest/evidence", IngestEvidence)
+	store := useMemoryObjectStore(t)
+
+	sum := sha256.Sum256([]byte(testSBOM))
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT ei.artifact_id").WillReturnRows(
+		sqlmock.NewRows([]string{"artifact_id", "id", "checksum_sha256", "version"}).
+			AddRow("ea001", "ea002", hex.EncodeToString(sum[:]), 2),
+	)
+	mock.ExpectRollback()
+
+	w := httptest.NewRecorder()
+	req := ingestRequest(t, map[string]string{"evidence_type": "vulnerability_report"}, "sbom.json", testSBOM)
+	req.Header.Set("Idempotency-Key", "api-service/sbom")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "ea002", data["id"])
+	assert.Equal(t, false, data["created"])
+	assert.Empty(t, store.objects)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestEvidence_NewVersionForChangedContent(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/ingest/evidence", IngestEvidence)
+	useMemoryObjectStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT ei.artifact_id").WillReturnRows(
+		sqlmock.NewRows([]string{"artifact_id", "id", "checksum_sha256", "version"}).
+			AddRow("ea001", "ea002", "0000", 2),
+	)
+	mock.ExpectQuery("WITH root AS").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "version", "root", "chain_hash"}).AddRow("ea002", 2, "ea001", ""),
+	)
+	mock.ExpectExec("UPDATE evidence_artifacts SET is_current = FALSE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_artifacts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_links").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_ingestions").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req := ingestRequest(t, map[string]string{"evidence_type": "vulnerability_report"}, "sbom.json", testSBOM)
+	req.Header.Set("Idempotency-Key", "api-service/sbom")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(3), data["version"])
+	assert.Equal(t, "ea001", data["parent_artifact_id"])
+	assert.Equal(t, "ea002", data["previous_version_id"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestEvidence_NoStorage(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/ingest/evidence", IngestEvidence)
+
+	w := httptest.NewRecorder()
+	req := ingestRequest(t, map[string]string{"evidence_type": "vulnerability_report"}, "sbom.json", testSBOM)
+	req.Header.Set("Idempotency-Key", "api-service/sbom")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestEvidence_KeyRecordFailureRemovesObject(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/ingest/evidence", IngestEvidence)
+	store := useMemoryObjectStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT ei.artifact_id").WillReturnRows(sqlmock.NewRows(nil))
+	mock.ExpectExec("INSERT INTO evidence_artifacts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_ingestions").WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	w := httptest.NewRecorder()
+	req := ingestRequest(t, map[string]string{"evidence_type": "vulnerability_report"}, "sbom.json", testSBOM)
+	req.Header.Set("Idempotency-Key", "api-service/sbom")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, store.objects)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestEvidence_MissingFile(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/ingest/evidence", IngestEvidence)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, ingestRequest(t, map[string]string{"evidence_type": "other"}, "", ""))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	integrityChecksumV1 = "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+	integrityChecksumV2 = "b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3"
+)
+
+func integrityVersionRows(v2Checksum string) *sqlmock.Rows {
+	now := time.Now()
+	chain1 := services.EvidenceChainHash("", "ea001", 1, integrityChecksumV1)
+	chain2 := services.EvidenceChainHash(chain1, "ea002", 2, integrityChecksumV2)
+	return sqlmock.NewRows([]string{
+		"id", "version", "status", "object_key", "checksum_sha256",
+		"previous_chain_hash", "chain_hash", "checksum_verified_at", "purged",
+	}).
+		AddRow("ea001", 1, "superseded", "a001/ea001/1/policy.pdf", integrityChecksumV1, "", chain1, now, false).
+		AddRow("ea002", 2, "approved", "a001/ea001/2/policy.pdf", v2Checksum, chain1, chain2, now, false)
+}
+
+func TestVerifyEvidenceIntegrity_NotFound(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence/:id/integrity/verify", VerifyEvidenceIntegrity)
+
+	mock.ExpectQuery("SELECT COALESCE\\(parent_artifact_id, id\\)").WillReturnRows(sqlmock.NewRows(nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence/nonexistent/integrity/verify", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestVerifyEvidenceIntegrity_ValidChain(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence/:id/integrity/verify", VerifyEvidenceIntegrity)
+
+	mock.ExpectQuery("SELECT COALESCE\\(parent_artifact_id, id\\)").WillReturnRows(
+		sqlmock.NewRows([]string{"root"}).AddRow("ea001"),
+	)
+	mock.ExpectQuery("SELECT id, version, status, object_key, checksum_sha256").WillReturnRows(
+		integrityVersionRows(integrityChecksumV2),
+	)
+	mock.ExpectExec("INSERT INTO evidence_integrity_checks").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence/ea002/integrity/verify", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, true, data["verified"])
+	assert.Equal(t, float64(2), data["versions_checked"])
+	assert.Equal(t, "ea001", data["root_artifact_id"])
+	assert.Equal(t, false, data["storage_checked"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVerifyEvidenceIntegrity_TamperedChecksum(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence/:id/integrity/verify", VerifyEvidenceIntegrity)
+
+	mock.ExpectQuery("SELECT COALESCE\\(parent_artifact_id, id\\)").WillReturnRows(
+		sqlmock.NewRows([]string{"root"}).AddRow("ea001"),
+	)
+	// The stored checksum was rewritten after the chain was sealed.
+	mock.ExpectQuery("SELECT id, version, status, object_key, checksum_sha256").WillReturnRows(
+		integrityVersionRows("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+	)
+	mock.ExpectExec("INSERT INTO evidence_integrity_checks").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence/ea002/integrity/verify", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, false, data["verified"])
+	versions := data["versions"].([]interface{})
+	assert.Equal(t, true, versions[0].(map[string]interface{})["chain_valid"])
+	assert.Equal(t, false, versions[1].(map[string]interface{})["chain_valid"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListEvidenceIntegrityChecks_Success(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/evidence/:id/integrity", ListEvidenceIntegrityChecks)
+
+	mock.ExpectQuery("SELECT COALESCE\\(parent_artifact_id, id\\)").WillReturnRows(
+		sqlmock.NewRows([]string{"root"}).AddRow("ea001"),
+	)
+	mock.ExpectQuery("FROM evidence_integrity_checks").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "verified", "versions_checked", "objects_rehashed", "checked_by", "name", "checked_at"}).
+			AddRow("ic001", true, 2, 2, "u0000000-0000-0000-0000-000000000001", "Alice Admin", time.Now()).
+			AddRow("ic002", true, 1, 0, nil, "", time.Now()),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence/ea002/integrity", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].([]interface{})
+	assert.Len(t, data, 2)
+	assert.Nil(t, data[1].(map[string]interface{})["checked_by"])
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// RedactEvidence paints redaction rectangles over a PNG/JPEG screenshot, optionally stamps a
+// caption with the capture time, collector and URL, and stores the result as a new version of
+// the artifact. The unredacted original is restricted to EvidenceRestrictedViewRoles.
+// Only the current version can be redacted, since the result becomes the new current version,
+// and an already-restricted source can only be redacted by those roles and must be covered by
+// at least one region: a caption alone would republish it unredacted.
+func RedactEvidence(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	artifactID := c.Param("id")
+
+	var req models.CreateEvidenceRedactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body"))
+		return
+	}
+	hasOverlay := req.Overlay.Timestamp || req.Overlay.Collector || (req.Overlay.URL != nil && *req.Overlay.URL != "")
+	if len(req.Regions) == 0 && !hasOverlay {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "At least one redaction region or overlay is required"))
+		return
+	}
+	if len(req.Regions) > models.MaxRedactionRegions {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+			fmt.Sprintf("At most %d redaction regions are allowed", models.MaxRedactionRegions)))
+		return
+	}
+	if req.Overlay.URL != nil && len(*req.Overlay.URL) > 2000 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "URL must be at most 2000 characters"))
+		return
+	}
+
+	var (
+		objectKey, fileName, mimeType, processingStatus, title, evidenceType string
+		description, sourceSystem                                            *string
+		freshDays                                                            *int
+		tags                                                                 pq.StringArray
+		purged, restricted, isCurrent                                        bool
+		uploadedAt                                                           time.Time
+		collector                                                            string
+	)
+	err := database.QueryRow(`
+		SELECT ea.object_key, ea.file_name, ea.mime_type, ea.processing_status, ea.purged_at IS NOT NULL,
+			ea.access_restricted, ea.is_current, ea.title, ea.description, ea.evidence_type, ea.source_system, ea.freshness_period_days, ea.tags,
+			ea.created_at, COALESCE(u.first_name || ' ' || u.last_name, '')
+		FROM evidence_artifacts ea
+		LEFT JOIN users u ON u.id = ea.uploaded_by
+		WHERE ea.id = $1 AND ea.org_id = $2
+	`, artifactID, orgID).Scan(&objectKey, &fileName, &mimeType, &processingStatus, &purged,
+		&restricted, &isCurrent, &title, &description, &evidenceType, &sourceSystem, &freshDays, &tags,
+		&uploadedAt, &collector)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence artifact not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get evidence for redaction")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	if purged {
+		c.JSON(http.StatusGone, errorResponse("EVIDENCE_PURGED", "File was deleted under the retention policy"))
+		return
+	}
+	if !isCurrent {
+		c.JSON(http.StatusConflict, errorResponse("NOT_CURRENT_VERSION", "Only the current version of an artifact can be redacted"))
+		return
+	}
+	if restricted {
+		if !models.HasRole(userRole, models.EvidenceRestrictedViewRoles) {
+			c.JSON(http.StatusForbidden, errorResponse("EVIDENCE_RESTRICTED", "The unredacted original is restricted; ask a CISO or compliance manager"))
+			return
+		}
+		if len(req.Regions) == 0 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Redacting a restricted original requires at least one redaction region"))
+			return
+		}
+	}
+	redactable := false
+	for _, m := range models.RedactableMIMETypes {
+		if m == mimeType {
+			redactable = true
+		}
+	}
+	if !redactable {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNSUPPORTED_MEDIA_TYPE", "Only PNG and JPEG screenshots can be redacted"))
+		return
+	}
+	if !models.IsProcessingCleared(processingStatus) {
+		c.JSON(http.StatusConflict, errorResponse("PROCESSING_PENDING", "File must pass malware scanning before it can be redacted"))
+		return
+	}
+	if minioService == nil {
+		c.JSON(http.StatusServiceUnavailable, errorResponse("SERVICE_UNAVAILABLE", "Storage service not available"))
+		return
+	}
+
+	capturedAt := uploadedAt
+	if req.CapturedAt != nil {
+		capturedAt = *req.CapturedAt
+	}
+	var caption []string
+	if req.Overlay.Timestamp {
+		caption = append(caption, "Captured: "+capturedAt.UTC().Format("2006-01-02 15:04:05 MST"))
+	}
+	if req.Overlay.Collector {
+		if collector == "" {
+			collector = "unknown"
+		}
+		caption = append(caption, "Collector: "+collector)
+	}
+	if req.Overlay.URL != nil && *req.Overlay.URL != "" {
+		caption = append(caption, "URL: "+*req.Overlay.URL)
+	}
+
+	ctx := c.Request.Context()
+	obj, err := minioService.OpenObject(ctx, objectKey)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to open evidence for redaction")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to read evidence file"))
+		return
+	}
+	redacted, err := services.RedactScreenshot(obj, req.Regions, caption)
+	obj.Close()
+	if errors.Is(err, services.ErrUnsupportedScreenshot) || errors.Is(err, services.ErrInvalidRedactionRegion) {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", err.Error()))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to redact screenshot")
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", err.Error()))
+		return
+	}
+
+	// Restrict the original before storing the redacted copy: if storing fails, the sensitive
+	// original stays locked down rather than the other way round.
+	reason := "Redacted version created"
+	if req.Reason != nil && *req.Reason != "" {
+		reason = *req.Reason
+	}
+	if _, err := database.Exec(`
+		UPDATE evidence_artifacts SET access_restricted = TRUE, access_restricted_reason = $1
+		WHERE id = $2 AND org_id = $3
+	`, reason, artifactID, orgID); err != nil {
+		log.Error().Err(err).Msg("Failed to restrict original evidence")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	stored, err := services.StoreEvidenceVersion(ctx, database.DB, objectStore, services.EvidenceVersionSpec{
+		OrgID:               orgID,
+		CurrentArtifactID:   artifactID,
+		Title:               title,
+		Description:         description,
+		EvidenceType:        evidenceType,
+		CollectionMethod:    "screenshot_capture",
+		SourceSystem:        sourceSystem,
+		FreshnessPeriodDays: freshDays,
+		Tags:                tags,
+		Metadata: map[string]interface{}{
+			"redacted_from":  artifactID,
+			"redactions":     len(req.Regions),
+			"overlay_lines":  caption,
+			"captured_at":    capturedAt,
+			"image_width":    redacted.Width,
+			"image_height":   redacted.Height,
+			"source_url":     req.Overlay.URL,
+			"redaction_note": req.Reason,
+		},
+		UploadedBy:     &userID,
+		CollectionDate: capturedAt,
+		FileName:       redactedFileName(fileName),
+		MIMEType:       redacted.MIMEType,
+		Data:           redacted.Data,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to store redacted evidence")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	regionsJSON, _ := json.Marshal(req.Regions)
+	if caption == nil {
+		caption = []string{}
+	}
+	var redaction models.EvidenceRedaction
+	err = database.QueryRow(`
+		INSERT INTO evidence_redactions (org_id, source_artifact_id, result_artifact_id, regions, overlay_lines, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, orgID, artifactID, stored.ID, string(regionsJSON), pq.Array(caption), req.Reason, userID).Scan(&redaction.ID, &redaction.CreatedAt)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to record evidence redaction")
+	}
+	redaction.SourceArtifactID = artifactID
+	redaction.ResultArtifactID = &stored.ID
+	redaction.Regions = req.Regions
+	redaction.OverlayLines = caption
+	redaction.Reason = req.Reason
+	redaction.CreatedBy = &userID
+
+	middleware.LogAudit(c, "evidence.redacted", "evidence_artifact", &artifactID, map[string]interface{}{
+		"result_artifact_id": stored.ID, "version": stored.Version,
+		"regions": len(req.Regions), "overlay_lines": len(caption),
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, gin.H{
+		"redaction": redaction,
+		"version":   stored,
+	}))
+}
+
+// ListEvidenceRedactions lists redactions made from, or producing, an artifact.
+func ListEvidenceRedactions(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	artifactID := c.Param("id")
+
+	rows, err := database.Query(`
+		SELECT id, source_artifact_id, result_artifact_id, regions, overlay_lines, reason, created_by, created_at
+		FROM evidence_redactions
+		WHERE org_id = $1 AND (source_artifact_id = $2 OR result_artifact_id = $2)
+		ORDER BY created_at DESC
+	`, orgID, artifactID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list evidence redactions")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []models.EvidenceRedaction{}
+	for rows.Next() {
+		var r models.EvidenceRedaction
+		var regions []byte
+		var lines pq.StringArray
+		if err := rows.Scan(&r.ID, &r.SourceArtifactID, &r.ResultArtifactID, &regions, &lines,
+			&r.Reason, &r.CreatedBy, &r.CreatedAt); err != nil {
+			log.Error().Err(err).Msg("Failed to scan evidence redaction")
+			continue
+		}
+		json.Unmarshal(regions, &r.Regions)
+		r.OverlayLines = lines
+		results = append(results, r)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, results))
+}
+
+// redactedFileName marks a file name as redacted, keeping its extension.
+func redactedFileName(name string) string {
+	for i := len(name) - 1; i > 0; i-- {
+		if name[i] == '.' {
+			return name[:i] + "-redacted" + name[i:]
+		}
+		if name[i] == '/' {
+			break
+		}
+	}
+	return name + "-redacted"
+}
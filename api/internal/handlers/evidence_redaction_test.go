@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func redactionSourceRow(mimeType string) *sqlmock.Rows {
+	return redactionSourceRowWith(mimeType, false, true)
+}
+
+func redactionSourceRowWith(mimeType string, restricted, current bool) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"object_key", "file_name", "mime_type", "processing_status", "purged",
+		"access_restricted", "is_current", "title", "description", "evidence_type", "source_system",
+		"freshness_period_days", "tags", "created_at", "uploader",
+	}).AddRow(
+		"a001/e001/1/console.png", "console.png", mimeType, "passed", false,
+		restricted, current, "AWS console IAM settings", nil, "screenshot", nil, nil, pq.Array([]string{}),
+		time.Now(), "Alice Admin",
+	)
+}
+
+func postRedaction(r *gin.Engine, body interface{}) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence/e001/redactions", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRedactEvidence_RequiresRegionOrOverlay(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.POST("/api/v1/evidence/:id/redactions", RedactEvidence)
+
+	w := postRedaction(r, map[string]interface{}{"regions": []interface{}{}})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRedactEvidence_NotAnImage(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.POST("/api/v1/evidence/:id/redactions", RedactEvidence)
+
+	mock.ExpectQuery("FROM evidence_artifacts ea").WillReturnRows(redactionSourceRow("application/pdf"))
+
+	w := postRedaction(r, map[string]interface{}{
+		"regions": []map[string]int{{"x": 0, "y": 0, "width": 10, "height": 10}},
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "UNSUPPORTED_MEDIA_TYPE")
+}
+
+func TestRedactEvidence_NotCurrentVersion(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.POST("/api/v1/evidence/:id/redactions", RedactEvidence)
+
+	mock.ExpectQuery("FROM evidence_artifacts ea").WillReturnRows(redactionSourceRowWith("image/png", false, false))
+
+	w := postRedaction(r, map[string]interface{}{
+		"regions": []map[string]int{{"x": 0, "y": 0, "width": 10, "height": 10}},
+	})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "NOT_CURRENT_VERSION")
+}
+
+func TestRedactEvidence_RestrictedRequiresRole(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "u0000000-0000-0000-0000-000000000002")
+		c.Set(middleware.ContextKeyOrgID, "a0000000-0000-0000-0000-000000000001")
+		c.Set(middleware.ContextKeyRole, "security_engineer")
+		c.Next()
+	})
+	r.POST("/api/v1/evidence/:id/redactions", RedactEvidence)
+
+	mock.ExpectQuery("FROM evidence_artifacts ea").WillReturnRows(redactionSourceRowWith("image/png", true, true))
+
+	w := postRedaction(r, map[string]interface{}{
+		"regions": []map[string]int{{"x": 0, "y": 0, "width": 10, "height": 10}},
+	})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "EVIDENCE_RESTRICTED")
+}
+
+func TestRedactEvidence_RestrictedOverlayOnly(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.POST("/api/v1/evidence/:id/redactions", RedactEvidence)
+
+	mock.ExpectQuery("FROM evidence_artifacts ea").WillReturnRows(redactionSourceRowWith("image/png", true, true))
+
+	w := postRedaction(r, map[string]interface{}{
+		"overlay": map[string]bool{"timestamp": true},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetDownloadURL_RestrictedOriginal(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "u0000000-0000-0000-0000-000000000002")
+		c.Set(middleware.ContextKeyOrgID, "a0000000-0000-0000-0000-000000000001")
+		c.Set(middleware.ContextKeyRole, "auditor")
+		c.Next()
+	})
+	r.GET("/api/v1/evidence/:id/download", GetDownloadURL)
+
+	mock.ExpectQuery("SELECT object_key, file_name").WillReturnRows(
+		sqlmock.NewRows([]string{"object_key", "file_name", "mime_type", "file_size", "status", "processing_status", "purged", "access_restricted", "id"}).
+			AddRow("a001/e001/1/console.png", "console.png", "image/png", 2048, "superseded", "passed", false, true, "e001"),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence/e001/download", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "EVIDENCE_RESTRICTED")
+}
+
+func TestRedactedFileName(t *testing.T) {
+	assert.Equal(t, "console-redacted.png", redactedFileName("console.png"))
+	assert.Equal(t, "screenshot-redacted", redactedFileName("screenshot"))
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+const evidenceRenewalTaskColumns = `
+	t.id, t.artifact_id, ea.title, ea.expires_at, t.control_id, t.status, t.assigned_to,
+	t.due_at, t.renewed_artifact_id, t.completed_at, t.notes, t.created_at, t.updated_at`
+
+func scanEvidenceRenewalTask(row rowScanner) (*models.EvidenceRenewalTask, error) {
+	var t models.EvidenceRenewalTask
+	if err := row.Scan(&t.ID, &t.ArtifactID, &t.ArtifactTitle, &t.ExpiresAt, &t.ControlID, &t.Status,
+		&t.AssignedTo, &t.DueAt, &t.RenewedArtifactID, &t.CompletedAt, &t.Notes, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListEvidenceRenewalTasks lists evidence renewal tasks, open ones by default.
+// assigned_to=me returns the caller's tasks.
+func ListEvidenceRenewalTasks(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	where := []string{"t.org_id = $1"}
+	args := []interface{}{orgID}
+	argN := 2
+
+	status := c.DefaultQuery("status", models.RenewalOpen)
+	if status != "all" {
+		valid := false
+		for _, s := range models.ValidRenewalStatuses {
+			if s == status {
+				valid = true
+			}
+		}
+		if !valid {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid status"))
+			return
+		}
+		where = append(where, fmt.Sprintf("t.status = $%d", argN))
+		args = append(args, status)
+		argN++
+	}
+	if v := c.Query("assigned_to"); v != "" {
+		if v == "me" {
+			v = userID
+		}
+		where = append(where, fmt.Sprintf("t.assigned_to = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+	if v := c.Query("control_id"); v != "" {
+		where = append(where, fmt.Sprintf("t.control_id = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+	if c.Query("overdue") == "true" {
+		where = append(where, "t.due_at < NOW()")
+	}
+
+	whereClause := joinWhere(where)
+
+	var total int
+	database.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM evidence_renewal_tasks t WHERE %s`, whereClause), args...).Scan(&total)
+
+	offset := (page - 1) * perPage
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM evidence_renewal_tasks t
+		JOIN evidence_artifacts ea ON ea.id = t.artifact_id
+		WHERE %s
+		ORDER BY t.due_at
+		LIMIT $%d OFFSET $%d
+	`, evidenceRenewalTaskColumns, whereClause, argN, argN+1)
+	args = append(args, perPage, offset)
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list evidence renewal tasks")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []models.EvidenceRenewalTask{}
+	for rows.Next() {
+		t, err := scanEvidenceRenewalTask(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to scan evidence renewal task")
+			continue
+		}
+		results = append(results, *t)
+	}
+
+	c.JSON(http.StatusOK, listResponse(c, results, total, page, perPage))
+}
+
+// UpdateEvidenceRenewalTask reassigns, annotates or cancels an open renewal task.
+func UpdateEvidenceRenewalTask(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	taskID := c.Param("id")
+
+	var req models.UpdateEvidenceRenewalTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body"))
+		return
+	}
+
+	var status string
+	err := database.QueryRow(`SELECT status FROM evidence_renewal_tasks WHERE id = $1 AND org_id = $2`,
+		taskID, orgID).Scan(&status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence renewal task not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get evidence renewal task")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if status != models.RenewalOpen {
+		c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", "Only open renewal tasks can be changed"))
+		return
+	}
+
+	sets := []string{}
+	args := []interface{}{}
+	argN := 1
+	changed := []string{}
+	add := func(field, expr string, v interface{}) {
+		sets = append(sets, fmt.Sprintf(expr, argN))
+		args = append(args, v)
+		argN++
+		changed = append(changed, field)
+	}
+
+	if req.AssignedTo != nil {
+		var exists bool
+		database.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND org_id = $2)", *req.AssignedTo, orgID).Scan(&exists)
+		if !exists {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "Assignee not found"))
+			return
+		}
+		add("assigned_to", "assigned_to = $%d", *req.AssignedTo)
+	}
+	if req.Notes != nil {
+		add("notes", "notes = $%d", *req.Notes)
+	}
+	if req.Status != nil {
+		if *req.Status != models.RenewalCancelled {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Tasks can only be cancelled; they complete when a new version is uploaded"))
+			return
+		}
+		add("status", "status = $%d", *req.Status)
+		sets = append(sets, "completed_at = NOW()")
+	}
+
+	if len(sets) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "No fields to update"))
+		return
+	}
+
+	args = append(args, taskID, orgID)
+	task, err := scanEvidenceRenewalTask(database.QueryRow(fmt.Sprintf(`
+		UPDATE evidence_renewal_tasks t SET %s
+		FROM evidence_artifacts ea
+		WHERE ea.id = t.artifact_id AND t.id = $%d AND t.org_id = $%d
+		RETURNING %s
+	`, joinStrings(sets), argN, argN+1, evidenceRenewalTaskColumns), args...))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update evidence renewal task")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "evidence_renewal_task.updated", "evidence_renewal_task", &taskID, map[string]interface{}{
+		"changed": changed, "artifact_id": task.ArtifactID,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, task))
+}
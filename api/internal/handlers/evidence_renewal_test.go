@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renewalTaskRows(status string) *sqlmock.Rows {
+	now := time.Now()
+	due := now.Add(10 * 24 * time.Hour)
+	return sqlmock.NewRows([]string{
+		"id", "artifact_id", "title", "expires_at", "control_id", "status", "assigned_to",
+		"due_at", "renewed_artifact_id", "completed_at", "notes", "created_at", "updated_at",
+	}).AddRow(
+		"ert001", "e001", "Quarterly access review", due, "c001", status, "u0000000-0000-0000-0000-000000000001",
+		due, nil, nil, nil, now, now,
+	)
+}
+
+func TestListEvidenceRenewalTasks_Mine(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/evidence-renewal-tasks", ListEvidenceRenewalTasks)
+
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs("a0000000-0000-0000-0000-000000000001", "open", "u0000000-0000-0000-0000-000000000001").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("FROM evidence_renewal_tasks t").WillReturnRows(renewalTaskRows("open"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence-renewal-tasks?assigned_to=me", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	items := resp["data"].([]interface{})
+	require.Len(t, items, 1)
+	assert.Equal(t, "Quarterly access review", items[0].(map[string]interface{})["artifact_title"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateEvidenceRenewalTask_Cancel(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.PUT("/api/v1/evidence-renewal-tasks/:id", UpdateEvidenceRenewalTask)
+
+	mock.ExpectQuery("SELECT status FROM evidence_renewal_tasks").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("open"))
+	mock.ExpectQuery("UPDATE evidence_renewal_tasks t SET").WillReturnRows(renewalTaskRows("cancelled"))
+
+	body, _ := json.Marshal(map[string]interface{}{"status": "cancelled", "notes": "Control retired"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/evidence-renewal-tasks/ert001", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateEvidenceRenewalTask_CannotComplete(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.PUT("/api/v1/evidence-renewal-tasks/:id", UpdateEvidenceRenewalTask)
+
+	mock.ExpectQuery("SELECT status FROM evidence_renewal_tasks").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("open"))
+
+	body, _ := json.Marshal(map[string]interface{}{"status": "completed"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/evidence-renewal-tasks/ert001", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
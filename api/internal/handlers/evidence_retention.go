@@ -0,0 +1,482 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// retentionUpcomingDays is how far ahead the retention report looks for upcoming purges.
+const retentionUpcomingDays = 90
+
+const evidenceRetentionRuleColumns = `
+	r.id, r.org_id, r.name, r.description, r.is_active, r.evidence_type, r.framework_id,
+	r.retention_trigger, r.retention_days, r.created_by, r.created_at, r.updated_at`
+
+func scanEvidenceRetentionRule(row rowScanner) (*models.EvidenceRetentionRule, error) {
+	var r models.EvidenceRetentionRule
+	if err := row.Scan(&r.ID, &r.OrgID, &r.Name, &r.Description, &r.IsActive, &r.EvidenceType, &r.FrameworkID,
+		&r.RetentionTrigger, &r.RetentionDays, &r.CreatedBy, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// frameworkExists checks that a framework ID refers to a framework in the catalog.
+func frameworkExists(frameworkID string) bool {
+	var exists bool
+	database.QueryRow(`SELECT EXISTS(SELECT 1 FROM frameworks WHERE id = $1)`, frameworkID).Scan(&exists)
+	return exists
+}
+
+// ListEvidenceRetentionRules lists the org's evidence retention rules.
+func ListEvidenceRetentionRules(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	rows, err := database.Query(fmt.Sprintf(`
+		SELECT %s FROM evidence_retention_rules r
+		WHERE r.org_id = $1
+		ORDER BY r.name
+	`, evidenceRetentionRuleColumns), orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list evidence retention rules")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []models.EvidenceRetentionRule{}
+	for rows.Next() {
+		r, err := scanEvidenceRetentionRule(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to scan evidence retention rule")
+			continue
+		}
+		results = append(results, *r)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, results))
+}
+
+// CreateEvidenceRetentionRule creates an evidence retention rule.
+func CreateEvidenceRetentionRule(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	var req models.CreateEvidenceRetentionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+	if len(req.Name) > 255 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Name must be at most 255 characters"))
+		return
+	}
+	if req.RetentionDays <= 0 || req.RetentionDays > models.MaxRetentionDays {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+			fmt.Sprintf("retention_days must be between 1 and %d", models.MaxRetentionDays)))
+		return
+	}
+	trigger := models.RetentionTriggerSuperseded
+	if req.RetentionTrigger != nil {
+		if !models.IsValidRetentionTrigger(*req.RetentionTrigger) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid retention_trigger"))
+			return
+		}
+		trigger = *req.RetentionTrigger
+	}
+	if req.EvidenceType != nil && !models.IsValidEvidenceType(*req.EvidenceType) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid evidence type"))
+		return
+	}
+	if req.FrameworkID != nil && !frameworkExists(*req.FrameworkID) {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "Framework not found"))
+		return
+	}
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	ruleID := uuid.New().String()
+	rule, err := scanEvidenceRetentionRule(database.QueryRow(fmt.Sprintf(`
+		INSERT INTO evidence_retention_rules AS r (id, org_id, name, description, is_active,
+			evidence_type, framework_id, retention_trigger, retention_days, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING %s
+	`, evidenceRetentionRuleColumns), ruleID, orgID, req.Name, req.Description, isActive,
+		req.EvidenceType, req.FrameworkID, trigger, req.RetentionDays, userID))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create evidence retention rule")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "evidence_retention_rule.created", "evidence_retention_rule", &ruleID, map[string]interface{}{
+		"name": req.Name, "retention_trigger": trigger, "retention_days": req.RetentionDays,
+		"evidence_type": req.EvidenceType, "framework_id": req.FrameworkID,
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, rule))
+}
+
+// UpdateEvidenceRetentionRule updates an evidence retention rule.
+func UpdateEvidenceRetentionRule(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	ruleID := c.Param("id")
+
+	var req models.UpdateEvidenceRetentionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body"))
+		return
+	}
+
+	sets := []string{}
+	args := []interface{}{}
+	argN := 1
+	changed := []string{}
+	add := func(field, expr string, v interface{}) {
+		sets = append(sets, fmt.Sprintf(expr, argN))
+		args = append(args, v)
+		argN++
+		changed = append(changed, field)
+	}
+
+	if req.Name != nil {
+		if *req.Name == "" || len(*req.Name) > 255 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Name must be 1-255 characters"))
+			return
+		}
+		add("name", "name = $%d", *req.Name)
+	}
+	if req.Description != nil {
+		add("description", "description = $%d", *req.Description)
+	}
+	if req.EvidenceType != nil {
+		if *req.EvidenceType == "" {
+			add("evidence_type", "evidence_type = $%d", nil)
+		} else if !models.IsValidEvidenceType(*req.EvidenceType) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid evidence type"))
+			return
+		} else {
+			add("evidence_type", "evidence_type = $%d", *req.EvidenceType)
+		}
+	}
+	if req.FrameworkID != nil {
+		if *req.FrameworkID == "" {
+			add("framework_id", "framework_id = $%d", nil)
+		} else if !frameworkExists(*req.FrameworkID) {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "Framework not found"))
+			return
+		} else {
+			add("framework_id", "framework_id = $%d", *req.FrameworkID)
+		}
+	}
+	if req.RetentionTrigger != nil {
+		if !models.IsValidRetentionTrigger(*req.RetentionTrigger) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid retention_trigger"))
+			return
+		}
+		add("retention_trigger", "retention_trigger = $%d", *req.RetentionTrigger)
+	}
+	if req.RetentionDays != nil {
+		if *req.RetentionDays <= 0 || *req.RetentionDays > models.MaxRetentionDays {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+				fmt.Sprintf("retention_days must be between 1 and %d", models.MaxRetentionDays)))
+			return
+		}
+		add("retention_days", "retention_days = $%d", *req.RetentionDays)
+	}
+	if req.IsActive != nil {
+		add("is_active", "is_active = $%d", *req.IsActive)
+	}
+
+	if len(sets) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "No fields to update"))
+		return
+	}
+
+	args = append(args, ruleID, orgID)
+	rule, err := scanEvidenceRetentionRule(database.QueryRow(fmt.Sprintf(`
+		UPDATE evidence_retention_rules AS r SET %s
+		WHERE r.id = $%d AND r.org_id = $%d
+		RETURNING %s
+	`, joinStrings(sets), argN, argN+1, evidenceRetentionRuleColumns), args...))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence retention rule not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update evidence retention rule")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "evidence_retention_rule.updated", "evidence_retention_rule", &ruleID, map[string]interface{}{
+		"changed": changed,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, rule))
+}
+
+// DeleteEvidenceRetentionRule deletes a retention rule. Artifacts it no longer covers stop
+// being eligible for purge unless another rule matches them.
+func DeleteEvidenceRetentionRule(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	ruleID := c.Param("id")
+
+	res, err := database.Exec(`DELETE FROM evidence_retention_rules WHERE id = $1 AND org_id = $2`, ruleID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete evidence retention rule")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence retention rule not found"))
+		return
+	}
+
+	middleware.LogAudit(c, "evidence_retention_rule.deleted", "evidence_retention_rule", &ruleID, nil)
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{"id": ruleID, "deleted": true}))
+}
+
+// SetEvidenceLegalHold places or releases a legal hold. The hold is recorded on the root
+// artifact and covers every version in the chain.
+func SetEvidenceLegalHold(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	artifactID := c.Param("id")
+
+	var req models.LegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body"))
+		return
+	}
+	reason := ""
+	if req.Reason != nil {
+		reason = strings.TrimSpace(*req.Reason)
+	}
+	if req.LegalHold && len(reason) < 10 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "A reason of at least 10 characters is required to place a legal hold"))
+		return
+	}
+
+	var rootID string
+	err := database.QueryRow(`
+		SELECT COALESCE(parent_artifact_id, id) FROM evidence_artifacts WHERE id = $1 AND org_id = $2
+	`, artifactID, orgID).Scan(&rootID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence artifact not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get evidence artifact")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	var (
+		holdBy *string
+		holdAt *time.Time
+	)
+	if req.LegalHold {
+		err = database.QueryRow(`
+			UPDATE evidence_artifacts SET legal_hold = TRUE, legal_hold_reason = $1,
+				legal_hold_by = $2, legal_hold_at = NOW()
+			WHERE id = $3
+			RETURNING legal_hold_by, legal_hold_at
+		`, reason, userID, rootID).Scan(&holdBy, &holdAt)
+	} else {
+		_, err = database.Exec(`
+			UPDATE evidence_artifacts SET legal_hold = FALSE, legal_hold_reason = NULL,
+				legal_hold_by = NULL, legal_hold_at = NULL
+			WHERE id = $1
+		`, rootID)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update legal hold")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	action := "evidence.legal_hold_released"
+	details := map[string]interface{}{"root_artifact_id": rootID}
+	if req.LegalHold {
+		action = "evidence.legal_hold_set"
+		details["reason"] = reason
+	}
+	middleware.LogAudit(c, action, "evidence", &artifactID, details)
+
+	resp := gin.H{
+		"id":                artifactID,
+		"root_artifact_id":  rootID,
+		"legal_hold":        req.LegalHold,
+		"legal_hold_reason": nil,
+		"legal_hold_by":     holdBy,
+		"legal_hold_at":     holdAt,
+	}
+	if req.LegalHold {
+		resp["legal_hold_reason"] = reason
+	}
+	c.JSON(http.StatusOK, successResponse(c, resp))
+}
+
+// GetEvidenceRetentionReport summarizes stored evidence against the org's retention rules:
+// what is covered, held, due for purge, coming up for purge and already purged.
+func GetEvidenceRetentionReport(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	var s struct {
+		storedCount, unmanagedCount, heldCount, blockedCount, dueCount, upcomingCount, purgedCount int
+		storedBytes, unmanagedBytes, heldBytes, dueBytes, upcomingBytes, purgedBytes               int64
+	}
+	err := database.QueryRow(fmt.Sprintf(`
+		WITH per_artifact AS (
+			SELECT ea.id, ea.file_size, ea.purged_at,
+				COUNT(d.rule_id) AS rules,
+				bool_and(d.retain_until IS NOT NULL) AS decided,
+				MAX(d.retain_until) AS retain_until,
+				EXISTS (SELECT 1 FROM evidence_artifacts lh
+					WHERE lh.id = COALESCE(ea.parent_artifact_id, ea.id) AND lh.legal_hold = TRUE) AS held,
+				%s AS blocked
+			FROM evidence_artifacts ea
+			LEFT JOIN evidence_retention_deadlines d ON d.artifact_id = ea.id
+			WHERE ea.org_id = $1
+			GROUP BY ea.id
+		), stored AS (
+			SELECT *, rules > 0 AND decided AND NOT blocked AS purgeable FROM per_artifact WHERE purged_at IS NULL
+		)
+		SELECT
+			COUNT(*), COALESCE(SUM(file_size), 0),
+			COUNT(*) FILTER (WHERE rules = 0), COALESCE(SUM(file_size) FILTER (WHERE rules = 0), 0),
+			COUNT(*) FILTER (WHERE held), COALESCE(SUM(file_size) FILTER (WHERE held), 0),
+			COUNT(*) FILTER (WHERE blocked),
+			COUNT(*) FILTER (WHERE purgeable AND retain_until <= NOW()),
+			COALESCE(SUM(file_size) FILTER (WHERE purgeable AND retain_until <= NOW()), 0),
+			COUNT(*) FILTER (WHERE purgeable AND retain_until > NOW() AND retain_until <= NOW() + make_interval(days => $2)),
+			COALESCE(SUM(file_size) FILTER (WHERE purgeable AND retain_until > NOW() AND retain_until <= NOW() + make_interval(days => $2)), 0),
+			(SELECT COUNT(*) FROM per_artifact WHERE purged_at >= NOW() - INTERVAL '365 days'),
+			(SELECT COALESCE(SUM(file_size), 0) FROM per_artifact WHERE purged_at >= NOW() - INTERVAL '365 days')
+		FROM stored
+	`, services.RetentionBlockedCondition), orgID, retentionUpcomingDays).Scan(
+		&s.storedCount, &s.storedBytes,
+		&s.unmanagedCount, &s.unmanagedBytes,
+		&s.heldCount, &s.heldBytes,
+		&s.blockedCount,
+		&s.dueCount, &s.dueBytes,
+		&s.upcomingCount, &s.upcomingBytes,
+		&s.purgedCount, &s.purgedBytes)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to compute evidence retention summary")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	rules := []gin.H{}
+	rows, err := database.Query(`
+		SELECT r.id, r.name, r.is_active, r.evidence_type, r.framework_id, f.name,
+			r.retention_trigger, r.retention_days,
+			COUNT(d.artifact_id) FILTER (WHERE ea.purged_at IS NULL),
+			COUNT(d.artifact_id) FILTER (WHERE ea.purged_at IS NULL AND d.retain_until IS NULL),
+			COUNT(d.artifact_id) FILTER (WHERE ea.purged_at IS NULL AND d.retain_until > NOW()),
+			COUNT(d.artifact_id) FILTER (WHERE ea.purged_at IS NULL AND d.retain_until <= NOW()),
+			(SELECT COUNT(*) FROM evidence_artifacts p WHERE p.purged_by_rule_id = r.id)
+		FROM evidence_retention_rules r
+		LEFT JOIN frameworks f ON f.id = r.framework_id
+		LEFT JOIN evidence_retention_deadlines d ON d.rule_id = r.id
+		LEFT JOIN evidence_artifacts ea ON ea.id = d.artifact_id
+		WHERE r.org_id = $1
+		GROUP BY r.id, f.name
+		ORDER BY r.name
+	`, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to compute evidence retention by rule")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id, name, trigger                         string
+			isActive                                  bool
+			eType, frameworkID, frameworkName         *string
+			days                                      int
+			matched, awaiting, within, lapsed, purged int
+		)
+		if err := rows.Scan(&id, &name, &isActive, &eType, &frameworkID, &frameworkName,
+			&trigger, &days, &matched, &awaiting, &within, &lapsed, &purged); err != nil {
+			log.Error().Err(err).Msg("Failed to scan evidence retention rule summary")
+			continue
+		}
+		rules = append(rules, gin.H{
+			"id":                id,
+			"name":              name,
+			"is_active":         isActive,
+			"evidence_type":     eType,
+			"framework_id":      frameworkID,
+			"framework_name":    frameworkName,
+			"retention_trigger": trigger,
+			"retention_days":    days,
+			"artifacts_matched": matched,
+			"awaiting_trigger":  awaiting,
+			"within_period":     within,
+			"period_lapsed":     lapsed,
+			"purged":            purged,
+		})
+	}
+
+	holds := []gin.H{}
+	holdRows, err := database.Query(`
+		SELECT ea.id, ea.title, ea.legal_hold_reason, ea.legal_hold_at,
+			ea.legal_hold_by, COALESCE(u.first_name || ' ' || u.last_name, '')
+		FROM evidence_artifacts ea
+		LEFT JOIN users u ON u.id = ea.legal_hold_by
+		WHERE ea.org_id = $1 AND ea.legal_hold = TRUE
+		ORDER BY ea.legal_hold_at DESC
+	`, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list legal holds")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer holdRows.Close()
+	for holdRows.Next() {
+		var (
+			id, title, holderName string
+			reason, holderID      *string
+			at                    *time.Time
+		)
+		if err := holdRows.Scan(&id, &title, &reason, &at, &holderID, &holderName); err != nil {
+			log.Error().Err(err).Msg("Failed to scan legal hold")
+			continue
+		}
+		hold := gin.H{"root_artifact_id": id, "title": title, "reason": reason, "placed_at": at, "placed_by": nil}
+		if holderID != nil {
+			hold["placed_by"] = gin.H{"id": *holderID, "name": holderName}
+		}
+		holds = append(holds, hold)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"stored":           gin.H{"artifacts": s.storedCount, "bytes": s.storedBytes},
+		"unmanaged":        gin.H{"artifacts": s.unmanagedCount, "bytes": s.unmanagedBytes},
+		"legal_hold":       gin.H{"artifacts": s.heldCount, "bytes": s.heldBytes},
+		"purge_blocked":    gin.H{"artifacts": s.blockedCount},
+		"purge_due":        gin.H{"artifacts": s.dueCount, "bytes": s.dueBytes},
+		"purge_upcoming":   gin.H{"artifacts": s.upcomingCount, "bytes": s.upcomingBytes, "within_days": retentionUpcomingDays},
+		"purged_last_year": gin.H{"artifacts": s.purgedCount, "bytes": s.purgedBytes},
+		"rules":            rules,
+		"legal_holds":      holds,
+		"generated_at":     time.Now(),
+	}))
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func retentionRuleRows() *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{
+		"id", "org_id", "name", "description", "is_active", "evidence_type", "framework_id",
+		"retention_trigger", "retention_days", "created_by", "created_at", "updated_at",
+	}).AddRow(
+		"err001", "a0000000-0000-0000-0000-000000000001", "SOX seven years", nil, true, nil, "f001",
+		"superseded", 2555, "u0000000-0000-0000-0000-000000000001", now, now,
+	)
+}
+
+func TestCreateEvidenceRetentionRule_Success(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence-retention-rules", CreateEvidenceRetentionRule)
+
+	mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("INSERT INTO evidence_retention_rules").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "SOX seven years", nil, true, nil, "f001", "superseded", 2555, sqlmock.AnyArg()).
+		WillReturnRows(retentionRuleRows())
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":           "SOX seven years",
+		"framework_id":   "f001",
+		"retention_days": 2555,
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence-retention-rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "superseded", data["retention_trigger"])
+	assert.Equal(t, float64(2555), data["retention_days"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateEvidenceRetentionRule_InvalidTrigger(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.POST("/api/v1/evidence-retention-rules", CreateEvidenceRetentionRule)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":              "Logs",
+		"retention_trigger": "approved",
+		"retention_days":    365,
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/evidence-retention-rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteEvidence_LegalHold(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.DELETE("/api/v1/evidence/:id", DeleteEvidence)
+
+	mock.ExpectQuery("SELECT ea.title").WillReturnRows(
+		sqlmock.NewRows([]string{"title", "legal_hold"}).AddRow("Payroll access review", true),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/evidence/e002", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "LEGAL_HOLD")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetEvidenceLegalHold_Success(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.PUT("/api/v1/evidence/:id/legal-hold", SetEvidenceLegalHold)
+
+	mock.ExpectQuery("SELECT COALESCE\\(parent_artifact_id, id\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"root"}).AddRow("e001"))
+	mock.ExpectQuery("UPDATE evidence_artifacts SET legal_hold = TRUE").
+		WithArgs("Litigation hold: matter 2026-114", sqlmock.AnyArg(), "e001").
+		WillReturnRows(sqlmock.NewRows([]string{"legal_hold_by", "legal_hold_at"}).
+			AddRow("u0000000-0000-0000-0000-000000000001", time.Now()))
+
+	body, _ := json.Marshal(map[string]interface{}{"legal_hold": true, "reason": "Litigation hold: matter 2026-114"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/evidence/e003/legal-hold", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, true, data["legal_hold"])
+	assert.Equal(t, "e001", data["root_artifact_id"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetEvidenceLegalHold_ReasonRequired(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.PUT("/api/v1/evidence/:id/legal-hold", SetEvidenceLegalHold)
+
+	body, _ := json.Marshal(map[string]interface{}{"legal_hold": true})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/evidence/e001/legal-hold", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetDownloadURL_Purged(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.GET("/api/v1/evidence/:id/download", GetDownloadURL)
+
+	mock.ExpectQuery("SELECT object_key, file_name").WillReturnRows(
+		sqlmock.NewRows([]string{"object_key", "file_name", "mime_type", "file_size", "status", "processing_status", "purged", "access_restricted", "id"}).
+			AddRow("a001/e001/1/report.pdf", "report.pdf", "application/pdf", 2048, "superseded", "passed", true, false, "e001"),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence/e001/download", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Contains(t, w.Body.String(), "EVIDENCE_PURGED")
+}
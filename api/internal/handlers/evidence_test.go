@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
 	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -193,6 +195,9 @@ func TestGetEvidence_Success(t *testing.T) {
 		"source_system", "uploaded_by",
 		"uploader_name", "uploader_email",
 		"tags", "created_at", "updated_at",
+		"processing_status", "detected_mime_type", "scan_engine", "scan_signature",
+		"processing_error", "processed_at",
+		"legal_hold", "legal_hold_reason", "purged_at",
 	}).AddRow(
 		"e001", "Okta MFA Config", nil, "configuration_export", "approved",
 		"system_export", "okta.json", 15234, "application/json",
@@ -202,6 +207,9 @@ func TestGetEvidence_Success(t *testing.T) {
 		"okta", "u001",
 		"Bob Security", "bob@acme.com",
 		pq.Array([]string{"mfa", "okta"}), now, now,
+		"passed", "text/plain", "clamav", nil,
+		nil, now,
+		false, nil, nil,
 	)
 	mock.ExpectQuery("SELECT ea.id, ea.title").WillReturnRows(row)
 
@@ -229,6 +237,7 @@ func TestGetEvidence_Success(t *testing.T) {
 	assert.Equal(t, "approved", data["status"])
 	assert.Equal(t, float64(1), data["version"])
 	assert.Equal(t, "fresh", data["freshness_status"])
+	assert.Equal(t, "passed", data["processing"].(map[string]interface{})["status"])
 }
 
 func TestGetEvidence_NotFound(t *testing.T) {
@@ -293,10 +302,13 @@ func TestDeleteEvidence_Success(t *testing.T) {
 	r := evidenceAuthRouter(mock)
 	r.DELETE("/api/v1/evidence/:id", DeleteEvidence)
 
-	mock.ExpectQuery("SELECT title").WillReturnRows(
-		sqlmock.NewRows([]string{"title"}).AddRow("Evidence to Delete"),
+	mock.ExpectQuery("SELECT ea.title").WillReturnRows(
+		sqlmock.NewRows([]string{"title", "legal_hold"}).AddRow("Evidence to Delete", false),
 	)
 	mock.ExpectExec("UPDATE evidence_artifacts SET status").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("FROM evidence_retention_deadlines").WillReturnRows(
+		sqlmock.NewRows([]string{"count", "undecided", "max"}).AddRow(0, 0, nil),
+	)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/api/v1/evidence/e001", nil)
@@ -315,7 +327,7 @@ func TestDeleteEvidence_NotFound(t *testing.T) {
 	r := evidenceAuthRouter(mock)
 	r.DELETE("/api/v1/evidence/:id", DeleteEvidence)
 
-	mock.ExpectQuery("SELECT title").WillReturnRows(sqlmock.NewRows(nil))
+	mock.ExpectQuery("SELECT ea.title").WillReturnRows(sqlmock.NewRows(nil))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/api/v1/evidence/nonexistent", nil)
@@ -334,6 +346,10 @@ func TestConfirmUpload_Success(t *testing.T) {
 		sqlmock.NewRows([]string{"status", "object_key", "uploaded_by", "file_size"}).
 			AddRow("draft", "a001/e001/1/test.pdf", &userID, 1024),
 	)
+	mock.ExpectQuery("SELECT ea.version, COALESCE\\(prev.chain_hash").WillReturnRows(
+		sqlmock.NewRows([]string{"version", "chain_hash"}).AddRow(1, ""),
+	)
+	mock.ExpectExec("UPDATE evidence_artifacts SET").WillReturnResult(sqlmock.NewResult(0, 1))
 
 	body := `{"checksum_sha256": "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"}`
 	w := httptest.NewRecorder()
@@ -348,6 +364,8 @@ func TestConfirmUpload_Success(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	data := resp["data"].(map[string]interface{})
 	assert.Equal(t, true, data["file_verified"])
+	assert.Equal(t, false, data["checksum_verified"])
+	assert.Len(t, data["chain_hash"], 64)
 }
 
 func TestConfirmUpload_AlreadyConfirmed(t *testing.T) {
@@ -799,3 +817,153 @@ func TestListVersions_Success(t *testing.T) {
 	assert.Equal(t, float64(2), meta["total_versions"])
 	assert.Equal(t, float64(2), meta["current_version"])
 }
+
+func downloadRow(processingStatus string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"object_key", "file_name", "mime_type", "file_size", "status", "processing_status", "purged", "access_restricted", "id"}).
+		AddRow("a001/e001/1/report.pdf", "report.pdf", "application/pdf", 2048, "approved", processingStatus, false, false, "e001")
+}
+
+func TestGetDownloadURL_ScanPending(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.GET("/api/v1/evidence/:id/download", GetDownloadURL)
+
+	mock.ExpectQuery("SELECT object_key, file_name").WillReturnRows(downloadRow("scanning"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence/e001/download", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "PROCESSING_PENDING")
+}
+
+func TestGetDownloadURL_Quarantined(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.GET("/api/v1/evidence/:id/download", GetDownloadURL)
+
+	mock.ExpectQuery("SELECT object_key, file_name").WillReturnRows(downloadRow("quarantined"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence/e001/download", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "EVIDENCE_QUARANTINED")
+}
+
+func TestGetDownloadURL_NotScanned(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.GET("/api/v1/evidence/:id/download", GetDownloadURL)
+
+	mock.ExpectQuery("SELECT object_key, file_name").WillReturnRows(downloadRow("not_scanned"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence/e001/download", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "PROCESSING_NOT_SCANNED")
+}
+
+func TestGetDownloadURL_NotScannedAllowed(t *testing.T) {
+	models.AllowUnscannedEvidence = true
+	defer func() { models.AllowUnscannedEvidence = false }()
+
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.GET("/api/v1/evidence/:id/download", GetDownloadURL)
+
+	mock.ExpectQuery("SELECT object_key, file_name").WillReturnRows(downloadRow("not_scanned"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence/e001/download", nil)
+	r.ServeHTTP(w, req)
+
+	// Opted in to serving unscanned evidence; MinIO is not configured in tests
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestGetDownloadURL_PassedWithoutStorage(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.GET("/api/v1/evidence/:id/download", GetDownloadURL)
+
+	mock.ExpectQuery("SELECT object_key, file_name").WillReturnRows(downloadRow("passed"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence/e001/download", nil)
+	r.ServeHTTP(w, req)
+
+	// Scanning passed; MinIO is not configured in tests
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestSearchEvidence_ContentMatch(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := evidenceAuthRouter(mock)
+	r.GET("/api/v1/evidence/search", SearchEvidence)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM evidence_artifacts ea WHERE .*evidence_contents").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("LEFT JOIN evidence_contents ct").WillReturnRows(
+		sqlmock.NewRows([]string{
+			"id", "title", "description", "evidence_type", "status",
+			"collection_method", "file_name", "file_size", "mime_type",
+			"version", "is_current", "collection_date", "expires_at",
+			"freshness_period_days", "source_system",
+			"uploaded_by", "uploader_name",
+			"tags", "created_at", "updated_at",
+			"links_count", "evaluations_count",
+			"meta_match", "content_match", "content",
+		}).AddRow(
+			"e001", "Okta export", nil, "configuration_export", "approved",
+			"system_export", "okta.json", 1024, "application/json",
+			1, true, "2026-10-01", nil,
+			nil, "okta",
+			nil, "",
+			pq.Array([]string{}), now, now,
+			2, 0,
+			false, true, "policy.name: Default\npolicy.mfa.enforced: true\npolicy.mfa.factors[0]: <totp>",
+		),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/evidence/search?q=mfa+enforced&sort=relevance", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].([]interface{})
+	require.Len(t, data, 1)
+	item := data[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"content"}, item["matched_in"])
+	snippets := item["content_snippets"].([]interface{})
+	require.Len(t, snippets, 1)
+	assert.Contains(t, snippets[0], "<mark>mfa</mark>.<mark>enforced</mark>")
+	assert.Contains(t, snippets[0], "&lt;totp&gt;")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGenerateContentSnippets(t *testing.T) {
+	text := strings.Repeat("filler text ", 20) + "MFA is enforced for all users. " + strings.Repeat("more ", 40) + "Enforced MFA exceptions: none."
+
+	snippets := generateContentSnippets(text, "mfa is enforced", 3)
+	require.Len(t, snippets, 1)
+	assert.Contains(t, snippets[0], "<mark>MFA is enforced</mark>")
+	assert.True(t, strings.HasPrefix(snippets[0], "..."))
+
+	snippets = generateContentSnippets(text, "enforced mfa", 3)
+	require.Len(t, snippets, 1)
+	assert.Contains(t, snippets[0], "<mark>Enforced MFA</mark>")
+
+	snippets = generateContentSnippets(text, "exceptions users", 3)
+	assert.Len(t, snippets, 2)
+
+	assert.Empty(t, generateContentSnippets(text, "password", 3))
+}
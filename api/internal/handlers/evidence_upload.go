@@ -6,16 +6,20 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/half-paul/raisin-protect/api/internal/middleware"
 	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
 	"github.com/rs/zerolog/log"
 )
 
 var checksumRegex = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
 
-// ConfirmEvidenceUpload confirms that a file upload to MinIO is complete.
+// ConfirmEvidenceUpload confirms that a file upload to MinIO is complete. The server streams
+// the stored object to compute its SHA-256, rejects a mismatching client checksum, and seals
+// the version into the artifact's hash chain.
 func ConfirmEvidenceUpload(c *gin.Context) {
 	orgID := middleware.GetOrgID(c)
 	callerID := middleware.GetUserID(c)
@@ -55,8 +59,15 @@ func ConfirmEvidenceUpload(c *gin.Context) {
 	var req models.ConfirmUploadRequest
 	c.ShouldBindJSON(&req) // optional body
 
+	if req.ChecksumSHA256 != nil && !checksumRegex.MatchString(*req.ChecksumSHA256) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "checksum_sha256 must be a 64-character hex string"))
+		return
+	}
+
 	// Verify file in MinIO
 	var actualSize int64
+	var checksum string
+	serverVerified := false
 	if minioService != nil {
 		actual, err := minioService.VerifyObjectExists(objectKey)
 		if err != nil {
@@ -81,26 +92,62 @@ func ConfirmEvidenceUpload(c *gin.Context) {
 				return
 			}
 		}
+
+		sum, _, err := minioService.HashObject(c.Request.Context(), objectKey)
+		if err != nil {
+			log.Error().Err(err).Str("artifact_id", artifactID).Msg("Failed to hash uploaded object")
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", "File could not be read from storage"))
+			return
+		}
+		checksum = sum
+		serverVerified = true
 	} else {
 		actualSize = fileSize // no MinIO in dev/test
 	}
 
-	// Store checksum if provided
 	if req.ChecksumSHA256 != nil {
-		if !checksumRegex.MatchString(*req.ChecksumSHA256) {
-			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "checksum_sha256 must be a 64-character hex string"))
+		clientChecksum := strings.ToLower(*req.ChecksumSHA256)
+		if serverVerified && clientChecksum != checksum {
+			middleware.LogAudit(c, "evidence.checksum_mismatch", "evidence", &artifactID, map[string]interface{}{
+				"client_checksum": clientChecksum, "server_checksum": checksum,
+			})
+			c.JSON(http.StatusUnprocessableEntity, errorResponseWithDetails("CHECKSUM_MISMATCH",
+				"Uploaded file does not match checksum_sha256", []gin.H{
+					{"field": "checksum_sha256", "expected": clientChecksum, "actual": checksum},
+				}))
 			return
 		}
-		database.Exec("UPDATE evidence_artifacts SET checksum_sha256 = $1 WHERE id = $2", *req.ChecksumSHA256, artifactID)
+		if !serverVerified {
+			checksum = clientChecksum
+		}
+	}
+
+	var chainHash *string
+	if checksum != "" {
+		h, err := services.SealEvidenceChain(c.Request.Context(), database.DB, orgID, artifactID, checksum, serverVerified)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to seal evidence chain")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+			return
+		}
+		chainHash = &h
+	}
+
+	var checksumResp *string
+	if checksum != "" {
+		checksumResp = &checksum
 	}
 
 	c.JSON(http.StatusOK, successResponse(c, gin.H{
-		"id":               artifactID,
-		"status":           "draft",
-		"file_verified":    true,
-		"file_size_actual": actualSize,
-		"checksum_sha256":  req.ChecksumSHA256,
-		"message":          "Upload confirmed. Artifact ready for review.",
+		"id":                artifactID,
+		"status":            "draft",
+		"file_verified":     true,
+		"file_size_actual":  actualSize,
+		"checksum_sha256":   checksumResp,
+		"checksum_verified": serverVerified,
+		"chain_hash":        chainHash,
+		"processing_status": models.ProcessingPending,
+		"message":           "Upload confirmed. Artifact ready for review.",
 	}))
 }
 
@@ -168,8 +215,10 @@ func GetDownloadURL(c *gin.Context) {
 
 	// Check for specific version request
 	versionStr := c.Query("version")
-	var objectKey, fileName, mimeType, status string
+	var objectKey, fileName, mimeType, status, processingStatus string
 	var fSize int64
+	var purged, restricted bool
+	var resolvedID string
 
 	if versionStr != "" {
 		version, err := strconv.Atoi(versionStr)
@@ -179,10 +228,10 @@ func GetDownloadURL(c *gin.Context) {
 		}
 		// Find the specific version
 		err = database.QueryRow(`
-			SELECT object_key, file_name, mime_type, file_size, status
+			SELECT object_key, file_name, mime_type, file_size, status, processing_status, purged_at IS NOT NULL, access_restricted, id
 			FROM evidence_artifacts
 			WHERE (id = $1 OR parent_artifact_id = $1) AND org_id = $2 AND version = $3
-		`, artifactID, orgID, version).Scan(&objectKey, &fileName, &mimeType, &fSize, &status)
+		`, artifactID, orgID, version).Scan(&objectKey, &fileName, &mimeType, &fSize, &status, &processingStatus, &purged, &restricted, &resolvedID)
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Version not found"))
 			return
@@ -194,9 +243,9 @@ func GetDownloadURL(c *gin.Context) {
 		}
 	} else {
 		err := database.QueryRow(`
-			SELECT object_key, file_name, mime_type, file_size, status
+			SELECT object_key, file_name, mime_type, file_size, status, processing_status, purged_at IS NOT NULL, access_restricted, id
 			FROM evidence_artifacts WHERE id = $1 AND org_id = $2
-		`, artifactID, orgID).Scan(&objectKey, &fileName, &mimeType, &fSize, &status)
+		`, artifactID, orgID).Scan(&objectKey, &fileName, &mimeType, &fSize, &status, &processingStatus, &purged, &restricted, &resolvedID)
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Evidence artifact not found"))
 			return
@@ -208,6 +257,17 @@ func GetDownloadURL(c *gin.Context) {
 		}
 	}
 
+	if purged {
+		c.JSON(http.StatusGone, errorResponse("EVIDENCE_PURGED", "File was deleted under the retention policy"))
+		return
+	}
+
+	// Originals replaced by a redacted version may contain sensitive data.
+	if restricted && !models.HasRole(middleware.GetUserRole(c), models.EvidenceRestrictedViewRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("EVIDENCE_RESTRICTED", "Access to the unredacted original is restricted; download the redacted version"))
+		return
+	}
+
 	if status == "draft" {
 		// Check if file was actually uploaded
 		if minioService != nil {
@@ -218,6 +278,24 @@ func GetDownloadURL(c *gin.Context) {
 		}
 	}
 
+	// Only hand out files that passed content validation and malware scanning (or content
+	// validation alone when unscanned evidence is explicitly allowed).
+	switch {
+	case models.IsProcessingCleared(processingStatus):
+	case processingStatus == models.ProcessingNotScanned:
+		c.JSON(http.StatusConflict, errorResponse("PROCESSING_NOT_SCANNED", "File has not been malware-scanned; download is unavailable until a scanner is configured"))
+		return
+	case processingStatus == models.ProcessingQuarantined:
+		c.JSON(http.StatusForbidden, errorResponse("EVIDENCE_QUARANTINED", "File failed content validation or malware scanning and has been quarantined"))
+		return
+	case processingStatus == models.ProcessingFailed:
+		c.JSON(http.StatusConflict, errorResponse("PROCESSING_FAILED", "File could not be scanned; download is unavailable"))
+		return
+	default:
+		c.JSON(http.StatusConflict, errorResponse("PROCESSING_PENDING", "File is still being scanned; try again shortly"))
+		return
+	}
+
 	if minioService == nil {
 		c.JSON(http.StatusServiceUnavailable, errorResponse("SERVICE_UNAVAILABLE", "Storage service not available"))
 		return
@@ -230,6 +308,12 @@ func GetDownloadURL(c *gin.Context) {
 		return
 	}
 
+	if restricted {
+		middleware.LogAudit(c, "evidence.restricted_download", "evidence_artifact", &resolvedID, map[string]interface{}{
+			"file_name": fileName,
+		})
+	}
+
 	c.JSON(http.StatusOK, successResponse(c, gin.H{
 		"id":        artifactID,
 		"file_name": fileName,
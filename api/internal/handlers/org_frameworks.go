@@ -130,6 +130,33 @@ func computeCoverageStats(orgID, versionID string) gin.H {
 		coveragePct = float64(mapped) / float64(inScope) * 100
 	}
 
+	// Shared responsibility split of mapped requirements: provider when every mapped
+	// control is inherited as provider-owned, shared when any inherited control carries
+	// provider or shared responsibility, otherwise customer.
+	var inherited, respCustomer, respProvider, respShared int
+	database.QueryRow(`
+		SELECT COUNT(*) FILTER (WHERE has_inherited),
+			COUNT(*) FILTER (WHERE resp = 'customer'),
+			COUNT(*) FILTER (WHERE resp = 'provider'),
+			COUNT(*) FILTER (WHERE resp = 'shared')
+		FROM (
+			SELECT r.id,
+				BOOL_OR(ci.id IS NOT NULL) AS has_inherited,
+				CASE
+					WHEN BOOL_AND(COALESCE(ci.responsibility::text, 'customer') = 'provider') THEN 'provider'
+					WHEN BOOL_OR(ci.responsibility IN ('provider', 'shared')) THEN 'shared'
+					ELSE 'customer'
+				END AS resp
+			FROM requirements r
+			JOIN control_mappings cm ON cm.requirement_id = r.id AND cm.org_id = $1
+			LEFT JOIN control_inheritances ci ON ci.control_id = cm.control_id
+			LEFT JOIN requirement_scopes rs ON rs.requirement_id = r.id AND rs.org_id = $1
+			WHERE r.framework_version_id = $2 AND r.is_assessable = TRUE
+			  AND (rs.id IS NULL OR rs.in_scope = TRUE)
+			GROUP BY r.id
+		) req_resp
+	`, orgID, versionID).Scan(&inherited, &respCustomer, &respProvider, &respShared)
+
 	return gin.H{
 		"total_requirements": totalReqs,
 		"in_scope":           inScope,
@@ -137,6 +164,12 @@ func computeCoverageStats(orgID, versionID string) gin.H {
 		"mapped":             mapped,
 		"unmapped":           unmapped,
 		"coverage_pct":       coveragePct,
+		"inherited":          inherited,
+		"responsibility": gin.H{
+			"customer": respCustomer,
+			"provider": respProvider,
+			"shared":   respShared,
+		},
 	}
 }
 
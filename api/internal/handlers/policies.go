@@ -14,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/half-paul/raisin-protect/api/internal/middleware"
 	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
 	"github.com/lib/pq"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/rs/zerolog/log"
@@ -807,7 +808,8 @@ func ArchivePolicy(c *gin.Context) {
 	}))
 }
 
-// SubmitForReview transitions a policy to in_review and creates signoff requests.
+// SubmitForReview transitions a policy to in_review and creates signoff requests. The response
+// includes the diff against the last signed-off version so reviewers see exactly what changed.
 func SubmitForReview(c *gin.Context) {
 	orgID := middleware.GetOrgID(c)
 	userID := middleware.GetUserID(c)
@@ -861,6 +863,19 @@ func SubmitForReview(c *gin.Context) {
 		return
 	}
 
+	// Template variables must all be filled in before review
+	var content string
+	if err := database.DB.QueryRow(`SELECT content FROM policy_versions WHERE id = $1`, *currentVersionID).Scan(&content); err != nil {
+		log.Error().Err(err).Msg("Failed to get policy content")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to submit for review"))
+		return
+	}
+	if placeholders := services.FindPolicyPlaceholders(content); len(placeholders) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNRESOLVED_PLACEHOLDERS",
+			"Policy content has unresolved template variables: "+strings.Join(placeholders, ", ")))
+		return
+	}
+
 	// Validate signers exist in org
 	for _, signerID := range req.SignerIDs {
 		var signerExists bool
@@ -871,6 +886,11 @@ func SubmitForReview(c *gin.Context) {
 		}
 	}
 
+	changes, err := reviewBaselineDiff(*currentVersionID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to diff policy version for review")
+	}
+
 	tx, err := database.DB.Begin()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to submit for review"))
@@ -929,21 +949,28 @@ func SubmitForReview(c *gin.Context) {
 		return
 	}
 
-	middleware.LogAudit(c, "policy.status_changed", "policy", &policyID, map[string]interface{}{
-		"from": currentStatus, "to": "in_review",
-	})
+	auditDetails := map[string]interface{}{"from": currentStatus, "to": "in_review"}
+	if changes != nil {
+		auditDetails["changes"] = changes.Stats
+		auditDetails["compared_to_version"] = changes.FromVersion
+	}
+	middleware.LogAudit(c, "policy.status_changed", "policy", &policyID, auditDetails)
 
 	c.JSON(http.StatusOK, successResponse(c, gin.H{
 		"id":               policyID,
 		"status":           "in_review",
 		"signoffs_created": len(signoffs),
 		"signoffs":         signoffs,
+		"changes":          changes,
 	}))
 }
 
-// PublishPolicy publishes an approved policy.
+// PublishPolicy publishes an approved policy and starts an acknowledgement campaign for the
+// published version. The audience comes from the request body, or is carried forward from the
+// policy's previous campaign.
 func PublishPolicy(c *gin.Context) {
 	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
 	userRole := middleware.GetUserRole(c)
 	policyID := c.Param("id")
 
@@ -953,6 +980,15 @@ func PublishPolicy(c *gin.Context) {
 		return
 	}
 
+	// The body is optional
+	var req models.PublishPolicyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+			return
+		}
+	}
+
 	var currentStatus string
 	var reviewFreqDays *int
 	var currentVersionID *string
@@ -990,6 +1026,28 @@ func PublishPolicy(c *gin.Context) {
 		database.DB.QueryRow(`SELECT version_number FROM policy_versions WHERE id = $1`, *currentVersionID).Scan(&versionNum)
 	}
 
+	// Resolve who must acknowledge the new version before anything changes
+	var ackAudience *policyAckAudience
+	if !req.SkipAcknowledgement && versionNum != nil {
+		if req.Acknowledgement != nil {
+			a, msg := parsePolicyAckAudience(req.Acknowledgement, now)
+			if msg != "" {
+				c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", msg))
+				return
+			}
+			if !checkPolicyAckAudience(c, orgID, a) {
+				return
+			}
+			ackAudience = a
+		} else {
+			a, err := previousPolicyAckAudience(policyID, orgID, now)
+			if err != nil {
+				log.Error().Err(err).Str("policy_id", policyID).Msg("Failed to load previous acknowledgement audience")
+			}
+			ackAudience = a
+		}
+	}
+
 	query := fmt.Sprintf(`UPDATE policies SET %s WHERE id = $1 AND org_id = $2 RETURNING published_at`, sets)
 	var publishedAt time.Time
 	err = database.DB.QueryRow(query, args...).Scan(&publishedAt)
@@ -999,10 +1057,49 @@ func PublishPolicy(c *gin.Context) {
 		return
 	}
 
+	// The version's effective date; republishing the same version keeps the first one.
+	if currentVersionID != nil {
+		if _, err := database.Exec(`UPDATE policy_versions SET published_at = $1 WHERE id = $2 AND published_at IS NULL`,
+			publishedAt, *currentVersionID); err != nil {
+			log.Error().Err(err).Str("policy_id", policyID).Msg("Failed to record version publish date")
+		}
+	}
+
 	middleware.LogAudit(c, "policy.status_changed", "policy", &policyID, map[string]interface{}{
 		"from": "approved", "to": "published",
 	})
 
+	// The policy is published either way; a campaign that fails to start can be created by hand.
+	var ackCampaign gin.H
+	if ackAudience != nil {
+		started, err := startPolicyAckCampaign(orgID, policyID, *currentVersionID, *versionNum, ackAudience, userID)
+		if err != nil {
+			log.Error().Err(err).Str("policy_id", policyID).Msg("Failed to start acknowledgement campaign")
+		} else {
+			middleware.LogAudit(c, "policy_ack_campaign.created", "policy_ack_campaign", &started.ID, map[string]interface{}{
+				"policy_id": policyID, "version_number": *versionNum,
+				"assigned": started.Assigned, "superseded": started.Superseded,
+			})
+			ackCampaign = policyAckCampaignJSON(started, *currentVersionID, *versionNum, ackAudience)
+		}
+	}
+
+	// Rendered documents are stored as evidence when storage is configured; they can be
+	// regenerated through POST /policies/:id/documents.
+	var documents []gin.H
+	if versionNum != nil && objectStore != nil {
+		docs, err := storePolicyDocuments(c.Request.Context(), orgID, policyID, *versionNum, models.ValidPolicyDocumentFormats, &userID)
+		if err != nil {
+			log.Error().Err(err).Str("policy_id", policyID).Msg("Failed to store policy documents")
+		}
+		if len(docs) > 0 {
+			documents = docs
+			middleware.LogAudit(c, "policy.document_rendered", "policy", &policyID, map[string]interface{}{
+				"version_number": *versionNum, "formats": models.ValidPolicyDocumentFormats,
+			})
+		}
+	}
+
 	c.JSON(http.StatusOK, successResponse(c, gin.H{
 		"id":           policyID,
 		"status":       "published",
@@ -1010,5 +1107,7 @@ func PublishPolicy(c *gin.Context) {
 		"current_version": gin.H{
 			"version_number": versionNum,
 		},
+		"acknowledgement_campaign": ackCampaign,
+		"documents":                documents,
 	}))
 }
@@ -440,11 +440,22 @@ func TestSubmitForReview_Success(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"status", "owner_id", "current_version_id"}).
 			AddRow("draft", "user-001", "version-001"))
 
+	// Check for unresolved template variables
+	mock.ExpectQuery("SELECT content FROM policy_versions").
+		WithArgs("version-001").
+		WillReturnRows(sqlmock.NewRows([]string{"content"}).AddRow("<h2>Scope</h2><p>All staff and contractors.</p>"))
+
 	// Validate signers
 	mock.ExpectQuery("SELECT EXISTS").
 		WithArgs("signer-001", "org-001").
 		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 
+	// Diff against the last signed-off version
+	mock.ExpectQuery("FROM policy_versions cur").
+		WithArgs("version-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"version_number", "content", "content_format", "base_version", "base_content", "base_format"}).
+			AddRow(2, "<h2>Scope</h2><p>All staff and contractors.</p>", "html", 1, "<h2>Scope</h2><p>All staff.</p>", "html"))
+
 	mock.ExpectBegin()
 
 	// Update status
@@ -479,6 +490,9 @@ func TestSubmitForReview_Success(t *testing.T) {
 	data := resp["data"].(map[string]interface{})
 	assert.Equal(t, "in_review", data["status"])
 	assert.Equal(t, float64(1), data["signoffs_created"])
+	changes := data["changes"].(map[string]interface{})
+	assert.Equal(t, float64(1), changes["from_version"])
+	assert.Equal(t, float64(1), changes["stats"].(map[string]interface{})["paragraphs_modified"])
 }
 
 func TestSubmitForReview_InvalidStatus(t *testing.T) {
@@ -520,6 +534,93 @@ func TestSubmitForReview_TooManySigners(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestListPolicySignoffs_IncludesChanges(t *testing.T) {
+	router, mock := setupPolicyRouter()
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("policy-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	now := time.Now()
+	mock.ExpectQuery("FROM policy_signoffs ps").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "policy_id", "pv_id", "version_number",
+			"signer_id", "s_first", "s_last", "s_email", "signer_role",
+			"requested_by", "r_first", "r_last",
+			"requested_at", "due_date", "status", "decided_at", "comments",
+			"reminder_count", "reminder_sent_at",
+		}).
+			AddRow("signoff-001", "policy-001", "version-002", 2,
+				"user-001", "Alice", "Smith", "alice@acme.com", "ciso",
+				"user-002", "Bob", "Jones",
+				now, nil, "pending", nil, nil,
+				0, nil).
+			AddRow("signoff-002", "policy-001", "version-002", 2,
+				"user-003", "Carol", "White", "carol@acme.com", "compliance_manager",
+				"user-002", "Bob", "Jones",
+				now, nil, "pending", nil, nil,
+				0, nil))
+
+	// One diff per version, shared by both signoffs
+	mock.ExpectQuery("FROM policy_versions cur").
+		WithArgs("version-002", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"version_number", "content", "content_format", "base_version", "base_content", "base_format"}).
+			AddRow(2, "<p>All staff and contractors.</p>", "html", 1, "<p>All staff.</p>", "html"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policies/policy-001/signoffs", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	signoffs := resp["data"].([]interface{})
+	assert.Len(t, signoffs, 2)
+	for _, s := range signoffs {
+		changes := s.(map[string]interface{})["changes"].(map[string]interface{})
+		assert.Equal(t, float64(1), changes["from_version"])
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListPendingSignoffs_IncludesChanges(t *testing.T) {
+	router, mock := setupPolicyRouter()
+
+	mock.ExpectQuery("SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	now := time.Now()
+	mock.ExpectQuery("FROM policy_signoffs ps").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "p_id", "identifier", "title", "category",
+			"pv_id", "version_number", "content_summary", "word_count",
+			"requested_by", "r_first", "r_last",
+			"requested_at", "due_date", "reminder_count",
+		}).AddRow("signoff-001", "policy-001", "POL-IS-001", "Information Security Policy", "information_security",
+			"version-002", 2, "Scope widened", 120,
+			"user-002", "Bob", "Jones",
+			now, nil, 0))
+
+	mock.ExpectQuery("FROM policy_versions cur").
+		WithArgs("version-002", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"version_number", "content", "content_format", "base_version", "base_content", "base_format"}).
+			AddRow(2, "<p>All staff and contractors.</p>", "html", 1, "<p>All staff.</p>", "html"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/signoffs/pending", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	results := resp["data"].([]interface{})
+	assert.Len(t, results, 1)
+	changes := results[0].(map[string]interface{})["changes"].(map[string]interface{})
+	assert.Equal(t, float64(1), changes["from_version"])
+	assert.Equal(t, float64(2), changes["to_version"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestApproveSignoff_Success(t *testing.T) {
 	router, mock := setupPolicyRouter()
 
@@ -639,10 +740,20 @@ func TestPublishPolicy_Success(t *testing.T) {
 	mock.ExpectQuery("SELECT version_number FROM policy_versions").
 		WillReturnRows(sqlmock.NewRows([]string{"version_number"}).AddRow(1))
 
+	// No previous acknowledgement campaign to carry forward
+	mock.ExpectQuery("FROM policy_ack_campaigns").
+		WithArgs("policy-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"all_users"}))
+
 	// Update to published
 	mock.ExpectQuery("UPDATE policies SET").
 		WillReturnRows(sqlmock.NewRows([]string{"published_at"}).AddRow(time.Now()))
 
+	// Version effective date
+	mock.ExpectExec("UPDATE policy_versions SET published_at").
+		WithArgs(sqlmock.AnyArg(), "version-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
 	// Audit
 
 	w := httptest.NewRecorder()
@@ -655,6 +766,78 @@ func TestPublishPolicy_Success(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	data := resp["data"].(map[string]interface{})
 	assert.Equal(t, "published", data["status"])
+	assert.Nil(t, data["acknowledgement_campaign"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublishPolicy_StartsAcknowledgementCampaign(t *testing.T) {
+	router, mock := setupPolicyRouter()
+
+	mock.ExpectQuery("SELECT status, review_frequency_days, current_version_id").
+		WithArgs("policy-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "review_frequency_days", "current_version_id"}).
+			AddRow("approved", nil, "version-002"))
+	mock.ExpectQuery("SELECT version_number FROM policy_versions").
+		WillReturnRows(sqlmock.NewRows([]string{"version_number"}).AddRow(2))
+
+	// Audience resolves to active users
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users u").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+	mock.ExpectQuery("UPDATE policies SET").
+		WillReturnRows(sqlmock.NewRows([]string{"published_at"}).AddRow(time.Now()))
+	mock.ExpectExec("UPDATE policy_versions SET published_at").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE policy_acknowledgements SET status = 'superseded'").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("UPDATE policy_ack_campaigns SET status = 'superseded'").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_ack_campaigns").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_acknowledgements").
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectCommit()
+
+	body := `{"acknowledgement": {"all_users": true, "due_in_days": 14, "reminder_interval_days": 7}}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/publish", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	campaign := data["acknowledgement_campaign"].(map[string]interface{})
+	assert.Equal(t, float64(12), campaign["acknowledgements_created"])
+	assert.Equal(t, float64(3), campaign["superseded_pending"])
+	assert.Equal(t, float64(7), campaign["reminder_interval_days"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublishPolicy_InvalidAcknowledgementRole(t *testing.T) {
+	router, mock := setupPolicyRouter()
+
+	mock.ExpectQuery("SELECT status, review_frequency_days, current_version_id").
+		WithArgs("policy-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "review_frequency_days", "current_version_id"}).
+			AddRow("approved", nil, "version-002"))
+	mock.ExpectQuery("SELECT version_number FROM policy_versions").
+		WillReturnRows(sqlmock.NewRows([]string{"version_number"}).AddRow(2))
+
+	body := `{"acknowledgement": {"roles": ["intern"]}}`
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/publish", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code, "policy must not be published with an invalid audience")
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestPublishPolicy_NotApproved(t *testing.T) {
@@ -775,6 +958,10 @@ func TestCloneTemplate_Success(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"content", "content_format", "word_count"}).
 			AddRow("<h1>Template Content</h1>", "html", 100))
 
+	// Template variables (none declared)
+	mock.ExpectQuery("FROM policy_template_variables").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "label", "var_type", "description", "default_value", "required"}))
+
 	mock.ExpectBegin()
 	mock.ExpectExec("INSERT INTO policies").WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectExec("INSERT INTO policy_versions").WillReturnResult(sqlmock.NewResult(1, 1))
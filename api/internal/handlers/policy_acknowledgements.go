@@ -0,0 +1,1011 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// errPolicyAckNoAudience is returned when a campaign's audience resolves to no active users.
+var errPolicyAckNoAudience = errors.New("acknowledgement audience has no active users")
+
+// policyAckAudience is a validated campaign audience with its schedule.
+type policyAckAudience struct {
+	AllUsers             bool
+	Roles                []string
+	UserIDs              []string
+	DueDate              *time.Time
+	ReminderIntervalDays *int
+}
+
+// parsePolicyAckAudience validates an audience request. Returns a validation message on failure.
+func parsePolicyAckAudience(req *models.PolicyAckAudienceRequest, now time.Time) (*policyAckAudience, string) {
+	if !req.AllUsers && len(req.Roles) == 0 && len(req.UserIDs) == 0 {
+		return nil, "One of all_users, roles or user_ids is required"
+	}
+	for _, r := range req.Roles {
+		if !models.IsValidRole(r) {
+			return nil, "Invalid role: " + r
+		}
+	}
+	if len(req.UserIDs) > 1000 {
+		return nil, "Maximum 1000 users per campaign"
+	}
+	if req.ReminderIntervalDays != nil && (*req.ReminderIntervalDays < 1 || *req.ReminderIntervalDays > 90) {
+		return nil, "reminder_interval_days must be between 1 and 90"
+	}
+	if req.DueDate != nil && req.DueInDays != nil {
+		return nil, "Specify due_date or due_in_days, not both"
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	due := today.AddDate(0, 0, models.PolicyAckDefaultDueDays)
+	if req.DueDate != nil {
+		parsed, err := time.Parse("2006-01-02", *req.DueDate)
+		if err != nil {
+			return nil, "due_date must be YYYY-MM-DD"
+		}
+		if parsed.Before(today) {
+			return nil, "due_date cannot be in the past"
+		}
+		due = parsed
+	}
+	if req.DueInDays != nil {
+		if *req.DueInDays < 1 || *req.DueInDays > 365 {
+			return nil, "due_in_days must be between 1 and 365"
+		}
+		due = today.AddDate(0, 0, *req.DueInDays)
+	}
+
+	roles := req.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	userIDs := req.UserIDs
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+	return &policyAckAudience{
+		AllUsers:             req.AllUsers,
+		Roles:                roles,
+		UserIDs:              userIDs,
+		DueDate:              &due,
+		ReminderIntervalDays: req.ReminderIntervalDays,
+	}, ""
+}
+
+// checkPolicyAckAudience verifies that named users are active members of the org and that the
+// audience resolves to at least one user. Writes the error response and returns false otherwise.
+func checkPolicyAckAudience(c *gin.Context, orgID string, a *policyAckAudience) bool {
+	if len(a.UserIDs) > 0 {
+		var found int
+		database.QueryRow(`SELECT COUNT(*) FROM users WHERE org_id = $1 AND status = 'active' AND id = ANY($2::uuid[])`,
+			orgID, pq.Array(a.UserIDs)).Scan(&found)
+		if found != len(a.UserIDs) {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "One or more users not found or inactive"))
+			return false
+		}
+		return true
+	}
+
+	var count int
+	database.QueryRow(`
+		SELECT COUNT(*) FROM users u
+		WHERE u.org_id = $1 AND u.status = 'active'
+			AND ($2 OR u.role::text = ANY($3::text[]))
+	`, orgID, a.AllUsers, pq.Array(a.Roles)).Scan(&count)
+	if count == 0 {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", "No active users match the acknowledgement audience"))
+		return false
+	}
+	return true
+}
+
+// previousPolicyAckAudience returns the audience of the policy's most recent campaign so that a
+// republished policy goes to the same people, with the same acknowledgement window. Returns nil
+// when the policy has never had a campaign.
+func previousPolicyAckAudience(policyID, orgID string, now time.Time) (*policyAckAudience, error) {
+	var (
+		a       policyAckAudience
+		dueDays *int
+	)
+	err := database.QueryRow(`
+		SELECT all_users, audience_roles, audience_user_ids, reminder_interval_days,
+			due_date - created_at::date
+		FROM policy_ack_campaigns
+		WHERE policy_id = $1 AND org_id = $2 AND status <> 'cancelled'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, policyID, orgID).Scan(&a.AllUsers, pq.Array(&a.Roles), pq.Array(&a.UserIDs), &a.ReminderIntervalDays, &dueDays)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	days := models.PolicyAckDefaultDueDays
+	if dueDays != nil && *dueDays > 0 {
+		days = *dueDays
+	}
+	due := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, days)
+	a.DueDate = &due
+	if a.Roles == nil {
+		a.Roles = []string{}
+	}
+	if a.UserIDs == nil {
+		a.UserIDs = []string{}
+	}
+	return &a, nil
+}
+
+// policyAckCampaignStart is the outcome of starting an acknowledgement campaign.
+type policyAckCampaignStart struct {
+	ID         string
+	Assigned   int64
+	Superseded int64
+}
+
+// startPolicyAckCampaign creates a campaign for a policy version and one pending acknowledgement
+// per active user in the audience. Earlier campaigns for the policy are superseded, along with
+// their outstanding acknowledgements; completed acknowledgements stay in each user's history.
+func startPolicyAckCampaign(orgID, policyID, versionID string, versionNumber int, a *policyAckAudience, createdBy string) (*policyAckCampaignStart, error) {
+	tx, err := database.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	out := &policyAckCampaignStart{ID: uuid.New().String()}
+
+	res, err := tx.Exec(`
+		UPDATE policy_acknowledgements SET status = 'superseded'
+		WHERE policy_id = $1 AND org_id = $2 AND status = 'pending'
+	`, policyID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("supersede acknowledgements: %w", err)
+	}
+	out.Superseded, _ = res.RowsAffected()
+
+	if _, err := tx.Exec(`
+		UPDATE policy_ack_campaigns SET status = 'superseded'
+		WHERE policy_id = $1 AND org_id = $2 AND status = 'active'
+	`, policyID, orgID); err != nil {
+		return nil, fmt.Errorf("supersede campaigns: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO policy_ack_campaigns (id, org_id, policy_id, policy_version_id, version_number,
+			all_users, audience_roles, audience_user_ids, status, due_date, reminder_interval_days, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'active', $9, $10, $11)
+	`, out.ID, orgID, policyID, versionID, versionNumber,
+		a.AllUsers, pq.Array(a.Roles), pq.Array(a.UserIDs), a.DueDate, a.ReminderIntervalDays, createdBy); err != nil {
+		return nil, fmt.Errorf("insert campaign: %w", err)
+	}
+
+	res, err = tx.Exec(`
+		INSERT INTO policy_acknowledgements (org_id, campaign_id, policy_id, policy_version_id, user_id, status)
+		SELECT u.org_id, $2, $3, $4, u.id, 'pending'
+		FROM users u
+		WHERE u.org_id = $1 AND u.status = 'active'
+			AND ($5 OR u.role::text = ANY($6::text[]) OR u.id = ANY($7::uuid[]))
+	`, orgID, out.ID, policyID, versionID, a.AllUsers, pq.Array(a.Roles), pq.Array(a.UserIDs))
+	if err != nil {
+		return nil, fmt.Errorf("insert acknowledgements: %w", err)
+	}
+	out.Assigned, _ = res.RowsAffected()
+	if out.Assigned == 0 {
+		return nil, errPolicyAckNoAudience
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit campaign: %w", err)
+	}
+	return out, nil
+}
+
+// ListPolicyAckCampaigns lists a policy's acknowledgement campaigns with completion per version.
+func ListPolicyAckCampaigns(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	policyID := c.Param("id")
+
+	var exists bool
+	database.QueryRow(`SELECT EXISTS(SELECT 1 FROM policies WHERE id = $1 AND org_id = $2)`, policyID, orgID).Scan(&exists)
+	if !exists {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy not found"))
+		return
+	}
+
+	rows, err := database.Query(`
+		SELECT pac.id, pac.policy_version_id, pac.version_number, pac.status, pac.due_date,
+			pac.all_users, pac.audience_roles, pac.audience_user_ids, pac.reminder_interval_days,
+			pac.evidence_artifact_id, pac.completed_at, pac.cancelled_at, pac.created_at,
+			COUNT(pa.id) FILTER (WHERE pa.status <> 'cancelled'),
+			COUNT(pa.id) FILTER (WHERE pa.status = 'acknowledged'),
+			COUNT(pa.id) FILTER (WHERE pa.status = 'pending'),
+			COUNT(pa.id) FILTER (WHERE pa.status = 'pending' AND pac.due_date < CURRENT_DATE),
+			COUNT(pa.id) FILTER (WHERE pa.status = 'acknowledged' AND pa.acknowledged_at::date > pac.due_date)
+		FROM policy_ack_campaigns pac
+		LEFT JOIN policy_acknowledgements pa ON pa.campaign_id = pac.id
+		WHERE pac.policy_id = $1 AND pac.org_id = $2
+		GROUP BY pac.id
+		ORDER BY pac.created_at DESC
+	`, policyID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policy acknowledgement campaigns")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []gin.H{}
+	for rows.Next() {
+		var (
+			id, versionID, status                       string
+			versionNumber                               int
+			dueDate, completedAt, cancelledAt           *time.Time
+			allUsers                                    bool
+			roles, userIDs                              []string
+			reminderInterval                            *int
+			evidenceID                                  *string
+			createdAt                                   time.Time
+			total, acknowledged, pending, overdue, late int
+		)
+		if err := rows.Scan(&id, &versionID, &versionNumber, &status, &dueDate,
+			&allUsers, pq.Array(&roles), pq.Array(&userIDs), &reminderInterval,
+			&evidenceID, &completedAt, &cancelledAt, &createdAt,
+			&total, &acknowledged, &pending, &overdue, &late); err != nil {
+			log.Error().Err(err).Msg("Failed to scan policy acknowledgement campaign")
+			continue
+		}
+
+		results = append(results, gin.H{
+			"id":                     id,
+			"policy_version":         gin.H{"id": versionID, "version_number": versionNumber},
+			"status":                 status,
+			"audience":               policyAckAudienceJSON(allUsers, roles, userIDs),
+			"due_date":               dueDate,
+			"reminder_interval_days": reminderInterval,
+			"evidence_artifact_id":   evidenceID,
+			"completed_at":           completedAt,
+			"cancelled_at":           cancelledAt,
+			"created_at":             createdAt,
+			"progress":               policyAckProgress(total, acknowledged, pending, overdue, late),
+		})
+	}
+
+	c.JSON(http.StatusOK, listResponse(c, results, len(results), 1, len(results)))
+}
+
+// CreatePolicyAckCampaign starts an acknowledgement campaign for a policy's published version.
+// Publishing starts one automatically; this is for re-running or widening a campaign.
+func CreatePolicyAckCampaign(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	policyID := c.Param("id")
+
+	var req models.PolicyAckAudienceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+	audience, msg := parsePolicyAckAudience(&req, time.Now())
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", msg))
+		return
+	}
+
+	var (
+		status        string
+		versionID     *string
+		versionNumber *int
+	)
+	err := database.QueryRow(`
+		SELECT p.status, p.current_version_id, pv.version_number
+		FROM policies p
+		LEFT JOIN policy_versions pv ON pv.id = p.current_version_id
+		WHERE p.id = $1 AND p.org_id = $2
+	`, policyID, orgID).Scan(&status, &versionID, &versionNumber)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if status != models.PolicyStatusPublished || versionID == nil || versionNumber == nil {
+		c.JSON(http.StatusConflict, errorResponse("POLICY_NOT_PUBLISHED", "Only published policies can be acknowledged"))
+		return
+	}
+
+	if !checkPolicyAckAudience(c, orgID, audience) {
+		return
+	}
+
+	started, err := startPolicyAckCampaign(orgID, policyID, *versionID, *versionNumber, audience, userID)
+	if errors.Is(err, errPolicyAckNoAudience) {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE", "No active users match the acknowledgement audience"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create policy acknowledgement campaign")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "policy_ack_campaign.created", "policy_ack_campaign", &started.ID, map[string]interface{}{
+		"policy_id": policyID, "version_number": *versionNumber,
+		"assigned": started.Assigned, "superseded": started.Superseded,
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, policyAckCampaignJSON(started, *versionID, *versionNumber, audience)))
+}
+
+// GetPolicyAckCampaign returns a campaign with each assignee's acknowledgement status.
+func GetPolicyAckCampaign(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	campaignID := c.Param("id")
+
+	var (
+		policyID, policyIdentifier, policyTitle, versionID, status string
+		versionNumber                                              int
+		allUsers                                                   bool
+		roles, userIDs                                             []string
+		dueDate, completedAt, cancelledAt                          *time.Time
+		reminderInterval                                           *int
+		evidenceID                                                 *string
+		createdAt                                                  time.Time
+	)
+	err := database.QueryRow(`
+		SELECT p.id, p.identifier, p.title, pac.policy_version_id, pac.version_number, pac.status,
+			pac.all_users, pac.audience_roles, pac.audience_user_ids, pac.due_date,
+			pac.reminder_interval_days, pac.evidence_artifact_id, pac.completed_at, pac.cancelled_at, pac.created_at
+		FROM policy_ack_campaigns pac
+		JOIN policies p ON p.id = pac.policy_id
+		WHERE pac.id = $1 AND pac.org_id = $2
+	`, campaignID, orgID).Scan(&policyID, &policyIdentifier, &policyTitle, &versionID, &versionNumber, &status,
+		&allUsers, pq.Array(&roles), pq.Array(&userIDs), &dueDate,
+		&reminderInterval, &evidenceID, &completedAt, &cancelledAt, &createdAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Acknowledgement campaign not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy acknowledgement campaign")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	where := []string{"pa.campaign_id = $1", "pa.org_id = $2"}
+	args := []interface{}{campaignID, orgID}
+	if v := c.Query("status"); v != "" {
+		where = append(where, "pa.status = $3")
+		args = append(args, v)
+	}
+
+	rows, err := database.Query(fmt.Sprintf(`
+		SELECT pa.id, pa.status, pa.acknowledged_at, pa.reminder_sent_at, pa.reminder_count,
+			u.id, u.first_name, u.last_name, u.email, u.role
+		FROM policy_acknowledgements pa
+		JOIN users u ON u.id = pa.user_id
+		WHERE %s
+		ORDER BY u.last_name, u.first_name
+	`, joinWhere(where)), args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policy acknowledgements")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	acks := []gin.H{}
+	counts := map[string]int{}
+	overdue, late := 0, 0
+	for rows.Next() {
+		var (
+			id, aStatus                    string
+			acknowledgedAt, reminderSentAt *time.Time
+			reminderCount                  int
+			uID, first, last, email, role  string
+		)
+		if err := rows.Scan(&id, &aStatus, &acknowledgedAt, &reminderSentAt, &reminderCount,
+			&uID, &first, &last, &email, &role); err != nil {
+			log.Error().Err(err).Msg("Failed to scan policy acknowledgement")
+			continue
+		}
+		counts[aStatus]++
+
+		isOverdue := aStatus == models.PolicyAckStatusPending && services.PolicyAckPastDue(dueDate, time.Now())
+		if isOverdue {
+			overdue++
+		}
+		if acknowledgedAt != nil && services.PolicyAckPastDue(dueDate, *acknowledgedAt) {
+			late++
+		}
+
+		acks = append(acks, gin.H{
+			"id":               id,
+			"status":           aStatus,
+			"user":             gin.H{"id": uID, "name": first + " " + last, "email": email, "role": role},
+			"acknowledged_at":  acknowledgedAt,
+			"overdue":          isOverdue,
+			"reminder_sent_at": reminderSentAt,
+			"reminder_count":   reminderCount,
+		})
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"id":                     campaignID,
+		"policy":                 gin.H{"id": policyID, "identifier": policyIdentifier, "title": policyTitle},
+		"policy_version":         gin.H{"id": versionID, "version_number": versionNumber},
+		"status":                 status,
+		"audience":               policyAckAudienceJSON(allUsers, roles, userIDs),
+		"due_date":               dueDate,
+		"reminder_interval_days": reminderInterval,
+		"evidence_artifact_id":   evidenceID,
+		"completed_at":           completedAt,
+		"cancelled_at":           cancelledAt,
+		"created_at":             createdAt,
+		"progress": policyAckProgress(
+			len(acks)-counts[models.PolicyAckStatusCancelled],
+			counts[models.PolicyAckStatusAcknowledged],
+			counts[models.PolicyAckStatusPending],
+			overdue, late,
+		),
+		"acknowledgements": acks,
+	}))
+}
+
+// CancelPolicyAckCampaign cancels an active campaign and its pending acknowledgements.
+func CancelPolicyAckCampaign(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	campaignID := c.Param("id")
+
+	var status string
+	err := database.QueryRow(`SELECT status FROM policy_ack_campaigns WHERE id = $1 AND org_id = $2`,
+		campaignID, orgID).Scan(&status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Acknowledgement campaign not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy acknowledgement campaign")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if status != models.PolicyAckCampaignActive {
+		c.JSON(http.StatusConflict, errorResponse("CONFLICT", "Only active campaigns can be cancelled"))
+		return
+	}
+
+	tx, err := database.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer tx.Rollback()
+
+	tx.Exec(`UPDATE policy_ack_campaigns SET status = 'cancelled', cancelled_at = NOW() WHERE id = $1`, campaignID)
+	res, _ := tx.Exec(`UPDATE policy_acknowledgements SET status = 'cancelled' WHERE campaign_id = $1 AND status = 'pending'`, campaignID)
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to cancel policy acknowledgement campaign")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	var cancelled int64
+	if res != nil {
+		cancelled, _ = res.RowsAffected()
+	}
+
+	middleware.LogAudit(c, "policy_ack_campaign.cancelled", "policy_ack_campaign", &campaignID, map[string]interface{}{
+		"pending_cancelled": cancelled,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"id":                         campaignID,
+		"status":                     models.PolicyAckCampaignCancelled,
+		"acknowledgements_cancelled": cancelled,
+	}))
+}
+
+// RemindPolicyAcknowledgements sends reminders to users who have not yet acknowledged.
+func RemindPolicyAcknowledgements(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	campaignID := c.Param("id")
+
+	var status string
+	err := database.QueryRow(`SELECT status FROM policy_ack_campaigns WHERE id = $1 AND org_id = $2`,
+		campaignID, orgID).Scan(&status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Acknowledgement campaign not found"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to send reminders"))
+		return
+	}
+	if status != models.PolicyAckCampaignActive {
+		c.JSON(http.StatusConflict, errorResponse("CONFLICT", "Reminders can only be sent for active campaigns"))
+		return
+	}
+
+	var req models.RemindPolicyAcknowledgementsRequest
+	c.ShouldBindJSON(&req)
+
+	where := "pa.campaign_id = $1 AND pa.org_id = $2 AND pa.status = 'pending'"
+	args := []interface{}{campaignID, orgID}
+	if len(req.AcknowledgementIDs) > 0 {
+		where += " AND pa.id = ANY($3::uuid[])"
+		args = append(args, pq.Array(req.AcknowledgementIDs))
+	}
+
+	rows, err := database.Query(fmt.Sprintf(`
+		SELECT pa.id, pa.user_id, u.first_name, u.last_name, pa.reminder_sent_at, pa.reminder_count
+		FROM policy_acknowledgements pa
+		LEFT JOIN users u ON u.id = pa.user_id
+		WHERE %s
+	`, where), args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to find pending acknowledgements"))
+		return
+	}
+	defer rows.Close()
+
+	type pendingAck struct {
+		ID             string
+		UserID         string
+		UserName       string
+		ReminderSentAt *time.Time
+		ReminderCount  int
+	}
+
+	var pending []pendingAck
+	for rows.Next() {
+		var pa pendingAck
+		var first, last string
+		if err := rows.Scan(&pa.ID, &pa.UserID, &first, &last, &pa.ReminderSentAt, &pa.ReminderCount); err == nil {
+			pa.UserName = first + " " + last
+			pending = append(pending, pa)
+		}
+	}
+
+	if len(pending) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("NO_PENDING_ACKNOWLEDGEMENTS", "No pending acknowledgements found"))
+		return
+	}
+
+	now := time.Now()
+	users := []gin.H{}
+	sent := 0
+
+	for _, pa := range pending {
+		// Rate limit: 1 reminder per 24h
+		if pa.ReminderSentAt != nil && now.Sub(*pa.ReminderSentAt) < 24*time.Hour {
+			continue
+		}
+
+		database.Exec(`
+			UPDATE policy_acknowledgements SET reminder_sent_at = $1, reminder_count = reminder_count + 1
+			WHERE id = $2
+		`, now, pa.ID)
+
+		users = append(users, gin.H{
+			"id":                 pa.UserID,
+			"name":               pa.UserName,
+			"acknowledgement_id": pa.ID,
+			"reminder_count":     pa.ReminderCount + 1,
+		})
+		sent++
+	}
+
+	if sent == 0 {
+		c.JSON(http.StatusTooManyRequests, errorResponse("REMINDER_RATE_LIMITED", "Reminder already sent within the last 24 hours"))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"reminders_sent": sent,
+		"users":          users,
+	}))
+}
+
+// GeneratePolicyAckEvidence stores the campaign's current acknowledgement roster as evidence for
+// the controls linked to the policy. Completed campaigns do this automatically; this endpoint
+// captures a roster on demand, e.g. at the start of an audit period.
+func GeneratePolicyAckEvidence(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	campaignID := c.Param("id")
+
+	var status string
+	err := database.QueryRow(`SELECT status FROM policy_ack_campaigns WHERE id = $1 AND org_id = $2`,
+		campaignID, orgID).Scan(&status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Acknowledgement campaign not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy acknowledgement campaign")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if status == models.PolicyAckCampaignCancelled {
+		c.JSON(http.StatusConflict, errorResponse("CONFLICT", "Cancelled campaigns cannot produce evidence"))
+		return
+	}
+	if objectStore == nil {
+		c.JSON(http.StatusServiceUnavailable, errorResponse("SERVICE_UNAVAILABLE", "Storage service not available"))
+		return
+	}
+
+	stored, roster, err := services.StorePolicyAckEvidence(c.Request.Context(), database.DB, objectStore, orgID, campaignID, &userID)
+	if err != nil {
+		log.Error().Err(err).Str("campaign_id", campaignID).Msg("Failed to store policy acknowledgement evidence")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to store acknowledgement evidence"))
+		return
+	}
+
+	middleware.LogAudit(c, "policy_ack_campaign.evidence_generated", "policy_ack_campaign", &campaignID, map[string]interface{}{
+		"evidence_artifact_id": stored.ID, "version": stored.Version,
+		"acknowledged": roster.Summary.Acknowledged, "assigned": roster.Summary.Assigned,
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, gin.H{
+		"campaign_id":     campaignID,
+		"evidence":        stored,
+		"summary":         roster.Summary,
+		"linked_controls": roster.ControlIDs,
+	}))
+}
+
+// ListPendingPolicyAcknowledgements lists the policies the current user still has to acknowledge.
+func ListPendingPolicyAcknowledgements(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	var total int
+	database.QueryRow(`
+		SELECT COUNT(*) FROM policy_acknowledgements pa
+		JOIN policy_ack_campaigns pac ON pac.id = pa.campaign_id
+		WHERE pa.user_id = $1 AND pa.org_id = $2 AND pa.status = 'pending' AND pac.status = 'active'
+	`, userID, orgID).Scan(&total)
+
+	offset := (page - 1) * perPage
+	rows, err := database.Query(`
+		SELECT pa.id, pac.id, pac.due_date,
+			p.id, p.identifier, p.title, p.category,
+			pv.id, pv.version_number, pv.change_summary,
+			pa.reminder_count, pa.created_at
+		FROM policy_acknowledgements pa
+		JOIN policy_ack_campaigns pac ON pac.id = pa.campaign_id
+		JOIN policies p ON p.id = pa.policy_id
+		JOIN policy_versions pv ON pv.id = pa.policy_version_id
+		WHERE pa.user_id = $1 AND pa.org_id = $2 AND pa.status = 'pending' AND pac.status = 'active'
+		ORDER BY COALESCE(pac.due_date, '2999-12-31'::date), p.identifier
+		LIMIT $3 OFFSET $4
+	`, userID, orgID, perPage, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list pending policy acknowledgements")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []gin.H{}
+	for rows.Next() {
+		var (
+			id, campaignID                                   string
+			dueDate                                          *time.Time
+			policyID, identifier, title, category, versionID string
+			versionNumber                                    int
+			changeSummary                                    *string
+			reminderCount                                    int
+			createdAt                                        time.Time
+		)
+		if err := rows.Scan(&id, &campaignID, &dueDate,
+			&policyID, &identifier, &title, &category,
+			&versionID, &versionNumber, &changeSummary,
+			&reminderCount, &createdAt); err != nil {
+			continue
+		}
+
+		urgency := "on_time"
+		if dueDate != nil {
+			if services.PolicyAckPastDue(dueDate, time.Now()) {
+				urgency = "overdue"
+			} else if dueDate.Before(time.Now().Add(3 * 24 * time.Hour)) {
+				urgency = "due_soon"
+			}
+		}
+
+		results = append(results, gin.H{
+			"id":       id,
+			"campaign": gin.H{"id": campaignID, "due_date": dueDate},
+			"policy": gin.H{
+				"id":         policyID,
+				"identifier": identifier,
+				"title":      title,
+				"category":   category,
+			},
+			"policy_version": gin.H{
+				"id":             versionID,
+				"version_number": versionNumber,
+				"change_summary": changeSummary,
+			},
+			"urgency":        urgency,
+			"reminder_count": reminderCount,
+			"requested_at":   createdAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, listResponse(c, results, total, page, perPage))
+}
+
+// AcknowledgePolicy records that the current user has read and understood a policy version.
+func AcknowledgePolicy(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	ackID := c.Param("id")
+
+	var (
+		status, ackUserID, campaignID, campaignStatus string
+		policyID, versionID                           string
+		versionNumber                                 int
+		dueDate                                       *time.Time
+	)
+	err := database.QueryRow(`
+		SELECT pa.status, pa.user_id, pac.id, pac.status, pa.policy_id, pa.policy_version_id,
+			pac.version_number, pac.due_date
+		FROM policy_acknowledgements pa
+		JOIN policy_ack_campaigns pac ON pac.id = pa.campaign_id
+		WHERE pa.id = $1 AND pa.org_id = $2
+	`, ackID, orgID).Scan(&status, &ackUserID, &campaignID, &campaignStatus, &policyID, &versionID,
+		&versionNumber, &dueDate)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Acknowledgement not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy acknowledgement")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	// Acknowledgements are personal; nobody can acknowledge on another user's behalf
+	if ackUserID != userID {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Only the assigned user can acknowledge this policy"))
+		return
+	}
+	if status != models.PolicyAckStatusPending || campaignStatus != models.PolicyAckCampaignActive {
+		c.JSON(http.StatusConflict, errorResponse("CONFLICT", "Acknowledgement is not pending"))
+		return
+	}
+
+	now := time.Now()
+
+	tx, err := database.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE policy_acknowledgements
+		SET status = 'acknowledged', statement = $1, acknowledged_at = $2, ip_address = $3, user_agent = $4
+		WHERE id = $5 AND status = 'pending'
+	`, models.PolicyAckStatement, now, c.ClientIP(), c.GetHeader("User-Agent"), ackID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update policy acknowledgement")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusConflict, errorResponse("CONFLICT", "Acknowledgement is not pending"))
+		return
+	}
+
+	// Close the campaign once no acknowledgements remain pending
+	var remaining int
+	tx.QueryRow(`SELECT COUNT(*) FROM policy_acknowledgements WHERE campaign_id = $1 AND status = 'pending'`, campaignID).Scan(&remaining)
+	campaignCompleted := false
+	if remaining == 0 {
+		if _, err := tx.Exec(`UPDATE policy_ack_campaigns SET status = 'completed', completed_at = NOW() WHERE id = $1 AND status = 'active'`, campaignID); err == nil {
+			campaignCompleted = true
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit policy acknowledgement")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "policy.acknowledged", "policy", &policyID, map[string]interface{}{
+		"acknowledgement_id": ackID, "campaign_id": campaignID, "version_number": versionNumber,
+	})
+
+	var evidenceArtifactID *string
+	if campaignCompleted {
+		middleware.LogAudit(c, "policy_ack_campaign.completed", "policy_ack_campaign", &campaignID, nil)
+
+		// The final roster is evidence for the policy's controls. The acknowledgement itself is
+		// already committed, so a storage failure only loses the automatic snapshot.
+		stored, _, err := services.StorePolicyAckEvidence(c.Request.Context(), database.DB, objectStore, orgID, campaignID, nil)
+		if err != nil {
+			log.Error().Err(err).Str("campaign_id", campaignID).Msg("Failed to store policy acknowledgement evidence")
+		} else {
+			evidenceArtifactID = &stored.ID
+		}
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"id":                   ackID,
+		"status":               models.PolicyAckStatusAcknowledged,
+		"policy_id":            policyID,
+		"policy_version":       gin.H{"id": versionID, "version_number": versionNumber},
+		"statement":            models.PolicyAckStatement,
+		"acknowledged_at":      now,
+		"late":                 services.PolicyAckPastDue(dueDate, now),
+		"campaign_completed":   campaignCompleted,
+		"evidence_artifact_id": evidenceArtifactID,
+	}))
+}
+
+// ListUserPolicyAcknowledgements returns a user's acknowledgement history across policies and
+// versions. Users can see their own history; CISO, compliance managers and auditors anyone's.
+func ListUserPolicyAcknowledgements(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	currentUserID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	targetID := c.Param("id")
+
+	if targetID != currentUserID && !models.HasRole(userRole, models.PolicyAckViewRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to view this user's acknowledgements"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	where := []string{"pa.user_id = $1", "pa.org_id = $2"}
+	args := []interface{}{targetID, orgID}
+	argN := 3
+	if v := c.Query("policy_id"); v != "" {
+		where = append(where, fmt.Sprintf("pa.policy_id = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+	if v := c.Query("status"); v != "" {
+		where = append(where, fmt.Sprintf("pa.status = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+	whereClause := joinWhere(where)
+
+	var total int
+	database.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM policy_acknowledgements pa WHERE %s`, whereClause), args...).Scan(&total)
+
+	offset := (page - 1) * perPage
+	rows, err := database.Query(fmt.Sprintf(`
+		SELECT pa.id, pa.campaign_id, pa.status, pa.statement, pa.acknowledged_at, pa.ip_address,
+			pac.due_date, pa.created_at,
+			p.id, p.identifier, p.title, pv.id, pv.version_number
+		FROM policy_acknowledgements pa
+		JOIN policy_ack_campaigns pac ON pac.id = pa.campaign_id
+		JOIN policies p ON p.id = pa.policy_id
+		JOIN policy_versions pv ON pv.id = pa.policy_version_id
+		WHERE %s
+		ORDER BY pa.created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argN, argN+1), append(args, perPage, offset)...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list user policy acknowledgements")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []gin.H{}
+	for rows.Next() {
+		var (
+			id, campaignID, status                 string
+			statement, ipAddress                   *string
+			acknowledgedAt, dueDate                *time.Time
+			createdAt                              time.Time
+			policyID, identifier, title, versionID string
+			versionNumber                          int
+		)
+		if err := rows.Scan(&id, &campaignID, &status, &statement, &acknowledgedAt, &ipAddress,
+			&dueDate, &createdAt,
+			&policyID, &identifier, &title, &versionID, &versionNumber); err != nil {
+			log.Error().Err(err).Msg("Failed to scan policy acknowledgement")
+			continue
+		}
+		results = append(results, gin.H{
+			"id":              id,
+			"campaign_id":     campaignID,
+			"status":          status,
+			"policy":          gin.H{"id": policyID, "identifier": identifier, "title": title},
+			"policy_version":  gin.H{"id": versionID, "version_number": versionNumber},
+			"statement":       statement,
+			"acknowledged_at": acknowledgedAt,
+			"ip_address":      ipAddress,
+			"due_date":        dueDate,
+			"requested_at":    createdAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, listResponse(c, results, total, page, perPage))
+}
+
+// policyAckAudienceJSON describes who a campaign was sent to.
+func policyAckAudienceJSON(allUsers bool, roles, userIDs []string) gin.H {
+	if roles == nil {
+		roles = []string{}
+	}
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+	return gin.H{"all_users": allUsers, "roles": roles, "user_ids": userIDs}
+}
+
+// policyAckCampaignJSON is the response for a newly started campaign.
+func policyAckCampaignJSON(s *policyAckCampaignStart, versionID string, versionNumber int, a *policyAckAudience) gin.H {
+	return gin.H{
+		"id":                       s.ID,
+		"status":                   models.PolicyAckCampaignActive,
+		"policy_version":           gin.H{"id": versionID, "version_number": versionNumber},
+		"audience":                 policyAckAudienceJSON(a.AllUsers, a.Roles, a.UserIDs),
+		"due_date":                 a.DueDate,
+		"reminder_interval_days":   a.ReminderIntervalDays,
+		"acknowledgements_created": s.Assigned,
+		"superseded_pending":       s.Superseded,
+	}
+}
+
+// policyAckProgress summarizes campaign completion.
+func policyAckProgress(total, acknowledged, pending, overdue, late int) gin.H {
+	completionPct := 0.0
+	if total > 0 {
+		completionPct = float64(int(float64(acknowledged)/float64(total)*1000)) / 10
+	}
+	return gin.H{
+		"total":          total,
+		"acknowledged":   acknowledged,
+		"pending":        pending,
+		"overdue":        overdue,
+		"late":           late,
+		"completion_pct": completionPct,
+	}
+}
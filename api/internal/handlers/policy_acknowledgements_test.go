@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPolicyAckRouter(role string) (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-001")
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, role)
+		c.Next()
+	})
+
+	protected.GET("/policies/:id/acknowledgement-campaigns", ListPolicyAckCampaigns)
+	protected.POST("/policies/:id/acknowledgement-campaigns", CreatePolicyAckCampaign)
+	protected.GET("/policy-ack-campaigns/:id", GetPolicyAckCampaign)
+	protected.POST("/policy-ack-campaigns/:id/remind", RemindPolicyAcknowledgements)
+	protected.GET("/policy-acknowledgements/pending", ListPendingPolicyAcknowledgements)
+	protected.POST("/policy-acknowledgements/:id/acknowledge", AcknowledgePolicy)
+	protected.GET("/users/:id/policy-acknowledgements", ListUserPolicyAcknowledgements)
+
+	return router, mock
+}
+
+func TestParsePolicyAckAudience(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	_, msg := parsePolicyAckAudience(&models.PolicyAckAudienceRequest{}, now)
+	assert.NotEmpty(t, msg, "an audience is required")
+
+	_, msg = parsePolicyAckAudience(&models.PolicyAckAudienceRequest{Roles: []string{"intern"}}, now)
+	assert.Contains(t, msg, "Invalid role")
+
+	days := 10
+	date := "2026-04-01"
+	_, msg = parsePolicyAckAudience(&models.PolicyAckAudienceRequest{AllUsers: true, DueInDays: &days, DueDate: &date}, now)
+	assert.NotEmpty(t, msg)
+
+	past := "2026-03-01"
+	_, msg = parsePolicyAckAudience(&models.PolicyAckAudienceRequest{AllUsers: true, DueDate: &past}, now)
+	assert.Equal(t, "due_date cannot be in the past", msg)
+
+	a, msg := parsePolicyAckAudience(&models.PolicyAckAudienceRequest{Roles: []string{models.RoleDevOpsEngineer}}, now)
+	require.Empty(t, msg)
+	assert.Equal(t, "2026-04-09", a.DueDate.Format("2006-01-02"), "defaults to 30 days")
+	assert.Equal(t, []string{}, a.UserIDs)
+
+	a, msg = parsePolicyAckAudience(&models.PolicyAckAudienceRequest{AllUsers: true, DueInDays: &days}, now)
+	require.Empty(t, msg)
+	assert.Equal(t, "2026-03-20", a.DueDate.Format("2006-01-02"))
+}
+
+func TestCreatePolicyAckCampaign_NotPublished(t *testing.T) {
+	router, mock := setupPolicyAckRouter(models.RoleCISO)
+
+	mock.ExpectQuery("SELECT p.status, p.current_version_id, pv.version_number").
+		WithArgs("policy-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "current_version_id", "version_number"}).
+			AddRow("draft", "version-001", 1))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/acknowledgement-campaigns",
+		bytes.NewBufferString(`{"all_users": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "POLICY_NOT_PUBLISHED")
+}
+
+func TestCreatePolicyAckCampaign_UnknownUsers(t *testing.T) {
+	router, mock := setupPolicyAckRouter(models.RoleCISO)
+
+	mock.ExpectQuery("SELECT p.status, p.current_version_id, pv.version_number").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "current_version_id", "version_number"}).
+			AddRow("published", "version-001", 1))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE org_id").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/acknowledgement-campaigns",
+		bytes.NewBufferString(`{"user_ids": ["user-002", "user-other-org"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestCreatePolicyAckCampaign_Success(t *testing.T) {
+	router, mock := setupPolicyAckRouter(models.RoleComplianceManager)
+
+	mock.ExpectQuery("SELECT p.status, p.current_version_id, pv.version_number").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "current_version_id", "version_number"}).
+			AddRow("published", "version-003", 3))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users u").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE policy_acknowledgements SET status = 'superseded'").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE policy_ack_campaigns SET status = 'superseded'").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO policy_ack_campaigns").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_acknowledgements").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/acknowledgement-campaigns",
+		bytes.NewBufferString(`{"roles": ["devops_engineer", "it_admin"], "due_date": "2999-01-31"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(4), data["acknowledgements_created"])
+	assert.Equal(t, float64(3), data["policy_version"].(map[string]interface{})["version_number"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListPolicyAckCampaigns_Progress(t *testing.T) {
+	router, mock := setupPolicyAckRouter(models.RoleAuditor)
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	due := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM policy_ack_campaigns pac").
+		WithArgs("policy-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "policy_version_id", "version_number", "status", "due_date",
+			"all_users", "audience_roles", "audience_user_ids", "reminder_interval_days",
+			"evidence_artifact_id", "completed_at", "cancelled_at", "created_at",
+			"total", "acknowledged", "pending", "overdue", "late",
+		}).
+			AddRow("camp-002", "version-002", 2, "active", due, true, "{}", "{}", 7,
+				nil, nil, nil, time.Now(), 8, 6, 2, 1, 1).
+			AddRow("camp-001", "version-001", 1, "superseded", due, true, "{}", "{}", nil,
+				"ea-001", nil, nil, time.Now(), 5, 4, 0, 0, 0))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policies/policy-001/acknowledgement-campaigns", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	items := resp["data"].([]interface{})
+	require.Len(t, items, 2)
+
+	progress := items[0].(map[string]interface{})["progress"].(map[string]interface{})
+	assert.Equal(t, 75.0, progress["completion_pct"])
+	assert.Equal(t, float64(1), progress["overdue"])
+	assert.Equal(t, 80.0, items[1].(map[string]interface{})["progress"].(map[string]interface{})["completion_pct"])
+}
+
+func TestAcknowledgePolicy_NotAssignee(t *testing.T) {
+	router, mock := setupPolicyAckRouter(models.RoleCISO)
+
+	mock.ExpectQuery("FROM policy_acknowledgements pa").
+		WithArgs("ack-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"status", "user_id", "campaign_id", "campaign_status", "policy_id", "policy_version_id",
+			"version_number", "due_date",
+		}).AddRow("pending", "user-002", "camp-001", "active", "policy-001", "version-001", 1, nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-acknowledgements/ack-001/acknowledge", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code, "even a CISO cannot acknowledge for someone else")
+}
+
+func TestAcknowledgePolicy_Superseded(t *testing.T) {
+	router, mock := setupPolicyAckRouter(models.RoleDevOpsEngineer)
+
+	mock.ExpectQuery("FROM policy_acknowledgements pa").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"status", "user_id", "campaign_id", "campaign_status", "policy_id", "policy_version_id",
+			"version_number", "due_date",
+		}).AddRow("superseded", "user-001", "camp-001", "superseded", "policy-001", "version-001", 1, nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-acknowledgements/ack-001/acknowledge", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAcknowledgePolicy_Success(t *testing.T) {
+	router, mock := setupPolicyAckRouter(models.RoleDevOpsEngineer)
+
+	due := time.Now().AddDate(0, 0, 10)
+	mock.ExpectQuery("FROM policy_acknowledgements pa").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"status", "user_id", "campaign_id", "campaign_status", "policy_id", "policy_version_id",
+			"version_number", "due_date",
+		}).AddRow("pending", "user-001", "camp-001", "active", "policy-001", "version-002", 2, due))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE policy_acknowledgements").
+		WithArgs(models.PolicyAckStatement, sqlmock.AnyArg(), sqlmock.AnyArg(), "test-agent", "ack-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM policy_acknowledgements WHERE campaign_id").
+		WithArgs("camp-001").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-acknowledgements/ack-001/acknowledge", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "acknowledged", data["status"])
+	assert.Equal(t, false, data["campaign_completed"])
+	assert.Equal(t, false, data["late"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemindPolicyAcknowledgements_RateLimited(t *testing.T) {
+	router, mock := setupPolicyAckRouter(models.RoleCISO)
+
+	mock.ExpectQuery("SELECT status FROM policy_ack_campaigns").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("active"))
+	mock.ExpectQuery("FROM policy_acknowledgements pa").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "first_name", "last_name", "reminder_sent_at", "reminder_count"}).
+			AddRow("ack-001", "user-002", "Ana", "Diaz", time.Now().Add(-2*time.Hour), 1))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-ack-campaigns/camp-001/remind", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestListUserPolicyAcknowledgements_OtherUserForbidden(t *testing.T) {
+	router, _ := setupPolicyAckRouter(models.RoleDevOpsEngineer)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/users/user-002/policy-acknowledgements", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestListUserPolicyAcknowledgements_Auditor(t *testing.T) {
+	router, mock := setupPolicyAckRouter(models.RoleAuditor)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM policy_acknowledgements pa").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("FROM policy_acknowledgements pa").
+		WithArgs("user-002", "org-001", "policy-001", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "campaign_id", "status", "statement", "acknowledged_at", "ip_address",
+			"due_date", "created_at", "policy_id", "identifier", "title", "policy_version_id", "version_number",
+		}).AddRow("ack-001", "camp-001", "acknowledged", models.PolicyAckStatement, time.Now(), "10.0.0.1",
+			nil, time.Now(), "policy-001", "POL-AUP-001", "Acceptable Use", "version-001", 1))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/users/user-002/policy-acknowledgements?policy_id=policy-001", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	items := resp["data"].([]interface{})
+	require.Len(t, items, 1)
+	assert.Equal(t, "acknowledged", items[0].(map[string]interface{})["status"])
+}
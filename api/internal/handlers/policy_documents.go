@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// policyDocumentSource is what a rendering needs beyond the document itself.
+type policyDocumentSource struct {
+	PolicyVersionID     string
+	ReviewFrequencyDays *int
+	ControlIDs          []string
+}
+
+// loadPolicyDocument gathers a policy version with its org branding, version history and
+// sign-offs. versionNumber 0 selects the current version. Returns sql.ErrNoRows when the policy
+// or version does not exist.
+func loadPolicyDocument(orgID, policyID string, versionNumber int) (*services.PolicyDocument, *policyDocumentSource, error) {
+	doc := &services.PolicyDocument{GeneratedAt: time.Now().UTC()}
+	src := &policyDocumentSource{ControlIDs: []string{}}
+	var branding string
+	err := database.QueryRow(`
+		SELECT o.name, COALESCE(o.settings->'branding', '{}'::jsonb)::text,
+			p.identifier, p.title, COALESCE(p.description, ''), p.category,
+			COALESCE(u.first_name || ' ' || u.last_name, ''), p.next_review_at, p.review_frequency_days,
+			pv.id, pv.version_number, pv.content, pv.content_format, pv.published_at
+		FROM policies p
+		JOIN organizations o ON o.id = p.org_id
+		LEFT JOIN users u ON u.id = p.owner_id
+		JOIN policy_versions pv ON pv.policy_id = p.id
+			AND (($3 = 0 AND pv.id = p.current_version_id) OR pv.version_number = $3)
+		WHERE p.id = $1 AND p.org_id = $2
+	`, policyID, orgID, versionNumber).Scan(&doc.OrgName, &branding,
+		&doc.PolicyIdentifier, &doc.Title, &doc.Description, &doc.Category,
+		&doc.OwnerName, &doc.NextReviewAt, &src.ReviewFrequencyDays,
+		&src.PolicyVersionID, &doc.VersionNumber, &doc.Content, &doc.ContentFormat, &doc.EffectiveDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal([]byte(branding), &doc.Branding); err != nil {
+		log.Warn().Err(err).Str("org_id", orgID).Msg("Ignoring invalid branding settings")
+	}
+	doc.BodyHTML = template.HTML(sanitizeHTML(services.PolicyContentHTML(doc.Content, doc.ContentFormat)))
+
+	rows, err := database.Query(`
+		SELECT pv.version_number, pv.change_type, COALESCE(pv.change_summary, ''),
+			COALESCE(u.first_name || ' ' || u.last_name, ''), pv.created_at, pv.published_at
+		FROM policy_versions pv
+		LEFT JOIN users u ON u.id = pv.created_by
+		WHERE pv.policy_id = $1 AND pv.version_number <= $2
+		ORDER BY pv.version_number
+	`, policyID, doc.VersionNumber)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load versions: %w", err)
+	}
+	for rows.Next() {
+		var v services.PolicyDocumentVersion
+		if err := rows.Scan(&v.VersionNumber, &v.ChangeType, &v.ChangeSummary, &v.Author, &v.CreatedAt, &v.PublishedAt); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("scan version: %w", err)
+		}
+		doc.Versions = append(doc.Versions, v)
+	}
+	rows.Close()
+
+	rows, err = database.Query(`
+		SELECT u.first_name || ' ' || u.last_name, COALESCE(ps.signer_role::text, ''), ps.status,
+			ps.decided_at, COALESCE(ps.comments, '')
+		FROM policy_signoffs ps
+		JOIN users u ON u.id = ps.signer_id
+		WHERE ps.policy_version_id = $1 AND ps.status <> 'withdrawn'
+		ORDER BY ps.decided_at NULLS LAST, u.last_name
+	`, src.PolicyVersionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load signoffs: %w", err)
+	}
+	for rows.Next() {
+		var s services.PolicyDocumentSignoff
+		if err := rows.Scan(&s.Name, &s.Role, &s.Status, &s.DecidedAt, &s.Comments); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("scan signoff: %w", err)
+		}
+		doc.Signoffs = append(doc.Signoffs, s)
+	}
+	rows.Close()
+
+	rows, err = database.Query(`SELECT control_id FROM policy_controls WHERE policy_id = $1 AND org_id = $2`, policyID, orgID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load policy controls: %w", err)
+	}
+	for rows.Next() {
+		var id string
+		if rows.Scan(&id) == nil {
+			src.ControlIDs = append(src.ControlIDs, id)
+		}
+	}
+	rows.Close()
+
+	return doc, src, nil
+}
+
+// renderPolicyDocument renders doc in the given format, returning the file and its MIME type.
+func renderPolicyDocument(doc *services.PolicyDocument, format string) ([]byte, string, error) {
+	if format == models.PolicyDocumentHTML {
+		data, err := services.RenderPolicyHTML(doc)
+		return data, "text/html; charset=utf-8", err
+	}
+	return services.RenderPolicyPDF(doc), "application/pdf", nil
+}
+
+func policyDocumentFileName(doc *services.PolicyDocument, format string) string {
+	return fmt.Sprintf("%s-v%d.%s", doc.PolicyIdentifier, doc.VersionNumber, format)
+}
+
+// storePolicyDocuments renders a policy version in each format and stores each rendition as the
+// next version of that format's policy_document evidence chain, linked to the policy's controls.
+func storePolicyDocuments(ctx context.Context, orgID, policyID string, versionNumber int, formats []string, renderedBy *string) ([]gin.H, error) {
+	doc, src, err := loadPolicyDocument(orgID, policyID, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+	freshDays := models.PolicyDocumentFreshnessDays
+	if src.ReviewFrequencyDays != nil {
+		freshDays = *src.ReviewFrequencyDays
+	}
+	sourceSystem := "policy_rendering"
+	effective := "not yet published"
+	if doc.EffectiveDate != nil {
+		effective = "effective " + doc.EffectiveDate.Format("2006-01-02")
+	}
+	description := fmt.Sprintf("%s %s version %d, %s", doc.PolicyIdentifier, doc.Title, doc.VersionNumber, effective)
+
+	results := []gin.H{}
+	for _, format := range formats {
+		data, mimeType, err := renderPolicyDocument(doc, format)
+		if err != nil {
+			return results, fmt.Errorf("render %s: %w", format, err)
+		}
+
+		var chain string
+		err = database.QueryRow(`
+			SELECT evidence_artifact_id FROM policy_documents
+			WHERE policy_id = $1 AND format = $2
+			ORDER BY created_at DESC LIMIT 1
+		`, policyID, format).Scan(&chain)
+		if err != nil && err != sql.ErrNoRows {
+			return results, fmt.Errorf("load %s chain: %w", format, err)
+		}
+
+		stored, err := services.StoreEvidenceVersion(ctx, database.DB, objectStore, services.EvidenceVersionSpec{
+			OrgID:               orgID,
+			CurrentArtifactID:   chain,
+			Title:               fmt.Sprintf("Policy document: %s %s v%d", doc.PolicyIdentifier, doc.Title, doc.VersionNumber),
+			Description:         &description,
+			EvidenceType:        "policy_document",
+			CollectionMethod:    "system_export",
+			SourceSystem:        &sourceSystem,
+			FreshnessPeriodDays: &freshDays,
+			Tags:                []string{"policy-document", format},
+			Metadata: map[string]interface{}{
+				"policy_id":         policyID,
+				"policy_version_id": src.PolicyVersionID,
+				"version_number":    doc.VersionNumber,
+				"format":            format,
+			},
+			UploadedBy:     renderedBy,
+			ControlIDs:     src.ControlIDs,
+			CollectionDate: doc.GeneratedAt,
+			FileName:       policyDocumentFileName(doc, format),
+			MIMEType:       mimeType,
+			Data:           data,
+		})
+		if err != nil {
+			return results, fmt.Errorf("store %s: %w", format, err)
+		}
+
+		var id string
+		if err := database.QueryRow(`
+			INSERT INTO policy_documents (org_id, policy_id, policy_version_id, version_number, format,
+				evidence_artifact_id, rendered_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`, orgID, policyID, src.PolicyVersionID, doc.VersionNumber, format, stored.ID, renderedBy).Scan(&id); err != nil {
+			return results, fmt.Errorf("record %s document: %w", format, err)
+		}
+
+		results = append(results, gin.H{
+			"id":                   id,
+			"format":               format,
+			"version_number":       doc.VersionNumber,
+			"evidence_artifact_id": stored.ID,
+			"evidence_version":     stored.Version,
+			"file_name":            policyDocumentFileName(doc, format),
+			"file_size":            stored.FileSize,
+			"checksum_sha256":      stored.ChecksumSHA256,
+			"linked_controls":      len(src.ControlIDs),
+		})
+	}
+	return results, nil
+}
+
+// RenderPolicyVersion downloads a policy version rendered as PDF (default) or HTML without
+// storing it.
+func RenderPolicyVersion(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	policyID := c.Param("id")
+
+	versionNum, err := strconv.Atoi(c.Param("version_number"))
+	if err != nil || versionNum < 1 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid version number"))
+		return
+	}
+	format := c.DefaultQuery("format", models.PolicyDocumentPDF)
+	if !models.IsValidPolicyDocumentFormat(format) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "format must be pdf or html"))
+		return
+	}
+
+	doc, _, err := loadPolicyDocument(orgID, policyID, versionNum)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy version not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load policy for rendering")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	data, mimeType, err := renderPolicyDocument(doc, format)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to render policy")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to render policy"))
+		return
+	}
+
+	disposition := "attachment"
+	if c.Query("inline") == "true" {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`%s; filename="%s"`, disposition, policyDocumentFileName(doc, format)))
+	c.Data(http.StatusOK, mimeType, data)
+}
+
+// CreatePolicyDocuments renders a policy version and stores the files as policy_document
+// evidence linked to the policy's controls.
+func CreatePolicyDocuments(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	policyID := c.Param("id")
+
+	var req models.RenderPolicyDocumentsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+			return
+		}
+	}
+	formats := req.Formats
+	if len(formats) == 0 {
+		formats = models.ValidPolicyDocumentFormats
+	}
+	seen := map[string]bool{}
+	for _, f := range formats {
+		if !models.IsValidPolicyDocumentFormat(f) || seen[f] {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "formats must be distinct values of pdf, html"))
+			return
+		}
+		seen[f] = true
+	}
+	versionNum := 0
+	if req.VersionNumber != nil {
+		if *req.VersionNumber < 1 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid version number"))
+			return
+		}
+		versionNum = *req.VersionNumber
+	}
+
+	var ownerID, secondaryOwnerID *string
+	err := database.QueryRow("SELECT owner_id, secondary_owner_id FROM policies WHERE id = $1 AND org_id = $2",
+		policyID, orgID).Scan(&ownerID, &secondaryOwnerID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	// Auth: owner, compliance_manager, ciso, security_engineer
+	isOwner := (ownerID != nil && *ownerID == userID) || (secondaryOwnerID != nil && *secondaryOwnerID == userID)
+	if !isOwner && !models.HasRole(userRole, models.PolicyCreateRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to render this policy"))
+		return
+	}
+
+	if objectStore == nil {
+		c.JSON(http.StatusServiceUnavailable, errorResponse("SERVICE_UNAVAILABLE", "Storage service not available"))
+		return
+	}
+
+	docs, err := storePolicyDocuments(c.Request.Context(), orgID, policyID, versionNum, formats, &userID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy version not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("policy_id", policyID).Msg("Failed to store policy documents")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to store policy documents"))
+		return
+	}
+
+	middleware.LogAudit(c, "policy.document_rendered", "policy", &policyID, map[string]interface{}{
+		"version_number": docs[0]["version_number"], "formats": formats,
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, docs))
+}
+
+// ListPolicyDocuments lists stored renditions of a policy, newest first.
+func ListPolicyDocuments(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	policyID := c.Param("id")
+
+	var exists bool
+	database.QueryRow(`SELECT EXISTS(SELECT 1 FROM policies WHERE id = $1 AND org_id = $2)`, policyID, orgID).Scan(&exists)
+	if !exists {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy not found"))
+		return
+	}
+
+	rows, err := database.Query(`
+		SELECT pd.id, pd.policy_version_id, pd.version_number, pd.format, pd.evidence_artifact_id,
+			ea.file_name, ea.file_size, ea.version, ea.is_current, pd.rendered_by, pd.created_at
+		FROM policy_documents pd
+		JOIN evidence_artifacts ea ON ea.id = pd.evidence_artifact_id
+		WHERE pd.policy_id = $1 AND pd.org_id = $2
+		ORDER BY pd.created_at DESC
+	`, policyID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policy documents")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	docs := []models.PolicyDocument{}
+	for rows.Next() {
+		var d models.PolicyDocument
+		if err := rows.Scan(&d.ID, &d.PolicyVersionID, &d.VersionNumber, &d.Format, &d.EvidenceArtifactID,
+			&d.FileName, &d.FileSize, &d.EvidenceVersion, &d.IsCurrent, &d.RenderedBy, &d.CreatedAt); err != nil {
+			log.Error().Err(err).Msg("Failed to scan policy document")
+			continue
+		}
+		docs = append(docs, d)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, docs))
+}
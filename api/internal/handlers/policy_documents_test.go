@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPolicyDocumentRouter(role string) (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-001")
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, role)
+		c.Next()
+	})
+
+	protected.GET("/policies/:id/versions/:version_number/render", RenderPolicyVersion)
+	protected.GET("/policies/:id/documents", ListPolicyDocuments)
+	protected.POST("/policies/:id/documents", CreatePolicyDocuments)
+
+	return router, mock
+}
+
+// expectPolicyDocumentLoad mocks loadPolicyDocument for version 2 of policy-001.
+func expectPolicyDocumentLoad(mock sqlmock.Sqlmock, content string) {
+	published := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM policies p\\s+JOIN organizations o").
+		WithArgs("policy-001", "org-001", 2).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"name", "branding", "identifier", "title", "description", "category", "owner", "next_review_at",
+			"review_frequency_days", "id", "version_number", "content", "content_format", "published_at",
+		}).AddRow("Acme Corp", `{"display_name": "Acme", "primary_color": "#0A7E3C"}`, "POL-AC-001", "Access Control Policy",
+			"", "access_control", "Dana Smith", nil, 365, "version-002", 2, content, "html", published))
+	mock.ExpectQuery("FROM policy_versions pv").
+		WithArgs("policy-001", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"version_number", "change_type", "change_summary", "author", "created_at", "published_at"}).
+			AddRow(1, "initial", "Initial version", "Dana Smith", published.AddDate(-1, 0, 0), published.AddDate(-1, 0, 0)).
+			AddRow(2, "minor", "Added leaver SLA", "Dana Smith", published.AddDate(0, 0, -3), published))
+	mock.ExpectQuery("FROM policy_signoffs ps").
+		WithArgs("version-002").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "role", "status", "decided_at", "comments"}).
+			AddRow("Sam Lee", "ciso", "approved", published, ""))
+	mock.ExpectQuery("SELECT control_id FROM policy_controls").
+		WillReturnRows(sqlmock.NewRows([]string{"control_id"}).AddRow("ctrl-001"))
+}
+
+func TestRenderPolicyVersion_HTML(t *testing.T) {
+	router, mock := setupPolicyDocumentRouter(models.RoleAuditor)
+	expectPolicyDocumentLoad(mock, `<h2>Scope</h2><p>All staff.</p><script>alert(1)</script>`)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policies/policy-001/versions/2/render?format=html", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="POL-AC-001-v2.html"`)
+	body := w.Body.String()
+	assert.Contains(t, body, "<h2>Scope</h2>")
+	assert.NotContains(t, body, "alert(1)", "content is sanitized")
+	assert.Contains(t, body, "#0A7E3C")
+	assert.Contains(t, body, "Added leaver SLA")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRenderPolicyVersion_PDF(t *testing.T) {
+	router, mock := setupPolicyDocumentRouter(models.RoleAuditor)
+	expectPolicyDocumentLoad(mock, `<p>All staff.</p>`)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policies/policy-001/versions/2/render", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF-")))
+}
+
+func TestRenderedPolicyDocuments_PassProcessing(t *testing.T) {
+	store := &memoryObjectStore{objects: map[string][]byte{}, types: map[string]string{}}
+	doc := &services.PolicyDocument{
+		OrgName:          "Acme Corp",
+		PolicyIdentifier: "POL-AC-001",
+		Title:            "Access Control Policy",
+		VersionNumber:    2,
+		Content:          "<p>All staff.</p>",
+		ContentFormat:    "html",
+		BodyHTML:         template.HTML("<p>All staff.</p>"),
+	}
+
+	for _, format := range []string{models.PolicyDocumentHTML, models.PolicyDocumentPDF} {
+		data, mimeType, err := renderPolicyDocument(doc, format)
+		require.NoError(t, err)
+		key := policyDocumentFileName(doc, format)
+		require.NoError(t, store.PutObject(context.Background(), key, mimeType, data))
+
+		out, err := services.InspectEvidenceObject(context.Background(), store, nil, key, mimeType)
+		require.NoError(t, err)
+		assert.Equal(t, models.ProcessingNotScanned, out.Status, format)
+	}
+	assert.Empty(t, store.quarantined)
+}
+
+func TestRenderPolicyVersion_NotFound(t *testing.T) {
+	router, mock := setupPolicyDocumentRouter(models.RoleAuditor)
+	mock.ExpectQuery("FROM policies p\\s+JOIN organizations o").WillReturnError(sql.ErrNoRows)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policies/policy-001/versions/9/render", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRenderPolicyVersion_InvalidFormat(t *testing.T) {
+	router, _ := setupPolicyDocumentRouter(models.RoleAuditor)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policies/policy-001/versions/2/render?format=docx", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreatePolicyDocuments_Forbidden(t *testing.T) {
+	router, mock := setupPolicyDocumentRouter(models.RoleDevOpsEngineer)
+	mock.ExpectQuery("SELECT owner_id, secondary_owner_id FROM policies").
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id", "secondary_owner_id"}).AddRow("user-002", nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/documents", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCreatePolicyDocuments_NoStorage(t *testing.T) {
+	router, mock := setupPolicyDocumentRouter(models.RoleComplianceManager)
+	mock.ExpectQuery("SELECT owner_id, secondary_owner_id FROM policies").
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id", "secondary_owner_id"}).AddRow("user-002", nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/documents",
+		strings.NewReader(`{"formats": ["pdf"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestCreatePolicyDocuments_InvalidFormats(t *testing.T) {
+	router, _ := setupPolicyDocumentRouter(models.RoleComplianceManager)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/documents",
+		strings.NewReader(`{"formats": ["pdf", "pdf"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListPolicyDocuments(t *testing.T) {
+	router, mock := setupPolicyDocumentRouter(models.RoleAuditor)
+	mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("FROM policy_documents pd").
+		WithArgs("policy-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "policy_version_id", "version_number", "format", "evidence_artifact_id",
+			"file_name", "file_size", "version", "is_current", "rendered_by", "created_at",
+		}).AddRow("doc-001", "version-002", 2, "pdf", "ev-001", "POL-AC-001-v2.pdf", 18234, 2, true, "user-001", time.Now()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policies/policy-001/documents", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"evidence_artifact_id":"ev-001"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
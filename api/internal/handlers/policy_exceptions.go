@@ -0,0 +1,634 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const policyExceptionColumns = `
+	e.id, e.policy_id, p.identifier, p.title, e.title, e.justification, e.affected_assets,
+	e.affected_user_ids, e.status, e.requested_by, e.approver_id, e.expires_at, e.decided_by,
+	e.decided_at, e.decision_comments, e.revoked_by, e.revoked_at, e.revocation_reason,
+	e.expired_at, e.created_at, e.updated_at`
+
+func scanPolicyException(row rowScanner) (*models.PolicyException, error) {
+	var e models.PolicyException
+	var assets, users pq.StringArray
+	if err := row.Scan(&e.ID, &e.PolicyID, &e.PolicyIdentifier, &e.PolicyTitle, &e.Title, &e.Justification,
+		&assets, &users, &e.Status, &e.RequestedBy, &e.ApproverID, &e.ExpiresAt, &e.DecidedBy,
+		&e.DecidedAt, &e.DecisionComments, &e.RevokedBy, &e.RevokedAt, &e.RevocationReason,
+		&e.ExpiredAt, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	e.AffectedAssets = []string(assets)
+	e.AffectedUserIDs = []string(users)
+	e.CompensatingControls = []models.PolicyExceptionControl{}
+	return &e, nil
+}
+
+// loadPolicyExceptionControls fills in the compensating controls of exceptions.
+func loadPolicyExceptionControls(exceptions []*models.PolicyException) error {
+	if len(exceptions) == 0 {
+		return nil
+	}
+	ids := make([]string, len(exceptions))
+	byID := map[string]*models.PolicyException{}
+	for i, e := range exceptions {
+		ids[i] = e.ID
+		byID[e.ID] = e
+	}
+	rows, err := database.Query(`
+		SELECT ec.exception_id, c.id, c.identifier, c.title
+		FROM policy_exception_controls ec
+		JOIN controls c ON c.id = ec.control_id
+		WHERE ec.exception_id = ANY($1)
+		ORDER BY c.identifier
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var exceptionID string
+		var ctl models.PolicyExceptionControl
+		if err := rows.Scan(&exceptionID, &ctl.ControlID, &ctl.Identifier, &ctl.Title); err != nil {
+			return err
+		}
+		if e := byID[exceptionID]; e != nil {
+			e.CompensatingControls = append(e.CompensatingControls, ctl)
+		}
+	}
+	return rows.Err()
+}
+
+func getPolicyException(exceptionID, orgID string) (*models.PolicyException, error) {
+	e, err := scanPolicyException(database.QueryRow(fmt.Sprintf(`
+		SELECT %s
+		FROM policy_exceptions e
+		JOIN policies p ON p.id = e.policy_id
+		WHERE e.id = $1 AND e.org_id = $2
+	`, policyExceptionColumns), exceptionID, orgID))
+	if err != nil {
+		return nil, err
+	}
+	if err := loadPolicyExceptionControls([]*models.PolicyException{e}); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ListPolicyExceptions lists the org's exception register. Filters: status (or active for
+// approved exceptions), policy_id, requested_by=me and expiring_within_days.
+func ListPolicyExceptions(c *gin.Context) {
+	listPolicyExceptions(c, c.Query("policy_id"))
+}
+
+// ListExceptionsForPolicy lists the exceptions to one policy.
+func ListExceptionsForPolicy(c *gin.Context) {
+	listPolicyExceptions(c, c.Param("id"))
+}
+
+func listPolicyExceptions(c *gin.Context, policyID string) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	where := []string{"e.org_id = $1"}
+	args := []interface{}{orgID}
+	argN := 2
+
+	if status := c.Query("status"); status != "" {
+		if status == "active" {
+			status = models.PolicyExceptionApproved
+		}
+		if !models.IsValidPolicyExceptionStatus(status) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid status"))
+			return
+		}
+		where = append(where, fmt.Sprintf("e.status = $%d", argN))
+		args = append(args, status)
+		argN++
+	}
+	if policyID != "" {
+		where = append(where, fmt.Sprintf("e.policy_id = $%d", argN))
+		args = append(args, policyID)
+		argN++
+	}
+	if c.Query("requested_by") == "me" {
+		where = append(where, fmt.Sprintf("e.requested_by = $%d", argN))
+		args = append(args, userID)
+		argN++
+	}
+	if v := c.Query("expiring_within_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 0 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "expiring_within_days must be a non-negative integer"))
+			return
+		}
+		where = append(where, "e.status = 'approved'", fmt.Sprintf("e.expires_at <= CURRENT_DATE + $%d::int", argN))
+		args = append(args, days)
+		argN++
+	}
+
+	whereClause := joinWhere(where)
+
+	var total int
+	database.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM policy_exceptions e WHERE %s`, whereClause), args...).Scan(&total)
+
+	offset := (page - 1) * perPage
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM policy_exceptions e
+		JOIN policies p ON p.id = e.policy_id
+		WHERE %s
+		ORDER BY e.expires_at, p.identifier
+		LIMIT $%d OFFSET $%d
+	`, policyExceptionColumns, whereClause, argN, argN+1)
+	args = append(args, perPage, offset)
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policy exceptions")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	exceptions := []*models.PolicyException{}
+	for rows.Next() {
+		e, err := scanPolicyException(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to scan policy exception")
+			continue
+		}
+		exceptions = append(exceptions, e)
+	}
+	rows.Close()
+
+	if err := loadPolicyExceptionControls(exceptions); err != nil {
+		log.Error().Err(err).Msg("Failed to load compensating controls")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	results := make([]models.PolicyException, len(exceptions))
+	for i, e := range exceptions {
+		results[i] = *e
+	}
+	c.JSON(http.StatusOK, listResponse(c, results, total, page, perPage))
+}
+
+// GetPolicyException returns one exception with its compensating controls.
+func GetPolicyException(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	e, err := getPolicyException(c.Param("id"), orgID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy exception not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy exception")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, e))
+}
+
+// parseExceptionExpiry parses an expiry date, which must fall within PolicyExceptionMaxDays.
+func parseExceptionExpiry(s string) (time.Time, error) {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expires_at must be a date (YYYY-MM-DD)")
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if !d.After(today) {
+		return time.Time{}, fmt.Errorf("expires_at must be in the future")
+	}
+	if d.After(today.AddDate(0, 0, models.PolicyExceptionMaxDays)) {
+		return time.Time{}, fmt.Errorf("exceptions may run for at most %d days", models.PolicyExceptionMaxDays)
+	}
+	return d, nil
+}
+
+// validateExceptionReferences checks that the affected users, compensating controls and
+// approver belong to the org. Returns a message describing the first problem.
+func validateExceptionReferences(orgID, requesterID string, userIDs, controlIDs []string, approverID *string) string {
+	if n := len(dedupeStrings(userIDs)); n > 0 {
+		var found int
+		database.QueryRow(`SELECT COUNT(*) FROM users WHERE org_id = $1 AND id = ANY($2) AND status = 'active'`,
+			orgID, pq.Array(dedupeStrings(userIDs))).Scan(&found)
+		if found != n {
+			return "Affected users must be active users in this organization"
+		}
+	}
+	if n := len(dedupeStrings(controlIDs)); n > 0 {
+		var found int
+		database.QueryRow(`SELECT COUNT(*) FROM controls WHERE org_id = $1 AND id = ANY($2)`,
+			orgID, pq.Array(dedupeStrings(controlIDs))).Scan(&found)
+		if found != n {
+			return "Compensating controls not found"
+		}
+	}
+	if approverID != nil {
+		if *approverID == requesterID {
+			return "The requester cannot approve their own exception"
+		}
+		var exists bool
+		database.QueryRow(`SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND org_id = $2 AND status = 'active')`,
+			*approverID, orgID).Scan(&exists)
+		if !exists {
+			return "Approver not found or not active in this org"
+		}
+	}
+	return ""
+}
+
+func dedupeStrings(in []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, s := range in {
+		s = strings.TrimSpace(s)
+		if s != "" && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func replaceExceptionControls(tx *sql.Tx, orgID, exceptionID string, controlIDs []string) error {
+	if _, err := tx.Exec(`DELETE FROM policy_exception_controls WHERE exception_id = $1`, exceptionID); err != nil {
+		return err
+	}
+	for _, id := range controlIDs {
+		if _, err := tx.Exec(`
+			INSERT INTO policy_exception_controls (exception_id, control_id, org_id) VALUES ($1, $2, $3)
+		`, exceptionID, id, orgID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreatePolicyException requests an exception to a published policy on behalf of the caller.
+func CreatePolicyException(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	policyID := c.Param("id")
+
+	var req models.CreatePolicyExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	req.Justification = strings.TrimSpace(req.Justification)
+	if req.Title == "" || len(req.Title) > 255 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Title must be 1-255 characters"))
+		return
+	}
+	if req.Justification == "" {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Justification is required"))
+		return
+	}
+	assets := dedupeStrings(req.AffectedAssets)
+	users := dedupeStrings(req.AffectedUserIDs)
+	controls := dedupeStrings(req.CompensatingControls)
+	if len(assets) == 0 && len(users) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "At least one affected asset or user is required"))
+		return
+	}
+	expiresAt, err := parseExceptionExpiry(req.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	var policyStatus string
+	err = database.QueryRow(`SELECT status FROM policies WHERE id = $1 AND org_id = $2 AND is_template = FALSE`,
+		policyID, orgID).Scan(&policyStatus)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if policyStatus != models.PolicyStatusPublished {
+		c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", "Exceptions can only be requested against a published policy"))
+		return
+	}
+
+	if msg := validateExceptionReferences(orgID, userID, users, controls, req.ApproverID); msg != "" {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", msg))
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer tx.Rollback()
+
+	var exceptionID string
+	err = tx.QueryRow(`
+		INSERT INTO policy_exceptions (org_id, policy_id, title, justification, affected_assets,
+			affected_user_ids, requested_by, approver_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, orgID, policyID, req.Title, req.Justification, pq.Array(assets), pq.Array(users),
+		userID, req.ApproverID, expiresAt).Scan(&exceptionID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to insert policy exception")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if err := replaceExceptionControls(tx, orgID, exceptionID, controls); err != nil {
+		log.Error().Err(err).Msg("Failed to link compensating controls")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit policy exception")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "policy_exception.requested", "policy_exception", &exceptionID, map[string]interface{}{
+		"policy_id": policyID, "expires_at": req.ExpiresAt, "compensating_controls": controls,
+	})
+
+	e, err := getPolicyException(exceptionID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reload policy exception")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	c.JSON(http.StatusCreated, successResponse(c, e))
+}
+
+// UpdatePolicyException changes an exception that has not been decided yet. Only the requester
+// or a policy manager may edit it.
+func UpdatePolicyException(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	exceptionID := c.Param("id")
+
+	var req models.UpdatePolicyExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body"))
+		return
+	}
+
+	e, err := getPolicyException(exceptionID, orgID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy exception not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy exception")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if e.RequestedBy != userID && !models.HasRole(userRole, models.PolicyCreateRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to edit this exception"))
+		return
+	}
+	if e.Status != models.PolicyExceptionRequested {
+		c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", "Only exceptions awaiting a decision can be changed"))
+		return
+	}
+
+	sets := []string{}
+	args := []interface{}{}
+	argN := 1
+	changed := []string{}
+	add := func(field, expr string, v interface{}) {
+		sets = append(sets, fmt.Sprintf(expr, argN))
+		args = append(args, v)
+		argN++
+		changed = append(changed, field)
+	}
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" || len(title) > 255 {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Title must be 1-255 characters"))
+			return
+		}
+		add("title", "title = $%d", title)
+	}
+	if req.Justification != nil {
+		justification := strings.TrimSpace(*req.Justification)
+		if justification == "" {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Justification is required"))
+			return
+		}
+		add("justification", "justification = $%d", justification)
+	}
+	assets, users := e.AffectedAssets, e.AffectedUserIDs
+	if req.AffectedAssets != nil {
+		assets = dedupeStrings(req.AffectedAssets)
+		add("affected_assets", "affected_assets = $%d", pq.Array(assets))
+	}
+	if req.AffectedUserIDs != nil {
+		users = dedupeStrings(req.AffectedUserIDs)
+		add("affected_user_ids", "affected_user_ids = $%d", pq.Array(users))
+	}
+	if len(assets) == 0 && len(users) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "At least one affected asset or user is required"))
+		return
+	}
+	if req.ExpiresAt != nil {
+		expiresAt, err := parseExceptionExpiry(*req.ExpiresAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", err.Error()))
+			return
+		}
+		add("expires_at", "expires_at = $%d", expiresAt)
+	}
+	if req.ApproverID != nil {
+		add("approver_id", "approver_id = $%d", *req.ApproverID)
+	}
+	var controls []string
+	if req.CompensatingControls != nil {
+		controls = dedupeStrings(req.CompensatingControls)
+		changed = append(changed, "compensating_controls")
+	}
+	if len(changed) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "No fields to update"))
+		return
+	}
+
+	var newUsers []string
+	if req.AffectedUserIDs != nil {
+		newUsers = users
+	}
+	if msg := validateExceptionReferences(orgID, e.RequestedBy, newUsers, controls, req.ApproverID); msg != "" {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", msg))
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer tx.Rollback()
+
+	if len(sets) > 0 {
+		args = append(args, exceptionID)
+		if _, err := tx.Exec(fmt.Sprintf(`UPDATE policy_exceptions SET %s WHERE id = $%d AND status = 'requested'`,
+			joinStrings(sets), argN), args...); err != nil {
+			log.Error().Err(err).Msg("Failed to update policy exception")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+			return
+		}
+	}
+	if req.CompensatingControls != nil {
+		if err := replaceExceptionControls(tx, orgID, exceptionID, controls); err != nil {
+			log.Error().Err(err).Msg("Failed to link compensating controls")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit policy exception")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "policy_exception.updated", "policy_exception", &exceptionID, map[string]interface{}{
+		"changed": changed, "policy_id": e.PolicyID,
+	})
+
+	e, err = getPolicyException(exceptionID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reload policy exception")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, e))
+}
+
+// canDecidePolicyException reports whether the caller may approve, reject or revoke e: its
+// designated approver, or a CISO or compliance manager when none is designated. Requesters
+// never decide their own exceptions.
+func canDecidePolicyException(e *models.PolicyException, userID, userRole string) bool {
+	if e.RequestedBy == userID {
+		return false
+	}
+	if e.ApproverID != nil {
+		return *e.ApproverID == userID || userRole == models.RoleCISO
+	}
+	return models.HasRole(userRole, models.PolicyPublishRoles)
+}
+
+// decidePolicyException moves an exception from one status to another for the approve, reject
+// and revoke endpoints.
+func decidePolicyException(c *gin.Context, from, to, action string, requireComments bool) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	exceptionID := c.Param("id")
+
+	var req models.PolicyExceptionDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+			return
+		}
+	}
+	if requireComments && (req.Comments == nil || strings.TrimSpace(*req.Comments) == "") {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Comments are required"))
+		return
+	}
+
+	e, err := getPolicyException(exceptionID, orgID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy exception not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy exception")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if !canDecidePolicyException(e, userID, userRole) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to decide this exception"))
+		return
+	}
+	if e.Status != from {
+		c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", fmt.Sprintf("Exception is %s, not %s", e.Status, from)))
+		return
+	}
+
+	var query string
+	if to == models.PolicyExceptionRevoked {
+		query = `UPDATE policy_exceptions SET status = $1, revoked_by = $2, revoked_at = NOW(), revocation_reason = $3
+			WHERE id = $4 AND status = $5`
+	} else {
+		query = `UPDATE policy_exceptions SET status = $1, decided_by = $2, decided_at = NOW(), decision_comments = $3
+			WHERE id = $4 AND status = $5`
+	}
+	res, err := database.Exec(query, to, userID, req.Comments, exceptionID, from)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update policy exception")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", "Exception was changed by someone else"))
+		return
+	}
+
+	middleware.LogAudit(c, action, "policy_exception", &exceptionID, map[string]interface{}{
+		"policy_id": e.PolicyID, "expires_at": e.ExpiresAt.Format("2006-01-02"),
+	})
+
+	e, err = getPolicyException(exceptionID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reload policy exception")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, e))
+}
+
+// ApprovePolicyException approves a requested exception.
+func ApprovePolicyException(c *gin.Context) {
+	decidePolicyException(c, models.PolicyExceptionRequested, models.PolicyExceptionApproved, "policy_exception.approved", false)
+}
+
+// RejectPolicyException rejects a requested exception; comments are required.
+func RejectPolicyException(c *gin.Context) {
+	decidePolicyException(c, models.PolicyExceptionRequested, models.PolicyExceptionRejected, "policy_exception.rejected", true)
+}
+
+// RevokePolicyException ends an approved exception before it expires; a reason is required.
+func RevokePolicyException(c *gin.Context) {
+	decidePolicyException(c, models.PolicyExceptionApproved, models.PolicyExceptionRevoked, "policy_exception.revoked", true)
+}
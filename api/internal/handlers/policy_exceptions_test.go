@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPolicyExceptionRouter(role string) (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-001")
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, role)
+		c.Next()
+	})
+
+	protected.GET("/policies/stats", GetPolicyStats)
+	protected.GET("/policies/:id/exceptions", ListExceptionsForPolicy)
+	protected.POST("/policies/:id/exceptions", CreatePolicyException)
+	protected.GET("/policy-exceptions", ListPolicyExceptions)
+	protected.PUT("/policy-exceptions/:id", UpdatePolicyException)
+	protected.POST("/policy-exceptions/:id/approve", ApprovePolicyException)
+	protected.POST("/policy-exceptions/:id/reject", RejectPolicyException)
+	protected.POST("/policy-exceptions/:id/revoke", RevokePolicyException)
+
+	return router, mock
+}
+
+var policyExceptionRowColumns = []string{
+	"id", "policy_id", "identifier", "title", "title", "justification", "affected_assets",
+	"affected_user_ids", "status", "requested_by", "approver_id", "expires_at", "decided_by",
+	"decided_at", "decision_comments", "revoked_by", "revoked_at", "revocation_reason",
+	"expired_at", "created_at", "updated_at",
+}
+
+func policyExceptionRow(status, requestedBy string, approverID interface{}) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(policyExceptionRowColumns).AddRow(
+		"exc-001", "policy-001", "POL-AC-001", "Access Control Policy", "Legacy SFTP without MFA",
+		"Vendor appliance cannot do MFA", "{sftp-01}", "{}", status, requestedBy, approverID,
+		now.AddDate(0, 3, 0), nil, nil, nil, nil, nil, nil, nil, now, now)
+}
+
+// expectPolicyExceptionLoad mocks getPolicyException.
+func expectPolicyExceptionLoad(mock sqlmock.Sqlmock, status, requestedBy string, approverID interface{}) {
+	mock.ExpectQuery("FROM policy_exceptions e\\s+JOIN policies p").
+		WithArgs("exc-001", "org-001").
+		WillReturnRows(policyExceptionRow(status, requestedBy, approverID))
+	mock.ExpectQuery("FROM policy_exception_controls ec").
+		WillReturnRows(sqlmock.NewRows([]string{"exception_id", "id", "identifier", "title"}).
+			AddRow("exc-001", "ctrl-001", "CTL-NET-004", "Network segmentation"))
+}
+
+func TestCreatePolicyException_Success(t *testing.T) {
+	router, mock := setupPolicyExceptionRouter(models.RoleDevOpsEngineer)
+	expires := time.Now().AddDate(0, 3, 0).Format("2006-01-02")
+
+	mock.ExpectQuery("SELECT status FROM policies").
+		WithArgs("policy-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("published"))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM controls").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("user-002", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO policy_exceptions").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("exc-001"))
+	mock.ExpectExec("DELETE FROM policy_exception_controls").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO policy_exception_controls").
+		WithArgs("exc-001", "ctrl-001", "org-001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	expectPolicyExceptionLoad(mock, "requested", "user-001", "user-002")
+
+	body := `{
+		"title": "Legacy SFTP without MFA",
+		"justification": "Vendor appliance cannot do MFA",
+		"affected_assets": ["sftp-01", "sftp-01"],
+		"compensating_control_ids": ["ctrl-001"],
+		"approver_id": "user-002",
+		"expires_at": "` + expires + `"
+	}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/exceptions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"identifier":"CTL-NET-004"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePolicyException_Validation(t *testing.T) {
+	router, _ := setupPolicyExceptionRouter(models.RoleDevOpsEngineer)
+	future := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+	tooFar := time.Now().AddDate(2, 0, 0).Format("2006-01-02")
+
+	for _, body := range []string{
+		`{"title": "x", "justification": "y", "expires_at": "` + future + `"}`,
+		`{"title": "x", "justification": "y", "affected_assets": ["a"], "expires_at": "2020-01-01"}`,
+		`{"title": "x", "justification": "y", "affected_assets": ["a"], "expires_at": "` + tooFar + `"}`,
+		`{"title": "x", "justification": "  ", "affected_assets": ["a"], "expires_at": "` + future + `"}`,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/exceptions", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestCreatePolicyException_PolicyNotPublished(t *testing.T) {
+	router, mock := setupPolicyExceptionRouter(models.RoleDevOpsEngineer)
+	mock.ExpectQuery("SELECT status FROM policies").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("draft"))
+
+	body := `{"title": "x", "justification": "y", "affected_assets": ["a"], "expires_at": "` +
+		time.Now().AddDate(0, 1, 0).Format("2006-01-02") + `"}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/exceptions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestApprovePolicyException_DesignatedApprover(t *testing.T) {
+	router, mock := setupPolicyExceptionRouter(models.RoleSecurityEngineer)
+	expectPolicyExceptionLoad(mock, "requested", "user-003", "user-001")
+	mock.ExpectExec("UPDATE policy_exceptions SET status = \\$1, decided_by").
+		WithArgs("approved", "user-001", nil, "exc-001", "requested").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPolicyExceptionLoad(mock, "approved", "user-003", "user-001")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-exceptions/exc-001/approve", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApprovePolicyException_Forbidden(t *testing.T) {
+	cases := []struct {
+		name        string
+		role        string
+		requestedBy string
+		approverID  interface{}
+	}{
+		{"own exception", models.RoleCISO, "user-001", nil},
+		{"not the designated approver", models.RoleComplianceManager, "user-003", "user-002"},
+		{"no approver and not a policy manager", models.RoleSecurityEngineer, "user-003", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, mock := setupPolicyExceptionRouter(tc.role)
+			expectPolicyExceptionLoad(mock, "requested", tc.requestedBy, tc.approverID)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/api/v1/policy-exceptions/exc-001/approve", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+		})
+	}
+}
+
+func TestRejectPolicyException_RequiresComments(t *testing.T) {
+	router, _ := setupPolicyExceptionRouter(models.RoleCISO)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-exceptions/exc-001/reject", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRevokePolicyException_NotApproved(t *testing.T) {
+	router, mock := setupPolicyExceptionRouter(models.RoleCISO)
+	expectPolicyExceptionLoad(mock, "expired", "user-003", nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-exceptions/exc-001/revoke",
+		bytes.NewBufferString(`{"comments": "Appliance replaced"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUpdatePolicyException_AfterDecision(t *testing.T) {
+	router, mock := setupPolicyExceptionRouter(models.RoleDevOpsEngineer)
+	expectPolicyExceptionLoad(mock, "approved", "user-001", nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/policy-exceptions/exc-001",
+		bytes.NewBufferString(`{"title": "Longer window"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestListExceptionsForPolicy(t *testing.T) {
+	router, mock := setupPolicyExceptionRouter(models.RoleAuditor)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM policy_exceptions e").
+		WithArgs("org-001", "approved", "policy-001").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("FROM policy_exceptions e\\s+JOIN policies p").
+		WithArgs("org-001", "approved", "policy-001", 20, 0).
+		WillReturnRows(policyExceptionRow("approved", "user-003", nil))
+	mock.ExpectQuery("FROM policy_exception_controls ec").
+		WillReturnRows(sqlmock.NewRows([]string{"exception_id", "id", "identifier", "title"}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policies/policy-001/exceptions?status=active", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"affected_assets":["sftp-01"]`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPolicyStats_ExceptionSummary(t *testing.T) {
+	router, mock := setupPolicyExceptionRouter(models.RoleAuditor)
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery("FROM policy_exceptions WHERE org_id").
+		WithArgs("org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"requested", "approved", "expiring", "expired"}).AddRow(2, 5, 1, 3))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policies/stats", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data struct {
+			ExceptionSummary map[string]int `json:"exception_summary"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, map[string]int{"requested": 2, "active": 5, "expiring_within_30_days": 1, "expired": 3}, resp.Data.ExceptionSummary)
+}
@@ -304,6 +304,17 @@ func GetPolicyStats(c *gin.Context) {
 		FROM policy_signoffs WHERE org_id = $1
 	`, orgID).Scan(&pendingSignoffs, &overdueSignoffs)
 
+	// Exception register
+	var exceptionsRequested, exceptionsActive, exceptionsExpiringSoon, exceptionsExpired int
+	database.DB.QueryRow(`
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'requested'),
+			COUNT(*) FILTER (WHERE status = 'approved'),
+			COUNT(*) FILTER (WHERE status = 'approved' AND expires_at <= CURRENT_DATE + 30),
+			COUNT(*) FILTER (WHERE status = 'expired')
+		FROM policy_exceptions WHERE org_id = $1
+	`, orgID).Scan(&exceptionsRequested, &exceptionsActive, &exceptionsExpiringSoon, &exceptionsExpired)
+
 	// Gap summary
 	var totalActiveControls, controlsWithPolicy int
 	database.DB.QueryRow(`
@@ -378,6 +389,12 @@ func GetPolicyStats(c *gin.Context) {
 			"total_pending":     pendingSignoffs,
 			"overdue_signoffs":  overdueSignoffs,
 		},
+		"exception_summary": gin.H{
+			"requested":               exceptionsRequested,
+			"active":                  exceptionsActive,
+			"expiring_within_30_days": exceptionsExpiringSoon,
+			"expired":                 exceptionsExpired,
+		},
 		"gap_summary": gin.H{
 			"total_active_controls":          totalActiveControls,
 			"controls_with_policy_coverage":  controlsWithPolicy,
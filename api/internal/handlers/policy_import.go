@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+)
+
+var nonIdentifierRe = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// policyImportResult reports what an import did with one document.
+type policyImportResult struct {
+	File       string                   `json:"file"`
+	Status     string                   `json:"status"`
+	Message    string                   `json:"message,omitempty"`
+	PolicyID   *string                  `json:"policy_id,omitempty"`
+	VersionID  *string                  `json:"version_id,omitempty"`
+	Identifier string                   `json:"identifier,omitempty"`
+	Policy     *services.ImportedPolicy `json:"policy,omitempty"`
+	Content    *string                  `json:"content,omitempty"`
+}
+
+// policyImportOptions are the form fields that apply to every document in an import.
+type policyImportOptions struct {
+	category   string
+	ownerID    string
+	identifier string
+	dryRun     bool
+}
+
+// ImportPolicies creates draft policies from an uploaded DOCX or Markdown document, or from a
+// ZIP archive of them. Each document becomes a policy with an initial version holding the
+// sanitized HTML; title, identifier, version and category are taken from the document where
+// it declares them. Documents whose identifier already exists are skipped, so re-running an
+// import only picks up what is new. With dry_run=true nothing is written.
+func ImportPolicies(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Multipart field 'file' is required"))
+		return
+	}
+	fileName := sanitizeFileName(fileHeader.Filename)
+	isArchive := strings.EqualFold(path.Ext(fileName), ".zip")
+	if !isArchive && !services.IsPolicyImportFile(fileName) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "File must be .docx, .md or a .zip of them"))
+		return
+	}
+	maxSize := int64(services.MaxPolicyImportFileSize)
+	if isArchive {
+		maxSize = services.MaxPolicyImportArchiveSize
+	}
+	if fileHeader.Size <= 0 || fileHeader.Size > maxSize {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", fmt.Sprintf("File size must be between 1 byte and %dMB", maxSize>>20)))
+		return
+	}
+
+	opts := policyImportOptions{
+		category:   strings.TrimSpace(c.PostForm("category")),
+		ownerID:    strings.TrimSpace(c.PostForm("owner_id")),
+		identifier: strings.TrimSpace(c.PostForm("identifier")),
+	}
+	if v := c.PostForm("dry_run"); v != "" {
+		if opts.dryRun, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "dry_run must be true or false"))
+			return
+		}
+	}
+	if opts.category != "" && !models.IsValidPolicyCategory(opts.category) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid policy category"))
+		return
+	}
+	if opts.identifier != "" {
+		if isArchive {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "identifier can only be set when importing a single document"))
+			return
+		}
+		if len(opts.identifier) > 50 || !identifierRegex.MatchString(opts.identifier) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Identifier must be 50 characters or less and contain only letters, digits and hyphens"))
+			return
+		}
+	}
+	if opts.ownerID == "" {
+		opts.ownerID = userID
+	} else {
+		var ok bool
+		if err := database.QueryRow(`SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND org_id = $2)`, opts.ownerID, orgID).Scan(&ok); err != nil {
+			log.Error().Err(err).Msg("Failed to check policy owner")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to import policies"))
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Owner not found"))
+			return
+		}
+	}
+
+	f, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Failed to read uploaded file"))
+		return
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Failed to read uploaded file"))
+		return
+	}
+
+	files := []services.PolicyImportFile{{Path: fileName, Data: data}}
+	if isArchive {
+		if files, err = services.ReadPolicyImportArchive(data); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("INVALID_ARCHIVE", err.Error()))
+			return
+		}
+		if len(files) == 0 {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("INVALID_ARCHIVE", "Archive contains no .docx or .md documents"))
+			return
+		}
+	}
+
+	results := make([]policyImportResult, 0, len(files))
+	summary := map[string]int{"created": 0, "would_create": 0, "skipped": 0, "failed": 0}
+	seen := map[string]string{}
+	for _, file := range files {
+		r := importPolicyDocument(c, orgID, userID, file, opts, seen)
+		if !isArchive && opts.dryRun && r.Policy != nil {
+			r.Content = &r.Policy.HTML
+		}
+		summary[r.Status]++
+		results = append(results, r)
+	}
+
+	status := http.StatusCreated
+	if opts.dryRun || summary["created"] == 0 {
+		status = http.StatusOK
+	}
+	if !isArchive && summary["failed"] == 1 {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("IMPORT_FAILED", results[0].Message))
+		return
+	}
+	c.JSON(status, successResponse(c, gin.H{
+		"dry_run": opts.dryRun,
+		"total":   len(results),
+		"summary": summary,
+		"results": results,
+	}))
+}
+
+// importPolicyDocument converts one document and, unless this is a dry run, creates its policy.
+// seen maps identifiers already taken earlier in the same import to the file that took them.
+func importPolicyDocument(c *gin.Context, orgID, userID string, file services.PolicyImportFile,
+	opts policyImportOptions, seen map[string]string) policyImportResult {
+	r := policyImportResult{File: file.Path}
+	fail := func(msg string) policyImportResult {
+		r.Status = "failed"
+		r.Message = msg
+		return r
+	}
+
+	p, err := services.ConvertPolicyDocument(file.Path, file.Data)
+	if err != nil {
+		return fail(err.Error())
+	}
+	r.Policy = p
+	p.HTML = sanitizeHTML(p.HTML)
+	if len(p.HTML) > 1024*1024 {
+		return fail("Policy content exceeds 1MB limit")
+	}
+	if opts.category != "" {
+		p.Category = opts.category
+	}
+	if utf8.RuneCountInString(p.Title) > 500 {
+		p.Title = string([]rune(p.Title)[:500])
+	}
+
+	identifier := opts.identifier
+	if identifier == "" {
+		identifier = p.Identifier
+	}
+	if len(identifier) > 50 || !identifierRegex.MatchString(identifier) {
+		identifier = policyIdentifierFromTitle(p.Title)
+	}
+	r.Identifier = identifier
+	if other, ok := seen[identifier]; ok {
+		r.Status = "skipped"
+		r.Message = "Identifier " + identifier + " is also used by " + other + " in this import"
+		return r
+	}
+	seen[identifier] = file.Path
+
+	var exists bool
+	if err := database.QueryRow(`SELECT EXISTS(SELECT 1 FROM policies WHERE org_id = $1 AND identifier = $2)`, orgID, identifier).Scan(&exists); err != nil {
+		log.Error().Err(err).Str("file", file.Path).Msg("Failed to check identifier")
+		return fail("Failed to check identifier")
+	}
+	if exists {
+		r.Status = "skipped"
+		r.Message = "Policy identifier already exists"
+		return r
+	}
+	if opts.dryRun {
+		r.Status = "would_create"
+		return r
+	}
+
+	tags := []string{"imported"}
+	if dir := path.Base(path.Dir(file.Path)); dir != "." && dir != "/" {
+		tags = append(tags, dir)
+	}
+	tags = dedupeStrings(append(tags, p.Keywords...))
+	var description *string
+	if p.Description != "" {
+		description = &p.Description
+	}
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"import": map[string]interface{}{
+			"source_file":    file.Path,
+			"source_version": p.Version,
+			"sections":       p.Sections,
+			"warnings":       p.Warnings,
+		},
+	})
+
+	policyID := uuid.New().String()
+	versionID := uuid.New().String()
+	tx, err := database.DB.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		return fail("Failed to create policy")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO policies (id, org_id, identifier, title, description, category, status,
+			owner_id, is_template, tags, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, 'draft', $7, FALSE, $8, $9)
+	`, policyID, orgID, identifier, p.Title, description, p.Category, opts.ownerID, pq.Array(tags), string(metadata))
+	if err != nil {
+		log.Error().Err(err).Str("file", file.Path).Msg("Failed to insert imported policy")
+		return fail("Failed to create policy")
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO policy_versions (id, org_id, policy_id, version_number, is_current,
+			content, content_format, change_summary, change_type,
+			word_count, character_count, created_by)
+		VALUES ($1, $2, $3, 1, TRUE, $4, $5, $6, 'initial', $7, $8, $9)
+	`, versionID, orgID, policyID, p.HTML, models.ContentFormatHTML, "Imported from "+path.Base(file.Path),
+		countWords(p.HTML), utf8.RuneCountInString(p.HTML), userID)
+	if err != nil {
+		log.Error().Err(err).Str("file", file.Path).Msg("Failed to insert imported policy version")
+		return fail("Failed to create policy")
+	}
+
+	if _, err = tx.Exec(`UPDATE policies SET current_version_id = $1 WHERE id = $2`, versionID, policyID); err != nil {
+		log.Error().Err(err).Msg("Failed to update current version")
+		return fail("Failed to create policy")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit policy import")
+		return fail("Failed to create policy")
+	}
+
+	middleware.LogAudit(c, "policy.created", "policy", &policyID, map[string]interface{}{
+		"identifier": identifier, "title": p.Title,
+	})
+	middleware.LogAudit(c, "policy.imported", "policy", &policyID, map[string]interface{}{
+		"source_file": file.Path, "source_version": p.Version, "sections": len(p.Sections),
+	})
+	middleware.LogAudit(c, "policy_version.created", "policy_version", &versionID, map[string]interface{}{
+		"policy_id": policyID, "version_number": 1,
+	})
+
+	r.Status = "created"
+	r.PolicyID = &policyID
+	r.VersionID = &versionID
+	return r
+}
+
+// policyIdentifierFromTitle builds an identifier such as POL-REMOTE-ACCESS from a title.
+func policyIdentifierFromTitle(title string) string {
+	slug := strings.Trim(nonIdentifierRe.ReplaceAllString(strings.ToUpper(title), "-"), "-")
+	slug = strings.TrimSuffix(slug, "-POLICY")
+	if slug == "" {
+		slug = strings.ToUpper(uuid.New().String()[:8])
+	}
+	id := "POL-" + slug
+	if len(id) > 50 {
+		id = strings.TrimRight(id[:50], "-")
+	}
+	return id
+}
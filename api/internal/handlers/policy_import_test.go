@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testImportMarkdown = "---\ntitle: Acceptable Use Policy\nversion: 2.1\n---\n# Scope\n\nApplies to all staff.\n\n## Rules\n\n<script>alert(1)</script>Be nice.\n"
+
+func setupPolicyImportRouter() (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-001")
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, models.RoleComplianceManager)
+		c.Next()
+	})
+	protected.POST("/policies/import", ImportPolicies)
+	return router, mock
+}
+
+func policyImportRequest(t *testing.T, fields map[string]string, fileName string, content []byte) *http.Request {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		require.NoError(t, mw.WriteField(k, v))
+	}
+	fw, err := mw.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	fw.Write(content)
+	require.NoError(t, mw.Close())
+
+	req, _ := http.NewRequest("POST", "/api/v1/policies/import", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func policyImportZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		w.Write([]byte(content))
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func expectImportedPolicyInsert(mock sqlmock.Sqlmock, identifier string) {
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO policies").
+		WithArgs(sqlmock.AnyArg(), "org-001", identifier, sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), "user-001", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_versions").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE policies SET current_version_id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+func TestImportPolicies_Markdown(t *testing.T) {
+	router, mock := setupPolicyImportRouter()
+
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM policies").
+		WithArgs("org-001", "POL-ACCEPTABLE-USE").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO policies").
+		WithArgs(sqlmock.AnyArg(), "org-001", "POL-ACCEPTABLE-USE", "Acceptable Use Policy", nil,
+			models.PolicyCategoryAcceptableUse, "user-001", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_versions").
+		WithArgs(sqlmock.AnyArg(), "org-001", sqlmock.AnyArg(), sqlmock.AnyArg(), models.ContentFormatHTML,
+			"Imported from aup.md", sqlmock.AnyArg(), sqlmock.AnyArg(), "user-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE policies SET current_version_id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, policyImportRequest(t, nil, "aup.md", []byte(testImportMarkdown)))
+
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["summary"].(map[string]interface{})["created"])
+	result := data["results"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "created", result["status"])
+	assert.NotEmpty(t, result["policy_id"])
+	policy := result["policy"].(map[string]interface{})
+	assert.Equal(t, "2.1", policy["source_version"])
+	assert.Len(t, policy["sections"], 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportPolicies_DryRunReturnsSanitizedContent(t *testing.T) {
+	router, mock := setupPolicyImportRouter()
+
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM policies").
+		WithArgs("org-001", "POL-AUP-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, policyImportRequest(t, map[string]string{"dry_run": "true", "identifier": "POL-AUP-1"},
+		"aup.md", []byte(testImportMarkdown)))
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	result := resp["data"].(map[string]interface{})["results"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "would_create", result["status"])
+	assert.Contains(t, result["content"], "<h2>Rules</h2>")
+	assert.NotContains(t, result["content"], "<script>")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportPolicies_Archive(t *testing.T) {
+	router, mock := setupPolicyImportRouter()
+	mock.MatchExpectationsInOrder(false)
+
+	archive := policyImportZip(t, map[string]string{
+		"Policies/HR/onboarding.md":  "---\nidentifier: POL-HR-001\n---\n# Onboarding\n\nNew starters get accounts on day one.",
+		"Policies/Security/aup.md":   testImportMarkdown,
+		"Policies/Security/old.md":   "---\nidentifier: POL-HR-001\n---\n# Old onboarding\n\nSuperseded.",
+		"Policies/Security/bad.docx": "not a docx",
+		"Policies/notes.txt":         "ignored",
+	})
+
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM policies").
+		WithArgs("org-001", "POL-HR-001").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM policies").
+		WithArgs("org-001", "POL-ACCEPTABLE-USE").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	expectImportedPolicyInsert(mock, "POL-ACCEPTABLE-USE")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, policyImportRequest(t, nil, "policies.zip", archive))
+
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(4), data["total"])
+	summary := data["summary"].(map[string]interface{})
+	assert.Equal(t, float64(1), summary["created"])
+	assert.Equal(t, float64(2), summary["skipped"])
+	assert.Equal(t, float64(1), summary["failed"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportPolicies_Validation(t *testing.T) {
+	router, _ := setupPolicyImportRouter()
+
+	cases := []struct {
+		name   string
+		fields map[string]string
+		file   string
+		body   []byte
+		code   int
+	}{
+		{"unsupported type", nil, "policy.pdf", []byte("%PDF"), http.StatusBadRequest},
+		{"bad category", map[string]string{"category": "nonsense"}, "aup.md", []byte(testImportMarkdown), http.StatusBadRequest},
+		{"identifier on archive", map[string]string{"identifier": "POL-1"}, "p.zip", policyImportZip(t, map[string]string{"a.md": "# A"}), http.StatusBadRequest},
+		{"empty archive", nil, "p.zip", policyImportZip(t, map[string]string{"a.txt": "x"}), http.StatusUnprocessableEntity},
+		{"empty document", nil, "empty.md", []byte("\n"), http.StatusUnprocessableEntity},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, policyImportRequest(t, tc.fields, tc.file, tc.body))
+			assert.Equal(t, tc.code, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestPolicyIdentifierFromTitle(t *testing.T) {
+	assert.Equal(t, "POL-REMOTE-ACCESS", policyIdentifierFromTitle("Remote Access Policy"))
+	assert.Equal(t, "POL-BYOD-MOBILE-DEVICES", policyIdentifierFromTitle("BYOD / Mobile devices"))
+	assert.LessOrEqual(t, len(policyIdentifierFromTitle("An Extremely Long Policy Title That Keeps Going And Going Forever")), 50)
+}
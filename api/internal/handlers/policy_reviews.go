@@ -0,0 +1,565 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const policyReviewColumns = `
+	r.id, r.policy_id, p.identifier, p.title, r.status, r.outcome, r.assigned_to, r.due_at,
+	r.review_version_id, r.started_by, r.started_at, r.notes, r.escalation_level, r.escalated_at,
+	r.completed_at, r.created_at, r.updated_at`
+
+// activePolicyReviewStatuses are the statuses of a review that is not finished.
+const activePolicyReviewStatuses = `('open', 'in_approval', 'revising')`
+
+func scanPolicyReview(row rowScanner) (*models.PolicyReview, error) {
+	var r models.PolicyReview
+	if err := row.Scan(&r.ID, &r.PolicyID, &r.PolicyIdentifier, &r.PolicyTitle, &r.Status, &r.Outcome,
+		&r.AssignedTo, &r.DueAt, &r.ReviewVersionID, &r.StartedBy, &r.StartedAt, &r.Notes,
+		&r.EscalationLevel, &r.EscalatedAt, &r.CompletedAt, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListPolicyReviews lists scheduled policy reviews, unfinished ones by default.
+// assigned_to=me returns the caller's reviews.
+func ListPolicyReviews(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	where := []string{"r.org_id = $1"}
+	args := []interface{}{orgID}
+	argN := 2
+
+	switch status := c.DefaultQuery("status", "active"); status {
+	case "all":
+	case "active":
+		where = append(where, "r.status IN "+activePolicyReviewStatuses)
+	default:
+		valid := false
+		for _, s := range models.ValidPolicyReviewStatuses {
+			if s == status {
+				valid = true
+			}
+		}
+		if !valid {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid status"))
+			return
+		}
+		where = append(where, fmt.Sprintf("r.status = $%d", argN))
+		args = append(args, status)
+		argN++
+	}
+	if v := c.Query("assigned_to"); v != "" {
+		if v == "me" {
+			v = userID
+		}
+		where = append(where, fmt.Sprintf("r.assigned_to = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+	if v := c.Query("policy_id"); v != "" {
+		where = append(where, fmt.Sprintf("r.policy_id = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+	if c.Query("overdue") == "true" {
+		where = append(where, "r.due_at < CURRENT_DATE", "r.status IN "+activePolicyReviewStatuses)
+	}
+
+	whereClause := joinWhere(where)
+
+	var total int
+	database.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM policy_reviews r WHERE %s`, whereClause), args...).Scan(&total)
+
+	offset := (page - 1) * perPage
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM policy_reviews r
+		JOIN policies p ON p.id = r.policy_id
+		WHERE %s
+		ORDER BY r.due_at, p.identifier
+		LIMIT $%d OFFSET $%d
+	`, policyReviewColumns, whereClause, argN, argN+1)
+	args = append(args, perPage, offset)
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policy reviews")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer rows.Close()
+
+	results := []models.PolicyReview{}
+	for rows.Next() {
+		r, err := scanPolicyReview(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to scan policy review")
+			continue
+		}
+		results = append(results, *r)
+	}
+
+	c.JSON(http.StatusOK, listResponse(c, results, total, page, perPage))
+}
+
+// UpdatePolicyReview reassigns, annotates or cancels an open review.
+func UpdatePolicyReview(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	reviewID := c.Param("id")
+
+	var req models.UpdatePolicyReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body"))
+		return
+	}
+
+	var status string
+	err := database.QueryRow(`SELECT status FROM policy_reviews WHERE id = $1 AND org_id = $2`,
+		reviewID, orgID).Scan(&status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy review not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy review")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if status != models.PolicyReviewOpen {
+		c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", "Only open reviews can be changed"))
+		return
+	}
+
+	sets := []string{}
+	args := []interface{}{}
+	argN := 1
+	changed := []string{}
+	add := func(field, expr string, v interface{}) {
+		sets = append(sets, fmt.Sprintf(expr, argN))
+		args = append(args, v)
+		argN++
+		changed = append(changed, field)
+	}
+
+	if req.AssignedTo != nil {
+		var exists bool
+		database.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND org_id = $2 AND status = 'active')", *req.AssignedTo, orgID).Scan(&exists)
+		if !exists {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "Assignee not found"))
+			return
+		}
+		add("assigned_to", "assigned_to = $%d", *req.AssignedTo)
+	}
+	if req.Notes != nil {
+		add("notes", "notes = $%d", *req.Notes)
+	}
+	if req.Status != nil {
+		if *req.Status != models.PolicyReviewCancelled {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Reviews can only be cancelled; they complete when the policy is republished"))
+			return
+		}
+		add("status", "status = $%d", *req.Status)
+		sets = append(sets, "completed_at = NOW()")
+	}
+
+	if len(sets) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "No fields to update"))
+		return
+	}
+
+	args = append(args, reviewID, orgID)
+	review, err := scanPolicyReview(database.QueryRow(fmt.Sprintf(`
+		UPDATE policy_reviews r SET %s
+		FROM policies p
+		WHERE p.id = r.policy_id AND r.id = $%d AND r.org_id = $%d
+		RETURNING %s
+	`, joinStrings(sets), argN, argN+1, policyReviewColumns), args...))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update policy review")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "policy_review.updated", "policy_review", &reviewID, map[string]interface{}{
+		"changed": changed, "policy_id": review.PolicyID,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, review))
+}
+
+// reviewTarget is an open review with the policy and current version it covers.
+type reviewTarget struct {
+	Status           string
+	PolicyID         string
+	PolicyStatus     string
+	VersionID        *string
+	VersionNumber    *int
+	Content          *string
+	ContentFormat    *string
+	ContentSummary   *string
+	OwnerID          *string
+	SecondaryOwnerID *string
+}
+
+// loadReviewTarget loads an open review the caller may act on. Writes the error response and
+// returns nil otherwise.
+func loadReviewTarget(c *gin.Context, reviewID, orgID, userID, userRole string) *reviewTarget {
+	var t reviewTarget
+	err := database.QueryRow(`
+		SELECT r.status, p.id, p.status, p.current_version_id, pv.version_number,
+			pv.content, pv.content_format, pv.content_summary, p.owner_id, p.secondary_owner_id
+		FROM policy_reviews r
+		JOIN policies p ON p.id = r.policy_id
+		LEFT JOIN policy_versions pv ON pv.id = p.current_version_id
+		WHERE r.id = $1 AND r.org_id = $2
+	`, reviewID, orgID).Scan(&t.Status, &t.PolicyID, &t.PolicyStatus, &t.VersionID, &t.VersionNumber,
+		&t.Content, &t.ContentFormat, &t.ContentSummary, &t.OwnerID, &t.SecondaryOwnerID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy review not found"))
+		return nil
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy review")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return nil
+	}
+
+	// Auth: owner, compliance_manager, ciso, security_engineer
+	isOwner := (t.OwnerID != nil && *t.OwnerID == userID) || (t.SecondaryOwnerID != nil && *t.SecondaryOwnerID == userID)
+	if !isOwner && !models.HasRole(userRole, models.PolicyCreateRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to review this policy"))
+		return nil
+	}
+	if t.Status != models.PolicyReviewOpen {
+		c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", "Review has already been started"))
+		return nil
+	}
+	if t.VersionID == nil || t.Content == nil {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "Policy has no version"))
+		return nil
+	}
+	return &t
+}
+
+// insertPolicyVersion adds the next version of a policy and makes it current.
+func insertPolicyVersion(tx *sql.Tx, orgID, policyID, content, contentFormat string, contentSummary *string,
+	changeSummary, changeType, userID string) (string, int, error) {
+	var maxVersion int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(version_number), 0) FROM policy_versions WHERE policy_id = $1`, policyID).Scan(&maxVersion); err != nil {
+		return "", 0, err
+	}
+	newVersion := maxVersion + 1
+
+	if _, err := tx.Exec(`UPDATE policy_versions SET is_current = FALSE WHERE policy_id = $1 AND is_current = TRUE`, policyID); err != nil {
+		return "", 0, err
+	}
+
+	versionID := uuid.New().String()
+	_, err := tx.Exec(`
+		INSERT INTO policy_versions (id, org_id, policy_id, version_number, is_current,
+			content, content_format, content_summary, change_summary, change_type,
+			word_count, character_count, created_by)
+		VALUES ($1, $2, $3, $4, TRUE, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, versionID, orgID, policyID, newVersion, content, contentFormat,
+		contentSummary, changeSummary, changeType, countWords(content), utf8.RuneCountInString(content), userID)
+	if err != nil {
+		return "", 0, err
+	}
+	return versionID, newVersion, nil
+}
+
+// ConfirmPolicyReview records that a policy needs no changes. The current content is re-issued
+// as a minor version and sent for sign-off again; the review completes, and next_review_at rolls
+// forward, when the approved version is published.
+func ConfirmPolicyReview(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	reviewID := c.Param("id")
+
+	var req models.ConfirmPolicyReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+			return
+		}
+	}
+	if len(req.SignerIDs) > 10 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Maximum 10 signers allowed"))
+		return
+	}
+	var dueDate *time.Time
+	if req.DueDate != nil {
+		parsed, err := time.Parse("2006-01-02", *req.DueDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "due_date must be YYYY-MM-DD"))
+			return
+		}
+		dueDate = &parsed
+	}
+
+	t := loadReviewTarget(c, reviewID, orgID, userID, userRole)
+	if t == nil {
+		return
+	}
+	if t.PolicyStatus != models.PolicyStatusPublished {
+		c.JSON(http.StatusConflict, errorResponse("INVALID_STATUS_TRANSITION", "Only a published policy can be confirmed unchanged"))
+		return
+	}
+
+	// Re-run sign-off with whoever approved the version under review
+	signerIDs := req.SignerIDs
+	if len(signerIDs) == 0 {
+		rows, err := database.Query(`
+			SELECT DISTINCT signer_id FROM policy_signoffs
+			WHERE policy_version_id = $1 AND status = 'approved'
+		`, *t.VersionID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to load previous approvers")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+			return
+		}
+		for rows.Next() {
+			var id string
+			if rows.Scan(&id) == nil {
+				signerIDs = append(signerIDs, id)
+			}
+		}
+		rows.Close()
+		if len(signerIDs) == 0 {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "The current version has no approvers; signer_ids is required"))
+			return
+		}
+	}
+
+	var active int
+	database.QueryRow(`SELECT COUNT(*) FROM users WHERE org_id = $1 AND status = 'active' AND id = ANY($2::uuid[])`,
+		orgID, pq.Array(signerIDs)).Scan(&active)
+	if active != len(signerIDs) {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("VALIDATION_ERROR", "One or more signers not found or not active in this org"))
+		return
+	}
+
+	changeSummary := "Scheduled review: no changes"
+	if req.Notes != nil && strings.TrimSpace(*req.Notes) != "" {
+		changeSummary += " — " + strings.TrimSpace(*req.Notes)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to confirm review"))
+		return
+	}
+	defer tx.Rollback()
+
+	versionID, versionNumber, err := insertPolicyVersion(tx, orgID, t.PolicyID, *t.Content, *t.ContentFormat,
+		t.ContentSummary, changeSummary, "minor", userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to insert review version")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to confirm review"))
+		return
+	}
+
+	if _, err := tx.Exec(`UPDATE policies SET current_version_id = $1, status = 'in_review' WHERE id = $2`, versionID, t.PolicyID); err != nil {
+		log.Error().Err(err).Msg("Failed to update policy for review")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to confirm review"))
+		return
+	}
+
+	signoffs := []gin.H{}
+	for _, signerID := range signerIDs {
+		signoffID := uuid.New().String()
+		_, err := tx.Exec(`
+			INSERT INTO policy_signoffs (id, org_id, policy_id, policy_version_id,
+				signer_id, signer_role, requested_by, due_date, status)
+			SELECT $1, $2, $3, $4, u.id, u.role, $5, $6, 'pending'
+			FROM users u WHERE u.id = $7
+		`, signoffID, orgID, t.PolicyID, versionID, userID, dueDate, signerID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to create signoff")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to create signoff requests"))
+			return
+		}
+		signoffs = append(signoffs, gin.H{"id": signoffID, "signer_id": signerID, "status": "pending", "due_date": dueDate})
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE policy_reviews SET status = 'in_approval', outcome = 'no_changes', review_version_id = $1,
+			started_by = $2, started_at = NOW(), notes = COALESCE($3, notes)
+		WHERE id = $4
+	`, versionID, userID, req.Notes, reviewID); err != nil {
+		log.Error().Err(err).Msg("Failed to update policy review")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to confirm review"))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit policy review confirmation")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to confirm review"))
+		return
+	}
+
+	middleware.LogAudit(c, "policy_review.no_changes", "policy_review", &reviewID, map[string]interface{}{
+		"policy_id": t.PolicyID, "version_number": versionNumber, "signers": len(signerIDs),
+	})
+	middleware.LogAudit(c, "policy.status_changed", "policy", &t.PolicyID, map[string]interface{}{
+		"from": t.PolicyStatus, "to": "in_review", "review_id": reviewID,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"id":             reviewID,
+		"status":         models.PolicyReviewInApproval,
+		"outcome":        models.PolicyReviewOutcomeNoChanges,
+		"policy_id":      t.PolicyID,
+		"policy_status":  "in_review",
+		"policy_version": gin.H{"id": versionID, "version_number": versionNumber, "change_type": "minor"},
+		"signoffs":       signoffs,
+	}))
+}
+
+// RevisePolicyReview starts a new draft version from a review. The owner edits and submits the
+// draft as usual; the review completes when the revised policy is published.
+func RevisePolicyReview(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	reviewID := c.Param("id")
+
+	var req models.RevisePolicyReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+			return
+		}
+	}
+	if req.Content != nil && len(*req.Content) > 1024*1024 {
+		c.JSON(http.StatusBadRequest, errorResponse("CONTENT_TOO_LARGE", "Policy content exceeds 1MB limit"))
+		return
+	}
+	if req.ContentFormat != nil && !models.IsValidContentFormat(*req.ContentFormat) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid content format"))
+		return
+	}
+	changeType := "minor"
+	if req.ChangeType != nil {
+		switch *req.ChangeType {
+		case "major", "minor", "patch":
+			changeType = *req.ChangeType
+		default:
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid change type"))
+			return
+		}
+	}
+
+	t := loadReviewTarget(c, reviewID, orgID, userID, userRole)
+	if t == nil {
+		return
+	}
+	if t.PolicyStatus == models.PolicyStatusArchived {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("POLICY_ARCHIVED", "Cannot create new versions for archived policy"))
+		return
+	}
+
+	content, contentFormat := *t.Content, *t.ContentFormat
+	if req.Content != nil {
+		content = *req.Content
+		if req.ContentFormat != nil {
+			contentFormat = *req.ContentFormat
+		}
+	}
+	if contentFormat == models.ContentFormatHTML {
+		content = sanitizeHTML(content)
+	}
+	changeSummary := "Scheduled review revision"
+	if req.ChangeSummary != nil && strings.TrimSpace(*req.ChangeSummary) != "" {
+		changeSummary = strings.TrimSpace(*req.ChangeSummary)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to start revision"))
+		return
+	}
+	defer tx.Rollback()
+
+	versionID, versionNumber, err := insertPolicyVersion(tx, orgID, t.PolicyID, content, contentFormat,
+		t.ContentSummary, changeSummary, changeType, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to insert review draft version")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to start revision"))
+		return
+	}
+
+	if _, err := tx.Exec(`UPDATE policies SET current_version_id = $1, status = 'draft' WHERE id = $2`, versionID, t.PolicyID); err != nil {
+		log.Error().Err(err).Msg("Failed to update policy for revision")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to start revision"))
+		return
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE policy_reviews SET status = 'revising', outcome = 'revised', review_version_id = $1,
+			started_by = $2, started_at = NOW(), notes = COALESCE($3, notes)
+		WHERE id = $4
+	`, versionID, userID, req.Notes, reviewID); err != nil {
+		log.Error().Err(err).Msg("Failed to update policy review")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to start revision"))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit policy review revision")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to start revision"))
+		return
+	}
+
+	middleware.LogAudit(c, "policy_review.revision_started", "policy_review", &reviewID, map[string]interface{}{
+		"policy_id": t.PolicyID, "version_number": versionNumber,
+	})
+	middleware.LogAudit(c, "policy_version.created", "policy_version", &versionID, map[string]interface{}{
+		"policy_id": t.PolicyID, "version_number": versionNumber, "review_id": reviewID,
+	})
+	if t.PolicyStatus != models.PolicyStatusDraft {
+		middleware.LogAudit(c, "policy.status_changed", "policy", &t.PolicyID, map[string]interface{}{
+			"from": t.PolicyStatus, "to": "draft", "review_id": reviewID,
+		})
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"id":            reviewID,
+		"status":        models.PolicyReviewRevising,
+		"outcome":       models.PolicyReviewOutcomeRevised,
+		"policy_id":     t.PolicyID,
+		"policy_status": "draft",
+		"policy_version": gin.H{
+			"id":             versionID,
+			"version_number": versionNumber,
+			"change_type":    changeType,
+			"change_summary": changeSummary,
+		},
+	}))
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPolicyReviewRouter(role string) (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-001")
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, role)
+		c.Next()
+	})
+
+	protected.GET("/policy-reviews", ListPolicyReviews)
+	protected.PUT("/policy-reviews/:id", UpdatePolicyReview)
+	protected.POST("/policy-reviews/:id/no-changes", ConfirmPolicyReview)
+	protected.POST("/policy-reviews/:id/revise", RevisePolicyReview)
+
+	return router, mock
+}
+
+var policyReviewTargetCols = []string{
+	"status", "id", "status", "current_version_id", "version_number",
+	"content", "content_format", "content_summary", "owner_id", "secondary_owner_id",
+}
+
+func TestListPolicyReviews_AssignedToMe(t *testing.T) {
+	router, mock := setupPolicyReviewRouter(models.RoleSecurityEngineer)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM policy_reviews r").
+		WithArgs("org-001", "user-001").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("FROM policy_reviews r").
+		WithArgs("org-001", "user-001", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "policy_id", "identifier", "title", "status", "outcome", "assigned_to", "due_at",
+			"review_version_id", "started_by", "started_at", "notes", "escalation_level", "escalated_at",
+			"completed_at", "created_at", "updated_at",
+		}).AddRow("review-001", "policy-001", "POL-IS-001", "Information Security Policy", "open", nil, "user-001",
+			now.AddDate(0, 0, 10), nil, nil, nil, nil, 0, nil, nil, now, now))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policy-reviews?assigned_to=me", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].([]interface{})
+	require.Len(t, data, 1)
+	assert.Equal(t, "POL-IS-001", data[0].(map[string]interface{})["policy_identifier"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListPolicyReviews_InvalidStatus(t *testing.T) {
+	router, _ := setupPolicyReviewRouter(models.RoleCISO)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policy-reviews?status=pending", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdatePolicyReview_OnlyCancel(t *testing.T) {
+	router, mock := setupPolicyReviewRouter(models.RoleCISO)
+
+	mock.ExpectQuery("SELECT status FROM policy_reviews").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("open"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/policy-reviews/review-001", bytes.NewBufferString(`{"status": "completed"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestConfirmPolicyReview_NotOwner(t *testing.T) {
+	router, mock := setupPolicyReviewRouter(models.RoleDevOpsEngineer)
+
+	mock.ExpectQuery("FROM policy_reviews r").
+		WithArgs("review-001", "org-001").
+		WillReturnRows(sqlmock.NewRows(policyReviewTargetCols).
+			AddRow("open", "policy-001", "published", "version-002", 2, "<p>Policy</p>", "html", nil, "user-002", nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-reviews/review-001/no-changes", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestConfirmPolicyReview_AlreadyStarted(t *testing.T) {
+	router, mock := setupPolicyReviewRouter(models.RoleComplianceManager)
+
+	mock.ExpectQuery("FROM policy_reviews r").
+		WillReturnRows(sqlmock.NewRows(policyReviewTargetCols).
+			AddRow("in_approval", "policy-001", "in_review", "version-003", 3, "<p>Policy</p>", "html", nil, "user-001", nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-reviews/review-001/no-changes", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestConfirmPolicyReview_NoPreviousApprovers(t *testing.T) {
+	router, mock := setupPolicyReviewRouter(models.RoleComplianceManager)
+
+	mock.ExpectQuery("FROM policy_reviews r").
+		WillReturnRows(sqlmock.NewRows(policyReviewTargetCols).
+			AddRow("open", "policy-001", "published", "version-002", 2, "<p>Policy</p>", "html", nil, "user-001", nil))
+	mock.ExpectQuery("SELECT DISTINCT signer_id FROM policy_signoffs").
+		WithArgs("version-002").
+		WillReturnRows(sqlmock.NewRows([]string{"signer_id"}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-reviews/review-001/no-changes", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "signer_ids")
+}
+
+func TestConfirmPolicyReview_ReusesApprovers(t *testing.T) {
+	router, mock := setupPolicyReviewRouter(models.RoleComplianceManager)
+
+	mock.ExpectQuery("FROM policy_reviews r").
+		WillReturnRows(sqlmock.NewRows(policyReviewTargetCols).
+			AddRow("open", "policy-001", "published", "version-002", 2, "<p>Policy</p>", "html", nil, "user-001", nil))
+	mock.ExpectQuery("SELECT DISTINCT signer_id FROM policy_signoffs").
+		WillReturnRows(sqlmock.NewRows([]string{"signer_id"}).AddRow("user-ciso"))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE org_id").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(version_number\\), 0\\)").
+		WithArgs("policy-001").
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(2))
+	mock.ExpectExec("UPDATE policy_versions SET is_current = FALSE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_versions").
+		WithArgs(sqlmock.AnyArg(), "org-001", "policy-001", 3, "<p>Policy</p>", "html", nil,
+			"Scheduled review: no changes", "minor", 1, 13, "user-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE policies SET current_version_id = \\$1, status = 'in_review'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_signoffs").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE policy_reviews SET status = 'in_approval'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-reviews/review-001/no-changes", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "in_approval", data["status"])
+	assert.Equal(t, float64(3), data["policy_version"].(map[string]interface{})["version_number"])
+	assert.Len(t, data["signoffs"], 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevisePolicyReview_Success(t *testing.T) {
+	router, mock := setupPolicyReviewRouter(models.RoleDevOpsEngineer)
+
+	mock.ExpectQuery("FROM policy_reviews r").
+		WillReturnRows(sqlmock.NewRows(policyReviewTargetCols).
+			AddRow("open", "policy-001", "published", "version-002", 2, "<p>Policy</p>", "html", nil, "user-001", nil))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(version_number\\), 0\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(2))
+	mock.ExpectExec("UPDATE policy_versions SET is_current = FALSE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_versions").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE policies SET current_version_id = \\$1, status = 'draft'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE policy_reviews SET status = 'revising'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-reviews/review-001/revise",
+		bytes.NewBufferString(`{"change_type": "major", "change_summary": "Align with new data retention rules"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "revising", data["status"])
+	assert.Equal(t, "draft", data["policy_status"])
+	assert.Equal(t, "major", data["policy_version"].(map[string]interface{})["change_type"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -14,7 +14,8 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// ListPolicySignoffs lists signoffs for a policy.
+// ListPolicySignoffs lists signoffs for a policy, each with the diff of its version against the
+// last signed-off version.
 func ListPolicySignoffs(c *gin.Context) {
 	orgID := middleware.GetOrgID(c)
 	policyID := c.Param("id")
@@ -71,6 +72,7 @@ func ListPolicySignoffs(c *gin.Context) {
 	defer rows.Close()
 
 	signoffs := []gin.H{}
+	versionIDs := []*string{}
 	for rows.Next() {
 		var (
 			id, policyIDr                       string
@@ -124,7 +126,9 @@ func ListPolicySignoffs(c *gin.Context) {
 		}
 
 		signoffs = append(signoffs, s)
+		versionIDs = append(versionIDs, pvID)
 	}
+	attachReviewChanges(signoffs, versionIDs, orgID)
 
 	reqID, _ := c.Get(middleware.ContextKeyRequestID)
 	c.JSON(http.StatusOK, gin.H{
@@ -346,7 +350,8 @@ func WithdrawSignoff(c *gin.Context) {
 	}))
 }
 
-// ListPendingSignoffs lists pending signoffs for the authenticated user.
+// ListPendingSignoffs lists pending signoffs for the authenticated user. Each entry carries the
+// diff of the version under review against the last signed-off version.
 func ListPendingSignoffs(c *gin.Context) {
 	orgID := middleware.GetOrgID(c)
 	userID := middleware.GetUserID(c)
@@ -406,6 +411,7 @@ func ListPendingSignoffs(c *gin.Context) {
 	defer rows.Close()
 
 	results := []gin.H{}
+	versionIDs := []*string{}
 	for rows.Next() {
 		var (
 			id                                     string
@@ -463,7 +469,9 @@ func ListPendingSignoffs(c *gin.Context) {
 		}
 
 		results = append(results, r)
+		versionIDs = append(versionIDs, pvID)
 	}
+	attachReviewChanges(results, versionIDs, orgID)
 
 	c.JSON(http.StatusOK, listResponse(c, results, total, page, perPage))
 }
@@ -595,3 +603,26 @@ func joinWhere(conditions []string) string {
 func joinStrings(s []string) string {
 	return strings.Join(s, ", ")
 }
+
+// attachReviewChanges sets "changes" on each signoff to the diff of its policy version against
+// the baseline reviewers last saw, the same diff SubmitForReview returns. Diffs are computed
+// once per version.
+func attachReviewChanges(signoffs []gin.H, versionIDs []*string, orgID string) {
+	diffs := map[string]*models.PolicyDiff{}
+	for i, versionID := range versionIDs {
+		if versionID == nil {
+			signoffs[i]["changes"] = nil
+			continue
+		}
+		diff, ok := diffs[*versionID]
+		if !ok {
+			var err error
+			diff, err = reviewBaselineDiff(*versionID, orgID)
+			if err != nil {
+				log.Error().Err(err).Str("policy_version_id", *versionID).Msg("Failed to diff policy version for sign-off")
+			}
+			diffs[*versionID] = diff
+		}
+		signoffs[i]["changes"] = diff
+	}
+}
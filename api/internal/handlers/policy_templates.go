@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/half-paul/raisin-protect/api/internal/middleware"
 	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
 	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 )
@@ -204,6 +206,31 @@ func ClonePolicyTemplate(c *gin.Context) {
 		contentFormat = "html"
 	}
 
+	// Substitute template variables from the request, org settings and defaults
+	declared, err := loadTemplateVariables(templateID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load template variables")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to clone template"))
+		return
+	}
+	resolver := &policyVariableResolver{orgID: orgID}
+	variables, unresolved, err := resolver.resolve(effectiveTemplateVariables(declared, content), req.Variables)
+	var invalid *policyVariableError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusUnprocessableEntity, errorResponse("INVALID_VARIABLE", invalid.Error()))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to resolve template variables")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to clone template"))
+		return
+	}
+	if len(variables) > 0 {
+		content = services.SubstitutePolicyVariables(content, contentFormat, policyVariableDisplayMap(variables))
+		words := countWords(content)
+		wordCount = &words
+	}
+
 	policyID := uuid.New().String()
 	versionID := uuid.New().String()
 
@@ -218,10 +245,11 @@ func ClonePolicyTemplate(c *gin.Context) {
 	// Create new policy
 	_, err = tx.Exec(`
 		INSERT INTO policies (id, org_id, identifier, title, description, category, status,
-			owner_id, review_frequency_days, is_template, cloned_from_policy_id, tags, metadata)
-		VALUES ($1, $2, $3, $4, $5, $6, 'draft', $7, $8, FALSE, $9, $10, '{}')
+			owner_id, review_frequency_days, is_template, cloned_from_policy_id, tags, metadata,
+			template_source_version_id)
+		VALUES ($1, $2, $3, $4, $5, $6, 'draft', $7, $8, FALSE, $9, $10, '{}', $11)
 	`, policyID, orgID, req.Identifier, title, description, tCategory,
-		ownerID, reviewFreqDays, templateID, pq.Array(tags))
+		ownerID, reviewFreqDays, templateID, pq.Array(tags), tCurrentVersionID)
 	if err != nil {
 		log.Error().Err(err).Str("step", "insert_policy").Msg("Failed to insert cloned policy")
 		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to clone template"))
@@ -246,6 +274,12 @@ func ClonePolicyTemplate(c *gin.Context) {
 	// Set current version
 	tx.Exec(`UPDATE policies SET current_version_id = $1 WHERE id = $2`, versionID, policyID)
 
+	if err := savePolicyVariableValues(tx, orgID, policyID, variables); err != nil {
+		log.Error().Err(err).Msg("Failed to insert policy variable values")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to clone template"))
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		log.Error().Err(err).Msg("Failed to commit clone")
 		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to clone template"))
@@ -270,6 +304,8 @@ func ClonePolicyTemplate(c *gin.Context) {
 			"version_number": 1,
 			"word_count":     wordCount,
 		},
+		"variables":  variables,
+		"unresolved": unresolved,
 		"created_at": time.Now(),
 	}))
 }
@@ -0,0 +1,648 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// loadTemplateVariables returns the variables declared on a template, in display order.
+func loadTemplateVariables(templateID string) ([]models.PolicyTemplateVariable, error) {
+	rows, err := database.Query(`
+		SELECT key, label, var_type, description, default_value, required
+		FROM policy_template_variables
+		WHERE template_id = $1
+		ORDER BY sort_order, key
+	`, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vars := []models.PolicyTemplateVariable{}
+	for rows.Next() {
+		var v models.PolicyTemplateVariable
+		if err := rows.Scan(&v.Key, &v.Label, &v.Type, &v.Description, &v.DefaultValue, &v.Required); err != nil {
+			return nil, err
+		}
+		vars = append(vars, v)
+	}
+	return vars, rows.Err()
+}
+
+// effectiveTemplateVariables adds a definition for every placeholder in content the template
+// does not declare: the standard definition where there is one, otherwise required text.
+func effectiveTemplateVariables(declared []models.PolicyTemplateVariable, content string) []models.PolicyTemplateVariable {
+	vars := append([]models.PolicyTemplateVariable{}, declared...)
+	seen := map[string]bool{}
+	for _, v := range declared {
+		seen[v.Key] = true
+	}
+	for _, key := range services.FindPolicyPlaceholders(content) {
+		if seen[key] {
+			continue
+		}
+		v, ok := models.StandardPolicyVariable(key)
+		if !ok {
+			v = models.PolicyTemplateVariable{Key: key, Label: key, Type: models.PolicyVariableText, Required: true}
+		}
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// policyVariableError reports a value that is not valid for its variable.
+type policyVariableError struct {
+	key string
+	err error
+}
+
+func (e *policyVariableError) Error() string { return e.key + ": " + e.err.Error() }
+
+// policyVariableResolver resolves template variables against one org's settings.
+type policyVariableResolver struct {
+	orgID       string
+	orgName     string
+	displayName string
+	settings    map[string]interface{}
+	loaded      bool
+}
+
+func (r *policyVariableResolver) load() error {
+	if r.loaded {
+		return nil
+	}
+	var settings string
+	err := database.QueryRow(`
+		SELECT name, COALESCE(settings->'branding'->>'display_name', ''),
+			COALESCE(settings->'`+models.PolicyVariableSettingsKey+`', '{}'::jsonb)::text
+		FROM organizations WHERE id = $1
+	`, r.orgID).Scan(&r.orgName, &r.displayName, &settings)
+	if err != nil {
+		return err
+	}
+	r.settings = map[string]interface{}{}
+	if err := json.Unmarshal([]byte(settings), &r.settings); err != nil {
+		log.Warn().Err(err).Str("org_id", r.orgID).Msg("Ignoring invalid policy variable settings")
+	}
+	r.loaded = true
+	return nil
+}
+
+// orgValue returns the org-wide value for key. The company name falls back to the branding
+// display name and then the org name.
+func (r *policyVariableResolver) orgValue(key string) string {
+	if v, ok := r.settings[key]; ok && v != nil {
+		if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+			return s
+		}
+	}
+	if key == "company_name" {
+		if r.displayName != "" {
+			return r.displayName
+		}
+		return r.orgName
+	}
+	return ""
+}
+
+// display validates value for v and returns its canonical form and the text substituted into
+// content. User variables display the user's name. Invalid values return a *policyVariableError.
+func (r *policyVariableResolver) display(v models.PolicyTemplateVariable, value string) (string, string, error) {
+	value, err := services.NormalizePolicyVariableValue(v.Type, value)
+	if err != nil {
+		return "", "", &policyVariableError{key: v.Key, err: err}
+	}
+	if v.Type != models.PolicyVariableUser {
+		return value, value, nil
+	}
+	var name string
+	err = database.QueryRow(`
+		SELECT COALESCE(first_name || ' ' || last_name, email) FROM users
+		WHERE id = $1 AND org_id = $2 AND status = 'active'
+	`, value, r.orgID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", "", &policyVariableError{key: v.Key, err: errors.New("not an active user in this organization")}
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return value, name, nil
+}
+
+// resolve works out a value for each variable. Supplied values win, then org settings, then the
+// template default. An invalid supplied value or an unknown supplied key is a *policyVariableError;
+// an invalid org setting leaves the variable unresolved. Optional variables with no value resolve to empty.
+func (r *policyVariableResolver) resolve(vars []models.PolicyTemplateVariable, supplied map[string]string) ([]models.PolicyVariableValue, []string, error) {
+	known := map[string]bool{}
+	for _, v := range vars {
+		known[v.Key] = true
+	}
+	for key := range supplied {
+		if !known[key] {
+			return nil, nil, &policyVariableError{key: key, err: errors.New("not a variable of this template")}
+		}
+	}
+	if len(vars) == 0 {
+		return []models.PolicyVariableValue{}, []string{}, nil
+	}
+	if err := r.load(); err != nil {
+		return nil, nil, err
+	}
+
+	values := []models.PolicyVariableValue{}
+	unresolved := []string{}
+	for _, v := range vars {
+		if raw, ok := supplied[v.Key]; ok {
+			value, display, err := r.display(v, raw)
+			if err != nil {
+				return nil, nil, err
+			}
+			values = append(values, models.PolicyVariableValue{Key: v.Key, Value: value, DisplayValue: display, Source: models.PolicyVariableSourceClone})
+			continue
+		}
+
+		candidates := []struct{ raw, source string }{{r.orgValue(v.Key), models.PolicyVariableSourceOrgSetting}}
+		if v.DefaultValue != nil {
+			candidates = append(candidates, struct{ raw, source string }{*v.DefaultValue, models.PolicyVariableSourceDefault})
+		}
+		resolved := false
+		for _, cand := range candidates {
+			if strings.TrimSpace(cand.raw) == "" {
+				continue
+			}
+			value, display, err := r.display(v, cand.raw)
+			var invalid *policyVariableError
+			if errors.As(err, &invalid) {
+				log.Warn().Err(err).Str("org_id", r.orgID).Str("key", v.Key).Str("source", cand.source).Msg("Skipping invalid policy variable value")
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			values = append(values, models.PolicyVariableValue{Key: v.Key, Value: value, DisplayValue: display, Source: cand.source})
+			resolved = true
+			break
+		}
+		if resolved {
+			continue
+		}
+		if !v.Required {
+			values = append(values, models.PolicyVariableValue{Key: v.Key, Source: models.PolicyVariableSourceDefault})
+			continue
+		}
+		unresolved = append(unresolved, v.Key)
+	}
+	return values, unresolved, nil
+}
+
+// policyVariableDisplayMap maps keys to the text substituted for them.
+func policyVariableDisplayMap(values []models.PolicyVariableValue) map[string]string {
+	m := map[string]string{}
+	for _, v := range values {
+		m[v.Key] = v.DisplayValue
+	}
+	return m
+}
+
+// savePolicyVariableValues upserts a policy's resolved variable values.
+func savePolicyVariableValues(tx *sql.Tx, orgID, policyID string, values []models.PolicyVariableValue) error {
+	for _, v := range values {
+		_, err := tx.Exec(`
+			INSERT INTO policy_variable_values (org_id, policy_id, key, value, display_value, source)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (policy_id, key) DO UPDATE
+			SET value = EXCLUDED.value, display_value = EXCLUDED.display_value, source = EXCLUDED.source
+		`, orgID, policyID, v.Key, v.Value, v.DisplayValue, v.Source)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTemplateVariables lists the variables a template declares alongside the placeholders its
+// content actually uses.
+func GetTemplateVariables(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	templateID := c.Param("id")
+
+	var content string
+	err := database.QueryRow(`
+		SELECT COALESCE(pv.content, '')
+		FROM policies p
+		LEFT JOIN policy_versions pv ON pv.id = p.current_version_id
+		WHERE p.id = $1 AND p.org_id = $2 AND p.is_template = TRUE
+	`, templateID, orgID).Scan(&content)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Template not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get template")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get template variables"))
+		return
+	}
+
+	declared, err := loadTemplateVariables(templateID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load template variables")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get template variables"))
+		return
+	}
+
+	placeholders := services.FindPolicyPlaceholders(content)
+	isDeclared := map[string]bool{}
+	for _, v := range declared {
+		isDeclared[v.Key] = true
+	}
+	undeclared := []string{}
+	for _, key := range placeholders {
+		if !isDeclared[key] {
+			undeclared = append(undeclared, key)
+		}
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"template_id":  templateID,
+		"variables":    declared,
+		"placeholders": placeholders,
+		"undeclared":   undeclared,
+		"standard":     models.StandardPolicyVariables,
+	}))
+}
+
+// UpdateTemplateVariables replaces the variables declared on a template. Standard keys take their
+// type and label from the standard definition when they are not given.
+func UpdateTemplateVariables(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userRole := middleware.GetUserRole(c)
+	templateID := c.Param("id")
+
+	if !models.HasRole(userRole, models.PolicyCreateRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to edit template variables"))
+		return
+	}
+
+	var req models.UpdateTemplateVariablesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+		return
+	}
+	if req.Variables == nil {
+		req.Variables = []models.PolicyTemplateVariable{}
+	}
+
+	seen := map[string]bool{}
+	for i := range req.Variables {
+		v := &req.Variables[i]
+		v.Key = strings.TrimSpace(v.Key)
+		if !services.PolicyVariableKeyRe.MatchString(v.Key) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid variable key: "+v.Key))
+			return
+		}
+		if seen[v.Key] {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Duplicate variable key: "+v.Key))
+			return
+		}
+		seen[v.Key] = true
+
+		if std, ok := models.StandardPolicyVariable(v.Key); ok {
+			if v.Type == "" {
+				v.Type = std.Type
+			}
+			if v.Label == "" {
+				v.Label = std.Label
+			}
+		}
+		if v.Type == "" {
+			v.Type = models.PolicyVariableText
+		}
+		if v.Label == "" {
+			v.Label = v.Key
+		}
+		if !models.IsValidPolicyVariableType(v.Type) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid variable type: "+v.Type))
+			return
+		}
+		if v.DefaultValue != nil && v.Type != models.PolicyVariableUser {
+			value, err := services.NormalizePolicyVariableValue(v.Type, *v.DefaultValue)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid default for "+v.Key+": "+err.Error()))
+				return
+			}
+			v.DefaultValue = &value
+		}
+	}
+
+	var exists bool
+	database.QueryRow(`SELECT EXISTS(SELECT 1 FROM policies WHERE id = $1 AND org_id = $2 AND is_template = TRUE)`, templateID, orgID).Scan(&exists)
+	if !exists {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Template not found"))
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to update template variables"))
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM policy_template_variables WHERE template_id = $1`, templateID); err != nil {
+		log.Error().Err(err).Msg("Failed to clear template variables")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to update template variables"))
+		return
+	}
+	for i, v := range req.Variables {
+		_, err := tx.Exec(`
+			INSERT INTO policy_template_variables (org_id, template_id, key, label, var_type, description, default_value, required, sort_order)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, orgID, templateID, v.Key, v.Label, v.Type, v.Description, v.DefaultValue, v.Required, i)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to insert template variable")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to update template variables"))
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit template variables")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to update template variables"))
+		return
+	}
+
+	keys := make([]string, 0, len(req.Variables))
+	for _, v := range req.Variables {
+		keys = append(keys, v.Key)
+	}
+	middleware.LogAudit(c, "policy_template.variables_updated", "policy", &templateID, map[string]interface{}{
+		"keys": keys,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"template_id": templateID,
+		"variables":   req.Variables,
+	}))
+}
+
+// ListPolicyVariables lists the variable values substituted into a policy and any placeholders
+// its current version still contains.
+func ListPolicyVariables(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	policyID := c.Param("id")
+
+	var content string
+	err := database.QueryRow(`
+		SELECT COALESCE(pv.content, '')
+		FROM policies p
+		LEFT JOIN policy_versions pv ON pv.id = p.current_version_id
+		WHERE p.id = $1 AND p.org_id = $2
+	`, policyID, orgID).Scan(&content)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Policy not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get policy")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to list policy variables"))
+		return
+	}
+
+	values, err := loadPolicyVariableValues(policyID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policy variables")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to list policy variables"))
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"policy_id":  policyID,
+		"variables":  values,
+		"unresolved": services.FindPolicyPlaceholders(content),
+	}))
+}
+
+func loadPolicyVariableValues(policyID string) ([]models.PolicyVariableValue, error) {
+	rows, err := database.Query(`
+		SELECT key, value, display_value, source FROM policy_variable_values
+		WHERE policy_id = $1 ORDER BY key
+	`, policyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []models.PolicyVariableValue{}
+	for rows.Next() {
+		var v models.PolicyVariableValue
+		if err := rows.Scan(&v.Key, &v.Value, &v.DisplayValue, &v.Source); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// policyRegenerationTarget is a draft cloned from a template, with the template version it was
+// rendered from.
+type policyRegenerationTarget struct {
+	id, identifier  string
+	templateID      *string
+	templateContent string
+	templateFormat  string
+	currentContent  string
+	currentSummary  *string
+}
+
+// RegeneratePolicyDrafts re-renders draft policies from their template source with freshly
+// resolved org-wide values, e.g. after the org's security officer changes. Values supplied at
+// clone time are kept. A draft whose content no longer matches what its old values produce has
+// been edited by hand and is skipped unless force is set.
+func RegeneratePolicyDrafts(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	if !models.HasRole(userRole, models.PolicyPublishRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to regenerate policy drafts"))
+		return
+	}
+
+	var req models.RegeneratePolicyDraftsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+			return
+		}
+	}
+
+	query := `
+		SELECT p.id, p.identifier, p.cloned_from_policy_id, tv.content, tv.content_format, cv.content, cv.content_summary
+		FROM policies p
+		JOIN policy_versions tv ON tv.id = p.template_source_version_id
+		JOIN policy_versions cv ON cv.id = p.current_version_id
+		WHERE p.org_id = $1 AND p.status = 'draft' AND p.is_template = FALSE`
+	args := []interface{}{orgID}
+	if len(req.PolicyIDs) > 0 {
+		query += ` AND p.id = ANY($2)`
+		args = append(args, pq.Array(req.PolicyIDs))
+	}
+	query += ` ORDER BY p.identifier`
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list regenerable drafts")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to regenerate policy drafts"))
+		return
+	}
+	targets := []policyRegenerationTarget{}
+	for rows.Next() {
+		var t policyRegenerationTarget
+		if err := rows.Scan(&t.id, &t.identifier, &t.templateID, &t.templateContent, &t.templateFormat,
+			&t.currentContent, &t.currentSummary); err != nil {
+			rows.Close()
+			log.Error().Err(err).Msg("Failed to scan regenerable draft")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to regenerate policy drafts"))
+			return
+		}
+		targets = append(targets, t)
+	}
+	rows.Close()
+
+	resolver := &policyVariableResolver{orgID: orgID}
+	results := []gin.H{}
+	regenerated := 0
+	for _, t := range targets {
+		result, err := regeneratePolicyDraft(resolver, orgID, userID, t, req.Force, req.DryRun)
+		if err != nil {
+			log.Error().Err(err).Str("policy_id", t.id).Msg("Failed to regenerate policy draft")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to regenerate policy drafts"))
+			return
+		}
+		if result["status"] == "regenerated" {
+			regenerated++
+			if !req.DryRun {
+				policyID := t.id
+				middleware.LogAudit(c, "policy.variables_regenerated", "policy", &policyID, map[string]interface{}{
+					"changed": result["changed"], "version_number": result["version_number"],
+				})
+			}
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"dry_run":     req.DryRun,
+		"regenerated": regenerated,
+		"policies":    results,
+	}))
+}
+
+// regeneratePolicyDraft re-resolves one draft and, unless dryRun, stores the result as a patch
+// version.
+func regeneratePolicyDraft(resolver *policyVariableResolver, orgID, userID string, t policyRegenerationTarget, force, dryRun bool) (gin.H, error) {
+	old, err := loadPolicyVariableValues(t.id)
+	if err != nil {
+		return nil, err
+	}
+	declared := []models.PolicyTemplateVariable{}
+	if t.templateID != nil {
+		if declared, err = loadTemplateVariables(*t.templateID); err != nil {
+			return nil, err
+		}
+	}
+	vars := effectiveTemplateVariables(declared, t.templateContent)
+
+	supplied := map[string]string{}
+	for _, v := range old {
+		if v.Source == models.PolicyVariableSourceClone {
+			supplied[v.Key] = v.Value
+		}
+	}
+	// A variable removed from the template keeps no supplied value.
+	known := map[string]bool{}
+	for _, v := range vars {
+		known[v.Key] = true
+	}
+	for key := range supplied {
+		if !known[key] {
+			delete(supplied, key)
+		}
+	}
+
+	values, unresolved, err := resolver.resolve(vars, supplied)
+	if err != nil {
+		return nil, err
+	}
+
+	oldDisplay := policyVariableDisplayMap(old)
+	newDisplay := policyVariableDisplayMap(values)
+	changed := []string{}
+	for _, v := range values {
+		if prev, ok := oldDisplay[v.Key]; !ok || prev != v.DisplayValue {
+			changed = append(changed, v.Key)
+		}
+	}
+
+	result := gin.H{"policy_id": t.id, "identifier": t.identifier, "changed": changed, "unresolved": unresolved}
+	expected := services.SubstitutePolicyVariables(t.templateContent, t.templateFormat, oldDisplay)
+	content := services.SubstitutePolicyVariables(t.templateContent, t.templateFormat, newDisplay)
+	switch {
+	case content == t.currentContent:
+		result["status"] = "up_to_date"
+		return result, nil
+	case t.currentContent != expected && !force:
+		result["status"] = "edited"
+		return result, nil
+	}
+	result["status"] = "regenerated"
+	if dryRun {
+		return result, nil
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	changeSummary := "Regenerated from template variables"
+	if len(changed) > 0 {
+		changeSummary += ": " + strings.Join(changed, ", ")
+	}
+	versionID, versionNumber, err := insertPolicyVersion(tx, orgID, t.id, content, t.templateFormat,
+		t.currentSummary, changeSummary, "patch", userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE policies SET current_version_id = $1 WHERE id = $2`, versionID, t.id); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(values))
+	for _, v := range values {
+		keys = append(keys, v.Key)
+	}
+	if _, err := tx.Exec(`DELETE FROM policy_variable_values WHERE policy_id = $1 AND NOT (key = ANY($2))`, t.id, pq.Array(keys)); err != nil {
+		return nil, err
+	}
+	if err := savePolicyVariableValues(tx, orgID, t.id, values); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	result["version_number"] = versionNumber
+	return result, nil
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPolicyVariableRouter(role string) (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-001")
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, role)
+		c.Next()
+	})
+
+	protected.GET("/policy-templates/:id/variables", GetTemplateVariables)
+	protected.PUT("/policy-templates/:id/variables", UpdateTemplateVariables)
+	protected.POST("/policy-templates/:id/clone", ClonePolicyTemplate)
+	protected.POST("/policies/:id/submit-for-review", SubmitForReview)
+	protected.POST("/policy-variables/regenerate", RegeneratePolicyDrafts)
+
+	return router, mock
+}
+
+var templateVariableColumns = []string{"key", "label", "var_type", "description", "default_value", "required"}
+
+// expectTemplateForClone mocks the template, identifier and content lookups of ClonePolicyTemplate.
+func expectTemplateForClone(mock sqlmock.Sqlmock, content string) {
+	mock.ExpectQuery("SELECT identifier, title, description, category").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"identifier", "title", "description", "category",
+			"review_frequency_days", "tags", "current_version_id",
+		}).AddRow("TPL-IS-001", "Info Security Template", "Template desc", "information_security",
+			365, "{template}", "tpl-version-001"))
+	mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery("SELECT content, content_format, word_count").
+		WillReturnRows(sqlmock.NewRows([]string{"content", "content_format", "word_count"}).AddRow(content, "html", 10))
+}
+
+func TestCloneTemplate_SubstitutesVariables(t *testing.T) {
+	router, mock := setupPolicyVariableRouter(models.RoleComplianceManager)
+	expectTemplateForClone(mock, "<p>{{company_name}} appoints {{security_officer}}. Logs are kept for {{retention_period}}.</p>")
+
+	mock.ExpectQuery("FROM policy_template_variables").
+		WithArgs("template-001").
+		WillReturnRows(sqlmock.NewRows(templateVariableColumns).
+			AddRow("security_officer", "Security officer", "user", nil, nil, true).
+			AddRow("retention_period", "Retention period", "duration", nil, "1 year", true))
+	mock.ExpectQuery("FROM organizations WHERE id").
+		WithArgs("org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "display_name", "policy_variables"}).
+			AddRow("Acme Corporation", "Acme & Co", `{"security_officer": "user-009"}`))
+	mock.ExpectQuery("FROM users").
+		WithArgs("user-002", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Sam Lee"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO policies").
+		WithArgs(sqlmock.AnyArg(), "org-001", "POL-IS-001", "Info Security Template", sqlmock.AnyArg(), "information_security",
+			"user-001", 365, "template-001", sqlmock.AnyArg(), "tpl-version-001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO policy_versions").
+		WithArgs(sqlmock.AnyArg(), "org-001", sqlmock.AnyArg(),
+			"<p>Acme &amp; Co appoints Sam Lee. Logs are kept for 1 year.</p>", "html",
+			sqlmock.AnyArg(), sqlmock.AnyArg(), 12, sqlmock.AnyArg(), "user-001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE policies SET current_version_id").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO policy_variable_values").
+		WithArgs("org-001", sqlmock.AnyArg(), "security_officer", "user-002", "Sam Lee", "clone").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO policy_variable_values").
+		WithArgs("org-001", sqlmock.AnyArg(), "retention_period", "1 year", "1 year", "default").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO policy_variable_values").
+		WithArgs("org-001", sqlmock.AnyArg(), "company_name", "Acme & Co", "Acme & Co", "org_setting").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	body := `{"identifier": "POL-IS-001", "variables": {"security_officer": "user-002"}}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-templates/template-001/clone", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Len(t, data["variables"], 3)
+	assert.Empty(t, data["unresolved"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCloneTemplate_UnknownVariable(t *testing.T) {
+	router, mock := setupPolicyVariableRouter(models.RoleComplianceManager)
+	expectTemplateForClone(mock, "<p>{{company_name}}</p>")
+	mock.ExpectQuery("FROM policy_template_variables").
+		WillReturnRows(sqlmock.NewRows(templateVariableColumns))
+
+	body := `{"identifier": "POL-IS-001", "variables": {"ceo_name": "Pat"}}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-templates/template-001/clone", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_VARIABLE")
+}
+
+func TestSubmitForReview_UnresolvedPlaceholders(t *testing.T) {
+	router, mock := setupPolicyVariableRouter(models.RoleComplianceManager)
+	mock.ExpectQuery("SELECT status, owner_id, current_version_id FROM policies").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "owner_id", "current_version_id"}).
+			AddRow("draft", "user-001", "version-001"))
+	mock.ExpectQuery("SELECT content FROM policy_versions").
+		WithArgs("version-001").
+		WillReturnRows(sqlmock.NewRows([]string{"content"}).AddRow("<p>Owned by {{security_officer}}, reviewed {{review_cadence}}.</p>"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policies/policy-001/submit-for-review",
+		bytes.NewBufferString(`{"signer_ids": ["signer-001"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "UNRESOLVED_PLACEHOLDERS")
+	assert.Contains(t, w.Body.String(), "security_officer, review_cadence")
+}
+
+func TestGetTemplateVariables(t *testing.T) {
+	router, mock := setupPolicyVariableRouter(models.RoleAuditor)
+	mock.ExpectQuery("FROM policies p").
+		WithArgs("template-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"content"}).AddRow("<p>{{company_name}} / {{security_officer}}</p>"))
+	mock.ExpectQuery("FROM policy_template_variables").
+		WillReturnRows(sqlmock.NewRows(templateVariableColumns).AddRow("company_name", "Company name", "text", nil, nil, true))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/policy-templates/template-001/variables", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"undeclared":["security_officer"]`)
+}
+
+func TestUpdateTemplateVariables(t *testing.T) {
+	router, mock := setupPolicyVariableRouter(models.RoleComplianceManager)
+	mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM policy_template_variables").WithArgs("template-001").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_template_variables").
+		WithArgs("org-001", "template-001", "security_officer", "Security officer", "user", nil, nil, true, 0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO policy_template_variables").
+		WithArgs("org-001", "template-001", "retention_period", "Retention period", "duration", nil, "7 years", false, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	body := `{"variables": [
+		{"key": "security_officer", "required": true},
+		{"key": "retention_period", "default_value": "7 Years"}
+	]}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/policy-templates/template-001/variables", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateTemplateVariables_Invalid(t *testing.T) {
+	router, _ := setupPolicyVariableRouter(models.RoleComplianceManager)
+
+	for _, body := range []string{
+		`{"variables": [{"key": "Company Name"}]}`,
+		`{"variables": [{"key": "owner", "type": "email"}]}`,
+		`{"variables": [{"key": "review_cadence", "default_value": "weekly"}]}`,
+		`{"variables": [{"key": "a"}, {"key": "a"}]}`,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/api/v1/policy-templates/template-001/variables", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestRegeneratePolicyDrafts(t *testing.T) {
+	router, mock := setupPolicyVariableRouter(models.RoleCISO)
+	template := "<p>Owned by {{security_officer}} at {{company_name}}.</p>"
+
+	mock.ExpectQuery("FROM policies p\\s+JOIN policy_versions tv").
+		WithArgs("org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "identifier", "cloned_from_policy_id", "content", "content_format", "content", "content_summary"}).
+			AddRow("policy-001", "POL-001", "template-001", template, "html", "<p>Owned by Sam Lee at Acme.</p>", nil).
+			AddRow("policy-002", "POL-002", "template-001", template, "html", "<p>Owned by Sam Lee at Acme, edited.</p>", nil))
+
+	valueColumns := []string{"key", "value", "display_value", "source"}
+	oldValues := sqlmock.NewRows(valueColumns).
+		AddRow("company_name", "Acme", "Acme", "clone").
+		AddRow("security_officer", "user-009", "Sam Lee", "org_setting")
+	declared := sqlmock.NewRows(templateVariableColumns).AddRow("security_officer", "Security officer", "user", nil, nil, true)
+
+	// policy-001: the security officer setting changed to user-010
+	mock.ExpectQuery("FROM policy_variable_values").WithArgs("policy-001").WillReturnRows(oldValues)
+	mock.ExpectQuery("FROM policy_template_variables").WithArgs("template-001").WillReturnRows(declared)
+	mock.ExpectQuery("FROM organizations WHERE id").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "display_name", "policy_variables"}).
+			AddRow("Acme Corporation", "", `{"security_officer": "user-010"}`))
+	mock.ExpectQuery("FROM users").WithArgs("user-010", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Kim Park"))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(version_number\\), 0\\)").WithArgs("policy-001").
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(1))
+	mock.ExpectExec("UPDATE policy_versions SET is_current = FALSE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_versions").
+		WithArgs(sqlmock.AnyArg(), "org-001", "policy-001", 2, "<p>Owned by Kim Park at Acme.</p>", "html",
+			nil, "Regenerated from template variables: security_officer", "patch", sqlmock.AnyArg(), sqlmock.AnyArg(), "user-001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE policies SET current_version_id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM policy_variable_values").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO policy_variable_values").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO policy_variable_values").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// policy-002 was edited by hand and is skipped
+	mock.ExpectQuery("FROM policy_variable_values").WithArgs("policy-002").
+		WillReturnRows(sqlmock.NewRows(valueColumns).
+			AddRow("company_name", "Acme", "Acme", "clone").
+			AddRow("security_officer", "user-009", "Sam Lee", "org_setting"))
+	mock.ExpectQuery("FROM policy_template_variables").WithArgs("template-001").
+		WillReturnRows(sqlmock.NewRows(templateVariableColumns).AddRow("security_officer", "Security officer", "user", nil, nil, true))
+	mock.ExpectQuery("FROM users").WithArgs("user-010", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Kim Park"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-variables/regenerate", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data struct {
+			Regenerated int                      `json:"regenerated"`
+			Policies    []map[string]interface{} `json:"policies"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Data.Regenerated)
+	require.Len(t, resp.Data.Policies, 2)
+	assert.Equal(t, "regenerated", resp.Data.Policies[0]["status"])
+	assert.Equal(t, "edited", resp.Data.Policies[1]["status"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegeneratePolicyDrafts_Forbidden(t *testing.T) {
+	router, _ := setupPolicyVariableRouter(models.RoleSecurityEngineer)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/policy-variables/regenerate", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
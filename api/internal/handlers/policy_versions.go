@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/half-paul/raisin-protect/api/internal/middleware"
 	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
 	"github.com/rs/zerolog/log"
 )
 
@@ -360,7 +361,8 @@ func CreatePolicyVersion(c *gin.Context) {
 	}))
 }
 
-// CompareVersions compares two versions side-by-side.
+// CompareVersions compares two versions and returns a server-side diff (inline and side-by-side,
+// with change statistics) alongside both versions' contents.
 func CompareVersions(c *gin.Context) {
 	orgID := middleware.GetOrgID(c)
 	policyID := c.Param("id")
@@ -475,9 +477,52 @@ func CompareVersions(c *gin.Context) {
 			buildVersionJSON(ver2),
 		},
 		"word_count_delta": wordDelta,
+		"diff":             policyVersionDiff(v1, ver1.Content, ver1.ContentFormat, v2, ver2.Content, ver2.ContentFormat),
 	}))
 }
 
+// policyVersionDiff diffs the lower-numbered version against the higher one, so the diff always
+// reads forward in time regardless of the order requested.
+func policyVersionDiff(v1 int, c1, f1 string, v2 int, c2, f2 string) *models.PolicyDiff {
+	if v1 > v2 {
+		v1, c1, f1, v2, c2, f2 = v2, c2, f2, v1, c1, f1
+	}
+	diff := services.DiffPolicyContent(c1, f1, c2, f2)
+	diff.FromVersion = v1
+	diff.ToVersion = v2
+	return diff
+}
+
+// reviewBaselineDiff diffs a version against the baseline reviewers last saw: the latest earlier
+// version with an approved sign-off, or the previous version if none was signed off. Returns nil
+// for a policy's first version.
+func reviewBaselineDiff(versionID, orgID string) (*models.PolicyDiff, error) {
+	var (
+		curNum                  int
+		curContent, curFormat   string
+		baseNum                 *int
+		baseContent, baseFormat *string
+	)
+	err := database.DB.QueryRow(`
+		SELECT cur.version_number, cur.content, cur.content_format,
+			base.version_number, base.content, base.content_format
+		FROM policy_versions cur
+		LEFT JOIN LATERAL (
+			SELECT pv.version_number, pv.content, pv.content_format
+			FROM policy_versions pv
+			WHERE pv.policy_id = cur.policy_id AND pv.version_number < cur.version_number
+			ORDER BY EXISTS(SELECT 1 FROM policy_signoffs ps WHERE ps.policy_version_id = pv.id AND ps.status = 'approved') DESC,
+				pv.version_number DESC
+			LIMIT 1
+		) base ON TRUE
+		WHERE cur.id = $1 AND cur.org_id = $2
+	`, versionID, orgID).Scan(&curNum, &curContent, &curFormat, &baseNum, &baseContent, &baseFormat)
+	if err != nil || baseNum == nil {
+		return nil, err
+	}
+	return policyVersionDiff(*baseNum, *baseContent, *baseFormat, curNum, curContent, curFormat), nil
+}
+
 // SearchPolicies provides advanced search across policies and content.
 func SearchPolicies(c *gin.Context) {
 	orgID := middleware.GetOrgID(c)
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+const riskAcceptanceColumns = `
+	a.id, a.risk_id, a.renewal_of, a.status, a.justification, a.expires_at, a.requested_by,
+	a.requested_at, a.decided_by, a.decided_at, a.decision_comments, a.appetite_position,
+	a.residual_score, a.ended_by, a.ended_at, a.created_at, a.updated_at`
+
+func scanRiskAcceptance(row rowScanner) (*models.RiskAcceptance, error) {
+	var a models.RiskAcceptance
+	if err := row.Scan(&a.ID, &a.RiskID, &a.RenewalOf, &a.Status, &a.Justification, &a.ExpiresAt,
+		&a.RequestedBy, &a.RequestedAt, &a.DecidedBy, &a.DecidedAt, &a.DecisionComments,
+		&a.AppetitePosition, &a.ResidualScore, &a.EndedBy, &a.EndedAt, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func getRiskAcceptance(acceptanceID, riskID, orgID string) (*models.RiskAcceptance, error) {
+	return scanRiskAcceptance(database.DB.QueryRow(`
+		SELECT `+riskAcceptanceColumns+`
+		FROM risk_acceptances a
+		WHERE a.id = $1 AND a.risk_id = $2 AND a.org_id = $3
+	`, acceptanceID, riskID, orgID))
+}
+
+// ListRiskAcceptances returns the acceptance history of a risk, newest first: every acceptance,
+// every renewal request and how each one ended.
+func ListRiskAcceptances(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	riskID := c.Param("id")
+
+	var exists bool
+	err := database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM risks WHERE id = $1 AND org_id = $2)", riskID, orgID).Scan(&exists)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to check risk")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to list risk acceptances"))
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk not found"))
+		return
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT `+riskAcceptanceColumns+`
+		FROM risk_acceptances a
+		WHERE a.risk_id = $1 AND a.org_id = $2
+		ORDER BY a.requested_at DESC, a.created_at DESC
+	`, riskID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list risk acceptances")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to list risk acceptances"))
+		return
+	}
+	defer rows.Close()
+
+	acceptances := []*models.RiskAcceptance{}
+	for rows.Next() {
+		a, err := scanRiskAcceptance(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to scan risk acceptance")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to list risk acceptances"))
+			return
+		}
+		acceptances = append(acceptances, a)
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, acceptances))
+}
+
+// RequestRiskAcceptanceRenewal asks for an accepted risk to stay accepted past its current
+// expiry. The renewal carries a fresh justification and takes effect once approved.
+func RequestRiskAcceptanceRenewal(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	riskID := c.Param("id")
+
+	var req models.RenewRiskAcceptanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request: "+err.Error()))
+		return
+	}
+	if strings.TrimSpace(req.Justification) == "" {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Justification is required to renew an acceptance"))
+		return
+	}
+	expiry, err := time.Parse("2006-01-02", req.AcceptanceExpiry)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid acceptance_expiry format (use YYYY-MM-DD)"))
+		return
+	}
+
+	var status string
+	var ownerID *string
+	var currentExpiry *time.Time
+	err = database.DB.QueryRow(`
+		SELECT status, owner_id, acceptance_expiry FROM risks WHERE id = $1 AND org_id = $2
+	`, riskID, orgID).Scan(&status, &ownerID, &currentExpiry)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get risk for acceptance renewal")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to request renewal"))
+		return
+	}
+
+	// Authorization: owner or authorized roles
+	isOwner := ownerID != nil && *ownerID == userID
+	if !isOwner && !models.HasRole(userRole, models.RiskCreateRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to renew this risk's acceptance"))
+		return
+	}
+	if status != models.RiskStatusAccepted {
+		c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", "Only accepted risks can have their acceptance renewed"))
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if !expiry.After(today) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "acceptance_expiry must be in the future"))
+		return
+	}
+	if currentExpiry != nil && !expiry.After(*currentExpiry) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+			fmt.Sprintf("acceptance_expiry must be after the current expiry of %s", currentExpiry.Format("2006-01-02"))))
+		return
+	}
+
+	var pending bool
+	err = database.DB.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM risk_acceptances WHERE risk_id = $1 AND status = 'requested')
+	`, riskID).Scan(&pending)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to check pending renewals")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to request renewal"))
+		return
+	}
+	if pending {
+		c.JSON(http.StatusConflict, errorResponse("RENEWAL_PENDING", "A renewal of this acceptance is already awaiting a decision"))
+		return
+	}
+
+	a, err := scanRiskAcceptance(database.DB.QueryRow(`
+		WITH a AS (
+			INSERT INTO risk_acceptances (org_id, risk_id, renewal_of, status, justification, expires_at, requested_by)
+			SELECT $1, $2, (SELECT id FROM risk_acceptances WHERE risk_id = $2 AND status = 'active'),
+				'requested', $3, $4, $5
+			RETURNING *
+		)
+		SELECT `+riskAcceptanceColumns+` FROM a
+	`, orgID, riskID, strings.TrimSpace(req.Justification), expiry, userID))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create acceptance renewal")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to request renewal"))
+		return
+	}
+
+	middleware.LogAudit(c, "risk_acceptance.renewal_requested", "risk", &riskID, map[string]interface{}{
+		"acceptance_id": a.ID, "expires_at": req.AcceptanceExpiry,
+	})
+
+	c.JSON(http.StatusCreated, successResponse(c, a))
+}
+
+// ApproveRiskAcceptanceRenewal approves a renewal request: it replaces the acceptance in force
+// and moves the risk's acceptance expiry. Renewing a risk above tolerance needs the CISO.
+func ApproveRiskAcceptanceRenewal(c *gin.Context) {
+	decideRiskAcceptanceRenewal(c, true)
+}
+
+// RejectRiskAcceptanceRenewal rejects a renewal request; comments are required. The acceptance
+// in force is unchanged and lapses at its expiry.
+func RejectRiskAcceptanceRenewal(c *gin.Context) {
+	decideRiskAcceptanceRenewal(c, false)
+}
+
+// decideRiskAcceptanceRenewal approves or rejects a requested renewal. Decisions are limited to
+// the roles that may accept risks, and requesters never decide their own renewals.
+func decideRiskAcceptanceRenewal(c *gin.Context, approve bool) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	riskID := c.Param("id")
+	acceptanceID := c.Param("acceptanceId")
+
+	var req models.RiskAcceptanceDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request body: "+err.Error()))
+			return
+		}
+	}
+	if !approve && (req.Comments == nil || strings.TrimSpace(*req.Comments) == "") {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Comments are required"))
+		return
+	}
+	if !models.HasRole(userRole, models.RiskAcceptRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Only CISO or compliance manager can decide acceptance renewals"))
+		return
+	}
+
+	a, err := getRiskAcceptance(acceptanceID, riskID, orgID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk acceptance not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get risk acceptance")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if a.RequestedBy != nil && *a.RequestedBy == userID {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Requesters cannot decide their own renewal"))
+		return
+	}
+	if a.Status != models.RiskAcceptanceRequested {
+		c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", fmt.Sprintf("Renewal is %s, not %s", a.Status, models.RiskAcceptanceRequested)))
+		return
+	}
+
+	if !approve {
+		res, err := database.DB.Exec(`
+			UPDATE risk_acceptances SET status = 'rejected', decided_by = $1, decided_at = NOW(), decision_comments = $2
+			WHERE id = $3 AND status = 'requested'
+		`, userID, req.Comments, acceptanceID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to reject acceptance renewal")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+			return
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", "Renewal was changed by someone else"))
+			return
+		}
+		middleware.LogAudit(c, "risk_acceptance.renewal_rejected", "risk", &riskID, map[string]interface{}{
+			"acceptance_id": acceptanceID,
+		})
+		respondRiskAcceptance(c, acceptanceID, riskID, orgID)
+		return
+	}
+
+	// Renewing a risk above tolerance needs CISO sign-off, as accepting it does
+	gate, err := loadRiskToleranceGate(riskID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get risk appetite position")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	if reason := gate.cisoApprovalReason(userRole, "renew this acceptance"); reason != "" {
+		c.JSON(http.StatusForbidden, errorResponse("CISO_APPROVAL_REQUIRED", reason))
+		return
+	}
+	position, residual := gate.Position, gate.Residual
+	var appetitePosition *string
+	if position != "" {
+		appetitePosition = &position
+	}
+
+	now := time.Now()
+	tx, err := database.DB.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	defer tx.Rollback()
+
+	// Each statement is guarded by the state it expects, so a renewal racing the expiry sweep or
+	// a status change fails instead of reviving an acceptance that has ended.
+	steps := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`UPDATE risk_acceptances SET status = 'superseded', ended_by = $1, ended_at = $2
+			WHERE risk_id = $3 AND status = 'active'`, []interface{}{userID, now, riskID}},
+		{`UPDATE risk_acceptances SET status = 'active', decided_by = $1, decided_at = $2, decision_comments = $3,
+				appetite_position = $4, residual_score = $5
+			WHERE id = $6 AND status = 'requested'`, []interface{}{userID, now, req.Comments, appetitePosition, residual, acceptanceID}},
+		{`UPDATE risks SET accepted_at = $1, accepted_by = $2, acceptance_justification = $3,
+				acceptance_expiry = $4, updated_at = $1
+			WHERE id = $5 AND org_id = $6 AND status = 'accepted'`, []interface{}{now, userID, a.Justification, a.ExpiresAt, riskID, orgID}},
+	}
+	for i, s := range steps {
+		res, err := tx.Exec(s.query, s.args...)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to approve acceptance renewal")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+			return
+		}
+		// Risks accepted before the history existed may have no acceptance to supersede
+		if n, _ := res.RowsAffected(); n == 0 && (i > 0 || a.RenewalOf != nil) {
+			c.JSON(http.StatusConflict, errorResponse("INVALID_STATE", "The acceptance being renewed is no longer in force"))
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit acceptance renewal")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+
+	middleware.LogAudit(c, "risk_acceptance.renewed", "risk", &riskID, map[string]interface{}{
+		"acceptance_id": acceptanceID, "renewal_of": a.RenewalOf,
+		"expires_at": a.ExpiresAt.Format("2006-01-02"), "appetite_position": position,
+	})
+	respondRiskAcceptance(c, acceptanceID, riskID, orgID)
+}
+
+func respondRiskAcceptance(c *gin.Context, acceptanceID, riskID, orgID string) {
+	a, err := getRiskAcceptance(acceptanceID, riskID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reload risk acceptance")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Internal server error"))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, a))
+}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRiskAcceptanceRouter(userID, role string) (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, userID)
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, role)
+		c.Next()
+	})
+	protected.GET("/risks/:id/acceptances", ListRiskAcceptances)
+	protected.POST("/risks/:id/acceptances", RequestRiskAcceptanceRenewal)
+	protected.POST("/risks/:id/acceptances/:acceptanceId/approve", ApproveRiskAcceptanceRenewal)
+	protected.POST("/risks/:id/acceptances/:acceptanceId/reject", RejectRiskAcceptanceRenewal)
+	protected.PUT("/risks/:id/status", ChangeRiskStatus)
+	return router, mock
+}
+
+var riskAcceptanceRowColumns = []string{
+	"id", "risk_id", "renewal_of", "status", "justification", "expires_at", "requested_by",
+	"requested_at", "decided_by", "decided_at", "decision_comments", "appetite_position",
+	"residual_score", "ended_by", "ended_at", "created_at", "updated_at",
+}
+
+func riskAcceptanceRow(id string, renewalOf interface{}, status, requestedBy string, decidedBy interface{}, expires time.Time) []driver.Value {
+	now := time.Now()
+	return []driver.Value{
+		id, "risk-001", renewalOf, status, "Compensating monitoring in place", expires, requestedBy,
+		now, decidedBy, nil, nil, nil, nil, nil, nil, now, now,
+	}
+}
+
+func TestListRiskAcceptances(t *testing.T) {
+	router, mock := setupRiskAcceptanceRouter("user-001", models.RoleAuditor)
+	expires := time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM risks").
+		WithArgs("risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("FROM risk_acceptances a\\s+WHERE a.risk_id = \\$1").
+		WithArgs("risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows(riskAcceptanceRowColumns).
+			AddRow(riskAcceptanceRow("acc-002", "acc-001", "requested", "user-002", nil, expires.AddDate(1, 0, 0))...).
+			AddRow(riskAcceptanceRow("acc-001", nil, "active", "user-003", "user-003", expires)...))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risks/risk-001/acceptances", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	history := resp["data"].([]interface{})
+	require.Len(t, history, 2)
+	assert.Equal(t, "acc-001", history[0].(map[string]interface{})["renewal_of"])
+	assert.Equal(t, "active", history[1].(map[string]interface{})["status"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func renewalRequest(body string) *http.Request {
+	req, _ := http.NewRequest("POST", "/api/v1/risks/risk-001/acceptances", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestRequestRiskAcceptanceRenewal_Success(t *testing.T) {
+	router, mock := setupRiskAcceptanceRouter("user-002", models.RoleDevOpsEngineer)
+	current := time.Now().UTC().AddDate(0, 0, 10).Truncate(24 * time.Hour)
+	renewTo := current.AddDate(0, 6, 0)
+
+	mock.ExpectQuery("SELECT status, owner_id, acceptance_expiry FROM risks").
+		WithArgs("risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "owner_id", "acceptance_expiry"}).
+			AddRow("accepted", "user-002", current))
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM risk_acceptances").
+		WithArgs("risk-001").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery("INSERT INTO risk_acceptances").
+		WithArgs("org-001", "risk-001", "Vendor patch slipped to Q3", renewTo, "user-002").
+		WillReturnRows(sqlmock.NewRows(riskAcceptanceRowColumns).
+			AddRow(riskAcceptanceRow("acc-002", "acc-001", "requested", "user-002", nil, renewTo)...))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, renewalRequest(`{"justification": "Vendor patch slipped to Q3", "acceptance_expiry": "`+
+		renewTo.Format("2006-01-02")+`"}`))
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"renewal_of":"acc-001"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRequestRiskAcceptanceRenewal_Rejected(t *testing.T) {
+	current := time.Now().UTC().AddDate(0, 0, 10).Truncate(24 * time.Hour)
+	later := current.AddDate(0, 6, 0).Format("2006-01-02")
+
+	cases := []struct {
+		name    string
+		status  string
+		expiry  string
+		pending bool
+		code    int
+	}{
+		{"not accepted", "treating", later, false, http.StatusConflict},
+		{"not extended", "accepted", current.Format("2006-01-02"), false, http.StatusBadRequest},
+		{"already pending", "accepted", later, true, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		router, mock := setupRiskAcceptanceRouter("user-002", models.RoleDevOpsEngineer)
+		mock.ExpectQuery("SELECT status, owner_id, acceptance_expiry FROM risks").
+			WillReturnRows(sqlmock.NewRows([]string{"status", "owner_id", "acceptance_expiry"}).
+				AddRow(tc.status, "user-002", current))
+		if tc.pending {
+			mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM risk_acceptances").
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, renewalRequest(`{"justification": "Still waiting", "acceptance_expiry": "`+tc.expiry+`"}`))
+		assert.Equal(t, tc.code, w.Code, tc.name)
+		assert.NoError(t, mock.ExpectationsWereMet(), tc.name)
+	}
+}
+
+func TestApproveRiskAcceptanceRenewal_Success(t *testing.T) {
+	router, mock := setupRiskAcceptanceRouter("user-003", models.RoleComplianceManager)
+	expires := time.Date(2027, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FROM risk_acceptances a\\s+WHERE a.id = \\$1").
+		WithArgs("acc-002", "risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows(riskAcceptanceRowColumns).
+			AddRow(riskAcceptanceRow("acc-002", "acc-001", "requested", "user-002", nil, expires)...))
+	mock.ExpectQuery("FROM risk_appetite_positions ap").
+		WithArgs("risk-001").
+		WillReturnRows(sqlmock.NewRows([]string{"position", "residual_score", "inherent_score", "tolerance_threshold"}).
+			AddRow("above_appetite", 12.0, 20.0, 15.0))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE risk_acceptances SET status = 'superseded'").
+		WithArgs("user-003", sqlmock.AnyArg(), "risk-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE risk_acceptances SET status = 'active'").
+		WithArgs("user-003", sqlmock.AnyArg(), nil, "above_appetite", 12.0, "acc-002").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE risks SET accepted_at = \\$1").
+		WithArgs(sqlmock.AnyArg(), "user-003", "Compensating monitoring in place", expires, "risk-001", "org-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("FROM risk_acceptances a\\s+WHERE a.id = \\$1").
+		WithArgs("acc-002", "risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows(riskAcceptanceRowColumns).
+			AddRow(riskAcceptanceRow("acc-002", "acc-001", "active", "user-002", "user-003", expires)...))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/risks/risk-001/acceptances/acc-002/approve", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"status":"active"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApproveRiskAcceptanceRenewal_AcceptanceEnded(t *testing.T) {
+	router, mock := setupRiskAcceptanceRouter("user-003", models.RoleCISO)
+	expires := time.Date(2027, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FROM risk_acceptances a\\s+WHERE a.id = \\$1").
+		WillReturnRows(sqlmock.NewRows(riskAcceptanceRowColumns).
+			AddRow(riskAcceptanceRow("acc-002", "acc-001", "requested", "user-002", nil, expires)...))
+	mock.ExpectQuery("FROM risk_appetite_positions ap").
+		WillReturnRows(sqlmock.NewRows([]string{"position", "residual_score", "inherent_score", "tolerance_threshold"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE risk_acceptances SET status = 'superseded'").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/risks/risk-001/acceptances/acc-002/approve", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDecideRiskAcceptanceRenewal_Forbidden(t *testing.T) {
+	expires := time.Date(2027, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	// Requesters never decide their own renewal
+	router, mock := setupRiskAcceptanceRouter("user-002", models.RoleComplianceManager)
+	mock.ExpectQuery("FROM risk_acceptances a\\s+WHERE a.id = \\$1").
+		WillReturnRows(sqlmock.NewRows(riskAcceptanceRowColumns).
+			AddRow(riskAcceptanceRow("acc-002", "acc-001", "requested", "user-002", nil, expires)...))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/risks/risk-001/acceptances/acc-002/approve", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Renewals above tolerance need the CISO
+	router, mock = setupRiskAcceptanceRouter("user-003", models.RoleComplianceManager)
+	mock.ExpectQuery("FROM risk_acceptances a\\s+WHERE a.id = \\$1").
+		WillReturnRows(sqlmock.NewRows(riskAcceptanceRowColumns).
+			AddRow(riskAcceptanceRow("acc-002", "acc-001", "requested", "user-002", nil, expires)...))
+	mock.ExpectQuery("FROM risk_appetite_positions ap").
+		WillReturnRows(sqlmock.NewRows([]string{"position", "residual_score", "inherent_score", "tolerance_threshold"}).
+			AddRow("above_tolerance", 20.0, 25.0, 15.0))
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/risks/risk-001/acceptances/acc-002/approve", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "CISO_APPROVAL_REQUIRED")
+
+	// Rejections need comments
+	router, _ = setupRiskAcceptanceRouter("user-003", models.RoleCISO)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/risks/risk-001/acceptances/acc-002/reject", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func reopenRiskRequest() *http.Request {
+	req, _ := http.NewRequest("PUT", "/api/v1/risks/risk-001/status", bytes.NewBufferString(`{"status": "open"}`))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestChangeRiskStatus_LeavingAcceptedRevokesAcceptance(t *testing.T) {
+	router, mock := setupRiskAcceptanceRouter("user-003", models.RoleComplianceManager)
+
+	mock.ExpectQuery("SELECT status, owner_id").
+		WithArgs("risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "owner_id"}).AddRow("accepted", "user-002"))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE risks SET status = \\$1").
+		WithArgs("open", sqlmock.AnyArg(), "risk-001", "org-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE risks SET accepted_at = NULL").
+		WithArgs("risk-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE risk_acceptances SET status = CASE").
+		WithArgs("risk-001", "user-003", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT identifier FROM risks").
+		WillReturnRows(sqlmock.NewRows([]string{"identifier"}).AddRow("RISK-001"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, reopenRiskRequest())
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChangeRiskStatus_RevocationFailureRollsBack(t *testing.T) {
+	router, mock := setupRiskAcceptanceRouter("user-003", models.RoleComplianceManager)
+
+	mock.ExpectQuery("SELECT status, owner_id").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "owner_id"}).AddRow("accepted", "user-002"))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE risks SET status = \\$1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE risks SET accepted_at = NULL").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE risk_acceptances SET status = CASE").
+		WillReturnError(errors.New("deadlock detected"))
+	mock.ExpectRollback()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, reopenRiskRequest())
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -15,16 +15,27 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// GetRiskHeatMap returns aggregated risk data for the 5×5 heat map.
+// GetRiskHeatMap returns aggregated risk data for the likelihood × impact heat map. The grid
+// follows the org's active scoring methodology; risks scored on levels the active methodology
+// no longer has are reported as unplotted rather than dropped.
 func GetRiskHeatMap(c *gin.Context) {
 	orgID := middleware.GetOrgID(c)
 
 	scoreType := c.DefaultQuery("score_type", "residual")
 	likelihoodCol := "residual_likelihood"
 	impactCol := "residual_impact"
+	scoreCol := "residual_score"
 	if scoreType == "inherent" {
 		likelihoodCol = "inherent_likelihood"
 		impactCol = "inherent_impact"
+		scoreCol = "inherent_score"
+	}
+
+	method, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get heat map data"))
+		return
 	}
 
 	where := []string{
@@ -55,11 +66,11 @@ func GetRiskHeatMap(c *gin.Context) {
 
 	// Build the grid: query risks grouped by likelihood × impact
 	query := fmt.Sprintf(`
-		SELECT %s, %s, id, identifier, title, status
+		SELECT %s, %s, %s, id, identifier, title, status
 		FROM risks
 		WHERE %s AND %s IS NOT NULL AND %s IS NOT NULL
-		ORDER BY %s DESC, %s DESC
-	`, likelihoodCol, impactCol, whereClause, likelihoodCol, impactCol, likelihoodCol, impactCol)
+		ORDER BY %s DESC NULLS LAST, identifier
+	`, likelihoodCol, impactCol, scoreCol, whereClause, likelihoodCol, impactCol, scoreCol)
 
 	rows, err := database.DB.Query(query, args...)
 	if err != nil {
@@ -79,57 +90,73 @@ func GetRiskHeatMap(c *gin.Context) {
 	bySeverity := map[string]int{"critical": 0, "high": 0, "medium": 0, "low": 0}
 	var totalScore float64
 	appetiteBreaches := 0
+	unplotted := []gin.H{}
 
 	for rows.Next() {
 		var likelihood, impact, id, identifier, title, status string
-		if err := rows.Scan(&likelihood, &impact, &id, &identifier, &title, &status); err != nil {
+		var storedScore *float64
+		if err := rows.Scan(&likelihood, &impact, &storedScore, &id, &identifier, &title, &status); err != nil {
 			continue
 		}
-		key := cellKey{likelihood, impact}
-		cellRisks[key] = append(cellRisks[key], gin.H{
-			"id": id, "identifier": identifier, "title": title, "status": status,
-		})
+		risk := gin.H{"id": id, "identifier": identifier, "title": title, "status": status}
+
+		// Stored scores keep whatever weighting the assessment was made with
+		score := float64(method.LikelihoodScore(likelihood) * method.ImpactScore(impact))
+		if storedScore != nil {
+			score = *storedScore
+		}
+		if method.IsValidLikelihood(likelihood) && method.IsValidImpact(impact) {
+			key := cellKey{likelihood, impact}
+			cellRisks[key] = append(cellRisks[key], risk)
+		} else {
+			risk["likelihood"] = likelihood
+			risk["impact"] = impact
+			risk["score"] = score
+			unplotted = append(unplotted, risk)
+		}
 
-		score := float64(models.LikelihoodScore(likelihood) * models.ImpactScore(impact))
-		severity := models.ScoreSeverity(score)
+		severity := method.Severity(score)
 		bySeverity[severity]++
 		totalScore += score
 		totalRisks++
 	}
 
-	// Check appetite breaches separately
+	// Check appetite breaches separately (effective appetite: risk, category or org)
 	database.DB.QueryRow(fmt.Sprintf(`
 		SELECT COUNT(*) FROM risks
-		WHERE %s AND residual_score IS NOT NULL AND risk_appetite_threshold IS NOT NULL AND residual_score > risk_appetite_threshold
+		WHERE %s AND id IN (
+			SELECT risk_id FROM risk_appetite_positions WHERE position IN ('above_appetite', 'above_tolerance')
+		)
 	`, whereClause), args[:argN-1]...).Scan(&appetiteBreaches)
 
-	// Build full 25-cell grid
-	likelihoods := models.ValidLikelihoodLevels
-	impacts := models.ValidImpactLevels
+	// Build the full grid, most severe corner first
+	likelihoods := method.LikelihoodLevels
+	impacts := method.ImpactLevels
 	grid := []gin.H{}
 
 	for li := len(likelihoods) - 1; li >= 0; li-- {
 		for ii := len(impacts) - 1; ii >= 0; ii-- {
 			l := likelihoods[li]
 			i := impacts[ii]
-			lScore := models.LikelihoodScore(l)
-			iScore := models.ImpactScore(i)
-			score := lScore * iScore
-			severity := models.ScoreSeverity(float64(score))
+			score := l.Score * i.Score
+			band := method.Band(float64(score))
 
-			key := cellKey{l, i}
+			key := cellKey{l.Key, i.Key}
 			risks := cellRisks[key]
 			if risks == nil {
 				risks = []gin.H{}
 			}
 
 			grid = append(grid, gin.H{
-				"likelihood":       l,
-				"likelihood_score": lScore,
-				"impact":           i,
-				"impact_score":     iScore,
+				"likelihood":       l.Key,
+				"likelihood_label": l.Label,
+				"likelihood_score": l.Score,
+				"impact":           i.Key,
+				"impact_label":     i.Label,
+				"impact_score":     i.Score,
 				"score":            score,
-				"severity":         severity,
+				"severity":         band.Severity,
+				"severity_label":   band.Label,
 				"count":            len(risks),
 				"risks":            risks,
 			})
@@ -142,13 +169,16 @@ func GetRiskHeatMap(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, successResponse(c, gin.H{
-		"score_type": scoreType,
-		"grid":       grid,
+		"score_type":  scoreType,
+		"methodology": gin.H{"id": method.ID, "version": method.Version, "name": method.Name},
+		"grid":        grid,
+		"unplotted":   unplotted,
 		"summary": gin.H{
 			"total_risks":       totalRisks,
 			"by_severity":       bySeverity,
 			"average_score":     avgScore,
 			"appetite_breaches": appetiteBreaches,
+			"unplotted":         len(unplotted),
 		},
 	}))
 }
@@ -169,6 +199,15 @@ func GetRiskGaps(c *gin.Context) {
 	gapType := c.DefaultQuery("gap_type", "all")
 	minSeverity := c.Query("min_severity")
 
+	method, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get gaps"))
+		return
+	}
+	// "High" means the high band or above
+	highCond, _ := severityScoreCondition(method, "high", "r.residual_score", true)
+
 	// Summary counts
 	var totalActive, noTreatments, noControls, highNoControls, overdueAssess, expiredAccept int
 
@@ -188,12 +227,12 @@ func GetRiskGaps(c *gin.Context) {
 		AND (SELECT COUNT(*) FROM risk_controls rc WHERE rc.risk_id = r.id) = 0
 	`, orgID).Scan(&noControls)
 
-	database.DB.QueryRow(`
+	database.DB.QueryRow(fmt.Sprintf(`
 		SELECT COUNT(*) FROM risks r WHERE r.org_id = $1 AND r.is_template = FALSE
 		AND r.status NOT IN ('closed','archived')
-		AND r.residual_score >= 12
+		AND %s
 		AND (SELECT COUNT(*) FROM risk_controls rc WHERE rc.risk_id = r.id) = 0
-	`, orgID).Scan(&highNoControls)
+	`, highCond), orgID).Scan(&highNoControls)
 
 	database.DB.QueryRow(`
 		SELECT COUNT(*) FROM risks WHERE org_id = $1 AND is_template = FALSE
@@ -217,13 +256,8 @@ func GetRiskGaps(c *gin.Context) {
 
 	// Filter by min severity
 	if minSeverity != "" {
-		switch minSeverity {
-		case "critical":
-			gapWhere = append(gapWhere, "r.residual_score >= 20")
-		case "high":
-			gapWhere = append(gapWhere, "r.residual_score >= 12")
-		case "medium":
-			gapWhere = append(gapWhere, "r.residual_score >= 6")
+		if cond, ok := severityScoreCondition(method, minSeverity, "r.residual_score", true); ok && cond != "" {
+			gapWhere = append(gapWhere, cond)
 		}
 	}
 
@@ -235,7 +269,7 @@ func GetRiskGaps(c *gin.Context) {
 	case "no_controls":
 		gapConditions = append(gapConditions, "(SELECT COUNT(*) FROM risk_controls rc WHERE rc.risk_id = r.id) = 0")
 	case "high_without_controls":
-		gapConditions = append(gapConditions, "r.residual_score >= 12 AND (SELECT COUNT(*) FROM risk_controls rc WHERE rc.risk_id = r.id) = 0")
+		gapConditions = append(gapConditions, highCond+" AND (SELECT COUNT(*) FROM risk_controls rc WHERE rc.risk_id = r.id) = 0")
 	case "overdue_assessment":
 		gapConditions = append(gapConditions, "r.next_assessment_at IS NOT NULL AND r.next_assessment_at < NOW()")
 	case "expired_acceptance":
@@ -301,7 +335,7 @@ func GetRiskGaps(c *gin.Context) {
 
 		severity := "low"
 		if residualScore != nil {
-			severity = models.ScoreSeverity(*residualScore)
+			severity = method.Severity(*residualScore)
 		}
 
 		// Determine gap types
@@ -312,7 +346,7 @@ func GetRiskGaps(c *gin.Context) {
 		if ctrlCount == 0 {
 			gapTypes = append(gapTypes, "no_controls")
 		}
-		if residualScore != nil && *residualScore >= 12 && ctrlCount == 0 {
+		if residualScore != nil && (severity == "critical" || severity == "high") && ctrlCount == 0 {
 			gapTypes = append(gapTypes, "high_without_controls")
 		}
 		if nextAssess != nil && nextAssess.Before(time.Now()) {
@@ -434,6 +468,13 @@ func SearchRisks(c *gin.Context) {
 		perPage = 20
 	}
 
+	method, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to search risks"))
+		return
+	}
+
 	where := []string{"r.org_id = $1", "r.is_template = FALSE"}
 	args := []interface{}{orgID}
 	argN := 2
@@ -454,15 +495,8 @@ func SearchRisks(c *gin.Context) {
 		argN++
 	}
 	if v := c.Query("severity"); v != "" {
-		switch v {
-		case "critical":
-			where = append(where, "r.residual_score >= 20")
-		case "high":
-			where = append(where, "r.residual_score >= 12 AND r.residual_score < 20")
-		case "medium":
-			where = append(where, "r.residual_score >= 6 AND r.residual_score < 12")
-		case "low":
-			where = append(where, "r.residual_score < 6")
+		if cond, ok := severityScoreCondition(method, v, "r.residual_score", false); ok && cond != "" {
+			where = append(where, cond)
 		}
 	}
 
@@ -509,7 +543,7 @@ func SearchRisks(c *gin.Context) {
 
 		severity := "low"
 		if residualScore != nil {
-			severity = models.ScoreSeverity(*residualScore)
+			severity = method.Severity(*residualScore)
 		}
 
 		// Generate match context
@@ -609,19 +643,19 @@ func GetRiskStats(c *gin.Context) {
 		}
 	}
 
-	// By severity (from residual score)
+	// By severity (from residual score, using the active methodology's bands)
+	method, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get risk stats"))
+		return
+	}
 	bySeverity := map[string]int{"critical": 0, "high": 0, "medium": 0, "low": 0}
-	sevRows, _ := database.DB.Query(`
-		SELECT
-			CASE
-				WHEN residual_score >= 20 THEN 'critical'
-				WHEN residual_score >= 12 THEN 'high'
-				WHEN residual_score >= 6 THEN 'medium'
-				ELSE 'low'
-			END as severity, COUNT(*)
+	sevRows, _ := database.DB.Query(fmt.Sprintf(`
+		SELECT %s as severity, COUNT(*)
 		FROM risks WHERE org_id = $1 AND is_template = FALSE AND residual_score IS NOT NULL
 		GROUP BY severity
-	`, orgID)
+	`, severityCaseExpr(method, "residual_score")), orgID)
 	if sevRows != nil {
 		defer sevRows.Close()
 		for sevRows.Next() {
@@ -648,7 +682,7 @@ func GetRiskStats(c *gin.Context) {
 	var highestRisk interface{}
 	var hID, hIdentifier, hTitle string
 	var hScore float64
-	err := database.DB.QueryRow(`
+	err = database.DB.QueryRow(`
 		SELECT id, identifier, title, residual_score FROM risks
 		WHERE org_id = $1 AND is_template = FALSE AND residual_score IS NOT NULL
 		ORDER BY residual_score DESC LIMIT 1
@@ -656,7 +690,7 @@ func GetRiskStats(c *gin.Context) {
 	if err == nil {
 		highestRisk = gin.H{
 			"id": hID, "identifier": hIdentifier, "title": hTitle,
-			"score": hScore, "severity": models.ScoreSeverity(hScore),
+			"score": hScore, "severity": method.Severity(hScore),
 		}
 	}
 
@@ -728,22 +762,15 @@ func GetRiskStats(c *gin.Context) {
 		AND acceptance_expiry IS NOT NULL AND acceptance_expiry < NOW()
 	`, orgID).Scan(&expiredAccept)
 
-	// Appetite summary
+	// Appetite summary (effective appetite: risk, category or org)
 	var withinAppetite, breaching, noThreshold int
 	database.DB.QueryRow(`
-		SELECT COUNT(*) FROM risks WHERE org_id = $1 AND is_template = FALSE
-		AND status NOT IN ('closed','archived') AND risk_appetite_threshold IS NOT NULL
-		AND (residual_score IS NULL OR residual_score <= risk_appetite_threshold)
-	`, orgID).Scan(&withinAppetite)
-	database.DB.QueryRow(`
-		SELECT COUNT(*) FROM risks WHERE org_id = $1 AND is_template = FALSE
-		AND status NOT IN ('closed','archived') AND risk_appetite_threshold IS NOT NULL
-		AND residual_score IS NOT NULL AND residual_score > risk_appetite_threshold
-	`, orgID).Scan(&breaching)
-	database.DB.QueryRow(`
-		SELECT COUNT(*) FROM risks WHERE org_id = $1 AND is_template = FALSE
-		AND status NOT IN ('closed','archived') AND risk_appetite_threshold IS NULL
-	`, orgID).Scan(&noThreshold)
+		SELECT COUNT(*) FILTER (WHERE position IN ('within_appetite', 'not_assessed')),
+		       COUNT(*) FILTER (WHERE position IN ('above_appetite', 'above_tolerance')),
+		       COUNT(*) FILTER (WHERE position = 'no_appetite')
+		FROM risk_appetite_positions
+		WHERE org_id = $1 AND status NOT IN ('closed','archived')
+	`, orgID).Scan(&withinAppetite, &breaching, &noThreshold)
 
 	// Templates count
 	var templatesAvailable int
@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+const riskAppetiteColumns = `a.id, a.org_id, a.category, a.appetite_threshold, a.tolerance_threshold,
+	a.statement, a.updated_by, a.created_at, a.updated_at`
+
+func scanRiskAppetite(s rowScanner) (*models.RiskAppetiteStatement, error) {
+	var a models.RiskAppetiteStatement
+	err := s.Scan(&a.ID, &a.OrgID, &a.Category, &a.AppetiteThreshold, &a.ToleranceThreshold,
+		&a.Statement, &a.UpdatedBy, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// isAppetiteBreach reports whether a risk_appetite_positions position is above appetite.
+func isAppetiteBreach(position *string) bool {
+	return position != nil &&
+		(*position == models.AppetitePositionAboveAppetite || *position == models.AppetitePositionAboveTolerance)
+}
+
+// riskToleranceGate is where a risk stands against its tolerance when it is accepted.
+type riskToleranceGate struct {
+	Position  string
+	Residual  *float64
+	Inherent  *float64
+	Tolerance *float64
+}
+
+// loadRiskToleranceGate reads a risk's appetite position and scores. Template risks have no
+// position, which leaves Position empty.
+func loadRiskToleranceGate(riskID string) (*riskToleranceGate, error) {
+	var g riskToleranceGate
+	err := database.DB.QueryRow(`
+		SELECT ap.position, ap.residual_score, r.inherent_score, ap.tolerance_threshold
+		FROM risk_appetite_positions ap
+		JOIN risks r ON r.id = ap.risk_id
+		WHERE ap.risk_id = $1
+	`, riskID).Scan(&g.Position, &g.Residual, &g.Inherent, &g.Tolerance)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// cisoApprovalReason explains why only the CISO may take action ("accept this risk") on the
+// risk, or returns "" when the role may. A risk without a residual assessment is judged on its
+// inherent score, so skipping the assessment does not skip the gate.
+func (g *riskToleranceGate) cisoApprovalReason(userRole, action string) string {
+	if models.HasRole(userRole, models.RiskAcceptAboveToleranceRoles) {
+		return ""
+	}
+	switch {
+	case g.Position == models.AppetitePositionAboveTolerance:
+		return fmt.Sprintf("Residual score %g is above the risk tolerance of %g; only the CISO can %s",
+			*g.Residual, *g.Tolerance, action)
+	case g.Position == models.AppetitePositionNotAssessed && g.Inherent != nil && g.Tolerance != nil && *g.Inherent > *g.Tolerance:
+		return fmt.Sprintf("Risk has no residual assessment and its inherent score %g is above the risk tolerance of %g; only the CISO can %s",
+			*g.Inherent, *g.Tolerance, action)
+	}
+	return ""
+}
+
+// appetiteObject renders a risk's effective appetite from risk_appetite_positions.
+func appetiteObject(threshold, tolerance *float64, source, position *string) interface{} {
+	if position == nil {
+		return nil
+	}
+	return gin.H{
+		"threshold": threshold,
+		"tolerance": tolerance,
+		"source":    source,
+		"position":  *position,
+	}
+}
+
+// listRiskAppetites returns the org statement (if any) first, then category statements.
+func listRiskAppetites(orgID string) ([]*models.RiskAppetiteStatement, error) {
+	rows, err := database.DB.Query(`
+		SELECT `+riskAppetiteColumns+`
+		FROM risk_appetite_statements a
+		WHERE a.org_id = $1
+		ORDER BY a.category NULLS FIRST
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statements := []*models.RiskAppetiteStatement{}
+	for rows.Next() {
+		a, err := scanRiskAppetite(rows)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, a)
+	}
+	return statements, rows.Err()
+}
+
+// ListRiskAppetite returns the organization's risk appetite statement and category overrides.
+func ListRiskAppetite(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	statements, err := listRiskAppetites(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list risk appetite")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to list risk appetite"))
+		return
+	}
+
+	var organization interface{}
+	categories := []*models.RiskAppetiteStatement{}
+	for _, a := range statements {
+		if a.Category == nil {
+			organization = a
+		} else {
+			categories = append(categories, a)
+		}
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"organization": organization,
+		"categories":   categories,
+	}))
+}
+
+// SetRiskAppetite creates or replaces the appetite and tolerance for the organization (no
+// category) or for one risk category.
+func SetRiskAppetite(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	var req models.SetRiskAppetiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request: "+err.Error()))
+		return
+	}
+	if req.Category != nil && !models.IsValidRiskCategory(*req.Category) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid risk category"))
+		return
+	}
+
+	method, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to set risk appetite"))
+		return
+	}
+	appetite, tolerance := *req.AppetiteThreshold, *req.ToleranceThreshold
+	for _, v := range []float64{appetite, tolerance} {
+		if math.IsNaN(v) || v < 1 || v > method.MaxScore() {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+				fmt.Sprintf("Appetite and tolerance thresholds must be between 1 and %g", method.MaxScore())))
+			return
+		}
+	}
+	if tolerance < appetite {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Tolerance threshold must not be below the appetite threshold"))
+		return
+	}
+
+	// The partial unique indexes need the matching predicate for ON CONFLICT to infer them.
+	conflict := "(org_id) WHERE category IS NULL"
+	if req.Category != nil {
+		conflict = "(org_id, category) WHERE category IS NOT NULL"
+	}
+	a, err := scanRiskAppetite(database.DB.QueryRow(`
+		INSERT INTO risk_appetite_statements AS a
+			(org_id, category, appetite_threshold, tolerance_threshold, statement, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT `+conflict+` DO UPDATE SET
+			appetite_threshold = EXCLUDED.appetite_threshold,
+			tolerance_threshold = EXCLUDED.tolerance_threshold,
+			statement = EXCLUDED.statement,
+			updated_by = EXCLUDED.updated_by
+		RETURNING `+riskAppetiteColumns,
+		orgID, req.Category, appetite, tolerance, req.Statement, userID))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to set risk appetite")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to set risk appetite"))
+		return
+	}
+
+	middleware.LogAudit(c, "risk_appetite.updated", "risk_appetite", &a.ID, map[string]interface{}{
+		"category":            req.Category,
+		"appetite_threshold":  appetite,
+		"tolerance_threshold": tolerance,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, a))
+}
+
+// DeleteRiskAppetite removes an appetite statement; risks in that category fall back to the
+// organization's appetite.
+func DeleteRiskAppetite(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	id := c.Param("id")
+
+	var category *string
+	err := database.DB.QueryRow(`
+		DELETE FROM risk_appetite_statements WHERE id = $1 AND org_id = $2 RETURNING category
+	`, id, orgID).Scan(&category)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk appetite statement not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete risk appetite")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to delete risk appetite"))
+		return
+	}
+
+	middleware.LogAudit(c, "risk_appetite.deleted", "risk_appetite", &id, map[string]interface{}{
+		"category": category,
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetRiskAppetiteUtilization reports, per risk category, how much of the appetite open risks
+// use (residual score / effective appetite) and lists every risk above appetite.
+func GetRiskAppetiteUtilization(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	statements, err := listRiskAppetites(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list risk appetite")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get appetite utilization"))
+		return
+	}
+	var organization *models.RiskAppetiteStatement
+	byCategory := map[string]*models.RiskAppetiteStatement{}
+	for _, a := range statements {
+		if a.Category == nil {
+			organization = a
+		} else {
+			byCategory[*a.Category] = a
+		}
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT p.category,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE p.position = 'within_appetite'),
+		       COUNT(*) FILTER (WHERE p.position = 'above_appetite'),
+		       COUNT(*) FILTER (WHERE p.position = 'above_tolerance'),
+		       COUNT(*) FILTER (WHERE p.position = 'not_assessed'),
+		       COUNT(*) FILTER (WHERE p.position = 'no_appetite'),
+		       AVG(p.residual_score / p.appetite_threshold) FILTER (WHERE p.residual_score IS NOT NULL AND p.appetite_threshold IS NOT NULL),
+		       MAX(p.residual_score / p.appetite_threshold) FILTER (WHERE p.residual_score IS NOT NULL AND p.appetite_threshold IS NOT NULL)
+		FROM risk_appetite_positions p
+		WHERE p.org_id = $1 AND p.status NOT IN ('closed', 'archived')
+		GROUP BY p.category
+		ORDER BY p.category
+	`, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to query appetite utilization")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get appetite utilization"))
+		return
+	}
+	defer rows.Close()
+
+	categories := []gin.H{}
+	totals := map[string]int{}
+	for rows.Next() {
+		var (
+			category                                                 string
+			total, within, aboveAppetite, aboveTolerance, unassessed int
+			noAppetite                                               int
+			avgUse, peakUse                                          *float64
+		)
+		if err := rows.Scan(&category, &total, &within, &aboveAppetite, &aboveTolerance, &unassessed, &noAppetite,
+			&avgUse, &peakUse); err != nil {
+			log.Error().Err(err).Msg("Failed to scan appetite utilization row")
+			continue
+		}
+		appetite := byCategory[category]
+		source := models.AppetiteSourceCategory
+		if appetite == nil {
+			appetite, source = organization, models.AppetiteSourceOrganization
+		}
+		var appetiteObj interface{}
+		if appetite != nil {
+			appetiteObj = gin.H{
+				"threshold": appetite.AppetiteThreshold,
+				"tolerance": appetite.ToleranceThreshold,
+				"source":    source,
+			}
+		}
+
+		totals["risks"] += total
+		totals[models.AppetitePositionWithin] += within
+		totals[models.AppetitePositionAboveAppetite] += aboveAppetite
+		totals[models.AppetitePositionAboveTolerance] += aboveTolerance
+		categories = append(categories, gin.H{
+			"category":                            category,
+			"appetite":                            appetiteObj,
+			"risks":                               total,
+			models.AppetitePositionWithin:         within,
+			models.AppetitePositionAboveAppetite:  aboveAppetite,
+			models.AppetitePositionAboveTolerance: aboveTolerance,
+			models.AppetitePositionNotAssessed:    unassessed,
+			models.AppetitePositionNoAppetite:     noAppetite,
+			"average_utilization":                 roundPtr(avgUse),
+			"peak_utilization":                    roundPtr(peakUse),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to read appetite utilization")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get appetite utilization"))
+		return
+	}
+
+	breachRows, err := database.DB.Query(`
+		SELECT p.risk_id, r.identifier, r.title, p.category, p.status, r.owner_id,
+		       p.residual_score, p.appetite_threshold, p.tolerance_threshold, p.appetite_source, p.position
+		FROM risk_appetite_positions p
+		JOIN risks r ON r.id = p.risk_id
+		WHERE p.org_id = $1 AND p.status NOT IN ('closed', 'archived')
+			AND p.position IN ('above_appetite', 'above_tolerance')
+		ORDER BY p.residual_score - p.appetite_threshold DESC, r.identifier ASC
+	`, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to query appetite breaches")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get appetite utilization"))
+		return
+	}
+	defer breachRows.Close()
+
+	breaches := []gin.H{}
+	for breachRows.Next() {
+		var (
+			id, identifier, title, category, status, source, position string
+			ownerID                                                   *string
+			residual, appetite, tolerance                             float64
+		)
+		if err := breachRows.Scan(&id, &identifier, &title, &category, &status, &ownerID,
+			&residual, &appetite, &tolerance, &source, &position); err != nil {
+			log.Error().Err(err).Msg("Failed to scan appetite breach row")
+			continue
+		}
+		breaches = append(breaches, gin.H{
+			"id":             id,
+			"identifier":     identifier,
+			"title":          title,
+			"category":       category,
+			"status":         status,
+			"owner_id":       ownerID,
+			"residual_score": residual,
+			"appetite":       gin.H{"threshold": appetite, "tolerance": tolerance, "source": source},
+			"position":       position,
+			"utilization":    math.Round(residual/appetite*100) / 100,
+		})
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"organization": organization,
+		"categories":   categories,
+		"breaches":     breaches,
+		"summary":      totals,
+	}))
+}
+
+func roundPtr(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	r := math.Round(*v*100) / 100
+	return &r
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRiskAppetiteRouter(role string) (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-001")
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, role)
+		c.Next()
+	})
+	protected.GET("/risk-appetite", ListRiskAppetite)
+	protected.PUT("/risk-appetite", SetRiskAppetite)
+	protected.GET("/risk-appetite/utilization", GetRiskAppetiteUtilization)
+	protected.DELETE("/risk-appetite/:id", DeleteRiskAppetite)
+	protected.PUT("/risks/:id/status", ChangeRiskStatus)
+	return router, mock
+}
+
+var riskAppetiteRowColumns = []string{
+	"id", "org_id", "category", "appetite_threshold", "tolerance_threshold",
+	"statement", "updated_by", "created_at", "updated_at",
+}
+
+func TestSetRiskAppetite_Category(t *testing.T) {
+	router, mock := setupRiskAppetiteRouter(models.RoleCISO)
+	now := time.Now()
+
+	expectDefaultRiskMethodology(mock)
+	mock.ExpectQuery("INSERT INTO risk_appetite_statements.*ON CONFLICT \\(org_id, category\\) WHERE category IS NOT NULL").
+		WithArgs("org-001", "technology", 8.0, 12.0, nil, "user-001").
+		WillReturnRows(sqlmock.NewRows(riskAppetiteRowColumns).
+			AddRow("app-001", "org-001", "technology", 8.0, 12.0, nil, "user-001", now, now))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/risk-appetite",
+		bytes.NewBufferString(`{"category": "technology", "appetite_threshold": 8, "tolerance_threshold": 12}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "technology", data["category"])
+	assert.Equal(t, 12.0, data["tolerance_threshold"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetRiskAppetite_Validation(t *testing.T) {
+	router, mock := setupRiskAppetiteRouter(models.RoleCISO)
+
+	// Thresholds are checked against the active methodology's score range
+	bodies := map[string]bool{
+		`{"category": "weather", "appetite_threshold": 8, "tolerance_threshold": 12}`: false,
+		`{"appetite_threshold": 12, "tolerance_threshold": 8}`:                        true,
+		`{"appetite_threshold": 8, "tolerance_threshold": 30}`:                        true,
+		`{"appetite_threshold": 8}`:                                                   false,
+	}
+	for body, loadsMethodology := range bodies {
+		if loadsMethodology {
+			expectDefaultRiskMethodology(mock)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/api/v1/risk-appetite", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		assert.NoError(t, mock.ExpectationsWereMet(), body)
+	}
+}
+
+func TestGetRiskAppetiteUtilization(t *testing.T) {
+	router, mock := setupRiskAppetiteRouter(models.RoleAuditor)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM risk_appetite_statements a").
+		WithArgs("org-001").
+		WillReturnRows(sqlmock.NewRows(riskAppetiteRowColumns).
+			AddRow("app-org", "org-001", nil, 10.0, 15.0, "Moderate appetite", "user-001", now, now).
+			AddRow("app-tech", "org-001", "technology", 6.0, 9.0, nil, "user-001", now, now))
+	mock.ExpectQuery("FROM risk_appetite_positions p\\s+WHERE p.org_id = \\$1").
+		WithArgs("org-001").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"category", "count", "within", "above_appetite", "above_tolerance", "not_assessed", "no_appetite", "avg", "max",
+		}).
+			AddRow("financial", 3, 2, 1, 0, 0, 0, 0.8333, 1.2).
+			AddRow("technology", 2, 0, 1, 1, 0, 0, 1.6667, 2.0))
+	mock.ExpectQuery("FROM risk_appetite_positions p\\s+JOIN risks r").
+		WithArgs("org-001").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"risk_id", "identifier", "title", "category", "status", "owner_id",
+			"residual_score", "appetite_threshold", "tolerance_threshold", "appetite_source", "position",
+		}).
+			AddRow("risk-003", "RISK-003", "Legacy VPN", "technology", "treating", "user-002", 12.0, 6.0, 9.0, "category", "above_tolerance").
+			AddRow("risk-002", "RISK-002", "Payroll fraud", "financial", "open", nil, 12.0, 10.0, 15.0, "organization", "above_appetite"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risk-appetite/utilization", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+
+	categories := data["categories"].([]interface{})
+	require.Len(t, categories, 2)
+	financial := categories[0].(map[string]interface{})
+	assert.Equal(t, "organization", financial["appetite"].(map[string]interface{})["source"])
+	assert.Equal(t, 0.83, financial["average_utilization"])
+	technology := categories[1].(map[string]interface{})
+	assert.Equal(t, "category", technology["appetite"].(map[string]interface{})["source"])
+	assert.Equal(t, float64(1), technology["above_tolerance"])
+
+	breaches := data["breaches"].([]interface{})
+	require.Len(t, breaches, 2)
+	assert.Equal(t, float64(2), breaches[0].(map[string]interface{})["utilization"])
+
+	summary := data["summary"].(map[string]interface{})
+	assert.Equal(t, float64(5), summary["risks"])
+	assert.Equal(t, float64(2), summary["above_appetite"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteRiskAppetite_NotFound(t *testing.T) {
+	router, mock := setupRiskAppetiteRouter(models.RoleCISO)
+
+	mock.ExpectQuery("DELETE FROM risk_appetite_statements").
+		WithArgs("app-404", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"category"}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/risk-appetite/app-404", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func acceptRiskRequest() *http.Request {
+	req, _ := http.NewRequest("PUT", "/api/v1/risks/risk-001/status", bytes.NewBufferString(
+		`{"status": "accepted", "justification": "Compensating monitoring in place", "acceptance_expiry": "2027-03-31"}`))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestChangeRiskStatus_AcceptAboveToleranceNeedsCISO(t *testing.T) {
+	router, mock := setupRiskAppetiteRouter(models.RoleComplianceManager)
+
+	mock.ExpectQuery("SELECT status, owner_id").
+		WithArgs("risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "owner_id"}).AddRow("open", "user-002"))
+	mock.ExpectQuery("FROM risk_appetite_positions ap").
+		WithArgs("risk-001").
+		WillReturnRows(sqlmock.NewRows([]string{"position", "residual_score", "inherent_score", "tolerance_threshold"}).
+			AddRow("above_tolerance", 20.0, 25.0, 15.0))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, acceptRiskRequest())
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "CISO_APPROVAL_REQUIRED")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChangeRiskStatus_AcceptUnassessedAboveToleranceNeedsCISO(t *testing.T) {
+	router, mock := setupRiskAppetiteRouter(models.RoleComplianceManager)
+
+	mock.ExpectQuery("SELECT status, owner_id").
+		WithArgs("risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "owner_id"}).AddRow("open", "user-002"))
+	mock.ExpectQuery("FROM risk_appetite_positions ap").
+		WithArgs("risk-001").
+		WillReturnRows(sqlmock.NewRows([]string{"position", "residual_score", "inherent_score", "tolerance_threshold"}).
+			AddRow("not_assessed", nil, 25.0, 15.0))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, acceptRiskRequest())
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "inherent score 25")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChangeRiskStatus_CISOAcceptsAboveTolerance(t *testing.T) {
+	router, mock := setupRiskAppetiteRouter(models.RoleCISO)
+
+	mock.ExpectQuery("SELECT status, owner_id").
+		WithArgs("risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "owner_id"}).AddRow("open", "user-002"))
+	mock.ExpectQuery("FROM risk_appetite_positions ap").
+		WithArgs("risk-001").
+		WillReturnRows(sqlmock.NewRows([]string{"position", "residual_score", "inherent_score", "tolerance_threshold"}).
+			AddRow("above_tolerance", 20.0, 25.0, 15.0))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE risks SET status = 'accepted'").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO risk_acceptances").
+		WithArgs("org-001", "risk-001", "Compensating monitoring in place", sqlmock.AnyArg(), "user-001",
+			sqlmock.AnyArg(), "above_tolerance", 20.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT COALESCE\\(first_name").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Carol CISO"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, acceptRiskRequest())
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	acceptance := resp["data"].(map[string]interface{})["acceptance"].(map[string]interface{})
+	assert.Equal(t, "above_tolerance", acceptance["appetite_position"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
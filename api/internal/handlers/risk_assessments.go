@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -62,6 +64,7 @@ func ListRiskAssessments(c *gin.Context) {
 	query := fmt.Sprintf(`
 		SELECT ra.id, ra.risk_id, ra.assessment_type, ra.likelihood, ra.impact,
 		       ra.likelihood_score, ra.impact_score, ra.overall_score, ra.scoring_formula,
+		       ra.severity, ra.impact_ratings, ra.methodology_id, ra.methodology_version,
 		       ra.justification, ra.assumptions, ra.data_sources,
 		       ra.assessed_by, ra.assessment_date, ra.valid_until, ra.is_current, ra.superseded_by,
 		       ra.created_at,
@@ -86,9 +89,12 @@ func ListRiskAssessments(c *gin.Context) {
 	for rows.Next() {
 		var (
 			id, raRiskID, assessType, likelihood, impact string
-			lScore, iScore                               int
-			overallScore                                 float64
-			formula                                      string
+			lScore                                       int
+			iScore, overallScore                         float64
+			formula, severity                            string
+			impactRatings                                []byte
+			methodID                                     *string
+			methodVersion                                int
 			justification, assumptions                   *string
 			dataSources                                  pq.StringArray
 			assessedBy                                   string
@@ -103,6 +109,7 @@ func ListRiskAssessments(c *gin.Context) {
 		err := rows.Scan(
 			&id, &raRiskID, &assessType, &likelihood, &impact,
 			&lScore, &iScore, &overallScore, &formula,
+			&severity, &impactRatings, &methodID, &methodVersion,
 			&justification, &assumptions, &dataSources,
 			&assessedBy, &assessmentDate, &validUntil, &isCurrent, &supersededBy,
 			&createdAt,
@@ -113,7 +120,10 @@ func ListRiskAssessments(c *gin.Context) {
 			continue
 		}
 
-		severity := models.ScoreSeverity(overallScore)
+		var ratings map[string]string
+		if len(impactRatings) > 0 {
+			json.Unmarshal(impactRatings, &ratings)
+		}
 
 		result := gin.H{
 			"id":               id,
@@ -121,11 +131,13 @@ func ListRiskAssessments(c *gin.Context) {
 			"assessment_type":  assessType,
 			"likelihood":       likelihood,
 			"impact":           impact,
+			"impact_ratings":   ratings,
 			"likelihood_score": lScore,
 			"impact_score":     iScore,
 			"overall_score":    overallScore,
 			"scoring_formula":  formula,
 			"severity":         severity,
+			"methodology":      gin.H{"id": methodID, "version": methodVersion},
 			"justification":    justification,
 			"assumptions":      assumptions,
 			"data_sources":     []string(dataSources),
@@ -164,12 +176,12 @@ func CreateRiskAssessment(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid assessment_type"))
 		return
 	}
-	if !models.IsValidLikelihood(req.Likelihood) {
-		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid likelihood value"))
+	if req.Impact == "" && len(req.ImpactRatings) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "impact or impact_ratings is required"))
 		return
 	}
-	if !models.IsValidImpact(req.Impact) {
-		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid impact value"))
+	if req.Impact != "" && len(req.ImpactRatings) > 0 {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Provide either impact or impact_ratings, not both"))
 		return
 	}
 
@@ -202,11 +214,38 @@ func CreateRiskAssessment(c *gin.Context) {
 		return
 	}
 
-	// Compute scores
-	lScore := models.LikelihoodScore(req.Likelihood)
-	iScore := models.ImpactScore(req.Impact)
-	overallScore := float64(lScore * iScore)
-	severity := models.ScoreSeverity(overallScore)
+	// Score with the org's active methodology
+	method, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to create assessment"))
+		return
+	}
+	if !method.IsValidLikelihood(req.Likelihood) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid likelihood value"))
+		return
+	}
+	lScore := method.LikelihoodScore(req.Likelihood)
+	impact := req.Impact
+	var iScore float64
+	var impactRatings []byte
+	if len(req.ImpactRatings) > 0 {
+		iScore, impact, err = method.WeightedImpact(req.ImpactRatings)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid impact_ratings: "+err.Error()))
+			return
+		}
+		impactRatings, _ = json.Marshal(req.ImpactRatings)
+	} else {
+		if !method.IsValidImpact(impact) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid impact value"))
+			return
+		}
+		iScore = float64(method.ImpactScore(impact))
+	}
+	overallScore := math.Round(float64(lScore)*iScore*100) / 100
+	band := method.Band(overallScore)
+	severity := band.Severity
 
 	assessID := uuid.New().String()
 	now := time.Now()
@@ -236,12 +275,14 @@ func CreateRiskAssessment(c *gin.Context) {
 	// Insert assessment
 	_, err = database.DB.Exec(`
 		INSERT INTO risk_assessments (id, org_id, risk_id, assessment_type, likelihood, impact,
-		                              likelihood_score, impact_score, overall_score, scoring_formula,
+		                              likelihood_score, impact_score, overall_score, scoring_formula, severity,
+		                              impact_ratings, methodology_id, methodology_version,
 		                              justification, assumptions, data_sources,
 		                              assessed_by, assessment_date, valid_until, is_current, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, TRUE, $15)
-	`, assessID, orgID, riskID, req.AssessmentType, req.Likelihood, req.Impact,
-		lScore, iScore, overallScore, formula,
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, TRUE, $19)
+	`, assessID, orgID, riskID, req.AssessmentType, req.Likelihood, impact,
+		lScore, iScore, overallScore, formula, severity,
+		impactRatings, method.ID, method.Version,
 		req.Justification, req.Assumptions, pq.Array(req.DataSources),
 		userID, now, validUntil)
 	if err != nil {
@@ -258,13 +299,13 @@ func CreateRiskAssessment(c *gin.Context) {
 			UPDATE risks SET inherent_likelihood = $1, inherent_impact = $2, inherent_score = $3,
 			                 last_assessed_at = $4, updated_at = $4
 			WHERE id = $5 AND org_id = $6
-		`, req.Likelihood, req.Impact, overallScore, now, riskID, orgID)
+		`, req.Likelihood, impact, overallScore, now, riskID, orgID)
 	case models.AssessmentTypeResidual:
 		database.DB.Exec(`
 			UPDATE risks SET residual_likelihood = $1, residual_impact = $2, residual_score = $3,
 			                 last_assessed_at = $4, updated_at = $4
 			WHERE id = $5 AND org_id = $6
-		`, req.Likelihood, req.Impact, overallScore, now, riskID, orgID)
+		`, req.Likelihood, impact, overallScore, now, riskID, orgID)
 
 		// Check appetite breach for response
 		var threshold *float64
@@ -275,7 +316,7 @@ func CreateRiskAssessment(c *gin.Context) {
 		}
 		riskUpdated = gin.H{
 			"residual_likelihood": req.Likelihood,
-			"residual_impact":     req.Impact,
+			"residual_impact":     impact,
 			"residual_score":      overallScore,
 			"appetite_breached":   breached,
 		}
@@ -290,10 +331,11 @@ func CreateRiskAssessment(c *gin.Context) {
 	}
 
 	middleware.LogAudit(c, "risk_assessment.created", "risk_assessment", &assessID, map[string]interface{}{
-		"risk_id":         riskID,
-		"assessment_type": req.AssessmentType,
-		"score":           overallScore,
-		"severity":        severity,
+		"risk_id":             riskID,
+		"assessment_type":     req.AssessmentType,
+		"score":               overallScore,
+		"severity":            severity,
+		"methodology_version": method.Version,
 	})
 
 	// Get assessor name
@@ -305,12 +347,15 @@ func CreateRiskAssessment(c *gin.Context) {
 		"risk_id":          riskID,
 		"assessment_type":  req.AssessmentType,
 		"likelihood":       req.Likelihood,
-		"impact":           req.Impact,
+		"impact":           impact,
+		"impact_ratings":   req.ImpactRatings,
 		"likelihood_score": lScore,
 		"impact_score":     iScore,
 		"overall_score":    overallScore,
 		"severity":         severity,
+		"severity_label":   band.Label,
 		"scoring_formula":  formula,
+		"methodology":      gin.H{"id": method.ID, "version": method.Version, "name": method.Name},
 		"is_current":       true,
 		"assessed_by":      gin.H{"id": userID, "name": assessorName},
 		"assessment_date":  now.Format("2006-01-02"),
@@ -350,23 +395,28 @@ func RecalculateRiskScores(c *gin.Context) {
 
 	now := time.Now()
 
-	// Read current inherent assessment
-	var inhL, inhI *string
-	var inhScore *float64
-	database.DB.QueryRow(`
-		SELECT likelihood, impact, overall_score FROM risk_assessments
-		WHERE risk_id = $1 AND org_id = $2 AND assessment_type = 'inherent' AND is_current = TRUE
-		ORDER BY assessment_date DESC LIMIT 1
-	`, riskID, orgID).Scan(&inhL, &inhI, &inhScore)
+	active, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to recalculate"))
+		return
+	}
 
-	// Read current residual assessment
-	var resL, resI *string
-	var resScore *float64
-	database.DB.QueryRow(`
-		SELECT likelihood, impact, overall_score FROM risk_assessments
-		WHERE risk_id = $1 AND org_id = $2 AND assessment_type = 'residual' AND is_current = TRUE
-		ORDER BY assessment_date DESC LIMIT 1
-	`, riskID, orgID).Scan(&resL, &resI, &resScore)
+	// Rescore the current inherent and residual assessments
+	inh, err := rescoreCurrentAssessment(orgID, riskID, models.AssessmentTypeInherent, active)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to rescore inherent assessment")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to recalculate"))
+		return
+	}
+	res, err := rescoreCurrentAssessment(orgID, riskID, models.AssessmentTypeResidual, active)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to rescore residual assessment")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to recalculate"))
+		return
+	}
+	inhL, inhI, inhScore := inh.likelihood, inh.impact, inh.score
+	resL, resI, resScore := res.likelihood, res.impact, res.score
 
 	// Update denormalized fields
 	database.DB.Exec(`
@@ -381,21 +431,11 @@ func RecalculateRiskScores(c *gin.Context) {
 
 	// Build response
 	var inhScoreObj, resScoreObj interface{}
-	if inhL != nil && inhI != nil && inhScore != nil {
-		inhScoreObj = gin.H{
-			"likelihood": *inhL,
-			"impact":     *inhI,
-			"score":      *inhScore,
-			"severity":   models.ScoreSeverity(*inhScore),
-		}
+	if inh.summary != nil {
+		inhScoreObj = inh.summary
 	}
-	if resL != nil && resI != nil && resScore != nil {
-		resScoreObj = gin.H{
-			"likelihood": *resL,
-			"impact":     *resI,
-			"score":      *resScore,
-			"severity":   models.ScoreSeverity(*resScore),
-		}
+	if res.summary != nil {
+		resScoreObj = res.summary
 	}
 
 	var threshold *float64
@@ -411,7 +451,81 @@ func RecalculateRiskScores(c *gin.Context) {
 		"inherent_score":    inhScoreObj,
 		"residual_score":    resScoreObj,
 		"appetite_breached": breached,
+		"methodology":       gin.H{"id": active.ID, "version": active.Version, "name": active.Name},
 		"recalculated_at":   now,
 	}))
 }
+
+// rescoredAssessment is a current assessment scored again with the methodology it was recorded
+// under. The fields are nil when the risk has no current assessment of that type.
+type rescoredAssessment struct {
+	likelihood, impact *string
+	score              *float64
+	summary            gin.H
+}
+
+// rescoreCurrentAssessment recomputes a risk's current assessment of one type from its stored
+// likelihood, impact and impact ratings, using the methodology version the assessment recorded
+// rather than the active one, so changing methodology never silently rescores history. The
+// summary flags assessments whose methodology is no longer the active version.
+func rescoreCurrentAssessment(orgID, riskID, assessmentType string, active *models.RiskMethodology) (*rescoredAssessment, error) {
+	var (
+		likelihood, impact string
+		overall            float64
+		methodID           *string
+		version            int
+		ratings            []byte
+	)
+	err := database.DB.QueryRow(`
+		SELECT likelihood, impact, overall_score, methodology_id, methodology_version, impact_ratings
+		FROM risk_assessments
+		WHERE risk_id = $1 AND org_id = $2 AND assessment_type = $3 AND is_current = TRUE
+		ORDER BY assessment_date DESC LIMIT 1
+	`, riskID, orgID, assessmentType).Scan(&likelihood, &impact, &overall, &methodID, &version, &ratings)
+	if err == sql.ErrNoRows {
+		return &rescoredAssessment{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := riskMethodologyVersion(orgID, methodID)
+	if err != nil {
+		return nil, err
+	}
+	score := overall
+	if lScore := method.LikelihoodScore(likelihood); lScore > 0 {
+		iScore := float64(method.ImpactScore(impact))
+		if len(ratings) > 0 {
+			var r map[string]string
+			if json.Unmarshal(ratings, &r) == nil {
+				if weighted, _, err := method.WeightedImpact(r); err == nil {
+					iScore = weighted
+				}
+			}
+		}
+		if iScore > 0 {
+			score = math.Round(float64(lScore)*iScore*100) / 100
+		}
+	}
+	band := method.Band(score)
+
+	return &rescoredAssessment{
+		likelihood: &likelihood,
+		impact:     &impact,
+		score:      &score,
+		summary: gin.H{
+			"likelihood":     likelihood,
+			"impact":         impact,
+			"score":          score,
+			"severity":       band.Severity,
+			"severity_label": band.Label,
+			"methodology": gin.H{
+				"id":       method.ID,
+				"version":  method.Version,
+				"outdated": version != active.Version,
+			},
+		},
+	}, nil
+}
 // end of file
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+const riskMethodologyColumns = `
+	m.id, m.version, m.name, m.description,
+	m.likelihood_levels, m.impact_levels, m.impact_dimensions, m.severity_bands,
+	m.is_active, m.activated_at, m.created_by, m.created_at`
+
+func scanRiskMethodology(s rowScanner) (*models.RiskMethodology, error) {
+	var m models.RiskMethodology
+	var id string
+	var likelihood, impact, dimensions, bands []byte
+	err := s.Scan(&id, &m.Version, &m.Name, &m.Description,
+		&likelihood, &impact, &dimensions, &bands,
+		&m.IsActive, &m.ActivatedAt, &m.CreatedBy, &m.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	m.ID = &id
+	for _, f := range []struct {
+		raw  []byte
+		dest interface{}
+	}{
+		{likelihood, &m.LikelihoodLevels},
+		{impact, &m.ImpactLevels},
+		{dimensions, &m.ImpactDimensions},
+		{bands, &m.SeverityBands},
+	} {
+		if len(f.raw) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(f.raw, f.dest); err != nil {
+			return nil, err
+		}
+	}
+	if m.ImpactDimensions == nil {
+		m.ImpactDimensions = []models.RiskImpactDimension{}
+	}
+	return &m, nil
+}
+
+// activeRiskMethodology returns the org's active scoring methodology, or the built-in default
+// when the org has not activated one.
+func activeRiskMethodology(orgID string) (*models.RiskMethodology, error) {
+	m, err := scanRiskMethodology(database.DB.QueryRow(`
+		SELECT `+riskMethodologyColumns+`
+		FROM risk_scoring_methodologies m
+		WHERE m.org_id = $1 AND m.is_active = TRUE
+	`, orgID))
+	if err == sql.ErrNoRows {
+		return models.DefaultRiskMethodology(), nil
+	}
+	return m, err
+}
+
+// riskMethodologyVersion returns the methodology an assessment was scored with. A nil ID means
+// the built-in default.
+func riskMethodologyVersion(orgID string, id *string) (*models.RiskMethodology, error) {
+	if id == nil {
+		m := models.DefaultRiskMethodology()
+		m.IsActive = false
+		return m, nil
+	}
+	return scanRiskMethodology(database.DB.QueryRow(`
+		SELECT `+riskMethodologyColumns+`
+		FROM risk_scoring_methodologies m
+		WHERE m.id = $1 AND m.org_id = $2
+	`, *id, orgID))
+}
+
+// ListRiskMethodologies lists every saved methodology version, newest first. Organizations that
+// have not saved one see the built-in default.
+func ListRiskMethodologies(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	rows, err := database.DB.Query(`
+		SELECT `+riskMethodologyColumns+`
+		FROM risk_scoring_methodologies m
+		WHERE m.org_id = $1
+		ORDER BY m.version DESC
+	`, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list risk methodologies")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to list risk methodologies"))
+		return
+	}
+	defer rows.Close()
+
+	results := []models.RiskMethodology{}
+	hasActive := false
+	for rows.Next() {
+		m, err := scanRiskMethodology(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to scan risk methodology")
+			continue
+		}
+		hasActive = hasActive || m.IsActive
+		results = append(results, *m)
+	}
+	def := models.DefaultRiskMethodology()
+	def.IsActive = !hasActive
+	results = append(results, *def)
+
+	c.JSON(http.StatusOK, successResponse(c, results))
+}
+
+// GetActiveRiskMethodology returns the methodology new assessments are scored with.
+func GetActiveRiskMethodology(c *gin.Context) {
+	m, err := activeRiskMethodology(middleware.GetOrgID(c))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get active risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get risk methodology"))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, m))
+}
+
+// GetRiskMethodology returns one methodology version.
+func GetRiskMethodology(c *gin.Context) {
+	id := c.Param("id")
+	m, err := riskMethodologyVersion(middleware.GetOrgID(c), &id)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk methodology not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get risk methodology"))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, m))
+}
+
+// CreateRiskMethodology saves a new methodology version. Versions are never edited in place, so
+// existing assessments keep the scales they were scored with. With activate set, the new
+// version becomes the one new assessments use.
+func CreateRiskMethodology(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	var req models.CreateRiskMethodologyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request: "+err.Error()))
+		return
+	}
+	m := &models.RiskMethodology{
+		Name:             req.Name,
+		Description:      req.Description,
+		LikelihoodLevels: req.LikelihoodLevels,
+		ImpactLevels:     req.ImpactLevels,
+		ImpactDimensions: req.ImpactDimensions,
+		SeverityBands:    req.SeverityBands,
+	}
+	if m.ImpactDimensions == nil {
+		m.ImpactDimensions = []models.RiskImpactDimension{}
+	}
+	if err := m.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	likelihood, _ := json.Marshal(m.LikelihoodLevels)
+	impact, _ := json.Marshal(m.ImpactLevels)
+	dimensions, _ := json.Marshal(m.ImpactDimensions)
+	bands, _ := json.Marshal(m.SeverityBands)
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to create risk methodology"))
+		return
+	}
+	defer tx.Rollback()
+
+	if req.Activate {
+		if _, err := tx.Exec(`
+			UPDATE risk_scoring_methodologies SET is_active = FALSE
+			WHERE org_id = $1 AND is_active = TRUE
+		`, orgID); err != nil {
+			log.Error().Err(err).Msg("Failed to deactivate risk methodology")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to create risk methodology"))
+			return
+		}
+	}
+
+	created, err := scanRiskMethodology(tx.QueryRow(`
+		INSERT INTO risk_scoring_methodologies AS m (org_id, version, name, description,
+			likelihood_levels, impact_levels, impact_dimensions, severity_bands,
+			is_active, activated_at, created_by)
+		VALUES ($1, (SELECT COALESCE(MAX(version), 0) + 1 FROM risk_scoring_methodologies WHERE org_id = $1),
+			$2, $3, $4, $5, $6, $7, $8, CASE WHEN $8 THEN NOW() END, $9)
+		RETURNING `+riskMethodologyColumns,
+		orgID, m.Name, m.Description, likelihood, impact, dimensions, bands, req.Activate, userID))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to create risk methodology"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to create risk methodology"))
+		return
+	}
+
+	middleware.LogAudit(c, "risk_methodology.created", "risk_methodology", created.ID, map[string]interface{}{
+		"version": created.Version, "name": created.Name, "activated": created.IsActive,
+	})
+	if created.IsActive {
+		middleware.LogAudit(c, "risk_methodology.activated", "risk_methodology", created.ID, map[string]interface{}{
+			"version": created.Version,
+		})
+	}
+
+	c.JSON(http.StatusCreated, successResponse(c, created))
+}
+
+// ActivateRiskMethodology makes a saved version the one new assessments are scored with.
+// Existing assessments are not rescored.
+func ActivateRiskMethodology(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	id := c.Param("id")
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to activate risk methodology"))
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		UPDATE risk_scoring_methodologies SET is_active = FALSE
+		WHERE org_id = $1 AND is_active = TRUE AND id <> $2
+	`, orgID, id); err != nil {
+		log.Error().Err(err).Msg("Failed to deactivate risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to activate risk methodology"))
+		return
+	}
+
+	m, err := scanRiskMethodology(tx.QueryRow(`
+		UPDATE risk_scoring_methodologies m
+		SET is_active = TRUE, activated_at = CASE WHEN m.is_active THEN m.activated_at ELSE NOW() END
+		WHERE m.id = $1 AND m.org_id = $2
+		RETURNING `+riskMethodologyColumns, id, orgID))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk methodology not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to activate risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to activate risk methodology"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit risk methodology activation")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to activate risk methodology"))
+		return
+	}
+
+	middleware.LogAudit(c, "risk_methodology.activated", "risk_methodology", m.ID, map[string]interface{}{
+		"version": m.Version,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, m))
+}
+
+// severityScoreCondition builds a SQL condition matching scores in col that fall in a severity
+// band of the methodology, or in that band and anything more severe when orAbove is set. The
+// least severe band is open below. It returns "" when no condition is needed and false for an
+// unknown severity. Band scores are validated numbers, so they are inlined.
+func severityScoreCondition(m *models.RiskMethodology, severity, col string, orAbove bool) (string, bool) {
+	bands := m.SeverityBands
+	for i, b := range bands {
+		if b.Severity != severity {
+			continue
+		}
+		conds := []string{}
+		if i < len(bands)-1 {
+			conds = append(conds, fmt.Sprintf("%s >= %g", col, b.MinScore))
+		}
+		if i > 0 && !orAbove {
+			conds = append(conds, fmt.Sprintf("%s < %g", col, bands[i-1].MinScore))
+		}
+		return strings.Join(conds, " AND "), true
+	}
+	return "", false
+}
+
+// severityCaseExpr builds a SQL CASE expression classifying col into the methodology's bands.
+func severityCaseExpr(m *models.RiskMethodology, col string) string {
+	bands := m.SeverityBands
+	var sb strings.Builder
+	sb.WriteString("CASE")
+	for _, b := range bands[:len(bands)-1] {
+		fmt.Fprintf(&sb, " WHEN %s >= %g THEN '%s'", col, b.MinScore, b.Severity)
+	}
+	fmt.Fprintf(&sb, " ELSE '%s' END", bands[len(bands)-1].Severity)
+	return sb.String()
+}
@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var riskMethodologyRowColumns = []string{
+	"id", "version", "name", "description",
+	"likelihood_levels", "impact_levels", "impact_dimensions", "severity_bands",
+	"is_active", "activated_at", "created_by", "created_at",
+}
+
+// expectDefaultRiskMethodology mocks an org without an active methodology.
+func expectDefaultRiskMethodology(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("FROM risk_scoring_methodologies m\\s+WHERE m.org_id = \\$1 AND m.is_active = TRUE").
+		WithArgs("org-001").
+		WillReturnRows(sqlmock.NewRows(riskMethodologyRowColumns))
+}
+
+// threeByThreeMethodology is a 3x3 matrix scoring 1, 3, 5 with a financial-heavy impact weighting.
+const threeByThreeMethodology = `{
+	"name": "Three by three",
+	"likelihood_levels": [
+		{"key": "low", "label": "Low", "score": 1},
+		{"key": "medium", "label": "Medium", "score": 3},
+		{"key": "high", "label": "High", "score": 5}
+	],
+	"impact_levels": [
+		{"key": "limited", "label": "Limited", "score": 1},
+		{"key": "serious", "label": "Serious", "score": 3},
+		{"key": "grave", "label": "Grave", "score": 5}
+	],
+	"impact_dimensions": [
+		{"key": "financial", "label": "Financial", "weight": 3},
+		{"key": "reputational", "label": "Reputational", "weight": 1}
+	],
+	"severity_bands": [
+		{"severity": "critical", "label": "Red", "min_score": 20},
+		{"severity": "high", "label": "Amber", "min_score": 9},
+		{"severity": "medium", "label": "Yellow", "min_score": 3},
+		{"severity": "low", "label": "Green", "min_score": 1}
+	]
+}`
+
+func threeByThree(t *testing.T) *models.RiskMethodology {
+	var m models.RiskMethodology
+	require.NoError(t, json.Unmarshal([]byte(threeByThreeMethodology), &m))
+	require.NoError(t, m.Validate())
+	return &m
+}
+
+func riskMethodologyValues(t *testing.T, id string, version int, active bool) []driver.Value {
+	var body map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(threeByThreeMethodology), &body))
+	now := time.Now()
+	return []driver.Value{id, version, "Three by three", nil,
+		[]byte(body["likelihood_levels"]), []byte(body["impact_levels"]),
+		[]byte(body["impact_dimensions"]), []byte(body["severity_bands"]),
+		active, now, "user-001", now}
+}
+
+func setupRiskMethodologyRouter(role string) (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-001")
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, role)
+		c.Next()
+	})
+	protected.GET("/risk-methodologies", ListRiskMethodologies)
+	protected.POST("/risk-methodologies", CreateRiskMethodology)
+	protected.POST("/risk-methodologies/:id/activate", ActivateRiskMethodology)
+	protected.GET("/risks/heat-map", GetRiskHeatMap)
+	protected.POST("/risks/:id/assessments", CreateRiskAssessment)
+	protected.POST("/risks/:id/recalculate", RecalculateRiskScores)
+	return router, mock
+}
+
+// ==================== Methodology model ====================
+
+func TestRiskMethodology_DefaultMatchesFixedScale(t *testing.T) {
+	m := models.DefaultRiskMethodology()
+	require.NoError(t, m.Validate())
+	for _, l := range models.ValidLikelihoodLevels {
+		assert.Equal(t, models.LikelihoodScore(l), m.LikelihoodScore(l))
+	}
+	for _, i := range models.ValidImpactLevels {
+		assert.Equal(t, models.ImpactScore(i), m.ImpactScore(i))
+	}
+	for score := 1.0; score <= 25; score++ {
+		assert.Equal(t, models.ScoreSeverity(score), m.Severity(score), "score %v", score)
+	}
+	assert.Equal(t, 25.0, m.MaxScore())
+}
+
+func TestRiskMethodology_WeightedImpact(t *testing.T) {
+	m := threeByThree(t)
+
+	// (3*5 + 1*1) / 4 = 4.0 sits halfway between serious (3) and grave (5); ties round up.
+	score, level, err := m.WeightedImpact(map[string]string{"financial": "grave", "reputational": "limited"})
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, score)
+	assert.Equal(t, "grave", level)
+
+	score, level, err = m.WeightedImpact(map[string]string{"financial": "limited", "reputational": "grave"})
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, score)
+	assert.Equal(t, "serious", level)
+
+	_, _, err = m.WeightedImpact(map[string]string{"financial": "grave"})
+	assert.Error(t, err, "every dimension must be rated")
+	_, _, err = m.WeightedImpact(map[string]string{"financial": "grave", "reputational": "limited", "legal": "grave"})
+	assert.Error(t, err, "unknown dimension")
+	_, _, err = m.WeightedImpact(map[string]string{"financial": "severe", "reputational": "limited"})
+	assert.Error(t, err, "level not on this scale")
+}
+
+func TestRiskMethodology_Validate(t *testing.T) {
+	cases := map[string]func(*models.RiskMethodology){
+		"one level":          func(m *models.RiskMethodology) { m.LikelihoodLevels = m.LikelihoodLevels[:1] },
+		"scores not rising":  func(m *models.RiskMethodology) { m.ImpactLevels[2].Score = 3 },
+		"score too high":     func(m *models.RiskMethodology) { m.ImpactLevels[2].Score = 11 },
+		"bad key":            func(m *models.RiskMethodology) { m.LikelihoodLevels[0].Key = "Very Low" },
+		"duplicate key":      func(m *models.RiskMethodology) { m.LikelihoodLevels[1].Key = "low" },
+		"one dimension":      func(m *models.RiskMethodology) { m.ImpactDimensions = m.ImpactDimensions[:1] },
+		"zero weight":        func(m *models.RiskMethodology) { m.ImpactDimensions[1].Weight = 0 },
+		"bands out of order": func(m *models.RiskMethodology) { m.SeverityBands[1].MinScore = 25 },
+		"missing band":       func(m *models.RiskMethodology) { m.SeverityBands = m.SeverityBands[:3] },
+		"unreachable band":   func(m *models.RiskMethodology) { m.SeverityBands[0].MinScore = 30 },
+	}
+	for name, mutate := range cases {
+		m := threeByThree(t)
+		mutate(m)
+		assert.Error(t, m.Validate(), name)
+	}
+
+	m := threeByThree(t)
+	assert.Equal(t, "high", m.Severity(15))
+	assert.Equal(t, "Amber", m.Band(9).Label)
+	assert.Equal(t, "low", m.Severity(0.5))
+}
+
+func TestSeverityScoreCondition(t *testing.T) {
+	m := models.DefaultRiskMethodology()
+	cond, ok := severityScoreCondition(m, "high", "r.residual_score", false)
+	assert.True(t, ok)
+	assert.Equal(t, "r.residual_score >= 12 AND r.residual_score < 20", cond)
+
+	cond, _ = severityScoreCondition(m, "high", "r.residual_score", true)
+	assert.Equal(t, "r.residual_score >= 12", cond)
+
+	cond, _ = severityScoreCondition(m, "low", "r.residual_score", false)
+	assert.Equal(t, "r.residual_score < 6", cond)
+
+	_, ok = severityScoreCondition(m, "extreme", "r.residual_score", false)
+	assert.False(t, ok)
+
+	assert.Equal(t, "CASE WHEN s >= 20 THEN 'critical' WHEN s >= 12 THEN 'high' WHEN s >= 6 THEN 'medium' ELSE 'low' END",
+		severityCaseExpr(m, "s"))
+}
+
+// ==================== Methodology endpoints ====================
+
+func TestCreateRiskMethodology_Activate(t *testing.T) {
+	router, mock := setupRiskMethodologyRouter(models.RoleCISO)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE risk_scoring_methodologies SET is_active = FALSE").
+		WithArgs("org-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO risk_scoring_methodologies").
+		WithArgs("org-001", "Three by three", nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), true, "user-001").
+		WillReturnRows(sqlmock.NewRows(riskMethodologyRowColumns).AddRow(riskMethodologyValues(t, "meth-002", 2, true)...))
+	mock.ExpectCommit()
+
+	var body map[string]interface{}
+	json.Unmarshal([]byte(threeByThreeMethodology), &body)
+	body["activate"] = true
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/risk-methodologies", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["version"])
+	assert.Equal(t, true, data["is_active"])
+	assert.Len(t, data["likelihood_levels"], 3)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRiskMethodology_Invalid(t *testing.T) {
+	router, _ := setupRiskMethodologyRouter(models.RoleCISO)
+
+	var body map[string]interface{}
+	json.Unmarshal([]byte(threeByThreeMethodology), &body)
+	body["severity_bands"] = []map[string]interface{}{{"severity": "critical", "label": "Red", "min_score": 20}}
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/risk-methodologies", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestActivateRiskMethodology_NotFound(t *testing.T) {
+	router, mock := setupRiskMethodologyRouter(models.RoleComplianceManager)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE risk_scoring_methodologies SET is_active = FALSE").
+		WithArgs("org-001", "meth-404").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("UPDATE risk_scoring_methodologies m").
+		WithArgs("meth-404", "org-001").
+		WillReturnRows(sqlmock.NewRows(riskMethodologyRowColumns))
+	mock.ExpectRollback()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/risk-methodologies/meth-404/activate", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListRiskMethodologies_IncludesDefault(t *testing.T) {
+	router, mock := setupRiskMethodologyRouter(models.RoleAuditor)
+
+	mock.ExpectQuery("FROM risk_scoring_methodologies m").
+		WithArgs("org-001").
+		WillReturnRows(sqlmock.NewRows(riskMethodologyRowColumns).AddRow(riskMethodologyValues(t, "meth-001", 1, true)...))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risk-methodologies", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].([]interface{})
+	require.Len(t, data, 2)
+	def := data[1].(map[string]interface{})
+	assert.Equal(t, float64(0), def["version"])
+	assert.Equal(t, true, def["is_default"])
+	assert.Equal(t, false, def["is_active"], "a saved version is active")
+}
+
+// ==================== Scoring with a custom methodology ====================
+
+func TestCreateAssessment_WeightedImpactRatings(t *testing.T) {
+	router, mock := setupRiskMethodologyRouter(models.RoleSecurityEngineer)
+
+	mock.ExpectQuery("SELECT owner_id FROM risks").
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow("user-002"))
+	mock.ExpectQuery("FROM risk_scoring_methodologies m\\s+WHERE m.org_id = \\$1 AND m.is_active = TRUE").
+		WillReturnRows(sqlmock.NewRows(riskMethodologyRowColumns).AddRow(riskMethodologyValues(t, "meth-001", 1, true)...))
+	mock.ExpectQuery("SELECT id FROM risk_assessments").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	// medium (3) x weighted impact 4.0 = 12, in the Amber band; impact snaps to grave
+	mock.ExpectExec("INSERT INTO risk_assessments").
+		WithArgs(sqlmock.AnyArg(), "org-001", "risk-001", "inherent", "medium", "grave",
+			3, 4.0, 12.0, "likelihood_x_impact", "high",
+			sqlmock.AnyArg(), "meth-001", 1,
+			nil, nil, sqlmock.AnyArg(), "user-001", sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE risks SET inherent_likelihood").
+		WithArgs("medium", "grave", 12.0, sqlmock.AnyArg(), "risk-001", "org-001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT assessment_frequency_days").
+		WillReturnRows(sqlmock.NewRows([]string{"freq"}).AddRow(nil))
+	mock.ExpectQuery("SELECT COALESCE").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Sam Analyst"))
+
+	body := `{"assessment_type": "inherent", "likelihood": "medium",
+		"impact_ratings": {"financial": "grave", "reputational": "limited"}}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/risks/risk-001/assessments", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, 12.0, data["overall_score"])
+	assert.Equal(t, "high", data["severity"])
+	assert.Equal(t, "Amber", data["severity_label"])
+	assert.Equal(t, float64(1), data["methodology"].(map[string]interface{})["version"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAssessment_RejectsLevelOutsideMethodology(t *testing.T) {
+	router, mock := setupRiskMethodologyRouter(models.RoleCISO)
+
+	mock.ExpectQuery("SELECT owner_id FROM risks").
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow(nil))
+	mock.ExpectQuery("FROM risk_scoring_methodologies").
+		WillReturnRows(sqlmock.NewRows(riskMethodologyRowColumns).AddRow(riskMethodologyValues(t, "meth-001", 1, true)...))
+
+	// "likely" is on the default scale but not on the org's 3x3
+	body := `{"assessment_type": "inherent", "likelihood": "likely", "impact": "grave"}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/risks/risk-001/assessments", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRecalculateRiskScores_KeepsRecordedMethodology(t *testing.T) {
+	router, mock := setupRiskMethodologyRouter(models.RoleCISO)
+
+	mock.ExpectQuery("SELECT identifier FROM risks").
+		WillReturnRows(sqlmock.NewRows([]string{"identifier"}).AddRow("RISK-001"))
+	mock.ExpectQuery("FROM risk_scoring_methodologies m\\s+WHERE m.org_id = \\$1 AND m.is_active = TRUE").
+		WillReturnRows(sqlmock.NewRows(riskMethodologyRowColumns).AddRow(riskMethodologyValues(t, "meth-001", 1, true)...))
+	// Inherent assessment was scored on the built-in 5x5 before the org switched methodology
+	mock.ExpectQuery("SELECT likelihood, impact, overall_score, methodology_id").
+		WithArgs("risk-001", "org-001", "inherent").
+		WillReturnRows(sqlmock.NewRows([]string{"likelihood", "impact", "overall_score", "methodology_id", "methodology_version", "impact_ratings"}).
+			AddRow("likely", "major", 16.0, nil, 0, nil))
+	// Residual assessment was scored on version 1 with weighted impact
+	mock.ExpectQuery("SELECT likelihood, impact, overall_score, methodology_id").
+		WithArgs("risk-001", "org-001", "residual").
+		WillReturnRows(sqlmock.NewRows([]string{"likelihood", "impact", "overall_score", "methodology_id", "methodology_version", "impact_ratings"}).
+			AddRow("low", "serious", 2.0, "meth-001", 1, []byte(`{"financial": "limited", "reputational": "grave"}`)))
+	mock.ExpectQuery("FROM risk_scoring_methodologies m\\s+WHERE m.id = \\$1").
+		WithArgs("meth-001", "org-001").
+		WillReturnRows(sqlmock.NewRows(riskMethodologyRowColumns).AddRow(riskMethodologyValues(t, "meth-001", 1, true)...))
+	mock.ExpectExec("UPDATE risks SET").
+		WithArgs("likely", "major", 16.0, "low", "serious", 2.0, sqlmock.AnyArg(), "risk-001", "org-001").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT risk_appetite_threshold").
+		WillReturnRows(sqlmock.NewRows([]string{"risk_appetite_threshold"}).AddRow(nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/risks/risk-001/recalculate", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+
+	inh := data["inherent_score"].(map[string]interface{})
+	assert.Equal(t, 16.0, inh["score"])
+	assert.Equal(t, "high", inh["severity"], "scored with the default bands it was recorded under")
+	assert.Equal(t, true, inh["methodology"].(map[string]interface{})["outdated"])
+
+	res := data["residual_score"].(map[string]interface{})
+	assert.Equal(t, 2.0, res["score"])
+	assert.Equal(t, "Green", res["severity_label"])
+	assert.Equal(t, false, res["methodology"].(map[string]interface{})["outdated"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRiskHeatMap_CustomMethodology(t *testing.T) {
+	router, mock := setupRiskMethodologyRouter(models.RoleAuditor)
+
+	mock.ExpectQuery("FROM risk_scoring_methodologies").
+		WillReturnRows(sqlmock.NewRows(riskMethodologyRowColumns).AddRow(riskMethodologyValues(t, "meth-001", 1, true)...))
+	mock.ExpectQuery("SELECT residual_likelihood, residual_impact, residual_score").
+		WillReturnRows(sqlmock.NewRows([]string{"l", "i", "score", "id", "identifier", "title", "status"}).
+			AddRow("high", "grave", 25.0, "risk-001", "RISK-001", "Ransomware", "open").
+			AddRow("medium", "serious", 10.5, "risk-002", "RISK-002", "Vendor breach", "treating").
+			AddRow("likely", "major", 16.0, "risk-003", "RISK-003", "Legacy scoring", "open"))
+	mock.ExpectQuery("SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risks/heat-map", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+
+	grid := data["grid"].([]interface{})
+	require.Len(t, grid, 9)
+	top := grid[0].(map[string]interface{})
+	assert.Equal(t, "high", top["likelihood"])
+	assert.Equal(t, "grave", top["impact"])
+	assert.Equal(t, "Red", top["severity_label"])
+	assert.Equal(t, float64(1), top["count"])
+
+	unplotted := data["unplotted"].([]interface{})
+	require.Len(t, unplotted, 1)
+	assert.Equal(t, "RISK-003", unplotted[0].(map[string]interface{})["identifier"])
+
+	summary := data["summary"].(map[string]interface{})
+	assert.Equal(t, float64(3), summary["total_risks"])
+	bySeverity := summary["by_severity"].(map[string]interface{})
+	assert.Equal(t, float64(1), bySeverity["critical"])
+	assert.Equal(t, float64(2), bySeverity["high"], "10.5 and 16 are both Amber on the 3x3")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+var currencyCodeRe = regexp.MustCompile(`^[A-Z]{3}$`)
+
+const riskQuantificationColumns = `
+	q.id, q.risk_id, q.currency,
+	q.frequency_min, q.frequency_most_likely, q.frequency_max, q.frequency_distribution,
+	q.magnitude_min, q.magnitude_most_likely, q.magnitude_max, q.magnitude_distribution,
+	q.iterations, q.seed, q.notes, q.results, q.simulated_at, q.updated_by,
+	q.created_at, q.updated_at`
+
+// scanFunc adapts a function to rowScanner, letting callers scan leading columns of their own.
+type scanFunc func(dest ...interface{}) error
+
+func (f scanFunc) Scan(dest ...interface{}) error { return f(dest...) }
+
+func scanRiskQuantification(s rowScanner) (*models.RiskQuantification, error) {
+	var q models.RiskQuantification
+	var results []byte
+	err := s.Scan(&q.ID, &q.RiskID, &q.Currency,
+		&q.Frequency.Min, &q.Frequency.MostLikely, &q.Frequency.Max, &q.Frequency.Distribution,
+		&q.Magnitude.Min, &q.Magnitude.MostLikely, &q.Magnitude.Max, &q.Magnitude.Distribution,
+		&q.Iterations, &q.Seed, &q.Notes, &results, &q.SimulatedAt, &q.UpdatedBy,
+		&q.CreatedAt, &q.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) > 0 {
+		q.Results = &models.LossSimulationSummary{}
+		if err := json.Unmarshal(results, q.Results); err != nil {
+			return nil, err
+		}
+	}
+	return &q, nil
+}
+
+// GetRiskQuantification returns a risk's FAIR model and its latest simulation results.
+func GetRiskQuantification(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	riskID := c.Param("id")
+
+	q, err := scanRiskQuantification(database.DB.QueryRow(`
+		SELECT `+riskQuantificationColumns+`
+		FROM risk_quantifications q
+		WHERE q.risk_id = $1 AND q.org_id = $2
+	`, riskID, orgID))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk has no quantitative model"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get risk quantification")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get risk quantification"))
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(c, q))
+}
+
+// SetRiskQuantification saves a risk's loss event frequency and loss magnitude estimates and
+// runs the Monte Carlo simulation. Without a seed a random one is drawn and stored, so the
+// results can always be reproduced from the saved model.
+func SetRiskQuantification(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	riskID := c.Param("id")
+
+	var req models.SetRiskQuantificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid request: "+err.Error()))
+		return
+	}
+
+	currency := "USD"
+	if req.Currency != nil {
+		currency = strings.ToUpper(strings.TrimSpace(*req.Currency))
+		if !currencyCodeRe.MatchString(currency) {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "currency must be a 3-letter ISO 4217 code"))
+			return
+		}
+	}
+	in := services.LossSimulationInput{
+		Frequency:  services.NormalizeLossRange(*req.Frequency),
+		Magnitude:  services.NormalizeLossRange(*req.Magnitude),
+		Iterations: models.DefaultSimulationIterations,
+	}
+	if req.Iterations != nil {
+		in.Iterations = *req.Iterations
+	}
+	if err := services.ValidateLossSimulationInput(in); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	var ownerID *string
+	var status string
+	err := database.DB.QueryRow("SELECT owner_id, status FROM risks WHERE id = $1 AND org_id = $2", riskID, orgID).Scan(&ownerID, &status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get risk")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to quantify risk"))
+		return
+	}
+	isOwner := ownerID != nil && *ownerID == userID
+	if !isOwner && !models.HasRole(userRole, models.RiskQuantifyRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to quantify this risk"))
+		return
+	}
+	if status == models.RiskStatusArchived {
+		c.JSON(http.StatusConflict, errorResponse("RISK_ARCHIVED", "Archived risks cannot be quantified"))
+		return
+	}
+
+	if req.Seed != nil {
+		in.Seed = *req.Seed
+	} else {
+		var b [8]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			log.Error().Err(err).Msg("Failed to generate simulation seed")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to quantify risk"))
+			return
+		}
+		in.Seed = int64(binary.BigEndian.Uint64(b[:]) >> 1)
+	}
+
+	summary := services.SummarizeAnnualLoss(services.SimulateAnnualLoss(in))
+	results, _ := json.Marshal(summary)
+
+	q, err := scanRiskQuantification(database.DB.QueryRow(`
+		INSERT INTO risk_quantifications AS q (org_id, risk_id, currency,
+			frequency_min, frequency_most_likely, frequency_max, frequency_distribution,
+			magnitude_min, magnitude_most_likely, magnitude_max, magnitude_distribution,
+			iterations, seed, notes, annualized_loss_expectancy, results, simulated_at,
+			created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW(), $17, $17)
+		ON CONFLICT (risk_id) DO UPDATE SET
+			currency = EXCLUDED.currency,
+			frequency_min = EXCLUDED.frequency_min,
+			frequency_most_likely = EXCLUDED.frequency_most_likely,
+			frequency_max = EXCLUDED.frequency_max,
+			frequency_distribution = EXCLUDED.frequency_distribution,
+			magnitude_min = EXCLUDED.magnitude_min,
+			magnitude_most_likely = EXCLUDED.magnitude_most_likely,
+			magnitude_max = EXCLUDED.magnitude_max,
+			magnitude_distribution = EXCLUDED.magnitude_distribution,
+			iterations = EXCLUDED.iterations,
+			seed = EXCLUDED.seed,
+			notes = EXCLUDED.notes,
+			annualized_loss_expectancy = EXCLUDED.annualized_loss_expectancy,
+			results = EXCLUDED.results,
+			simulated_at = NOW(),
+			updated_by = EXCLUDED.updated_by
+		RETURNING `+riskQuantificationColumns,
+		orgID, riskID, currency,
+		in.Frequency.Min, in.Frequency.MostLikely, in.Frequency.Max, in.Frequency.Distribution,
+		in.Magnitude.Min, in.Magnitude.MostLikely, in.Magnitude.Max, in.Magnitude.Distribution,
+		in.Iterations, in.Seed, req.Notes, summary.ALE, string(results), userID))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to save risk quantification")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to quantify risk"))
+		return
+	}
+
+	middleware.LogAudit(c, "risk.quantified", "risk", &riskID, map[string]interface{}{
+		"currency":                   currency,
+		"annualized_loss_expectancy": summary.ALE,
+		"iterations":                 in.Iterations,
+		"seed":                       in.Seed,
+	})
+
+	c.JSON(http.StatusOK, successResponse(c, q))
+}
+
+// DeleteRiskQuantification removes a risk's quantitative model.
+func DeleteRiskQuantification(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	riskID := c.Param("id")
+
+	var ownerID *string
+	err := database.DB.QueryRow("SELECT owner_id FROM risks WHERE id = $1 AND org_id = $2", riskID, orgID).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk not found"))
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get risk")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to remove risk quantification"))
+		return
+	}
+	isOwner := ownerID != nil && *ownerID == userID
+	if !isOwner && !models.HasRole(userRole, models.RiskQuantifyRoles) {
+		c.JSON(http.StatusForbidden, errorResponse("FORBIDDEN", "Not authorized to quantify this risk"))
+		return
+	}
+
+	res, err := database.DB.Exec("DELETE FROM risk_quantifications WHERE risk_id = $1 AND org_id = $2", riskID, orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete risk quantification")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to remove risk quantification"))
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk has no quantitative model"))
+		return
+	}
+
+	middleware.LogAudit(c, "risk.quantification_removed", "risk", &riskID, nil)
+	c.JSON(http.StatusOK, successResponse(c, gin.H{"risk_id": riskID, "removed": true}))
+}
+
+// GetRiskQuantificationSummary aggregates the quantified risks in the register. Every model is
+// re-simulated from its stored seed at a common iteration count and the simulated years are
+// summed, so register percentiles and the exceedance curve reflect the combined annual loss
+// rather than a sum of per-risk percentiles. Only risks in the requested currency are included.
+// The iteration count is lowered to keep the register within MaxSummarySampledValues; a
+// register too large for MinSimulationIterations must be narrowed by category or status.
+func GetRiskQuantificationSummary(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+
+	currency := strings.ToUpper(c.DefaultQuery("currency", "USD"))
+	if !currencyCodeRe.MatchString(currency) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "currency must be a 3-letter ISO 4217 code"))
+		return
+	}
+	iterations := models.DefaultSimulationIterations
+	if v := c.Query("iterations"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < models.MinSimulationIterations || n > models.MaxSummaryIterations {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR",
+				fmt.Sprintf("iterations must be between %d and %d", models.MinSimulationIterations, models.MaxSummaryIterations)))
+			return
+		}
+		iterations = n
+	}
+
+	where := []string{"q.org_id = $1", "r.status != 'archived'"}
+	args := []interface{}{orgID}
+	argN := 2
+	if v := c.Query("category"); v != "" {
+		where = append(where, fmt.Sprintf("r.category = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+	if v := c.Query("status"); v != "" {
+		where = append(where, fmt.Sprintf("r.status = $%d", argN))
+		args = append(args, v)
+		argN++
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT r.identifier, r.title, r.category::text, `+riskQuantificationColumns+`
+		FROM risk_quantifications q
+		JOIN risks r ON r.id = q.risk_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY r.identifier
+	`, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list risk quantifications")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to summarize risk quantification"))
+		return
+	}
+	defer rows.Close()
+
+	type quantifiedRisk struct {
+		identifier, title, category string
+		q                           *models.RiskQuantification
+	}
+	included := []quantifiedRisk{}
+	otherCurrencies := map[string]int{}
+	for rows.Next() {
+		var r quantifiedRisk
+		q, err := scanRiskQuantification(scanFunc(func(dest ...interface{}) error {
+			return rows.Scan(append([]interface{}{&r.identifier, &r.title, &r.category}, dest...)...)
+		}))
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to scan risk quantification")
+			continue
+		}
+		if q.Currency != currency {
+			otherCurrencies[q.Currency]++
+			continue
+		}
+		r.q = q
+		included = append(included, r)
+	}
+
+	cost := 0
+	for _, r := range included {
+		cost += services.LossSimulationCost(services.LossSimulationInput{Frequency: r.q.Frequency})
+	}
+	if cost > 0 && iterations*cost > models.MaxSummarySampledValues {
+		iterations = models.MaxSummarySampledValues / cost
+		if iterations < models.MinSimulationIterations {
+			c.JSON(http.StatusUnprocessableEntity, errorResponse("UNPROCESSABLE",
+				"Too many quantified risks to simulate together; filter the summary by category or status"))
+			return
+		}
+	}
+
+	perRisk := make([][]float64, 0, len(included))
+	risks := make([]gin.H, 0, len(included))
+	byCategory := map[string]float64{}
+	var aleTotal float64
+	for _, r := range included {
+		losses := services.SimulateAnnualLoss(services.LossSimulationInput{
+			Frequency: r.q.Frequency, Magnitude: r.q.Magnitude, Iterations: iterations, Seed: r.q.Seed,
+		})
+		s := services.SummarizeAnnualLoss(losses)
+		perRisk = append(perRisk, losses)
+		byCategory[r.category] += s.ALE
+		aleTotal += s.ALE
+		risks = append(risks, gin.H{
+			"risk_id":                    r.q.RiskID,
+			"identifier":                 r.identifier,
+			"title":                      r.title,
+			"category":                   r.category,
+			"annualized_loss_expectancy": s.ALE,
+			"p90":                        s.Percentiles["p90"],
+			"p99":                        s.Percentiles["p99"],
+			"simulated_at":               r.q.SimulatedAt,
+		})
+	}
+	for _, r := range risks {
+		share := 0.0
+		if aleTotal > 0 {
+			share = r["annualized_loss_expectancy"].(float64) / aleTotal
+		}
+		r["share_of_ale"] = float64(int64(share*10000+0.5)) / 10000
+	}
+	sort.SliceStable(risks, func(i, j int) bool {
+		return risks[i]["annualized_loss_expectancy"].(float64) > risks[j]["annualized_loss_expectancy"].(float64)
+	})
+
+	categories := make([]gin.H, 0, len(byCategory))
+	for cat, ale := range byCategory {
+		categories = append(categories, gin.H{"category": cat, "annualized_loss_expectancy": ale})
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i]["annualized_loss_expectancy"].(float64) > categories[j]["annualized_loss_expectancy"].(float64)
+	})
+
+	var register *models.LossSimulationSummary
+	if len(perRisk) > 0 {
+		register = services.SummarizeAnnualLoss(services.AggregateAnnualLoss(perRisk))
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"currency":             currency,
+		"iterations":           iterations,
+		"quantified_risks":     len(included),
+		"excluded_by_currency": otherCurrencies,
+		"register":             register,
+		"by_category":          categories,
+		"risks":                risks,
+		"generated_at":         time.Now(),
+	}))
+}
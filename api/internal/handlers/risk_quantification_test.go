@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRiskQuantificationRouter(role string) (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-001")
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, role)
+		c.Next()
+	})
+	protected.GET("/risks/quantification/summary", GetRiskQuantificationSummary)
+	protected.GET("/risks/:id/quantification", GetRiskQuantification)
+	protected.PUT("/risks/:id/quantification", SetRiskQuantification)
+	protected.DELETE("/risks/:id/quantification", DeleteRiskQuantification)
+	return router, mock
+}
+
+var riskQuantificationRowColumns = []string{
+	"id", "risk_id", "currency",
+	"frequency_min", "frequency_most_likely", "frequency_max", "frequency_distribution",
+	"magnitude_min", "magnitude_most_likely", "magnitude_max", "magnitude_distribution",
+	"iterations", "seed", "notes", "results", "simulated_at", "updated_by",
+	"created_at", "updated_at",
+}
+
+func riskQuantificationValues(riskID, currency string, seed int64) []driver.Value {
+	now := time.Now()
+	return []driver.Value{"quant-" + riskID, riskID, currency,
+		0.5, 1.0, 4.0, "pert",
+		10000.0, 50000.0, 500000.0, "lognormal",
+		10000, seed, nil, []byte(`{"iterations": 10000, "annualized_loss_expectancy": 91234.5}`), now, "user-001",
+		now, now}
+}
+
+const testRiskQuantificationBody = `{
+	"loss_event_frequency": {"min": 0.5, "most_likely": 1, "max": 4, "distribution": "pert"},
+	"loss_magnitude": {"min": 10000, "most_likely": 50000, "max": 500000, "distribution": "lognormal"},
+	"seed": 99, "iterations": 5000
+}`
+
+func TestSetRiskQuantification_Success(t *testing.T) {
+	router, mock := setupRiskQuantificationRouter(models.RoleSecurityEngineer)
+
+	mock.ExpectQuery("SELECT owner_id, status FROM risks").
+		WithArgs("risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id", "status"}).AddRow("user-002", "open"))
+	mock.ExpectQuery("INSERT INTO risk_quantifications").
+		WithArgs("org-001", "risk-001", "USD", 0.5, 1.0, 4.0, "pert", 10000.0, math.Sqrt(10000.0*500000.0), 500000.0, "lognormal",
+			5000, int64(99), nil, sqlmock.AnyArg(), sqlmock.AnyArg(), "user-001").
+		WillReturnRows(sqlmock.NewRows(riskQuantificationRowColumns).
+			AddRow(riskQuantificationValues("risk-001", "USD", 99)...))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/risks/risk-001/quantification", bytes.NewBufferString(testRiskQuantificationBody))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(99), data["seed"])
+	assert.Equal(t, "pert", data["loss_event_frequency"].(map[string]interface{})["distribution"])
+	assert.NotNil(t, data["results"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetRiskQuantification_Validation(t *testing.T) {
+	router, _ := setupRiskQuantificationRouter(models.RoleCISO)
+
+	bodies := []string{
+		`{"loss_magnitude": {"min": 1, "most_likely": 2, "max": 3, "distribution": "pert"}}`,
+		`{"loss_event_frequency": {"min": 2, "most_likely": 1, "max": 4, "distribution": "pert"},
+		  "loss_magnitude": {"min": 1, "most_likely": 2, "max": 3, "distribution": "pert"}}`,
+		`{"loss_event_frequency": {"min": 0.5, "most_likely": 1, "max": 4, "distribution": "pert"},
+		  "loss_magnitude": {"min": 0, "most_likely": 2, "max": 3, "distribution": "lognormal"}}`,
+		`{"currency": "dollars", "loss_event_frequency": {"min": 0.5, "most_likely": 1, "max": 4, "distribution": "pert"},
+		  "loss_magnitude": {"min": 1, "most_likely": 2, "max": 3, "distribution": "pert"}}`,
+	}
+	for _, body := range bodies {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/api/v1/risks/risk-001/quantification", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestSetRiskQuantification_Forbidden(t *testing.T) {
+	router, mock := setupRiskQuantificationRouter(models.RoleDevOpsEngineer)
+
+	mock.ExpectQuery("SELECT owner_id, status FROM risks").
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id", "status"}).AddRow("user-002", "open"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/risks/risk-001/quantification", bytes.NewBufferString(testRiskQuantificationBody))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestGetRiskQuantification_NotFound(t *testing.T) {
+	router, mock := setupRiskQuantificationRouter(models.RoleAuditor)
+
+	mock.ExpectQuery("FROM risk_quantifications q").
+		WithArgs("risk-001", "org-001").
+		WillReturnRows(sqlmock.NewRows(riskQuantificationRowColumns))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risks/risk-001/quantification", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeleteRiskQuantification_Success(t *testing.T) {
+	router, mock := setupRiskQuantificationRouter(models.RoleComplianceManager)
+
+	mock.ExpectQuery("SELECT owner_id FROM risks").
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow(nil))
+	mock.ExpectExec("DELETE FROM risk_quantifications").
+		WithArgs("risk-001", "org-001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/risks/risk-001/quantification", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectFrequentRiskQuantifications mocks n quantified risks that each allow up to 365 loss
+// events a year.
+func expectFrequentRiskQuantifications(mock sqlmock.Sqlmock, n int) {
+	cols := append([]string{"identifier", "title", "category"}, riskQuantificationRowColumns...)
+	rows := sqlmock.NewRows(cols)
+	for i := 0; i < n; i++ {
+		riskID := fmt.Sprintf("risk-%03d", i)
+		values := riskQuantificationValues(riskID, "USD", int64(i))
+		values[5] = 365.0
+		rows.AddRow(append([]driver.Value{riskID, "Phishing", "cyber_security"}, values...)...)
+	}
+	mock.ExpectQuery("FROM risk_quantifications q\\s+JOIN risks r").WillReturnRows(rows)
+}
+
+func TestGetRiskQuantificationSummary_IterationsCapped(t *testing.T) {
+	router, mock := setupRiskQuantificationRouter(models.RoleAuditor)
+	expectFrequentRiskQuantifications(mock, 10)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risks/quantification/summary?iterations=10000", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	want := float64(models.MaxSummarySampledValues / (10 * 366))
+	assert.Equal(t, want, data["iterations"])
+	assert.Equal(t, want, data["register"].(map[string]interface{})["iterations"])
+}
+
+func TestGetRiskQuantificationSummary_TooLarge(t *testing.T) {
+	router, mock := setupRiskQuantificationRouter(models.RoleAuditor)
+	expectFrequentRiskQuantifications(mock, 60)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risks/quantification/summary", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRiskQuantificationSummary_IterationsAboveSummaryLimit(t *testing.T) {
+	router, _ := setupRiskQuantificationRouter(models.RoleAuditor)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risks/quantification/summary?iterations=100000", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetRiskQuantificationSummary(t *testing.T) {
+	router, mock := setupRiskQuantificationRouter(models.RoleAuditor)
+
+	cols := append([]string{"identifier", "title", "category"}, riskQuantificationRowColumns...)
+	rows := sqlmock.NewRows(cols).
+		AddRow(append([]driver.Value{"RISK-001", "Ransomware", "cyber_security"}, riskQuantificationValues("risk-001", "USD", 1)...)...).
+		AddRow(append([]driver.Value{"RISK-002", "Vendor breach", "third_party"}, riskQuantificationValues("risk-002", "USD", 2)...)...).
+		AddRow(append([]driver.Value{"RISK-003", "EU fine", "compliance"}, riskQuantificationValues("risk-003", "EUR", 3)...)...)
+	mock.ExpectQuery("FROM risk_quantifications q\\s+JOIN risks r").
+		WithArgs("org-001").
+		WillReturnRows(rows)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risks/quantification/summary?iterations=2000", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["quantified_risks"])
+	assert.Equal(t, float64(1), data["excluded_by_currency"].(map[string]interface{})["EUR"])
+
+	risks := data["risks"].([]interface{})
+	require.Len(t, risks, 2)
+	a := risks[0].(map[string]interface{})
+	b := risks[1].(map[string]interface{})
+	register := data["register"].(map[string]interface{})
+	assert.Equal(t, float64(2000), register["iterations"])
+	assert.InDelta(t, a["annualized_loss_expectancy"].(float64)+b["annualized_loss_expectancy"].(float64),
+		register["annualized_loss_expectancy"], 0.05, "register ALE is the sum of risk ALEs")
+	assert.InDelta(t, 1.0, a["share_of_ale"].(float64)+b["share_of_ale"].(float64), 0.001)
+	assert.Len(t, data["by_category"], 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
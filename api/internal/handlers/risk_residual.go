@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// residualRisk is a risk with the inputs its residual derivation needs.
+type residualRisk struct {
+	ID, Identifier, Title, Category, Status string
+	OwnerID                                 *string
+	Inputs                                  services.ResidualInputs
+}
+
+// loadResidualRisks gathers derivation inputs for the org's open risks (anything not closed or
+// archived), or for one risk of any status when riskID is given. Risks are returned in
+// identifier order.
+func loadResidualRisks(orgID string, riskID *string, method *models.RiskMethodology) ([]*residualRisk, error) {
+	filter := func(col, param string) (string, []interface{}) {
+		if riskID == nil {
+			return "", nil
+		}
+		return fmt.Sprintf(" AND %s = %s", col, param), []interface{}{*riskID}
+	}
+
+	now := time.Now()
+	cond, extra := filter("r.id", "$2")
+	if riskID == nil {
+		cond = " AND r.status NOT IN ('closed', 'archived')"
+	}
+	rows, err := database.DB.Query(`
+		SELECT r.id, r.identifier, r.title, r.category, r.status, r.owner_id,
+		       r.inherent_likelihood, r.inherent_impact,
+		       r.residual_likelihood, r.residual_impact, r.residual_score,
+		       ra.assessment_date, ra.valid_until
+		FROM risks r
+		LEFT JOIN risk_assessments ra ON ra.risk_id = r.id
+			AND ra.assessment_type = 'residual' AND ra.is_current = TRUE
+		WHERE r.org_id = $1 AND r.is_template = FALSE`+cond+`
+		ORDER BY r.identifier ASC
+	`, append([]interface{}{orgID}, extra...)...)
+	if err != nil {
+		return nil, fmt.Errorf("query risks: %w", err)
+	}
+	defer rows.Close()
+
+	risks := []*residualRisk{}
+	byID := map[string]*residualRisk{}
+	for rows.Next() {
+		r := &residualRisk{}
+		var (
+			resLikelihood, resImpact *string
+			resScore                 *float64
+			assessedAt, validUntil   *time.Time
+		)
+		if err := rows.Scan(&r.ID, &r.Identifier, &r.Title, &r.Category, &r.Status, &r.OwnerID,
+			&r.Inputs.InherentLikelihood, &r.Inputs.InherentImpact,
+			&resLikelihood, &resImpact, &resScore, &assessedAt, &validUntil); err != nil {
+			return nil, fmt.Errorf("scan risk: %w", err)
+		}
+		r.Inputs.Methodology = method
+		r.Inputs.Now = now
+		if resLikelihood != nil && resImpact != nil && resScore != nil {
+			r.Inputs.Recorded = &services.RecordedResidual{
+				Likelihood: *resLikelihood, Impact: *resImpact, Score: *resScore,
+				AssessedAt: assessedAt, ValidUntil: validUntil,
+			}
+		}
+		risks = append(risks, r)
+		byID[r.ID] = r
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate risks: %w", err)
+	}
+	if len(risks) == 0 {
+		return risks, nil
+	}
+
+	// Linked controls with their test results over the effectiveness window
+	cond, extra = filter("rc.risk_id", "$3")
+	ctrlRows, err := database.DB.Query(`
+		SELECT rc.risk_id, c.id, c.identifier, c.title, rc.effectiveness, rc.mitigation_percentage, rc.updated_at,
+		       COUNT(tr.id) FILTER (WHERE tr.status = 'pass'),
+		       COUNT(tr.id) FILTER (WHERE tr.status = 'warning'),
+		       COUNT(tr.id) FILTER (WHERE tr.status = 'fail'),
+		       COUNT(tr.id) FILTER (WHERE tr.status = 'error')
+		FROM risk_controls rc
+		JOIN controls c ON c.id = rc.control_id
+		LEFT JOIN test_results tr ON tr.control_id = rc.control_id AND tr.org_id = rc.org_id
+			AND tr.created_at > NOW() - INTERVAL '1 day' * $2
+		WHERE rc.org_id = $1`+cond+`
+		GROUP BY rc.id, c.id
+		ORDER BY rc.created_at ASC
+	`, append([]interface{}{orgID, services.EffectivenessTestWindowDays}, extra...)...)
+	if err != nil {
+		return nil, fmt.Errorf("query risk controls: %w", err)
+	}
+	defer ctrlRows.Close()
+	for ctrlRows.Next() {
+		var (
+			rID                        string
+			ctrl                       services.ResidualControlInput
+			pass, warn, fail, errCount int
+		)
+		if err := ctrlRows.Scan(&rID, &ctrl.ControlID, &ctrl.Identifier, &ctrl.Title, &ctrl.Effectiveness,
+			&ctrl.MitigationPercentage, &ctrl.ChangedAt, &pass, &warn, &fail, &errCount); err != nil {
+			return nil, fmt.Errorf("scan risk control: %w", err)
+		}
+		ctrl.TestResultCounts = map[string]int{"pass": pass, "warning": warn, "fail": fail, "error": errCount}
+		if r, ok := byID[rID]; ok {
+			r.Inputs.Controls = append(r.Inputs.Controls, ctrl)
+		}
+	}
+	if err := ctrlRows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate risk controls: %w", err)
+	}
+
+	// Only verified treatments have demonstrated an effect
+	cond, extra = filter("rt.risk_id", "$3")
+	treatRows, err := database.DB.Query(`
+		SELECT rt.risk_id, rt.id, rt.title, rt.treatment_type, rt.effectiveness_rating,
+		       COALESCE(rt.effectiveness_reviewed_at, rt.completed_at, rt.updated_at)
+		FROM risk_treatments rt
+		WHERE rt.org_id = $1 AND rt.status = $2`+cond+`
+		ORDER BY rt.created_at ASC
+	`, append([]interface{}{orgID, models.TreatmentStatusVerified}, extra...)...)
+	if err != nil {
+		return nil, fmt.Errorf("query risk treatments: %w", err)
+	}
+	defer treatRows.Close()
+	for treatRows.Next() {
+		var (
+			rID string
+			t   services.ResidualTreatmentInput
+		)
+		if err := treatRows.Scan(&rID, &t.TreatmentID, &t.Title, &t.TreatmentType, &t.EffectivenessRating, &t.VerifiedAt); err != nil {
+			return nil, fmt.Errorf("scan risk treatment: %w", err)
+		}
+		if r, ok := byID[rID]; ok {
+			r.Inputs.Treatments = append(r.Inputs.Treatments, t)
+		}
+	}
+	if err := treatRows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate risk treatments: %w", err)
+	}
+	return risks, nil
+}
+
+func methodologySummary(m *models.RiskMethodology) gin.H {
+	return gin.H{"id": m.ID, "version": m.Version, "name": m.Name}
+}
+
+// GetRiskResidualDerivation suggests a residual likelihood and impact for a risk from its linked
+// controls (with live test health) and verified treatments, and flags a recorded residual that
+// is stale or inconsistent with them.
+func GetRiskResidualDerivation(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	riskID := c.Param("id")
+
+	method, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to derive residual risk"))
+		return
+	}
+
+	risks, err := loadResidualRisks(orgID, &riskID, method)
+	if err != nil {
+		log.Error().Err(err).Str("risk_id", riskID).Msg("Failed to load residual derivation inputs")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to derive residual risk"))
+		return
+	}
+	if len(risks) == 0 {
+		c.JSON(http.StatusNotFound, errorResponse("NOT_FOUND", "Risk not found"))
+		return
+	}
+	r := risks[0]
+	d := services.DeriveResidualRisk(r.Inputs)
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"risk": gin.H{
+			"id":         r.ID,
+			"identifier": r.Identifier,
+			"title":      r.Title,
+			"status":     r.Status,
+		},
+		"methodology":          methodologySummary(method),
+		"inherent":             d.Inherent,
+		"recorded":             d.Recorded,
+		"suggested":            d.Suggested,
+		"likelihood_reduction": d.LikelihoodReduction,
+		"impact_reduction":     d.ImpactReduction,
+		"factors":              d.Factors,
+		"flags":                d.Flags,
+		"explanation":          d.Explanation,
+	}))
+}
+
+// ListResidualReview lists open risks whose residual score is missing, stale or inconsistent
+// with their controls and treatments. Filter with ?flag=<code>.
+func ListResidualReview(c *gin.Context) {
+	orgID := middleware.GetOrgID(c)
+	flagFilter := c.Query("flag")
+
+	method, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to review residual risk"))
+		return
+	}
+
+	risks, err := loadResidualRisks(orgID, nil, method)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load residual derivation inputs")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to review residual risk"))
+		return
+	}
+
+	results := []gin.H{}
+	flagged := 0
+	byFlag := map[string]int{}
+	for _, r := range risks {
+		d := services.DeriveResidualRisk(r.Inputs)
+		if len(d.Flags) == 0 {
+			continue
+		}
+		flagged++
+		codes := []string{}
+		seen := map[string]bool{}
+		for _, f := range d.Flags {
+			if !seen[f.Code] {
+				seen[f.Code] = true
+				codes = append(codes, f.Code)
+				byFlag[f.Code]++
+			}
+		}
+		if flagFilter != "" && !seen[flagFilter] {
+			continue
+		}
+		results = append(results, gin.H{
+			"id":         r.ID,
+			"identifier": r.Identifier,
+			"title":      r.Title,
+			"category":   r.Category,
+			"status":     r.Status,
+			"owner_id":   r.OwnerID,
+			"recorded":   d.Recorded,
+			"suggested":  d.Suggested,
+			"flag_codes": codes,
+			"flags":      d.Flags,
+		})
+	}
+
+	c.JSON(http.StatusOK, successResponse(c, gin.H{
+		"methodology": methodologySummary(method),
+		"risks":       results,
+		"summary": gin.H{
+			"active_risks":  len(risks),
+			"flagged_risks": flagged,
+			"by_flag":       byFlag,
+		},
+	}))
+}
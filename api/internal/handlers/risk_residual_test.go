@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRiskResidualRouter(role string) (*gin.Engine, sqlmock.Sqlmock) {
+	router, mock := setupTestRouter()
+	middleware.SetAuditDB(nil)
+
+	protected := router.Group("/api/v1")
+	protected.Use(func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-001")
+		c.Set(middleware.ContextKeyOrgID, "org-001")
+		c.Set(middleware.ContextKeyRole, role)
+		c.Next()
+	})
+	protected.GET("/risks/residual-review", ListResidualReview)
+	protected.GET("/risks/:id/residual-derivation", GetRiskResidualDerivation)
+	return router, mock
+}
+
+var (
+	residualRiskColumns = []string{
+		"id", "identifier", "title", "category", "status", "owner_id",
+		"inherent_likelihood", "inherent_impact",
+		"residual_likelihood", "residual_impact", "residual_score",
+		"assessment_date", "valid_until",
+	}
+	residualControlColumns = []string{
+		"risk_id", "id", "identifier", "title", "effectiveness", "mitigation_percentage", "updated_at",
+		"pass", "warning", "fail", "error",
+	}
+	residualTreatmentColumns = []string{
+		"risk_id", "id", "title", "treatment_type", "effectiveness_rating", "verified_at",
+	}
+)
+
+func TestGetRiskResidualDerivation_Success(t *testing.T) {
+	router, mock := setupRiskResidualRouter(models.RoleAuditor)
+	now := time.Now()
+	assessed := now.AddDate(0, -2, 0)
+
+	expectDefaultRiskMethodology(mock)
+	mock.ExpectQuery("FROM risks r\\s+LEFT JOIN risk_assessments ra").
+		WithArgs("org-001", "risk-001").
+		WillReturnRows(sqlmock.NewRows(residualRiskColumns).
+			AddRow("risk-001", "RISK-CY-001", "Ransomware", "cyber_security", "treating", "user-002",
+				"likely", "major", "possible", "moderate", 9.0, assessed, nil))
+	mock.ExpectQuery("FROM risk_controls rc").
+		WithArgs("org-001", 30, "risk-001").
+		WillReturnRows(sqlmock.NewRows(residualControlColumns).
+			AddRow("risk-001", "ctrl-001", "CTRL-BK-001", "Offline backups", "effective", nil, now.AddDate(0, 0, -3), 4, 0, 0, 0).
+			AddRow("risk-001", "ctrl-002", "CTRL-EP-002", "EDR", "effective", 40, assessed, 1, 0, 3, 0))
+	mock.ExpectQuery("FROM risk_treatments rt").
+		WithArgs("org-001", "verified", "risk-001").
+		WillReturnRows(sqlmock.NewRows(residualTreatmentColumns))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risks/risk-001/residual-derivation", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+
+	suggested := data["suggested"].(map[string]interface{})
+	assert.Equal(t, "unlikely", suggested["likelihood"])
+	assert.Equal(t, "major", suggested["impact"])
+	assert.Equal(t, float64(8), suggested["score"])
+	assert.Equal(t, 0.5, data["likelihood_reduction"])
+
+	factors := data["factors"].([]interface{})
+	require.Len(t, factors, 2)
+	assert.NotNil(t, factors[1].(map[string]interface{})["live_health"])
+
+	codes := []string{}
+	for _, f := range data["flags"].([]interface{}) {
+		codes = append(codes, f.(map[string]interface{})["code"].(string))
+	}
+	assert.ElementsMatch(t, []string{"control_health_degraded", "residual_stale"}, codes)
+	assert.NotEmpty(t, data["explanation"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRiskResidualDerivation_NotFound(t *testing.T) {
+	router, mock := setupRiskResidualRouter(models.RoleAuditor)
+
+	expectDefaultRiskMethodology(mock)
+	mock.ExpectQuery("FROM risks r").
+		WithArgs("org-001", "risk-404").
+		WillReturnRows(sqlmock.NewRows(residualRiskColumns))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risks/risk-404/residual-derivation", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListResidualReview_FiltersByFlag(t *testing.T) {
+	router, mock := setupRiskResidualRouter(models.RoleComplianceManager)
+	now := time.Now()
+
+	expectDefaultRiskMethodology(mock)
+	mock.ExpectQuery("FROM risks r").
+		WithArgs("org-001").
+		WillReturnRows(sqlmock.NewRows(residualRiskColumns).
+			// Residual never recorded despite a linked control
+			AddRow("risk-001", "RISK-001", "Phishing", "cyber_security", "open", nil,
+				"likely", "moderate", nil, nil, nil, nil, nil).
+			// Recorded residual claims far more reduction than anything supports
+			AddRow("risk-002", "RISK-002", "Vendor outage", "third_party", "monitoring", nil,
+				"possible", "major", "rare", "minor", 2.0, now, nil).
+			// Consistent: nothing acts on the risk and the residual equals the inherent score
+			AddRow("risk-003", "RISK-003", "Office flood", "physical", "monitoring", nil,
+				"rare", "minor", "rare", "minor", 2.0, now, nil))
+	mock.ExpectQuery("FROM risk_controls rc").
+		WithArgs("org-001", 30).
+		WillReturnRows(sqlmock.NewRows(residualControlColumns).
+			AddRow("risk-001", "ctrl-001", "CTRL-AT-001", "Awareness training", "partially_effective", nil, now, 0, 0, 0, 0))
+	mock.ExpectQuery("FROM risk_treatments rt").
+		WithArgs("org-001", "verified").
+		WillReturnRows(sqlmock.NewRows(residualTreatmentColumns))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/risks/residual-review?flag=residual_understated", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+
+	risks := data["risks"].([]interface{})
+	require.Len(t, risks, 1)
+	assert.Equal(t, "RISK-002", risks[0].(map[string]interface{})["identifier"])
+
+	summary := data["summary"].(map[string]interface{})
+	assert.Equal(t, float64(3), summary["active_risks"])
+	assert.Equal(t, float64(2), summary["flagged_risks"])
+	byFlag := summary["by_flag"].(map[string]interface{})
+	assert.Equal(t, float64(1), byFlag["residual_missing"])
+	assert.Equal(t, float64(1), byFlag["residual_understated"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
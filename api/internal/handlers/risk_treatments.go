@@ -274,15 +274,21 @@ func CreateRiskTreatment(c *gin.Context) {
 	// Compute expected residual score
 	var expResScore *float64
 	if req.ExpectedResidualLikelihood != nil && req.ExpectedResidualImpact != nil {
-		if !models.IsValidLikelihood(*req.ExpectedResidualLikelihood) {
+		method, err := activeRiskMethodology(orgID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to load risk methodology")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to create treatment"))
+			return
+		}
+		if !method.IsValidLikelihood(*req.ExpectedResidualLikelihood) {
 			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid expected_residual_likelihood"))
 			return
 		}
-		if !models.IsValidImpact(*req.ExpectedResidualImpact) {
+		if !method.IsValidImpact(*req.ExpectedResidualImpact) {
 			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid expected_residual_impact"))
 			return
 		}
-		score := float64(models.LikelihoodScore(*req.ExpectedResidualLikelihood) * models.ImpactScore(*req.ExpectedResidualImpact))
+		score := float64(method.LikelihoodScore(*req.ExpectedResidualLikelihood) * method.ImpactScore(*req.ExpectedResidualImpact))
 		expResScore = &score
 	}
 
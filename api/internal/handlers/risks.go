@@ -55,6 +55,13 @@ func ListRisks(c *gin.Context) {
 		perPage = 20
 	}
 
+	method, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to list risks"))
+		return
+	}
+
 	where := []string{"r.org_id = $1"}
 	args := []interface{}{orgID}
 	argN := 2
@@ -83,16 +90,9 @@ func ListRisks(c *gin.Context) {
 		argN++
 	}
 	if v := c.Query("severity"); v != "" {
-		// Severity is computed from residual_score
-		switch v {
-		case "critical":
-			where = append(where, "r.residual_score >= 20")
-		case "high":
-			where = append(where, "r.residual_score >= 12 AND r.residual_score < 20")
-		case "medium":
-			where = append(where, "r.residual_score >= 6 AND r.residual_score < 12")
-		case "low":
-			where = append(where, "r.residual_score < 6")
+		// Severity is computed from residual_score using the active methodology's bands
+		if cond, ok := severityScoreCondition(method, v, "r.residual_score", false); ok && cond != "" {
+			where = append(where, cond)
 		}
 	}
 	if v := c.Query("score_min"); v != "" {
@@ -177,7 +177,7 @@ func ListRisks(c *gin.Context) {
 	// Count
 	var total int
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM risks r WHERE %s", whereClause)
-	err := database.DB.QueryRow(countQuery, args...).Scan(&total)
+	err = database.DB.QueryRow(countQuery, args...).Scan(&total)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to count risks")
 		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to count risks"))
@@ -192,6 +192,7 @@ func ListRisks(c *gin.Context) {
 		       r.inherent_likelihood, r.inherent_impact, r.inherent_score,
 		       r.residual_likelihood, r.residual_impact, r.residual_score,
 		       r.risk_appetite_threshold,
+		       ap.appetite_threshold, ap.tolerance_threshold, ap.appetite_source, ap.position,
 		       r.assessment_frequency_days, r.next_assessment_at, r.last_assessed_at,
 		       r.source, r.affected_assets, r.tags,
 		       r.created_at, r.updated_at,
@@ -201,6 +202,7 @@ func ListRisks(c *gin.Context) {
 		       (SELECT COUNT(*) FROM risk_treatments rt WHERE rt.risk_id = r.id AND rt.status NOT IN ('cancelled','verified','ineffective')) AS active_treatments_count
 		FROM risks r
 		LEFT JOIN users u ON r.owner_id = u.id
+		LEFT JOIN risk_appetite_positions ap ON ap.risk_id = r.id
 		WHERE %s
 		ORDER BY %s %s NULLS LAST
 		LIMIT $%d OFFSET $%d
@@ -223,6 +225,8 @@ func ListRisks(c *gin.Context) {
 			inhLikelihood, inhImpact                *string
 			resLikelihood, resImpact                *string
 			inhScore, resScore, appetiteThreshold   *float64
+			effAppetite, tolerance                  *float64
+			appetiteSource, appetitePosition        *string
 			assessFreq                              *int
 			nextAssessAt, lastAssessAt              *time.Time
 			source                                  *string
@@ -238,6 +242,7 @@ func ListRisks(c *gin.Context) {
 			&inhLikelihood, &inhImpact, &inhScore,
 			&resLikelihood, &resImpact, &resScore,
 			&appetiteThreshold,
+			&effAppetite, &tolerance, &appetiteSource, &appetitePosition,
 			&assessFreq, &nextAssessAt, &lastAssessAt,
 			&source, &affectedAssets, &tags,
 			&createdAt, &updatedAt,
@@ -262,7 +267,7 @@ func ListRisks(c *gin.Context) {
 				"likelihood": *inhLikelihood,
 				"impact":     *inhImpact,
 				"score":      *inhScore,
-				"severity":   models.ScoreSeverity(*inhScore),
+				"severity":   method.Severity(*inhScore),
 			}
 		}
 		if resLikelihood != nil && resImpact != nil && resScore != nil {
@@ -270,14 +275,11 @@ func ListRisks(c *gin.Context) {
 				"likelihood": *resLikelihood,
 				"impact":     *resImpact,
 				"score":      *resScore,
-				"severity":   models.ScoreSeverity(*resScore),
+				"severity":   method.Severity(*resScore),
 			}
 		}
 
-		appetiteBreached := false
-		if resScore != nil && appetiteThreshold != nil {
-			appetiteBreached = *resScore > *appetiteThreshold
-		}
+		appetiteBreached := isAppetiteBreach(appetitePosition)
 
 		result := gin.H{
 			"id":                     id,
@@ -291,6 +293,7 @@ func ListRisks(c *gin.Context) {
 			"residual_score":         residualScoreObj,
 			"risk_appetite_threshold": appetiteThreshold,
 			"appetite_breached":      appetiteBreached,
+			"appetite":               appetiteObject(effAppetite, tolerance, appetiteSource, appetitePosition),
 			"assessment_frequency_days": assessFreq,
 			"next_assessment_at":     nextAssessAt,
 			"last_assessed_at":       lastAssessAt,
@@ -320,6 +323,8 @@ func GetRisk(c *gin.Context) {
 		inhLikelihood, inhImpact                *string
 		resLikelihood, resImpact                *string
 		inhScore, resScore, appetiteThreshold   *float64
+		effAppetite, tolerance                  *float64
+		appetiteSource, appetitePosition        *string
 		acceptedAt                              *time.Time
 		acceptedBy, acceptJustification         *string
 		acceptExpiry                            *time.Time
@@ -341,6 +346,7 @@ func GetRisk(c *gin.Context) {
 		       r.inherent_likelihood, r.inherent_impact, r.inherent_score,
 		       r.residual_likelihood, r.residual_impact, r.residual_score,
 		       r.risk_appetite_threshold,
+		       ap.appetite_threshold, ap.tolerance_threshold, ap.appetite_source, ap.position,
 		       r.accepted_at, r.accepted_by, r.acceptance_justification, r.acceptance_expiry,
 		       r.assessment_frequency_days, r.next_assessment_at, r.last_assessed_at,
 		       r.source, r.affected_assets, r.is_template, r.template_source_id,
@@ -351,6 +357,7 @@ func GetRisk(c *gin.Context) {
 		FROM risks r
 		LEFT JOIN users u ON r.owner_id = u.id
 		LEFT JOIN users u2 ON r.secondary_owner_id = u2.id
+		LEFT JOIN risk_appetite_positions ap ON ap.risk_id = r.id
 		WHERE r.id = $1 AND r.org_id = $2
 	`, riskID, orgID).Scan(
 		&id, &identifier, &title, &description, &category, &status,
@@ -358,6 +365,7 @@ func GetRisk(c *gin.Context) {
 		&inhLikelihood, &inhImpact, &inhScore,
 		&resLikelihood, &resImpact, &resScore,
 		&appetiteThreshold,
+		&effAppetite, &tolerance, &appetiteSource, &appetitePosition,
 		&acceptedAt, &acceptedBy, &acceptJustification, &acceptExpiry,
 		&assessFreq, &nextAssessAt, &lastAssessAt,
 		&source, &affectedAssets, &isTemplate, &templateSourceID,
@@ -385,33 +393,37 @@ func GetRisk(c *gin.Context) {
 		secondaryOwner = gin.H{"id": *secondaryOwnerID, "name": secondaryName.String, "email": secondaryEmail.String}
 	}
 
+	method, err := activeRiskMethodology(orgID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load risk methodology")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to get risk"))
+		return
+	}
+
 	// Build scores
 	var inherentScoreObj, residualScoreObj interface{}
 	if inhLikelihood != nil && inhImpact != nil && inhScore != nil {
 		inherentScoreObj = gin.H{
 			"likelihood":       *inhLikelihood,
-			"likelihood_score": models.LikelihoodScore(*inhLikelihood),
+			"likelihood_score": method.LikelihoodScore(*inhLikelihood),
 			"impact":           *inhImpact,
-			"impact_score":     models.ImpactScore(*inhImpact),
+			"impact_score":     method.ImpactScore(*inhImpact),
 			"score":            *inhScore,
-			"severity":         models.ScoreSeverity(*inhScore),
+			"severity":         method.Severity(*inhScore),
 		}
 	}
 	if resLikelihood != nil && resImpact != nil && resScore != nil {
 		residualScoreObj = gin.H{
 			"likelihood":       *resLikelihood,
-			"likelihood_score": models.LikelihoodScore(*resLikelihood),
+			"likelihood_score": method.LikelihoodScore(*resLikelihood),
 			"impact":           *resImpact,
-			"impact_score":     models.ImpactScore(*resImpact),
+			"impact_score":     method.ImpactScore(*resImpact),
 			"score":            *resScore,
-			"severity":         models.ScoreSeverity(*resScore),
+			"severity":         method.Severity(*resScore),
 		}
 	}
 
-	appetiteBreached := false
-	if resScore != nil && appetiteThreshold != nil {
-		appetiteBreached = *resScore > *appetiteThreshold
-	}
+	appetiteBreached := isAppetiteBreach(appetitePosition)
 
 	// Acceptance
 	var acceptance interface{}
@@ -521,6 +533,7 @@ func GetRisk(c *gin.Context) {
 		"residual_score":            residualScoreObj,
 		"risk_appetite_threshold":   appetiteThreshold,
 		"appetite_breached":         appetiteBreached,
+		"appetite":                  appetiteObject(effAppetite, tolerance, appetiteSource, appetitePosition),
 		"acceptance":                acceptance,
 		"assessment_frequency_days": assessFreq,
 		"next_assessment_at":        nextAssessAt,
@@ -565,27 +578,39 @@ func CreateRisk(c *gin.Context) {
 		return
 	}
 
+	// Scores and thresholds are validated against the org's active methodology
+	var method *models.RiskMethodology
+	if req.RiskAppetiteThreshold != nil || req.InitialAssessment != nil {
+		var err error
+		method, err = activeRiskMethodology(orgID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to load risk methodology")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to create risk"))
+			return
+		}
+	}
+
 	// Validate appetite threshold
-	if req.RiskAppetiteThreshold != nil && (*req.RiskAppetiteThreshold < 1 || *req.RiskAppetiteThreshold > 25) {
-		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Risk appetite threshold must be between 1 and 25"))
+	if req.RiskAppetiteThreshold != nil && (*req.RiskAppetiteThreshold < 1 || *req.RiskAppetiteThreshold > method.MaxScore()) {
+		c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", fmt.Sprintf("Risk appetite threshold must be between 1 and %g", method.MaxScore())))
 		return
 	}
 
 	// Validate initial assessment if provided
 	if req.InitialAssessment != nil {
-		if !models.IsValidLikelihood(req.InitialAssessment.InherentLikelihood) {
+		if !method.IsValidLikelihood(req.InitialAssessment.InherentLikelihood) {
 			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid inherent_likelihood value"))
 			return
 		}
-		if !models.IsValidImpact(req.InitialAssessment.InherentImpact) {
+		if !method.IsValidImpact(req.InitialAssessment.InherentImpact) {
 			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid inherent_impact value"))
 			return
 		}
-		if req.InitialAssessment.ResidualLikelihood != nil && !models.IsValidLikelihood(*req.InitialAssessment.ResidualLikelihood) {
+		if req.InitialAssessment.ResidualLikelihood != nil && !method.IsValidLikelihood(*req.InitialAssessment.ResidualLikelihood) {
 			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid residual_likelihood value"))
 			return
 		}
-		if req.InitialAssessment.ResidualImpact != nil && !models.IsValidImpact(*req.InitialAssessment.ResidualImpact) {
+		if req.InitialAssessment.ResidualImpact != nil && !method.IsValidImpact(*req.InitialAssessment.ResidualImpact) {
 			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Invalid residual_impact value"))
 			return
 		}
@@ -664,19 +689,20 @@ func CreateRisk(c *gin.Context) {
 		ia := req.InitialAssessment
 
 		// Create inherent assessment
-		inhLScore := models.LikelihoodScore(ia.InherentLikelihood)
-		inhIScore := models.ImpactScore(ia.InherentImpact)
+		inhLScore := method.LikelihoodScore(ia.InherentLikelihood)
+		inhIScore := method.ImpactScore(ia.InherentImpact)
 		inhOverall := float64(inhLScore * inhIScore)
-		inhSeverity := models.ScoreSeverity(inhOverall)
+		inhSeverity := method.Severity(inhOverall)
 		inhAssessID := uuid.New().String()
 
 		_, err = database.DB.Exec(`
 			INSERT INTO risk_assessments (id, org_id, risk_id, assessment_type, likelihood, impact,
-			                              likelihood_score, impact_score, overall_score, scoring_formula,
+			                              likelihood_score, impact_score, overall_score, scoring_formula, severity,
+			                              methodology_id, methodology_version,
 			                              justification, assessed_by, assessment_date, is_current, created_at)
-			VALUES ($1, $2, $3, 'inherent', $4, $5, $6, $7, $8, 'likelihood_x_impact', $9, $10, $11, TRUE, $11)
+			VALUES ($1, $2, $3, 'inherent', $4, $5, $6, $7, $8, 'likelihood_x_impact', $9, $10, $11, $12, $13, $14, TRUE, $14)
 		`, inhAssessID, orgID, riskID, ia.InherentLikelihood, ia.InherentImpact,
-			inhLScore, inhIScore, inhOverall, ia.Justification, userID, now)
+			inhLScore, inhIScore, inhOverall, inhSeverity, method.ID, method.Version, ia.Justification, userID, now)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to create inherent assessment")
 		}
@@ -703,19 +729,20 @@ func CreateRisk(c *gin.Context) {
 
 		// Create residual assessment if provided
 		if ia.ResidualLikelihood != nil && ia.ResidualImpact != nil {
-			resLScore := models.LikelihoodScore(*ia.ResidualLikelihood)
-			resIScore := models.ImpactScore(*ia.ResidualImpact)
+			resLScore := method.LikelihoodScore(*ia.ResidualLikelihood)
+			resIScore := method.ImpactScore(*ia.ResidualImpact)
 			resOverall := float64(resLScore * resIScore)
-			resSeverity := models.ScoreSeverity(resOverall)
+			resSeverity := method.Severity(resOverall)
 			resAssessID := uuid.New().String()
 
 			_, err = database.DB.Exec(`
 				INSERT INTO risk_assessments (id, org_id, risk_id, assessment_type, likelihood, impact,
-				                              likelihood_score, impact_score, overall_score, scoring_formula,
+				                              likelihood_score, impact_score, overall_score, scoring_formula, severity,
+				                              methodology_id, methodology_version,
 				                              justification, assessed_by, assessment_date, is_current, created_at)
-				VALUES ($1, $2, $3, 'residual', $4, $5, $6, $7, $8, 'likelihood_x_impact', $9, $10, $11, TRUE, $11)
+				VALUES ($1, $2, $3, 'residual', $4, $5, $6, $7, $8, 'likelihood_x_impact', $9, $10, $11, $12, $13, $14, TRUE, $14)
 			`, resAssessID, orgID, riskID, *ia.ResidualLikelihood, *ia.ResidualImpact,
-				resLScore, resIScore, resOverall, ia.Justification, userID, now)
+				resLScore, resIScore, resOverall, resSeverity, method.ID, method.Version, ia.Justification, userID, now)
 			if err != nil {
 				log.Error().Err(err).Msg("Failed to create residual assessment")
 			}
@@ -858,8 +885,14 @@ func UpdateRisk(c *gin.Context) {
 		argN++
 	}
 	if req.RiskAppetiteThreshold != nil {
-		if *req.RiskAppetiteThreshold < 1 || *req.RiskAppetiteThreshold > 25 {
-			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", "Risk appetite threshold must be between 1 and 25"))
+		method, err := activeRiskMethodology(orgID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to load risk methodology")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to update risk"))
+			return
+		}
+		if *req.RiskAppetiteThreshold < 1 || *req.RiskAppetiteThreshold > method.MaxScore() {
+			c.JSON(http.StatusBadRequest, errorResponse("VALIDATION_ERROR", fmt.Sprintf("Risk appetite threshold must be between 1 and %g", method.MaxScore())))
 			return
 		}
 		sets = append(sets, fmt.Sprintf("risk_appetite_threshold = $%d", argN))
@@ -1055,8 +1088,34 @@ func ChangeRiskStatus(c *gin.Context) {
 			return
 		}
 
+		// Accepting a risk above tolerance needs CISO sign-off
+		gate, err := loadRiskToleranceGate(riskID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get risk appetite position")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to change risk status"))
+			return
+		}
+		if reason := gate.cisoApprovalReason(userRole, "accept this risk"); reason != "" {
+			c.JSON(http.StatusForbidden, errorResponse("CISO_APPROVAL_REQUIRED", reason))
+			return
+		}
+		position, residual := gate.Position, gate.Residual
+
+		var appetitePosition *string
+		if position != "" {
+			appetitePosition = &position
+		}
+
 		now := time.Now()
-		_, err = database.DB.Exec(`
+		tx, err := database.DB.Begin()
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to begin transaction")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to change risk status"))
+			return
+		}
+		defer tx.Rollback()
+
+		_, err = tx.Exec(`
 			UPDATE risks SET status = 'accepted', accepted_at = $1, accepted_by = $2,
 			                 acceptance_justification = $3, acceptance_expiry = $4, updated_at = $1
 			WHERE id = $5 AND org_id = $6
@@ -1067,8 +1126,26 @@ func ChangeRiskStatus(c *gin.Context) {
 			return
 		}
 
+		// Record the acceptance in the risk's acceptance history
+		_, err = tx.Exec(`
+			INSERT INTO risk_acceptances (org_id, risk_id, status, justification, expires_at,
+				requested_by, requested_at, decided_by, decided_at, appetite_position, residual_score)
+			VALUES ($1, $2, 'active', $3, $4, $5, $6, $5, $6, $7, $8)
+		`, orgID, riskID, *req.Justification, expiry, userID, now, appetitePosition, residual)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to record risk acceptance")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to change risk status"))
+			return
+		}
+
+		if err := tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("Failed to commit risk acceptance")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to change risk status"))
+			return
+		}
+
 		middleware.LogAudit(c, "risk.status_changed", "risk", &riskID, map[string]interface{}{
-			"from": currentStatus, "to": "accepted",
+			"from": currentStatus, "to": "accepted", "appetite_position": position,
 		})
 
 		var userName string
@@ -1078,10 +1155,11 @@ func ChangeRiskStatus(c *gin.Context) {
 			"id":         riskID,
 			"status":     "accepted",
 			"acceptance": gin.H{
-				"accepted_at":   now,
-				"accepted_by":   gin.H{"id": userID, "name": userName},
-				"expiry":        expiry.Format("2006-01-02"),
-				"justification": *req.Justification,
+				"accepted_at":       now,
+				"accepted_by":       gin.H{"id": userID, "name": userName},
+				"expiry":            expiry.Format("2006-01-02"),
+				"justification":     *req.Justification,
+				"appetite_position": position,
 			},
 			"updated_at": now,
 		}))
@@ -1090,7 +1168,15 @@ func ChangeRiskStatus(c *gin.Context) {
 
 	// Normal status transition
 	now := time.Now()
-	_, err = database.DB.Exec(`
+	tx, err := database.DB.Begin()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin transaction")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to change risk status"))
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
 		UPDATE risks SET status = $1, updated_at = $2 WHERE id = $3 AND org_id = $4
 	`, req.Status, now, riskID, orgID)
 	if err != nil {
@@ -1099,13 +1185,37 @@ func ChangeRiskStatus(c *gin.Context) {
 		return
 	}
 
-	// Clear acceptance fields when moving from accepted to active status
+	// Clear acceptance fields when moving from accepted to active status. The acceptance record
+	// must end with them: an active one left behind blocks the risk's next acceptance.
 	if currentStatus == models.RiskStatusAccepted {
-		database.DB.Exec(`
+		_, err = tx.Exec(`
 			UPDATE risks SET accepted_at = NULL, accepted_by = NULL,
 			                 acceptance_justification = NULL, acceptance_expiry = NULL
 			WHERE id = $1
 		`, riskID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to clear risk acceptance")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to change risk status"))
+			return
+		}
+
+		// End the acceptance in force and any renewal still awaiting a decision
+		_, err = tx.Exec(`
+			UPDATE risk_acceptances SET status = CASE WHEN status = 'active' THEN 'revoked' ELSE 'cancelled' END::risk_acceptance_status,
+			                            ended_by = $2, ended_at = $3
+			WHERE risk_id = $1 AND status IN ('active', 'requested')
+		`, riskID, userID, now)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to end risk acceptance")
+			c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to change risk status"))
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("Failed to commit risk status change")
+		c.JSON(http.StatusInternalServerError, errorResponse("INTERNAL_ERROR", "Failed to change risk status"))
+		return
 	}
 
 	middleware.LogAudit(c, "risk.status_changed", "risk", &riskID, map[string]interface{}{
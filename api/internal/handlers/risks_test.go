@@ -100,6 +100,9 @@ func TestCreateRisk_Success(t *testing.T) {
 func TestCreateRisk_WithInitialAssessment(t *testing.T) {
 	router, mock := setupRiskRouter()
 
+	// Scored with the org's active methodology (none saved: built-in default)
+	expectDefaultRiskMethodology(mock)
+
 	// Check identifier uniqueness
 	mock.ExpectQuery("SELECT EXISTS").
 		WithArgs("org-001", "RISK-CY-011").
@@ -211,6 +214,8 @@ func TestListRisks_Success(t *testing.T) {
 
 	now := time.Now()
 
+	expectDefaultRiskMethodology(mock)
+
 	// Count query
 	mock.ExpectQuery("SELECT COUNT").
 		WithArgs("org-001").
@@ -223,6 +228,7 @@ func TestListRisks_Success(t *testing.T) {
 		"inherent_likelihood", "inherent_impact", "inherent_score",
 		"residual_likelihood", "residual_impact", "residual_score",
 		"risk_appetite_threshold",
+		"appetite_threshold", "tolerance_threshold", "appetite_source", "position",
 		"assessment_frequency_days", "next_assessment_at", "last_assessed_at",
 		"source", "affected_assets", "tags",
 		"created_at", "updated_at",
@@ -234,6 +240,7 @@ func TestListRisks_Success(t *testing.T) {
 		"likely", "severe", 20.0,
 		"possible", "major", 12.0,
 		10.0,
+		10.0, 15.0, "risk", "above_appetite",
 		90, now.Add(60*24*time.Hour), now,
 		"threat_assessment", "{}", "{}",
 		now, now,
@@ -256,6 +263,7 @@ func TestListRisks_Success(t *testing.T) {
 	risk := data[0].(map[string]interface{})
 	assert.Equal(t, "RISK-CY-001", risk["identifier"])
 	assert.Equal(t, true, risk["appetite_breached"])
+	assert.Equal(t, "above_appetite", risk["appetite"].(map[string]interface{})["position"])
 }
 
 func TestGetRisk_NotFound(t *testing.T) {
@@ -396,6 +404,8 @@ func TestCreateAssessment_Success(t *testing.T) {
 		WithArgs("risk-001", "org-001").
 		WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow("user-001"))
 
+	expectDefaultRiskMethodology(mock)
+
 	// Check previous current assessment (none)
 	mock.ExpectQuery("SELECT id FROM risk_assessments").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}))
@@ -450,7 +460,12 @@ func TestCreateAssessment_Success(t *testing.T) {
 }
 
 func TestCreateAssessment_InvalidLikelihood(t *testing.T) {
-	router, _ := setupRiskRouter()
+	router, mock := setupRiskRouter()
+
+	// Levels are validated against the org's active methodology
+	mock.ExpectQuery("SELECT owner_id").
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow("user-001"))
+	expectDefaultRiskMethodology(mock)
 
 	body := map[string]interface{}{
 		"assessment_type": "inherent",
@@ -776,6 +791,8 @@ func TestSearchRisks_MissingQuery(t *testing.T) {
 func TestSearchRisks_Success(t *testing.T) {
 	router, mock := setupRiskRouter()
 
+	expectDefaultRiskMethodology(mock)
+
 	mock.ExpectQuery("SELECT COUNT").
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
@@ -440,6 +440,46 @@ func TestResolveAlert_Success(t *testing.T) {
 	assert.Equal(t, "resolved", data["status"])
 }
 
+func TestGetAlert_RiskAlertWithoutControl(t *testing.T) {
+	_, mock := setupTestRouter()
+	r := setupAuthRouter(mock)
+	r.GET("/api/v1/alerts/:id", GetAlert)
+
+	now := time.Now()
+	mock.ExpectQuery("FROM alerts a").WillReturnRows(sqlmock.NewRows([]string{
+		"id", "alert_number", "title", "description", "severity", "status",
+		"control_id", "c_identifier", "c_title", "c_category",
+		"test_id", "t_identifier", "t_title", "test_type",
+		"test_result_id", "alert_rule_id", "rule_name",
+		"assigned_to", "assigned_at", "assigned_by",
+		"sla_deadline", "sla_breached",
+		"resolved_by", "resolved_at", "resolution_notes",
+		"suppressed_until", "suppression_reason",
+		"delivered_at", "metadata",
+		"created_at", "updated_at",
+	}).AddRow("a001", 8, "Risk above tolerance: RISK-001 Ransomware", nil, "high", "open",
+		nil, nil, nil, nil,
+		nil, nil, nil, nil,
+		nil, nil, nil,
+		nil, nil, nil,
+		nil, false,
+		nil, nil, nil,
+		nil, nil,
+		"{}", `{"source": "risk_appetite", "stage": "above_tolerance"}`,
+		now, now))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/alerts/a001", nil)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	data := resp["data"].(map[string]interface{})
+	assert.Nil(t, data["control"])
+	assert.Equal(t, "risk_appetite", data["metadata"].(map[string]interface{})["source"])
+}
+
 func TestSuppressAlert_Success(t *testing.T) {
 	_, mock := setupTestRouter()
 	r := setupAuthRouter(mock)
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/half-paul/raisin-protect/api/internal/auth"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// Context keys for API key authentication.
+const (
+	ContextKeyAPIKeyID    = "api_key_id"
+	ContextKeyAPIKeyOwner = "api_key_owner_id"
+)
+
+var apiKeyDB *sql.DB
+
+// SetAPIKeyDB sets the database connection used to look up API keys.
+func SetAPIKeyDB(db *sql.DB) {
+	apiKeyDB = db
+}
+
+// APIKeyRequired authenticates requests with an org API key (X-API-Key header or
+// "Authorization: ApiKey <key>") that grants the given scope. The org is taken from the key.
+func APIKeyRequired(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKeyDB == nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": gin.H{"code": "INTERNAL_ERROR", "message": "Authentication not configured"},
+			})
+			return
+		}
+
+		raw := c.GetHeader("X-API-Key")
+		if raw == "" {
+			parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+			if len(parts) == 2 && strings.EqualFold(parts[0], "apikey") {
+				raw = parts[1]
+			}
+		}
+		if raw == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": gin.H{"code": "UNAUTHORIZED", "message": "API key required"},
+			})
+			return
+		}
+
+		var keyID, orgID string
+		var createdBy *string
+		var scopes pq.StringArray
+		err := apiKeyDB.QueryRow(`
+			SELECT k.id, k.org_id, k.created_by, k.scopes
+			FROM org_api_keys k
+			JOIN organizations o ON o.id = k.org_id AND o.status = 'active'
+			WHERE k.key_hash = $1 AND k.revoked_at IS NULL
+			  AND (k.expires_at IS NULL OR k.expires_at > NOW())
+		`, auth.HashAPIKey(raw)).Scan(&keyID, &orgID, &createdBy, &scopes)
+		if err == sql.ErrNoRows {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": gin.H{"code": "UNAUTHORIZED", "message": "Invalid, expired or revoked API key"},
+			})
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to look up API key")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": gin.H{"code": "INTERNAL_ERROR", "message": "Internal server error"},
+			})
+			return
+		}
+
+		granted := false
+		for _, s := range scopes {
+			if s == scope {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": gin.H{"code": "FORBIDDEN", "message": "API key does not grant scope " + scope},
+			})
+			return
+		}
+
+		if _, err := apiKeyDB.Exec(`UPDATE org_api_keys SET last_used_at = NOW() WHERE id = $1`, keyID); err != nil {
+			log.Warn().Err(err).Str("api_key_id", keyID).Msg("Failed to record API key use")
+		}
+
+		c.Set(ContextKeyOrgID, orgID)
+		c.Set(ContextKeyAPIKeyID, keyID)
+		if createdBy != nil {
+			c.Set(ContextKeyAPIKeyOwner, *createdBy)
+		}
+
+		c.Next()
+	}
+}
+
+// GetAPIKeyID extracts the authenticating API key ID from context.
+func GetAPIKeyID(c *gin.Context) string {
+	if v, ok := c.Get(ContextKeyAPIKeyID); ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
@@ -51,7 +51,8 @@ func LogAudit(c *gin.Context, action, resourceType string, resourceID *string, m
 	}
 }
 
-// LogAuditWithOrg writes an audit log entry with an explicit org ID (for pre-auth actions like register).
+// LogAuditWithOrg writes an audit log entry with an explicit org ID (for pre-auth actions like
+// register, and background workers, which pass no IP address).
 func LogAuditWithOrg(orgID, actorID *string, action, resourceType string, resourceID *string, metadata map[string]interface{}, ipAddress, userAgent string) {
 	if auditDB == nil {
 		return
@@ -66,7 +67,7 @@ func LogAuditWithOrg(orgID, actorID *string, action, resourceType string, resour
 
 	_, err = auditDB.Exec(`
 		INSERT INTO audit_log (id, org_id, actor_id, action, resource_type, resource_id, metadata, ip_address, user_agent)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::inet, $9)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, '')::inet, $9)
 	`, id, orgID, actorID, action, resourceType, resourceID, string(metadataJSON), ipAddress, userAgent)
 
 	if err != nil {
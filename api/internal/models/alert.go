@@ -13,7 +13,7 @@ type Alert struct {
 	Status            string     `json:"status"`
 	TestID            *string    `json:"test_id"`
 	TestResultID      *string    `json:"test_result_id"`
-	ControlID         string     `json:"control_id"`
+	ControlID         *string    `json:"control_id"`
 	AlertRuleID       *string    `json:"alert_rule_id"`
 	AssignedTo        *string    `json:"assigned_to"`
 	AssignedAt        *time.Time `json:"assigned_at"`
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// API key scopes.
+const (
+	ScopeEvidenceIngest = "evidence:ingest"
+)
+
+// ValidAPIKeyScopes lists scopes that can be granted to an org API key.
+var ValidAPIKeyScopes = []string{ScopeEvidenceIngest}
+
+// APIKeyPrefix marks Raisin Protect API keys so they are recognizable in secret scanners.
+const APIKeyPrefix = "rpk_"
+
+// APIKeyRoles can create and revoke org API keys.
+var APIKeyRoles = AdminRoles
+
+// IsValidAPIKeyScope checks if an API key scope is valid.
+func IsValidAPIKeyScope(s string) bool {
+	for _, v := range ValidAPIKeyScopes {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// OrgAPIKey is a machine credential scoped to an org. The raw key is only returned at creation.
+type OrgAPIKey struct {
+	ID         string     `json:"id"`
+	OrgID      string     `json:"org_id"`
+	Name       string     `json:"name"`
+	KeyPrefix  string     `json:"key_prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedBy  *string    `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyRequest is the request for creating an org API key.
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required"`
+	ExpiresInDays *int     `json:"expires_in_days"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// Attestation campaign statuses.
+const (
+	AttestationCampaignActive    = "active"
+	AttestationCampaignCompleted = "completed"
+	AttestationCampaignCancelled = "cancelled"
+)
+
+// Control attestation statuses.
+const (
+	AttestationStatusPending   = "pending"
+	AttestationStatusAttested  = "attested"
+	AttestationStatusException = "exception"
+	AttestationStatusCancelled = "cancelled"
+)
+
+// ValidAttestationResponses are the statuses an owner can respond with.
+var ValidAttestationResponses = []string{AttestationStatusAttested, AttestationStatusException}
+
+// AttestationCampaignRoles can create, cancel and send reminders for campaigns.
+var AttestationCampaignRoles = []string{RoleCISO, RoleComplianceManager}
+
+// AttestationViewRoles can view campaigns and their progress.
+var AttestationViewRoles = []string{RoleCISO, RoleComplianceManager, RoleAuditor}
+
+// AttestationEvidenceFreshnessDays is how long a generated attestation artifact stays fresh.
+const AttestationEvidenceFreshnessDays = 365
+
+// IsValidAttestationResponse checks if an attestation response status is valid.
+func IsValidAttestationResponse(s string) bool {
+	for _, v := range ValidAttestationResponses {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// AttestationCampaign represents a periodic control ownership attestation campaign.
+type AttestationCampaign struct {
+	ID                   string     `json:"id"`
+	OrgID                string     `json:"org_id"`
+	Name                 string     `json:"name"`
+	Description          *string    `json:"description"`
+	Status               string     `json:"status"`
+	DueDate              *time.Time `json:"due_date"`
+	ReminderIntervalDays *int       `json:"reminder_interval_days"`
+	CreatedBy            *string    `json:"created_by"`
+	CompletedAt          *time.Time `json:"completed_at"`
+	CancelledAt          *time.Time `json:"cancelled_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// ControlAttestation represents one owner's attestation for a control within a campaign.
+type ControlAttestation struct {
+	ID                    string     `json:"id"`
+	OrgID                 string     `json:"org_id"`
+	CampaignID            string     `json:"campaign_id"`
+	ControlID             string     `json:"control_id"`
+	AttesterID            string     `json:"attester_id"`
+	Status                string     `json:"status"`
+	Statement             *string    `json:"statement"`
+	SupportingArtifactIDs []string   `json:"supporting_artifact_ids"`
+	AttestedBy            *string    `json:"attested_by"`
+	AttestedAt            *time.Time `json:"attested_at"`
+	EvidenceArtifactID    *string    `json:"evidence_artifact_id"`
+	ReminderSentAt        *time.Time `json:"reminder_sent_at"`
+	ReminderCount         int        `json:"reminder_count"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+// CreateAttestationCampaignRequest is the request for creating an attestation campaign.
+// When ControlIDs is empty, all active controls (optionally filtered by category) are included.
+type CreateAttestationCampaignRequest struct {
+	Name                 string   `json:"name" binding:"required"`
+	Description          *string  `json:"description"`
+	DueDate              *string  `json:"due_date"`
+	ReminderIntervalDays *int     `json:"reminder_interval_days"`
+	ControlIDs           []string `json:"control_ids"`
+	Category             *string  `json:"category"`
+}
+
+// SubmitAttestationRequest is the request for an owner's attestation response.
+type SubmitAttestationRequest struct {
+	Status                string   `json:"status" binding:"required"`
+	Statement             string   `json:"statement" binding:"required"`
+	SupportingArtifactIDs []string `json:"supporting_artifact_ids"`
+}
+
+// RemindAttestationsRequest is the request for sending attestation reminders.
+type RemindAttestationsRequest struct {
+	AttestationIDs []string `json:"attestation_ids"`
+}
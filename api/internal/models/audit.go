@@ -431,8 +431,9 @@ type BulkCreateRequestsReq struct {
 
 // SubmitEvidenceReq links evidence to a request.
 type SubmitEvidenceReq struct {
-	ArtifactID string  `json:"artifact_id" binding:"required"`
-	Notes      *string `json:"notes"`
+	ArtifactID   string  `json:"artifact_id" binding:"required"`
+	Notes        *string `json:"notes"`
+	SampleItemID *string `json:"sample_item_id"`
 }
 
 // ReviewEvidenceReq for auditor evidence review.
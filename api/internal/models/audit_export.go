@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Audit evidence export statuses.
+const (
+	AuditExportPending   = "pending"
+	AuditExportBuilding  = "building"
+	AuditExportCompleted = "completed"
+	AuditExportFailed    = "failed"
+)
+
+// MaxAuditExportAttempts is how often a failed export build is retried before it is marked failed.
+const MaxAuditExportAttempts = 3
+
+// AuditExportRoles can request and download evidence exports (auditors only for their audits).
+var AuditExportRoles = []string{RoleCISO, RoleComplianceManager, RoleAuditor}
+
+// AuditEvidenceExport is a ZIP bundle of an audit's accepted evidence with a signed manifest.
+type AuditEvidenceExport struct {
+	ID                 string     `json:"id"`
+	AuditID            string     `json:"audit_id"`
+	Status             string     `json:"status"`
+	Attempts           int        `json:"attempts"`
+	RequestedBy        *string    `json:"requested_by"`
+	RequestedByName    string     `json:"requested_by_name"`
+	FileSize           *int64     `json:"file_size"`
+	ChecksumSHA256     *string    `json:"checksum_sha256"`
+	ManifestSHA256     *string    `json:"manifest_sha256"`
+	Signature          *string    `json:"signature"`
+	SigningKeyID       *string    `json:"signing_key_id"`
+	RequestCount       int        `json:"request_count"`
+	ArtifactCount      int        `json:"artifact_count"`
+	FilesIncluded      int        `json:"files_included"`
+	ChecksumMismatches int        `json:"checksum_mismatches"`
+	ErrorMessage       *string    `json:"error_message"`
+	StartedAt          *time.Time `json:"started_at"`
+	CompletedAt        *time.Time `json:"completed_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
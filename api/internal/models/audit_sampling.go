@@ -0,0 +1,103 @@
+package models
+
+import "time"
+
+// Audit sample selection methods.
+const (
+	SampleMethodRandom = "random"
+	SampleMethodManual = "manual"
+)
+
+// Population and sample limits.
+const (
+	MaxPopulationItems = 50000
+	MaxSampleSize      = 1000
+)
+
+// AuditSampleSelectRoles can draw a sample from a request population.
+var AuditSampleSelectRoles = []string{RoleAuditor}
+
+// AuditPopulation is the list of items (e.g. change tickets) a request is sampled from.
+type AuditPopulation struct {
+	ID             string    `json:"id"`
+	RequestID      string    `json:"request_id"`
+	Name           string    `json:"name"`
+	SourceFileName *string   `json:"source_file_name"`
+	KeyColumn      *string   `json:"key_column"`
+	Columns        []string  `json:"columns"`
+	ItemCount      int       `json:"item_count"`
+	ChecksumSHA256 string    `json:"checksum_sha256"`
+	UploadedBy     *string   `json:"uploaded_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// AuditPopulationItem is one population entry. Position is 1-based upload order.
+type AuditPopulationItem struct {
+	ID         string                 `json:"id"`
+	Position   int                    `json:"position"`
+	ItemKey    string                 `json:"item_key"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// PopulationItemInput is one item in a JSON population upload.
+type PopulationItemInput struct {
+	Key        string                 `json:"key"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// UploadPopulationRequest is the JSON form of a population upload; CSV files can be sent as
+// multipart instead.
+type UploadPopulationRequest struct {
+	Name  string                `json:"name"`
+	Items []PopulationItemInput `json:"items"`
+}
+
+// SelectSampleRequest draws a sample. Random samples use Seed when given, otherwise a random
+// seed is generated and recorded; manual samples list the chosen item keys.
+type SelectSampleRequest struct {
+	Method     string   `json:"method"`
+	SampleSize int      `json:"sample_size"`
+	Seed       *int64   `json:"seed"`
+	ItemKeys   []string `json:"item_keys"`
+	Notes      *string  `json:"notes"`
+}
+
+// AuditSample is a sample drawn from a request population.
+type AuditSample struct {
+	ID                 string            `json:"id"`
+	RequestID          string            `json:"request_id"`
+	PopulationID       string            `json:"population_id"`
+	Method             string            `json:"method"`
+	Seed               *int64            `json:"seed"`
+	SampleSize         int               `json:"sample_size"`
+	PopulationSize     int               `json:"population_size"`
+	PopulationChecksum string            `json:"population_checksum"`
+	Notes              *string           `json:"notes"`
+	SelectedBy         *string           `json:"selected_by"`
+	CreatedAt          time.Time         `json:"created_at"`
+	Items              []AuditSampleItem `json:"items"`
+	Summary            map[string]int    `json:"summary"`
+}
+
+// AuditSampleItem is a selected population item and the evidence submitted for it.
+type AuditSampleItem struct {
+	ID               string                 `json:"id"`
+	SampleOrder      int                    `json:"sample_order"`
+	PopulationItemID string                 `json:"population_item_id"`
+	Position         int                    `json:"position"`
+	ItemKey          string                 `json:"item_key"`
+	Attributes       map[string]interface{} `json:"attributes"`
+	Status           string                 `json:"status"`
+	EvidenceCount    int                    `json:"evidence_count"`
+	AcceptedCount    int                    `json:"accepted_count"`
+}
+
+// Sample item statuses, derived from the evidence submitted for the item.
+const (
+	SampleItemRequested          = "requested"
+	SampleItemPendingReview      = "pending_review"
+	SampleItemNeedsClarification = "needs_clarification"
+	SampleItemRejected           = "rejected"
+	SampleItemAccepted           = "accepted"
+)
@@ -20,6 +20,9 @@ type Control struct {
 	SourceTemplateID        *string   `json:"source_template_id"`
 	Metadata                string    `json:"metadata"` // raw JSON
 	MappingsCount           int       `json:"mappings_count,omitempty"`
+	EffectivenessScore      *float64  `json:"effectiveness_score"`
+	EffectivenessRating     string    `json:"effectiveness_rating"`
+	EffectivenessCalcAt     *time.Time `json:"effectiveness_calculated_at"`
 	CreatedAt               time.Time `json:"created_at"`
 	UpdatedAt               time.Time `json:"updated_at"`
 }
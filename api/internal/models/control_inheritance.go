@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// Inheritance source types.
+const (
+	InheritanceSourceProvider     = "provider"
+	InheritanceSourceOrganization = "organization"
+)
+
+// Shared responsibility values.
+const (
+	ResponsibilityCustomer = "customer"
+	ResponsibilityProvider = "provider"
+	ResponsibilityShared   = "shared"
+)
+
+// ValidInheritanceSources lists valid inheritance source types.
+var ValidInheritanceSources = []string{InheritanceSourceProvider, InheritanceSourceOrganization}
+
+// ValidResponsibilities lists valid shared responsibility values.
+var ValidResponsibilities = []string{ResponsibilityCustomer, ResponsibilityProvider, ResponsibilityShared}
+
+// ControlInheritance marks a control as inherited from a provider or another org.
+type ControlInheritance struct {
+	ID                     string    `json:"id"`
+	OrgID                  string    `json:"org_id"`
+	ControlID              string    `json:"control_id"`
+	SourceType             string    `json:"source_type"`
+	ProviderName           *string   `json:"provider_name"`
+	ProviderReference      *string   `json:"provider_reference"`
+	SourceOrgID            *string   `json:"source_org_id"`
+	SourceControlID        *string   `json:"source_control_id"`
+	Responsibility         string    `json:"responsibility"`
+	CustomerResponsibility *string   `json:"customer_responsibility"`
+	CreatedBy              *string   `json:"created_by"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
+
+// OrgInheritanceGrant allows a consumer org to inherit the granting org's controls.
+type OrgInheritanceGrant struct {
+	ID            string     `json:"id"`
+	OrgID         string     `json:"org_id"`
+	ConsumerOrgID string     `json:"consumer_org_id"`
+	Notes         *string    `json:"notes"`
+	GrantedBy     *string    `json:"granted_by"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// IsValidInheritanceSource checks if an inheritance source type is valid.
+func IsValidInheritanceSource(s string) bool {
+	for _, v := range ValidInheritanceSources {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidResponsibility checks if a responsibility value is valid.
+func IsValidResponsibility(r string) bool {
+	for _, v := range ValidResponsibilities {
+		if v == r {
+			return true
+		}
+	}
+	return false
+}
+
+// SetControlInheritanceRequest is the request for marking a control as inherited.
+type SetControlInheritanceRequest struct {
+	SourceType             string  `json:"source_type" binding:"required"`
+	ProviderName           *string `json:"provider_name"`
+	ProviderReference      *string `json:"provider_reference"`
+	SourceOrgID            *string `json:"source_org_id"`
+	SourceControlID        *string `json:"source_control_id"`
+	Responsibility         *string `json:"responsibility"`
+	CustomerResponsibility *string `json:"customer_responsibility"`
+}
+
+// CreateInheritanceGrantRequest is the request for granting another org inheritance.
+type CreateInheritanceGrantRequest struct {
+	ConsumerOrgID string  `json:"consumer_org_id" binding:"required"`
+	Notes         *string `json:"notes"`
+}
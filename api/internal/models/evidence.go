@@ -6,7 +6,7 @@ import "time"
 var ValidEvidenceTypes = []string{
 	"screenshot", "api_response", "configuration_export", "log_sample",
 	"policy_document", "access_list", "vulnerability_report", "certificate",
-	"training_record", "penetration_test", "audit_report", "other",
+	"training_record", "penetration_test", "audit_report", "attestation", "other",
 }
 
 // Evidence statuses.
@@ -25,6 +25,44 @@ var ValidLinkStrengths = []string{"primary", "supporting", "supplementary"}
 // Link target types.
 var ValidLinkTargetTypes = []string{"control", "requirement"}
 
+// Evidence processing statuses. Stored objects are sniffed and malware-scanned after upload;
+// downloads are only issued once processing has cleared them. not_scanned means the content
+// check passed but no scanner is configured, so the object was never malware-scanned; it is
+// scanned once a scanner is configured.
+const (
+	ProcessingPending     = "pending"
+	ProcessingScanning    = "scanning"
+	ProcessingPassed      = "passed"
+	ProcessingNotScanned  = "not_scanned"
+	ProcessingQuarantined = "quarantined"
+	ProcessingFailed      = "failed"
+)
+
+// AllowUnscannedEvidence lets not_scanned objects be served. It is set at startup from
+// RP_EVIDENCE_ALLOW_UNSCANNED and is off by default.
+var AllowUnscannedEvidence bool
+
+// IsProcessingCleared reports whether an object with this processing status may be served.
+func IsProcessingCleared(status string) bool {
+	return status == ProcessingPassed || (status == ProcessingNotScanned && AllowUnscannedEvidence)
+}
+
+// MaxProcessingAttempts is how often a scanner error is retried before processing is marked failed.
+const MaxProcessingAttempts = 5
+
+// Evidence text extraction statuses.
+const (
+	ExtractionExtracted   = "extracted"
+	ExtractionUnsupported = "unsupported"
+	ExtractionFailed      = "failed"
+)
+
+// MaxExtractionInputSize is the largest file text extraction will read (25MB).
+const MaxExtractionInputSize = 26214400
+
+// MaxExtractedTextSize caps stored extracted text (512KB) to stay within tsvector limits.
+const MaxExtractedTextSize = 524288
+
 // Allowed MIME types for evidence uploads.
 var AllowedMIMETypes = []string{
 	"application/pdf", "image/png", "image/jpeg", "image/gif",
@@ -57,6 +95,24 @@ var ValidEvidenceStatusTransitions = map[string][]string{
 	"expired":        {"pending_review"},
 }
 
+// FreshnessWarningDays is how far ahead of expiry evidence is reported as expiring soon.
+const FreshnessWarningDays = 30
+
+// EvidenceFreshnessStatus classifies an expiry time as fresh, expiring_soon or expired.
+// Evidence without an expiry is always fresh.
+func EvidenceFreshnessStatus(expiresAt *time.Time, now time.Time) string {
+	if expiresAt == nil {
+		return "fresh"
+	}
+	if expiresAt.Before(now) {
+		return "expired"
+	}
+	if expiresAt.Before(now.Add(FreshnessWarningDays * 24 * time.Hour)) {
+		return "expiring_soon"
+	}
+	return "fresh"
+}
+
 // IsValidEvidenceType checks if the evidence type is valid.
 func IsValidEvidenceType(t string) bool {
 	for _, v := range ValidEvidenceTypes {
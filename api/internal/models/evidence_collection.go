@@ -0,0 +1,133 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Evidence collection source types.
+const (
+	CollectionSourceHTTPJSON    = "http_json"
+	CollectionSourceS3Listing   = "s3_listing"
+	CollectionSourceAgentUpload = "agent_upload"
+)
+
+// ValidCollectionSources lists valid evidence collection source types.
+var ValidCollectionSources = []string{CollectionSourceHTTPJSON, CollectionSourceS3Listing, CollectionSourceAgentUpload}
+
+// Evidence collection run statuses.
+const (
+	CollectionRunSuccess = "success"
+	CollectionRunFailed  = "failed"
+	CollectionRunMissed  = "missed"
+)
+
+// Evidence collection run triggers.
+const (
+	CollectionTriggerScheduled = "scheduled"
+	CollectionTriggerManual    = "manual"
+	CollectionTriggerAgent     = "agent"
+)
+
+// MinCollectionIntervalMin is the shortest allowed collection schedule.
+const MinCollectionIntervalMin = 15
+
+// MaxCollectedContentSize caps the size of a single collected payload (10MB).
+const MaxCollectedContentSize = 10485760
+
+// IsValidCollectionSource checks if a collection source type is valid.
+func IsValidCollectionSource(s string) bool {
+	for _, v := range ValidCollectionSources {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectionMethodForSource maps a job source to the evidence collection method it produces.
+func CollectionMethodForSource(source string) string {
+	if source == CollectionSourceAgentUpload {
+		return "api_ingestion"
+	}
+	return "automated_pull"
+}
+
+// EvidenceCollectionJob is a scheduled job that pulls evidence and versions an artifact.
+type EvidenceCollectionJob struct {
+	ID                  string     `json:"id"`
+	OrgID               string     `json:"org_id"`
+	Name                string     `json:"name"`
+	Description         *string    `json:"description"`
+	IsActive            bool       `json:"is_active"`
+	SourceType          string     `json:"source_type"`
+	SourceConfig        string     `json:"-"`
+	ArtifactTitle       string     `json:"artifact_title"`
+	EvidenceType        string     `json:"evidence_type"`
+	FreshnessPeriodDays *int       `json:"freshness_period_days"`
+	Tags                []string   `json:"tags"`
+	ControlIDs          []string   `json:"control_ids"`
+	CurrentArtifactID   *string    `json:"current_artifact_id"`
+	ScheduleIntervalMin int        `json:"schedule_interval_min"`
+	NextRunAt           *time.Time `json:"next_run_at"`
+	LastRunAt           *time.Time `json:"last_run_at"`
+	LastRunStatus       *string    `json:"last_run_status"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CreatedBy           *string    `json:"created_by"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// EvidenceCollectionRun records one execution of a collection job.
+type EvidenceCollectionRun struct {
+	ID              string     `json:"id"`
+	JobID           string     `json:"job_id"`
+	Status          string     `json:"status"`
+	TriggerType     string     `json:"trigger_type"`
+	ArtifactID      *string    `json:"artifact_id"`
+	ArtifactVersion *int       `json:"artifact_version"`
+	FileSize        *int64     `json:"file_size"`
+	ChecksumSHA256  *string    `json:"checksum_sha256"`
+	ErrorMessage    *string    `json:"error_message"`
+	TriggeredBy     *string    `json:"triggered_by"`
+	StartedAt       time.Time  `json:"started_at"`
+	CompletedAt     *time.Time `json:"completed_at"`
+}
+
+// CreateEvidenceCollectionJobRequest is the request for creating a collection job.
+type CreateEvidenceCollectionJobRequest struct {
+	Name                string          `json:"name" binding:"required"`
+	Description         *string         `json:"description"`
+	SourceType          string          `json:"source_type" binding:"required"`
+	SourceConfig        json.RawMessage `json:"source_config"`
+	ArtifactTitle       *string         `json:"artifact_title"`
+	EvidenceType        *string         `json:"evidence_type"`
+	FreshnessPeriodDays *int            `json:"freshness_period_days"`
+	Tags                []string        `json:"tags"`
+	ControlIDs          []string        `json:"control_ids"`
+	ScheduleIntervalMin int             `json:"schedule_interval_min" binding:"required"`
+	IsActive            *bool           `json:"is_active"`
+}
+
+// UpdateEvidenceCollectionJobRequest is the request for updating a collection job.
+type UpdateEvidenceCollectionJobRequest struct {
+	Name                *string         `json:"name"`
+	Description         *string         `json:"description"`
+	SourceConfig        json.RawMessage `json:"source_config"`
+	ArtifactTitle       *string         `json:"artifact_title"`
+	EvidenceType        *string         `json:"evidence_type"`
+	FreshnessPeriodDays *int            `json:"freshness_period_days"`
+	Tags                []string        `json:"tags"`
+	ControlIDs          []string        `json:"control_ids"`
+	ScheduleIntervalMin *int            `json:"schedule_interval_min"`
+	IsActive            *bool           `json:"is_active"`
+}
+
+// AgentOutputRequest is command output uploaded by a collection agent.
+type AgentOutputRequest struct {
+	Command     *string `json:"command"`
+	ExitCode    *int    `json:"exit_code"`
+	Output      string  `json:"output" binding:"required"`
+	Host        *string `json:"host"`
+	CollectedAt *string `json:"collected_at"`
+}
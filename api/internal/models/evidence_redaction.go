@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// RedactableMIMETypes are the screenshot formats that can be redacted and annotated.
+var RedactableMIMETypes = []string{"image/png", "image/jpeg"}
+
+// MaxRedactionRegions caps the rectangles in one redaction request.
+const MaxRedactionRegions = 200
+
+// EvidenceRestrictedViewRoles can download access-restricted (unredacted) originals.
+var EvidenceRestrictedViewRoles = []string{RoleCISO, RoleComplianceManager}
+
+// RedactionRegion is a rectangle, in source pixels, painted over before storing.
+type RedactionRegion struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// RedactionOverlay selects the caption stamped below the screenshot.
+type RedactionOverlay struct {
+	Timestamp bool    `json:"timestamp"`
+	Collector bool    `json:"collector"`
+	URL       *string `json:"url"`
+}
+
+// CreateEvidenceRedactionRequest redacts a screenshot into a new version of the artifact.
+type CreateEvidenceRedactionRequest struct {
+	Regions []RedactionRegion `json:"regions"`
+	Overlay RedactionOverlay  `json:"overlay"`
+	// CapturedAt is stamped as the timestamp; defaults to when the original was uploaded.
+	CapturedAt *time.Time `json:"captured_at"`
+	Reason     *string    `json:"reason"`
+}
+
+// EvidenceRedaction records one redaction and the version it produced.
+type EvidenceRedaction struct {
+	ID               string            `json:"id"`
+	SourceArtifactID string            `json:"source_artifact_id"`
+	ResultArtifactID *string           `json:"result_artifact_id"`
+	Regions          []RedactionRegion `json:"regions"`
+	OverlayLines     []string          `json:"overlay_lines"`
+	Reason           *string           `json:"reason"`
+	CreatedBy        *string           `json:"created_by"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
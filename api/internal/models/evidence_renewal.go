@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Evidence renewal task statuses.
+const (
+	RenewalOpen      = "open"
+	RenewalCompleted = "completed"
+	RenewalCancelled = "cancelled"
+)
+
+// ValidRenewalStatuses lists valid evidence renewal task statuses.
+var ValidRenewalStatuses = []string{RenewalOpen, RenewalCompleted, RenewalCancelled}
+
+// EvidenceRenewalTask asks an owner to collect a fresh version of expiring evidence.
+type EvidenceRenewalTask struct {
+	ID                string     `json:"id"`
+	ArtifactID        string     `json:"artifact_id"`
+	ArtifactTitle     string     `json:"artifact_title"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+	ControlID         *string    `json:"control_id"`
+	Status            string     `json:"status"`
+	AssignedTo        *string    `json:"assigned_to"`
+	DueAt             time.Time  `json:"due_at"`
+	RenewedArtifactID *string    `json:"renewed_artifact_id"`
+	CompletedAt       *time.Time `json:"completed_at"`
+	Notes             *string    `json:"notes"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// UpdateEvidenceRenewalTaskRequest reassigns, annotates or cancels a renewal task.
+// Tasks complete automatically when a new version is uploaded.
+type UpdateEvidenceRenewalTaskRequest struct {
+	AssignedTo *string `json:"assigned_to"`
+	Status     *string `json:"status"`
+	Notes      *string `json:"notes"`
+}
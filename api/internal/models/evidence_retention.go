@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Evidence retention triggers: the event a retention period counts from.
+const (
+	RetentionTriggerCreated    = "created"
+	RetentionTriggerCollected  = "collected"
+	RetentionTriggerSuperseded = "superseded"
+	RetentionTriggerExpired    = "expired"
+)
+
+// ValidRetentionTriggers lists valid evidence retention triggers.
+var ValidRetentionTriggers = []string{
+	RetentionTriggerCreated, RetentionTriggerCollected, RetentionTriggerSuperseded, RetentionTriggerExpired,
+}
+
+// MaxRetentionDays caps retention periods at 100 years.
+const MaxRetentionDays = 36500
+
+// IsValidRetentionTrigger checks if a retention trigger is valid.
+func IsValidRetentionTrigger(s string) bool {
+	for _, v := range ValidRetentionTriggers {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// EvidenceRetentionRule is an org-level minimum retention period for evidence.
+type EvidenceRetentionRule struct {
+	ID               string    `json:"id"`
+	OrgID            string    `json:"org_id"`
+	Name             string    `json:"name"`
+	Description      *string   `json:"description"`
+	IsActive         bool      `json:"is_active"`
+	EvidenceType     *string   `json:"evidence_type"`
+	FrameworkID      *string   `json:"framework_id"`
+	RetentionTrigger string    `json:"retention_trigger"`
+	RetentionDays    int       `json:"retention_days"`
+	CreatedBy        *string   `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CreateEvidenceRetentionRuleRequest is the request for creating a retention rule.
+type CreateEvidenceRetentionRuleRequest struct {
+	Name             string  `json:"name" binding:"required"`
+	Description      *string `json:"description"`
+	EvidenceType     *string `json:"evidence_type"`
+	FrameworkID      *string `json:"framework_id"`
+	RetentionTrigger *string `json:"retention_trigger"`
+	RetentionDays    int     `json:"retention_days" binding:"required"`
+	IsActive         *bool   `json:"is_active"`
+}
+
+// UpdateEvidenceRetentionRuleRequest is the request for updating a retention rule.
+// An empty string clears evidence_type or framework_id.
+type UpdateEvidenceRetentionRuleRequest struct {
+	Name             *string `json:"name"`
+	Description      *string `json:"description"`
+	EvidenceType     *string `json:"evidence_type"`
+	FrameworkID      *string `json:"framework_id"`
+	RetentionTrigger *string `json:"retention_trigger"`
+	RetentionDays    *int    `json:"retention_days"`
+	IsActive         *bool   `json:"is_active"`
+}
+
+// LegalHoldRequest places or releases a legal hold on an evidence version chain.
+type LegalHoldRequest struct {
+	LegalHold bool    `json:"legal_hold"`
+	Reason    *string `json:"reason"`
+}
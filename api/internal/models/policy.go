@@ -254,6 +254,8 @@ type CloneTemplateRequest struct {
 	OwnerID        *string  `json:"owner_id"`
 	ReviewFreqDays *int     `json:"review_frequency_days"`
 	Tags           []string `json:"tags"`
+	// Variables supplies template variable values by key, overriding org settings.
+	Variables map[string]string `json:"variables"`
 }
 
 // RemindSignoffRequest is the request for sending reminders.
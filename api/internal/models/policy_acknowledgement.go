@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// Policy acknowledgement campaign statuses.
+const (
+	PolicyAckCampaignActive     = "active"
+	PolicyAckCampaignCompleted  = "completed"
+	PolicyAckCampaignSuperseded = "superseded"
+	PolicyAckCampaignCancelled  = "cancelled"
+)
+
+// Policy acknowledgement statuses.
+const (
+	PolicyAckStatusPending      = "pending"
+	PolicyAckStatusAcknowledged = "acknowledged"
+	PolicyAckStatusSuperseded   = "superseded"
+	PolicyAckStatusCancelled    = "cancelled"
+)
+
+// PolicyAckStatement is the statement recorded when a user acknowledges a policy version.
+const PolicyAckStatement = "I have read and understood this policy"
+
+// PolicyAckDefaultDueDays is the acknowledgement window when no due date is given.
+const PolicyAckDefaultDueDays = 30
+
+// PolicyAckEvidenceFreshnessDays is how long a generated acknowledgement roster stays fresh.
+const PolicyAckEvidenceFreshnessDays = 365
+
+// PolicyAckCampaignRoles can create, cancel and send reminders for acknowledgement campaigns.
+var PolicyAckCampaignRoles = []string{RoleCISO, RoleComplianceManager}
+
+// PolicyAckViewRoles can view campaign progress and other users' acknowledgement history.
+var PolicyAckViewRoles = []string{RoleCISO, RoleComplianceManager, RoleAuditor}
+
+// PolicyAckCampaign represents a request for the workforce to acknowledge a published policy version.
+type PolicyAckCampaign struct {
+	ID                   string     `json:"id"`
+	OrgID                string     `json:"org_id"`
+	PolicyID             string     `json:"policy_id"`
+	PolicyVersionID      string     `json:"policy_version_id"`
+	VersionNumber        int        `json:"version_number"`
+	AllUsers             bool       `json:"all_users"`
+	AudienceRoles        []string   `json:"audience_roles"`
+	AudienceUserIDs      []string   `json:"audience_user_ids"`
+	Status               string     `json:"status"`
+	DueDate              *time.Time `json:"due_date"`
+	ReminderIntervalDays *int       `json:"reminder_interval_days"`
+	EvidenceArtifactID   *string    `json:"evidence_artifact_id"`
+	CreatedBy            *string    `json:"created_by"`
+	CompletedAt          *time.Time `json:"completed_at"`
+	CancelledAt          *time.Time `json:"cancelled_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// PolicyAcknowledgement represents one user's acknowledgement of a policy version.
+type PolicyAcknowledgement struct {
+	ID              string     `json:"id"`
+	OrgID           string     `json:"org_id"`
+	CampaignID      string     `json:"campaign_id"`
+	PolicyID        string     `json:"policy_id"`
+	PolicyVersionID string     `json:"policy_version_id"`
+	UserID          string     `json:"user_id"`
+	Status          string     `json:"status"`
+	Statement       *string    `json:"statement"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at"`
+	IPAddress       *string    `json:"ip_address"`
+	UserAgent       *string    `json:"user_agent"`
+	ReminderSentAt  *time.Time `json:"reminder_sent_at"`
+	ReminderCount   int        `json:"reminder_count"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// PolicyAckAudienceRequest selects who must acknowledge a policy version.
+// At least one of AllUsers, Roles or UserIDs is required.
+type PolicyAckAudienceRequest struct {
+	AllUsers             bool     `json:"all_users"`
+	Roles                []string `json:"roles"`
+	UserIDs              []string `json:"user_ids"`
+	DueDate              *string  `json:"due_date"`
+	DueInDays            *int     `json:"due_in_days"`
+	ReminderIntervalDays *int     `json:"reminder_interval_days"`
+}
+
+// PublishPolicyRequest is the optional request body for publishing a policy.
+// Without Acknowledgement, the audience of the policy's previous campaign is carried forward;
+// SkipAcknowledgement publishes without starting a campaign.
+type PublishPolicyRequest struct {
+	Acknowledgement     *PolicyAckAudienceRequest `json:"acknowledgement"`
+	SkipAcknowledgement bool                      `json:"skip_acknowledgement"`
+}
+
+// RemindPolicyAcknowledgementsRequest is the request for sending acknowledgement reminders.
+type RemindPolicyAcknowledgementsRequest struct {
+	AcknowledgementIDs []string `json:"acknowledgement_ids"`
+}
@@ -0,0 +1,71 @@
+package models
+
+// Policy diff modes: structured content is aligned section by section, plain text word by word.
+const (
+	PolicyDiffModeSections = "sections"
+	PolicyDiffModeWords    = "words"
+)
+
+// Diff statuses for sections, paragraphs and word runs.
+const (
+	DiffUnchanged = "unchanged"
+	DiffAdded     = "added"
+	DiffRemoved   = "removed"
+	DiffModified  = "modified"
+)
+
+// PolicyDiff is the server-side comparison of two policy versions.
+type PolicyDiff struct {
+	FromVersion int                 `json:"from_version"`
+	ToVersion   int                 `json:"to_version"`
+	Mode        string              `json:"mode"`
+	Stats       PolicyDiffStats     `json:"stats"`
+	Inline      []PolicyDiffSection `json:"inline"`
+	SideBySide  []PolicyDiffRow     `json:"side_by_side"`
+	Truncated   bool                `json:"truncated"`
+}
+
+// PolicyDiffStats counts changes between two versions.
+type PolicyDiffStats struct {
+	ParagraphsAdded     int `json:"paragraphs_added"`
+	ParagraphsRemoved   int `json:"paragraphs_removed"`
+	ParagraphsModified  int `json:"paragraphs_modified"`
+	ParagraphsUnchanged int `json:"paragraphs_unchanged"`
+	SectionsAdded       int `json:"sections_added"`
+	SectionsRemoved     int `json:"sections_removed"`
+	SectionsModified    int `json:"sections_modified"`
+	WordsAdded          int `json:"words_added"`
+	WordsRemoved        int `json:"words_removed"`
+}
+
+// PolicyDiffSection groups paragraph changes under a heading. Title is empty for content before
+// the first heading and for plain text.
+type PolicyDiffSection struct {
+	Title    string                `json:"title"`
+	OldTitle *string               `json:"old_title,omitempty"`
+	Status   string                `json:"status"`
+	Blocks   []PolicyDiffParagraph `json:"blocks"`
+}
+
+// PolicyDiffParagraph is one paragraph in the inline view. Words holds the word-level runs for
+// modified paragraphs.
+type PolicyDiffParagraph struct {
+	Status string           `json:"status"`
+	Old    *string          `json:"old,omitempty"`
+	New    *string          `json:"new,omitempty"`
+	Words  []PolicyDiffWord `json:"words,omitempty"`
+}
+
+// PolicyDiffWord is a run of words with the same status.
+type PolicyDiffWord struct {
+	Status string `json:"status"`
+	Text   string `json:"text"`
+}
+
+// PolicyDiffRow is one line of the side-by-side view; Left is the older version.
+type PolicyDiffRow struct {
+	Section string           `json:"section"`
+	Status  string           `json:"status"`
+	Left    []PolicyDiffWord `json:"left"`
+	Right   []PolicyDiffWord `json:"right"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Policy document formats.
+const (
+	PolicyDocumentPDF  = "pdf"
+	PolicyDocumentHTML = "html"
+)
+
+// ValidPolicyDocumentFormats lists the formats a policy version can be rendered to.
+var ValidPolicyDocumentFormats = []string{PolicyDocumentPDF, PolicyDocumentHTML}
+
+// IsValidPolicyDocumentFormat checks if a document format is valid.
+func IsValidPolicyDocumentFormat(f string) bool {
+	for _, v := range ValidPolicyDocumentFormats {
+		if v == f {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyDocumentFreshnessDays is the evidence freshness period for rendered policies without a
+// review frequency.
+const PolicyDocumentFreshnessDays = 365
+
+// PolicyDocument is a stored rendition of a policy version.
+type PolicyDocument struct {
+	ID                 string    `json:"id"`
+	PolicyVersionID    string    `json:"policy_version_id"`
+	VersionNumber      int       `json:"version_number"`
+	Format             string    `json:"format"`
+	EvidenceArtifactID string    `json:"evidence_artifact_id"`
+	FileName           string    `json:"file_name"`
+	FileSize           int64     `json:"file_size"`
+	EvidenceVersion    int       `json:"evidence_version"`
+	IsCurrent          bool      `json:"is_current"`
+	RenderedBy         *string   `json:"rendered_by"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// RenderPolicyDocumentsRequest renders a policy version and stores it as evidence.
+// VersionNumber defaults to the current version and Formats to both PDF and HTML.
+type RenderPolicyDocumentsRequest struct {
+	VersionNumber *int     `json:"version_number"`
+	Formats       []string `json:"formats"`
+}
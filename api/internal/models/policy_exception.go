@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// Policy exception statuses.
+const (
+	PolicyExceptionRequested = "requested"
+	PolicyExceptionApproved  = "approved"
+	PolicyExceptionRejected  = "rejected"
+	PolicyExceptionExpired   = "expired"
+	PolicyExceptionRevoked   = "revoked"
+)
+
+// ValidPolicyExceptionStatuses lists valid policy exception statuses.
+var ValidPolicyExceptionStatuses = []string{
+	PolicyExceptionRequested, PolicyExceptionApproved, PolicyExceptionRejected,
+	PolicyExceptionExpired, PolicyExceptionRevoked,
+}
+
+// IsValidPolicyExceptionStatus checks if a policy exception status is valid.
+func IsValidPolicyExceptionStatus(s string) bool {
+	for _, v := range ValidPolicyExceptionStatuses {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyExceptionMaxDays is the longest an exception may run before it must be requested again.
+const PolicyExceptionMaxDays = 365
+
+// PolicyExceptionExpiryLeadDays is how long before expiry the requester is alerted.
+const PolicyExceptionExpiryLeadDays = 14
+
+// PolicyExceptionControl is a compensating control of an exception.
+type PolicyExceptionControl struct {
+	ControlID  string `json:"control_id"`
+	Identifier string `json:"identifier"`
+	Title      string `json:"title"`
+}
+
+// PolicyException is an approved (or requested) deviation from a policy.
+type PolicyException struct {
+	ID                   string                   `json:"id"`
+	PolicyID             string                   `json:"policy_id"`
+	PolicyIdentifier     string                   `json:"policy_identifier"`
+	PolicyTitle          string                   `json:"policy_title"`
+	Title                string                   `json:"title"`
+	Justification        string                   `json:"justification"`
+	AffectedAssets       []string                 `json:"affected_assets"`
+	AffectedUserIDs      []string                 `json:"affected_user_ids"`
+	Status               string                   `json:"status"`
+	RequestedBy          string                   `json:"requested_by"`
+	ApproverID           *string                  `json:"approver_id"`
+	ExpiresAt            time.Time                `json:"expires_at"`
+	DecidedBy            *string                  `json:"decided_by"`
+	DecidedAt            *time.Time               `json:"decided_at"`
+	DecisionComments     *string                  `json:"decision_comments"`
+	RevokedBy            *string                  `json:"revoked_by"`
+	RevokedAt            *time.Time               `json:"revoked_at"`
+	RevocationReason     *string                  `json:"revocation_reason"`
+	ExpiredAt            *time.Time               `json:"expired_at"`
+	CompensatingControls []PolicyExceptionControl `json:"compensating_controls"`
+	CreatedAt            time.Time                `json:"created_at"`
+	UpdatedAt            time.Time                `json:"updated_at"`
+}
+
+// CreatePolicyExceptionRequest requests an exception to a published policy.
+type CreatePolicyExceptionRequest struct {
+	Title                string   `json:"title" binding:"required"`
+	Justification        string   `json:"justification" binding:"required"`
+	AffectedAssets       []string `json:"affected_assets"`
+	AffectedUserIDs      []string `json:"affected_user_ids"`
+	CompensatingControls []string `json:"compensating_control_ids"`
+	ApproverID           *string  `json:"approver_id"`
+	ExpiresAt            string   `json:"expires_at" binding:"required"`
+}
+
+// UpdatePolicyExceptionRequest changes an exception that has not been decided yet.
+type UpdatePolicyExceptionRequest struct {
+	Title                *string  `json:"title"`
+	Justification        *string  `json:"justification"`
+	AffectedAssets       []string `json:"affected_assets"`
+	AffectedUserIDs      []string `json:"affected_user_ids"`
+	CompensatingControls []string `json:"compensating_control_ids"`
+	ApproverID           *string  `json:"approver_id"`
+	ExpiresAt            *string  `json:"expires_at"`
+}
+
+// PolicyExceptionDecisionRequest approves, rejects or revokes an exception.
+type PolicyExceptionDecisionRequest struct {
+	Comments *string `json:"comments"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Policy review statuses.
+const (
+	PolicyReviewOpen       = "open"
+	PolicyReviewInApproval = "in_approval"
+	PolicyReviewRevising   = "revising"
+	PolicyReviewCompleted  = "completed"
+	PolicyReviewCancelled  = "cancelled"
+)
+
+// ValidPolicyReviewStatuses lists valid policy review statuses.
+var ValidPolicyReviewStatuses = []string{
+	PolicyReviewOpen, PolicyReviewInApproval, PolicyReviewRevising, PolicyReviewCompleted, PolicyReviewCancelled,
+}
+
+// Policy review outcomes chosen by the owner.
+const (
+	PolicyReviewOutcomeNoChanges = "no_changes"
+	PolicyReviewOutcomeRevised   = "revised"
+)
+
+// PolicyReviewLeadDays is how long before next_review_at a review task is opened.
+const PolicyReviewLeadDays = 30
+
+// PolicyReviewEscalationDays is how long a review may stay overdue before it is escalated to the CISO.
+const PolicyReviewEscalationDays = 14
+
+// PolicyReview is a scheduled review of a published policy.
+type PolicyReview struct {
+	ID               string     `json:"id"`
+	PolicyID         string     `json:"policy_id"`
+	PolicyIdentifier string     `json:"policy_identifier"`
+	PolicyTitle      string     `json:"policy_title"`
+	Status           string     `json:"status"`
+	Outcome          *string    `json:"outcome"`
+	AssignedTo       *string    `json:"assigned_to"`
+	DueAt            time.Time  `json:"due_at"`
+	ReviewVersionID  *string    `json:"review_version_id"`
+	StartedBy        *string    `json:"started_by"`
+	StartedAt        *time.Time `json:"started_at"`
+	Notes            *string    `json:"notes"`
+	EscalationLevel  int        `json:"escalation_level"`
+	EscalatedAt      *time.Time `json:"escalated_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// ConfirmPolicyReviewRequest confirms a policy needs no changes. Without SignerIDs the approvers
+// of the current version are asked to sign off again.
+type ConfirmPolicyReviewRequest struct {
+	SignerIDs []string `json:"signer_ids"`
+	DueDate   *string  `json:"due_date"`
+	Notes     *string  `json:"notes"`
+}
+
+// RevisePolicyReviewRequest starts a new draft version from a review. Without Content the
+// current content is copied into the draft.
+type RevisePolicyReviewRequest struct {
+	Content       *string `json:"content"`
+	ContentFormat *string `json:"content_format"`
+	ChangeSummary *string `json:"change_summary"`
+	ChangeType    *string `json:"change_type"`
+	Notes         *string `json:"notes"`
+}
+
+// UpdatePolicyReviewRequest reassigns, annotates or cancels an open review.
+type UpdatePolicyReviewRequest struct {
+	AssignedTo *string `json:"assigned_to"`
+	Status     *string `json:"status"`
+	Notes      *string `json:"notes"`
+}
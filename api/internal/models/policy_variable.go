@@ -0,0 +1,91 @@
+package models
+
+// Policy template variable types.
+const (
+	PolicyVariableText     = "text"
+	PolicyVariableUser     = "user"
+	PolicyVariableDuration = "duration"
+	PolicyVariableCadence  = "cadence"
+	PolicyVariableNumber   = "number"
+)
+
+// ValidPolicyVariableTypes lists valid template variable types.
+var ValidPolicyVariableTypes = []string{
+	PolicyVariableText, PolicyVariableUser, PolicyVariableDuration, PolicyVariableCadence, PolicyVariableNumber,
+}
+
+// IsValidPolicyVariableType checks if a template variable type is valid.
+func IsValidPolicyVariableType(t string) bool {
+	for _, v := range ValidPolicyVariableTypes {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyCadences are the values a cadence variable accepts.
+var PolicyCadences = []string{"monthly", "quarterly", "semi-annually", "annually", "biennially"}
+
+// Where a policy's variable value came from. Org-sourced values are re-resolved when drafts are
+// regenerated; values supplied at clone time are kept.
+const (
+	PolicyVariableSourceClone      = "clone"
+	PolicyVariableSourceOrgSetting = "org_setting"
+	PolicyVariableSourceDefault    = "default"
+)
+
+// PolicyVariableSettingsKey is the organizations.settings key holding org-wide variable values,
+// e.g. {"policy_variables": {"security_officer": "<user id>", "retention_period": "7 years"}}.
+const PolicyVariableSettingsKey = "policy_variables"
+
+// PolicyTemplateVariable is a placeholder declared on a template, written {{key}} in its content.
+type PolicyTemplateVariable struct {
+	Key          string  `json:"key"`
+	Label        string  `json:"label"`
+	Type         string  `json:"type"`
+	Description  *string `json:"description"`
+	DefaultValue *string `json:"default_value"`
+	Required     bool    `json:"required"`
+}
+
+// StandardPolicyVariables are the variables most templates use. Declaring one of these keys
+// without a type or label takes them from here.
+var StandardPolicyVariables = []PolicyTemplateVariable{
+	{Key: "company_name", Label: "Company name", Type: PolicyVariableText, Required: true},
+	{Key: "security_officer", Label: "Security officer", Type: PolicyVariableUser, Required: true},
+	{Key: "retention_period", Label: "Retention period", Type: PolicyVariableDuration, Required: true},
+	{Key: "review_cadence", Label: "Review cadence", Type: PolicyVariableCadence, Required: true},
+}
+
+// StandardPolicyVariable returns the standard definition for key, if there is one.
+func StandardPolicyVariable(key string) (PolicyTemplateVariable, bool) {
+	for _, v := range StandardPolicyVariables {
+		if v.Key == key {
+			return v, true
+		}
+	}
+	return PolicyTemplateVariable{}, false
+}
+
+// PolicyVariableValue is a resolved variable of a policy cloned from a template.
+type PolicyVariableValue struct {
+	Key          string `json:"key"`
+	Value        string `json:"value"`
+	DisplayValue string `json:"display_value"`
+	Source       string `json:"source"`
+}
+
+// UpdateTemplateVariablesRequest replaces the variables declared on a template.
+type UpdateTemplateVariablesRequest struct {
+	Variables []PolicyTemplateVariable `json:"variables"`
+}
+
+// RegeneratePolicyDraftsRequest re-renders draft policies whose org-sourced variable values have
+// changed. Without PolicyIDs every affected draft is considered. Drafts edited since they were
+// last rendered are skipped unless Force is set.
+type RegeneratePolicyDraftsRequest struct {
+	PolicyIDs []string `json:"policy_ids"`
+	Force     bool     `json:"force"`
+	DryRun    bool     `json:"dry_run"`
+}
@@ -398,26 +398,29 @@ type Risk struct {
 
 // RiskAssessment represents a point-in-time risk assessment.
 type RiskAssessment struct {
-	ID              string     `json:"id"`
-	OrgID           string     `json:"org_id"`
-	RiskID          string     `json:"risk_id"`
-	AssessmentType  string     `json:"assessment_type"`
-	Likelihood      string     `json:"likelihood"`
-	Impact          string     `json:"impact"`
-	LikelihoodScore int        `json:"likelihood_score"`
-	ImpactScore     int        `json:"impact_score"`
-	OverallScore    float64    `json:"overall_score"`
-	ScoringFormula  string     `json:"scoring_formula"`
-	Severity        string     `json:"severity"`
-	Justification   *string    `json:"justification"`
-	Assumptions     *string    `json:"assumptions"`
-	DataSources     []string   `json:"data_sources"`
-	AssessedBy      string     `json:"assessed_by"`
-	AssessmentDate  time.Time  `json:"assessment_date"`
-	ValidUntil      *time.Time `json:"valid_until"`
-	IsCurrent       bool       `json:"is_current"`
-	SupersededBy    *string    `json:"superseded_by"`
-	CreatedAt       time.Time  `json:"created_at"`
+	ID                 string            `json:"id"`
+	OrgID              string            `json:"org_id"`
+	RiskID             string            `json:"risk_id"`
+	AssessmentType     string            `json:"assessment_type"`
+	Likelihood         string            `json:"likelihood"`
+	Impact             string            `json:"impact"`
+	LikelihoodScore    int               `json:"likelihood_score"`
+	ImpactScore        float64           `json:"impact_score"`
+	ImpactRatings      map[string]string `json:"impact_ratings"`
+	OverallScore       float64           `json:"overall_score"`
+	ScoringFormula     string            `json:"scoring_formula"`
+	MethodologyID      *string           `json:"methodology_id"`
+	MethodologyVersion int               `json:"methodology_version"`
+	Severity           string            `json:"severity"`
+	Justification      *string           `json:"justification"`
+	Assumptions        *string           `json:"assumptions"`
+	DataSources        []string          `json:"data_sources"`
+	AssessedBy         string            `json:"assessed_by"`
+	AssessmentDate     time.Time         `json:"assessment_date"`
+	ValidUntil         *time.Time        `json:"valid_until"`
+	IsCurrent          bool              `json:"is_current"`
+	SupersededBy       *string           `json:"superseded_by"`
+	CreatedAt          time.Time         `json:"created_at"`
 }
 
 // RiskTreatment represents a treatment plan for a risk.
@@ -517,14 +520,15 @@ type ChangeRiskStatusRequest struct {
 
 // CreateAssessmentRequest is the request for creating a risk assessment.
 type CreateAssessmentRequest struct {
-	AssessmentType string   `json:"assessment_type" binding:"required"`
-	Likelihood     string   `json:"likelihood" binding:"required"`
-	Impact         string   `json:"impact" binding:"required"`
-	ScoringFormula *string  `json:"scoring_formula"`
-	Justification  *string  `json:"justification"`
-	Assumptions    *string  `json:"assumptions"`
-	DataSources    []string `json:"data_sources"`
-	ValidUntil     *string  `json:"valid_until"`
+	AssessmentType string            `json:"assessment_type" binding:"required"`
+	Likelihood     string            `json:"likelihood" binding:"required"`
+	Impact         string            `json:"impact"`
+	ImpactRatings  map[string]string `json:"impact_ratings"`
+	ScoringFormula *string           `json:"scoring_formula"`
+	Justification  *string           `json:"justification"`
+	Assumptions    *string           `json:"assumptions"`
+	DataSources    []string          `json:"data_sources"`
+	ValidUntil     *string           `json:"valid_until"`
 }
 
 // CreateTreatmentRequest is the request for creating a treatment plan.
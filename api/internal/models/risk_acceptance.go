@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Risk acceptance statuses.
+const (
+	RiskAcceptanceRequested  = "requested"
+	RiskAcceptanceActive     = "active"
+	RiskAcceptanceRejected   = "rejected"
+	RiskAcceptanceSuperseded = "superseded"
+	RiskAcceptanceExpired    = "expired"
+	RiskAcceptanceRevoked    = "revoked"
+	RiskAcceptanceCancelled  = "cancelled"
+)
+
+// RiskAcceptanceExpiryLeadDays is how long before an acceptance expires the owner is alerted to
+// request a renewal.
+const RiskAcceptanceExpiryLeadDays = 30
+
+// RiskAcceptanceExpiryStatus is the status a risk returns to when its acceptance expires.
+const RiskAcceptanceExpiryStatus = RiskStatusOpen
+
+// RiskAcceptance is one acceptance of a risk, or a request to renew one.
+type RiskAcceptance struct {
+	ID               string     `json:"id"`
+	RiskID           string     `json:"risk_id"`
+	RenewalOf        *string    `json:"renewal_of"`
+	Status           string     `json:"status"`
+	Justification    string     `json:"justification"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RequestedBy      *string    `json:"requested_by"`
+	RequestedAt      time.Time  `json:"requested_at"`
+	DecidedBy        *string    `json:"decided_by"`
+	DecidedAt        *time.Time `json:"decided_at"`
+	DecisionComments *string    `json:"decision_comments"`
+	AppetitePosition *string    `json:"appetite_position"`
+	ResidualScore    *float64   `json:"residual_score"`
+	EndedBy          *string    `json:"ended_by"`
+	EndedAt          *time.Time `json:"ended_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// RenewRiskAcceptanceRequest asks for an accepted risk to stay accepted past its current expiry.
+type RenewRiskAcceptanceRequest struct {
+	Justification    string `json:"justification" binding:"required"`
+	AcceptanceExpiry string `json:"acceptance_expiry" binding:"required"`
+}
+
+// RiskAcceptanceDecisionRequest approves or rejects a renewal request.
+type RiskAcceptanceDecisionRequest struct {
+	Comments *string `json:"comments"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Appetite positions: where a risk's residual score sits against its effective appetite.
+const (
+	AppetitePositionWithin         = "within_appetite"
+	AppetitePositionAboveAppetite  = "above_appetite"
+	AppetitePositionAboveTolerance = "above_tolerance"
+	AppetitePositionNotAssessed    = "not_assessed"
+	AppetitePositionNoAppetite     = "no_appetite"
+)
+
+// Appetite sources: which threshold applies to a risk.
+const (
+	AppetiteSourceRisk         = "risk"
+	AppetiteSourceCategory     = "category"
+	AppetiteSourceOrganization = "organization"
+)
+
+// RiskAppetiteStatement is an organization-wide (nil Category) or per-category appetite.
+type RiskAppetiteStatement struct {
+	ID                 string    `json:"id"`
+	OrgID              string    `json:"org_id"`
+	Category           *string   `json:"category"`
+	AppetiteThreshold  float64   `json:"appetite_threshold"`
+	ToleranceThreshold float64   `json:"tolerance_threshold"`
+	Statement          *string   `json:"statement"`
+	UpdatedBy          *string   `json:"updated_by"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// RiskAppetiteManageRoles can set the organization's risk appetite.
+var RiskAppetiteManageRoles = []string{RoleCISO}
+
+// RiskAcceptAboveToleranceRoles can accept a risk whose residual score exceeds tolerance.
+var RiskAcceptAboveToleranceRoles = []string{RoleCISO}
+
+// SetRiskAppetiteRequest creates or replaces the appetite for the organization or a category.
+type SetRiskAppetiteRequest struct {
+	Category           *string  `json:"category"`
+	AppetiteThreshold  *float64 `json:"appetite_threshold" binding:"required"`
+	ToleranceThreshold *float64 `json:"tolerance_threshold" binding:"required"`
+	Statement          *string  `json:"statement"`
+}
@@ -0,0 +1,304 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+)
+
+// Scoring methodology limits.
+const (
+	MinMethodologyLevels     = 2
+	MaxMethodologyLevels     = 10
+	MaxMethodologyLevelScore = 10
+	MaxImpactDimensions      = 8
+)
+
+// Default impact dimension keys.
+const (
+	ImpactDimensionFinancial    = "financial"
+	ImpactDimensionOperational  = "operational"
+	ImpactDimensionReputational = "reputational"
+	ImpactDimensionRegulatory   = "regulatory"
+)
+
+// SeverityRanks orders severity bands from most to least severe.
+var SeverityRanks = []string{"critical", "high", "medium", "low"}
+
+var methodologyKeyRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// RiskScaleLevel is one step of a likelihood or impact scale.
+type RiskScaleLevel struct {
+	Key         string  `json:"key"`
+	Label       string  `json:"label"`
+	Score       int     `json:"score"`
+	Description *string `json:"description,omitempty"`
+}
+
+// RiskImpactDimension is one weighted facet of impact, rated on the impact scale.
+type RiskImpactDimension struct {
+	Key    string  `json:"key"`
+	Label  string  `json:"label"`
+	Weight float64 `json:"weight"`
+}
+
+// RiskSeverityBand maps scores of at least MinScore to a severity.
+type RiskSeverityBand struct {
+	Severity string  `json:"severity"`
+	Label    string  `json:"label"`
+	MinScore float64 `json:"min_score"`
+}
+
+// RiskMethodology is a versioned risk scoring methodology. Versions are immutable once saved;
+// each assessment records the version it was scored with.
+type RiskMethodology struct {
+	ID               *string               `json:"id"`
+	Version          int                   `json:"version"`
+	Name             string                `json:"name"`
+	Description      *string               `json:"description"`
+	LikelihoodLevels []RiskScaleLevel      `json:"likelihood_levels"`
+	ImpactLevels     []RiskScaleLevel      `json:"impact_levels"`
+	ImpactDimensions []RiskImpactDimension `json:"impact_dimensions"`
+	SeverityBands    []RiskSeverityBand    `json:"severity_bands"`
+	IsActive         bool                  `json:"is_active"`
+	IsDefault        bool                  `json:"is_default"`
+	CreatedBy        *string               `json:"created_by"`
+	CreatedAt        *time.Time            `json:"created_at"`
+	ActivatedAt      *time.Time            `json:"activated_at"`
+}
+
+// DefaultRiskMethodology is the built-in 5x5 likelihood x impact matrix used by organizations
+// that have not configured their own. It is version 0 and has no ID.
+func DefaultRiskMethodology() *RiskMethodology {
+	return &RiskMethodology{
+		Version: 0,
+		Name:    "Standard 5x5",
+		LikelihoodLevels: []RiskScaleLevel{
+			{Key: LikelihoodRare, Label: "Rare", Score: 1},
+			{Key: LikelihoodUnlikely, Label: "Unlikely", Score: 2},
+			{Key: LikelihoodPossible, Label: "Possible", Score: 3},
+			{Key: LikelihoodLikely, Label: "Likely", Score: 4},
+			{Key: LikelihoodAlmostCertain, Label: "Almost Certain", Score: 5},
+		},
+		ImpactLevels: []RiskScaleLevel{
+			{Key: ImpactNegligible, Label: "Negligible", Score: 1},
+			{Key: ImpactMinor, Label: "Minor", Score: 2},
+			{Key: ImpactModerate, Label: "Moderate", Score: 3},
+			{Key: ImpactMajor, Label: "Major", Score: 4},
+			{Key: ImpactSevere, Label: "Severe", Score: 5},
+		},
+		ImpactDimensions: []RiskImpactDimension{
+			{Key: ImpactDimensionFinancial, Label: "Financial", Weight: 1},
+			{Key: ImpactDimensionOperational, Label: "Operational", Weight: 1},
+			{Key: ImpactDimensionReputational, Label: "Reputational", Weight: 1},
+			{Key: ImpactDimensionRegulatory, Label: "Regulatory", Weight: 1},
+		},
+		SeverityBands: []RiskSeverityBand{
+			{Severity: "critical", Label: "Critical", MinScore: 20},
+			{Severity: "high", Label: "High", MinScore: 12},
+			{Severity: "medium", Label: "Medium", MinScore: 6},
+			{Severity: "low", Label: "Low", MinScore: 1},
+		},
+		IsActive:  true,
+		IsDefault: true,
+	}
+}
+
+func findScaleLevel(levels []RiskScaleLevel, key string) (RiskScaleLevel, bool) {
+	for _, l := range levels {
+		if l.Key == key {
+			return l, true
+		}
+	}
+	return RiskScaleLevel{}, false
+}
+
+// IsValidLikelihood checks a likelihood key against the methodology's scale.
+func (m *RiskMethodology) IsValidLikelihood(key string) bool {
+	_, ok := findScaleLevel(m.LikelihoodLevels, key)
+	return ok
+}
+
+// IsValidImpact checks an impact key against the methodology's scale.
+func (m *RiskMethodology) IsValidImpact(key string) bool {
+	_, ok := findScaleLevel(m.ImpactLevels, key)
+	return ok
+}
+
+// LikelihoodScore maps a likelihood key to its score, or 0 if the key is not on the scale.
+func (m *RiskMethodology) LikelihoodScore(key string) int {
+	l, _ := findScaleLevel(m.LikelihoodLevels, key)
+	return l.Score
+}
+
+// ImpactScore maps an impact key to its score, or 0 if the key is not on the scale.
+func (m *RiskMethodology) ImpactScore(key string) int {
+	l, _ := findScaleLevel(m.ImpactLevels, key)
+	return l.Score
+}
+
+// MaxScore is the highest overall score the methodology can produce.
+func (m *RiskMethodology) MaxScore() float64 {
+	if len(m.LikelihoodLevels) == 0 || len(m.ImpactLevels) == 0 {
+		return 0
+	}
+	return float64(m.LikelihoodLevels[len(m.LikelihoodLevels)-1].Score * m.ImpactLevels[len(m.ImpactLevels)-1].Score)
+}
+
+// Band returns the severity band for a score: the most severe band whose minimum the score
+// reaches, or the least severe band when it reaches none.
+func (m *RiskMethodology) Band(score float64) RiskSeverityBand {
+	if len(m.SeverityBands) == 0 {
+		return RiskSeverityBand{Severity: ScoreSeverity(score)}
+	}
+	for _, b := range m.SeverityBands {
+		if score >= b.MinScore {
+			return b
+		}
+	}
+	return m.SeverityBands[len(m.SeverityBands)-1]
+}
+
+// Severity returns the severity for a score.
+func (m *RiskMethodology) Severity(score float64) string {
+	return m.Band(score).Severity
+}
+
+// WeightedImpact combines per-dimension impact ratings into one impact score: the weighted mean
+// of the rated levels' scores, rounded to two decimals. Every dimension must be rated. The
+// returned key is the impact level nearest the weighted score, rounding up on a tie.
+func (m *RiskMethodology) WeightedImpact(ratings map[string]string) (float64, string, error) {
+	if len(m.ImpactDimensions) == 0 {
+		return 0, "", fmt.Errorf("methodology has no impact dimensions")
+	}
+	for key := range ratings {
+		found := false
+		for _, d := range m.ImpactDimensions {
+			if d.Key == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, "", fmt.Errorf("unknown impact dimension %q", key)
+		}
+	}
+	var sum, weights float64
+	for _, d := range m.ImpactDimensions {
+		level, ok := ratings[d.Key]
+		if !ok {
+			return 0, "", fmt.Errorf("impact dimension %q must be rated", d.Key)
+		}
+		score := m.ImpactScore(level)
+		if score == 0 {
+			return 0, "", fmt.Errorf("invalid impact level %q for dimension %q", level, d.Key)
+		}
+		sum += d.Weight * float64(score)
+		weights += d.Weight
+	}
+	weighted := math.Round(sum/weights*100) / 100
+
+	nearest := m.ImpactLevels[0]
+	for _, l := range m.ImpactLevels[1:] {
+		if math.Abs(float64(l.Score)-weighted) <= math.Abs(float64(nearest.Score)-weighted) {
+			nearest = l
+		}
+	}
+	return weighted, nearest.Key, nil
+}
+
+// Validate checks a methodology before it is saved.
+func (m *RiskMethodology) Validate() error {
+	if m.Name == "" || len(m.Name) > 255 {
+		return fmt.Errorf("name is required and must not exceed 255 characters")
+	}
+	if err := validateScale("likelihood_levels", m.LikelihoodLevels); err != nil {
+		return err
+	}
+	if err := validateScale("impact_levels", m.ImpactLevels); err != nil {
+		return err
+	}
+
+	if len(m.ImpactDimensions) == 1 || len(m.ImpactDimensions) > MaxImpactDimensions {
+		return fmt.Errorf("impact_dimensions must be empty or list between 2 and %d dimensions", MaxImpactDimensions)
+	}
+	seen := map[string]bool{}
+	for _, d := range m.ImpactDimensions {
+		if !methodologyKeyRegex.MatchString(d.Key) {
+			return fmt.Errorf("impact dimension key %q must be lowercase letters, digits and underscores", d.Key)
+		}
+		if seen[d.Key] {
+			return fmt.Errorf("duplicate impact dimension %q", d.Key)
+		}
+		seen[d.Key] = true
+		if d.Label == "" {
+			return fmt.Errorf("impact dimension %q needs a label", d.Key)
+		}
+		if d.Weight <= 0 || math.IsInf(d.Weight, 0) || math.IsNaN(d.Weight) {
+			return fmt.Errorf("impact dimension %q weight must be greater than zero", d.Key)
+		}
+	}
+
+	if len(m.SeverityBands) != len(SeverityRanks) {
+		return fmt.Errorf("severity_bands must define critical, high, medium and low")
+	}
+	for i, b := range m.SeverityBands {
+		if b.Severity != SeverityRanks[i] {
+			return fmt.Errorf("severity_bands must be ordered critical, high, medium, low")
+		}
+		if b.Label == "" {
+			return fmt.Errorf("severity band %q needs a label", b.Severity)
+		}
+		if i > 0 && b.MinScore >= m.SeverityBands[i-1].MinScore {
+			return fmt.Errorf("severity band min_score must decrease from critical to low")
+		}
+	}
+	if m.SeverityBands[0].MinScore > m.MaxScore() {
+		return fmt.Errorf("critical min_score exceeds the highest possible score (%g)", m.MaxScore())
+	}
+	if m.SeverityBands[len(m.SeverityBands)-1].MinScore < 0 {
+		return fmt.Errorf("low min_score must not be negative")
+	}
+	return nil
+}
+
+func validateScale(name string, levels []RiskScaleLevel) error {
+	if len(levels) < MinMethodologyLevels || len(levels) > MaxMethodologyLevels {
+		return fmt.Errorf("%s must list between %d and %d levels", name, MinMethodologyLevels, MaxMethodologyLevels)
+	}
+	seen := map[string]bool{}
+	for i, l := range levels {
+		if !methodologyKeyRegex.MatchString(l.Key) {
+			return fmt.Errorf("%s key %q must be lowercase letters, digits and underscores", name, l.Key)
+		}
+		if seen[l.Key] {
+			return fmt.Errorf("%s has duplicate key %q", name, l.Key)
+		}
+		seen[l.Key] = true
+		if l.Label == "" {
+			return fmt.Errorf("%s level %q needs a label", name, l.Key)
+		}
+		if l.Score < 1 || l.Score > MaxMethodologyLevelScore {
+			return fmt.Errorf("%s scores must be between 1 and %d", name, MaxMethodologyLevelScore)
+		}
+		if i > 0 && l.Score <= levels[i-1].Score {
+			return fmt.Errorf("%s must be ordered lowest to highest with increasing scores", name)
+		}
+	}
+	return nil
+}
+
+// RiskMethodologyManageRoles can create and activate scoring methodologies.
+var RiskMethodologyManageRoles = []string{RoleCISO, RoleComplianceManager}
+
+// CreateRiskMethodologyRequest saves a new methodology version.
+type CreateRiskMethodologyRequest struct {
+	Name             string                `json:"name" binding:"required"`
+	Description      *string               `json:"description"`
+	LikelihoodLevels []RiskScaleLevel      `json:"likelihood_levels" binding:"required"`
+	ImpactLevels     []RiskScaleLevel      `json:"impact_levels" binding:"required"`
+	ImpactDimensions []RiskImpactDimension `json:"impact_dimensions"`
+	SeverityBands    []RiskSeverityBand    `json:"severity_bands" binding:"required"`
+	Activate         bool                  `json:"activate"`
+}
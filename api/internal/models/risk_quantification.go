@@ -0,0 +1,97 @@
+package models
+
+import "time"
+
+// Loss distribution constants (from risk_loss_distribution enum).
+const (
+	LossDistributionPERT      = "pert"
+	LossDistributionLognormal = "lognormal"
+)
+
+// ValidLossDistributions is the complete list.
+var ValidLossDistributions = []string{LossDistributionPERT, LossDistributionLognormal}
+
+// IsValidLossDistribution checks if a distribution is valid.
+func IsValidLossDistribution(d string) bool {
+	for _, v := range ValidLossDistributions {
+		if v == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Simulation limits.
+const (
+	DefaultSimulationIterations = 10000
+	MinSimulationIterations     = 1000
+	MaxSimulationIterations     = 100000
+	MaxLossEventFrequency       = 365
+	LossExceedanceCurvePoints   = 21
+
+	// The register summary re-simulates every quantified risk on request, so it is held to fewer
+	// iterations and a budget of sampled values (years plus loss events) across the register.
+	MaxSummaryIterations    = 10000
+	MaxSummarySampledValues = 20000000
+)
+
+// LossPercentiles are the annual loss percentiles reported for every simulation.
+var LossPercentiles = []int{10, 50, 90, 95, 99}
+
+// LossRange is a calibrated min / most likely / max estimate and the distribution drawn from it.
+// Lognormal ranges are fitted to min and max alone, as the 5th and 95th percentiles; their most
+// likely value is not taken from the request and is reported as the distribution's median.
+type LossRange struct {
+	Min          float64 `json:"min"`
+	MostLikely   float64 `json:"most_likely"`
+	Max          float64 `json:"max"`
+	Distribution string  `json:"distribution"`
+}
+
+// LossExceedancePoint is the probability that a year's loss exceeds Loss.
+type LossExceedancePoint struct {
+	Loss        float64 `json:"loss"`
+	Probability float64 `json:"probability"`
+}
+
+// LossSimulationSummary is the outcome of a Monte Carlo simulation of annual loss.
+type LossSimulationSummary struct {
+	Iterations        int                   `json:"iterations"`
+	ALE               float64               `json:"annualized_loss_expectancy"`
+	StdDev            float64               `json:"std_dev"`
+	Min               float64               `json:"min"`
+	Max               float64               `json:"max"`
+	ProbabilityOfLoss float64               `json:"probability_of_loss"`
+	Percentiles       map[string]float64    `json:"percentiles"`
+	ExceedanceCurve   []LossExceedancePoint `json:"loss_exceedance_curve"`
+}
+
+// RiskQuantification is a risk's FAIR model and its latest simulation.
+type RiskQuantification struct {
+	ID          string                 `json:"id"`
+	RiskID      string                 `json:"risk_id"`
+	Currency    string                 `json:"currency"`
+	Frequency   LossRange              `json:"loss_event_frequency"`
+	Magnitude   LossRange              `json:"loss_magnitude"`
+	Iterations  int                    `json:"iterations"`
+	Seed        int64                  `json:"seed"`
+	Notes       *string                `json:"notes"`
+	Results     *LossSimulationSummary `json:"results"`
+	SimulatedAt time.Time              `json:"simulated_at"`
+	UpdatedBy   *string                `json:"updated_by"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// RiskQuantifyRoles can model and simulate risks quantitatively.
+var RiskQuantifyRoles = []string{RoleCISO, RoleComplianceManager, RoleSecurityEngineer}
+
+// SetRiskQuantificationRequest sets a risk's FAIR model; the simulation runs on save.
+type SetRiskQuantificationRequest struct {
+	Currency   *string    `json:"currency"`
+	Frequency  *LossRange `json:"loss_event_frequency" binding:"required"`
+	Magnitude  *LossRange `json:"loss_magnitude" binding:"required"`
+	Iterations *int       `json:"iterations"`
+	Seed       *int64     `json:"seed"`
+	Notes      *string    `json:"notes"`
+}
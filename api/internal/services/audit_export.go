@@ -0,0 +1,559 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// AuditExportStore is the object storage access needed to build audit evidence exports.
+// *MinIOService satisfies it.
+type AuditExportStore interface {
+	OpenObject(ctx context.Context, objectKey string) (io.ReadCloser, error)
+	UploadObject(ctx context.Context, objectKey, contentType string, r io.Reader, size int64) error
+}
+
+// Files written at the root of every export.
+const (
+	AuditExportManifestCSV  = "manifest.csv"
+	AuditExportManifestJSON = "manifest.json"
+	AuditExportSignature    = "manifest.json.sig"
+	AuditExportPublicKey    = "signing_key.pem"
+)
+
+// ExportSigner signs export manifests with an Ed25519 key.
+type ExportSigner struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+// NewExportSigner creates a signer from a base64-encoded Ed25519 seed.
+func NewExportSigner(seedB64 string) (*ExportSigner, error) {
+	seed, err := base64.StdEncoding.DecodeString(seedB64)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("export signing key must be a base64-encoded %d-byte Ed25519 seed", ed25519.SeedSize)
+	}
+	key := ed25519.NewKeyFromSeed(seed)
+	sum := sha256.Sum256(key.Public().(ed25519.PublicKey))
+	return &ExportSigner{key: key, keyID: hex.EncodeToString(sum[:8])}, nil
+}
+
+// KeyID is a short fingerprint of the public key.
+func (s *ExportSigner) KeyID() string {
+	return s.keyID
+}
+
+// PublicKey returns the key that verifies signatures made by this signer.
+func (s *ExportSigner) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// PublicKeyPEM returns the public key as a PKIX PEM block.
+func (s *ExportSigner) PublicKeyPEM() string {
+	der, _ := x509.MarshalPKIXPublicKey(s.PublicKey())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// Sign returns the base64 Ed25519 signature over data.
+func (s *ExportSigner) Sign(data []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, data))
+}
+
+// VerifyExportSignature reports whether sigB64 is a valid signature over data.
+func VerifyExportSignature(pub ed25519.PublicKey, data []byte, sigB64 string) bool {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(sigB64))
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, data, sig)
+}
+
+// AuditExportJob is a claimed export to build.
+type AuditExportJob struct {
+	ID          string
+	OrgID       string
+	AuditID     string
+	RequestedBy *string
+	Attempts    int
+}
+
+// AuditExportResult describes a finished export package.
+type AuditExportResult struct {
+	ObjectKey          string
+	FileSize           int64
+	ChecksumSHA256     string
+	ManifestSHA256     string
+	Signature          string
+	SigningKeyID       string
+	RequestCount       int
+	ArtifactCount      int
+	FilesIncluded      int
+	ChecksumMismatches int
+}
+
+// AuditExportManifest is the content of manifest.json.
+type AuditExportManifest struct {
+	FormatVersion     int                          `json:"format_version"`
+	ExportID          string                       `json:"export_id"`
+	OrgID             string                       `json:"org_id"`
+	AuditID           string                       `json:"audit_id"`
+	AuditTitle        string                       `json:"audit_title"`
+	GeneratedAt       time.Time                    `json:"generated_at"`
+	RequestedBy       *string                      `json:"requested_by"`
+	Requests          []AuditExportManifestRequest `json:"requests"`
+	Files             []AuditExportManifestEntry   `json:"files"`
+	ManifestCSVSHA256 string                       `json:"manifest_csv_sha256"`
+	SignatureAlg      string                       `json:"signature_algorithm"`
+	SigningKeyID      string                       `json:"signing_key_id"`
+}
+
+// AuditExportManifestRequest is one audit request folder in the package.
+type AuditExportManifestRequest struct {
+	ID              string  `json:"id"`
+	ReferenceNumber *string `json:"reference_number"`
+	Title           string  `json:"title"`
+	Folder          string  `json:"folder"`
+	FileCount       int     `json:"file_count"`
+}
+
+// AuditExportManifestEntry is one accepted evidence submission. Path is empty when the file
+// could not be included; SkipReason says why.
+type AuditExportManifestEntry struct {
+	Path              string                    `json:"path"`
+	SkipReason        string                    `json:"skip_reason,omitempty"`
+	RequestID         string                    `json:"request_id"`
+	LinkID            string                    `json:"link_id"`
+	ArtifactID        string                    `json:"artifact_id"`
+	RootArtifactID    string                    `json:"root_artifact_id"`
+	Title             string                    `json:"title"`
+	Version           int                       `json:"version"`
+	FileName          string                    `json:"file_name"`
+	MIMEType          string                    `json:"mime_type"`
+	FileSize          int64                     `json:"file_size"`
+	RecordedSHA256    *string                   `json:"recorded_sha256"`
+	ExportedSHA256    string                    `json:"exported_sha256,omitempty"`
+	ChecksumMatch     *bool                     `json:"checksum_match"`
+	ChainHash         *string                   `json:"chain_hash"`
+	PreviousChainHash *string                   `json:"previous_chain_hash"`
+	CollectionDate    string                    `json:"collection_date"`
+	Custody           []AuditExportCustodyEvent `json:"chain_of_custody"`
+}
+
+// AuditExportCustodyEvent is one step in an artifact's chain of custody.
+type AuditExportCustodyEvent struct {
+	Event  string     `json:"event"`
+	At     *time.Time `json:"at"`
+	By     *string    `json:"by,omitempty"`
+	ByName string     `json:"by_name,omitempty"`
+	Detail string     `json:"detail,omitempty"`
+}
+
+// Chain-of-custody events recorded in the manifest.
+const (
+	CustodyUploaded         = "uploaded"
+	CustodyChecksumVerified = "checksum_verified"
+	CustodyScanned          = "scanned"
+	CustodySubmitted        = "submitted"
+	CustodyAccepted         = "accepted"
+)
+
+var custodyEvents = []string{CustodyUploaded, CustodyChecksumVerified, CustodyScanned, CustodySubmitted, CustodyAccepted}
+
+type auditExportItem struct {
+	entry            AuditExportManifestEntry
+	requestRef       *string
+	requestTitle     string
+	objectKey        string
+	processingStatus string
+	purged           bool
+	restricted       bool
+	uploadedAt       time.Time
+}
+
+// RunAuditEvidenceExport builds a claimed export and records the outcome. Build errors put the
+// export back in the queue until MaxAuditExportAttempts is reached, after which it is failed.
+func RunAuditEvidenceExport(ctx context.Context, db *sql.DB, store AuditExportStore, signer *ExportSigner, job AuditExportJob) (*AuditExportResult, error) {
+	res, buildErr := BuildAuditEvidenceExport(ctx, db, store, signer, job)
+	if buildErr != nil {
+		status := models.AuditExportPending
+		if job.Attempts >= models.MaxAuditExportAttempts {
+			status = models.AuditExportFailed
+		}
+		if _, err := db.ExecContext(ctx, `
+			UPDATE audit_evidence_exports SET status = $1, error_message = $2 WHERE id = $3
+		`, status, buildErr.Error(), job.ID); err != nil {
+			return nil, fmt.Errorf("record export failure: %w", err)
+		}
+		return nil, buildErr
+	}
+
+	_, err := db.ExecContext(ctx, `
+		UPDATE audit_evidence_exports
+		SET status = 'completed', object_key = $1, file_size = $2, checksum_sha256 = $3,
+			manifest_sha256 = $4, signature = $5, signing_key_id = $6,
+			request_count = $7, artifact_count = $8, files_included = $9, checksum_mismatches = $10,
+			error_message = NULL, completed_at = NOW()
+		WHERE id = $11
+	`, res.ObjectKey, res.FileSize, res.ChecksumSHA256, res.ManifestSHA256, res.Signature, res.SigningKeyID,
+		res.RequestCount, res.ArtifactCount, res.FilesIncluded, res.ChecksumMismatches, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("record export: %w", err)
+	}
+	return res, nil
+}
+
+// BuildAuditEvidenceExport packages every accepted evidence submission of an audit into a ZIP
+// with a folder per request, re-hashing each file as it is copied. The manifest (CSV and JSON)
+// records checksums and chain of custody, and manifest.json is signed; the signature ships as
+// manifest.json.sig next to the public key. Files that were purged, did not pass scanning or
+// are access-restricted originals are listed in the manifest but left out of the package.
+func BuildAuditEvidenceExport(ctx context.Context, db *sql.DB, store AuditExportStore, signer *ExportSigner, job AuditExportJob) (*AuditExportResult, error) {
+	var auditTitle string
+	err := db.QueryRowContext(ctx, `SELECT title FROM audits WHERE id = $1 AND org_id = $2`,
+		job.AuditID, job.OrgID).Scan(&auditTitle)
+	if err != nil {
+		return nil, fmt.Errorf("load audit: %w", err)
+	}
+
+	items, err := loadAuditExportItems(ctx, db, job)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "audit-export-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zipHash := sha256.New()
+	zw := zip.NewWriter(io.MultiWriter(tmp, zipHash))
+
+	manifest := AuditExportManifest{
+		FormatVersion: 1,
+		ExportID:      job.ID,
+		OrgID:         job.OrgID,
+		AuditID:       job.AuditID,
+		AuditTitle:    auditTitle,
+		GeneratedAt:   time.Now().UTC(),
+		RequestedBy:   job.RequestedBy,
+		Requests:      []AuditExportManifestRequest{},
+		Files:         []AuditExportManifestEntry{},
+		SignatureAlg:  "Ed25519",
+		SigningKeyID:  signer.KeyID(),
+	}
+	res := &AuditExportResult{
+		ObjectKey:    fmt.Sprintf("%s/exports/audits/%s/%s.zip", job.OrgID, job.AuditID, job.ID),
+		SigningKeyID: signer.KeyID(),
+	}
+
+	folders := map[string]int{}
+	usedFolders := map[string]bool{}
+	usedPaths := map[string]bool{}
+	artifacts := map[string]bool{}
+	for _, it := range items {
+		idx, ok := folders[it.entry.RequestID]
+		if !ok {
+			name := it.requestTitle
+			if it.requestRef != nil && *it.requestRef != "" {
+				name = *it.requestRef + " " + name
+			}
+			folder := uniqueExportName(usedFolders, exportPathSegment(name, 80))
+			idx = len(manifest.Requests)
+			folders[it.entry.RequestID] = idx
+			manifest.Requests = append(manifest.Requests, AuditExportManifestRequest{
+				ID: it.entry.RequestID, ReferenceNumber: it.requestRef, Title: it.requestTitle, Folder: folder,
+			})
+		}
+		artifacts[it.entry.ArtifactID] = true
+
+		entry := it.entry
+		switch {
+		case it.purged:
+			entry.SkipReason = "purged under the retention policy"
+		case it.restricted:
+			entry.SkipReason = "unredacted original is access-restricted; submit the redacted version"
+		case !models.IsProcessingCleared(it.processingStatus):
+			entry.SkipReason = "file has not passed malware scanning (" + it.processingStatus + ")"
+		default:
+			folder := manifest.Requests[idx].Folder
+			path := uniqueExportName(usedPaths, folder+"/"+exportPathSegment(fmt.Sprintf("v%d_%s", entry.Version, entry.FileName), 120))
+			sum, err := copyExportFile(ctx, zw, store, it.objectKey, path, it.uploadedAt)
+			if err != nil {
+				return nil, fmt.Errorf("export artifact %s: %w", entry.ArtifactID, err)
+			}
+			entry.Path = path
+			entry.ExportedSHA256 = sum
+			if entry.RecordedSHA256 != nil {
+				match := *entry.RecordedSHA256 == sum
+				entry.ChecksumMatch = &match
+				if !match {
+					res.ChecksumMismatches++
+				}
+			}
+			manifest.Requests[idx].FileCount++
+			res.FilesIncluded++
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+	res.RequestCount = len(manifest.Requests)
+	res.ArtifactCount = len(artifacts)
+
+	csvData, err := auditExportManifestCSV(manifest.Files)
+	if err != nil {
+		return nil, err
+	}
+	csvSum := sha256.Sum256(csvData)
+	manifest.ManifestCSVSHA256 = hex.EncodeToString(csvSum[:])
+
+	jsonData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	jsonSum := sha256.Sum256(jsonData)
+	res.ManifestSHA256 = hex.EncodeToString(jsonSum[:])
+	res.Signature = signer.Sign(jsonData)
+
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{AuditExportManifestCSV, csvData},
+		{AuditExportManifestJSON, jsonData},
+		{AuditExportSignature, []byte(res.Signature + "\n")},
+		{AuditExportPublicKey, []byte(signer.PublicKeyPEM())},
+	} {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: manifest.GeneratedAt})
+		if err != nil {
+			return nil, fmt.Errorf("write %s: %w", f.name, err)
+		}
+		if _, err := w.Write(f.data); err != nil {
+			return nil, fmt.Errorf("write %s: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("finish zip: %w", err)
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("stat zip: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("rewind zip: %w", err)
+	}
+	res.FileSize = size
+	res.ChecksumSHA256 = hex.EncodeToString(zipHash.Sum(nil))
+
+	if err := store.UploadObject(ctx, res.ObjectKey, "application/zip", tmp, size); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func loadAuditExportItems(ctx context.Context, db *sql.DB, job AuditExportJob) ([]auditExportItem, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT ar.id, ar.reference_number, ar.title, ael.id,
+			ea.id, COALESCE(ea.parent_artifact_id, ea.id), ea.title, ea.version,
+			ea.file_name, ea.mime_type, ea.file_size, ea.object_key,
+			ea.checksum_sha256, ea.checksum_verified_at, ea.chain_hash, ea.previous_chain_hash,
+			to_char(ea.collection_date, 'YYYY-MM-DD'), ea.processing_status, ea.purged_at IS NOT NULL, ea.access_restricted,
+			ea.created_at, ea.uploaded_by, COALESCE(up.first_name || ' ' || up.last_name, ''),
+			ea.processed_at, COALESCE(ea.scan_engine, ''),
+			ael.submitted_at, ael.submitted_by, COALESCE(sub.first_name || ' ' || sub.last_name, ''),
+			ael.reviewed_at, ael.reviewed_by, COALESCE(rev.first_name || ' ' || rev.last_name, ''),
+			COALESCE(ael.review_notes, '')
+		FROM audit_evidence_links ael
+		JOIN audit_requests ar ON ar.id = ael.request_id
+		JOIN evidence_artifacts ea ON ea.id = ael.artifact_id
+		LEFT JOIN users up ON up.id = ea.uploaded_by
+		LEFT JOIN users sub ON sub.id = ael.submitted_by
+		LEFT JOIN users rev ON rev.id = ael.reviewed_by
+		WHERE ael.audit_id = $1 AND ael.org_id = $2 AND ael.status = 'accepted'
+		ORDER BY ar.reference_number NULLS LAST, ar.created_at, ar.id, ael.submitted_at
+	`, job.AuditID, job.OrgID)
+	if err != nil {
+		return nil, fmt.Errorf("load accepted evidence: %w", err)
+	}
+	defer rows.Close()
+
+	var items []auditExportItem
+	for rows.Next() {
+		var it auditExportItem
+		e := &it.entry
+		var verifiedAt, processedAt, reviewedAt *time.Time
+		var submittedAt time.Time
+		var uploadedBy, submittedBy, reviewedBy *string
+		var uploadedByName, scanEngine, submittedByName, reviewedByName, reviewNotes string
+		if err := rows.Scan(&e.RequestID, &it.requestRef, &it.requestTitle, &e.LinkID,
+			&e.ArtifactID, &e.RootArtifactID, &e.Title, &e.Version,
+			&e.FileName, &e.MIMEType, &e.FileSize, &it.objectKey,
+			&e.RecordedSHA256, &verifiedAt, &e.ChainHash, &e.PreviousChainHash,
+			&e.CollectionDate, &it.processingStatus, &it.purged, &it.restricted,
+			&it.uploadedAt, &uploadedBy, &uploadedByName,
+			&processedAt, &scanEngine,
+			&submittedAt, &submittedBy, &submittedByName,
+			&reviewedAt, &reviewedBy, &reviewedByName, &reviewNotes); err != nil {
+			return nil, fmt.Errorf("scan accepted evidence: %w", err)
+		}
+
+		uploadedAt := it.uploadedAt
+		e.Custody = []AuditExportCustodyEvent{
+			{Event: CustodyUploaded, At: &uploadedAt, By: uploadedBy, ByName: uploadedByName},
+		}
+		if verifiedAt != nil {
+			e.Custody = append(e.Custody, AuditExportCustodyEvent{Event: CustodyChecksumVerified, At: verifiedAt, Detail: "server-side SHA-256"})
+		}
+		if processedAt != nil {
+			detail := it.processingStatus
+			if scanEngine != "" {
+				detail += " (" + scanEngine + ")"
+			}
+			e.Custody = append(e.Custody, AuditExportCustodyEvent{Event: CustodyScanned, At: processedAt, Detail: detail})
+		}
+		e.Custody = append(e.Custody,
+			AuditExportCustodyEvent{Event: CustodySubmitted, At: &submittedAt, By: submittedBy, ByName: submittedByName},
+			AuditExportCustodyEvent{Event: CustodyAccepted, At: reviewedAt, By: reviewedBy, ByName: reviewedByName, Detail: reviewNotes},
+		)
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// copyExportFile streams an object into the zip and returns the SHA-256 of what was written.
+func copyExportFile(ctx context.Context, zw *zip.Writer, store AuditExportStore, objectKey, path string, modified time.Time) (string, error) {
+	obj, err := store.OpenObject(ctx, objectKey)
+	if err != nil {
+		return "", err
+	}
+	defer obj.Close()
+
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: path, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, h), obj); err != nil {
+		return "", fmt.Errorf("read object: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// auditExportManifestCSV flattens the manifest entries, one row per submission, with an
+// at/by column pair per custody event.
+func auditExportManifestCSV(entries []AuditExportManifestEntry) ([]byte, error) {
+	header := []string{
+		"path", "skip_reason", "request_id", "link_id", "artifact_id", "root_artifact_id", "title",
+		"version", "file_name", "mime_type", "file_size", "recorded_sha256", "exported_sha256",
+		"checksum_match", "chain_hash", "previous_chain_hash", "collection_date",
+	}
+	for _, ev := range custodyEvents {
+		header = append(header, ev+"_at", ev+"_by")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		match := ""
+		if e.ChecksumMatch != nil {
+			match = strconv.FormatBool(*e.ChecksumMatch)
+		}
+		row := []string{
+			e.Path, e.SkipReason, e.RequestID, e.LinkID, e.ArtifactID, e.RootArtifactID, e.Title,
+			strconv.Itoa(e.Version), e.FileName, e.MIMEType, strconv.FormatInt(e.FileSize, 10),
+			derefString(e.RecordedSHA256), e.ExportedSHA256, match,
+			derefString(e.ChainHash), derefString(e.PreviousChainHash), e.CollectionDate,
+		}
+		for _, name := range custodyEvents {
+			at, by := "", ""
+			for _, ev := range e.Custody {
+				if ev.Event != name {
+					continue
+				}
+				if ev.At != nil {
+					at = ev.At.UTC().Format(time.RFC3339)
+				}
+				by = ev.ByName
+				if by == "" {
+					by = derefString(ev.By)
+				}
+			}
+			row = append(row, at, by)
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("encode manifest csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// exportPathSegment turns a title into a portable file or folder name.
+func exportPathSegment(s string, max int) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range s {
+		ok := r == '.' || r == '-' || r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if ok {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	out := strings.Trim(b.String(), "_.")
+	if len(out) > max {
+		out = strings.TrimRight(out[:max], "_.")
+	}
+	if out == "" {
+		out = "untitled"
+	}
+	return out
+}
+
+// uniqueExportName appends a counter to name until it is unused, then marks it used.
+func uniqueExportName(used map[string]bool, name string) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		ext := ""
+		base := name
+		if dot := strings.LastIndex(name, "."); dot > strings.LastIndex(name, "/")+1 {
+			base, ext = name[:dot], name[dot:]
+		}
+		candidate = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
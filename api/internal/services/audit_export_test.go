@@ -0,0 +1,186 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExportStore struct {
+	objects  map[string]string
+	uploaded map[string][]byte
+	err      error
+}
+
+func (s *fakeExportStore) OpenObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, errors.New("object not found")
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
+
+func (s *fakeExportStore) UploadObject(ctx context.Context, key, contentType string, r io.Reader, size int64) error {
+	if s.err != nil {
+		return s.err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	s.uploaded[key] = data
+	return nil
+}
+
+func testExportSigner(t *testing.T) *ExportSigner {
+	signer, err := NewExportSigner(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32)))
+	require.NoError(t, err)
+	return signer
+}
+
+func sha(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+var auditExportItemColumns = []string{
+	"request_id", "reference_number", "request_title", "link_id",
+	"artifact_id", "root_id", "title", "version", "file_name", "mime_type", "file_size", "object_key",
+	"checksum_sha256", "checksum_verified_at", "chain_hash", "previous_chain_hash",
+	"collection_date", "processing_status", "purged", "access_restricted",
+	"created_at", "uploaded_by", "uploaded_by_name", "processed_at", "scan_engine",
+	"submitted_at", "submitted_by", "submitted_by_name",
+	"reviewed_at", "reviewed_by", "reviewed_by_name", "review_notes",
+}
+
+func auditExportItemRow(rows *sqlmock.Rows, reqID, ref, artifactID, fileName, objectKey string, checksum interface{}, status string, purged bool) *sqlmock.Rows {
+	now := time.Now()
+	return rows.AddRow(reqID, ref, "Access reviews", "l-"+artifactID,
+		artifactID, artifactID, "Quarterly access review", 2, fileName, "text/csv", 10, objectKey,
+		checksum, now, "chain", "prev",
+		"2026-09-30", status, purged, false,
+		now, "u001", "Ada Admin", now, "clamav",
+		now, "u002", "Sam Submitter",
+		now, "u003", "Alex Auditor", "Looks good")
+}
+
+func TestBuildAuditEvidenceExport_PackagesAndSigns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT title FROM audits").WithArgs("aud1", "org1").
+		WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("SOC 2 Type II"))
+	rows := sqlmock.NewRows(auditExportItemColumns)
+	auditExportItemRow(rows, "r1", "PBC-001", "e1", "users.csv", "org1/e1/2/users.csv", sha("alice,bob"), "passed", false)
+	auditExportItemRow(rows, "r1", "PBC-001", "e2", "users.csv", "org1/e2/2/users.csv", sha("original"), "passed", false)
+	auditExportItemRow(rows, "r2", "PBC-002", "e3", "old.csv", "org1/e3/2/old.csv", sha("old"), "passed", true)
+	mock.ExpectQuery("FROM audit_evidence_links ael").WithArgs("aud1", "org1").WillReturnRows(rows)
+
+	store := &fakeExportStore{
+		objects: map[string]string{
+			"org1/e1/2/users.csv": "alice,bob",
+			"org1/e2/2/users.csv": "tampered",
+		},
+		uploaded: map[string][]byte{},
+	}
+	signer := testExportSigner(t)
+	res, err := BuildAuditEvidenceExport(context.Background(), db, store, signer,
+		AuditExportJob{ID: "x1", OrgID: "org1", AuditID: "aud1"})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, "org1/exports/audits/aud1/x1.zip", res.ObjectKey)
+	assert.Equal(t, 2, res.RequestCount)
+	assert.Equal(t, 3, res.ArtifactCount)
+	assert.Equal(t, 2, res.FilesIncluded)
+	assert.Equal(t, 1, res.ChecksumMismatches)
+
+	data := store.uploaded[res.ObjectKey]
+	require.NotEmpty(t, data)
+	assert.Equal(t, int64(len(data)), res.FileSize)
+	assert.Equal(t, sha(string(data)), res.ChecksumSHA256)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+	assert.Equal(t, "alice,bob", files["PBC-001_Access_reviews/v2_users.csv"])
+	assert.Equal(t, "tampered", files["PBC-001_Access_reviews/v2_users_2.csv"])
+	assert.Contains(t, files, AuditExportManifestCSV)
+	assert.Contains(t, files, AuditExportPublicKey)
+
+	manifestJSON := []byte(files[AuditExportManifestJSON])
+	assert.True(t, VerifyExportSignature(signer.PublicKey(), manifestJSON, files[AuditExportSignature]))
+	assert.Equal(t, res.ManifestSHA256, sha(string(manifestJSON)))
+
+	var manifest AuditExportManifest
+	require.NoError(t, json.Unmarshal(manifestJSON, &manifest))
+	assert.Equal(t, sha(files[AuditExportManifestCSV]), manifest.ManifestCSVSHA256)
+	assert.Equal(t, signer.KeyID(), manifest.SigningKeyID)
+	require.Len(t, manifest.Files, 3)
+	assert.True(t, *manifest.Files[0].ChecksumMatch)
+	assert.False(t, *manifest.Files[1].ChecksumMatch)
+	assert.Empty(t, manifest.Files[2].Path)
+	assert.Contains(t, manifest.Files[2].SkipReason, "purged")
+	assert.Len(t, manifest.Files[0].Custody, 5)
+	assert.Equal(t, 0, manifest.Requests[1].FileCount)
+
+	// Any change to the manifest invalidates the signature.
+	tampered := bytes.Replace(manifestJSON, []byte("SOC 2"), []byte("SOC 1"), 1)
+	assert.False(t, VerifyExportSignature(signer.PublicKey(), tampered, files[AuditExportSignature]))
+}
+
+func TestRunAuditEvidenceExport_RequeuesThenFails(t *testing.T) {
+	for _, tc := range []struct {
+		attempts int
+		status   string
+	}{{1, "pending"}, {3, "failed"}} {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		mock.ExpectQuery("SELECT title FROM audits").
+			WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("SOC 2"))
+		rows := sqlmock.NewRows(auditExportItemColumns)
+		auditExportItemRow(rows, "r1", "PBC-001", "e1", "users.csv", "org1/e1/2/users.csv", nil, "passed", false)
+		mock.ExpectQuery("FROM audit_evidence_links ael").WillReturnRows(rows)
+		mock.ExpectExec("UPDATE audit_evidence_exports SET status").
+			WithArgs(tc.status, sqlmock.AnyArg(), "x1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		store := &fakeExportStore{objects: map[string]string{}, uploaded: map[string][]byte{}}
+		_, err = RunAuditEvidenceExport(context.Background(), db, store, testExportSigner(t),
+			AuditExportJob{ID: "x1", OrgID: "org1", AuditID: "aud1", Attempts: tc.attempts})
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		db.Close()
+	}
+}
+
+func TestExportPathSegment(t *testing.T) {
+	assert.Equal(t, "PBC-001_Access_reviews_Q3", exportPathSegment("PBC-001 Access reviews (Q3)", 80))
+	assert.Equal(t, "etc_passwd", exportPathSegment("../etc/passwd", 80))
+	assert.Equal(t, "untitled", exportPathSegment("///", 80))
+	assert.Equal(t, "abc", exportPathSegment("abcdef", 3))
+}
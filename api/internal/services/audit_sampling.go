@@ -0,0 +1,160 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// ErrInvalidPopulation is returned when a population upload cannot be used for sampling.
+var ErrInvalidPopulation = errors.New("invalid population")
+
+// ParsedPopulation is a population read from a CSV file.
+type ParsedPopulation struct {
+	Columns   []string
+	KeyColumn string
+	Items     []models.PopulationItemInput
+}
+
+// ParsePopulationCSV reads a population list with a header row. Each row becomes an item keyed
+// by keyColumn (the first column when empty); every column is kept as an attribute. Blank rows
+// are skipped; empty or duplicate keys are rejected so each sampled item is unambiguous.
+func ParsePopulationCSV(r io.Reader, keyColumn string) (*ParsedPopulation, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidPopulation)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPopulation, err)
+	}
+	columns := make([]string, len(header))
+	for i, h := range header {
+		columns[i] = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+	}
+
+	keyIdx := 0
+	if keyColumn != "" {
+		keyIdx = -1
+		for i, col := range columns {
+			if strings.EqualFold(col, keyColumn) {
+				keyIdx = i
+				break
+			}
+		}
+		if keyIdx < 0 {
+			return nil, fmt.Errorf("%w: key column %q not found in header", ErrInvalidPopulation, keyColumn)
+		}
+	}
+
+	p := &ParsedPopulation{Columns: columns, KeyColumn: columns[keyIdx]}
+	seen := map[string]int{}
+	line := 1
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPopulation, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(p.Items) >= models.MaxPopulationItems {
+			return nil, fmt.Errorf("%w: more than %d items", ErrInvalidPopulation, models.MaxPopulationItems)
+		}
+
+		key := ""
+		if keyIdx < len(record) {
+			key = strings.TrimSpace(record[keyIdx])
+		}
+		if key == "" {
+			return nil, fmt.Errorf("%w: row %d has an empty %s", ErrInvalidPopulation, line, p.KeyColumn)
+		}
+		if len(key) > 255 {
+			return nil, fmt.Errorf("%w: row %d %s is longer than 255 characters", ErrInvalidPopulation, line, p.KeyColumn)
+		}
+		if first, dup := seen[key]; dup {
+			return nil, fmt.Errorf("%w: %s %q appears on rows %d and %d", ErrInvalidPopulation, p.KeyColumn, key, first, line)
+		}
+		seen[key] = line
+
+		attrs := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			if i < len(record) && col != "" {
+				attrs[col] = strings.TrimSpace(record[i])
+			}
+		}
+		p.Items = append(p.Items, models.PopulationItemInput{Key: key, Attributes: attrs})
+	}
+
+	if len(p.Items) == 0 {
+		return nil, fmt.Errorf("%w: no items found", ErrInvalidPopulation)
+	}
+	return p, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// PopulationChecksum is the SHA-256 of the item keys in position order, joined by newlines.
+// It is recorded with each sample so a random selection can be replayed against the same list.
+func PopulationChecksum(keys []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// RandomSampleIndexes picks sampleSize distinct 0-based population positions. The selection is
+// the first sampleSize entries of a permutation of [0, populationSize) drawn from Go's PCG
+// generator seeded with (seed, 0), so the same seed over the same population always yields the
+// same sample in the same order.
+func RandomSampleIndexes(populationSize, sampleSize int, seed int64) []int {
+	if sampleSize > populationSize {
+		sampleSize = populationSize
+	}
+	if sampleSize <= 0 {
+		return []int{}
+	}
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	return rng.Perm(populationSize)[:sampleSize]
+}
+
+// SampleItemStatus derives a sample item's status from the evidence submitted for it: accepted
+// once any submission is accepted, otherwise the most pressing state of its open submissions.
+func SampleItemStatus(linkStatuses []string) string {
+	if len(linkStatuses) == 0 {
+		return models.SampleItemRequested
+	}
+	counts := map[string]int{}
+	for _, s := range linkStatuses {
+		counts[s]++
+	}
+	switch {
+	case counts["accepted"] > 0:
+		return models.SampleItemAccepted
+	case counts["pending_review"] > 0:
+		return models.SampleItemPendingReview
+	case counts["needs_clarification"] > 0:
+		return models.SampleItemNeedsClarification
+	default:
+		return models.SampleItemRejected
+	}
+}
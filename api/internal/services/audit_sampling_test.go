@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePopulationCSV(t *testing.T) {
+	csv := "\ufeffTicket,Summary,Closed\nCHG-1,Patch db,2026-01-03\n\n,,\nCHG-2, Rotate keys ,2026-02-11\n"
+
+	p, err := ParsePopulationCSV(strings.NewReader(csv), "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Ticket", "Summary", "Closed"}, p.Columns)
+	assert.Equal(t, "Ticket", p.KeyColumn)
+	require.Len(t, p.Items, 2)
+	assert.Equal(t, "CHG-2", p.Items[1].Key)
+	assert.Equal(t, "Rotate keys", p.Items[1].Attributes["Summary"])
+
+	p, err = ParsePopulationCSV(strings.NewReader(csv), "closed")
+	require.NoError(t, err)
+	assert.Equal(t, "Closed", p.KeyColumn)
+	assert.Equal(t, "2026-01-03", p.Items[0].Key)
+}
+
+func TestParsePopulationCSV_Rejects(t *testing.T) {
+	for name, tc := range map[string]struct{ csv, key string }{
+		"empty":          {"", ""},
+		"header only":    {"Ticket\n", ""},
+		"unknown column": {"Ticket\nCHG-1\n", "Owner"},
+		"empty key":      {"Ticket,Summary\n,orphan\n", ""},
+		"duplicate key":  {"Ticket\nCHG-1\nCHG-2\nCHG-1\n", ""},
+	} {
+		_, err := ParsePopulationCSV(strings.NewReader(tc.csv), tc.key)
+		assert.True(t, errors.Is(err, ErrInvalidPopulation), name)
+	}
+}
+
+func TestRandomSampleIndexes_Reproducible(t *testing.T) {
+	a := RandomSampleIndexes(400, 25, 20261018)
+	b := RandomSampleIndexes(400, 25, 20261018)
+	assert.Equal(t, a, b, "same seed yields the same sample")
+	assert.Len(t, a, 25)
+
+	seen := map[int]bool{}
+	for _, i := range a {
+		assert.True(t, i >= 0 && i < 400)
+		assert.False(t, seen[i], "indexes are distinct")
+		seen[i] = true
+	}
+
+	assert.NotEqual(t, a, RandomSampleIndexes(400, 25, 20261019))
+	assert.Len(t, RandomSampleIndexes(3, 10, 1), 3)
+	assert.Empty(t, RandomSampleIndexes(0, 5, 1))
+}
+
+func TestPopulationChecksum(t *testing.T) {
+	assert.Equal(t, PopulationChecksum([]string{"a", "b"}), PopulationChecksum([]string{"a", "b"}))
+	assert.NotEqual(t, PopulationChecksum([]string{"a", "b"}), PopulationChecksum([]string{"b", "a"}))
+	assert.Len(t, PopulationChecksum(nil), 64)
+}
+
+func TestSampleItemStatus(t *testing.T) {
+	assert.Equal(t, models.SampleItemRequested, SampleItemStatus(nil))
+	assert.Equal(t, models.SampleItemAccepted, SampleItemStatus([]string{"rejected", "accepted"}))
+	assert.Equal(t, models.SampleItemPendingReview, SampleItemStatus([]string{"rejected", "pending_review"}))
+	assert.Equal(t, models.SampleItemNeedsClarification, SampleItemStatus([]string{"needs_clarification", "rejected"}))
+	assert.Equal(t, models.SampleItemRejected, SampleItemStatus([]string{"rejected"}))
+}
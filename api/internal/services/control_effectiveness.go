@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// Look-back windows for effectiveness signals.
+const (
+	EffectivenessTestWindowDays  = 30
+	EffectivenessAlertWindowDays = 90
+)
+
+// Effectiveness component names and weights. Components without data are
+// dropped and the remaining weights are renormalized.
+const (
+	ComponentTestPassRate      = "test_pass_rate"
+	ComponentEvidenceFreshness = "evidence_freshness"
+	ComponentEvidenceVerdicts  = "evidence_verdicts"
+	ComponentAuditFindings     = "audit_findings"
+	ComponentAlertSLA          = "alert_sla"
+)
+
+var effectivenessWeights = map[string]float64{
+	ComponentTestPassRate:      0.35,
+	ComponentEvidenceFreshness: 0.20,
+	ComponentEvidenceVerdicts:  0.20,
+	ComponentAuditFindings:     0.15,
+	ComponentAlertSLA:          0.10,
+}
+
+// Per-item scores (0–100) used inside components.
+var (
+	freshnessItemScores = map[string]float64{"fresh": 100, "expiring_soon": 70, "expired": 0}
+	verdictItemScores   = map[string]float64{"sufficient": 100, "partial": 50, "needs_update": 25, "insufficient": 0}
+	findingPenalties    = map[string]float64{"critical": 60, "high": 40, "medium": 20, "low": 10, "informational": 0}
+)
+
+// Alert SLA breach penalties.
+const (
+	openBreachPenalty     = 25
+	resolvedBreachPenalty = 10
+)
+
+// EffectivenessComponent is one weighted signal in a control's effectiveness score.
+type EffectivenessComponent struct {
+	Name    string   `json:"name"`
+	Weight  float64  `json:"weight"`
+	Score   *float64 `json:"score"`
+	Samples int      `json:"samples"`
+	Detail  string   `json:"detail"`
+}
+
+// ControlEffectiveness is the computed effectiveness of a single control.
+type ControlEffectiveness struct {
+	ControlID    string                   `json:"control_id"`
+	Score        *float64                 `json:"score"`
+	Rating       string                   `json:"rating"`
+	Components   []EffectivenessComponent `json:"components"`
+	CalculatedAt time.Time                `json:"calculated_at"`
+}
+
+// EffectivenessInputs holds the raw signals gathered for a control.
+type EffectivenessInputs struct {
+	TestResultCounts      map[string]int // test_result_status → count within the test window
+	EvidenceFreshness     []string       // freshness status per linked current artifact
+	EvaluationVerdicts    []string       // latest verdict per linked current artifact
+	OpenFindingSeverities []string       // severities of unresolved audit findings
+	OpenSLABreaches       int            // breached alerts still open
+	ResolvedSLABreaches   int            // breached alerts resolved/closed within the alert window
+}
+
+// EffectivenessRatingForScore maps a 0–100 score onto the control_effectiveness enum.
+func EffectivenessRatingForScore(score float64) string {
+	switch {
+	case score >= 80:
+		return models.EffectivenessEffective
+	case score >= 50:
+		return models.EffectivenessPartiallyEffective
+	default:
+		return models.EffectivenessIneffective
+	}
+}
+
+// ScoreControlEffectiveness combines gathered signals into a weighted score.
+// Audit findings and alert SLA breaches only penalize; a control with no test
+// results, linked evidence or evaluations is reported as not_assessed.
+func ScoreControlEffectiveness(in EffectivenessInputs) ControlEffectiveness {
+	components := []EffectivenessComponent{}
+	positiveSignals := 0
+
+	// Test pass rate (warnings count as half a pass, skips are ignored)
+	pass, warn := in.TestResultCounts["pass"], in.TestResultCounts["warning"]
+	executed := pass + warn + in.TestResultCounts["fail"] + in.TestResultCounts["error"]
+	testComp := EffectivenessComponent{Name: ComponentTestPassRate, Samples: executed}
+	if executed > 0 {
+		s := (float64(pass) + 0.5*float64(warn)) / float64(executed) * 100
+		testComp.Score = &s
+		testComp.Detail = fmt.Sprintf("%d of %d test results passed in the last %d days", pass, executed, EffectivenessTestWindowDays)
+		positiveSignals++
+	} else {
+		testComp.Detail = fmt.Sprintf("No test results in the last %d days", EffectivenessTestWindowDays)
+	}
+	components = append(components, testComp)
+
+	freshComp := EffectivenessComponent{Name: ComponentEvidenceFreshness, Samples: len(in.EvidenceFreshness)}
+	if s, ok := averageItemScore(in.EvidenceFreshness, freshnessItemScores); ok {
+		freshComp.Score = &s
+		expired := countOf(in.EvidenceFreshness, "expired")
+		freshComp.Detail = fmt.Sprintf("%d linked artifacts, %d expired", len(in.EvidenceFreshness), expired)
+		positiveSignals++
+	} else {
+		freshComp.Detail = "No current evidence linked"
+	}
+	components = append(components, freshComp)
+
+	verdictComp := EffectivenessComponent{Name: ComponentEvidenceVerdicts, Samples: len(in.EvaluationVerdicts)}
+	if s, ok := averageItemScore(in.EvaluationVerdicts, verdictItemScores); ok {
+		verdictComp.Score = &s
+		verdictComp.Detail = fmt.Sprintf("%d of %d latest evaluations sufficient",
+			countOf(in.EvaluationVerdicts, "sufficient"), len(in.EvaluationVerdicts))
+		positiveSignals++
+	} else {
+		verdictComp.Detail = "No evidence evaluations"
+	}
+	components = append(components, verdictComp)
+
+	findingScore := 100.0
+	for _, sev := range in.OpenFindingSeverities {
+		findingScore -= findingPenalties[sev]
+	}
+	findingScore = math.Max(findingScore, 0)
+	components = append(components, EffectivenessComponent{
+		Name:    ComponentAuditFindings,
+		Score:   &findingScore,
+		Samples: len(in.OpenFindingSeverities),
+		Detail:  fmt.Sprintf("%d open audit findings", len(in.OpenFindingSeverities)),
+	})
+
+	alertScore := 100.0 - float64(in.OpenSLABreaches*openBreachPenalty+in.ResolvedSLABreaches*resolvedBreachPenalty)
+	alertScore = math.Max(alertScore, 0)
+	components = append(components, EffectivenessComponent{
+		Name:    ComponentAlertSLA,
+		Score:   &alertScore,
+		Samples: in.OpenSLABreaches + in.ResolvedSLABreaches,
+		Detail: fmt.Sprintf("%d open and %d resolved SLA breaches in the last %d days",
+			in.OpenSLABreaches, in.ResolvedSLABreaches, EffectivenessAlertWindowDays),
+	})
+
+	result := ControlEffectiveness{
+		Rating:       models.EffectivenessNotAssessed,
+		CalculatedAt: time.Now(),
+	}
+
+	var weightSum, weighted float64
+	for _, comp := range components {
+		if comp.Score != nil {
+			weightSum += effectivenessWeights[comp.Name]
+		}
+	}
+	for i := range components {
+		if components[i].Score == nil || weightSum == 0 {
+			continue
+		}
+		components[i].Weight = roundTo(effectivenessWeights[components[i].Name]/weightSum, 4)
+		rounded := roundTo(*components[i].Score, 2)
+		components[i].Score = &rounded
+		weighted += rounded * effectivenessWeights[components[i].Name] / weightSum
+	}
+	result.Components = components
+
+	if positiveSignals > 0 {
+		score := roundTo(weighted, 2)
+		result.Score = &score
+		result.Rating = EffectivenessRatingForScore(score)
+	}
+	return result
+}
+
+// GatherEffectivenessInputs loads the raw effectiveness signals for a control.
+func GatherEffectivenessInputs(ctx context.Context, db *sql.DB, orgID, controlID string) (EffectivenessInputs, error) {
+	in := EffectivenessInputs{TestResultCounts: map[string]int{}}
+	now := time.Now()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT status, COUNT(*) FROM test_results
+		WHERE org_id = $1 AND control_id = $2
+			AND created_at > NOW() - INTERVAL '1 day' * $3
+		GROUP BY status
+	`, orgID, controlID, EffectivenessTestWindowDays)
+	if err != nil {
+		return in, fmt.Errorf("query test results: %w", err)
+	}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err == nil {
+			in.TestResultCounts[status] = count
+		}
+	}
+	rows.Close()
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT ea.expires_at, lv.verdict
+		FROM evidence_links el
+		JOIN evidence_artifacts ea ON ea.id = el.artifact_id
+		LEFT JOIN LATERAL (
+			SELECT ee.verdict FROM evidence_evaluations ee
+			WHERE ee.artifact_id = ea.id
+			ORDER BY ee.created_at DESC LIMIT 1
+		) lv ON TRUE
+		WHERE el.org_id = $1 AND el.control_id = $2
+			AND ea.is_current = TRUE AND ea.status NOT IN ('rejected', 'superseded')
+	`, orgID, controlID)
+	if err != nil {
+		return in, fmt.Errorf("query linked evidence: %w", err)
+	}
+	for rows.Next() {
+		var expiresAt *time.Time
+		var verdict *string
+		if err := rows.Scan(&expiresAt, &verdict); err != nil {
+			continue
+		}
+		in.EvidenceFreshness = append(in.EvidenceFreshness, models.EvidenceFreshnessStatus(expiresAt, now))
+		if verdict != nil {
+			in.EvaluationVerdicts = append(in.EvaluationVerdicts, *verdict)
+		}
+	}
+	rows.Close()
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT severity FROM audit_findings
+		WHERE org_id = $1 AND control_id = $2
+			AND status NOT IN ('verified', 'risk_accepted', 'closed')
+	`, orgID, controlID)
+	if err != nil {
+		return in, fmt.Errorf("query audit findings: %w", err)
+	}
+	for rows.Next() {
+		var sev string
+		if err := rows.Scan(&sev); err == nil {
+			in.OpenFindingSeverities = append(in.OpenFindingSeverities, sev)
+		}
+	}
+	rows.Close()
+
+	err = db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status NOT IN ('resolved', 'closed')),
+			COUNT(*) FILTER (WHERE status IN ('resolved', 'closed'))
+		FROM alerts
+		WHERE org_id = $1 AND control_id = $2 AND sla_breached = TRUE
+			AND created_at > NOW() - INTERVAL '1 day' * $3
+	`, orgID, controlID, EffectivenessAlertWindowDays).Scan(&in.OpenSLABreaches, &in.ResolvedSLABreaches)
+	if err != nil {
+		return in, fmt.Errorf("query alert SLA breaches: %w", err)
+	}
+
+	return in, nil
+}
+
+// RecalculateControlEffectiveness computes a control's effectiveness, appends
+// it to control_effectiveness_history and caches it on the control row in the
+// same transaction.
+// triggerType is "scheduled" or "manual"; calculatedBy is nil for the worker.
+func RecalculateControlEffectiveness(ctx context.Context, db *sql.DB, orgID, controlID, triggerType string, calculatedBy *string) (*ControlEffectiveness, error) {
+	in, err := GatherEffectivenessInputs(ctx, db, orgID, controlID)
+	if err != nil {
+		return nil, err
+	}
+	result := ScoreControlEffectiveness(in)
+	result.ControlID = controlID
+
+	componentsJSON, err := json.Marshal(result.Components)
+	if err != nil {
+		return nil, fmt.Errorf("marshal components: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO control_effectiveness_history (id, org_id, control_id, score, rating,
+			components, trigger_type, calculated_by, calculated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, uuid.New().String(), orgID, controlID, result.Score, result.Rating,
+		string(componentsJSON), triggerType, calculatedBy, result.CalculatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("insert effectiveness history: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE controls SET effectiveness_score = $1, effectiveness_rating = $2,
+			effectiveness_calculated_at = $3
+		WHERE id = $4 AND org_id = $5
+	`, result.Score, result.Rating, result.CalculatedAt, controlID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("update control effectiveness: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return &result, nil
+}
+
+func averageItemScore(items []string, scores map[string]float64) (float64, bool) {
+	if len(items) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, item := range items {
+		sum += scores[item]
+	}
+	return sum / float64(len(items)), true
+}
+
+func countOf(items []string, value string) int {
+	n := 0
+	for _, item := range items {
+		if item == value {
+			n++
+		}
+	}
+	return n
+}
+
+func roundTo(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectEffectivenessInputs(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT status, COUNT").WillReturnRows(
+		sqlmock.NewRows([]string{"status", "count"}).AddRow("pass", 10))
+	mock.ExpectQuery("SELECT ea.expires_at, lv.verdict").WillReturnRows(
+		sqlmock.NewRows([]string{"expires_at", "verdict"}))
+	mock.ExpectQuery("SELECT severity FROM audit_findings").WillReturnRows(
+		sqlmock.NewRows([]string{"severity"}))
+	mock.ExpectQuery("FROM alerts").WillReturnRows(
+		sqlmock.NewRows([]string{"open", "resolved"}).AddRow(0, 0))
+}
+
+func TestRecalculateControlEffectiveness_WritesInOneTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	expectEffectivenessInputs(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO control_effectiveness_history").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE controls SET effectiveness_score").
+		WithArgs(100.0, "effective", sqlmock.AnyArg(), "c001", "org1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := RecalculateControlEffectiveness(context.Background(), db, "org1", "c001", "manual", nil)
+	require.NoError(t, err)
+	require.NotNil(t, result.Score)
+	assert.Equal(t, 100.0, *result.Score)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecalculateControlEffectiveness_UpdateFailureRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	expectEffectivenessInputs(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO control_effectiveness_history").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE controls SET effectiveness_score").WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	_, err = RecalculateControlEffectiveness(context.Background(), db, "org1", "c001", "manual", nil)
+	assert.ErrorContains(t, err, "update control effectiveness")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
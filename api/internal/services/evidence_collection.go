@@ -0,0 +1,507 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/lib/pq"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Collection source limits.
+const (
+	DefaultCollectionTimeoutSeconds = 30
+	MaxCollectionTimeoutSeconds     = 120
+	DefaultS3ListingMaxKeys         = 1000
+	MaxS3ListingMaxKeys             = 10000
+)
+
+// RedactedSecret replaces secret values when source configs are returned to clients.
+const RedactedSecret = "********"
+
+// ErrCollectionJobNotFound is returned when a collection job does not exist in the org.
+var ErrCollectionJobNotFound = errors.New("evidence collection job not found")
+
+// HTTPJSONSourceConfig fetches a JSON document with a GET request.
+type HTTPJSONSourceConfig struct {
+	URL            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+}
+
+// S3ListingSourceConfig lists objects in an S3-compatible bucket.
+type S3ListingSourceConfig struct {
+	Endpoint  string `json:"endpoint"`
+	Bucket    string `json:"bucket"`
+	Prefix    string `json:"prefix,omitempty"`
+	Region    string `json:"region,omitempty"`
+	AccessKey string `json:"access_key,omitempty"`
+	SecretKey string `json:"secret_key,omitempty"`
+	UseSSL    bool   `json:"use_ssl"`
+	MaxKeys   int    `json:"max_keys,omitempty"`
+}
+
+// AgentUploadSourceConfig describes command output an agent pushes for a job.
+// When Command is set, uploads for a different command are rejected.
+type AgentUploadSourceConfig struct {
+	Command string `json:"command,omitempty"`
+	Host    string `json:"host,omitempty"`
+}
+
+// CollectedContent is the payload produced by a collection source.
+type CollectedContent struct {
+	FileName string
+	MIMEType string
+	Data     []byte
+	Metadata map[string]interface{}
+}
+
+// ParseCollectionSourceConfig decodes and validates a source config for the given source type.
+func ParseCollectionSourceConfig(sourceType string, raw []byte) (interface{}, error) {
+	if len(raw) == 0 {
+		raw = []byte("{}")
+	}
+	switch sourceType {
+	case models.CollectionSourceHTTPJSON:
+		var cfg HTTPJSONSourceConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid http_json source_config")
+		}
+		u, err := url.Parse(cfg.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("source_config.url must be an absolute http(s) URL")
+		}
+		if cfg.TimeoutSeconds < 0 || cfg.TimeoutSeconds > MaxCollectionTimeoutSeconds {
+			return nil, fmt.Errorf("source_config.timeout_seconds must be between 1 and %d", MaxCollectionTimeoutSeconds)
+		}
+		return cfg, nil
+	case models.CollectionSourceS3Listing:
+		var cfg S3ListingSourceConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid s3_listing source_config")
+		}
+		if cfg.Endpoint == "" || strings.Contains(cfg.Endpoint, "/") {
+			return nil, fmt.Errorf("source_config.endpoint must be a host[:port]")
+		}
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("source_config.bucket is required")
+		}
+		if (cfg.AccessKey == "") != (cfg.SecretKey == "") {
+			return nil, fmt.Errorf("source_config.access_key and secret_key must be set together")
+		}
+		if cfg.MaxKeys < 0 || cfg.MaxKeys > MaxS3ListingMaxKeys {
+			return nil, fmt.Errorf("source_config.max_keys must be between 1 and %d", MaxS3ListingMaxKeys)
+		}
+		return cfg, nil
+	case models.CollectionSourceAgentUpload:
+		var cfg AgentUploadSourceConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid agent_upload source_config")
+		}
+		return cfg, nil
+	}
+	return nil, fmt.Errorf("unknown source type %q", sourceType)
+}
+
+// RedactSourceConfig returns a source config with header values and secret keys masked.
+func RedactSourceConfig(raw []byte) map[string]interface{} {
+	cfg := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return cfg
+	}
+	if headers, ok := cfg["headers"].(map[string]interface{}); ok {
+		for k := range headers {
+			headers[k] = RedactedSecret
+		}
+	}
+	if _, ok := cfg["secret_key"]; ok {
+		cfg["secret_key"] = RedactedSecret
+	}
+	return cfg
+}
+
+// cgnatRange is the carrier-grade NAT shared address space (RFC 6598).
+var cgnatRange = &net.IPNet{IP: net.IPv4(100, 64, 0, 0).To4(), Mask: net.CIDRMask(10, 32)}
+
+// guardedDialer refuses loopback, link-local, private (RFC 1918 and ULA), carrier-grade NAT
+// and unspecified addresses so collection sources cannot be pointed at the API host, the
+// internal network or cloud metadata endpoints. The check runs on the resolved address at
+// connect time, so DNS names that resolve to internal addresses are refused too.
+func guardedDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() ||
+				ip.IsPrivate() || cgnatRange.Contains(ip) {
+				return fmt.Errorf("collection source address %s is not allowed", host)
+			}
+			return nil
+		},
+	}
+}
+
+// guardedTransport connects directly; going through a proxy would move the address check from
+// the target to the proxy.
+func guardedTransport() *http.Transport {
+	return &http.Transport{
+		DialContext:         guardedDialer().DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// CollectHTTPJSON fetches the configured URL and returns the JSON response body.
+func CollectHTTPJSON(ctx context.Context, cfg HTTPJSONSourceConfig) (*CollectedContent, error) {
+	timeout := cfg.TimeoutSeconds
+	if timeout == 0 {
+		timeout = DefaultCollectionTimeoutSeconds
+	}
+	client := &http.Client{Transport: guardedTransport(), Timeout: time.Duration(timeout) * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("endpoint returned HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, models.MaxCollectedContentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if len(body) > models.MaxCollectedContentSize {
+		return nil, fmt.Errorf("response exceeds %d bytes", models.MaxCollectedContentSize)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("response is not valid JSON")
+	}
+
+	return &CollectedContent{
+		FileName: "response.json",
+		MIMEType: "application/json",
+		Data:     body,
+		Metadata: map[string]interface{}{
+			"source_url":  cfg.URL,
+			"http_status": resp.StatusCode,
+		},
+	}, nil
+}
+
+// CollectS3Listing lists the configured bucket and returns the listing as JSON.
+func CollectS3Listing(ctx context.Context, cfg S3ListingSourceConfig) (*CollectedContent, error) {
+	opts := &minio.Options{Secure: cfg.UseSSL, Region: cfg.Region, Transport: guardedTransport()}
+	if cfg.AccessKey != "" {
+		opts.Creds = credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, "")
+	} else {
+		opts.Creds = credentials.NewStaticV4("", "", "")
+	}
+	client, err := minio.New(cfg.Endpoint, opts)
+	if err != nil {
+		return nil, fmt.Errorf("create S3 client: %w", err)
+	}
+
+	maxKeys := cfg.MaxKeys
+	if maxKeys == 0 {
+		maxKeys = DefaultS3ListingMaxKeys
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, MaxCollectionTimeoutSeconds*time.Second)
+	defer cancel()
+
+	type listedObject struct {
+		Key          string    `json:"key"`
+		Size         int64     `json:"size"`
+		LastModified time.Time `json:"last_modified"`
+		ETag         string    `json:"etag"`
+		StorageClass string    `json:"storage_class,omitempty"`
+	}
+	objects := []listedObject{}
+	var totalSize int64
+	truncated := false
+	for obj := range client.ListObjects(listCtx, cfg.Bucket, minio.ListObjectsOptions{Prefix: cfg.Prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("list bucket: %w", obj.Err)
+		}
+		if len(objects) >= maxKeys {
+			truncated = true
+			break
+		}
+		objects = append(objects, listedObject{
+			Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified,
+			ETag: obj.ETag, StorageClass: obj.StorageClass,
+		})
+		totalSize += obj.Size
+	}
+
+	data, _ := json.MarshalIndent(map[string]interface{}{
+		"endpoint":     cfg.Endpoint,
+		"bucket":       cfg.Bucket,
+		"prefix":       cfg.Prefix,
+		"listed_at":    time.Now().UTC(),
+		"object_count": len(objects),
+		"total_size":   totalSize,
+		"truncated":    truncated,
+		"objects":      objects,
+	}, "", "  ")
+
+	return &CollectedContent{
+		FileName: "bucket-listing.json",
+		MIMEType: "application/json",
+		Data:     data,
+		Metadata: map[string]interface{}{
+			"bucket":       cfg.Bucket,
+			"prefix":       cfg.Prefix,
+			"object_count": len(objects),
+			"truncated":    truncated,
+		},
+	}, nil
+}
+
+// AgentOutputContent wraps command output uploaded by an agent as a JSON evidence record.
+func AgentOutputContent(cfg AgentUploadSourceConfig, out models.AgentOutputRequest) (*CollectedContent, error) {
+	if strings.TrimSpace(out.Output) == "" {
+		return nil, fmt.Errorf("output is empty")
+	}
+	if len(out.Output) > models.MaxCollectedContentSize {
+		return nil, fmt.Errorf("output exceeds %d bytes", models.MaxCollectedContentSize)
+	}
+	command := cfg.Command
+	if out.Command != nil {
+		if cfg.Command != "" && *out.Command != cfg.Command {
+			return nil, fmt.Errorf("command does not match the job's configured command")
+		}
+		command = *out.Command
+	}
+	collectedAt := time.Now().UTC()
+	if out.CollectedAt != nil {
+		t, err := time.Parse(time.RFC3339, *out.CollectedAt)
+		if err != nil {
+			return nil, fmt.Errorf("collected_at must be RFC 3339")
+		}
+		if t.After(time.Now().Add(5 * time.Minute)) {
+			return nil, fmt.Errorf("collected_at cannot be in the future")
+		}
+		collectedAt = t.UTC()
+	}
+	host := cfg.Host
+	if out.Host != nil {
+		host = *out.Host
+	}
+
+	data, _ := json.MarshalIndent(map[string]interface{}{
+		"command":      command,
+		"exit_code":    out.ExitCode,
+		"host":         host,
+		"collected_at": collectedAt,
+		"output":       out.Output,
+	}, "", "  ")
+
+	return &CollectedContent{
+		FileName: "command-output.json",
+		MIMEType: "application/json",
+		Data:     data,
+		Metadata: map[string]interface{}{
+			"command":   command,
+			"exit_code": out.ExitCode,
+			"host":      host,
+		},
+	}, nil
+}
+
+// CollectForJob produces content for a pull source. Agent uploads use AgentOutputContent.
+func CollectForJob(ctx context.Context, sourceType string, rawConfig []byte) (*CollectedContent, error) {
+	cfg, err := ParseCollectionSourceConfig(sourceType, rawConfig)
+	if err != nil {
+		return nil, err
+	}
+	switch c := cfg.(type) {
+	case HTTPJSONSourceConfig:
+		return CollectHTTPJSON(ctx, c)
+	case S3ListingSourceConfig:
+		return CollectS3Listing(ctx, c)
+	}
+	return nil, fmt.Errorf("source type %s is not pulled by the server", sourceType)
+}
+
+// RunEvidenceCollectionJob executes one run of a collection job: it collects content (or wraps
+// the agent upload), stores it as the next version of the job's artifact, links the job's
+// controls, and records the run. Collection failures are recorded as failed runs rather than
+// returned as errors; the returned error is only for lookups and bookkeeping.
+func RunEvidenceCollectionJob(ctx context.Context, db *sql.DB, store ObjectStore, orgID, jobID, trigger string,
+	triggeredBy *string, agent *models.AgentOutputRequest) (*models.EvidenceCollectionRun, error) {
+
+	var (
+		job        models.EvidenceCollectionJob
+		controlIDs pq.StringArray
+		tags       pq.StringArray
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT id, org_id, name, source_type, source_config::text, artifact_title, evidence_type,
+			freshness_period_days, tags, control_ids::text[], current_artifact_id, created_by
+		FROM evidence_collection_jobs WHERE id = $1 AND org_id = $2
+	`, jobID, orgID).Scan(&job.ID, &job.OrgID, &job.Name, &job.SourceType, &job.SourceConfig,
+		&job.ArtifactTitle, &job.EvidenceType, &job.FreshnessPeriodDays, &tags, &controlIDs,
+		&job.CurrentArtifactID, &job.CreatedBy)
+	if err == sql.ErrNoRows {
+		return nil, ErrCollectionJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load collection job: %w", err)
+	}
+
+	run := &models.EvidenceCollectionRun{
+		ID:          uuid.New().String(),
+		JobID:       job.ID,
+		TriggerType: trigger,
+		TriggeredBy: triggeredBy,
+		StartedAt:   time.Now(),
+	}
+
+	var content *CollectedContent
+	var collectErr error
+	if job.SourceType == models.CollectionSourceAgentUpload {
+		if agent == nil {
+			collectErr = fmt.Errorf("agent_upload jobs only run when an agent uploads output")
+		} else {
+			var cfg interface{}
+			cfg, collectErr = ParseCollectionSourceConfig(job.SourceType, []byte(job.SourceConfig))
+			if collectErr == nil {
+				content, collectErr = AgentOutputContent(cfg.(AgentUploadSourceConfig), *agent)
+			}
+		}
+	} else {
+		content, collectErr = CollectForJob(ctx, job.SourceType, []byte(job.SourceConfig))
+	}
+
+	var stored *StoredEvidenceVersion
+	if collectErr == nil {
+		current := ""
+		if job.CurrentArtifactID != nil {
+			current = *job.CurrentArtifactID
+		}
+		uploadedBy := triggeredBy
+		if uploadedBy == nil {
+			uploadedBy = job.CreatedBy
+		}
+		metadata := content.Metadata
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+		metadata["collection_job_id"] = job.ID
+		metadata["collection_run_id"] = run.ID
+		sourceSystem := "collection_job:" + job.SourceType
+		stored, collectErr = StoreEvidenceVersion(ctx, db, store, EvidenceVersionSpec{
+			OrgID:               job.OrgID,
+			CurrentArtifactID:   current,
+			Title:               job.ArtifactTitle,
+			Description:         strPtr(fmt.Sprintf("Collected by evidence collection job %q", job.Name)),
+			EvidenceType:        job.EvidenceType,
+			CollectionMethod:    models.CollectionMethodForSource(job.SourceType),
+			SourceSystem:        &sourceSystem,
+			FreshnessPeriodDays: job.FreshnessPeriodDays,
+			Tags:                tags,
+			Metadata:            metadata,
+			UploadedBy:          uploadedBy,
+			ControlIDs:          controlIDs,
+			CollectionDate:      time.Now().UTC(),
+			FileName:            content.FileName,
+			MIMEType:            content.MIMEType,
+			Data:                content.Data,
+		})
+	}
+
+	now := time.Now()
+	run.CompletedAt = &now
+	if collectErr != nil {
+		run.Status = models.CollectionRunFailed
+		msg := collectErr.Error()
+		run.ErrorMessage = &msg
+	} else {
+		run.Status = models.CollectionRunSuccess
+		run.ArtifactID = &stored.ID
+		run.ArtifactVersion = &stored.Version
+		run.FileSize = &stored.FileSize
+		run.ChecksumSHA256 = &stored.ChecksumSHA256
+	}
+
+	if err := recordCollectionRun(ctx, db, job.OrgID, run); err != nil {
+		return run, err
+	}
+	return run, nil
+}
+
+// RecordMissedCollection records a missed run for an agent job that received no upload in time.
+func RecordMissedCollection(ctx context.Context, db *sql.DB, orgID, jobID string) error {
+	now := time.Now()
+	msg := "No agent upload received within the schedule interval"
+	return recordCollectionRun(ctx, db, orgID, &models.EvidenceCollectionRun{
+		ID:           uuid.New().String(),
+		JobID:        jobID,
+		Status:       models.CollectionRunMissed,
+		TriggerType:  models.CollectionTriggerScheduled,
+		ErrorMessage: &msg,
+		StartedAt:    now,
+		CompletedAt:  &now,
+	})
+}
+
+// recordCollectionRun inserts the run and rolls the job's schedule forward.
+func recordCollectionRun(ctx context.Context, db *sql.DB, orgID string, run *models.EvidenceCollectionRun) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO evidence_collection_runs (id, org_id, job_id, status, trigger_type,
+			artifact_id, artifact_version, file_size, checksum_sha256, error_message,
+			triggered_by, started_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, run.ID, orgID, run.JobID, run.Status, run.TriggerType,
+		run.ArtifactID, run.ArtifactVersion, run.FileSize, run.ChecksumSHA256, run.ErrorMessage,
+		run.TriggeredBy, run.StartedAt, run.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("record collection run: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		UPDATE evidence_collection_jobs SET
+			last_run_at = $1,
+			last_run_status = $2,
+			consecutive_failures = CASE WHEN $2 = 'success' THEN 0 ELSE consecutive_failures + 1 END,
+			current_artifact_id = COALESCE($3, current_artifact_id),
+			next_run_at = NOW() + make_interval(mins => schedule_interval_min)
+		WHERE id = $4
+	`, run.StartedAt, run.Status, run.ArtifactID, run.JobID)
+	if err != nil {
+		return fmt.Errorf("update collection job: %w", err)
+	}
+	return nil
+}
+
+func strPtr(s string) *string {
+	return &s
+}
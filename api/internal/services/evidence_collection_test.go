@@ -0,0 +1,33 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuardedDialer_RefusesInternalAddresses(t *testing.T) {
+	control := guardedDialer().Control
+	for _, addr := range []string{
+		"127.0.0.1:80",
+		"169.254.169.254:80",
+		"0.0.0.0:80",
+		"10.1.2.3:443",
+		"172.16.0.1:443",
+		"192.168.1.1:443",
+		"100.64.0.1:443",
+		"100.127.255.254:443",
+		"[::1]:443",
+		"[fd00::1]:443",
+		"[fe80::1]:443",
+	} {
+		assert.Error(t, control("tcp", addr, nil), addr)
+	}
+	for _, addr := range []string{"93.184.216.34:443", "100.128.0.1:443", "[2606:4700::1111]:443"} {
+		assert.NoError(t, control("tcp", addr, nil), addr)
+	}
+}
+
+func TestGuardedTransport_NoProxy(t *testing.T) {
+	assert.Nil(t, guardedTransport().Proxy)
+}
@@ -0,0 +1,692 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// ErrExtractionUnsupported is returned for file types text cannot be extracted from.
+var ErrExtractionUnsupported = errors.New("text extraction is not supported for this file type")
+
+// maxInflatedSize bounds how much a single compressed part (zip entry or PDF stream) may expand to.
+const maxInflatedSize = 64 << 20
+
+// ExtractEvidenceText returns the searchable text of an evidence file and the extractor used.
+func ExtractEvidenceText(mimeType string, data []byte) (string, string, error) {
+	var (
+		text      string
+		extractor string
+		err       error
+	)
+	switch mimeType {
+	case "application/pdf":
+		extractor = "pdf"
+		text, err = extractPDFText(data)
+	case "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		extractor = "docx"
+		text, err = extractDOCXText(data)
+	case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		extractor = "xlsx"
+		text, err = extractXLSXText(data)
+	case "text/csv":
+		extractor = "csv"
+		text = extractCSVText(data)
+	case "application/json":
+		extractor = "json"
+		text = extractJSONText(data)
+	case "application/xml":
+		extractor = "xml"
+		text = extractXMLText(bytes.NewReader(data))
+	case "text/plain":
+		extractor = "text"
+		text = string(data)
+	default:
+		return "", "", ErrExtractionUnsupported
+	}
+	if err != nil {
+		return "", extractor, err
+	}
+	return normalizeExtractedText(text), extractor, nil
+}
+
+// normalizeExtractedText makes text safe to store in Postgres: valid UTF-8, no NUL bytes,
+// and no runs of blank lines.
+func normalizeExtractedText(s string) string {
+	s = strings.ToValidUTF8(s, "")
+	s = strings.ReplaceAll(s, "\x00", "")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	for _, l := range lines {
+		l = strings.TrimRightFunc(l, unicode.IsSpace)
+		if l == "" && (len(out) == 0 || out[len(out)-1] == "") {
+			continue
+		}
+		out = append(out, l)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// truncateText cuts s to at most max bytes without splitting a UTF-8 sequence.
+func truncateText(s string, max int) (string, bool) {
+	if len(s) <= max {
+		return s, false
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut], true
+}
+
+// --- Office Open XML ---
+
+func openZipEntry(zr *zip.Reader, name string) (io.ReadCloser, error) {
+	for _, f := range zr.File {
+		if f.Name == name {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			return struct {
+				io.Reader
+				io.Closer
+			}{io.LimitReader(rc, maxInflatedSize), rc}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s not found", name)
+}
+
+// extractDOCXText reads paragraph text from word/document.xml.
+func extractDOCXText(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("open docx: %w", err)
+	}
+	rc, err := openZipEntry(zr, "word/document.xml")
+	if err != nil {
+		return "", fmt.Errorf("open docx: %w", err)
+	}
+	defer rc.Close()
+
+	var b strings.Builder
+	dec := xml.NewDecoder(rc)
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("parse docx: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				b.WriteByte('\t')
+			case "br", "cr":
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				b.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// extractXLSXText writes every worksheet as tab-separated rows, resolving shared strings.
+func extractXLSXText(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("open xlsx: %w", err)
+	}
+
+	var shared []string
+	if rc, err := openZipEntry(zr, "xl/sharedStrings.xml"); err == nil {
+		shared, err = readXLSXSharedStrings(rc)
+		rc.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var sheets []string
+	for _, f := range zr.File {
+		if strings.HasPrefix(f.Name, "xl/worksheets/") && strings.HasSuffix(f.Name, ".xml") {
+			sheets = append(sheets, f.Name)
+		}
+	}
+	sort.Strings(sheets)
+
+	var b strings.Builder
+	for _, name := range sheets {
+		rc, err := openZipEntry(zr, name)
+		if err != nil {
+			return "", fmt.Errorf("open xlsx: %w", err)
+		}
+		err = writeXLSXSheet(&b, rc, shared)
+		rc.Close()
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+func readXLSXSharedStrings(r io.Reader) ([]string, error) {
+	var (
+		shared []string
+		cur    strings.Builder
+		inText bool
+	)
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return shared, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse xlsx shared strings: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "si" {
+				cur.Reset()
+			}
+			inText = t.Name.Local == "t"
+		case xml.EndElement:
+			if t.Name.Local == "si" {
+				shared = append(shared, cur.String())
+			}
+			if t.Name.Local == "t" {
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				cur.Write(t)
+			}
+		}
+	}
+}
+
+func writeXLSXSheet(b *strings.Builder, r io.Reader, shared []string) error {
+	var (
+		cellType string
+		value    strings.Builder
+		inValue  bool
+		rowCells []string
+	)
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parse xlsx sheet: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				rowCells = rowCells[:0]
+			case "c":
+				cellType = ""
+				value.Reset()
+				for _, a := range t.Attr {
+					if a.Name.Local == "t" {
+						cellType = a.Value
+					}
+				}
+			case "v", "t":
+				inValue = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "v", "t":
+				inValue = false
+			case "c":
+				v := value.String()
+				if cellType == "s" {
+					if i, err := strconv.Atoi(v); err == nil && i >= 0 && i < len(shared) {
+						v = shared[i]
+					}
+				}
+				if v != "" {
+					rowCells = append(rowCells, v)
+				}
+			case "row":
+				if len(rowCells) > 0 {
+					b.WriteString(strings.Join(rowCells, "\t"))
+					b.WriteByte('\n')
+				}
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		}
+	}
+}
+
+// --- Structured text ---
+
+func extractCSVText(data []byte) string {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return string(data)
+	}
+	var b strings.Builder
+	for _, rec := range records {
+		b.WriteString(strings.Join(rec, "\t"))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// extractJSONText flattens JSON into "path: value" lines so keys are searchable alongside values.
+func extractJSONText(data []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return string(data)
+	}
+	var b strings.Builder
+	writeJSONLeaves(&b, "", v)
+	return b.String()
+}
+
+func writeJSONLeaves(b *strings.Builder, path string, v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			writeJSONLeaves(b, p, t[k])
+		}
+	case []interface{}:
+		for i, item := range t {
+			writeJSONLeaves(b, fmt.Sprintf("%s[%d]", path, i), item)
+		}
+	case nil:
+		fmt.Fprintf(b, "%s: null\n", path)
+	default:
+		fmt.Fprintf(b, "%s: %v\n", path, t)
+	}
+}
+
+func extractXMLText(r io.Reader) string {
+	var b strings.Builder
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		if cd, ok := tok.(xml.CharData); ok {
+			if s := strings.TrimSpace(string(cd)); s != "" {
+				b.WriteString(s)
+				b.WriteByte('\n')
+			}
+		}
+	}
+	return b.String()
+}
+
+// --- PDF ---
+
+// extractPDFText pulls text drawn by Tj/TJ operators out of uncompressed and Flate-compressed
+// content streams. It covers PDFs produced by office suites and report generators with simple
+// fonts; text in embedded images or CID-keyed fonts without a usable encoding is not recovered.
+func extractPDFText(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \r\n\t"), []byte("%PDF-")) {
+		return "", fmt.Errorf("not a PDF file")
+	}
+	var b strings.Builder
+	rest := data
+	for {
+		i := bytes.Index(rest, []byte("stream"))
+		if i < 0 {
+			break
+		}
+		if i >= 3 && bytes.Equal(rest[i-3:i], []byte("end")) {
+			rest = rest[i+len("stream"):]
+			continue
+		}
+		dict := rest[:i]
+		if k := bytes.LastIndex(dict, []byte(" obj")); k >= 0 {
+			dict = dict[k:]
+		}
+
+		start := i + len("stream")
+		if start < len(rest) && rest[start] == '\r' {
+			start++
+		}
+		if start < len(rest) && rest[start] == '\n' {
+			start++
+		}
+		end := bytes.Index(rest[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		raw := rest[start : start+end]
+		rest = rest[start+end+len("endstream"):]
+
+		if skipPDFStream(dict) {
+			continue
+		}
+		content := raw
+		if bytes.Contains(dict, []byte("/FlateDecode")) {
+			zr, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			content, _ = io.ReadAll(io.LimitReader(zr, maxInflatedSize))
+			zr.Close()
+		} else if bytes.Contains(dict, []byte("/Filter")) {
+			continue
+		}
+		writePDFContentText(&b, content)
+	}
+	return b.String(), nil
+}
+
+// pdfNonContentStream matches dictionary entries of streams that are not page content:
+// images, object and xref streams, XMP metadata and embedded font programs.
+var pdfNonContentStream = regexp.MustCompile(`/(?:Subtype\s*/(?:Image|Type1C|CIDFontType0C|OpenType)|Type\s*/(?:ObjStm|XRef|Metadata)|Length[123])\b`)
+
+// skipPDFStream reports whether a stream's dictionary marks it as something other than page content.
+func skipPDFStream(dict []byte) bool {
+	return pdfNonContentStream.Match(dict)
+}
+
+// writePDFContentText interprets the text operators of a content stream.
+func writePDFContentText(b *strings.Builder, content []byte) {
+	var pending []string
+	lastNewline := true
+	emit := func(s string) {
+		if s == "" {
+			return
+		}
+		b.WriteString(s)
+		lastNewline = false
+	}
+	newline := func() {
+		if !lastNewline {
+			b.WriteByte('\n')
+			lastNewline = true
+		}
+	}
+
+	for i := 0; i < len(content); {
+		ch := content[i]
+		switch {
+		case ch == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case ch == '(':
+			s, n := readPDFLiteralString(content[i:])
+			pending = append(pending, s)
+			i += n
+		case ch == '<' && i+1 < len(content) && content[i+1] != '<':
+			end := bytes.IndexByte(content[i:], '>')
+			if end < 0 {
+				return
+			}
+			pending = append(pending, decodePDFHexString(content[i+1:i+end]))
+			i += end + 1
+		case ch == '-' || ch == '.' || (ch >= '0' && ch <= '9'):
+			j := i + 1
+			for j < len(content) && (content[j] == '.' || (content[j] >= '0' && content[j] <= '9')) {
+				j++
+			}
+			// Large negative kerning inside a TJ array is how generators encode word spacing.
+			if n, err := strconv.ParseFloat(string(content[i:j]), 64); err == nil && n < -200 && len(pending) > 0 {
+				pending = append(pending, " ")
+			}
+			i = j
+		case (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '\'' || ch == '"' || ch == '*':
+			j := i + 1
+			for j < len(content) && ((content[j] >= 'a' && content[j] <= 'z') || (content[j] >= 'A' && content[j] <= 'Z') || content[j] == '*') {
+				j++
+			}
+			switch string(content[i:j]) {
+			case "Tj", "TJ":
+				emit(strings.Join(pending, ""))
+			case "'", "\"":
+				newline()
+				emit(strings.Join(pending, ""))
+			case "Td", "TD", "T*", "ET", "Tm":
+				newline()
+			}
+			pending = pending[:0]
+			i = j
+		default:
+			i++
+		}
+	}
+	newline()
+}
+
+// readPDFLiteralString decodes a (...) string starting at s[0] and returns it with the bytes consumed.
+func readPDFLiteralString(s []byte) (string, int) {
+	var out []byte
+	depth := 0
+	i := 0
+	for i < len(s) {
+		ch := s[i]
+		switch {
+		case ch == '\\' && i+1 < len(s):
+			i++
+			switch e := s[i]; e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					v := 0
+					k := 0
+					for k < 3 && i < len(s) && s[i] >= '0' && s[i] <= '7' {
+						v = v*8 + int(s[i]-'0')
+						i++
+						k++
+					}
+					out = append(out, byte(v))
+					continue
+				}
+				out = append(out, e)
+			}
+			i++
+		case ch == '(':
+			if depth > 0 {
+				out = append(out, ch)
+			}
+			depth++
+			i++
+		case ch == ')':
+			depth--
+			i++
+			if depth == 0 {
+				return decodePDFTextBytes(out), i
+			}
+			out = append(out, ch)
+		default:
+			out = append(out, ch)
+			i++
+		}
+	}
+	return decodePDFTextBytes(out), i
+}
+
+func decodePDFHexString(h []byte) string {
+	h = bytes.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, h)
+	if len(h)%2 == 1 {
+		h = append(h, '0')
+	}
+	out := make([]byte, 0, len(h)/2)
+	for i := 0; i+1 < len(h); i += 2 {
+		v, err := strconv.ParseUint(string(h[i:i+2]), 16, 8)
+		if err != nil {
+			return ""
+		}
+		out = append(out, byte(v))
+	}
+	return decodePDFTextBytes(out)
+}
+
+// decodePDFTextBytes decodes UTF-16BE (with BOM) or single-byte text, dropping strings that are
+// mostly unprintable — glyph IDs from embedded fonts rather than characters.
+func decodePDFTextBytes(b []byte) string {
+	var s string
+	if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		u := make([]uint16, 0, len(b)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		s = string(utf16.Decode(u))
+	} else {
+		r := make([]rune, len(b))
+		for i, c := range b {
+			r[i] = rune(c)
+		}
+		s = string(r)
+	}
+	printable := 0
+	total := 0
+	for _, r := range s {
+		total++
+		if unicode.IsPrint(r) || r == '\t' || r == '\n' {
+			printable++
+		}
+	}
+	if total > 0 && printable*10 < total*8 {
+		return ""
+	}
+	return s
+}
+
+// --- Indexing ---
+
+// EvidenceExtractionItem is an artifact whose content needs to be indexed.
+type EvidenceExtractionItem struct {
+	ID        string
+	OrgID     string
+	ObjectKey string
+	MIMEType  string
+	FileSize  int64
+}
+
+func readEvidenceObject(ctx context.Context, store EvidenceContentStore, objectKey string) ([]byte, error) {
+	obj, err := store.OpenObject(ctx, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	data, err := io.ReadAll(io.LimitReader(obj, models.MaxExtractionInputSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read object: %w", err)
+	}
+	return data, nil
+}
+
+// IndexEvidenceContent extracts text from a stored artifact and saves it for content search.
+// Unsupported and unreadable files are recorded too, so they are not retried on every pass;
+// only a failure to save the result is returned as an error.
+func IndexEvidenceContent(ctx context.Context, db *sql.DB, store EvidenceContentStore, item EvidenceExtractionItem) (string, error) {
+	status := models.ExtractionExtracted
+	var (
+		text, extractor string
+		errMsg          *string
+	)
+	fail := func(s string, err error) {
+		status = s
+		m := err.Error()
+		errMsg = &m
+	}
+
+	if item.FileSize > models.MaxExtractionInputSize {
+		fail(models.ExtractionUnsupported, fmt.Errorf("file is larger than %d bytes", models.MaxExtractionInputSize))
+	} else if data, err := readEvidenceObject(ctx, store, item.ObjectKey); err != nil {
+		fail(models.ExtractionFailed, err)
+	} else {
+		text, extractor, err = ExtractEvidenceText(item.MIMEType, data)
+		switch {
+		case err == ErrExtractionUnsupported:
+			fail(models.ExtractionUnsupported, err)
+		case err != nil:
+			fail(models.ExtractionFailed, err)
+		}
+	}
+
+	contentLength := len(text)
+	text, truncated := truncateText(text, models.MaxExtractedTextSize)
+	var extractorCol *string
+	if extractor != "" {
+		extractorCol = &extractor
+	}
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO evidence_contents (artifact_id, org_id, status, extractor, content, content_length, truncated, error, extracted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		ON CONFLICT (artifact_id) DO UPDATE SET
+			status = EXCLUDED.status, extractor = EXCLUDED.extractor, content = EXCLUDED.content,
+			content_length = EXCLUDED.content_length, truncated = EXCLUDED.truncated,
+			error = EXCLUDED.error, extracted_at = NOW()
+	`, item.ID, item.OrgID, status, extractorCol, text, contentLength, truncated, errMsg)
+	if err != nil {
+		return "", fmt.Errorf("store extracted text: %w", err)
+	}
+	return status, nil
+}
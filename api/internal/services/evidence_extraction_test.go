@@ -0,0 +1,96 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		w.Write([]byte(content))
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestExtractEvidenceText_DOCX(t *testing.T) {
+	doc := buildZip(t, map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+			<w:p><w:r><w:t>Access Control Policy</w:t></w:r></w:p>
+			<w:p><w:r><w:t xml:space="preserve">MFA is </w:t></w:r><w:r><w:t>enforced for all users.</w:t></w:r></w:p>
+		</w:body></w:document>`,
+	})
+
+	text, extractor, err := ExtractEvidenceText("application/vnd.openxmlformats-officedocument.wordprocessingml.document", doc)
+	require.NoError(t, err)
+	assert.Equal(t, "docx", extractor)
+	assert.Equal(t, "Access Control Policy\nMFA is enforced for all users.", text)
+}
+
+func TestExtractEvidenceText_XLSX(t *testing.T) {
+	book := buildZip(t, map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>user</t></si><si><t>mfa_enabled</t></si><si><r><t>ali</t></r><r><t>ce</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+			<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2" t="inlineStr"><is><t>yes</t></is></c><c r="C2"><v>42</v></c></row>
+		</sheetData></worksheet>`,
+	})
+
+	text, _, err := ExtractEvidenceText("application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", book)
+	require.NoError(t, err)
+	assert.Equal(t, "user\tmfa_enabled\nalice\tyes\t42", text)
+}
+
+func TestExtractEvidenceText_JSON(t *testing.T) {
+	text, _, err := ExtractEvidenceText("application/json", []byte(`{"policy": {"mfa": {"enforced": true}}, "exempt": ["svc-backup"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "exempt[0]: svc-backup\npolicy.mfa.enforced: true", text)
+}
+
+func TestExtractEvidenceText_CSV(t *testing.T) {
+	text, _, err := ExtractEvidenceText("text/csv", []byte("user,\"last login\"\nalice,2026-10-01\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "user\tlast login\nalice\t2026-10-01", text)
+}
+
+func TestExtractEvidenceText_PDF(t *testing.T) {
+	content := "BT /F1 12 Tf 72 712 Td (Password policy) Tj 0 -14 Td [(MFA is en) -20 (forced) -300 (for admins)] TJ ET"
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write([]byte(content))
+	zw.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj << /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+	pdf.Write(compressed.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n")
+	pdf.WriteString("5 0 obj << /Length 24 >>\nstream\nBT (Page 2 \\(draft\\)) Tj ET\nendstream\nendobj\n%%EOF")
+
+	text, extractor, err := ExtractEvidenceText("application/pdf", pdf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "pdf", extractor)
+	assert.Equal(t, "Password policy\nMFA is enforced for admins\nPage 2 (draft)", text)
+}
+
+func TestExtractEvidenceText_Unsupported(t *testing.T) {
+	_, _, err := ExtractEvidenceText("image/png", []byte("\x89PNG\r\n\x1a\n"))
+	assert.Equal(t, ErrExtractionUnsupported, err)
+}
+
+func TestTruncateText_RuneBoundary(t *testing.T) {
+	s, truncated := truncateText("añb", 2)
+	assert.True(t, truncated)
+	assert.Equal(t, "a", s)
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// FreshnessSweepResult counts what one evidence freshness sweep changed.
+type FreshnessSweepResult struct {
+	Expired         int64
+	TasksClosed     int64
+	TasksOpened     int64
+	AlertsResolved  int64
+	AlertsEscalated int64
+	AlertsRaised    int64
+}
+
+// freshnessWindow matches current approved or expired evidence (aliased ea) that expires within
+// warning days ($1) or has already expired.
+const freshnessWindow = `ea.is_current = TRUE
+	AND ea.status IN ('approved', 'expired')
+	AND ea.purged_at IS NULL
+	AND ea.expires_at IS NOT NULL
+	AND ea.expires_at <= NOW() + make_interval(days => $1)`
+
+// freshnessRenewed matches evidence (aliased ea) that no longer needs renewing: a newer
+// version replaced it, or its expiry moved out of the warning window.
+const freshnessRenewed = `(ea.is_current = FALSE
+	OR ea.expires_at IS NULL
+	OR ea.expires_at > NOW() + make_interval(days => $1))`
+
+// SweepEvidenceFreshness expires approved evidence past expires_at, keeps renewal tasks and
+// freshness alerts in step with the evidence, and closes both once evidence is renewed.
+// Each step is a single statement, so a sweep is safe to run from several workers.
+func SweepEvidenceFreshness(ctx context.Context, db *sql.DB, warningDays int) (*FreshnessSweepResult, error) {
+	var r FreshnessSweepResult
+	steps := []struct {
+		name  string
+		query string
+		args  []interface{}
+		count *int64
+	}{
+		{"expire evidence", `
+			UPDATE evidence_artifacts SET status = 'expired'
+			WHERE status = 'approved' AND is_current = TRUE
+				AND expires_at IS NOT NULL AND expires_at <= NOW()
+		`, nil, &r.Expired},
+
+		// A task completes when the chain has a newer version (or the expiry was extended);
+		// it is cancelled when the evidence was removed without a replacement.
+		{"close renewal tasks", fmt.Sprintf(`
+			WITH closing AS (
+				SELECT t.id, ea.is_current AS extended,
+					(SELECT cur.id FROM evidence_artifacts cur
+						WHERE cur.is_current = TRUE AND cur.id <> ea.id
+							AND COALESCE(cur.parent_artifact_id, cur.id) = COALESCE(ea.parent_artifact_id, ea.id)
+						LIMIT 1) AS renewed_id
+				FROM evidence_renewal_tasks t
+				JOIN evidence_artifacts ea ON ea.id = t.artifact_id
+				WHERE t.status = 'open' AND %s
+			)
+			UPDATE evidence_renewal_tasks t SET
+				status = CASE WHEN c.renewed_id IS NOT NULL OR c.extended THEN 'completed' ELSE 'cancelled' END::evidence_renewal_status,
+				renewed_artifact_id = c.renewed_id,
+				completed_at = NOW()
+			FROM closing c
+			WHERE c.id = t.id
+		`, freshnessRenewed), []interface{}{warningDays}, &r.TasksClosed},
+
+		// One task per expiry date: a cancelled or completed task is not reopened unless the
+		// artifact's expiry changes.
+		{"open renewal tasks", fmt.Sprintf(`
+			INSERT INTO evidence_renewal_tasks (org_id, artifact_id, control_id, assigned_to, due_at)
+			SELECT ea.org_id, ea.id, ctl.id, COALESCE(ctl.owner_id, ea.uploaded_by), ea.expires_at
+			FROM evidence_artifacts ea
+			LEFT JOIN LATERAL (
+				SELECT c.id, c.owner_id
+				FROM evidence_links el
+				JOIN controls c ON c.id = el.control_id
+				WHERE el.artifact_id = ea.id AND el.target_type = 'control'
+				ORDER BY c.owner_id IS NULL, el.created_at
+				LIMIT 1
+			) ctl ON TRUE
+			WHERE %s
+				AND NOT EXISTS (
+					SELECT 1 FROM evidence_renewal_tasks t
+					WHERE t.artifact_id = ea.id AND (t.status = 'open' OR t.due_at = ea.expires_at)
+				)
+			ON CONFLICT DO NOTHING
+		`, freshnessWindow), []interface{}{warningDays}, &r.TasksOpened},
+
+		{"resolve alerts", fmt.Sprintf(`
+			UPDATE alerts a SET status = 'resolved', resolved_at = NOW(),
+				resolution_notes = 'Evidence renewed'
+			FROM evidence_artifacts ea
+			WHERE a.evidence_artifact_id = ea.id
+				AND a.status NOT IN ('resolved', 'closed')
+				AND %s
+		`, freshnessRenewed), []interface{}{warningDays}, &r.AlertsResolved},
+
+		{"escalate alerts", `
+			UPDATE alerts a SET severity = 'high',
+				title = 'Evidence expired: ' || ea.title,
+				sla_deadline = NULL,
+				metadata = a.metadata || '{"freshness": "expired"}'::jsonb
+			FROM evidence_artifacts ea
+			WHERE a.evidence_artifact_id = ea.id
+				AND a.metadata->>'freshness' = 'expiring_soon'
+				AND a.status NOT IN ('resolved', 'closed')
+				AND ea.is_current = TRUE AND ea.expires_at <= NOW()
+		`, nil, &r.AlertsEscalated},
+
+		// One alert per linked control, or a single alert without a control for unlinked evidence.
+		// Expiring-soon alerts use the expiry as their SLA. An alert resolved by hand is not raised
+		// again until the evidence reaches the next level.
+		{"raise alerts", fmt.Sprintf(`
+			INSERT INTO alerts (org_id, title, description, severity, status,
+				control_id, evidence_artifact_id, assigned_to, assigned_at, sla_deadline,
+				delivery_channels, tags, metadata)
+			SELECT ea.org_id,
+				CASE WHEN lvl.expired THEN 'Evidence expired: ' ELSE 'Evidence expiring soon: ' END || ea.title,
+				'Evidence "' || ea.title || '"' || COALESCE(' supporting ' || c.identifier, '') ||
+					CASE WHEN lvl.expired THEN ' expired on ' ELSE ' expires on ' END ||
+					to_char(ea.expires_at, 'YYYY-MM-DD') || '. Upload a new version to renew it.',
+				CASE WHEN lvl.expired THEN 'high' ELSE 'medium' END::alert_severity,
+				'open', c.id, ea.id,
+				COALESCE(c.owner_id, ea.uploaded_by),
+				CASE WHEN COALESCE(c.owner_id, ea.uploaded_by) IS NOT NULL THEN NOW() END,
+				CASE WHEN lvl.expired THEN NULL ELSE ea.expires_at END,
+				ARRAY['in_app']::alert_delivery_channel[],
+				ARRAY['evidence_freshness'],
+				jsonb_build_object('source', 'evidence_freshness',
+					'freshness', CASE WHEN lvl.expired THEN 'expired' ELSE 'expiring_soon' END,
+					'expires_at', ea.expires_at)
+			FROM evidence_artifacts ea
+			CROSS JOIN LATERAL (SELECT ea.expires_at <= NOW() AS expired) lvl
+			LEFT JOIN (evidence_links el JOIN controls c ON c.id = el.control_id)
+				ON el.artifact_id = ea.id AND el.target_type = 'control'
+			WHERE %s
+				AND NOT EXISTS (
+					SELECT 1 FROM alerts a
+					WHERE a.evidence_artifact_id = ea.id AND a.control_id IS NOT DISTINCT FROM c.id
+						AND (a.status NOT IN ('resolved', 'closed')
+							OR a.metadata->>'freshness' = CASE WHEN lvl.expired THEN 'expired' ELSE 'expiring_soon' END)
+				)
+		`, freshnessWindow), []interface{}{warningDays}, &r.AlertsRaised},
+	}
+
+	for _, s := range steps {
+		res, err := db.ExecContext(ctx, s.query, s.args...)
+		if err != nil {
+			return &r, fmt.Errorf("%s: %w", s.name, err)
+		}
+		*s.count, _ = res.RowsAffected()
+	}
+	return &r, nil
+}
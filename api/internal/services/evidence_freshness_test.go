@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepEvidenceFreshness_Counts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE evidence_artifacts SET status = 'expired'").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE evidence_renewal_tasks t SET").WithArgs(30).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO evidence_renewal_tasks").WithArgs(30).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("UPDATE alerts a SET status = 'resolved'").WithArgs(30).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE alerts a SET severity = 'high'").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO alerts.*LEFT JOIN \(evidence_links el JOIN controls c`).WithArgs(30).WillReturnResult(sqlmock.NewResult(0, 4))
+
+	r, err := SweepEvidenceFreshness(context.Background(), db, 30)
+	require.NoError(t, err)
+	assert.Equal(t, FreshnessSweepResult{
+		Expired: 2, TasksClosed: 1, TasksOpened: 3, AlertsResolved: 1, AlertsEscalated: 2, AlertsRaised: 4,
+	}, *r)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSweepEvidenceFreshness_StopsOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE evidence_artifacts SET status = 'expired'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE evidence_renewal_tasks t SET").WillReturnError(errors.New("deadlock detected"))
+
+	r, err := SweepEvidenceFreshness(context.Background(), db, 30)
+	assert.ErrorContains(t, err, "close renewal tasks")
+	assert.Equal(t, int64(1), r.Expired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// EvidenceContentStore is the object storage access needed by the processing pipeline.
+// *MinIOService satisfies it.
+type EvidenceContentStore interface {
+	OpenObject(ctx context.Context, objectKey string) (io.ReadCloser, error)
+	QuarantineObject(ctx context.Context, objectKey string) (string, error)
+}
+
+// ScanVerdict is a malware scanner's decision about one object.
+type ScanVerdict struct {
+	Clean     bool
+	Signature string
+}
+
+// Scanner checks object content for malware. Scan reads r to the end unless it returns an error.
+type Scanner interface {
+	Name() string
+	Scan(ctx context.Context, r io.Reader) (ScanVerdict, error)
+}
+
+// ClamAVScanner streams content to clamd using the INSTREAM command.
+type ClamAVScanner struct {
+	Network string
+	Address string
+	Timeout time.Duration
+}
+
+// clamAVChunkSize is the payload size of each INSTREAM chunk.
+const clamAVChunkSize = 64 * 1024
+
+// NewClamAVScanner parses a clamd address of the form "unix:///run/clamav/clamd.ctl",
+// "tcp://127.0.0.1:3310" or a bare socket path.
+func NewClamAVScanner(address string) (*ClamAVScanner, error) {
+	s := &ClamAVScanner{Timeout: 2 * time.Minute}
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		s.Network, s.Address = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		s.Network, s.Address = "tcp", strings.TrimPrefix(address, "tcp://")
+	case strings.HasPrefix(address, "/"):
+		s.Network, s.Address = "unix", address
+	default:
+		return nil, fmt.Errorf("unsupported clamd address %q", address)
+	}
+	if s.Address == "" {
+		return nil, fmt.Errorf("clamd address is empty")
+	}
+	return s, nil
+}
+
+// Name identifies the scan engine in processing results.
+func (s *ClamAVScanner) Name() string {
+	return "clamav"
+}
+
+// Scan sends r to clamd in length-prefixed chunks and parses the verdict.
+func (s *ClamAVScanner) Scan(ctx context.Context, r io.Reader) (ScanVerdict, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, s.Network, s.Address)
+	if err != nil {
+		return ScanVerdict{}, fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if s.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.Timeout))
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return ScanVerdict{}, fmt.Errorf("start clamd stream: %w", err)
+	}
+	buf := make([]byte, 4+clamAVChunkSize)
+	for {
+		n, rerr := r.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, err := conn.Write(buf[:4+n]); err != nil {
+				return ScanVerdict{}, fmt.Errorf("stream to clamd: %w", err)
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return ScanVerdict{}, fmt.Errorf("read content: %w", rerr)
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return ScanVerdict{}, fmt.Errorf("end clamd stream: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return ScanVerdict{}, fmt.Errorf("read clamd reply: %w", err)
+	}
+	return parseClamAVReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamAVReply interprets "stream: OK", "stream: <signature> FOUND" and "<message> ERROR".
+func parseClamAVReply(reply string) (ScanVerdict, error) {
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return ScanVerdict{Clean: true}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return ScanVerdict{Signature: strings.TrimSuffix(reply, " FOUND")}, nil
+	default:
+		return ScanVerdict{}, fmt.Errorf("clamd: %s", reply)
+	}
+}
+
+// sniffLen is the number of leading bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// SniffMIMEType detects a MIME type from the leading bytes of content, without parameters.
+func SniffMIMEType(head []byte) string {
+	detected := http.DetectContentType(head)
+	if i := strings.Index(detected, ";"); i >= 0 {
+		detected = detected[:i]
+	}
+	return strings.TrimSpace(detected)
+}
+
+// compatibleSniffedMIME lists, per declared type, sniffed types that are consistent with it.
+// Office Open XML documents are ZIP containers, and structured text has no magic bytes.
+var compatibleSniffedMIME = map[string][]string{
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":       {"application/zip"},
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": {"application/zip"},
+	"application/json": {"text/plain"},
+	"text/csv":         {"text/plain"},
+	"application/xml":  {"text/xml", "text/plain"},
+}
+
+// MIMEMatchesContent reports whether a sniffed content type is consistent with the declared one.
+// Parameters on the declared type, such as charset, are ignored.
+func MIMEMatchesContent(declared, detected string) bool {
+	if i := strings.Index(declared, ";"); i >= 0 {
+		declared = strings.TrimSpace(declared[:i])
+	}
+	if declared == detected {
+		return true
+	}
+	for _, m := range compatibleSniffedMIME[declared] {
+		if m == detected {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessingOutcome is the result of validating and scanning one stored evidence object.
+type ProcessingOutcome struct {
+	Status        string
+	DetectedMIME  string
+	ScanEngine    *string
+	Signature     *string
+	Reason        *string
+	QuarantineKey *string
+}
+
+// InspectEvidenceObject sniffs the stored object against its declared MIME type and, when a
+// scanner is configured, streams it through the scanner. Mismatched or infected objects are
+// moved to quarantine; without a scanner the outcome is not_scanned rather than passed. An
+// error means the object could not be read, scanned or moved and the inspection should be
+// retried.
+func InspectEvidenceObject(ctx context.Context, store EvidenceContentStore, scanner Scanner, objectKey, declaredMIME string) (*ProcessingOutcome, error) {
+	obj, err := store.OpenObject(ctx, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	br := bufio.NewReaderSize(obj, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("read object: %w", err)
+	}
+	out := &ProcessingOutcome{Status: models.ProcessingPassed, DetectedMIME: SniffMIMEType(head)}
+
+	if !MIMEMatchesContent(declaredMIME, out.DetectedMIME) {
+		reason := fmt.Sprintf("content looks like %s but was declared as %s", out.DetectedMIME, declaredMIME)
+		out.Reason = &reason
+		return quarantineEvidenceObject(ctx, store, objectKey, out)
+	}
+
+	if scanner == nil {
+		out.Status = models.ProcessingNotScanned
+	} else {
+		engine := scanner.Name()
+		out.ScanEngine = &engine
+		verdict, err := scanner.Scan(ctx, br)
+		if err != nil {
+			return nil, fmt.Errorf("scan object: %w", err)
+		}
+		if !verdict.Clean {
+			reason := "malware detected: " + verdict.Signature
+			out.Signature = &verdict.Signature
+			out.Reason = &reason
+			return quarantineEvidenceObject(ctx, store, objectKey, out)
+		}
+	}
+	return out, nil
+}
+
+func quarantineEvidenceObject(ctx context.Context, store EvidenceContentStore, objectKey string, out *ProcessingOutcome) (*ProcessingOutcome, error) {
+	key, err := store.QuarantineObject(ctx, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	out.Status = models.ProcessingQuarantined
+	out.QuarantineKey = &key
+	return out, nil
+}
+
+// EvidenceProcessingItem is an artifact claimed for processing.
+type EvidenceProcessingItem struct {
+	ID        string
+	OrgID     string
+	ObjectKey string
+	MIMEType  string
+	Attempts  int
+}
+
+// ProcessEvidenceArtifact inspects a claimed artifact and records the outcome. Inspection errors
+// put the artifact back in the queue until MaxProcessingAttempts is reached, after which it is
+// marked failed; either way downloads stay blocked.
+func ProcessEvidenceArtifact(ctx context.Context, db *sql.DB, store EvidenceContentStore, scanner Scanner, item EvidenceProcessingItem) (*ProcessingOutcome, error) {
+	out, inspectErr := InspectEvidenceObject(ctx, store, scanner, item.ObjectKey, item.MIMEType)
+	if inspectErr != nil {
+		status := models.ProcessingPending
+		if item.Attempts >= models.MaxProcessingAttempts {
+			status = models.ProcessingFailed
+		}
+		if _, err := db.ExecContext(ctx, `
+			UPDATE evidence_artifacts SET processing_status = $1, processing_error = $2 WHERE id = $3
+		`, status, inspectErr.Error(), item.ID); err != nil {
+			return nil, fmt.Errorf("record processing failure: %w", err)
+		}
+		return nil, inspectErr
+	}
+
+	_, err := db.ExecContext(ctx, `
+		UPDATE evidence_artifacts
+		SET processing_status = $1, detected_mime_type = $2, scan_engine = $3, scan_signature = $4,
+			processing_error = $5, quarantine_object_key = $6, processed_at = NOW()
+		WHERE id = $7
+	`, out.Status, out.DetectedMIME, out.ScanEngine, out.Signature, out.Reason, out.QuarantineKey, item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("record processing outcome: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeScanner flags content containing the EICAR test string.
+type fakeScanner struct {
+	err     error
+	scanned []byte
+}
+
+func (f *fakeScanner) Name() string { return "fake" }
+
+func (f *fakeScanner) Scan(ctx context.Context, r io.Reader) (ScanVerdict, error) {
+	if f.err != nil {
+		return ScanVerdict{}, f.err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ScanVerdict{}, err
+	}
+	f.scanned = data
+	if bytes.Contains(data, []byte("EICAR-STANDARD-ANTIVIRUS-TEST-FILE")) {
+		return ScanVerdict{Signature: "Eicar-Test-Signature"}, nil
+	}
+	return ScanVerdict{Clean: true}, nil
+}
+
+type fakeContentStore struct {
+	objects     map[string][]byte
+	quarantined []string
+}
+
+func (s *fakeContentStore) OpenObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, errors.New("object not found")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *fakeContentStore) QuarantineObject(ctx context.Context, key string) (string, error) {
+	s.quarantined = append(s.quarantined, key)
+	return "quarantine/" + key, nil
+}
+
+func TestInspectEvidenceObject_Clean(t *testing.T) {
+	content := bytes.Repeat([]byte("user,mfa_enabled\nalice,true\n"), 100)
+	store := &fakeContentStore{objects: map[string][]byte{"k": content}}
+	scanner := &fakeScanner{}
+
+	out, err := InspectEvidenceObject(context.Background(), store, scanner, "k", "text/csv")
+	require.NoError(t, err)
+	assert.Equal(t, models.ProcessingPassed, out.Status)
+	assert.Equal(t, "text/plain", out.DetectedMIME)
+	assert.Equal(t, "fake", *out.ScanEngine)
+	// The scanner sees the whole object, including the bytes read for sniffing.
+	assert.Equal(t, content, scanner.scanned)
+	assert.Empty(t, store.quarantined)
+}
+
+func TestInspectEvidenceObject_NoScanner(t *testing.T) {
+	store := &fakeContentStore{objects: map[string][]byte{"k": []byte("user,mfa_enabled\nalice,true\n")}}
+
+	out, err := InspectEvidenceObject(context.Background(), store, nil, "k", "text/csv")
+	require.NoError(t, err)
+	assert.Equal(t, models.ProcessingNotScanned, out.Status)
+	assert.Nil(t, out.ScanEngine)
+	assert.Empty(t, store.quarantined)
+}
+
+func TestInspectEvidenceObject_MIMEMismatchQuarantined(t *testing.T) {
+	// A Windows executable uploaded as a PNG screenshot.
+	store := &fakeContentStore{objects: map[string][]byte{"k": append([]byte("MZ\x90\x00\x03"), make([]byte, 64)...)}}
+
+	out, err := InspectEvidenceObject(context.Background(), store, &fakeScanner{}, "k", "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, models.ProcessingQuarantined, out.Status)
+	assert.Equal(t, "quarantine/k", *out.QuarantineKey)
+	assert.Contains(t, *out.Reason, "declared as image/png")
+	assert.Equal(t, []string{"k"}, store.quarantined)
+}
+
+func TestInspectEvidenceObject_MalwareQuarantined(t *testing.T) {
+	store := &fakeContentStore{objects: map[string][]byte{"k": []byte(eicar)}}
+
+	out, err := InspectEvidenceObject(context.Background(), store, &fakeScanner{}, "k", "text/plain")
+	require.NoError(t, err)
+	assert.Equal(t, models.ProcessingQuarantined, out.Status)
+	assert.Equal(t, "Eicar-Test-Signature", *out.Signature)
+	assert.Equal(t, []string{"k"}, store.quarantined)
+}
+
+func TestInspectEvidenceObject_ScannerError(t *testing.T) {
+	store := &fakeContentStore{objects: map[string][]byte{"k": []byte("hello")}}
+
+	_, err := InspectEvidenceObject(context.Background(), store, &fakeScanner{err: errors.New("clamd down")}, "k", "text/plain")
+	assert.Error(t, err)
+	assert.Empty(t, store.quarantined)
+}
+
+func TestMIMEMatchesContent(t *testing.T) {
+	assert.True(t, MIMEMatchesContent("application/pdf", SniffMIMEType([]byte("%PDF-1.7\n"))))
+	assert.True(t, MIMEMatchesContent("application/json", SniffMIMEType([]byte(`{"a": 1}`))))
+	assert.True(t, MIMEMatchesContent(
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		SniffMIMEType([]byte("PK\x03\x04\x14\x00\x06\x00"))))
+	assert.True(t, MIMEMatchesContent("text/html; charset=utf-8", SniffMIMEType([]byte("<!DOCTYPE html><html>"))))
+	assert.False(t, MIMEMatchesContent("application/pdf", SniffMIMEType([]byte("PK\x03\x04\x14\x00\x06\x00"))))
+	assert.False(t, MIMEMatchesContent("text/plain", SniffMIMEType([]byte("<html><script>alert(1)</script>"))))
+}
+
+// serveFakeClamd accepts one INSTREAM session and replies with the verdict for the streamed bytes.
+func serveFakeClamd(t *testing.T, l net.Listener) {
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cmd := make([]byte, len("zINSTREAM\x00"))
+	_, err = io.ReadFull(conn, cmd)
+	require.NoError(t, err)
+	require.Equal(t, "zINSTREAM\x00", string(cmd))
+
+	var data []byte
+	for {
+		var size uint32
+		require.NoError(t, binary.Read(conn, binary.BigEndian, &size))
+		if size == 0 {
+			break
+		}
+		chunk := make([]byte, size)
+		_, err := io.ReadFull(conn, chunk)
+		require.NoError(t, err)
+		data = append(data, chunk...)
+	}
+
+	reply := "stream: OK\x00"
+	if strings.Contains(string(data), "EICAR") {
+		reply = "stream: Eicar-Test-Signature FOUND\x00"
+	}
+	conn.Write([]byte(reply))
+}
+
+func TestClamAVScanner_Instream(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "clamd.sock")
+	l, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+	defer l.Close()
+
+	scanner, err := NewClamAVScanner("unix://" + sock)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() { serveFakeClamd(t, l); close(done) }()
+	verdict, err := scanner.Scan(context.Background(), strings.NewReader(eicar))
+	<-done
+	require.NoError(t, err)
+	assert.False(t, verdict.Clean)
+	assert.Equal(t, "Eicar-Test-Signature", verdict.Signature)
+
+	done = make(chan struct{})
+	go func() { serveFakeClamd(t, l); close(done) }()
+	verdict, err = scanner.Scan(context.Background(), bytes.NewReader(bytes.Repeat([]byte("a"), 3*clamAVChunkSize+7)))
+	<-done
+	require.NoError(t, err)
+	assert.True(t, verdict.Clean)
+}
+
+func TestParseClamAVReply_Error(t *testing.T) {
+	_, err := parseClamAVReply("INSTREAM size limit exceeded. ERROR")
+	assert.Error(t, err)
+}
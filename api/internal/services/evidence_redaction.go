@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// MaxRedactionPixels bounds the decoded size of a screenshot (about 50 megapixels), so a small
+// compressed file can't expand into an enormous bitmap.
+const MaxRedactionPixels = 50_000_000
+
+// captionMaxRows caps the caption strip height.
+const captionMaxRows = 12
+
+var (
+	// ErrUnsupportedScreenshot is returned for content that is not a PNG or JPEG image.
+	ErrUnsupportedScreenshot = errors.New("evidence is not a PNG or JPEG image")
+	// ErrInvalidRedactionRegion is returned when a region is empty or outside the image.
+	ErrInvalidRedactionRegion = errors.New("invalid redaction region")
+)
+
+var (
+	redactionFill = color.RGBA{0, 0, 0, 255}
+	captionBG     = color.RGBA{32, 32, 32, 255}
+	captionFG     = color.RGBA{255, 255, 255, 255}
+)
+
+// RedactedScreenshot is the re-encoded image produced by RedactScreenshot.
+type RedactedScreenshot struct {
+	Data     []byte
+	MIMEType string
+	Width    int
+	Height   int
+}
+
+// RedactScreenshot paints the regions over a PNG or JPEG screenshot and, when caption lines are
+// given, appends a caption strip below the image. The result is re-encoded in the source
+// format, which also drops embedded metadata such as EXIF.
+func RedactScreenshot(r io.Reader, regions []models.RedactionRegion, caption []string) (*RedactedScreenshot, error) {
+	data, err := io.ReadAll(io.LimitReader(r, models.MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read screenshot: %w", err)
+	}
+	if len(data) > models.MaxFileSize {
+		return nil, fmt.Errorf("screenshot exceeds %d bytes", models.MaxFileSize)
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "png" && format != "jpeg") {
+		return nil, ErrUnsupportedScreenshot
+	}
+	if cfg.Width*cfg.Height > MaxRedactionPixels {
+		return nil, fmt.Errorf("screenshot is %dx%d; at most %d pixels are supported", cfg.Width, cfg.Height, MaxRedactionPixels)
+	}
+	if err := validateRedactionRegions(regions, cfg.Width, cfg.Height); err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode screenshot: %w", err)
+	}
+
+	b := src.Bounds()
+	scale := captionScale(b.Dx())
+	lines := wrapCaption(caption, (b.Dx()-4*scale)/(6*scale))
+	stripHeight := 0
+	if len(lines) > 0 {
+		stripHeight = len(lines)*10*scale + 4*scale
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()+stripHeight))
+	draw.Draw(out, image.Rect(0, 0, b.Dx(), b.Dy()), src, b.Min, draw.Src)
+	for _, reg := range regions {
+		rect := image.Rect(reg.X, reg.Y, reg.X+reg.Width, reg.Y+reg.Height).Intersect(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(out, rect, image.NewUniform(redactionFill), image.Point{}, draw.Src)
+	}
+	if stripHeight > 0 {
+		draw.Draw(out, image.Rect(0, b.Dy(), b.Dx(), b.Dy()+stripHeight), image.NewUniform(captionBG), image.Point{}, draw.Src)
+		for i, line := range lines {
+			drawCaptionText(out, 2*scale, b.Dy()+2*scale+i*10*scale, scale, line)
+		}
+	}
+
+	var buf bytes.Buffer
+	res := &RedactedScreenshot{Width: out.Bounds().Dx(), Height: out.Bounds().Dy()}
+	if format == "png" {
+		res.MIMEType = "image/png"
+		err = png.Encode(&buf, out)
+	} else {
+		res.MIMEType = "image/jpeg"
+		err = jpeg.Encode(&buf, out, &jpeg.Options{Quality: 92})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode screenshot: %w", err)
+	}
+	res.Data = buf.Bytes()
+	return res, nil
+}
+
+// validateRedactionRegions checks that every region has a positive size and overlaps an image
+// of the given dimensions.
+func validateRedactionRegions(regions []models.RedactionRegion, width, height int) error {
+	bounds := image.Rect(0, 0, width, height)
+	for i, reg := range regions {
+		if reg.Width <= 0 || reg.Height <= 0 {
+			return fmt.Errorf("%w: region %d must have a positive width and height", ErrInvalidRedactionRegion, i+1)
+		}
+		if image.Rect(reg.X, reg.Y, reg.X+reg.Width, reg.Y+reg.Height).Intersect(bounds).Empty() {
+			return fmt.Errorf("%w: region %d lies outside the %dx%d image", ErrInvalidRedactionRegion, i+1, width, height)
+		}
+	}
+	return nil
+}
+
+// captionScale picks a glyph scale so captions stay legible on large screenshots.
+func captionScale(width int) int {
+	s := width / 640
+	if s < 1 {
+		return 1
+	}
+	if s > 4 {
+		return 4
+	}
+	return s
+}
+
+// wrapCaption breaks caption lines at perRow characters, keeping at most captionMaxRows rows.
+// Non-ASCII characters are replaced with '?', as the caption font only covers ASCII.
+func wrapCaption(caption []string, perRow int) []string {
+	if perRow < 1 {
+		perRow = 1
+	}
+	var rows []string
+	for _, line := range caption {
+		rs := []rune(line)
+		for i, r := range rs {
+			if r < 0x20 || r > 0x7E {
+				rs[i] = '?'
+			}
+		}
+		for len(rs) > perRow {
+			rows = append(rows, string(rs[:perRow]))
+			rs = rs[perRow:]
+		}
+		rows = append(rows, string(rs))
+	}
+	if len(rows) > captionMaxRows {
+		rows = rows[:captionMaxRows]
+	}
+	return rows
+}
+
+func drawCaptionText(img *image.RGBA, x, y, scale int, text string) {
+	for _, ch := range text {
+		glyph := captionFont[ch-0x20]
+		for col, bits := range glyph {
+			for row := 0; row < 8; row++ {
+				if bits&(1<<row) == 0 {
+					continue
+				}
+				px := image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale)
+				draw.Draw(img, px, image.NewUniform(captionFG), image.Point{}, draw.Src)
+			}
+		}
+		x += 6 * scale
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testScreenshot(t *testing.T, w, h int, encode func(*bytes.Buffer, image.Image) error) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{200, 220, 240, 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, encode(&buf, img))
+	return buf.Bytes()
+}
+
+func encodePNG(b *bytes.Buffer, img image.Image) error { return png.Encode(b, img) }
+
+func TestRedactScreenshot_PaintsRegionsAndCaption(t *testing.T) {
+	src := testScreenshot(t, 320, 200, encodePNG)
+	regions := []models.RedactionRegion{{X: 10, Y: 10, Width: 50, Height: 20}, {X: 300, Y: 190, Width: 100, Height: 100}}
+
+	out, err := RedactScreenshot(bytes.NewReader(src), regions, []string{"Captured: 2026-10-18 09:00:00 UTC", "Collector: Ada Admin"})
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", out.MIMEType)
+	assert.Equal(t, 320, out.Width)
+	assert.Greater(t, out.Height, 200, "caption strip is appended below the screenshot")
+
+	img, err := png.Decode(bytes.NewReader(out.Data))
+	require.NoError(t, err)
+	r, g, b, _ := img.At(30, 20).RGBA()
+	assert.Zero(t, r+g+b, "redacted pixel is black")
+	r, g, b, _ = img.At(319, 199).RGBA()
+	assert.Zero(t, r+g+b, "region clipped at the image edge is still painted")
+	r, _, _, _ = img.At(100, 100).RGBA()
+	assert.Equal(t, uint32(200*0x101), r, "pixels outside regions are untouched")
+}
+
+func TestRedactScreenshot_JPEGStaysJPEG(t *testing.T) {
+	src := testScreenshot(t, 64, 64, func(b *bytes.Buffer, img image.Image) error { return jpeg.Encode(b, img, nil) })
+
+	out, err := RedactScreenshot(bytes.NewReader(src), []models.RedactionRegion{{X: 0, Y: 0, Width: 8, Height: 8}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", out.MIMEType)
+	assert.Equal(t, 64, out.Height, "no caption requested")
+}
+
+func TestRedactScreenshot_Rejects(t *testing.T) {
+	src := testScreenshot(t, 40, 40, encodePNG)
+
+	_, err := RedactScreenshot(bytes.NewReader(src), []models.RedactionRegion{{X: 100, Y: 100, Width: 5, Height: 5}}, nil)
+	assert.True(t, errors.Is(err, ErrInvalidRedactionRegion))
+
+	_, err = RedactScreenshot(bytes.NewReader(src), []models.RedactionRegion{{X: 1, Y: 1, Width: 0, Height: 5}}, nil)
+	assert.True(t, errors.Is(err, ErrInvalidRedactionRegion))
+
+	_, err = RedactScreenshot(strings.NewReader("%PDF-1.7 not an image"), nil, []string{"x"})
+	assert.Equal(t, ErrUnsupportedScreenshot, err)
+}
+
+func TestWrapCaption(t *testing.T) {
+	rows := wrapCaption([]string{"URL: https://console.example.com/very/long/path", "Café"}, 20)
+	assert.Equal(t, []string{"URL: https://console", ".example.com/very/lo", "ng/path", "Caf?"}, rows)
+}
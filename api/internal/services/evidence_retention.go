@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// EvidenceObjectRemover deletes stored evidence objects.
+type EvidenceObjectRemover interface {
+	RemoveObject(ctx context.Context, objectKey string) error
+}
+
+// RetentionBlockedCondition matches artifacts (aliased ea) that must not be purged: the version
+// chain is under legal hold, or the artifact is submitted to an audit that is still open.
+const RetentionBlockedCondition = `(
+	EXISTS (SELECT 1 FROM evidence_artifacts lh
+		WHERE lh.id = COALESCE(ea.parent_artifact_id, ea.id) AND lh.legal_hold = TRUE)
+	OR EXISTS (SELECT 1 FROM audit_evidence_links ael JOIN audits au ON au.id = ael.audit_id
+		WHERE ael.artifact_id = ea.id AND au.status NOT IN ('completed', 'cancelled'))
+)`
+
+// EvidenceRetention summarizes the retention obligations on one artifact. RetainUntil is the
+// latest deadline across matching rules; it is nil when no rule matches or a rule's trigger
+// event (e.g. supersession) has not happened yet, in which case Pending is true.
+type EvidenceRetention struct {
+	RulesMatched int        `json:"rules_matched"`
+	RetainUntil  *time.Time `json:"retain_until"`
+	Pending      bool       `json:"pending"`
+}
+
+// GetEvidenceRetention returns the retention obligations on an artifact.
+func GetEvidenceRetention(ctx context.Context, db *sql.DB, artifactID string) (*EvidenceRetention, error) {
+	var (
+		r         EvidenceRetention
+		undecided int
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE retain_until IS NULL), MAX(retain_until)
+		FROM evidence_retention_deadlines
+		WHERE artifact_id = $1
+	`, artifactID).Scan(&r.RulesMatched, &undecided, &r.RetainUntil)
+	if err != nil {
+		return nil, fmt.Errorf("load retention deadlines: %w", err)
+	}
+	if undecided > 0 {
+		r.Pending = true
+		r.RetainUntil = nil
+	}
+	return &r, nil
+}
+
+// PurgedEvidence identifies an artifact whose stored file was purged.
+type PurgedEvidence struct {
+	ID          string
+	OrgID       string
+	RuleID      string
+	RetainUntil time.Time
+}
+
+type purgeCandidate struct {
+	PurgedEvidence
+	objectKey     string
+	quarantineKey *string
+}
+
+// PurgeExpiredEvidence deletes the stored files of up to limit artifacts whose retention has
+// lapsed under every matching rule, and marks the records as purged. Artifacts no rule matches
+// are never purged. Failures on individual artifacts are returned with the purged list so one
+// bad object does not stall the rest.
+func PurgeExpiredEvidence(ctx context.Context, db *sql.DB, store EvidenceObjectRemover, limit int) ([]PurgedEvidence, []error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT ea.id, ea.org_id, ea.object_key, ea.quarantine_object_key,
+			(array_agg(d.rule_id ORDER BY d.retain_until DESC))[1]::text, MAX(d.retain_until)
+		FROM evidence_artifacts ea
+		JOIN evidence_retention_deadlines d ON d.artifact_id = ea.id
+		WHERE ea.purged_at IS NULL AND NOT %s
+		GROUP BY ea.id
+		HAVING bool_and(d.retain_until IS NOT NULL) AND MAX(d.retain_until) <= NOW()
+		ORDER BY MAX(d.retain_until)
+		LIMIT $1
+	`, RetentionBlockedCondition), limit)
+	if err != nil {
+		return nil, []error{fmt.Errorf("list purgeable evidence: %w", err)}
+	}
+
+	var candidates []purgeCandidate
+	for rows.Next() {
+		var c purgeCandidate
+		if err := rows.Scan(&c.ID, &c.OrgID, &c.objectKey, &c.quarantineKey, &c.RuleID, &c.RetainUntil); err != nil {
+			rows.Close()
+			return nil, []error{fmt.Errorf("scan purgeable evidence: %w", err)}
+		}
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+
+	purged := []PurgedEvidence{}
+	var errs []error
+	for _, c := range candidates {
+		ok, err := purgeEvidenceArtifact(ctx, db, store, c)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("purge %s: %w", c.ID, err))
+			continue
+		}
+		if ok {
+			purged = append(purged, c.PurgedEvidence)
+		}
+	}
+	return purged, errs
+}
+
+// purgeEvidenceArtifact marks the record first and deletes the object before committing, so a
+// storage failure rolls the mark back. The hold check is repeated in the update in case a hold
+// was placed since the candidate list was built; false means the artifact was skipped.
+func purgeEvidenceArtifact(ctx context.Context, db *sql.DB, store EvidenceObjectRemover, c purgeCandidate) (bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE evidence_artifacts ea SET purged_at = NOW(), purged_by_rule_id = $2, is_current = FALSE
+		WHERE ea.id = $1 AND ea.purged_at IS NULL AND NOT %s
+	`, RetentionBlockedCondition), c.ID, c.RuleID)
+	if err != nil {
+		return false, fmt.Errorf("mark purged: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	// Extracted text is a copy of the file contents and goes with it.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM evidence_contents WHERE artifact_id = $1`, c.ID); err != nil {
+		return false, fmt.Errorf("delete extracted content: %w", err)
+	}
+
+	if err := store.RemoveObject(ctx, c.objectKey); err != nil {
+		return false, err
+	}
+	if c.quarantineKey != nil && *c.quarantineKey != "" {
+		if err := store.RemoveObject(ctx, *c.quarantineKey); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+	return true, nil
+}
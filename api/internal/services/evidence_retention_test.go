@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRemover struct {
+	err     error
+	removed []string
+}
+
+func (f *fakeRemover) RemoveObject(ctx context.Context, key string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.removed = append(f.removed, key)
+	return nil
+}
+
+func purgeCandidateRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "org_id", "object_key", "quarantine_object_key", "rule_id", "retain_until"}).
+		AddRow("e001", "a001", "a001/e001/1/payroll.csv", nil, "r001", time.Now().Add(-time.Hour))
+}
+
+func TestPurgeExpiredEvidence_RemovesObjectAndMarksRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("FROM evidence_artifacts ea").WillReturnRows(purgeCandidateRows())
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE evidence_artifacts ea SET purged_at").WithArgs("e001", "r001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM evidence_contents").WithArgs("e001").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	store := &fakeRemover{}
+	purged, errs := PurgeExpiredEvidence(context.Background(), db, store, 10)
+	assert.Empty(t, errs)
+	require.Len(t, purged, 1)
+	assert.Equal(t, "r001", purged[0].RuleID)
+	assert.Equal(t, []string{"a001/e001/1/payroll.csv"}, store.removed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeExpiredEvidence_StorageFailureRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("FROM evidence_artifacts ea").WillReturnRows(purgeCandidateRows())
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE evidence_artifacts ea SET purged_at").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM evidence_contents").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	purged, errs := PurgeExpiredEvidence(context.Background(), db, &fakeRemover{err: errors.New("minio down")}, 10)
+	assert.Empty(t, purged)
+	assert.Len(t, errs, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeExpiredEvidence_HoldPlacedMeanwhile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("FROM evidence_artifacts ea").WillReturnRows(purgeCandidateRows())
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE evidence_artifacts ea SET purged_at").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	store := &fakeRemover{}
+	purged, errs := PurgeExpiredEvidence(context.Background(), db, store, 10)
+	assert.Empty(t, purged)
+	assert.Empty(t, errs)
+	assert.Empty(t, store.removed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
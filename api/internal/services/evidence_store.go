@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/lib/pq"
+)
+
+// ObjectStore is the part of object storage needed to write server-produced evidence, and to
+// remove it again when the artifact row that points at it is not committed.
+// *MinIOService satisfies it.
+type ObjectStore interface {
+	PutObject(ctx context.Context, objectKey, contentType string, data []byte) error
+	RemoveObject(ctx context.Context, objectKey string) error
+}
+
+// ErrEmptyEvidence is returned when evidence content has no bytes.
+var ErrEmptyEvidence = errors.New("evidence content is empty")
+
+// ErrNoObjectStore is returned when evidence would be registered without object storage to hold
+// its content.
+var ErrNoObjectStore = errors.New("object storage is not configured")
+
+// EvidenceVersionSpec describes server-produced evidence to store as a new artifact version.
+type EvidenceVersionSpec struct {
+	OrgID string
+	// CurrentArtifactID is any artifact in the version chain to extend.
+	// Empty (or a chain that no longer exists) starts a new chain at version 1.
+	CurrentArtifactID   string
+	Title               string
+	Description         *string
+	EvidenceType        string
+	CollectionMethod    string
+	SourceSystem        *string
+	FreshnessPeriodDays *int
+	Tags                []string
+	Metadata            map[string]interface{}
+	UploadedBy          *string
+	// ControlIDs are linked to the new version in addition to links copied from the previous one.
+	ControlIDs     []string
+	CollectionDate time.Time
+	FileName       string
+	MIMEType       string
+	Data           []byte
+}
+
+// StoredEvidenceVersion is the artifact version created by StoreEvidenceVersion.
+type StoredEvidenceVersion struct {
+	ID                string  `json:"id"`
+	RootArtifactID    string  `json:"parent_artifact_id"`
+	Version           int     `json:"version"`
+	ObjectKey         string  `json:"-"`
+	FileSize          int64   `json:"file_size"`
+	ChecksumSHA256    string  `json:"checksum_sha256"`
+	ChainHash         string  `json:"chain_hash"`
+	PreviousVersionID *string `json:"previous_version_id"`
+}
+
+// StoreEvidenceVersion stores content as the next version of an evidence chain, following the
+// same rules as manual versioning: the current version is superseded, the new row keeps the
+// chain root as parent_artifact_id, and the previous version's links are carried forward.
+// Unlike manual uploads the content is already in hand, so the new version is checksummed,
+// written to the object store and submitted for review in one transaction. The object is
+// removed again if the transaction does not commit.
+func StoreEvidenceVersion(ctx context.Context, db *sql.DB, store ObjectStore, spec EvidenceVersionSpec) (*StoredEvidenceVersion, error) {
+	if err := checkEvidenceContent(store, spec.Data); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	out, err := storeEvidenceVersionTx(ctx, tx, store, spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := commitEvidenceVersion(tx, store, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IngestEvidenceVersion stores pipeline content under an idempotency key. The key names the
+// version chain it feeds: the first ingest starts the chain, later ingests add a version when
+// the content changed and return the current version unchanged otherwise (created is false).
+// Ingests with the same key are serialized, so concurrent retries cannot start two chains or
+// add the same content twice.
+func IngestEvidenceVersion(ctx context.Context, db *sql.DB, store ObjectStore, idempotencyKey string, apiKeyID *string, spec EvidenceVersionSpec) (out *StoredEvidenceVersion, created bool, err error) {
+	if err := checkEvidenceContent(store, spec.Data); err != nil {
+		return nil, false, err
+	}
+	sum := sha256.Sum256(spec.Data)
+	checksum := hex.EncodeToString(sum[:])
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The lock is held until the transaction ends. The unique key on evidence_ingestions
+	// cannot do this on its own because the row is written after the artifact it points at.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1 || '/' || $2))`,
+		spec.OrgID, idempotencyKey); err != nil {
+		return nil, false, fmt.Errorf("lock idempotency key: %w", err)
+	}
+
+	var rootID string
+	var currentID, currentChecksum *string
+	var currentVersion *int
+	err = tx.QueryRowContext(ctx, `
+		SELECT ei.artifact_id, cur.id, cur.checksum_sha256, cur.version
+		FROM evidence_ingestions ei
+		LEFT JOIN evidence_artifacts cur
+			ON (cur.id = ei.artifact_id OR cur.parent_artifact_id = ei.artifact_id) AND cur.is_current = TRUE
+		WHERE ei.org_id = $1 AND ei.idempotency_key = $2
+	`, spec.OrgID, idempotencyKey).Scan(&rootID, &currentID, &currentChecksum, &currentVersion)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("load evidence ingestion: %w", err)
+	}
+	if currentID != nil && currentChecksum != nil && *currentChecksum == checksum {
+		return &StoredEvidenceVersion{
+			ID:             *currentID,
+			RootArtifactID: rootID,
+			Version:        *currentVersion,
+			FileSize:       int64(len(spec.Data)),
+			ChecksumSHA256: checksum,
+		}, false, nil
+	}
+
+	spec.CurrentArtifactID = rootID
+	out, err = storeEvidenceVersionTx(ctx, tx, store, spec)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO evidence_ingestions (org_id, idempotency_key, artifact_id, api_key_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (org_id, idempotency_key) DO UPDATE
+			SET artifact_id = EXCLUDED.artifact_id, api_key_id = EXCLUDED.api_key_id,
+				ingest_count = evidence_ingestions.ingest_count + 1
+	`, spec.OrgID, idempotencyKey, out.RootArtifactID, apiKeyID); err != nil {
+		store.RemoveObject(context.Background(), out.ObjectKey)
+		return nil, false, fmt.Errorf("record evidence ingestion: %w", err)
+	}
+
+	if err := commitEvidenceVersion(tx, store, out); err != nil {
+		return nil, false, err
+	}
+	return out, true, nil
+}
+
+func checkEvidenceContent(store ObjectStore, data []byte) error {
+	if store == nil {
+		return ErrNoObjectStore
+	}
+	if len(data) == 0 {
+		return ErrEmptyEvidence
+	}
+	if len(data) > models.MaxFileSize {
+		return fmt.Errorf("evidence content exceeds %d bytes", models.MaxFileSize)
+	}
+	return nil
+}
+
+// commitEvidenceVersion commits the transaction that registered out, removing its object if
+// the commit fails.
+func commitEvidenceVersion(tx *sql.Tx, store ObjectStore, out *StoredEvidenceVersion) error {
+	if err := tx.Commit(); err != nil {
+		if rmErr := store.RemoveObject(context.Background(), out.ObjectKey); rmErr != nil {
+			return fmt.Errorf("commit evidence version: %w (object %s left behind: %v)", err, out.ObjectKey, rmErr)
+		}
+		return fmt.Errorf("commit evidence version: %w", err)
+	}
+	return nil
+}
+
+// storeEvidenceVersionTx registers the next version of spec's chain in tx and writes its
+// object. The caller commits.
+func storeEvidenceVersionTx(ctx context.Context, tx *sql.Tx, store ObjectStore, spec EvidenceVersionSpec) (*StoredEvidenceVersion, error) {
+	sum := sha256.Sum256(spec.Data)
+	out := &StoredEvidenceVersion{
+		ID:             uuid.New().String(),
+		FileSize:       int64(len(spec.Data)),
+		ChecksumSHA256: hex.EncodeToString(sum[:]),
+		Version:        1,
+	}
+
+	// Find the current version of the chain the spec points at.
+	var parentArtifactID *string
+	prevChainHash := ""
+	if spec.CurrentArtifactID != "" {
+		var prevID, rootID, prevChain string
+		var prevVersion int
+		err := tx.QueryRowContext(ctx, `
+			WITH root AS (
+				SELECT COALESCE(parent_artifact_id, id) AS id
+				FROM evidence_artifacts WHERE id = $1 AND org_id = $2
+			)
+			SELECT ea.id, ea.version, root.id, COALESCE(ea.chain_hash, '')
+			FROM evidence_artifacts ea, root
+			WHERE (ea.id = root.id OR ea.parent_artifact_id = root.id) AND ea.is_current = TRUE
+			FOR UPDATE OF ea
+		`, spec.CurrentArtifactID, spec.OrgID).Scan(&prevID, &prevVersion, &rootID, &prevChain)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("load current version: %w", err)
+		}
+		if err == nil {
+			out.PreviousVersionID = &prevID
+			out.Version = prevVersion + 1
+			parentArtifactID = &rootID
+			prevChainHash = prevChain
+		}
+	}
+	out.ChainHash = EvidenceChainHash(prevChainHash, out.ID, out.Version, out.ChecksumSHA256)
+
+	out.RootArtifactID = out.ID
+	if parentArtifactID != nil {
+		out.RootArtifactID = *parentArtifactID
+	}
+	out.ObjectKey = fmt.Sprintf("%s/%s/%d/%s", spec.OrgID, out.RootArtifactID, out.Version, spec.FileName)
+
+	if out.PreviousVersionID != nil {
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE evidence_artifacts SET is_current = FALSE, status = 'superseded' WHERE id = $1",
+			*out.PreviousVersionID); err != nil {
+			return nil, fmt.Errorf("supersede previous version: %w", err)
+		}
+	}
+
+	var expiresAt *time.Time
+	if spec.FreshnessPeriodDays != nil {
+		exp := spec.CollectionDate.AddDate(0, 0, *spec.FreshnessPeriodDays)
+		expiresAt = &exp
+	}
+	metadata := []byte("{}")
+	if spec.Metadata != nil {
+		metadata, _ = json.Marshal(spec.Metadata)
+	}
+	tags := spec.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO evidence_artifacts (id, org_id, title, description, evidence_type, status,
+			collection_method, file_name, file_size, mime_type, object_key, checksum_sha256,
+			parent_artifact_id, version, is_current,
+			collection_date, expires_at, freshness_period_days,
+			source_system, uploaded_by, tags, metadata,
+			checksum_verified_at, previous_chain_hash, chain_hash)
+		VALUES ($1, $2, $3, $4, $5, 'pending_review', $6, $7, $8, $9, $10, $11,
+			$12, $13, TRUE, $14, $15, $16, $17, $18, $19, $20,
+			NOW(), $21, $22)
+	`, out.ID, spec.OrgID, spec.Title, spec.Description, spec.EvidenceType,
+		spec.CollectionMethod, spec.FileName, out.FileSize, spec.MIMEType, out.ObjectKey, out.ChecksumSHA256,
+		parentArtifactID, out.Version,
+		spec.CollectionDate.Format("2006-01-02"), expiresAt, spec.FreshnessPeriodDays,
+		spec.SourceSystem, spec.UploadedBy, pq.Array(tags), string(metadata),
+		prevChainHash, out.ChainHash)
+	if err != nil {
+		return nil, fmt.Errorf("insert evidence version: %w", err)
+	}
+
+	if out.PreviousVersionID != nil {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO evidence_links (id, org_id, artifact_id, target_type, control_id, requirement_id, notes, strength, linked_by)
+			SELECT gen_random_uuid(), org_id, $1, target_type, control_id, requirement_id, notes, strength, linked_by
+			FROM evidence_links WHERE artifact_id = $2
+		`, out.ID, *out.PreviousVersionID); err != nil {
+			return nil, fmt.Errorf("copy evidence links: %w", err)
+		}
+	}
+
+	// Link requested controls that are not already carried over. evidence_links uniqueness
+	// is deferred, so skip existing links explicitly rather than relying on ON CONFLICT.
+	if len(spec.ControlIDs) > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO evidence_links (org_id, artifact_id, target_type, control_id, strength, linked_by)
+			SELECT $1, $2, 'control', c.id, 'primary', $3
+			FROM controls c
+			WHERE c.org_id = $1 AND c.id = ANY($4::uuid[])
+			  AND NOT EXISTS (
+				SELECT 1 FROM evidence_links el
+				WHERE el.artifact_id = $2 AND el.control_id = c.id
+			  )
+		`, spec.OrgID, out.ID, spec.UploadedBy, pq.Array(spec.ControlIDs)); err != nil {
+			return nil, fmt.Errorf("link controls: %w", err)
+		}
+	}
+
+	if err := store.PutObject(ctx, out.ObjectKey, spec.MIMEType, spec.Data); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -2,8 +2,12 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/url"
 	"time"
 
@@ -97,6 +101,75 @@ func (s *MinIOService) VerifyObjectExists(objectKey string) (int64, error) {
 	return info.Size, nil
 }
 
+// PutObject stores server-generated content (e.g. attestation records) directly in the bucket.
+func (s *MinIOService) PutObject(ctx context.Context, objectKey, contentType string, data []byte) error {
+	_, err := s.client.PutObject(ctx, s.bucket, objectKey, bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return fmt.Errorf("failed to store object: %w", err)
+	}
+	return nil
+}
+
+// UploadObject streams content of a known size into the bucket, for server-built files too
+// large to hold in memory.
+func (s *MinIOService) UploadObject(ctx context.Context, objectKey, contentType string, r io.Reader, size int64) error {
+	_, err := s.client.PutObject(ctx, s.bucket, objectKey, r, size, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	return nil
+}
+
+// HashObject streams an object from the bucket and returns its SHA-256 and size.
+func (s *MinIOService) HashObject(ctx context.Context, objectKey string) (string, int64, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, objectKey, minio.GetObjectOptions{})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open object: %w", err)
+	}
+	defer obj.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, obj)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read object: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// OpenObject streams an object from the bucket. The caller must close the reader.
+func (s *MinIOService) OpenObject(ctx context.Context, objectKey string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, objectKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+	return obj, nil
+}
+
+// QuarantineObject moves an object under the quarantine/ prefix so it can no longer be reached
+// through its original key, and returns the new key.
+func (s *MinIOService) QuarantineObject(ctx context.Context, objectKey string) (string, error) {
+	quarantineKey := "quarantine/" + objectKey
+	_, err := s.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: s.bucket, Object: quarantineKey},
+		minio.CopySrcOptions{Bucket: s.bucket, Object: objectKey})
+	if err != nil {
+		return "", fmt.Errorf("failed to copy object to quarantine: %w", err)
+	}
+	if err := s.client.RemoveObject(ctx, s.bucket, objectKey, minio.RemoveObjectOptions{}); err != nil {
+		return "", fmt.Errorf("failed to remove quarantined object: %w", err)
+	}
+	return quarantineKey, nil
+}
+
+// RemoveObject permanently deletes an object. Removing a missing object is not an error.
+func (s *MinIOService) RemoveObject(ctx context.Context, objectKey string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, objectKey, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to remove object: %w", err)
+	}
+	return nil
+}
+
 // UploadTTLSeconds returns the upload URL TTL in seconds.
 func (s *MinIOService) UploadTTLSeconds() int {
 	return int(s.uploadTTL.Seconds())
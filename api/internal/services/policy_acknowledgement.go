@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// PolicyAckSweepResult counts what one policy acknowledgement sweep changed.
+type PolicyAckSweepResult struct {
+	Cancelled          int64
+	Assigned           int64
+	Reminded           int64
+	CampaignsCompleted int64
+	Completed          []CompletedPolicyAckCampaign
+}
+
+// CompletedPolicyAckCampaign is a campaign closed by a sweep. The sweep that completes a
+// campaign is the only one to see it, so its caller owns the follow-up (roster evidence, audit).
+type CompletedPolicyAckCampaign struct {
+	ID    string
+	OrgID string
+}
+
+// SweepPolicyAcknowledgements keeps active acknowledgement campaigns in step with the workforce:
+// users who left are dropped, users who joined an audience role are assigned, pending
+// acknowledgements are reminded on the campaign's interval, and campaigns with nothing left
+// pending are completed. Each step is a single statement, so a sweep is safe to run from
+// several workers. Completed campaigns are returned in the result for the caller to snapshot.
+func SweepPolicyAcknowledgements(ctx context.Context, db *sql.DB) (*PolicyAckSweepResult, error) {
+	var r PolicyAckSweepResult
+	steps := []struct {
+		name  string
+		query string
+		count *int64
+	}{
+		{"cancel departed users", `
+			UPDATE policy_acknowledgements pa SET status = 'cancelled'
+			FROM users u
+			WHERE u.id = pa.user_id AND pa.status = 'pending' AND u.status <> 'active'
+		`, &r.Cancelled},
+
+		// Named users were chosen explicitly, so only all-user and role audiences grow.
+		{"assign new users", `
+			INSERT INTO policy_acknowledgements (org_id, campaign_id, policy_id, policy_version_id, user_id, status)
+			SELECT pac.org_id, pac.id, pac.policy_id, pac.policy_version_id, u.id, 'pending'
+			FROM policy_ack_campaigns pac
+			JOIN users u ON u.org_id = pac.org_id AND u.status = 'active'
+			WHERE pac.status = 'active'
+				AND (pac.all_users OR u.role::text = ANY(pac.audience_roles))
+				AND NOT EXISTS (
+					SELECT 1 FROM policy_acknowledgements pa
+					WHERE pa.campaign_id = pac.id AND pa.user_id = u.id
+				)
+			ON CONFLICT (campaign_id, user_id) DO NOTHING
+		`, &r.Assigned},
+
+		{"send reminders", `
+			UPDATE policy_acknowledgements pa
+			SET reminder_sent_at = NOW(), reminder_count = pa.reminder_count + 1
+			FROM policy_ack_campaigns pac
+			WHERE pac.id = pa.campaign_id
+				AND pac.status = 'active'
+				AND pac.reminder_interval_days IS NOT NULL
+				AND pa.status = 'pending'
+				AND COALESCE(pa.reminder_sent_at, pa.created_at) <= NOW() - make_interval(days => pac.reminder_interval_days)
+		`, &r.Reminded},
+	}
+
+	for _, s := range steps {
+		res, err := db.ExecContext(ctx, s.query)
+		if err != nil {
+			return &r, fmt.Errorf("%s: %w", s.name, err)
+		}
+		*s.count, _ = res.RowsAffected()
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		UPDATE policy_ack_campaigns pac SET status = 'completed', completed_at = NOW()
+		WHERE pac.status = 'active'
+			AND NOT EXISTS (
+				SELECT 1 FROM policy_acknowledgements pa
+				WHERE pa.campaign_id = pac.id AND pa.status = 'pending'
+			)
+		RETURNING pac.id, pac.org_id
+	`)
+	if err != nil {
+		return &r, fmt.Errorf("complete campaigns: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c CompletedPolicyAckCampaign
+		if err := rows.Scan(&c.ID, &c.OrgID); err != nil {
+			return &r, fmt.Errorf("complete campaigns: %w", err)
+		}
+		r.Completed = append(r.Completed, c)
+	}
+	r.CampaignsCompleted = int64(len(r.Completed))
+	return &r, rows.Err()
+}
+
+// PolicyAckRoster is the content of a generated acknowledgement evidence record.
+type PolicyAckRoster struct {
+	CampaignID       string                 `json:"campaign_id"`
+	PolicyID         string                 `json:"policy_id"`
+	PolicyIdentifier string                 `json:"policy_identifier"`
+	PolicyTitle      string                 `json:"policy_title"`
+	VersionNumber    int                    `json:"version_number"`
+	DueDate          *time.Time             `json:"due_date"`
+	Statement        string                 `json:"statement"`
+	GeneratedAt      time.Time              `json:"generated_at"`
+	Summary          PolicyAckRosterSummary `json:"summary"`
+	Entries          []PolicyAckRosterEntry `json:"acknowledgements"`
+	ControlIDs       []string               `json:"-"`
+}
+
+type PolicyAckRosterSummary struct {
+	Assigned     int `json:"assigned"`
+	Acknowledged int `json:"acknowledged"`
+	Pending      int `json:"pending"`
+	Late         int `json:"late"`
+}
+
+type PolicyAckRosterEntry struct {
+	UserID         string     `json:"user_id"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	Status         string     `json:"status"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+}
+
+// StorePolicyAckEvidence writes the campaign's acknowledgement roster as a training record and
+// links it to the policy's controls. Each snapshot is a new version of the campaign's evidence
+// chain. uploadedBy is nil for automatic snapshots.
+func StorePolicyAckEvidence(ctx context.Context, db *sql.DB, store ObjectStore, orgID, campaignID string, uploadedBy *string) (*StoredEvidenceVersion, *PolicyAckRoster, error) {
+	roster := &PolicyAckRoster{CampaignID: campaignID, Statement: models.PolicyAckStatement, GeneratedAt: time.Now().UTC()}
+	var currentArtifactID *string
+	err := db.QueryRowContext(ctx, `
+		SELECT p.id, p.identifier, p.title, pac.version_number, pac.due_date, pac.evidence_artifact_id
+		FROM policy_ack_campaigns pac
+		JOIN policies p ON p.id = pac.policy_id
+		WHERE pac.id = $1 AND pac.org_id = $2
+	`, campaignID, orgID).Scan(&roster.PolicyID, &roster.PolicyIdentifier, &roster.PolicyTitle,
+		&roster.VersionNumber, &roster.DueDate, &currentArtifactID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load campaign: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT u.id, u.first_name || ' ' || u.last_name, u.email, u.role, pa.status, pa.acknowledged_at
+		FROM policy_acknowledgements pa
+		JOIN users u ON u.id = pa.user_id
+		WHERE pa.campaign_id = $1 AND pa.status <> 'cancelled'
+		ORDER BY u.last_name, u.first_name
+	`, campaignID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load roster: %w", err)
+	}
+	for rows.Next() {
+		var e PolicyAckRosterEntry
+		if err := rows.Scan(&e.UserID, &e.Name, &e.Email, &e.Role, &e.Status, &e.AcknowledgedAt); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("scan roster: %w", err)
+		}
+		roster.Summary.Assigned++
+		switch e.Status {
+		case models.PolicyAckStatusAcknowledged:
+			roster.Summary.Acknowledged++
+			if PolicyAckPastDue(roster.DueDate, *e.AcknowledgedAt) {
+				roster.Summary.Late++
+			}
+		case models.PolicyAckStatusPending:
+			roster.Summary.Pending++
+		}
+		roster.Entries = append(roster.Entries, e)
+	}
+	rows.Close()
+
+	roster.ControlIDs = []string{}
+	ctrlRows, err := db.QueryContext(ctx, `SELECT control_id FROM policy_controls WHERE policy_id = $1 AND org_id = $2`,
+		roster.PolicyID, orgID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load policy controls: %w", err)
+	}
+	for ctrlRows.Next() {
+		var id string
+		if ctrlRows.Scan(&id) == nil {
+			roster.ControlIDs = append(roster.ControlIDs, id)
+		}
+	}
+	ctrlRows.Close()
+
+	content, err := json.MarshalIndent(roster, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chain := ""
+	if currentArtifactID != nil {
+		chain = *currentArtifactID
+	}
+	description := fmt.Sprintf("%d of %d assigned users acknowledged %s version %d",
+		roster.Summary.Acknowledged, roster.Summary.Assigned, roster.PolicyIdentifier, roster.VersionNumber)
+	sourceSystem := "policy_acknowledgement"
+	freshDays := models.PolicyAckEvidenceFreshnessDays
+
+	stored, err := StoreEvidenceVersion(ctx, db, store, EvidenceVersionSpec{
+		OrgID:               orgID,
+		CurrentArtifactID:   chain,
+		Title:               fmt.Sprintf("Policy acknowledgements: %s %s v%d", roster.PolicyIdentifier, roster.PolicyTitle, roster.VersionNumber),
+		Description:         &description,
+		EvidenceType:        "training_record",
+		CollectionMethod:    "system_export",
+		SourceSystem:        &sourceSystem,
+		FreshnessPeriodDays: &freshDays,
+		Tags:                []string{"policy-acknowledgement", "security-awareness"},
+		Metadata: map[string]interface{}{
+			"campaign_id":    campaignID,
+			"policy_id":      roster.PolicyID,
+			"version_number": roster.VersionNumber,
+		},
+		UploadedBy:     uploadedBy,
+		ControlIDs:     roster.ControlIDs,
+		CollectionDate: roster.GeneratedAt,
+		FileName:       fmt.Sprintf("acknowledgements-%s-v%d.json", roster.PolicyIdentifier, roster.VersionNumber),
+		MIMEType:       "application/json",
+		Data:           content,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := db.ExecContext(ctx, `UPDATE policy_ack_campaigns SET evidence_artifact_id = $1 WHERE id = $2`,
+		stored.ID, campaignID); err != nil {
+		return nil, nil, fmt.Errorf("record campaign evidence: %w", err)
+	}
+	return stored, roster, nil
+}
+
+// PolicyAckPastDue reports whether at falls after the due date (due dates are inclusive).
+func PolicyAckPastDue(dueDate *time.Time, at time.Time) bool {
+	if dueDate == nil {
+		return false
+	}
+	return at.After(dueDate.AddDate(0, 0, 1))
+}
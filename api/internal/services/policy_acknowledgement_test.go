@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepPolicyAcknowledgements_Counts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE policy_acknowledgements pa SET status = 'cancelled'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO policy_acknowledgements").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("SET reminder_sent_at = NOW\\(\\)").WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectQuery("UPDATE policy_ack_campaigns pac SET status = 'completed'").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "org_id"}).
+			AddRow("campaign-001", "org-001").
+			AddRow("campaign-002", "org-002"))
+
+	r, err := SweepPolicyAcknowledgements(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyAckSweepResult{
+		Cancelled: 1, Assigned: 3, Reminded: 12, CampaignsCompleted: 2,
+		Completed: []CompletedPolicyAckCampaign{{ID: "campaign-001", OrgID: "org-001"}, {ID: "campaign-002", OrgID: "org-002"}},
+	}, *r)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSweepPolicyAcknowledgements_StopsOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE policy_acknowledgements pa SET status = 'cancelled'").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO policy_acknowledgements").WillReturnError(errors.New("deadlock detected"))
+
+	r, err := SweepPolicyAcknowledgements(context.Background(), db)
+	assert.ErrorContains(t, err, "assign new users")
+	assert.Equal(t, int64(2), r.Cancelled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorePolicyAckEvidence_NoObjectStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("FROM policy_ack_campaigns pac").
+		WithArgs("campaign-001", "org-001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "identifier", "title", "version_number", "due_date", "evidence_artifact_id"}).
+			AddRow("policy-001", "POL-IS-001", "Information Security Policy", 2, nil, nil))
+	mock.ExpectQuery("FROM policy_acknowledgements pa").
+		WithArgs("campaign-001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "role", "status", "acknowledged_at"}).
+			AddRow("user-001", "Alice Smith", "alice@acme.com", "ciso", "acknowledged", time.Now()))
+	mock.ExpectQuery("SELECT control_id FROM policy_controls").
+		WillReturnRows(sqlmock.NewRows([]string{"control_id"}).AddRow("ctrl-001"))
+
+	_, _, err = StorePolicyAckEvidence(context.Background(), db, nil, "org-001", "campaign-001", nil)
+	assert.ErrorIs(t, err, ErrNoObjectStore)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPolicyAckPastDue(t *testing.T) {
+	due := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	assert.False(t, PolicyAckPastDue(nil, time.Now()))
+	assert.False(t, PolicyAckPastDue(&due, due.Add(23*time.Hour)), "the due date itself is on time")
+	assert.True(t, PolicyAckPastDue(&due, due.Add(25*time.Hour)))
+}
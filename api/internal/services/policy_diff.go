@@ -0,0 +1,406 @@
+package services
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// maxDiffCells bounds the LCS table for a single alignment. Larger inputs fall back to treating
+// the compared ranges as wholly replaced and mark the diff as truncated.
+const maxDiffCells = 4_000_000
+
+// Similarity thresholds for pairing an unmatched removed item with an added one as a modification.
+const (
+	paragraphModifiedThreshold = 0.4
+	sectionRenamedThreshold    = 0.5
+)
+
+// headingSentinel marks a heading line while HTML is flattened to text.
+const headingSentinel = "\x00H\x00"
+
+var (
+	htmlDropRe    = regexp.MustCompile(`(?is)<(script|style)\b[^>]*>.*?</(script|style)\s*>`)
+	htmlHeadingRe = regexp.MustCompile(`(?is)<h[1-6]\b[^>]*>(.*?)</h[1-6]\s*>`)
+	htmlBlockRe   = regexp.MustCompile(`(?i)</?(p|div|li|ul|ol|tr|table|thead|tbody|blockquote|pre|section|article|header|footer|br|hr)\b[^>]*>`)
+	htmlTagRe     = regexp.MustCompile(`<[^>]+>`)
+	mdHeadingRe   = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdListItemRe  = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+`)
+	sectionNumRe  = regexp.MustCompile(`^[\d.]+\s+`)
+)
+
+// policySection is a heading and the paragraphs under it.
+type policySection struct {
+	title string
+	paras []string
+}
+
+// policyDiffBuilder accumulates sections, side-by-side rows and stats.
+type policyDiffBuilder struct {
+	d *models.PolicyDiff
+}
+
+// DiffPolicyContent compares two versions of policy content. Markdown and HTML are split into
+// sections by heading and aligned section by section, then paragraph by paragraph; plain text is
+// compared paragraph by paragraph. Modified paragraphs carry a word-level diff.
+func DiffPolicyContent(oldContent, oldFormat, newContent, newFormat string) *models.PolicyDiff {
+	b := &policyDiffBuilder{d: &models.PolicyDiff{
+		Mode:       models.PolicyDiffModeWords,
+		Inline:     []models.PolicyDiffSection{},
+		SideBySide: []models.PolicyDiffRow{},
+	}}
+	if oldFormat != models.ContentFormatPlainText || newFormat != models.ContentFormatPlainText {
+		b.d.Mode = models.PolicyDiffModeSections
+	}
+	oldSecs := splitPolicyContent(oldContent, oldFormat)
+	newSecs := splitPolicyContent(newContent, newFormat)
+
+	pairs, ok := lcsPairs(len(oldSecs), len(newSecs), func(i, j int) bool {
+		return sectionKey(oldSecs[i].title) == sectionKey(newSecs[j].title)
+	})
+	if !ok {
+		b.d.Truncated = true
+	}
+	walkAlignment(len(oldSecs), len(newSecs), pairs, func(gapOld, gapNew []int) {
+		// Unmatched sections with similar bodies are treated as renamed rather than replaced.
+		used := map[int]bool{}
+		for _, i := range gapOld {
+			match := -1
+			for _, j := range gapNew {
+				if !used[j] && textSimilarity(strings.Join(oldSecs[i].paras, " "), strings.Join(newSecs[j].paras, " ")) >= sectionRenamedThreshold {
+					match = j
+					break
+				}
+			}
+			if match < 0 {
+				b.addSection(oldSecs[i].title, nil, b.diffParagraphs(oldSecs[i].paras, nil))
+				continue
+			}
+			used[match] = true
+			oldTitle := oldSecs[i].title
+			b.addSection(newSecs[match].title, &oldTitle, b.diffParagraphs(oldSecs[i].paras, newSecs[match].paras))
+		}
+		for _, j := range gapNew {
+			if !used[j] {
+				b.addSection(newSecs[j].title, nil, b.diffParagraphs(nil, newSecs[j].paras))
+			}
+		}
+	}, func(i, j int) {
+		var oldTitle *string
+		if oldSecs[i].title != newSecs[j].title {
+			t := oldSecs[i].title
+			oldTitle = &t
+		}
+		b.addSection(newSecs[j].title, oldTitle, b.diffParagraphs(oldSecs[i].paras, newSecs[j].paras))
+	})
+	return b.d
+}
+
+// addSection classifies a section from its blocks and records it in the inline and
+// side-by-side views.
+func (b *policyDiffBuilder) addSection(title string, oldTitle *string, blocks []models.PolicyDiffParagraph) {
+	counts := map[string]int{}
+	for _, blk := range blocks {
+		counts[blk.Status]++
+	}
+	status := models.DiffUnchanged
+	switch {
+	case len(blocks) > 0 && counts[models.DiffAdded] == len(blocks):
+		status = models.DiffAdded
+		b.d.Stats.SectionsAdded++
+	case len(blocks) > 0 && counts[models.DiffRemoved] == len(blocks):
+		status = models.DiffRemoved
+		b.d.Stats.SectionsRemoved++
+	case oldTitle != nil || counts[models.DiffUnchanged] != len(blocks):
+		status = models.DiffModified
+		b.d.Stats.SectionsModified++
+	}
+	if blocks == nil {
+		blocks = []models.PolicyDiffParagraph{}
+	}
+	b.d.Inline = append(b.d.Inline, models.PolicyDiffSection{Title: title, OldTitle: oldTitle, Status: status, Blocks: blocks})
+
+	for _, blk := range blocks {
+		row := models.PolicyDiffRow{Section: title, Status: blk.Status, Left: []models.PolicyDiffWord{}, Right: []models.PolicyDiffWord{}}
+		switch blk.Status {
+		case models.DiffUnchanged:
+			row.Left = []models.PolicyDiffWord{{Status: models.DiffUnchanged, Text: *blk.Old}}
+			row.Right = []models.PolicyDiffWord{{Status: models.DiffUnchanged, Text: *blk.New}}
+		case models.DiffRemoved:
+			row.Left = []models.PolicyDiffWord{{Status: models.DiffRemoved, Text: *blk.Old}}
+		case models.DiffAdded:
+			row.Right = []models.PolicyDiffWord{{Status: models.DiffAdded, Text: *blk.New}}
+		case models.DiffModified:
+			for _, w := range blk.Words {
+				if w.Status != models.DiffAdded {
+					row.Left = append(row.Left, w)
+				}
+				if w.Status != models.DiffRemoved {
+					row.Right = append(row.Right, w)
+				}
+			}
+		}
+		b.d.SideBySide = append(b.d.SideBySide, row)
+	}
+}
+
+// diffParagraphs aligns two paragraph lists. Unmatched paragraphs in the same gap are paired as
+// modifications when they are similar enough; the rest are additions or removals.
+func (b *policyDiffBuilder) diffParagraphs(oldParas, newParas []string) []models.PolicyDiffParagraph {
+	var out []models.PolicyDiffParagraph
+	pairs, ok := lcsPairs(len(oldParas), len(newParas), func(i, j int) bool { return oldParas[i] == newParas[j] })
+	if !ok {
+		b.d.Truncated = true
+	}
+	walkAlignment(len(oldParas), len(newParas), pairs, func(gapOld, gapNew []int) {
+		k := 0
+		for _, i := range gapOld {
+			if k < len(gapNew) && textSimilarity(oldParas[i], newParas[gapNew[k]]) >= paragraphModifiedThreshold {
+				out = append(out, b.modified(oldParas[i], newParas[gapNew[k]]))
+				k++
+				continue
+			}
+			old := oldParas[i]
+			out = append(out, models.PolicyDiffParagraph{Status: models.DiffRemoved, Old: &old})
+			b.d.Stats.ParagraphsRemoved++
+			b.d.Stats.WordsRemoved += len(strings.Fields(old))
+		}
+		for ; k < len(gapNew); k++ {
+			nw := newParas[gapNew[k]]
+			out = append(out, models.PolicyDiffParagraph{Status: models.DiffAdded, New: &nw})
+			b.d.Stats.ParagraphsAdded++
+			b.d.Stats.WordsAdded += len(strings.Fields(nw))
+		}
+	}, func(i, j int) {
+		text := oldParas[i]
+		out = append(out, models.PolicyDiffParagraph{Status: models.DiffUnchanged, Old: &text, New: &text})
+		b.d.Stats.ParagraphsUnchanged++
+	})
+	return out
+}
+
+func (b *policyDiffBuilder) modified(oldText, newText string) models.PolicyDiffParagraph {
+	words, ok := DiffWords(oldText, newText)
+	if !ok {
+		b.d.Truncated = true
+	}
+	for _, w := range words {
+		switch w.Status {
+		case models.DiffAdded:
+			b.d.Stats.WordsAdded += len(strings.Fields(w.Text))
+		case models.DiffRemoved:
+			b.d.Stats.WordsRemoved += len(strings.Fields(w.Text))
+		}
+	}
+	b.d.Stats.ParagraphsModified++
+	return models.PolicyDiffParagraph{Status: models.DiffModified, Old: &oldText, New: &newText, Words: words}
+}
+
+// DiffWords returns word runs turning oldText into newText. ok is false when the texts were too
+// large to align and are reported as a whole replacement.
+func DiffWords(oldText, newText string) ([]models.PolicyDiffWord, bool) {
+	a, b := strings.Fields(oldText), strings.Fields(newText)
+	pairs, ok := lcsPairs(len(a), len(b), func(i, j int) bool { return a[i] == b[j] })
+
+	var runs []models.PolicyDiffWord
+	emit := func(status, word string) {
+		if n := len(runs); n > 0 && runs[n-1].Status == status {
+			runs[n-1].Text += " " + word
+			return
+		}
+		runs = append(runs, models.PolicyDiffWord{Status: status, Text: word})
+	}
+	walkAlignment(len(a), len(b), pairs, func(gapOld, gapNew []int) {
+		for _, i := range gapOld {
+			emit(models.DiffRemoved, a[i])
+		}
+		for _, j := range gapNew {
+			emit(models.DiffAdded, b[j])
+		}
+	}, func(i, j int) {
+		emit(models.DiffUnchanged, a[i])
+	})
+	return runs, ok
+}
+
+// lcsPairs returns the index pairs of a longest common subsequence of two sequences of lengths
+// n and m. ok is false when n*m exceeds maxDiffCells, in which case no pairs are returned.
+func lcsPairs(n, m int, eq func(i, j int) bool) ([][2]int, bool) {
+	if n == 0 || m == 0 {
+		return nil, true
+	}
+	if n*m > maxDiffCells {
+		return nil, false
+	}
+	// dp[i][j] is the LCS length of the suffixes starting at i and j.
+	dp := make([][]int32, n+1)
+	for i := range dp {
+		dp[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case eq(i, j):
+				dp[i][j] = dp[i+1][j+1] + 1
+			case dp[i+1][j] >= dp[i][j+1]:
+				dp[i][j] = dp[i+1][j]
+			default:
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+	var pairs [][2]int
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case eq(i, j):
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case dp[i+1][j] >= dp[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs, true
+}
+
+// walkAlignment visits two sequences in order: gap receives the unmatched indexes before each
+// matched pair (and after the last), match receives each pair.
+func walkAlignment(n, m int, pairs [][2]int, gap func(gapOld, gapNew []int), match func(i, j int)) {
+	i, j := 0, 0
+	flush := func(toI, toJ int) {
+		if i == toI && j == toJ {
+			return
+		}
+		var gOld, gNew []int
+		for ; i < toI; i++ {
+			gOld = append(gOld, i)
+		}
+		for ; j < toJ; j++ {
+			gNew = append(gNew, j)
+		}
+		gap(gOld, gNew)
+	}
+	for _, p := range pairs {
+		flush(p[0], p[1])
+		match(p[0], p[1])
+		i, j = p[0]+1, p[1]+1
+	}
+	flush(n, m)
+}
+
+// textSimilarity is the Dice coefficient of the word-level LCS, ignoring case and punctuation:
+// 1 for identical text, 0 for nothing in common.
+func textSimilarity(a, b string) float64 {
+	wa, wb := similarityWords(a), similarityWords(b)
+	if len(wa)+len(wb) == 0 {
+		return 1
+	}
+	pairs, ok := lcsPairs(len(wa), len(wb), func(i, j int) bool { return wa[i] == wb[j] })
+	if !ok {
+		return 0
+	}
+	return 2 * float64(len(pairs)) / float64(len(wa)+len(wb))
+}
+
+func similarityWords(s string) []string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, w := range words {
+		words[i] = strings.TrimFunc(w, unicode.IsPunct)
+	}
+	return words
+}
+
+// sectionKey normalizes a heading for alignment so renumbering ("3.2 Access" to "4.1 Access")
+// and case changes still match.
+func sectionKey(title string) string {
+	t := strings.ToLower(strings.TrimSpace(title))
+	return strings.Join(strings.Fields(sectionNumRe.ReplaceAllString(t, "")), " ")
+}
+
+// splitPolicyContent splits content into sections of normalized paragraphs.
+func splitPolicyContent(content, format string) []policySection {
+	var lines []string
+	switch format {
+	case models.ContentFormatHTML:
+		lines = htmlToDiffLines(content)
+	case models.ContentFormatMarkdown:
+		lines = markdownToDiffLines(content)
+	default:
+		lines = strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	}
+
+	sections := []policySection{{}}
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			cur := &sections[len(sections)-1]
+			cur.paras = append(cur.paras, strings.Join(para, " "))
+			para = nil
+		}
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(line, headingSentinel) {
+			flush()
+			sections = append(sections, policySection{title: strings.TrimPrefix(line, headingSentinel)})
+			continue
+		}
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			flush()
+			continue
+		}
+		para = append(para, line)
+	}
+	flush()
+
+	if len(sections[0].paras) == 0 && len(sections) > 1 {
+		sections = sections[1:]
+	}
+	return sections
+}
+
+// markdownToDiffLines marks ATX headings and puts each list item in its own paragraph.
+func markdownToDiffLines(content string) []string {
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			out = append(out, "")
+			continue
+		}
+		if !inFence {
+			if m := mdHeadingRe.FindStringSubmatch(line); m != nil {
+				out = append(out, headingSentinel+m[2])
+				continue
+			}
+			if mdListItemRe.MatchString(line) {
+				out = append(out, "", line)
+				continue
+			}
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// htmlToDiffLines flattens HTML to text lines, breaking paragraphs at block elements and marking
+// headings.
+func htmlToDiffLines(content string) []string {
+	s := htmlDropRe.ReplaceAllString(content, "")
+	s = htmlHeadingRe.ReplaceAllStringFunc(s, func(h string) string {
+		inner := htmlHeadingRe.FindStringSubmatch(h)[1]
+		text := strings.Join(strings.Fields(html.UnescapeString(htmlTagRe.ReplaceAllString(inner, " "))), " ")
+		return "\n\n" + headingSentinel + text + "\n\n"
+	})
+	s = htmlBlockRe.ReplaceAllString(s, "\n\n")
+	s = htmlTagRe.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	return strings.Split(s, "\n")
+}
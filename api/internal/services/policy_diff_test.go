@@ -0,0 +1,115 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPolicyContent_MarkdownSections(t *testing.T) {
+	oldDoc := `# 1 Purpose
+This policy defines access control requirements.
+
+# 2 Scope
+Applies to all employees.
+
+# 3 Passwords
+Passwords must be at least 8 characters long and rotated every 90 days.
+
+- Use a password manager
+- Never share passwords
+`
+	newDoc := `# 1 Purpose
+This policy defines access control requirements.
+
+# 2 Scope
+Applies to all employees and contractors.
+
+# 3 Passwords
+Passwords must be at least 14 characters long.
+
+- Use a password manager
+
+# 4 Exceptions
+Exceptions require CISO approval.
+`
+	d := DiffPolicyContent(oldDoc, models.ContentFormatMarkdown, newDoc, models.ContentFormatMarkdown)
+
+	assert.Equal(t, models.PolicyDiffModeSections, d.Mode)
+	assert.False(t, d.Truncated)
+	require.Len(t, d.Inline, 4)
+	assert.Equal(t, models.DiffUnchanged, d.Inline[0].Status)
+	assert.Equal(t, models.DiffModified, d.Inline[1].Status)
+	assert.Equal(t, models.DiffModified, d.Inline[2].Status)
+	assert.Equal(t, "4 Exceptions", d.Inline[3].Title)
+	assert.Equal(t, models.DiffAdded, d.Inline[3].Status)
+
+	assert.Equal(t, 1, d.Stats.SectionsAdded)
+	assert.Equal(t, 2, d.Stats.SectionsModified)
+	assert.Equal(t, 1, d.Stats.ParagraphsAdded)
+	assert.Equal(t, 1, d.Stats.ParagraphsRemoved, "dropped list item")
+	assert.Equal(t, 2, d.Stats.ParagraphsModified)
+	assert.Equal(t, 2, d.Stats.ParagraphsUnchanged)
+
+	scope := d.Inline[1].Blocks[0]
+	assert.Equal(t, []models.PolicyDiffWord{
+		{Status: models.DiffUnchanged, Text: "Applies to all"},
+		{Status: models.DiffRemoved, Text: "employees."},
+		{Status: models.DiffAdded, Text: "employees and contractors."},
+	}, scope.Words)
+
+	assert.Len(t, d.SideBySide, 6)
+	for _, row := range d.SideBySide {
+		if row.Status == models.DiffModified {
+			for _, w := range row.Left {
+				assert.NotEqual(t, models.DiffAdded, w.Status)
+			}
+			for _, w := range row.Right {
+				assert.NotEqual(t, models.DiffRemoved, w.Status)
+			}
+		}
+	}
+}
+
+func TestDiffPolicyContent_HTMLRenumberedAndRenamed(t *testing.T) {
+	oldDoc := `<h2>3.1 Access Reviews</h2><p>Access is reviewed quarterly by managers.</p>
+<h2>3.2 Logging</h2><p>Logs are retained for one year &amp; reviewed weekly.</p>`
+	newDoc := `<h2>4.1 access reviews</h2><p>Access is reviewed quarterly by managers.</p>
+<h2>4.2 Audit Logging</h2><p>Logs are retained for one year &amp; reviewed weekly.</p>`
+
+	d := DiffPolicyContent(oldDoc, models.ContentFormatHTML, newDoc, models.ContentFormatHTML)
+
+	require.Len(t, d.Inline, 2)
+	assert.Equal(t, models.DiffModified, d.Inline[0].Status, "renumbered heading matches but title changed")
+	assert.Equal(t, "3.1 Access Reviews", *d.Inline[0].OldTitle)
+	assert.Equal(t, models.DiffModified, d.Inline[1].Status, "renamed section with the same body is not replaced")
+	assert.Equal(t, "3.2 Logging", *d.Inline[1].OldTitle)
+	assert.Equal(t, "Logs are retained for one year & reviewed weekly.", *d.Inline[1].Blocks[0].New)
+	assert.Equal(t, 2, d.Stats.ParagraphsUnchanged)
+	assert.Zero(t, d.Stats.SectionsAdded+d.Stats.SectionsRemoved)
+}
+
+func TestDiffPolicyContent_PlainTextWords(t *testing.T) {
+	d := DiffPolicyContent("All laptops must be encrypted.\n\nReport loss within 24 hours.",
+		models.ContentFormatPlainText,
+		"All laptops and phones must be encrypted.\n\nReport loss within 24 hours.",
+		models.ContentFormatPlainText)
+
+	assert.Equal(t, models.PolicyDiffModeWords, d.Mode)
+	require.Len(t, d.Inline, 1)
+	assert.Equal(t, 1, d.Stats.ParagraphsModified)
+	assert.Equal(t, 2, d.Stats.WordsAdded)
+	assert.Zero(t, d.Stats.WordsRemoved)
+}
+
+func TestDiffPolicyContent_Identical(t *testing.T) {
+	d := DiffPolicyContent("<p>Same</p>", models.ContentFormatHTML, "<p>Same</p>", models.ContentFormatHTML)
+	assert.Equal(t, models.PolicyDiffStats{ParagraphsUnchanged: 1}, d.Stats)
+}
+
+func TestSectionKey(t *testing.T) {
+	assert.Equal(t, sectionKey("3.2  Access Control"), sectionKey("4.1 access control"))
+	assert.NotEqual(t, sectionKey("Access Control"), sectionKey("Access Reviews"))
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// PolicyExceptionSweepResult counts what one policy exception sweep changed.
+type PolicyExceptionSweepResult struct {
+	Expired        int64
+	AlertsResolved int64
+	AlertsRaised   int64
+}
+
+// SweepPolicyExceptions expires approved exceptions once their expiry date has passed and raises
+// alerts to the requester: a warning when an exception comes within lead days of expiry, and a
+// high severity alert when it expires. Warnings are resolved once the exception is no longer
+// approved. Each step is a single statement, so a sweep is safe to run from several workers.
+func SweepPolicyExceptions(ctx context.Context, db *sql.DB, leadDays int) (*PolicyExceptionSweepResult, error) {
+	var r PolicyExceptionSweepResult
+	steps := []struct {
+		name  string
+		query string
+		args  []interface{}
+		count *int64
+	}{
+		// expires_at is the last day the exception applies.
+		{"expire exceptions", `
+			UPDATE policy_exceptions SET status = 'expired', expired_at = NOW()
+			WHERE status = 'approved' AND expires_at < CURRENT_DATE
+		`, nil, &r.Expired},
+
+		{"resolve alerts", `
+			UPDATE alerts a SET status = 'resolved', resolved_at = NOW(),
+				resolution_notes = 'Policy exception ' || e.status::text
+			FROM policy_exceptions e
+			WHERE a.policy_id = e.policy_id
+				AND a.metadata->>'exception_id' = e.id::text
+				AND a.metadata->>'stage' = 'expiring'
+				AND a.status NOT IN ('resolved', 'closed')
+				AND e.status != 'approved'
+		`, nil, &r.AlertsResolved},
+
+		// Alerts hang off the first compensating control, or failing that the first control the
+		// policy covers; exceptions with neither are raised on the policy alone.
+		{"raise alerts", `
+			INSERT INTO alerts (org_id, title, description, severity, status,
+				control_id, policy_id, assigned_to, assigned_at,
+				delivery_channels, tags, metadata)
+			SELECT e.org_id,
+				CASE WHEN e.status = 'expired' THEN 'Policy exception expired: ' ELSE 'Policy exception expiring: ' END ||
+					p.identifier || ' ' || e.title,
+				'The exception to ' || p.identifier || ' ' ||
+					CASE WHEN e.status = 'expired' THEN 'expired after ' ELSE 'expires after ' END ||
+					to_char(e.expires_at, 'YYYY-MM-DD') ||
+					'. Bring the affected assets into compliance or request a new exception.',
+				CASE WHEN e.status = 'expired' THEN 'high' ELSE 'medium' END::alert_severity,
+				'open', ctl.control_id, p.id,
+				e.requested_by, NOW(),
+				ARRAY['in_app']::alert_delivery_channel[],
+				ARRAY['policy_exception'],
+				jsonb_build_object('source', 'policy_exception', 'exception_id', e.id,
+					'stage', CASE WHEN e.status = 'expired' THEN 'expired' ELSE 'expiring' END,
+					'expires_at', e.expires_at)
+			FROM policy_exceptions e
+			JOIN policies p ON p.id = e.policy_id
+			LEFT JOIN LATERAL (
+				SELECT control_id FROM (
+					SELECT ec.control_id, 0 AS rank, ec.created_at FROM policy_exception_controls ec
+					WHERE ec.exception_id = e.id
+					UNION ALL
+					SELECT pc.control_id, 1, pc.created_at FROM policy_controls pc
+					WHERE pc.policy_id = p.id
+				) c
+				ORDER BY rank, created_at
+				LIMIT 1
+			) ctl ON TRUE
+			WHERE (e.status = 'expired'
+					OR (e.status = 'approved' AND e.expires_at <= CURRENT_DATE + $1::int))
+				AND NOT EXISTS (
+					SELECT 1 FROM alerts a
+					WHERE a.policy_id = e.policy_id
+						AND a.metadata->>'exception_id' = e.id::text
+						AND a.metadata->>'stage' = CASE WHEN e.status = 'expired' THEN 'expired' ELSE 'expiring' END
+				)
+		`, []interface{}{leadDays}, &r.AlertsRaised},
+	}
+
+	for _, s := range steps {
+		res, err := db.ExecContext(ctx, s.query, s.args...)
+		if err != nil {
+			return &r, fmt.Errorf("%s: %w", s.name, err)
+		}
+		*s.count, _ = res.RowsAffected()
+	}
+	return &r, nil
+}
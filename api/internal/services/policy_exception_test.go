@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepPolicyExceptions_Counts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE policy_exceptions SET status = 'expired'").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE alerts a SET status = 'resolved'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO alerts.*LEFT JOIN LATERAL.*FROM policy_exception_controls ec").WithArgs(14).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	r, err := SweepPolicyExceptions(context.Background(), db, 14)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyExceptionSweepResult{Expired: 2, AlertsResolved: 1, AlertsRaised: 3}, *r)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSweepPolicyExceptions_StopsOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE policy_exceptions SET status = 'expired'").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE alerts a SET status = 'resolved'").WillReturnError(errors.New("connection reset"))
+
+	r, err := SweepPolicyExceptions(context.Background(), db, 14)
+	assert.ErrorContains(t, err, "resolve alerts")
+	assert.Equal(t, int64(2), r.Expired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,791 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// Limits on what a policy import will read.
+const (
+	MaxPolicyImportFileSize    = 10 << 20
+	MaxPolicyImportArchiveSize = 200 << 20
+	MaxPolicyImportFiles       = 200
+)
+
+// PolicySection is a heading found in imported policy content.
+type PolicySection struct {
+	Level int    `json:"level"`
+	Title string `json:"title"`
+}
+
+// ImportedPolicy is a policy document converted to HTML with the metadata inferred from it.
+// HTML is not yet sanitized; callers run it through the policy sanitizer before storing it.
+type ImportedPolicy struct {
+	Title       string          `json:"title"`
+	Identifier  string          `json:"identifier"`
+	Version     string          `json:"source_version"`
+	Description string          `json:"description"`
+	Category    string          `json:"category"`
+	Keywords    []string        `json:"keywords"`
+	HTML        string          `json:"-"`
+	Sections    []PolicySection `json:"sections"`
+	Warnings    []string        `json:"warnings"`
+}
+
+// PolicyImportFile is a document read from an import archive.
+type PolicyImportFile struct {
+	Path string
+	Data []byte
+}
+
+// IsPolicyImportFile reports whether a file name has an importable extension.
+func IsPolicyImportFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".docx", ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// ReadPolicyImportArchive returns the DOCX and Markdown files in a ZIP archive, skipping
+// directories, hidden files and macOS resource forks.
+func ReadPolicyImportArchive(data []byte) ([]PolicyImportFile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	files := []PolicyImportFile{}
+	var total int64
+	for _, f := range zr.File {
+		name := strings.ReplaceAll(f.Name, "\\", "/")
+		base := path.Base(name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") ||
+			strings.HasPrefix(base, ".") || strings.HasPrefix(base, "~$") || !IsPolicyImportFile(base) {
+			continue
+		}
+		if len(files) == MaxPolicyImportFiles {
+			return nil, fmt.Errorf("archive holds more than %d documents", MaxPolicyImportFiles)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		body, err := io.ReadAll(io.LimitReader(rc, MaxPolicyImportFileSize+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if len(body) > MaxPolicyImportFileSize {
+			return nil, fmt.Errorf("%s exceeds %d MB", name, MaxPolicyImportFileSize>>20)
+		}
+		total += int64(len(body))
+		if total > MaxPolicyImportArchiveSize {
+			return nil, fmt.Errorf("archive expands to more than %d MB", MaxPolicyImportArchiveSize>>20)
+		}
+		files = append(files, PolicyImportFile{Path: name, Data: body})
+	}
+	return files, nil
+}
+
+// ConvertPolicyDocument converts a DOCX or Markdown file to HTML and infers its metadata.
+func ConvertPolicyDocument(fileName string, data []byte) (*ImportedPolicy, error) {
+	var p *ImportedPolicy
+	var err error
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".docx":
+		p, err = convertDOCX(data)
+	case ".md", ".markdown":
+		p = convertMarkdown(string(data))
+	default:
+		return nil, fmt.Errorf("unsupported file type %q; upload .docx, .md or a .zip of them", path.Ext(fileName))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.Sections = ExtractPolicySections(p.HTML)
+	if strings.TrimSpace(stripTags(p.HTML)) == "" {
+		return nil, fmt.Errorf("document has no text")
+	}
+	if p.Title == "" {
+		for _, s := range p.Sections {
+			if s.Level == 1 {
+				p.Title = s.Title
+				break
+			}
+		}
+	}
+	if p.Title == "" {
+		base := path.Base(fileName)
+		p.Title = strings.TrimSpace(strings.NewReplacer("_", " ", "-", " ").Replace(strings.TrimSuffix(base, path.Ext(base))))
+	}
+	if p.Version == "" {
+		if m := policyVersionTextRe.FindStringSubmatch(firstChars(stripTags(p.HTML), 2000)); m != nil {
+			p.Version = m[1]
+		}
+	}
+	if c := normalizePolicyCategory(p.Category); c != "" {
+		p.Category = c
+	} else {
+		p.Category = InferPolicyCategory(p.Title)
+	}
+	if p.Keywords == nil {
+		p.Keywords = []string{}
+	}
+	if p.Warnings == nil {
+		p.Warnings = []string{}
+	}
+	return p, nil
+}
+
+var (
+	policyHeadingRe     = regexp.MustCompile(`(?is)<h([1-6])[^>]*>(.*?)</h[1-6]>`)
+	policyTagRe         = regexp.MustCompile(`<[^>]+>`)
+	policyVersionTextRe = regexp.MustCompile(`(?i)\bversion\s*(?:no\.?|number)?\s*[:#]?\s*v?(\d+(?:\.\d+){0,2})\b`)
+	frontMatterLineRe   = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9 _-]*):\s*(.*)$`)
+)
+
+// ExtractPolicySections lists the headings of HTML content in document order.
+func ExtractPolicySections(content string) []PolicySection {
+	sections := []PolicySection{}
+	for _, m := range policyHeadingRe.FindAllStringSubmatch(content, -1) {
+		level, _ := strconv.Atoi(m[1])
+		title := strings.Join(strings.Fields(html.UnescapeString(stripTags(m[2]))), " ")
+		if title != "" {
+			sections = append(sections, PolicySection{Level: level, Title: title})
+		}
+	}
+	return sections
+}
+
+func stripTags(s string) string {
+	return policyTagRe.ReplaceAllString(s, " ")
+}
+
+func firstChars(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// policyCategoryKeywords maps title keywords to categories, most specific first.
+var policyCategoryKeywords = []struct {
+	keyword  string
+	category string
+}{
+	{"acceptable use", models.PolicyCategoryAcceptableUse},
+	{"data classification", models.PolicyCategoryDataClassification},
+	{"classification", models.PolicyCategoryDataClassification},
+	{"retention", models.PolicyCategoryDataRetention},
+	{"privacy", models.PolicyCategoryDataPrivacy},
+	{"incident", models.PolicyCategoryIncidentResponse},
+	{"business continuity", models.PolicyCategoryBusinessContinuity},
+	{"disaster recovery", models.PolicyCategoryBusinessContinuity},
+	{"backup", models.PolicyCategoryBusinessContinuity},
+	{"change management", models.PolicyCategoryChangeManagement},
+	{"vulnerability", models.PolicyCategoryVulnerabilityMgmt},
+	{"patch", models.PolicyCategoryVulnerabilityMgmt},
+	{"vendor", models.PolicyCategoryVendorManagement},
+	{"supplier", models.PolicyCategoryVendorManagement},
+	{"third party", models.PolicyCategoryVendorManagement},
+	{"physical", models.PolicyCategoryPhysicalSecurity},
+	{"encryption", models.PolicyCategoryEncryption},
+	{"cryptograph", models.PolicyCategoryEncryption},
+	{"key management", models.PolicyCategoryEncryption},
+	{"network", models.PolicyCategoryNetworkSecurity},
+	{"firewall", models.PolicyCategoryNetworkSecurity},
+	{"secure development", models.PolicyCategorySecureDevelopment},
+	{"software development", models.PolicyCategorySecureDevelopment},
+	{"sdlc", models.PolicyCategorySecureDevelopment},
+	{"human resource", models.PolicyCategoryHumanResources},
+	{"personnel", models.PolicyCategoryHumanResources},
+	{"onboarding", models.PolicyCategoryHumanResources},
+	{"risk", models.PolicyCategoryRiskManagement},
+	{"asset", models.PolicyCategoryAssetManagement},
+	{"logging", models.PolicyCategoryLoggingMonitoring},
+	{"monitoring", models.PolicyCategoryLoggingMonitoring},
+	{"password", models.PolicyCategoryAccessControl},
+	{"access", models.PolicyCategoryAccessControl},
+	{"compliance", models.PolicyCategoryCompliance},
+	{"information security", models.PolicyCategoryInformationSecurity},
+	{"security", models.PolicyCategoryInformationSecurity},
+}
+
+// InferPolicyCategory guesses a policy category from its title, falling back to custom.
+func InferPolicyCategory(title string) string {
+	t := " " + strings.Join(strings.Fields(strings.ToLower(strings.NewReplacer("-", " ", "_", " ", "&", " ").Replace(title))), " ") + " "
+	for _, k := range policyCategoryKeywords {
+		if strings.Contains(t, " "+k.keyword) {
+			return k.category
+		}
+	}
+	return models.PolicyCategoryCustom
+}
+
+// normalizePolicyCategory maps a category written in a document ("Access Control") to its
+// constant, or "" when it is not one.
+func normalizePolicyCategory(s string) string {
+	c := strings.Join(strings.Fields(strings.ToLower(strings.NewReplacer("-", " ", "_", " ").Replace(s))), "_")
+	if c != "" && models.IsValidPolicyCategory(c) {
+		return c
+	}
+	return ""
+}
+
+// --- Markdown ---
+
+// convertMarkdown converts Markdown with optional front matter (title, identifier, version,
+// category, description, tags) to HTML.
+func convertMarkdown(content string) *ImportedPolicy {
+	p := &ImportedPolicy{}
+	content = strings.TrimPrefix(strings.ReplaceAll(content, "\r\n", "\n"), "\uFEFF")
+	if strings.HasPrefix(content, "---\n") {
+		if end := strings.Index(content[4:], "\n---"); end >= 0 {
+			for _, line := range strings.Split(content[4:4+end], "\n") {
+				m := frontMatterLineRe.FindStringSubmatch(strings.TrimSpace(line))
+				if m == nil {
+					continue
+				}
+				value := strings.Trim(strings.TrimSpace(m[2]), `"'`)
+				switch strings.ToLower(strings.ReplaceAll(m[1], " ", "_")) {
+				case "title":
+					p.Title = value
+				case "identifier", "id", "policy_id":
+					p.Identifier = value
+				case "version":
+					p.Version = value
+				case "category":
+					p.Category = value
+				case "description", "summary":
+					p.Description = value
+				case "tags", "keywords":
+					p.Keywords = splitKeywords(strings.Trim(value, "[]"))
+				}
+			}
+			content = content[4+end+4:]
+		}
+	}
+	p.HTML = PolicyContentHTML(content, models.ContentFormatMarkdown)
+	return p
+}
+
+func splitKeywords(s string) []string {
+	out := []string{}
+	for _, k := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if k = strings.Trim(strings.TrimSpace(k), `"'`); k != "" {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// --- DOCX ---
+
+// docxAttr returns the value of the attribute with the given local name.
+func docxAttr(e xml.StartElement, local string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// docxOn reads a WordprocessingML toggle property such as <w:b/> or <w:b w:val="0"/>.
+func docxOn(e xml.StartElement) bool {
+	switch docxAttr(e, "val") {
+	case "0", "false", "off", "none":
+		return false
+	}
+	return true
+}
+
+// readZipXML decodes an optional part of a DOCX package; a missing part is not an error.
+func readZipXML(zr *zip.Reader, name string, v interface{}) error {
+	rc, err := openZipEntry(zr, name)
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+	return nil
+}
+
+type docxStyles struct {
+	Styles []struct {
+		ID   string `xml:"styleId,attr"`
+		Name struct {
+			Val string `xml:"val,attr"`
+		} `xml:"name"`
+		BasedOn struct {
+			Val string `xml:"val,attr"`
+		} `xml:"basedOn"`
+		OutlineLvl *struct {
+			Val string `xml:"val,attr"`
+		} `xml:"pPr>outlineLvl"`
+	} `xml:"style"`
+}
+
+type docxNumbering struct {
+	Abstract []struct {
+		ID     string `xml:"abstractNumId,attr"`
+		Levels []struct {
+			Ilvl   string `xml:"ilvl,attr"`
+			NumFmt struct {
+				Val string `xml:"val,attr"`
+			} `xml:"numFmt"`
+		} `xml:"lvl"`
+	} `xml:"abstractNum"`
+	Nums []struct {
+		ID       string `xml:"numId,attr"`
+		Abstract struct {
+			Val string `xml:"val,attr"`
+		} `xml:"abstractNumId"`
+	} `xml:"num"`
+}
+
+type docxRelationships struct {
+	Rels []struct {
+		ID         string `xml:"Id,attr"`
+		Target     string `xml:"Target,attr"`
+		TargetMode string `xml:"TargetMode,attr"`
+	} `xml:"Relationship"`
+}
+
+type docxCoreProps struct {
+	Title       string `xml:"title"`
+	Subject     string `xml:"subject"`
+	Description string `xml:"description"`
+	Keywords    string `xml:"keywords"`
+	Category    string `xml:"category"`
+	Version     string `xml:"version"`
+}
+
+type docxCustomProps struct {
+	Props []struct {
+		Name  string `xml:"name,attr"`
+		Value struct {
+			Text string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"property"`
+}
+
+// docxConverter holds what document.xml needs from the rest of the package.
+type docxConverter struct {
+	headingLevels map[string]int // style ID -> heading level, -1 for the Title style
+	listOrdered   map[string]bool
+	links         map[string]string
+	images        int
+	trackedDel    int
+}
+
+func newDOCXConverter(zr *zip.Reader) (*docxConverter, error) {
+	d := &docxConverter{headingLevels: map[string]int{}, listOrdered: map[string]bool{}, links: map[string]string{}}
+
+	var styles docxStyles
+	if err := readZipXML(zr, "word/styles.xml", &styles); err != nil {
+		return nil, err
+	}
+	headingNameRe := regexp.MustCompile(`^heading\s*([1-9])$`)
+	basedOn := map[string]string{}
+	for _, s := range styles.Styles {
+		name := strings.ToLower(strings.TrimSpace(s.Name.Val))
+		switch {
+		case name == "title":
+			d.headingLevels[s.ID] = -1
+		case headingNameRe.MatchString(name):
+			n, _ := strconv.Atoi(headingNameRe.FindStringSubmatch(name)[1])
+			d.headingLevels[s.ID] = n
+		case s.OutlineLvl != nil:
+			if n, err := strconv.Atoi(s.OutlineLvl.Val); err == nil && n < 9 {
+				d.headingLevels[s.ID] = n + 1
+			}
+		}
+		if s.BasedOn.Val != "" {
+			basedOn[s.ID] = s.BasedOn.Val
+		}
+	}
+	// Custom heading styles usually derive from a built-in one.
+	for id, parent := range basedOn {
+		if _, ok := d.headingLevels[id]; ok {
+			continue
+		}
+		for i := 0; i < 5 && parent != ""; i++ {
+			if lvl, ok := d.headingLevels[parent]; ok {
+				d.headingLevels[id] = lvl
+				break
+			}
+			parent = basedOn[parent]
+		}
+	}
+
+	var numbering docxNumbering
+	if err := readZipXML(zr, "word/numbering.xml", &numbering); err != nil {
+		return nil, err
+	}
+	abstractOrdered := map[string]bool{}
+	for _, a := range numbering.Abstract {
+		for _, l := range a.Levels {
+			abstractOrdered[a.ID+"/"+l.Ilvl] = l.NumFmt.Val != "bullet" && l.NumFmt.Val != "none" && l.NumFmt.Val != ""
+		}
+	}
+	for _, n := range numbering.Nums {
+		for i := 0; i < 9; i++ {
+			lvl := strconv.Itoa(i)
+			d.listOrdered[n.ID+"/"+lvl] = abstractOrdered[n.Abstract.Val+"/"+lvl]
+		}
+	}
+
+	var rels docxRelationships
+	if err := readZipXML(zr, "word/_rels/document.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	for _, r := range rels.Rels {
+		t := strings.ToLower(r.Target)
+		if r.TargetMode == "External" && (strings.HasPrefix(t, "http://") || strings.HasPrefix(t, "https://") || strings.HasPrefix(t, "mailto:")) {
+			d.links[r.ID] = r.Target
+		}
+	}
+	return d, nil
+}
+
+// docxParagraph collects one w:p.
+type docxParagraph struct {
+	style   string
+	outline int // w:outlineLvl + 1, 0 when unset
+	numID   string
+	ilvl    int
+	inNum   bool
+	body    strings.Builder
+}
+
+// docxRun collects one w:r.
+type docxRun struct {
+	bold, italic, underline, strike bool
+	text                            strings.Builder
+}
+
+func (r *docxRun) html() string {
+	s := r.text.String()
+	if s == "" {
+		return ""
+	}
+	s = strings.ReplaceAll(html.EscapeString(s), "\n", "<br>")
+	if r.strike {
+		s = "<s>" + s + "</s>"
+	}
+	if r.underline {
+		s = "<u>" + s + "</u>"
+	}
+	if r.italic {
+		s = "<em>" + s + "</em>"
+	}
+	if r.bold {
+		s = "<strong>" + s + "</strong>"
+	}
+	return s
+}
+
+// convertDOCX converts word/document.xml to HTML: headings, paragraphs, bold/italic/underline
+// runs, numbered and bulleted lists (nested by level), tables and external hyperlinks. Images,
+// field codes and tracked deletions are dropped.
+func convertDOCX(data []byte) (*ImportedPolicy, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open docx: %w", err)
+	}
+	conv, err := newDOCXConverter(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ImportedPolicy{}
+	var core docxCoreProps
+	if err := readZipXML(zr, "docProps/core.xml", &core); err != nil {
+		return nil, err
+	}
+	p.Title = strings.TrimSpace(core.Title)
+	p.Description = strings.TrimSpace(core.Description)
+	if p.Description == "" {
+		p.Description = strings.TrimSpace(core.Subject)
+	}
+	p.Keywords = splitKeywords(core.Keywords)
+	p.Category = strings.TrimSpace(core.Category)
+	p.Version = strings.TrimSpace(core.Version)
+
+	var custom docxCustomProps
+	if err := readZipXML(zr, "docProps/custom.xml", &custom); err != nil {
+		return nil, err
+	}
+	for _, prop := range custom.Props {
+		value := strings.TrimSpace(prop.Value.Text)
+		if value == "" {
+			continue
+		}
+		switch strings.ToLower(strings.Join(strings.Fields(prop.Name), " ")) {
+		case "version", "document version", "policy version":
+			p.Version = value
+		case "identifier", "policy id", "document id", "policy number", "document number":
+			p.Identifier = value
+		case "category", "policy category":
+			p.Category = value
+		}
+	}
+
+	rc, err := openZipEntry(zr, "word/document.xml")
+	if err != nil {
+		return nil, fmt.Errorf("open docx: %w", err)
+	}
+	defer rc.Close()
+
+	body, titleText, err := conv.convertBody(xml.NewDecoder(rc))
+	if err != nil {
+		return nil, err
+	}
+	p.HTML = body
+	if p.Title == "" {
+		p.Title = titleText
+	}
+	if conv.images > 0 {
+		p.Warnings = append(p.Warnings, fmt.Sprintf("%d image(s) were not imported", conv.images))
+	}
+	if conv.trackedDel > 0 {
+		p.Warnings = append(p.Warnings, "tracked deletions were dropped; accept or reject changes before importing to be sure")
+	}
+	return p, nil
+}
+
+func (d *docxConverter) convertBody(dec *xml.Decoder) (string, string, error) {
+	var out strings.Builder
+	var listStack []string // open list tags, one per nesting level
+	var title string
+
+	var para *docxParagraph
+	var run *docxRun
+	var link string
+	inLink := false
+	inText := false
+	skipDepth := 0 // > 0 while inside an element whose content is dropped
+	tableDepth := 0
+	cellParas := 0
+
+	closeLists := func(depth int) {
+		for len(listStack) > depth {
+			out.WriteString("</li></" + listStack[len(listStack)-1] + ">\n")
+			listStack = listStack[:len(listStack)-1]
+		}
+	}
+
+	endParagraph := func() {
+		content := strings.TrimSpace(para.body.String())
+		level := d.headingLevels[para.style]
+		if para.outline > 0 {
+			level = para.outline
+		}
+		switch {
+		case tableDepth > 0:
+			if content != "" {
+				if cellParas > 0 {
+					out.WriteString("<br>")
+				}
+				out.WriteString(content)
+				cellParas++
+			}
+		case content == "":
+		case level == -1:
+			closeLists(0)
+			if title == "" {
+				title = strings.Join(strings.Fields(html.UnescapeString(stripTags(content))), " ")
+			}
+			out.WriteString("<h1>" + content + "</h1>\n")
+		case level > 0:
+			closeLists(0)
+			if level > 6 {
+				level = 6
+			}
+			fmt.Fprintf(&out, "<h%d>%s</h%d>\n", level, content, level)
+		case para.inNum:
+			depth := para.ilvl + 1
+			tag := "ul"
+			if d.listOrdered[para.numID+"/"+strconv.Itoa(para.ilvl)] {
+				tag = "ol"
+			}
+			if len(listStack) >= depth {
+				closeLists(depth)
+				if listStack[depth-1] != tag {
+					closeLists(depth - 1)
+				} else {
+					out.WriteString("</li>\n")
+				}
+			}
+			for len(listStack) < depth {
+				out.WriteString("<" + tag + ">\n")
+				listStack = append(listStack, tag)
+				if len(listStack) < depth {
+					out.WriteString("<li>")
+				}
+			}
+			out.WriteString("<li>" + content)
+		default:
+			closeLists(0)
+			out.WriteString("<p>" + content + "</p>\n")
+		}
+		para = nil
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("parse docx: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if skipDepth > 0 {
+				skipDepth++
+				continue
+			}
+			switch t.Name.Local {
+			case "del", "instrText", "delText", "Fallback", "footnoteReference", "endnoteReference", "commentReference":
+				if t.Name.Local == "del" {
+					d.trackedDel++
+				}
+				skipDepth = 1
+			case "drawing", "pict", "object":
+				d.images++
+				skipDepth = 1
+			case "tbl":
+				if tableDepth == 0 {
+					closeLists(0)
+					out.WriteString("<table>\n")
+				}
+				tableDepth++
+			case "tr":
+				if tableDepth == 1 {
+					out.WriteString("<tr>")
+				}
+			case "tc":
+				if tableDepth == 1 {
+					out.WriteString("<td>")
+					cellParas = 0
+				}
+			case "p":
+				para = &docxParagraph{}
+			case "pStyle":
+				if para != nil {
+					para.style = docxAttr(t, "val")
+				}
+			case "outlineLvl":
+				if para != nil {
+					if n, err := strconv.Atoi(docxAttr(t, "val")); err == nil && n < 9 {
+						para.outline = n + 1
+					}
+				}
+			case "numId":
+				if para != nil {
+					para.numID = docxAttr(t, "val")
+					para.inNum = para.numID != "0"
+				}
+			case "ilvl":
+				if para != nil {
+					para.ilvl, _ = strconv.Atoi(docxAttr(t, "val"))
+					if para.ilvl > 8 {
+						para.ilvl = 8
+					}
+				}
+			case "hyperlink":
+				link = d.links[docxAttr(t, "id")]
+				inLink = true
+				if para != nil && link != "" {
+					para.body.WriteString(`<a href="` + html.EscapeString(link) + `">`)
+				}
+			case "r":
+				run = &docxRun{}
+			case "b":
+				if run != nil {
+					run.bold = docxOn(t)
+				}
+			case "i":
+				if run != nil {
+					run.italic = docxOn(t)
+				}
+			case "u":
+				if run != nil {
+					run.underline = docxOn(t)
+				}
+			case "strike", "dstrike":
+				if run != nil {
+					run.strike = docxOn(t)
+				}
+			case "t":
+				inText = true
+			case "tab":
+				if run != nil {
+					run.text.WriteString(" ")
+				}
+			case "br", "cr":
+				if run != nil {
+					run.text.WriteString("\n")
+				}
+			}
+		case xml.EndElement:
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "r":
+				if run != nil && para != nil {
+					para.body.WriteString(run.html())
+				}
+				run = nil
+			case "hyperlink":
+				if para != nil && inLink && link != "" {
+					para.body.WriteString("</a>")
+				}
+				inLink = false
+				link = ""
+			case "p":
+				if para != nil {
+					endParagraph()
+				}
+			case "tc":
+				if tableDepth == 1 {
+					out.WriteString("</td>")
+				}
+			case "tr":
+				if tableDepth == 1 {
+					out.WriteString("</tr>\n")
+				}
+			case "tbl":
+				tableDepth--
+				if tableDepth == 0 {
+					out.WriteString("</table>\n")
+				}
+			}
+		case xml.CharData:
+			if inText && skipDepth == 0 && run != nil {
+				run.text.Write(t)
+			}
+		}
+	}
+	closeLists(0)
+	return out.String(), title, nil
+}
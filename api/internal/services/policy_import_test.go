@@ -0,0 +1,148 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+const testDOCXNamespaces = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+func buildTestDOCX(t *testing.T) []byte {
+	return buildTestZip(t, map[string]string{
+		"docProps/core.xml": `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">
+			<dc:title>Remote Access Policy</dc:title><dc:subject>How staff connect remotely</dc:subject>
+			<cp:keywords>vpn, remote; mfa</cp:keywords></cp:coreProperties>`,
+		"docProps/custom.xml": `<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes">
+			<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="2" name="Policy ID"><vt:lpwstr>POL-RA-007</vt:lpwstr></property>
+			<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="3" name="Version"><vt:lpwstr>3.2</vt:lpwstr></property></Properties>`,
+		"word/styles.xml": `<w:styles ` + testDOCXNamespaces + `>
+			<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/></w:style>
+			<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/></w:style>
+			<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/></w:style>
+			<w:style w:type="paragraph" w:styleId="PolicyClause"><w:name w:val="Policy Clause"/><w:basedOn w:val="Heading2"/></w:style>
+			</w:styles>`,
+		"word/numbering.xml": `<w:numbering ` + testDOCXNamespaces + `>
+			<w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0"><w:numFmt w:val="bullet"/></w:lvl><w:lvl w:ilvl="1"><w:numFmt w:val="decimal"/></w:lvl></w:abstractNum>
+			<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num></w:numbering>`,
+		"word/_rels/document.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId5" Type="hyperlink" Target="https://intranet.example.com/vpn" TargetMode="External"/>
+			<Relationship Id="rId6" Type="hyperlink" Target="javascript:alert(1)" TargetMode="External"/></Relationships>`,
+		"word/document.xml": `<w:document ` + testDOCXNamespaces + `><w:body>
+			<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t>Remote Access</w:t></w:r></w:p>
+			<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>1. Purpose</w:t></w:r></w:p>
+			<w:p><w:r><w:t xml:space="preserve">Staff </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>must</w:t></w:r><w:r><w:rPr><w:b w:val="0"/></w:rPr><w:t xml:space="preserve"> use the </w:t></w:r><w:hyperlink r:id="rId5"><w:r><w:t>VPN</w:t></w:r></w:hyperlink><w:del><w:r><w:delText>old text</w:delText></w:r></w:del><w:r><w:t>.</w:t></w:r></w:p>
+			<w:p><w:pPr><w:pStyle w:val="PolicyClause"/></w:pPr><w:r><w:t>Requirements</w:t></w:r></w:p>
+			<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Use MFA</w:t></w:r></w:p>
+			<w:p><w:pPr><w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Hardware keys</w:t></w:r></w:p>
+			<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:hyperlink r:id="rId6"><w:r><w:t>Log off</w:t></w:r></w:hyperlink></w:p>
+			<w:p><w:r><w:drawing><w:t>ignored</w:t></w:drawing></w:r><w:r><w:fldChar/><w:instrText>PAGE</w:instrText></w:r></w:p>
+			<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Role</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Access</w:t></w:r></w:p><w:p><w:r><w:t>Review</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+			</w:body></w:document>`,
+	})
+}
+
+func TestConvertPolicyDocument_DOCX(t *testing.T) {
+	p, err := ConvertPolicyDocument("remote-access.docx", buildTestDOCX(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Remote Access Policy", p.Title, "document properties win over the Title style")
+	assert.Equal(t, "POL-RA-007", p.Identifier)
+	assert.Equal(t, "3.2", p.Version)
+	assert.Equal(t, "How staff connect remotely", p.Description)
+	assert.Equal(t, []string{"vpn", "remote", "mfa"}, p.Keywords)
+	assert.Equal(t, models.PolicyCategoryAccessControl, p.Category)
+
+	assert.Contains(t, p.HTML, "<h1>Remote Access</h1>")
+	assert.Contains(t, p.HTML, `<p>Staff <strong>must</strong> use the <a href="https://intranet.example.com/vpn">VPN</a>.</p>`)
+	assert.Contains(t, p.HTML, "<h2>Requirements</h2>", "styles based on a heading are headings")
+	assert.Contains(t, p.HTML, "<ul>\n<li>Use MFA<ol>\n<li>Hardware keys</li></ol>\n</li>\n<li>Log off</li></ul>")
+	assert.Contains(t, p.HTML, "<table>\n<tr><td>Role</td><td>Access<br>Review</td></tr>\n</table>")
+	assert.NotContains(t, p.HTML, "old text")
+	assert.NotContains(t, p.HTML, "PAGE")
+	assert.NotContains(t, p.HTML, "ignored")
+	assert.NotContains(t, p.HTML, "javascript")
+
+	assert.Equal(t, []PolicySection{{1, "Remote Access"}, {1, "1. Purpose"}, {2, "Requirements"}}, p.Sections)
+	assert.Len(t, p.Warnings, 2)
+}
+
+func TestConvertPolicyDocument_Markdown(t *testing.T) {
+	md := "---\ntitle: \"Data Retention Standard\"\nidentifier: POL-DR-002\nversion: 1.4\ncategory: Data Retention\ntags: [records, legal]\n---\n" +
+		"# Data Retention\n\nRecords are kept for **seven** years.\n\n## Exceptions\n\n- Legal hold\n"
+
+	p, err := ConvertPolicyDocument("retention.md", []byte(md))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Data Retention Standard", p.Title)
+	assert.Equal(t, "POL-DR-002", p.Identifier)
+	assert.Equal(t, "1.4", p.Version)
+	assert.Equal(t, models.PolicyCategoryDataRetention, p.Category)
+	assert.Equal(t, []string{"records", "legal"}, p.Keywords)
+	assert.Contains(t, p.HTML, "<strong>seven</strong>")
+	assert.Equal(t, []PolicySection{{1, "Data Retention"}, {2, "Exceptions"}}, p.Sections)
+}
+
+func TestConvertPolicyDocument_InfersFromContent(t *testing.T) {
+	p, err := ConvertPolicyDocument("vendor_risk.md", []byte("Document version: 2.0\n\nSuppliers are assessed yearly."))
+	require.NoError(t, err)
+	assert.Equal(t, "vendor risk", p.Title, "falls back to the file name")
+	assert.Equal(t, "2.0", p.Version)
+	assert.Equal(t, models.PolicyCategoryVendorManagement, p.Category)
+
+	_, err = ConvertPolicyDocument("empty.md", []byte("\n\n"))
+	assert.Error(t, err)
+	_, err = ConvertPolicyDocument("policy.pdf", []byte("%PDF-1.4"))
+	assert.Error(t, err)
+	_, err = ConvertPolicyDocument("broken.docx", []byte("not a zip"))
+	assert.Error(t, err)
+}
+
+func TestInferPolicyCategory(t *testing.T) {
+	assert.Equal(t, models.PolicyCategoryAcceptableUse, InferPolicyCategory("Acceptable Use Policy"))
+	assert.Equal(t, models.PolicyCategoryBusinessContinuity, InferPolicyCategory("Backup & Restore"))
+	assert.Equal(t, models.PolicyCategoryInformationSecurity, InferPolicyCategory("Information Security Policy"))
+	assert.Equal(t, models.PolicyCategoryCustom, InferPolicyCategory("Travel Expenses"))
+}
+
+func TestReadPolicyImportArchive(t *testing.T) {
+	data := buildTestZip(t, map[string]string{
+		"Policies/HR/onboarding.md":       "# Onboarding",
+		"Policies/access.docx":            "docx bytes",
+		"Policies/readme.txt":             "skip",
+		"__MACOSX/Policies/._access.docx": "fork",
+		"Policies/.hidden.md":             "skip",
+		"Policies/~$access.docx":          "lock file",
+		"Policies/Archive/":               "",
+	})
+
+	files, err := ReadPolicyImportArchive(data)
+	require.NoError(t, err)
+	paths := []string{}
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	assert.ElementsMatch(t, []string{"Policies/HR/onboarding.md", "Policies/access.docx"}, paths)
+
+	_, err = ReadPolicyImportArchive([]byte("nope"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// US Letter in points, with 0.75in margins.
+const (
+	pdfPageWidth    = 612.0
+	pdfPageHeight   = 792.0
+	pdfMargin       = 54.0
+	pdfFooterHeight = 28.0
+	pdfContentWidth = pdfPageWidth - 2*pdfMargin
+)
+
+// pdfFont is one of the standard Type 1 fonts every PDF reader provides, so nothing is embedded.
+type pdfFont int
+
+const (
+	pdfRegular pdfFont = iota
+	pdfBold
+	pdfItalic
+)
+
+var pdfFontNames = []string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique"}
+
+// Glyph widths (1/1000 em) for WinAnsi codes 32-126, from the Adobe core font metrics.
+// Helvetica-Oblique shares Helvetica's widths.
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// winAnsiExtras maps the non-Latin-1 characters policies commonly contain to WinAnsiEncoding.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// winAnsiWidths are the widths of the extras; other codes above 126 use an average width.
+var winAnsiWidths = map[byte]int{
+	0x80: 556, 0x82: 222, 0x84: 333, 0x85: 1000, 0x91: 222, 0x92: 222,
+	0x93: 333, 0x94: 333, 0x95: 350, 0x96: 556, 0x97: 1000, 0x99: 1000,
+}
+
+// toWinAnsi encodes s for the standard fonts. Characters outside WinAnsiEncoding become '?'.
+func toWinAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 32 && r <= 126:
+			out = append(out, byte(r))
+		case r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiExtras[r]; ok {
+				out = append(out, b)
+			} else if r >= 32 {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// pdfTextWidth measures s in points.
+func pdfTextWidth(f pdfFont, size float64, s string) float64 {
+	widths := &helveticaWidths
+	if f == pdfBold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, b := range toWinAnsi(s) {
+		switch {
+		case b >= 32 && b <= 126:
+			total += widths[b-32]
+		case winAnsiWidths[b] > 0:
+			total += winAnsiWidths[b]
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// pdfWrap breaks s into lines no wider than width, splitting words that do not fit on a line.
+func pdfWrap(f pdfFont, size float64, s string, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if pdfTextWidth(f, size, candidate) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+			line = ""
+		}
+		for pdfTextWidth(f, size, word) > width {
+			runes := []rune(word)
+			n := len(runes) - 1
+			for n > 1 && pdfTextWidth(f, size, string(runes[:n])) > width {
+				n--
+			}
+			lines = append(lines, string(runes[:n]))
+			word = string(runes[n:])
+		}
+		line = word
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfEscape writes a PDF literal string.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range toWinAnsi(s) {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c > 126:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// pdfColor is an RGB colour with components in 0-1.
+type pdfColor struct{ R, G, B float64 }
+
+var (
+	pdfBlack = pdfColor{0.13, 0.13, 0.13}
+	pdfGrey  = pdfColor{0.45, 0.45, 0.45}
+	pdfLight = pdfColor{0.94, 0.94, 0.94}
+	pdfWhite = pdfColor{1, 1, 1}
+)
+
+// parseHexColor parses #RRGGBB, falling back to def.
+func parseHexColor(s string, def pdfColor) pdfColor {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(strings.TrimSpace(s), "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return def
+	}
+	return pdfColor{float64(r) / 255, float64(g) / 255, float64(b) / 255}
+}
+
+// pdfLayout lays out flowing text, tables and rules top to bottom, starting a new page when the
+// current one is full.
+type pdfLayout struct {
+	pages []*bytes.Buffer
+	y     float64
+	// onNewPage runs after a page break caused by flowing content (e.g. to repeat a table header).
+	onNewPage func()
+}
+
+func (l *pdfLayout) cur() *bytes.Buffer { return l.pages[len(l.pages)-1] }
+
+func (l *pdfLayout) newPage() {
+	l.pages = append(l.pages, &bytes.Buffer{})
+	l.y = pdfPageHeight - pdfMargin
+}
+
+// ensure starts a new page unless h points fit above the footer.
+func (l *pdfLayout) ensure(h float64) {
+	if len(l.pages) == 0 || l.y-h < pdfMargin+pdfFooterHeight {
+		l.newPage()
+		if l.onNewPage != nil {
+			l.onNewPage()
+		}
+	}
+}
+
+func (l *pdfLayout) text(f pdfFont, size float64, col pdfColor, x, y float64, s string) {
+	fmt.Fprintf(l.cur(), "BT %.3f %.3f %.3f rg /F%d %.1f Tf %.2f %.2f Td %s Tj ET\n",
+		col.R, col.G, col.B, int(f)+1, size, x, y, pdfEscape(s))
+}
+
+func (l *pdfLayout) rect(x, y, w, h float64, col pdfColor) {
+	fmt.Fprintf(l.cur(), "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n", col.R, col.G, col.B, x, y, w, h)
+}
+
+func (l *pdfLayout) rule(col pdfColor) {
+	fmt.Fprintf(l.cur(), "%.3f %.3f %.3f RG 0.5 w %.2f %.2f m %.2f %.2f l S\n",
+		col.R, col.G, col.B, pdfMargin, l.y, pdfPageWidth-pdfMargin, l.y)
+}
+
+// paragraph flows wrapped text at the given indent, then leaves space after it.
+func (l *pdfLayout) paragraph(f pdfFont, size float64, col pdfColor, indent float64, s string, after float64) {
+	leading := size * 1.4
+	for _, line := range pdfWrap(f, size, s, pdfContentWidth-indent) {
+		l.ensure(leading)
+		l.y -= leading
+		l.text(f, size, col, pdfMargin+indent, l.y+size*0.3, line)
+	}
+	l.y -= after
+}
+
+// heading keeps at least two lines of following text on the same page.
+func (l *pdfLayout) heading(size float64, col pdfColor, s string) {
+	l.ensure(size*1.6 + 30)
+	l.y -= 6
+	l.paragraph(pdfBold, size, col, 0, s, 4)
+}
+
+// table draws a header row in the accent colour and wrapped body rows; the header repeats on
+// each page the table spans. widths are fractions of the content width.
+func (l *pdfLayout) table(accent pdfColor, widths []float64, header []string, rows [][]string) {
+	const size, pad = 8.5, 4.0
+	leading := size * 1.35
+	colX := make([]float64, len(widths))
+	colW := make([]float64, len(widths))
+	x := pdfMargin
+	for i, w := range widths {
+		colX[i], colW[i] = x, w*pdfContentWidth
+		x += colW[i]
+	}
+
+	drawRow := func(f pdfFont, col pdfColor, fill *pdfColor, cells []string) {
+		wrapped := make([][]string, len(cells))
+		lines := 1
+		for i, cell := range cells {
+			wrapped[i] = pdfWrap(f, size, cell, colW[i]-2*pad)
+			if len(wrapped[i]) > lines {
+				lines = len(wrapped[i])
+			}
+		}
+		h := float64(lines)*leading + 2*pad
+		l.ensure(h)
+		if fill != nil {
+			l.rect(pdfMargin, l.y-h, pdfContentWidth, h, *fill)
+		}
+		for i := range cells {
+			for j, line := range wrapped[i] {
+				l.text(f, size, col, colX[i]+pad, l.y-pad-float64(j+1)*leading+size*0.3, line)
+			}
+		}
+		l.y -= h
+	}
+	drawHeader := func() { drawRow(pdfBold, pdfWhite, &accent, header) }
+
+	l.ensure(3 * leading)
+	drawHeader()
+	l.onNewPage = drawHeader
+	for i, row := range rows {
+		var fill *pdfColor
+		if i%2 == 1 {
+			fill = &pdfLight
+		}
+		drawRow(pdfRegular, pdfBlack, fill, row)
+	}
+	l.onNewPage = nil
+	l.y -= 12
+}
+
+// pdfObjectWriter assembles numbered objects and the cross-reference table.
+type pdfObjectWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (w *pdfObjectWriter) object(body string) int {
+	w.offsets = append(w.offsets, w.buf.Len())
+	n := len(w.offsets)
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", n, body)
+	return n
+}
+
+func (w *pdfObjectWriter) stream(dict string, data []byte) int {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write(data)
+	zw.Close()
+	w.offsets = append(w.offsets, w.buf.Len())
+	n := len(w.offsets)
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< %s /Length %d /Filter /FlateDecode >>\nstream\n", n, dict, z.Len())
+	w.buf.Write(z.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return n
+}
+
+// pdfDate formats t as a PDF date string.
+func pdfDate(t time.Time) string {
+	return "D:" + t.UTC().Format("20060102150405") + "Z"
+}
+
+// writePDF serializes laid-out pages. footer is called for each page with its number and the
+// page count so it can draw page furniture into the page's stream.
+func writePDF(pages []*bytes.Buffer, title, author string, created time.Time, footer func(l *pdfLayout, page, total int)) []byte {
+	w := &pdfObjectWriter{}
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Fixed object numbers: 1 catalog, 2 page tree, 3-5 fonts, 6 info; pages follow.
+	pageRefs := make([]string, len(pages))
+	for i := range pages {
+		pageRefs[i] = fmt.Sprintf("%d 0 R", 7+2*i)
+	}
+	w.object("<< /Type /Catalog /Pages 2 0 R >>")
+	w.object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageRefs, " "), len(pages)))
+	for _, name := range pdfFontNames {
+		w.object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	w.object(fmt.Sprintf("<< /Title %s /Author %s /Producer (Raisin Protect) /CreationDate (%s) >>",
+		pdfEscape(title), pdfEscape(author), pdfDate(created)))
+
+	for i, content := range pages {
+		if footer != nil {
+			fl := &pdfLayout{pages: []*bytes.Buffer{content}}
+			footer(fl, i+1, len(pages))
+		}
+		w.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 8+2*i))
+		w.stream("", content.Bytes())
+	}
+
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, off := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, xref)
+	return w.buf.Bytes()
+}
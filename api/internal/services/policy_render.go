@@ -0,0 +1,395 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// PolicyBranding is an org's document branding, kept under organizations.settings.branding.
+type PolicyBranding struct {
+	DisplayName    string `json:"display_name"`
+	PrimaryColor   string `json:"primary_color"`
+	FooterText     string `json:"footer_text"`
+	Classification string `json:"classification"`
+}
+
+// defaultPolicyColor is used when the org has not set a valid #RRGGBB primary colour.
+const defaultPolicyColor = "#1F3A5F"
+
+// PolicyDocumentVersion is one row of a rendered policy's version history.
+type PolicyDocumentVersion struct {
+	VersionNumber int
+	ChangeType    string
+	ChangeSummary string
+	Author        string
+	CreatedAt     time.Time
+	PublishedAt   *time.Time
+}
+
+// PolicyDocumentSignoff is one approver decision on the rendered version.
+type PolicyDocumentSignoff struct {
+	Name      string
+	Role      string
+	Status    string
+	DecidedAt *time.Time
+	Comments  string
+}
+
+// PolicyDocument is everything needed to render one policy version.
+type PolicyDocument struct {
+	OrgName          string
+	Branding         PolicyBranding
+	PolicyIdentifier string
+	Title            string
+	Description      string
+	Category         string
+	OwnerName        string
+	VersionNumber    int
+	Content          string
+	ContentFormat    string
+	// BodyHTML is the content as sanitized HTML, used by the HTML rendering.
+	BodyHTML      template.HTML
+	EffectiveDate *time.Time
+	NextReviewAt  *time.Time
+	Versions      []PolicyDocumentVersion
+	Signoffs      []PolicyDocumentSignoff
+	GeneratedAt   time.Time
+}
+
+func (d *PolicyDocument) orgDisplayName() string {
+	if d.Branding.DisplayName != "" {
+		return d.Branding.DisplayName
+	}
+	return d.OrgName
+}
+
+func (d *PolicyDocument) primaryColor() string {
+	if c := strings.TrimSpace(d.Branding.PrimaryColor); hexColorRe.MatchString(c) {
+		return c
+	}
+	return defaultPolicyColor
+}
+
+func (d *PolicyDocument) effectiveDate() string {
+	if d.EffectiveDate == nil {
+		return "Not yet published"
+	}
+	return d.EffectiveDate.Format("January 2, 2006")
+}
+
+func (d *PolicyDocument) metadataRows() [][2]string {
+	rows := [][2]string{
+		{"Policy", d.PolicyIdentifier},
+		{"Version", fmt.Sprintf("%d", d.VersionNumber)},
+		{"Effective date", d.effectiveDate()},
+		{"Category", strings.ReplaceAll(d.Category, "_", " ")},
+	}
+	if d.OwnerName != "" {
+		rows = append(rows, [2]string{"Owner", d.OwnerName})
+	}
+	if d.NextReviewAt != nil {
+		rows = append(rows, [2]string{"Next review", d.NextReviewAt.Format("January 2, 2006")})
+	}
+	if d.Branding.Classification != "" {
+		rows = append(rows, [2]string{"Classification", d.Branding.Classification})
+	}
+	return rows
+}
+
+func formatDocDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+func (d *PolicyDocument) versionRows() [][]string {
+	rows := make([][]string, 0, len(d.Versions))
+	for _, v := range d.Versions {
+		created := v.CreatedAt
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", v.VersionNumber), v.ChangeType, formatDocDate(&created),
+			formatDocDate(v.PublishedAt), v.Author, v.ChangeSummary,
+		})
+	}
+	return rows
+}
+
+func (d *PolicyDocument) signoffRows() [][]string {
+	rows := make([][]string, 0, len(d.Signoffs))
+	for _, s := range d.Signoffs {
+		rows = append(rows, []string{
+			s.Name, strings.ReplaceAll(s.Role, "_", " "), s.Status, formatDocDate(s.DecidedAt), s.Comments,
+		})
+	}
+	return rows
+}
+
+var (
+	policyVersionHeader = []string{"Version", "Change", "Created", "Published", "Author", "Summary"}
+	policySignoffHeader = []string{"Approver", "Role", "Decision", "Date", "Comments"}
+)
+
+// RenderPolicyPDF renders a policy version as a branded PDF: a cover page with the document
+// metadata, the version history and approvals, then the policy text section by section.
+func RenderPolicyPDF(d *PolicyDocument) []byte {
+	accent := parseHexColor(d.primaryColor(), pdfColor{})
+	l := &pdfLayout{}
+
+	// Cover
+	l.newPage()
+	band := 150.0
+	l.rect(0, pdfPageHeight-band, pdfPageWidth, band, accent)
+	l.text(pdfBold, 16, pdfWhite, pdfMargin, pdfPageHeight-70, d.orgDisplayName())
+	if d.Branding.Classification != "" {
+		l.text(pdfRegular, 10, pdfWhite, pdfMargin, pdfPageHeight-90, strings.ToUpper(d.Branding.Classification))
+	}
+	l.y = pdfPageHeight - band - 60
+	l.paragraph(pdfBold, 26, pdfBlack, 0, d.Title, 6)
+	if d.Description != "" {
+		l.paragraph(pdfItalic, 11, pdfGrey, 0, d.Description, 12)
+	}
+	l.y -= 12
+	for _, row := range d.metadataRows() {
+		l.y -= 18
+		l.text(pdfBold, 10, pdfGrey, pdfMargin, l.y, row[0])
+		l.text(pdfRegular, 11, pdfBlack, pdfMargin+110, l.y, row[1])
+	}
+	l.y -= 30
+	l.rule(accent)
+	l.y -= 16
+	l.text(pdfRegular, 9, pdfGrey, pdfMargin, l.y, "Generated "+d.GeneratedAt.UTC().Format("2006-01-02 15:04 UTC"))
+
+	// Version history and approvals
+	l.newPage()
+	l.heading(16, accent, "Version history")
+	l.table(accent, []float64{0.09, 0.1, 0.13, 0.13, 0.2, 0.35}, policyVersionHeader, d.versionRows())
+	l.heading(16, accent, fmt.Sprintf("Approvals for version %d", d.VersionNumber))
+	if len(d.Signoffs) == 0 {
+		l.paragraph(pdfItalic, 10, pdfGrey, 0, "No sign-offs were recorded for this version.", 8)
+	} else {
+		l.table(accent, []float64{0.24, 0.2, 0.13, 0.13, 0.3}, policySignoffHeader, d.signoffRows())
+	}
+
+	// Policy text
+	l.newPage()
+	l.paragraph(pdfBold, 20, pdfBlack, 0, d.Title, 10)
+	for _, sec := range splitPolicyContent(d.Content, d.ContentFormat) {
+		if sec.title != "" {
+			l.heading(13, accent, sec.title)
+		}
+		for _, p := range sec.paras {
+			indent := 0.0
+			if m := mdListItemRe.FindString(p); m != "" {
+				p, indent = "•  "+strings.TrimPrefix(p, m), 12
+			}
+			l.paragraph(pdfRegular, 10.5, pdfBlack, indent, p, 6)
+		}
+	}
+
+	footerLeft := fmt.Sprintf("%s v%d", d.PolicyIdentifier, d.VersionNumber)
+	if d.Branding.FooterText != "" {
+		footerLeft += "  |  " + d.Branding.FooterText
+	}
+	return writePDF(l.pages, d.Title, d.orgDisplayName(), d.GeneratedAt, func(fl *pdfLayout, page, total int) {
+		if page == 1 {
+			return
+		}
+		fl.y = pdfMargin + 14
+		fl.rule(pdfLight)
+		fl.text(pdfRegular, 8, pdfGrey, pdfMargin, pdfMargin, footerLeft)
+		right := fmt.Sprintf("Page %d of %d", page, total)
+		fl.text(pdfRegular, 8, pdfGrey, pdfPageWidth-pdfMargin-pdfTextWidth(pdfRegular, 8, right), pdfMargin, right)
+	})
+}
+
+var policyHTMLTemplate = template.Must(template.New("policy").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Doc.PolicyIdentifier}} {{.Doc.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; color: #222; max-width: 52rem; margin: 0 auto; padding: 0 1.5rem 3rem; line-height: 1.5; }
+.cover { background: {{.Color}}; color: #fff; padding: 2rem 1.5rem; margin: 0 -1.5rem 2rem; }
+.cover .org { font-weight: bold; font-size: 1.1rem; }
+.cover .classification { text-transform: uppercase; font-size: .8rem; letter-spacing: .08em; }
+.cover h1 { margin: 1.5rem 0 .5rem; font-size: 2rem; }
+dl.meta { display: grid; grid-template-columns: 9rem 1fr; gap: .3rem 1rem; }
+dl.meta dt { font-weight: bold; color: #666; }
+dl.meta dd { margin: 0; }
+h2 { color: {{.Color}}; border-bottom: 1px solid #ddd; padding-bottom: .3rem; margin-top: 2.5rem; }
+table { width: 100%; border-collapse: collapse; font-size: .85rem; }
+th { background: {{.Color}}; color: #fff; text-align: left; padding: .4rem; }
+td { padding: .4rem; vertical-align: top; border-bottom: 1px solid #eee; }
+.policy-body h1, .policy-body h2, .policy-body h3 { color: {{.Color}}; }
+footer { margin-top: 3rem; font-size: .75rem; color: #777; border-top: 1px solid #ddd; padding-top: .5rem; }
+@media print { .cover { -webkit-print-color-adjust: exact; print-color-adjust: exact; } section { break-inside: avoid; } }
+</style>
+</head>
+<body>
+<header class="cover">
+<div class="org">{{.Org}}</div>
+{{if .Doc.Branding.Classification}}<div class="classification">{{.Doc.Branding.Classification}}</div>{{end}}
+<h1>{{.Doc.Title}}</h1>
+{{if .Doc.Description}}<p>{{.Doc.Description}}</p>{{end}}
+</header>
+<dl class="meta">
+{{range .Meta}}<dt>{{index . 0}}</dt><dd>{{index . 1}}</dd>
+{{end}}</dl>
+<section>
+<h2>Version history</h2>
+<table>
+<thead><tr>{{range .VersionHeader}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Versions}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+</section>
+<section>
+<h2>Approvals for version {{.Doc.VersionNumber}}</h2>
+{{if .Signoffs}}<table>
+<thead><tr>{{range .SignoffHeader}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Signoffs}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>{{else}}<p><em>No sign-offs were recorded for this version.</em></p>{{end}}
+</section>
+<h2>Policy</h2>
+<article class="policy-body">
+{{.Doc.BodyHTML}}
+</article>
+<footer>{{.Doc.PolicyIdentifier}} v{{.Doc.VersionNumber}}{{if .Doc.Branding.FooterText}} | {{.Doc.Branding.FooterText}}{{end}} | Generated {{.Generated}}</footer>
+</body>
+</html>
+`))
+
+// RenderPolicyHTML renders a policy version as a standalone HTML page with inline styles.
+// d.BodyHTML must already be sanitized.
+func RenderPolicyHTML(d *PolicyDocument) ([]byte, error) {
+	var buf bytes.Buffer
+	err := policyHTMLTemplate.Execute(&buf, map[string]interface{}{
+		"Doc":           d,
+		"Org":           d.orgDisplayName(),
+		"Color":         template.CSS(d.primaryColor()),
+		"Meta":          d.metadataRows(),
+		"VersionHeader": policyVersionHeader,
+		"Versions":      d.versionRows(),
+		"SignoffHeader": policySignoffHeader,
+		"Signoffs":      d.signoffRows(),
+		"Generated":     d.GeneratedAt.UTC().Format("2006-01-02 15:04 UTC"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var (
+	hexColorRe = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+	mdStrongRe = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdEmRe     = regexp.MustCompile(`\*([^*\s][^*]*?)\*|\b_([^_\s][^_]*?)_\b`)
+	mdCodeRe   = regexp.MustCompile("`([^`]+)`")
+)
+
+// mdInline escapes text and applies bold, italic and inline code.
+func mdInline(s string) string {
+	s = html.EscapeString(s)
+	s = mdCodeRe.ReplaceAllString(s, "<code>$1</code>")
+	s = mdStrongRe.ReplaceAllString(s, "<strong>$1$2</strong>")
+	return mdEmRe.ReplaceAllString(s, "<em>$1$2</em>")
+}
+
+// PolicyContentHTML converts policy content to HTML for rendering. HTML is returned unchanged;
+// Markdown headings, lists, code blocks and emphasis are converted; plain text becomes
+// paragraphs. Callers sanitize the result.
+func PolicyContentHTML(content, format string) string {
+	switch format {
+	case models.ContentFormatHTML:
+		return content
+	case models.ContentFormatMarkdown:
+	default:
+		var b strings.Builder
+		for _, p := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+			if p = strings.TrimSpace(p); p != "" {
+				b.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(p), "\n", "<br>") + "</p>\n")
+			}
+		}
+		return b.String()
+	}
+
+	var b strings.Builder
+	var para []string
+	list := ""
+	inFence := false
+	flush := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + mdInline(strings.Join(para, " ")) + "</p>\n")
+			para = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			b.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+			closeList()
+			if inFence {
+				b.WriteString("</code></pre>\n")
+			} else {
+				b.WriteString("<pre><code>")
+			}
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			b.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+		if m := mdHeadingRe.FindStringSubmatch(line); m != nil {
+			flush()
+			closeList()
+			level := len(m[1])
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", level, mdInline(m[2]), level)
+			continue
+		}
+		if m := mdListItemRe.FindStringSubmatch(line); m != nil {
+			flush()
+			kind := "ul"
+			if m[1] != "-" && m[1] != "*" && m[1] != "+" {
+				kind = "ol"
+			}
+			if list != kind {
+				closeList()
+				b.WriteString("<" + kind + ">\n")
+				list = kind
+			}
+			b.WriteString("<li>" + mdInline(strings.TrimSpace(line[len(m[0]):])) + "</li>\n")
+			continue
+		}
+		if trimmed == "" {
+			flush()
+			closeList()
+			continue
+		}
+		closeList()
+		para = append(para, trimmed)
+	}
+	flush()
+	closeList()
+	if inFence {
+		b.WriteString("</code></pre>\n")
+	}
+	return b.String()
+}
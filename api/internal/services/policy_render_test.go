@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func samplePolicyDocument() *PolicyDocument {
+	effective := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	decided := time.Date(2026, 2, 27, 16, 0, 0, 0, time.UTC)
+	return &PolicyDocument{
+		OrgName:          "Acme Corp",
+		Branding:         PolicyBranding{DisplayName: "Acme", PrimaryColor: "#0A7E3C", FooterText: "Internal use only", Classification: "Internal"},
+		PolicyIdentifier: "POL-AC-001",
+		Title:            "Access Control Policy",
+		Category:         "access_control",
+		OwnerName:        "Dana Smith",
+		VersionNumber:    2,
+		Content:          "# Purpose\n\nDefines how access is granted.\n\n## Reviews\n\n- Quarterly access reviews\n- Revoke leavers within 24 hours",
+		ContentFormat:    models.ContentFormatMarkdown,
+		EffectiveDate:    &effective,
+		Versions: []PolicyDocumentVersion{
+			{VersionNumber: 1, ChangeType: "initial", ChangeSummary: "Initial version", Author: "Dana Smith", CreatedAt: effective.AddDate(-1, 0, 0)},
+			{VersionNumber: 2, ChangeType: "minor", ChangeSummary: "Added leaver SLA", Author: "Dana Smith", CreatedAt: effective.AddDate(0, 0, -5), PublishedAt: &effective},
+		},
+		Signoffs: []PolicyDocumentSignoff{
+			{Name: "Sam Lee", Role: "ciso", Status: "approved", DecidedAt: &decided, Comments: "Looks good"},
+		},
+		GeneratedAt: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestRenderPolicyPDF(t *testing.T) {
+	data := RenderPolicyPDF(samplePolicyDocument())
+	require.True(t, bytes.HasPrefix(data, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(data, []byte("%%EOF\n")))
+	assert.Contains(t, string(data), "/Count 3")
+
+	text, err := extractPDFText(data)
+	require.NoError(t, err)
+	for _, want := range []string{
+		"Acme", "Access Control Policy", "March 1, 2026", "Version history", "Added leaver SLA",
+		"Approvals for version 2", "Sam Lee", "Purpose", "Quarterly access reviews", "Page 2 of 3", "Internal use only",
+	} {
+		assert.Contains(t, text, want)
+	}
+}
+
+func TestRenderPolicyPDF_LongContentPaginates(t *testing.T) {
+	d := samplePolicyDocument()
+	d.ContentFormat = models.ContentFormatPlainText
+	d.Content = strings.Repeat("Employees must lock their screens when leaving their desks unattended.\n\n", 200)
+	d.Signoffs = nil
+
+	data := RenderPolicyPDF(d)
+	assert.NotContains(t, string(data), "/Count 3")
+	text, err := extractPDFText(data)
+	require.NoError(t, err)
+	assert.Contains(t, text, "No sign-offs were recorded")
+}
+
+func TestRenderPolicyHTML(t *testing.T) {
+	d := samplePolicyDocument()
+	d.Branding.PrimaryColor = "red; background: url(x)"
+	d.Title = "Access <Control> Policy"
+	d.BodyHTML = template.HTML(PolicyContentHTML(d.Content, d.ContentFormat))
+
+	out, err := RenderPolicyHTML(d)
+	require.NoError(t, err)
+	html := string(out)
+	assert.Contains(t, html, "Access &lt;Control&gt; Policy")
+	assert.Contains(t, html, defaultPolicyColor, "invalid colours fall back to the default")
+	assert.NotContains(t, html, "url(x)")
+	assert.Contains(t, html, "<h1>Purpose</h1>")
+	assert.Contains(t, html, "<td>Sam Lee</td>")
+	assert.Contains(t, html, "March 1, 2026")
+}
+
+func TestPolicyContentHTML(t *testing.T) {
+	md := "## Scope\nApplies to **all** staff & contractors.\n\n1. First\n2. Second\n\n```\n<raw>\n```"
+	assert.Equal(t, "<h2>Scope</h2>\n<p>Applies to <strong>all</strong> staff &amp; contractors.</p>\n"+
+		"<ol>\n<li>First</li>\n<li>Second</li>\n</ol>\n<pre><code>&lt;raw&gt;\n</code></pre>\n",
+		PolicyContentHTML(md, models.ContentFormatMarkdown))
+
+	assert.Equal(t, "<p>Line one<br>line two</p>\n<p>a &lt; b</p>\n",
+		PolicyContentHTML("Line one\nline two\n\na < b", models.ContentFormatPlainText))
+}
+
+func TestPDFWrapAndEncoding(t *testing.T) {
+	lines := pdfWrap(pdfRegular, 10, "the quick brown fox jumps over the lazy dog", 80)
+	assert.Greater(t, len(lines), 1)
+	for _, line := range lines {
+		assert.LessOrEqual(t, pdfTextWidth(pdfRegular, 10, line), 80.0)
+	}
+	assert.Len(t, pdfWrap(pdfRegular, 10, strings.Repeat("x", 100), 50), 10, "long words are split")
+
+	assert.Equal(t, []byte{'a', 0x93, 'b', 0x94, 0xE9, '?'}, toWinAnsi("a“b”é漢"))
+	assert.Equal(t, `(a\(b\)\\ \351)`, pdfEscape(`a(b)\ é`))
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// PolicyReviewSweepResult counts what one policy review sweep changed.
+type PolicyReviewSweepResult struct {
+	Completed       int64
+	Cancelled       int64
+	Opened          int64
+	Escalated       int64
+	AlertsResolved  int64
+	AlertsEscalated int64
+	AlertsRaised    int64
+}
+
+// policyReviewLevel is the escalation level an unfinished review (aliased r) has reached:
+// 1 once due_at passes, 2 once it has been overdue for more than escalation days ($1).
+const policyReviewLevel = `CASE
+		WHEN r.due_at < CURRENT_DATE - $1::int THEN 2
+		WHEN r.due_at < CURRENT_DATE THEN 1
+		ELSE 0 END`
+
+// SweepPolicyReviews opens a review task for each published policy whose next_review_at falls
+// within lead days, completes reviews once the policy is republished, cancels reviews the
+// schedule no longer calls for, and escalates overdue reviews through alerts: first to the
+// owner, then to the CISO. Each step is a single statement, so a sweep is safe to run from
+// several workers.
+func SweepPolicyReviews(ctx context.Context, db *sql.DB, leadDays, escalationDays int) (*PolicyReviewSweepResult, error) {
+	var r PolicyReviewSweepResult
+	steps := []struct {
+		name  string
+		query string
+		args  []interface{}
+		count *int64
+	}{
+		// Publishing rolls next_review_at forward, which is what closes the cycle. A review the
+		// owner never started completes too if the policy was republished another way.
+		{"complete reviews", `
+			UPDATE policy_reviews r SET status = 'completed', completed_at = NOW(),
+				outcome = COALESCE(r.outcome, 'revised')
+			FROM policies p
+			WHERE p.id = r.policy_id
+				AND r.status IN ('open', 'in_approval', 'revising')
+				AND p.status = 'published'
+				AND p.published_at >= COALESCE(r.started_at, r.created_at)
+		`, nil, &r.Completed},
+
+		// An open review follows the schedule: if next_review_at is cleared or moved, the review
+		// is cancelled and the next step opens one for the new date.
+		{"cancel reviews", `
+			UPDATE policy_reviews r SET status = 'cancelled', completed_at = NOW()
+			FROM policies p
+			WHERE p.id = r.policy_id
+				AND r.status IN ('open', 'in_approval', 'revising')
+				AND (p.status = 'archived'
+					OR (r.status = 'open' AND p.next_review_at IS DISTINCT FROM r.due_at))
+		`, nil, &r.Cancelled},
+
+		// One review per review date: a cancelled review is not reopened unless the date changes.
+		// Templates are never reviewed; policies created from them carry their own schedule.
+		{"open reviews", `
+			INSERT INTO policy_reviews (org_id, policy_id, assigned_to, due_at)
+			SELECT p.org_id, p.id, COALESCE(p.owner_id, p.secondary_owner_id), p.next_review_at
+			FROM policies p
+			WHERE p.status = 'published'
+				AND p.is_template = FALSE
+				AND p.next_review_at IS NOT NULL
+				AND p.next_review_at <= CURRENT_DATE + $1::int
+				AND NOT EXISTS (
+					SELECT 1 FROM policy_reviews r
+					WHERE r.policy_id = p.id
+						AND (r.status IN ('open', 'in_approval', 'revising') OR r.due_at = p.next_review_at)
+				)
+			ON CONFLICT DO NOTHING
+		`, []interface{}{leadDays}, &r.Opened},
+
+		{"escalate reviews", fmt.Sprintf(`
+			UPDATE policy_reviews r SET escalation_level = %s, escalated_at = NOW()
+			WHERE r.status IN ('open', 'in_approval', 'revising')
+				AND r.escalation_level < %s
+		`, policyReviewLevel, policyReviewLevel), []interface{}{escalationDays}, &r.Escalated},
+
+		{"resolve alerts", `
+			UPDATE alerts a SET status = 'resolved', resolved_at = NOW(),
+				resolution_notes = 'Policy review ' || r.status::text
+			FROM policy_reviews r
+			WHERE a.policy_id = r.policy_id
+				AND a.metadata->>'review_id' = r.id::text
+				AND a.status NOT IN ('resolved', 'closed')
+				AND r.status IN ('completed', 'cancelled')
+		`, nil, &r.AlertsResolved},
+
+		{"escalate alerts", `
+			UPDATE alerts a SET severity = 'high',
+				title = 'Policy review escalated: ' || p.identifier || ' ' || p.title,
+				assigned_to = COALESCE((
+					SELECT u.id FROM users u
+					WHERE u.org_id = r.org_id AND u.role = 'ciso' AND u.status = 'active'
+					ORDER BY u.created_at LIMIT 1
+				), a.assigned_to),
+				assigned_at = NOW(),
+				metadata = a.metadata || '{"escalation_level": 2}'::jsonb
+			FROM policy_reviews r
+			JOIN policies p ON p.id = r.policy_id
+			WHERE a.policy_id = r.policy_id
+				AND a.metadata->>'review_id' = r.id::text
+				AND a.metadata->>'escalation_level' = '1'
+				AND a.status NOT IN ('resolved', 'closed')
+				AND r.escalation_level = 2
+		`, nil, &r.AlertsEscalated},
+
+		// Alerts hang off the first control the policy covers, or only the policy when it covers
+		// none. An alert resolved by hand is not raised again until the review reaches the next
+		// level.
+		{"raise alerts", `
+			INSERT INTO alerts (org_id, title, description, severity, status,
+				control_id, policy_id, assigned_to, assigned_at,
+				delivery_channels, tags, metadata)
+			SELECT r.org_id,
+				CASE WHEN r.escalation_level = 2 THEN 'Policy review escalated: ' ELSE 'Policy review overdue: ' END ||
+					p.identifier || ' ' || p.title,
+				'The scheduled review of ' || p.identifier || ' was due on ' || to_char(r.due_at, 'YYYY-MM-DD') ||
+					'. Confirm the policy needs no changes or start a new draft.',
+				CASE WHEN r.escalation_level = 2 THEN 'high' ELSE 'medium' END::alert_severity,
+				'open', ctl.control_id, p.id,
+				asg.user_id,
+				CASE WHEN asg.user_id IS NOT NULL THEN NOW() END,
+				ARRAY['in_app']::alert_delivery_channel[],
+				ARRAY['policy_review'],
+				jsonb_build_object('source', 'policy_review', 'review_id', r.id,
+					'escalation_level', r.escalation_level, 'due_at', r.due_at)
+			FROM policy_reviews r
+			JOIN policies p ON p.id = r.policy_id
+			LEFT JOIN LATERAL (
+				SELECT pc.control_id FROM policy_controls pc
+				WHERE pc.policy_id = p.id
+				ORDER BY pc.coverage = 'full' DESC, pc.created_at
+				LIMIT 1
+			) ctl ON TRUE
+			CROSS JOIN LATERAL (
+				SELECT CASE WHEN r.escalation_level = 2 THEN COALESCE((
+					SELECT u.id FROM users u
+					WHERE u.org_id = r.org_id AND u.role = 'ciso' AND u.status = 'active'
+					ORDER BY u.created_at LIMIT 1
+				), r.assigned_to) ELSE r.assigned_to END AS user_id
+			) asg
+			WHERE r.status IN ('open', 'in_approval', 'revising')
+				AND r.escalation_level > 0
+				AND NOT EXISTS (
+					SELECT 1 FROM alerts a
+					WHERE a.policy_id = r.policy_id AND a.metadata->>'review_id' = r.id::text
+						AND (a.status NOT IN ('resolved', 'closed')
+							OR (a.metadata->>'escalation_level')::int >= r.escalation_level)
+				)
+		`, nil, &r.AlertsRaised},
+	}
+
+	for _, s := range steps {
+		res, err := db.ExecContext(ctx, s.query, s.args...)
+		if err != nil {
+			return &r, fmt.Errorf("%s: %w", s.name, err)
+		}
+		*s.count, _ = res.RowsAffected()
+	}
+	return &r, nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepPolicyReviews_Counts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE policy_reviews r SET status = 'completed'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE policy_reviews r SET status = 'cancelled'").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO policy_reviews(.|\n)*p.is_template = FALSE").WithArgs(30).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("UPDATE policy_reviews r SET escalation_level").WithArgs(14).WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec("UPDATE alerts a SET status = 'resolved'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE alerts a SET severity = 'high'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO alerts.*LEFT JOIN LATERAL.*FROM policy_controls pc").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	r, err := SweepPolicyReviews(context.Background(), db, 30, 14)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyReviewSweepResult{
+		Completed: 1, Cancelled: 2, Opened: 3, Escalated: 4, AlertsResolved: 1, AlertsEscalated: 1, AlertsRaised: 2,
+	}, *r)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSweepPolicyReviews_StopsOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE policy_reviews r SET status = 'completed'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE policy_reviews r SET status = 'cancelled'").WillReturnError(errors.New("deadlock detected"))
+
+	r, err := SweepPolicyReviews(context.Background(), db, 30, 14)
+	assert.ErrorContains(t, err, "cancel reviews")
+	assert.Equal(t, int64(1), r.Completed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+var (
+	// policyPlaceholderRe matches a template variable placeholder such as {{company_name}}.
+	policyPlaceholderRe = regexp.MustCompile(`\{\{\s*([a-z][a-z0-9_]*)\s*\}\}`)
+	// PolicyVariableKeyRe is the form variable keys must take.
+	PolicyVariableKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+	policyDurationRe    = regexp.MustCompile(`^(\d{1,4})\s+(day|week|month|year)s?$`)
+)
+
+// FindPolicyPlaceholders returns the distinct variable keys left as placeholders in content, in
+// the order they first appear.
+func FindPolicyPlaceholders(content string) []string {
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, m := range policyPlaceholderRe.FindAllStringSubmatch(content, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			keys = append(keys, m[1])
+		}
+	}
+	return keys
+}
+
+// SubstitutePolicyVariables replaces placeholders that have a display value. Placeholders without
+// one are left in place so they can be found before review. Values are escaped for HTML content.
+func SubstitutePolicyVariables(content, format string, values map[string]string) string {
+	return policyPlaceholderRe.ReplaceAllStringFunc(content, func(m string) string {
+		key := policyPlaceholderRe.FindStringSubmatch(m)[1]
+		v, ok := values[key]
+		if !ok {
+			return m
+		}
+		if format == models.ContentFormatHTML {
+			return html.EscapeString(v)
+		}
+		return v
+	})
+}
+
+// NormalizePolicyVariableValue validates a value for a variable type and returns it in canonical
+// form. User variables hold a user ID, which the caller checks against the org.
+func NormalizePolicyVariableValue(varType, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("value is empty")
+	}
+	switch varType {
+	case models.PolicyVariableText, models.PolicyVariableUser:
+		if len(value) > 500 {
+			return "", fmt.Errorf("value exceeds 500 characters")
+		}
+		return value, nil
+	case models.PolicyVariableNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", fmt.Errorf("must be a number")
+		}
+		return value, nil
+	case models.PolicyVariableDuration:
+		m := policyDurationRe.FindStringSubmatch(strings.ToLower(value))
+		if m == nil {
+			return "", fmt.Errorf(`must be a duration such as "90 days" or "7 years"`)
+		}
+		unit := m[2]
+		if m[1] != "1" {
+			unit += "s"
+		}
+		return m[1] + " " + unit, nil
+	case models.PolicyVariableCadence:
+		v := strings.ToLower(value)
+		for _, c := range models.PolicyCadences {
+			if c == v {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("must be one of %s", strings.Join(models.PolicyCadences, ", "))
+	}
+	return "", fmt.Errorf("unknown variable type %q", varType)
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindPolicyPlaceholders(t *testing.T) {
+	content := "<p>{{company_name}} appoints {{ security_officer }}. {{company_name}} keeps logs for {{retention_period}}.</p><p>{{Not_A_Key}} {notes}</p>"
+	assert.Equal(t, []string{"company_name", "security_officer", "retention_period"}, FindPolicyPlaceholders(content))
+	assert.Empty(t, FindPolicyPlaceholders("No placeholders here."))
+}
+
+func TestSubstitutePolicyVariables(t *testing.T) {
+	values := map[string]string{"company_name": "Smith & Sons", "retention_period": "7 years"}
+	content := "{{company_name}} keeps records for {{retention_period}}; contact {{security_officer}}."
+
+	assert.Equal(t, "Smith &amp; Sons keeps records for 7 years; contact {{security_officer}}.",
+		SubstitutePolicyVariables(content, models.ContentFormatHTML, values))
+	assert.Equal(t, "Smith & Sons keeps records for 7 years; contact {{security_officer}}.",
+		SubstitutePolicyVariables(content, models.ContentFormatMarkdown, values))
+}
+
+func TestNormalizePolicyVariableValue(t *testing.T) {
+	v, err := NormalizePolicyVariableValue(models.PolicyVariableDuration, " 7 Years ")
+	require.NoError(t, err)
+	assert.Equal(t, "7 years", v)
+
+	v, err = NormalizePolicyVariableValue(models.PolicyVariableDuration, "1 months")
+	require.NoError(t, err)
+	assert.Equal(t, "1 month", v)
+
+	_, err = NormalizePolicyVariableValue(models.PolicyVariableDuration, "forever")
+	assert.Error(t, err)
+
+	v, err = NormalizePolicyVariableValue(models.PolicyVariableCadence, "Quarterly")
+	require.NoError(t, err)
+	assert.Equal(t, "quarterly", v)
+
+	_, err = NormalizePolicyVariableValue(models.PolicyVariableCadence, "fortnightly")
+	assert.Error(t, err)
+
+	_, err = NormalizePolicyVariableValue(models.PolicyVariableNumber, "ten")
+	assert.Error(t, err)
+
+	_, err = NormalizePolicyVariableValue(models.PolicyVariableText, "   ")
+	assert.Error(t, err)
+}
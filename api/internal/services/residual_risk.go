@@ -0,0 +1,403 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// Residual derivation axes: controls and most treatments make a risk less likely; transfer
+// treatments (insurance, outsourcing) limit its impact.
+const (
+	ResidualAxisLikelihood = "likelihood"
+	ResidualAxisImpact     = "impact"
+)
+
+// Residual derivation factor sources.
+const (
+	ResidualSourceControl   = "control"
+	ResidualSourceTreatment = "treatment"
+)
+
+// Residual derivation flags.
+const (
+	ResidualFlagMissing       = "residual_missing"
+	ResidualFlagStale         = "residual_stale"
+	ResidualFlagExpired       = "residual_expired"
+	ResidualFlagAboveInherent = "residual_above_inherent"
+	ResidualFlagUnderstated   = "residual_understated"
+	ResidualFlagOverstated    = "residual_overstated"
+	ResidualFlagControlHealth = "control_health_degraded"
+	ResidualFlagNotScored     = "inherent_not_scored"
+)
+
+// DefaultControlMitigation is the share of likelihood a fully effective control removes when
+// its link records no mitigation_percentage.
+const DefaultControlMitigation = 0.5
+
+// Strength (0–1) credited for each control effectiveness rating.
+var controlEffectivenessStrength = map[string]float64{
+	models.EffectivenessEffective:          1,
+	models.EffectivenessPartiallyEffective: 0.5,
+	models.EffectivenessIneffective:        0,
+	models.EffectivenessNotAssessed:        0,
+}
+
+// Share of the treated axis removed by a verified treatment, by effectiveness rating. Verified
+// treatments without a rating are credited as partially effective.
+var treatmentRatingReduction = map[string]float64{
+	"highly_effective":    0.75,
+	"effective":           0.5,
+	"partially_effective": 0.25,
+	"ineffective":         0,
+}
+
+// ResidualControlInput is a control linked to the risk.
+type ResidualControlInput struct {
+	ControlID            string
+	Identifier           string
+	Title                string
+	Effectiveness        string         // risk_controls.effectiveness as last reviewed
+	MitigationPercentage *int           // share of likelihood the control addresses
+	TestResultCounts     map[string]int // test_result_status → count within the test window
+	ChangedAt            time.Time      // link created or effectiveness last reviewed
+}
+
+// ResidualTreatmentInput is a verified treatment on the risk.
+type ResidualTreatmentInput struct {
+	TreatmentID         string
+	Title               string
+	TreatmentType       string
+	EffectivenessRating *string
+	VerifiedAt          time.Time
+}
+
+// RecordedResidual is the residual score currently on the risk.
+type RecordedResidual struct {
+	Likelihood string
+	Impact     string
+	Score      float64
+	AssessedAt *time.Time
+	ValidUntil *time.Time
+}
+
+// ResidualInputs holds everything a residual derivation depends on.
+type ResidualInputs struct {
+	Methodology        *models.RiskMethodology
+	InherentLikelihood *string
+	InherentImpact     *string
+	Recorded           *RecordedResidual
+	Controls           []ResidualControlInput
+	Treatments         []ResidualTreatmentInput
+	Now                time.Time
+}
+
+// ControlHealth is a control's pass rate over recent test results.
+type ControlHealth struct {
+	Score   float64 `json:"score"`
+	Rating  string  `json:"rating"`
+	Samples int     `json:"samples"`
+}
+
+// ResidualFactor explains one control or treatment's contribution to the derivation.
+type ResidualFactor struct {
+	Source        string         `json:"source"`
+	ID            string         `json:"id"`
+	Identifier    string         `json:"identifier,omitempty"`
+	Title         string         `json:"title"`
+	Axis          string         `json:"axis"`
+	Effectiveness string         `json:"effectiveness"`
+	LiveHealth    *ControlHealth `json:"live_health,omitempty"`
+	Strength      float64        `json:"strength"`
+	Reduction     float64        `json:"reduction"`
+	Detail        string         `json:"detail"`
+}
+
+// ResidualFlag marks a residual score that needs attention.
+type ResidualFlag struct {
+	Code      string  `json:"code"`
+	ControlID *string `json:"control_id,omitempty"`
+	Detail    string  `json:"detail"`
+}
+
+// ResidualPoint is a likelihood x impact position on the methodology's scales.
+type ResidualPoint struct {
+	Likelihood      string  `json:"likelihood"`
+	Impact          string  `json:"impact"`
+	LikelihoodScore int     `json:"likelihood_score"`
+	ImpactScore     int     `json:"impact_score"`
+	Score           float64 `json:"score"`
+	Severity        string  `json:"severity"`
+}
+
+// ResidualDerivation is the suggested residual for a risk and how it was reached.
+type ResidualDerivation struct {
+	Inherent            *ResidualPoint   `json:"inherent"`
+	Suggested           *ResidualPoint   `json:"suggested"`
+	Recorded            *ResidualPoint   `json:"recorded"`
+	LikelihoodReduction float64          `json:"likelihood_reduction"`
+	ImpactReduction     float64          `json:"impact_reduction"`
+	Factors             []ResidualFactor `json:"factors"`
+	Flags               []ResidualFlag   `json:"flags"`
+	Explanation         []string         `json:"explanation"`
+}
+
+// LiveControlHealth scores recent test results the same way the control effectiveness engine
+// scores its test pass rate component. It returns nil when no tests executed in the window.
+func LiveControlHealth(counts map[string]int) *ControlHealth {
+	pass, warn := counts["pass"], counts["warning"]
+	executed := pass + warn + counts["fail"] + counts["error"]
+	if executed == 0 {
+		return nil
+	}
+	score := roundTo((float64(pass)+0.5*float64(warn))/float64(executed)*100, 2)
+	return &ControlHealth{Score: score, Rating: EffectivenessRatingForScore(score), Samples: executed}
+}
+
+// DeriveResidualRisk suggests a residual likelihood and impact from the inherent position and
+// the controls and verified treatments acting on the risk.
+//
+// Each control removes strength x mitigation of the inherent likelihood, where strength comes
+// from its reviewed effectiveness, capped by live test health so failing tests override a stale
+// review. Verified treatments remove a share of likelihood (mitigate, avoid) or impact
+// (transfer) set by their effectiveness rating. Reductions on the same axis compound as
+// 1 - (1 - r1)(1 - r2)..., and the reduced score is rounded up to the next level on the scale.
+func DeriveResidualRisk(in ResidualInputs) ResidualDerivation {
+	m := in.Methodology
+	d := ResidualDerivation{Factors: []ResidualFactor{}, Flags: []ResidualFlag{}, Explanation: []string{}}
+
+	if in.Recorded != nil && m.IsValidLikelihood(in.Recorded.Likelihood) && m.IsValidImpact(in.Recorded.Impact) {
+		d.Recorded = residualPoint(m, in.Recorded.Likelihood, in.Recorded.Impact)
+		d.Recorded.Score = in.Recorded.Score
+		d.Recorded.Severity = m.Severity(in.Recorded.Score)
+	}
+
+	likelihoodRemaining, impactRemaining := 1.0, 1.0
+	for _, ctrl := range in.Controls {
+		f, flag := controlFactor(ctrl)
+		d.Factors = append(d.Factors, f)
+		if flag != nil {
+			d.Flags = append(d.Flags, *flag)
+		}
+		likelihoodRemaining *= 1 - f.Reduction
+	}
+	for _, t := range in.Treatments {
+		f := treatmentFactor(t)
+		d.Factors = append(d.Factors, f)
+		if f.Axis == ResidualAxisImpact {
+			impactRemaining *= 1 - f.Reduction
+		} else {
+			likelihoodRemaining *= 1 - f.Reduction
+		}
+	}
+	d.LikelihoodReduction = roundTo(1-likelihoodRemaining, 4)
+	d.ImpactReduction = roundTo(1-impactRemaining, 4)
+
+	if in.InherentLikelihood == nil || in.InherentImpact == nil ||
+		!m.IsValidLikelihood(*in.InherentLikelihood) || !m.IsValidImpact(*in.InherentImpact) {
+		d.Flags = append(d.Flags, ResidualFlag{
+			Code:   ResidualFlagNotScored,
+			Detail: "Inherent risk is not scored on the active methodology, so no residual can be derived",
+		})
+		d.Explanation = append(d.Explanation, "No suggestion: score the inherent risk first.")
+		return d
+	}
+
+	d.Inherent = residualPoint(m, *in.InherentLikelihood, *in.InherentImpact)
+	likelihood := levelAtLeast(m.LikelihoodLevels, float64(d.Inherent.LikelihoodScore)*likelihoodRemaining)
+	impact := levelAtLeast(m.ImpactLevels, float64(d.Inherent.ImpactScore)*impactRemaining)
+	d.Suggested = residualPoint(m, likelihood.Key, impact.Key)
+
+	d.Explanation = append(d.Explanation,
+		fmt.Sprintf("Inherent %s x %s scores %g (%s).", d.Inherent.Likelihood, d.Inherent.Impact, d.Inherent.Score, d.Inherent.Severity),
+		fmt.Sprintf("%d controls and %d verified treatments reduce likelihood by %.0f%%: %d becomes %s (%d).",
+			len(in.Controls), len(in.Treatments), d.LikelihoodReduction*100,
+			d.Inherent.LikelihoodScore, likelihood.Key, likelihood.Score),
+		fmt.Sprintf("Transfer treatments reduce impact by %.0f%%: %d becomes %s (%d).",
+			d.ImpactReduction*100, d.Inherent.ImpactScore, impact.Key, impact.Score),
+		fmt.Sprintf("Suggested residual %s x %s scores %g (%s).", d.Suggested.Likelihood, d.Suggested.Impact, d.Suggested.Score, d.Suggested.Severity),
+	)
+
+	d.Flags = append(d.Flags, residualFlags(in, d)...)
+	return d
+}
+
+func controlFactor(ctrl ResidualControlInput) (ResidualFactor, *ResidualFlag) {
+	f := ResidualFactor{
+		Source:        ResidualSourceControl,
+		ID:            ctrl.ControlID,
+		Identifier:    ctrl.Identifier,
+		Title:         ctrl.Title,
+		Axis:          ResidualAxisLikelihood,
+		Effectiveness: ctrl.Effectiveness,
+		LiveHealth:    LiveControlHealth(ctrl.TestResultCounts),
+	}
+	reviewed := controlEffectivenessStrength[ctrl.Effectiveness]
+	f.Strength = reviewed
+
+	var flag *ResidualFlag
+	switch {
+	case f.LiveHealth == nil:
+		f.Detail = fmt.Sprintf("Reviewed as %s; no test results in the last %d days", ctrl.Effectiveness, EffectivenessTestWindowDays)
+	case ctrl.Effectiveness == models.EffectivenessNotAssessed:
+		f.Strength = controlEffectivenessStrength[f.LiveHealth.Rating]
+		f.Detail = fmt.Sprintf("Not reviewed; credited from live test health (%s)", f.LiveHealth.Rating)
+	default:
+		live := controlEffectivenessStrength[f.LiveHealth.Rating]
+		if live < reviewed {
+			f.Strength = live
+			id := ctrl.ControlID
+			flag = &ResidualFlag{
+				Code:      ResidualFlagControlHealth,
+				ControlID: &id,
+				Detail: fmt.Sprintf("%s is reviewed as %s but its tests rate it %s (%g%% over %d results)",
+					controlName(ctrl), ctrl.Effectiveness, f.LiveHealth.Rating, f.LiveHealth.Score, f.LiveHealth.Samples),
+			}
+			f.Detail = fmt.Sprintf("Reviewed as %s; capped at %s by live test health", ctrl.Effectiveness, f.LiveHealth.Rating)
+		} else {
+			f.Detail = fmt.Sprintf("Reviewed as %s; live test health agrees (%s)", ctrl.Effectiveness, f.LiveHealth.Rating)
+		}
+	}
+
+	mitigation := DefaultControlMitigation
+	if ctrl.MitigationPercentage != nil {
+		mitigation = float64(*ctrl.MitigationPercentage) / 100
+		f.Detail += fmt.Sprintf("; mitigates %d%% of likelihood", *ctrl.MitigationPercentage)
+	}
+	f.Reduction = roundTo(f.Strength*mitigation, 4)
+	return f, flag
+}
+
+func treatmentFactor(t ResidualTreatmentInput) ResidualFactor {
+	f := ResidualFactor{
+		Source:        ResidualSourceTreatment,
+		ID:            t.TreatmentID,
+		Title:         t.Title,
+		Axis:          ResidualAxisLikelihood,
+		Effectiveness: "partially_effective",
+	}
+	if t.EffectivenessRating != nil {
+		f.Effectiveness = *t.EffectivenessRating
+	}
+	if t.TreatmentType == models.TreatmentTransfer {
+		f.Axis = ResidualAxisImpact
+	}
+	if t.TreatmentType == models.TreatmentAccept {
+		f.Detail = "Acceptance does not reduce risk"
+		return f
+	}
+	f.Reduction = treatmentRatingReduction[f.Effectiveness]
+	f.Strength = f.Reduction
+	f.Detail = fmt.Sprintf("Verified %s treatment rated %s", t.TreatmentType, f.Effectiveness)
+	if t.EffectivenessRating == nil {
+		f.Detail += " (no rating recorded)"
+	}
+	return f
+}
+
+func residualFlags(in ResidualInputs, d ResidualDerivation) []ResidualFlag {
+	flags := []ResidualFlag{}
+	if d.Recorded == nil {
+		if len(d.Factors) > 0 {
+			flags = append(flags, ResidualFlag{
+				Code:   ResidualFlagMissing,
+				Detail: fmt.Sprintf("No residual score recorded; controls and treatments suggest %g (%s)", d.Suggested.Score, d.Suggested.Severity),
+			})
+		}
+		return flags
+	}
+
+	if in.Recorded.ValidUntil != nil && in.Recorded.ValidUntil.Before(in.Now) {
+		flags = append(flags, ResidualFlag{
+			Code:   ResidualFlagExpired,
+			Detail: fmt.Sprintf("Residual assessment expired on %s", in.Recorded.ValidUntil.Format("2006-01-02")),
+		})
+	}
+	if in.Recorded.AssessedAt != nil {
+		var latest time.Time
+		var changed string
+		for _, c := range in.Controls {
+			if c.ChangedAt.After(latest) {
+				latest, changed = c.ChangedAt, "control "+controlName(c)
+			}
+		}
+		for _, t := range in.Treatments {
+			if t.VerifiedAt.After(latest) {
+				latest, changed = t.VerifiedAt, "treatment "+t.Title
+			}
+		}
+		if latest.After(*in.Recorded.AssessedAt) {
+			flags = append(flags, ResidualFlag{
+				Code: ResidualFlagStale,
+				Detail: fmt.Sprintf("Residual assessed %s, before %s changed on %s",
+					in.Recorded.AssessedAt.Format("2006-01-02"), changed, latest.Format("2006-01-02")),
+			})
+		}
+	}
+
+	if d.Recorded.Score > d.Inherent.Score {
+		flags = append(flags, ResidualFlag{
+			Code:   ResidualFlagAboveInherent,
+			Detail: fmt.Sprintf("Residual score %g is higher than inherent score %g", d.Recorded.Score, d.Inherent.Score),
+		})
+	}
+	recordedRank, suggestedRank := severityRank(d.Recorded.Severity), severityRank(d.Suggested.Severity)
+	switch {
+	case recordedRank > suggestedRank:
+		flags = append(flags, ResidualFlag{
+			Code: ResidualFlagUnderstated,
+			Detail: fmt.Sprintf("Recorded residual is %s but controls and treatments only support %s",
+				d.Recorded.Severity, d.Suggested.Severity),
+		})
+	case recordedRank < suggestedRank:
+		flags = append(flags, ResidualFlag{
+			Code: ResidualFlagOverstated,
+			Detail: fmt.Sprintf("Recorded residual is %s but controls and treatments support %s",
+				d.Recorded.Severity, d.Suggested.Severity),
+		})
+	}
+	return flags
+}
+
+// severityRank orders severities so a larger rank is less severe.
+func severityRank(severity string) int {
+	for i, s := range models.SeverityRanks {
+		if s == severity {
+			return i
+		}
+	}
+	return len(models.SeverityRanks)
+}
+
+func residualPoint(m *models.RiskMethodology, likelihood, impact string) *ResidualPoint {
+	p := &ResidualPoint{
+		Likelihood:      likelihood,
+		Impact:          impact,
+		LikelihoodScore: m.LikelihoodScore(likelihood),
+		ImpactScore:     m.ImpactScore(impact),
+	}
+	p.Score = float64(p.LikelihoodScore * p.ImpactScore)
+	p.Severity = m.Severity(p.Score)
+	return p
+}
+
+// levelAtLeast returns the lowest level scoring at least target, so derived residuals never
+// claim more reduction than the controls justify.
+func levelAtLeast(levels []models.RiskScaleLevel, target float64) models.RiskScaleLevel {
+	target = math.Round(target*100) / 100
+	for _, l := range levels {
+		if float64(l.Score) >= target {
+			return l
+		}
+	}
+	return levels[len(levels)-1]
+}
+
+func controlName(c ResidualControlInput) string {
+	if c.Identifier != "" {
+		return c.Identifier
+	}
+	return c.Title
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func flagCodes(d ResidualDerivation) []string {
+	codes := []string{}
+	for _, f := range d.Flags {
+		codes = append(codes, f.Code)
+	}
+	return codes
+}
+
+func TestLiveControlHealth(t *testing.T) {
+	assert.Nil(t, LiveControlHealth(map[string]int{"skip": 4}))
+
+	h := LiveControlHealth(map[string]int{"pass": 3, "warning": 2, "fail": 1, "skip": 9})
+	require.NotNil(t, h)
+	assert.Equal(t, 6, h.Samples)
+	assert.InDelta(t, 66.67, h.Score, 0.001)
+	assert.Equal(t, models.EffectivenessPartiallyEffective, h.Rating)
+}
+
+func TestDeriveResidualRisk_ControlsAndTreatments(t *testing.T) {
+	now := time.Now()
+	assessed := now.AddDate(0, -2, 0)
+	forty := 40
+	in := ResidualInputs{
+		Methodology:        models.DefaultRiskMethodology(),
+		InherentLikelihood: strPtr(models.LikelihoodLikely),
+		InherentImpact:     strPtr(models.ImpactMajor),
+		Recorded: &RecordedResidual{
+			Likelihood: models.LikelihoodPossible, Impact: models.ImpactModerate, Score: 9, AssessedAt: &assessed,
+		},
+		Controls: []ResidualControlInput{
+			{ControlID: "ctrl-1", Identifier: "CTRL-AC-001", Effectiveness: models.EffectivenessEffective,
+				ChangedAt: now.AddDate(0, -3, 0)},
+			{ControlID: "ctrl-2", Identifier: "CTRL-BK-002", Effectiveness: models.EffectivenessEffective,
+				MitigationPercentage: &forty, TestResultCounts: map[string]int{"pass": 1, "fail": 3},
+				ChangedAt: now.AddDate(0, 0, -5)},
+		},
+		Treatments: []ResidualTreatmentInput{
+			{TreatmentID: "t-1", Title: "Cyber insurance", TreatmentType: models.TreatmentTransfer,
+				EffectivenessRating: strPtr("effective"), VerifiedAt: now.AddDate(0, -4, 0)},
+		},
+		Now: now,
+	}
+	d := DeriveResidualRisk(in)
+
+	require.NotNil(t, d.Inherent)
+	assert.Equal(t, float64(16), d.Inherent.Score)
+	assert.Equal(t, "high", d.Inherent.Severity)
+
+	// The failing control is capped to ineffective, so only ctrl-1 reduces likelihood.
+	assert.Equal(t, 0.5, d.LikelihoodReduction)
+	assert.Equal(t, 0.5, d.ImpactReduction)
+	require.Len(t, d.Factors, 3)
+	assert.Equal(t, float64(0), d.Factors[1].Reduction)
+	assert.Equal(t, ResidualAxisImpact, d.Factors[2].Axis)
+
+	require.NotNil(t, d.Suggested)
+	assert.Equal(t, models.LikelihoodUnlikely, d.Suggested.Likelihood)
+	assert.Equal(t, models.ImpactMinor, d.Suggested.Impact)
+	assert.Equal(t, float64(4), d.Suggested.Score)
+	assert.Equal(t, "low", d.Suggested.Severity)
+
+	assert.ElementsMatch(t, []string{ResidualFlagControlHealth, ResidualFlagStale, ResidualFlagOverstated}, flagCodes(d))
+	assert.Equal(t, "ctrl-2", *d.Flags[0].ControlID)
+	assert.NotEmpty(t, d.Explanation)
+}
+
+func TestDeriveResidualRisk_RoundsUpToScale(t *testing.T) {
+	// One partially effective control removes a quarter of "almost certain" (5): 3.75 rounds up
+	// to "likely" (4) rather than down to "possible".
+	d := DeriveResidualRisk(ResidualInputs{
+		Methodology:        models.DefaultRiskMethodology(),
+		InherentLikelihood: strPtr(models.LikelihoodAlmostCertain),
+		InherentImpact:     strPtr(models.ImpactSevere),
+		Controls: []ResidualControlInput{
+			{ControlID: "ctrl-1", Effectiveness: models.EffectivenessPartiallyEffective},
+		},
+		Now: time.Now(),
+	})
+	require.NotNil(t, d.Suggested)
+	assert.Equal(t, models.LikelihoodLikely, d.Suggested.Likelihood)
+	assert.Equal(t, models.ImpactSevere, d.Suggested.Impact)
+	assert.Equal(t, []string{ResidualFlagMissing}, flagCodes(d))
+}
+
+func TestDeriveResidualRisk_UntestedUnreviewedControlUsesLiveHealth(t *testing.T) {
+	d := DeriveResidualRisk(ResidualInputs{
+		Methodology:        models.DefaultRiskMethodology(),
+		InherentLikelihood: strPtr(models.LikelihoodLikely),
+		InherentImpact:     strPtr(models.ImpactModerate),
+		Controls: []ResidualControlInput{
+			{ControlID: "ctrl-1", Effectiveness: models.EffectivenessNotAssessed,
+				TestResultCounts: map[string]int{"pass": 10}},
+		},
+		Now: time.Now(),
+	})
+	assert.Equal(t, float64(1), d.Factors[0].Strength)
+	assert.Equal(t, 0.5, d.LikelihoodReduction)
+}
+
+func TestDeriveResidualRisk_Inconsistent(t *testing.T) {
+	now := time.Now()
+	expired := now.AddDate(0, 0, -1)
+	d := DeriveResidualRisk(ResidualInputs{
+		Methodology:        models.DefaultRiskMethodology(),
+		InherentLikelihood: strPtr(models.LikelihoodPossible),
+		InherentImpact:     strPtr(models.ImpactMajor),
+		Recorded: &RecordedResidual{
+			Likelihood: models.LikelihoodRare, Impact: models.ImpactMinor, Score: 2, ValidUntil: &expired,
+		},
+		Now: now,
+	})
+	// With nothing acting on the risk the residual should equal the inherent 12 (high).
+	assert.Equal(t, float64(12), d.Suggested.Score)
+	assert.ElementsMatch(t, []string{ResidualFlagExpired, ResidualFlagUnderstated}, flagCodes(d))
+
+	d = DeriveResidualRisk(ResidualInputs{
+		Methodology:        models.DefaultRiskMethodology(),
+		InherentLikelihood: strPtr(models.LikelihoodRare),
+		InherentImpact:     strPtr(models.ImpactMinor),
+		Recorded:           &RecordedResidual{Likelihood: models.LikelihoodLikely, Impact: models.ImpactMinor, Score: 8},
+		Now:                now,
+	})
+	assert.Contains(t, flagCodes(d), ResidualFlagAboveInherent)
+}
+
+func TestDeriveResidualRisk_NoInherent(t *testing.T) {
+	d := DeriveResidualRisk(ResidualInputs{Methodology: models.DefaultRiskMethodology(), Now: time.Now()})
+	assert.Nil(t, d.Suggested)
+	assert.Equal(t, []string{ResidualFlagNotScored}, flagCodes(d))
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// RiskAcceptanceSweepResult counts what one risk acceptance sweep changed.
+type RiskAcceptanceSweepResult struct {
+	Expired           int64
+	RenewalsCancelled int64
+	AlertsResolved    int64
+	AlertsRaised      int64
+}
+
+// SweepRiskAcceptances reopens accepted risks once their acceptance expiry has passed, recording
+// the acceptance as expired and cancelling any renewal still awaiting a decision. The owner is
+// alerted when an acceptance comes within lead days of expiry, and the owner and the accepter
+// when it expires. Each step is a single statement, so a sweep is safe to run from several
+// workers.
+func SweepRiskAcceptances(ctx context.Context, db *sql.DB, leadDays int) (*RiskAcceptanceSweepResult, error) {
+	reopenTo := models.RiskAcceptanceExpiryStatus
+	if !models.IsValidRiskStatusTransition(models.RiskStatusAccepted, reopenTo) {
+		return nil, fmt.Errorf("risk status transition from %s to %s is not allowed", models.RiskStatusAccepted, reopenTo)
+	}
+
+	var r RiskAcceptanceSweepResult
+	steps := []struct {
+		name  string
+		query string
+		args  []interface{}
+		count *int64
+	}{
+		// acceptance_expiry is the last day the acceptance applies.
+		{"expire acceptances", `
+			WITH lapsed AS (
+				UPDATE risks SET status = $1::risk_status, accepted_at = NULL, accepted_by = NULL,
+					acceptance_justification = NULL, acceptance_expiry = NULL, updated_at = NOW()
+				WHERE status = 'accepted' AND acceptance_expiry < CURRENT_DATE
+				RETURNING id
+			)
+			UPDATE risk_acceptances a SET status = 'expired', ended_at = NOW()
+			FROM lapsed l
+			WHERE a.risk_id = l.id AND a.status = 'active'
+		`, []interface{}{reopenTo}, &r.Expired},
+
+		{"cancel renewals", `
+			UPDATE risk_acceptances req SET status = 'cancelled', ended_at = NOW(),
+				decision_comments = 'The acceptance ended before the renewal was decided'
+			WHERE req.status = 'requested'
+				AND NOT EXISTS (
+					SELECT 1 FROM risk_acceptances a
+					WHERE a.risk_id = req.risk_id AND a.status = 'active'
+				)
+		`, nil, &r.RenewalsCancelled},
+
+		{"resolve alerts", `
+			UPDATE alerts al SET status = 'resolved', resolved_at = NOW(),
+				resolution_notes = 'Risk acceptance ' || a.status::text
+			FROM risk_acceptances a
+			WHERE al.risk_id = a.risk_id
+				AND al.metadata->>'acceptance_id' = a.id::text
+				AND al.metadata->>'stage' = 'expiring'
+				AND al.status NOT IN ('resolved', 'closed')
+				AND a.status != 'active'
+		`, nil, &r.AlertsResolved},
+
+		// Alerts reference the risk's first linked control, when it has one.
+		{"raise alerts", `
+			INSERT INTO alerts (org_id, title, description, severity, status,
+				control_id, risk_id, assigned_to, assigned_at,
+				delivery_channels, tags, metadata)
+			SELECT a.org_id,
+				CASE WHEN a.status = 'expired' THEN 'Risk acceptance expired: ' ELSE 'Risk acceptance expiring: ' END ||
+					r.identifier || ' ' || r.title,
+				CASE WHEN a.status = 'expired'
+					THEN 'The acceptance of ' || r.identifier || ' expired after ' || to_char(a.expires_at, 'YYYY-MM-DD') ||
+						' and the risk has been reopened. Treat the risk or accept it again.'
+					ELSE 'The acceptance of ' || r.identifier || ' expires after ' || to_char(a.expires_at, 'YYYY-MM-DD') ||
+						'. Request a renewal or plan treatment before it lapses.'
+				END,
+				CASE WHEN a.status = 'expired' THEN 'high' ELSE 'medium' END::alert_severity,
+				'open', ctl.control_id, a.risk_id,
+				rcpt.user_id, NOW(),
+				ARRAY['in_app']::alert_delivery_channel[],
+				ARRAY['risk_acceptance'],
+				jsonb_build_object('source', 'risk_acceptance', 'acceptance_id', a.id,
+					'stage', CASE WHEN a.status = 'expired' THEN 'expired' ELSE 'expiring' END,
+					'expires_at', a.expires_at)
+			FROM risk_acceptances a
+			JOIN risks r ON r.id = a.risk_id
+			CROSS JOIN LATERAL (
+				SELECT DISTINCT u AS user_id
+				FROM unnest(CASE WHEN a.status = 'expired'
+					THEN ARRAY[r.owner_id, a.decided_by]
+					ELSE ARRAY[r.owner_id]
+				END) u
+				WHERE u IS NOT NULL
+			) rcpt
+			LEFT JOIN LATERAL (
+				SELECT rc.control_id FROM risk_controls rc
+				WHERE rc.risk_id = a.risk_id
+				ORDER BY rc.created_at
+				LIMIT 1
+			) ctl ON TRUE
+			WHERE (a.status = 'expired'
+					OR (a.status = 'active' AND a.expires_at <= CURRENT_DATE + $1::int
+						AND NOT EXISTS (
+							SELECT 1 FROM risk_acceptances req
+							WHERE req.renewal_of = a.id AND req.status = 'requested'
+						)))
+				AND NOT EXISTS (
+					SELECT 1 FROM alerts al
+					WHERE al.risk_id = a.risk_id
+						AND al.metadata->>'acceptance_id' = a.id::text
+						AND al.metadata->>'stage' = CASE WHEN a.status = 'expired' THEN 'expired' ELSE 'expiring' END
+						AND al.assigned_to = rcpt.user_id
+				)
+		`, []interface{}{leadDays}, &r.AlertsRaised},
+	}
+
+	for _, s := range steps {
+		res, err := db.ExecContext(ctx, s.query, s.args...)
+		if err != nil {
+			return &r, fmt.Errorf("%s: %w", s.name, err)
+		}
+		*s.count, _ = res.RowsAffected()
+	}
+	return &r, nil
+}
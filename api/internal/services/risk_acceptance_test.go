@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepRiskAcceptances_Counts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE risks SET status = \\$1::risk_status.*UPDATE risk_acceptances a SET status = 'expired'").
+		WithArgs(models.RiskStatusOpen).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE risk_acceptances req SET status = 'cancelled'").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE alerts al SET status = 'resolved'").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO alerts.*FROM risk_acceptances a.*LEFT JOIN LATERAL").
+		WithArgs(30).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+
+	r, err := SweepRiskAcceptances(context.Background(), db, 30)
+	require.NoError(t, err)
+	assert.Equal(t, RiskAcceptanceSweepResult{Expired: 2, RenewalsCancelled: 1, AlertsResolved: 1, AlertsRaised: 5}, *r)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSweepRiskAcceptances_StopsOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE risks SET status").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE risk_acceptances req").WillReturnError(errors.New("connection reset"))
+
+	r, err := SweepRiskAcceptances(context.Background(), db, 30)
+	assert.ErrorContains(t, err, "cancel renewals")
+	assert.Equal(t, int64(0), r.AlertsRaised)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
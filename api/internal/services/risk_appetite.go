@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// RiskAppetiteSweepResult counts what one risk appetite sweep changed.
+type RiskAppetiteSweepResult struct {
+	AlertsResolved int64
+	AlertsRaised   int64
+}
+
+// SweepRiskAppetite raises an alert to the owner of every open risk whose residual score is above
+// its effective appetite: medium severity above appetite, high above tolerance. An alert is
+// resolved once the risk moves to another position or is accepted, closed or archived, so a
+// risk that improves from above tolerance to above appetite gets a fresh, lower alert. Each step
+// is a single statement, so a sweep is safe to run from several workers.
+func SweepRiskAppetite(ctx context.Context, db *sql.DB) (*RiskAppetiteSweepResult, error) {
+	var r RiskAppetiteSweepResult
+	steps := []struct {
+		name  string
+		query string
+		count *int64
+	}{
+		{"resolve alerts", `
+			UPDATE alerts a SET status = 'resolved', resolved_at = NOW(),
+				resolution_notes = 'Risk ' || CASE
+					WHEN p.status IN ('accepted', 'closed', 'archived') THEN p.status::text
+					ELSE 'now ' || replace(p.position, '_', ' ')
+				END
+			FROM risk_appetite_positions p
+			WHERE a.risk_id = p.risk_id
+				AND a.metadata->>'source' = 'risk_appetite'
+				AND a.status NOT IN ('resolved', 'closed')
+				AND (p.status IN ('accepted', 'closed', 'archived') OR p.position != a.metadata->>'stage')
+		`, &r.AlertsResolved},
+
+		// Alerts reference the risk's first linked control, when it has one.
+		{"raise alerts", `
+			INSERT INTO alerts (org_id, title, description, severity, status,
+				control_id, risk_id, assigned_to, assigned_at,
+				delivery_channels, tags, metadata)
+			SELECT p.org_id,
+				CASE WHEN p.position = 'above_tolerance' THEN 'Risk above tolerance: ' ELSE 'Risk above appetite: ' END ||
+					r.identifier || ' ' || r.title,
+				'Residual score ' || p.residual_score || ' exceeds the ' || p.appetite_source || ' ' ||
+					CASE WHEN p.position = 'above_tolerance'
+						THEN 'tolerance of ' || p.tolerance_threshold
+						ELSE 'appetite of ' || p.appetite_threshold
+					END ||
+					'. Treat the risk further or escalate it for acceptance.',
+				CASE WHEN p.position = 'above_tolerance' THEN 'high' ELSE 'medium' END::alert_severity,
+				'open', ctl.control_id, p.risk_id,
+				r.owner_id, CASE WHEN r.owner_id IS NOT NULL THEN NOW() END,
+				ARRAY['in_app']::alert_delivery_channel[],
+				ARRAY['risk_appetite'],
+				jsonb_build_object('source', 'risk_appetite', 'stage', p.position,
+					'residual_score', p.residual_score,
+					'appetite_threshold', p.appetite_threshold,
+					'tolerance_threshold', p.tolerance_threshold,
+					'appetite_source', p.appetite_source)
+			FROM risk_appetite_positions p
+			JOIN risks r ON r.id = p.risk_id
+			LEFT JOIN LATERAL (
+				SELECT rc.control_id FROM risk_controls rc
+				WHERE rc.risk_id = p.risk_id
+				ORDER BY rc.created_at
+				LIMIT 1
+			) ctl ON TRUE
+			WHERE p.position IN ('above_appetite', 'above_tolerance')
+				AND p.status NOT IN ('accepted', 'closed', 'archived')
+				AND NOT EXISTS (
+					SELECT 1 FROM alerts a
+					WHERE a.risk_id = p.risk_id
+						AND a.metadata->>'source' = 'risk_appetite'
+						AND a.metadata->>'stage' = p.position
+						AND a.status NOT IN ('resolved', 'closed')
+				)
+		`, &r.AlertsRaised},
+	}
+
+	for _, s := range steps {
+		res, err := db.ExecContext(ctx, s.query)
+		if err != nil {
+			return &r, fmt.Errorf("%s: %w", s.name, err)
+		}
+		*s.count, _ = res.RowsAffected()
+	}
+	return &r, nil
+}
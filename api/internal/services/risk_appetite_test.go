@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepRiskAppetite_Counts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE alerts a SET status = 'resolved'.*FROM risk_appetite_positions p").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO alerts.*FROM risk_appetite_positions p.*LEFT JOIN LATERAL").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	r, err := SweepRiskAppetite(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, RiskAppetiteSweepResult{AlertsResolved: 1, AlertsRaised: 4}, *r)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSweepRiskAppetite_StopsOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE alerts a SET status = 'resolved'").WillReturnError(errors.New("connection reset"))
+
+	_, err = SweepRiskAppetite(context.Background(), db)
+	assert.ErrorContains(t, err, "resolve alerts")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+)
+
+// z95 is the standard normal quantile for the 95th percentile. Lognormal ranges treat min and
+// max as a 90% confidence interval, the convention FAIR calibration training uses.
+const z95 = 1.6448536269514722
+
+// LossSimulationInput describes one risk's FAIR model: how often loss events occur per year and
+// how much each one costs.
+type LossSimulationInput struct {
+	Frequency  models.LossRange
+	Magnitude  models.LossRange
+	Iterations int
+	Seed       int64
+}
+
+// NormalizeLossRange fills in a range's derived values. A lognormal range is fitted to min and
+// max only, so its most likely value is replaced with the median of that fit.
+func NormalizeLossRange(r models.LossRange) models.LossRange {
+	if r.Distribution == models.LossDistributionLognormal && r.Min > 0 && r.Max >= r.Min {
+		r.MostLikely = math.Sqrt(r.Min * r.Max)
+	}
+	return r
+}
+
+// ValidateLossRange checks a min / most likely / max estimate against its distribution.
+func ValidateLossRange(name string, r models.LossRange) error {
+	if !models.IsValidLossDistribution(r.Distribution) {
+		return fmt.Errorf("%s distribution must be pert or lognormal", name)
+	}
+	for _, v := range []float64{r.Min, r.MostLikely, r.Max} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%s values must be finite numbers", name)
+		}
+	}
+	if r.Min < 0 {
+		return fmt.Errorf("%s values must not be negative", name)
+	}
+	if r.Min > r.MostLikely || r.MostLikely > r.Max {
+		return fmt.Errorf("%s must satisfy min <= most_likely <= max", name)
+	}
+	if r.Distribution == models.LossDistributionLognormal && r.Min <= 0 {
+		return fmt.Errorf("%s min must be greater than zero for a lognormal distribution", name)
+	}
+	return nil
+}
+
+// ValidateLossSimulationInput checks a model before it is simulated or stored.
+func ValidateLossSimulationInput(in LossSimulationInput) error {
+	if err := ValidateLossRange("frequency", in.Frequency); err != nil {
+		return err
+	}
+	if in.Frequency.Max > models.MaxLossEventFrequency {
+		return fmt.Errorf("frequency max must be %d events per year or fewer", models.MaxLossEventFrequency)
+	}
+	if err := ValidateLossRange("magnitude", in.Magnitude); err != nil {
+		return err
+	}
+	if in.Iterations < models.MinSimulationIterations || in.Iterations > models.MaxSimulationIterations {
+		return fmt.Errorf("iterations must be between %d and %d", models.MinSimulationIterations, models.MaxSimulationIterations)
+	}
+	return nil
+}
+
+// LossSimulationCost estimates the number of values one simulated year samples for the model:
+// the frequency draw plus one magnitude per loss event at the highest estimated rate.
+func LossSimulationCost(in LossSimulationInput) int {
+	return 1 + int(math.Ceil(in.Frequency.Max))
+}
+
+// SimulateAnnualLoss runs the Monte Carlo simulation and returns the total loss of each
+// simulated year. Each year draws a loss event frequency, a Poisson number of events at that
+// rate, and a magnitude for every event. The generator is PCG seeded with (seed, 0), so the
+// same input always yields the same years in the same order.
+func SimulateAnnualLoss(in LossSimulationInput) []float64 {
+	rng := rand.New(rand.NewPCG(uint64(in.Seed), 0))
+	losses := make([]float64, in.Iterations)
+	for i := range losses {
+		rate := sampleLossRange(rng, in.Frequency)
+		events := samplePoisson(rng, rate)
+		total := 0.0
+		for e := 0; e < events; e++ {
+			total += sampleLossRange(rng, in.Magnitude)
+		}
+		losses[i] = total
+	}
+	return losses
+}
+
+// SummarizeAnnualLoss reduces simulated annual losses to the figures reported for a risk or a
+// register: annualized loss expectancy (the mean), percentiles and a loss exceedance curve.
+func SummarizeAnnualLoss(losses []float64) *models.LossSimulationSummary {
+	s := &models.LossSimulationSummary{
+		Iterations:      len(losses),
+		Percentiles:     map[string]float64{},
+		ExceedanceCurve: []models.LossExceedancePoint{},
+	}
+	if len(losses) == 0 {
+		return s
+	}
+	sorted := append([]float64(nil), losses...)
+	sort.Float64s(sorted)
+
+	var sum, nonZero float64
+	for _, l := range sorted {
+		sum += l
+		if l > 0 {
+			nonZero++
+		}
+	}
+	n := float64(len(sorted))
+	s.ALE = roundCents(sum / n)
+	var sq float64
+	for _, l := range sorted {
+		sq += (l - sum/n) * (l - sum/n)
+	}
+	s.StdDev = roundCents(math.Sqrt(sq / n))
+	s.Min = roundCents(sorted[0])
+	s.Max = roundCents(sorted[len(sorted)-1])
+	s.ProbabilityOfLoss = math.Round(nonZero/n*10000) / 10000
+	for _, p := range models.LossPercentiles {
+		s.Percentiles[fmt.Sprintf("p%d", p)] = roundCents(percentile(sorted, float64(p)/100))
+	}
+	s.ExceedanceCurve = exceedanceCurve(sorted)
+	return s
+}
+
+// AggregateAnnualLoss adds per-risk simulated years together, year by year, giving the
+// register's combined annual loss. Risks are treated as independent.
+func AggregateAnnualLoss(perRisk [][]float64) []float64 {
+	if len(perRisk) == 0 {
+		return nil
+	}
+	total := make([]float64, len(perRisk[0]))
+	for _, losses := range perRisk {
+		for i := range total {
+			if i < len(losses) {
+				total[i] += losses[i]
+			}
+		}
+	}
+	return total
+}
+
+// sampleLossRange draws one value from a PERT or lognormal estimate. Lognormal draws ignore most
+// likely: min and max alone fix the distribution as its 90% confidence interval.
+func sampleLossRange(rng *rand.Rand, r models.LossRange) float64 {
+	if r.Max <= r.Min {
+		return r.Min
+	}
+	if r.Distribution == models.LossDistributionLognormal {
+		mu := (math.Log(r.Min) + math.Log(r.Max)) / 2
+		sigma := (math.Log(r.Max) - math.Log(r.Min)) / (2 * z95)
+		return math.Exp(mu + sigma*rng.NormFloat64())
+	}
+	// PERT: a beta distribution rescaled onto [min, max] with its mode at most likely.
+	span := r.Max - r.Min
+	alpha := 1 + 4*(r.MostLikely-r.Min)/span
+	beta := 1 + 4*(r.Max-r.MostLikely)/span
+	x := sampleGamma(rng, alpha)
+	y := sampleGamma(rng, beta)
+	return r.Min + span*x/(x+y)
+}
+
+// sampleGamma draws from Gamma(shape, 1) using Marsaglia and Tsang's method. PERT shapes are
+// always at least 1, which is all the method handles.
+func sampleGamma(rng *rand.Rand, shape float64) float64 {
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// samplePoisson draws an event count at the given yearly rate. Rates up to 30 use Knuth's
+// multiplication method; above that a rounded normal approximation is accurate enough.
+func samplePoisson(rng *rand.Rand, rate float64) int {
+	if rate <= 0 {
+		return 0
+	}
+	if rate > 30 {
+		n := math.Round(rate + math.Sqrt(rate)*rng.NormFloat64())
+		if n < 0 {
+			return 0
+		}
+		return int(n)
+	}
+	limit := math.Exp(-rate)
+	k := 0
+	for p := rng.Float64(); p > limit; p *= rng.Float64() {
+		k++
+	}
+	return k
+}
+
+// percentile interpolates linearly between the closest ranks of sorted values.
+func percentile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+// exceedanceCurve samples the probability that a year's loss exceeds each of a range of loss
+// amounts, from zero up to the largest simulated loss.
+func exceedanceCurve(sorted []float64) []models.LossExceedancePoint {
+	points := []models.LossExceedancePoint{}
+	maxLoss := sorted[len(sorted)-1]
+	if maxLoss <= 0 {
+		return points
+	}
+	n := len(sorted)
+	steps := models.LossExceedanceCurvePoints - 1
+	for i := 0; i <= steps; i++ {
+		loss := maxLoss * float64(i) / float64(steps)
+		// Index of the first simulated year with a loss above this amount.
+		idx := sort.Search(n, func(j int) bool { return sorted[j] > loss })
+		points = append(points, models.LossExceedancePoint{
+			Loss:        roundCents(loss),
+			Probability: math.Round(float64(n-idx)/float64(n)*10000) / 10000,
+		})
+	}
+	return points
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLossInput() LossSimulationInput {
+	return LossSimulationInput{
+		Frequency:  models.LossRange{Min: 0.1, MostLikely: 0.5, Max: 2, Distribution: models.LossDistributionPERT},
+		Magnitude:  models.LossRange{Min: 50000, MostLikely: 200000, Max: 2000000, Distribution: models.LossDistributionLognormal},
+		Iterations: 20000,
+		Seed:       42,
+	}
+}
+
+func TestValidateLossSimulationInput(t *testing.T) {
+	require.NoError(t, ValidateLossSimulationInput(testLossInput()))
+
+	cases := map[string]func(*LossSimulationInput){
+		"bad distribution":  func(in *LossSimulationInput) { in.Frequency.Distribution = "uniform" },
+		"out of order":      func(in *LossSimulationInput) { in.Magnitude.MostLikely = 5000000 },
+		"negative":          func(in *LossSimulationInput) { in.Frequency.Min = -1 },
+		"lognormal zero":    func(in *LossSimulationInput) { in.Magnitude.Min = 0 },
+		"too frequent":      func(in *LossSimulationInput) { in.Frequency.Max = 1000 },
+		"too few iteration": func(in *LossSimulationInput) { in.Iterations = 10 },
+		"not a number":      func(in *LossSimulationInput) { in.Magnitude.Max = math.Inf(1) },
+	}
+	for name, mutate := range cases {
+		in := testLossInput()
+		mutate(&in)
+		assert.Error(t, ValidateLossSimulationInput(in), name)
+	}
+}
+
+func TestNormalizeLossRange(t *testing.T) {
+	lognormal := models.LossRange{Min: 10000, MostLikely: 5, Max: 1000000, Distribution: models.LossDistributionLognormal}
+	n := NormalizeLossRange(lognormal)
+	assert.InDelta(t, 100000, n.MostLikely, 0.001, "most likely is the median of the 90% interval")
+	assert.NoError(t, ValidateLossRange("magnitude", n))
+
+	pert := models.LossRange{Min: 1, MostLikely: 2, Max: 10, Distribution: models.LossDistributionPERT}
+	assert.Equal(t, pert, NormalizeLossRange(pert))
+}
+
+func TestSimulateAnnualLoss_Seeded(t *testing.T) {
+	a := SimulateAnnualLoss(testLossInput())
+	b := SimulateAnnualLoss(testLossInput())
+	assert.Equal(t, a, b, "same seed yields the same simulation")
+
+	other := testLossInput()
+	other.Seed = 7
+	assert.NotEqual(t, a, SimulateAnnualLoss(other))
+}
+
+func TestSimulateAnnualLoss_MatchesExpectation(t *testing.T) {
+	in := testLossInput()
+	s := SummarizeAnnualLoss(SimulateAnnualLoss(in))
+
+	// PERT mean is (min + 4 * most likely + max) / 6; the lognormal mean is exp(mu + sigma^2 / 2).
+	freq := (0.1 + 4*0.5 + 2) / 6
+	mu := (math.Log(50000) + math.Log(2000000)) / 2
+	sigma := (math.Log(2000000) - math.Log(50000)) / (2 * z95)
+	expected := freq * math.Exp(mu+sigma*sigma/2)
+	assert.InEpsilon(t, expected, s.ALE, 0.05)
+
+	assert.Equal(t, 20000, s.Iterations)
+	assert.True(t, s.Percentiles["p10"] <= s.Percentiles["p50"])
+	assert.True(t, s.Percentiles["p50"] <= s.Percentiles["p90"])
+	assert.True(t, s.Percentiles["p95"] <= s.Percentiles["p99"])
+	assert.True(t, s.Percentiles["p99"] <= s.Max)
+	assert.Greater(t, s.ProbabilityOfLoss, 0.3)
+	assert.Less(t, s.ProbabilityOfLoss, 0.7)
+}
+
+func TestSampleLossRange_PERT(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 0))
+	r := models.LossRange{Min: 1000, MostLikely: 1500, Max: 5000, Distribution: models.LossDistributionPERT}
+	sum := 0.0
+	for i := 0; i < 20000; i++ {
+		v := sampleLossRange(rng, r)
+		require.True(t, v >= r.Min && v <= r.Max, "%v outside the range", v)
+		sum += v
+	}
+	assert.InEpsilon(t, (1000+4*1500+5000)/6.0, sum/20000, 0.02)
+
+	in := testLossInput()
+	in.Frequency = models.LossRange{Distribution: models.LossDistributionPERT}
+	s := SummarizeAnnualLoss(SimulateAnnualLoss(in))
+	assert.Equal(t, 0.0, s.ALE)
+	assert.Empty(t, s.ExceedanceCurve, "no losses, no curve")
+}
+
+func TestSummarizeAnnualLoss(t *testing.T) {
+	s := SummarizeAnnualLoss([]float64{0, 0, 100, 200, 300, 400, 500, 600, 700, 800, 900})
+	assert.Equal(t, 409.09, s.ALE)
+	assert.Equal(t, 400.0, s.Percentiles["p50"])
+	assert.Equal(t, 900.0, s.Max)
+	assert.Equal(t, 0.8182, s.ProbabilityOfLoss)
+
+	require.Len(t, s.ExceedanceCurve, models.LossExceedanceCurvePoints)
+	assert.Equal(t, models.LossExceedancePoint{Loss: 0, Probability: 0.8182}, s.ExceedanceCurve[0])
+	last := s.ExceedanceCurve[len(s.ExceedanceCurve)-1]
+	assert.Equal(t, models.LossExceedancePoint{Loss: 900, Probability: 0}, last)
+	for i := 1; i < len(s.ExceedanceCurve); i++ {
+		assert.LessOrEqual(t, s.ExceedanceCurve[i].Probability, s.ExceedanceCurve[i-1].Probability)
+	}
+}
+
+func TestAggregateAnnualLoss(t *testing.T) {
+	assert.Equal(t, []float64{5, 7, 9}, AggregateAnnualLoss([][]float64{{1, 2, 3}, {4, 5, 6}}))
+	assert.Nil(t, AggregateAnnualLoss(nil))
+}
@@ -0,0 +1,100 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// AuditExportWorker builds requested audit evidence export packages.
+type AuditExportWorker struct {
+	DB       *sql.DB
+	Store    services.AuditExportStore
+	Signer   *services.ExportSigner
+	Interval time.Duration
+	WorkerID string
+}
+
+// NewAuditExportWorker creates a new audit export worker.
+func NewAuditExportWorker(db *sql.DB, store services.AuditExportStore, signer *services.ExportSigner, interval time.Duration) *AuditExportWorker {
+	return &AuditExportWorker{
+		DB:       db,
+		Store:    store,
+		Signer:   signer,
+		Interval: interval,
+		WorkerID: fmt.Sprintf("exporter-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the audit export worker loop.
+func (w *AuditExportWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).
+		Str("signing_key_id", w.Signer.KeyID()).Msg("Audit export worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Audit export worker stopped")
+			return
+		case <-ticker.C:
+			w.buildPending(ctx)
+		}
+	}
+}
+
+// buildPending claims requested exports by moving them to building (so concurrent workers skip
+// them), then builds each one. Claims left in building by a worker that died are picked up
+// again after 30 minutes.
+func (w *AuditExportWorker) buildPending(ctx context.Context) {
+	rows, err := w.DB.QueryContext(ctx, `
+		UPDATE audit_evidence_exports
+		SET status = 'building', attempts = attempts + 1, started_at = NOW()
+		WHERE id IN (
+			SELECT id FROM audit_evidence_exports
+			WHERE status = 'pending'
+			   OR (status = 'building' AND started_at < NOW() - INTERVAL '30 minutes')
+			ORDER BY created_at
+			LIMIT 2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, org_id, audit_id, requested_by, attempts
+	`)
+	if err != nil {
+		log.Error().Err(err).Msg("Exporter: failed to claim pending exports")
+		return
+	}
+
+	var jobs []services.AuditExportJob
+	for rows.Next() {
+		var j services.AuditExportJob
+		if err := rows.Scan(&j.ID, &j.OrgID, &j.AuditID, &j.RequestedBy, &j.Attempts); err != nil {
+			log.Error().Err(err).Msg("Exporter: failed to scan claimed export")
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	rows.Close()
+
+	for _, j := range jobs {
+		res, err := services.RunAuditEvidenceExport(ctx, w.DB, w.Store, w.Signer, j)
+		if err != nil {
+			log.Error().Err(err).Str("export_id", j.ID).Int("attempt", j.Attempts).Msg("Exporter: failed to build export")
+			continue
+		}
+		ev := log.Info()
+		if res.ChecksumMismatches > 0 {
+			ev = log.Warn()
+		}
+		ev.Str("export_id", j.ID).Str("audit_id", j.AuditID).
+			Int("files", res.FilesIncluded).Int("checksum_mismatches", res.ChecksumMismatches).
+			Int64("size", res.FileSize).Msg("Exporter: export built")
+	}
+}
@@ -0,0 +1,102 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// EvidenceCollectionWorker runs scheduled evidence collection jobs.
+type EvidenceCollectionWorker struct {
+	DB       *sql.DB
+	Store    services.ObjectStore
+	Interval time.Duration
+	WorkerID string
+}
+
+// NewEvidenceCollectionWorker creates a new evidence collection worker.
+// store may be nil, in which case runs are recorded without storing content.
+func NewEvidenceCollectionWorker(db *sql.DB, store services.ObjectStore, interval time.Duration) *EvidenceCollectionWorker {
+	return &EvidenceCollectionWorker{
+		DB:       db,
+		Store:    store,
+		Interval: interval,
+		WorkerID: fmt.Sprintf("collector-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the evidence collection worker loop.
+func (w *EvidenceCollectionWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).Msg("Evidence collection worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Evidence collection worker stopped")
+			return
+		case <-ticker.C:
+			w.runDueJobs(ctx)
+		}
+	}
+}
+
+// runDueJobs claims due jobs by pushing next_run_at forward (so concurrent workers skip them),
+// then pulls content for HTTP/S3 sources and records missed runs for agent jobs whose upload
+// did not arrive within the interval.
+func (w *EvidenceCollectionWorker) runDueJobs(ctx context.Context) {
+	rows, err := w.DB.QueryContext(ctx, `
+		UPDATE evidence_collection_jobs SET next_run_at = NOW() + make_interval(mins => schedule_interval_min)
+		WHERE id IN (
+			SELECT id FROM evidence_collection_jobs
+			WHERE is_active = TRUE AND next_run_at IS NOT NULL AND next_run_at <= NOW()
+			ORDER BY next_run_at
+			LIMIT 20
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, org_id, source_type
+	`)
+	if err != nil {
+		log.Error().Err(err).Msg("Collector: failed to claim due jobs")
+		return
+	}
+
+	type dueJob struct{ ID, OrgID, SourceType string }
+	var jobs []dueJob
+	for rows.Next() {
+		var j dueJob
+		if err := rows.Scan(&j.ID, &j.OrgID, &j.SourceType); err != nil {
+			log.Error().Err(err).Msg("Collector: failed to scan due job")
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	rows.Close()
+
+	for _, j := range jobs {
+		if j.SourceType == models.CollectionSourceAgentUpload {
+			if err := services.RecordMissedCollection(ctx, w.DB, j.OrgID, j.ID); err != nil {
+				log.Error().Err(err).Str("job_id", j.ID).Msg("Collector: failed to record missed run")
+			}
+			continue
+		}
+
+		run, err := services.RunEvidenceCollectionJob(ctx, w.DB, w.Store, j.OrgID, j.ID,
+			models.CollectionTriggerScheduled, nil, nil)
+		if err != nil {
+			log.Error().Err(err).Str("job_id", j.ID).Msg("Collector: failed to run job")
+			continue
+		}
+		if run.Status != models.CollectionRunSuccess {
+			log.Warn().Str("job_id", j.ID).Str("error", *run.ErrorMessage).Msg("Collector: collection failed")
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// EvidenceExtractionWorker extracts text from scanned evidence files for content search.
+type EvidenceExtractionWorker struct {
+	DB       *sql.DB
+	Store    services.EvidenceContentStore
+	Interval time.Duration
+	WorkerID string
+}
+
+// NewEvidenceExtractionWorker creates a new evidence text extraction worker.
+func NewEvidenceExtractionWorker(db *sql.DB, store services.EvidenceContentStore, interval time.Duration) *EvidenceExtractionWorker {
+	return &EvidenceExtractionWorker{
+		DB:       db,
+		Store:    store,
+		Interval: interval,
+		WorkerID: fmt.Sprintf("extractor-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the evidence extraction worker loop.
+func (w *EvidenceExtractionWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).Msg("Evidence extraction worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Evidence extraction worker stopped")
+			return
+		case <-ticker.C:
+			w.indexPending(ctx)
+		}
+	}
+}
+
+// indexPending extracts text for artifacts that passed scanning but have no content row yet.
+// Only scanned files are parsed. Two workers picking the same artifact is harmless: the
+// content row is upserted.
+func (w *EvidenceExtractionWorker) indexPending(ctx context.Context) {
+	rows, err := w.DB.QueryContext(ctx, `
+		SELECT ea.id, ea.org_id, ea.object_key, ea.mime_type, ea.file_size
+		FROM evidence_artifacts ea
+		WHERE ea.processing_status IN ('passed', 'not_scanned')
+		  AND NOT EXISTS (SELECT 1 FROM evidence_contents ct WHERE ct.artifact_id = ea.id)
+		ORDER BY ea.processed_at
+		LIMIT 10
+	`)
+	if err != nil {
+		log.Error().Err(err).Msg("Extractor: failed to list pending evidence")
+		return
+	}
+
+	var items []services.EvidenceExtractionItem
+	for rows.Next() {
+		var it services.EvidenceExtractionItem
+		if err := rows.Scan(&it.ID, &it.OrgID, &it.ObjectKey, &it.MIMEType, &it.FileSize); err != nil {
+			log.Error().Err(err).Msg("Extractor: failed to scan pending evidence")
+			continue
+		}
+		items = append(items, it)
+	}
+	rows.Close()
+
+	for _, it := range items {
+		status, err := services.IndexEvidenceContent(ctx, w.DB, w.Store, it)
+		if err != nil {
+			log.Error().Err(err).Str("artifact_id", it.ID).Msg("Extractor: failed to index evidence")
+			continue
+		}
+		log.Debug().Str("artifact_id", it.ID).Str("status", status).Msg("Extractor: indexed evidence")
+	}
+}
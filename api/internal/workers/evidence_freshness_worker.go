@@ -0,0 +1,68 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// EvidenceFreshnessWorker expires stale evidence and keeps renewal tasks and freshness alerts
+// up to date.
+type EvidenceFreshnessWorker struct {
+	DB       *sql.DB
+	Interval time.Duration
+	WorkerID string
+}
+
+// NewEvidenceFreshnessWorker creates a new evidence freshness worker.
+func NewEvidenceFreshnessWorker(db *sql.DB, interval time.Duration) *EvidenceFreshnessWorker {
+	return &EvidenceFreshnessWorker{
+		DB:       db,
+		Interval: interval,
+		WorkerID: fmt.Sprintf("freshness-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the evidence freshness worker loop.
+func (w *EvidenceFreshnessWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).Msg("Evidence freshness worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Evidence freshness worker stopped")
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+		}
+	}
+}
+
+func (w *EvidenceFreshnessWorker) sweep(ctx context.Context) {
+	r, err := services.SweepEvidenceFreshness(ctx, w.DB, models.FreshnessWarningDays)
+	if err != nil {
+		log.Error().Err(err).Msg("Freshness: sweep failed")
+	}
+	if r == nil {
+		return
+	}
+	if r.Expired+r.TasksOpened+r.TasksClosed+r.AlertsRaised+r.AlertsEscalated+r.AlertsResolved > 0 {
+		log.Info().
+			Int64("expired", r.Expired).
+			Int64("tasks_opened", r.TasksOpened).
+			Int64("tasks_closed", r.TasksClosed).
+			Int64("alerts_raised", r.AlertsRaised).
+			Int64("alerts_escalated", r.AlertsEscalated).
+			Int64("alerts_resolved", r.AlertsResolved).
+			Msg("Freshness: sweep complete")
+	}
+}
@@ -0,0 +1,103 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// EvidenceProcessingWorker validates and malware-scans uploaded evidence objects.
+type EvidenceProcessingWorker struct {
+	DB       *sql.DB
+	Store    services.EvidenceContentStore
+	Scanner  services.Scanner
+	Interval time.Duration
+	WorkerID string
+}
+
+// NewEvidenceProcessingWorker creates a new evidence processing worker.
+// scanner may be nil, in which case only content-type validation is performed.
+func NewEvidenceProcessingWorker(db *sql.DB, store services.EvidenceContentStore, scanner services.Scanner, interval time.Duration) *EvidenceProcessingWorker {
+	return &EvidenceProcessingWorker{
+		DB:       db,
+		Store:    store,
+		Scanner:  scanner,
+		Interval: interval,
+		WorkerID: fmt.Sprintf("processor-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the evidence processing worker loop.
+func (w *EvidenceProcessingWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).
+		Bool("scanner", w.Scanner != nil).Msg("Evidence processing worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Evidence processing worker stopped")
+			return
+		case <-ticker.C:
+			w.processPending(ctx)
+		}
+	}
+}
+
+// processPending claims uploaded artifacts awaiting processing by moving them to scanning
+// (so concurrent workers skip them), then inspects each one. Claims left in scanning by a
+// worker that died are picked up again after 15 minutes. Drafts are only claimed once the
+// upload has been confirmed, which is when their checksum is recorded. With a scanner
+// configured, artifacts processed while there was none are claimed too, after new uploads.
+func (w *EvidenceProcessingWorker) processPending(ctx context.Context) {
+	rows, err := w.DB.QueryContext(ctx, `
+		UPDATE evidence_artifacts
+		SET processing_status = 'scanning', processing_attempts = processing_attempts + 1,
+			processing_started_at = NOW()
+		WHERE id IN (
+			SELECT id FROM evidence_artifacts
+			WHERE (processing_status = 'pending' AND (checksum_sha256 IS NOT NULL OR status <> 'draft'))
+			   OR (processing_status = 'scanning' AND processing_started_at < NOW() - INTERVAL '15 minutes')
+			   OR (processing_status = 'not_scanned' AND $1)
+			ORDER BY processing_status = 'not_scanned', created_at
+			LIMIT 10
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, org_id, object_key, mime_type, processing_attempts
+	`, w.Scanner != nil)
+	if err != nil {
+		log.Error().Err(err).Msg("Processor: failed to claim pending evidence")
+		return
+	}
+
+	var items []services.EvidenceProcessingItem
+	for rows.Next() {
+		var it services.EvidenceProcessingItem
+		if err := rows.Scan(&it.ID, &it.OrgID, &it.ObjectKey, &it.MIMEType, &it.Attempts); err != nil {
+			log.Error().Err(err).Msg("Processor: failed to scan claimed evidence")
+			continue
+		}
+		items = append(items, it)
+	}
+	rows.Close()
+
+	for _, it := range items {
+		out, err := services.ProcessEvidenceArtifact(ctx, w.DB, w.Store, w.Scanner, it)
+		if err != nil {
+			log.Error().Err(err).Str("artifact_id", it.ID).Int("attempt", it.Attempts).Msg("Processor: failed to process evidence")
+			continue
+		}
+		if out.Status == models.ProcessingQuarantined {
+			log.Warn().Str("artifact_id", it.ID).Str("org_id", it.OrgID).Str("reason", *out.Reason).
+				Msg("Processor: evidence quarantined")
+		}
+	}
+}
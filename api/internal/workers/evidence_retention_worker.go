@@ -0,0 +1,63 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// purgeBatchSize is the number of artifacts purged per tick.
+const purgeBatchSize = 100
+
+// EvidenceRetentionWorker purges evidence files whose retention period has lapsed.
+type EvidenceRetentionWorker struct {
+	DB       *sql.DB
+	Store    services.EvidenceObjectRemover
+	Interval time.Duration
+	WorkerID string
+}
+
+// NewEvidenceRetentionWorker creates a new evidence retention worker.
+func NewEvidenceRetentionWorker(db *sql.DB, store services.EvidenceObjectRemover, interval time.Duration) *EvidenceRetentionWorker {
+	return &EvidenceRetentionWorker{
+		DB:       db,
+		Store:    store,
+		Interval: interval,
+		WorkerID: fmt.Sprintf("retention-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the evidence retention worker loop.
+func (w *EvidenceRetentionWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).Msg("Evidence retention worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Evidence retention worker stopped")
+			return
+		case <-ticker.C:
+			w.purgeExpired(ctx)
+		}
+	}
+}
+
+// purgeExpired purges one batch per tick; a backlog drains over successive ticks.
+func (w *EvidenceRetentionWorker) purgeExpired(ctx context.Context) {
+	purged, errs := services.PurgeExpiredEvidence(ctx, w.DB, w.Store, purgeBatchSize)
+	for _, err := range errs {
+		log.Error().Err(err).Msg("Retention: failed to purge evidence")
+	}
+	for _, p := range purged {
+		log.Info().Str("artifact_id", p.ID).Str("org_id", p.OrgID).Str("rule_id", p.RuleID).
+			Time("retain_until", p.RetainUntil).Msg("Retention: purged evidence file")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/services"
 	"github.com/rs/zerolog/log"
 )
 
@@ -44,6 +45,8 @@ func (w *MonitoringWorker) Run(ctx context.Context) {
 			w.processDueTests(ctx)
 			w.checkSLABreaches(ctx)
 			w.unsuppressExpiredAlerts(ctx)
+			w.refreshControlEffectiveness(ctx)
+			w.remindPendingAttestations(ctx)
 		}
 	}
 }
@@ -332,6 +335,64 @@ func (w *MonitoringWorker) unsuppressExpiredAlerts(ctx context.Context) {
 	}
 }
 
+// refreshControlEffectiveness recomputes effectiveness for controls not scored in the last day.
+func (w *MonitoringWorker) refreshControlEffectiveness(ctx context.Context) {
+	rows, err := w.DB.QueryContext(ctx, `
+		SELECT id, org_id FROM controls
+		WHERE status <> 'deprecated'
+			AND (effectiveness_calculated_at IS NULL OR effectiveness_calculated_at < NOW() - INTERVAL '24 hours')
+		ORDER BY effectiveness_calculated_at NULLS FIRST
+		LIMIT 50
+	`)
+	if err != nil {
+		log.Error().Err(err).Msg("Worker: failed to query controls for effectiveness refresh")
+		return
+	}
+
+	type staleControl struct{ ID, OrgID string }
+	var stale []staleControl
+	for rows.Next() {
+		var sc staleControl
+		if err := rows.Scan(&sc.ID, &sc.OrgID); err == nil {
+			stale = append(stale, sc)
+		}
+	}
+	rows.Close()
+
+	refreshed := 0
+	for _, sc := range stale {
+		if _, err := services.RecalculateControlEffectiveness(ctx, w.DB, sc.OrgID, sc.ID, "scheduled", nil); err != nil {
+			log.Error().Err(err).Str("control_id", sc.ID).Msg("Worker: failed to refresh control effectiveness")
+			continue
+		}
+		refreshed++
+	}
+	if refreshed > 0 {
+		log.Info().Int("count", refreshed).Msg("Worker: refreshed control effectiveness scores")
+	}
+}
+
+// remindPendingAttestations sends automatic reminders for campaigns with a reminder interval.
+func (w *MonitoringWorker) remindPendingAttestations(ctx context.Context) {
+	res, err := w.DB.ExecContext(ctx, `
+		UPDATE control_attestations ca
+		SET reminder_sent_at = NOW(), reminder_count = ca.reminder_count + 1
+		FROM attestation_campaigns ac
+		WHERE ac.id = ca.campaign_id
+			AND ac.status = 'active'
+			AND ac.reminder_interval_days IS NOT NULL
+			AND ca.status = 'pending'
+			AND COALESCE(ca.reminder_sent_at, ca.created_at) < NOW() - INTERVAL '1 day' * ac.reminder_interval_days
+	`)
+	if err != nil {
+		log.Error().Err(err).Msg("Worker: failed to send attestation reminders")
+		return
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Info().Int64("count", n).Msg("Worker: sent attestation reminders")
+	}
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
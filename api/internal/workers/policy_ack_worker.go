@@ -0,0 +1,89 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/middleware"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// PolicyAckWorker keeps policy acknowledgement campaigns up to date: assigning new users,
+// sending reminders and completing finished campaigns. Completed campaigns get the same roster
+// evidence and audit entry as a campaign closed by its last acknowledgement.
+type PolicyAckWorker struct {
+	DB       *sql.DB
+	Store    services.ObjectStore
+	Interval time.Duration
+	WorkerID string
+}
+
+// NewPolicyAckWorker creates a new policy acknowledgement worker. store may be nil, in which case
+// completed campaigns are audited but no roster evidence is stored.
+func NewPolicyAckWorker(db *sql.DB, store services.ObjectStore, interval time.Duration) *PolicyAckWorker {
+	return &PolicyAckWorker{
+		DB:       db,
+		Store:    store,
+		Interval: interval,
+		WorkerID: fmt.Sprintf("policy-ack-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the policy acknowledgement worker loop.
+func (w *PolicyAckWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).Msg("Policy acknowledgement worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Policy acknowledgement worker stopped")
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+		}
+	}
+}
+
+func (w *PolicyAckWorker) sweep(ctx context.Context) {
+	r, err := services.SweepPolicyAcknowledgements(ctx, w.DB)
+	if err != nil {
+		log.Error().Err(err).Msg("PolicyAck: sweep failed")
+	}
+	if r == nil {
+		return
+	}
+	if r.Cancelled+r.Assigned+r.Reminded+r.CampaignsCompleted > 0 {
+		log.Info().
+			Int64("cancelled", r.Cancelled).
+			Int64("assigned", r.Assigned).
+			Int64("reminded", r.Reminded).
+			Int64("campaigns_completed", r.CampaignsCompleted).
+			Msg("PolicyAck: sweep complete")
+	}
+
+	for _, campaign := range r.Completed {
+		w.recordCompletion(ctx, campaign)
+	}
+}
+
+// recordCompletion snapshots a completed campaign's roster as evidence and audits the completion.
+// The campaign is already completed, so a storage failure only loses the automatic snapshot.
+func (w *PolicyAckWorker) recordCompletion(ctx context.Context, campaign services.CompletedPolicyAckCampaign) {
+	metadata := map[string]interface{}{"completed_by": "sweep"}
+	if w.Store == nil {
+		log.Warn().Str("campaign_id", campaign.ID).Msg("PolicyAck: no object storage, skipping roster evidence")
+	} else if stored, _, err := services.StorePolicyAckEvidence(ctx, w.DB, w.Store, campaign.OrgID, campaign.ID, nil); err != nil {
+		log.Error().Err(err).Str("campaign_id", campaign.ID).Msg("PolicyAck: failed to store roster evidence")
+	} else {
+		metadata["evidence_artifact_id"] = stored.ID
+	}
+	middleware.LogAuditWithOrg(&campaign.OrgID, nil, "policy_ack_campaign.completed", "policy_ack_campaign", &campaign.ID,
+		metadata, "", "")
+}
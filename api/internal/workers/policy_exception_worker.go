@@ -0,0 +1,67 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// PolicyExceptionWorker expires policy exceptions past their expiry date and alerts requesters
+// ahead of and at expiry.
+type PolicyExceptionWorker struct {
+	DB       *sql.DB
+	Interval time.Duration
+	LeadDays int
+	WorkerID string
+}
+
+// NewPolicyExceptionWorker creates a new policy exception worker.
+func NewPolicyExceptionWorker(db *sql.DB, interval time.Duration) *PolicyExceptionWorker {
+	return &PolicyExceptionWorker{
+		DB:       db,
+		Interval: interval,
+		LeadDays: models.PolicyExceptionExpiryLeadDays,
+		WorkerID: fmt.Sprintf("policy-exception-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the policy exception worker loop.
+func (w *PolicyExceptionWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).Msg("Policy exception worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Policy exception worker stopped")
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+		}
+	}
+}
+
+func (w *PolicyExceptionWorker) sweep(ctx context.Context) {
+	r, err := services.SweepPolicyExceptions(ctx, w.DB, w.LeadDays)
+	if err != nil {
+		log.Error().Err(err).Msg("PolicyException: sweep failed")
+	}
+	if r == nil {
+		return
+	}
+	if r.Expired+r.AlertsResolved+r.AlertsRaised > 0 {
+		log.Info().
+			Int64("expired", r.Expired).
+			Int64("alerts_resolved", r.AlertsResolved).
+			Int64("alerts_raised", r.AlertsRaised).
+			Msg("PolicyException: sweep complete")
+	}
+}
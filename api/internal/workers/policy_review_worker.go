@@ -0,0 +1,73 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// PolicyReviewWorker opens scheduled policy review tasks ahead of next_review_at, closes them
+// when the policy is republished and escalates overdue reviews.
+type PolicyReviewWorker struct {
+	DB             *sql.DB
+	Interval       time.Duration
+	LeadDays       int
+	EscalationDays int
+	WorkerID       string
+}
+
+// NewPolicyReviewWorker creates a new policy review worker.
+func NewPolicyReviewWorker(db *sql.DB, interval time.Duration) *PolicyReviewWorker {
+	return &PolicyReviewWorker{
+		DB:             db,
+		Interval:       interval,
+		LeadDays:       models.PolicyReviewLeadDays,
+		EscalationDays: models.PolicyReviewEscalationDays,
+		WorkerID:       fmt.Sprintf("policy-review-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the policy review worker loop.
+func (w *PolicyReviewWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).Msg("Policy review worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Policy review worker stopped")
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+		}
+	}
+}
+
+func (w *PolicyReviewWorker) sweep(ctx context.Context) {
+	r, err := services.SweepPolicyReviews(ctx, w.DB, w.LeadDays, w.EscalationDays)
+	if err != nil {
+		log.Error().Err(err).Msg("PolicyReview: sweep failed")
+	}
+	if r == nil {
+		return
+	}
+	if r.Completed+r.Cancelled+r.Opened+r.Escalated+r.AlertsResolved+r.AlertsEscalated+r.AlertsRaised > 0 {
+		log.Info().
+			Int64("completed", r.Completed).
+			Int64("cancelled", r.Cancelled).
+			Int64("opened", r.Opened).
+			Int64("escalated", r.Escalated).
+			Int64("alerts_resolved", r.AlertsResolved).
+			Int64("alerts_escalated", r.AlertsEscalated).
+			Int64("alerts_raised", r.AlertsRaised).
+			Msg("PolicyReview: sweep complete")
+	}
+}
@@ -0,0 +1,68 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/models"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// RiskAcceptanceWorker reopens risks whose acceptance has expired and alerts owners and
+// accepters ahead of and at expiry.
+type RiskAcceptanceWorker struct {
+	DB       *sql.DB
+	Interval time.Duration
+	LeadDays int
+	WorkerID string
+}
+
+// NewRiskAcceptanceWorker creates a new risk acceptance worker.
+func NewRiskAcceptanceWorker(db *sql.DB, interval time.Duration) *RiskAcceptanceWorker {
+	return &RiskAcceptanceWorker{
+		DB:       db,
+		Interval: interval,
+		LeadDays: models.RiskAcceptanceExpiryLeadDays,
+		WorkerID: fmt.Sprintf("risk-acceptance-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the risk acceptance worker loop.
+func (w *RiskAcceptanceWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).Msg("Risk acceptance worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Risk acceptance worker stopped")
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+		}
+	}
+}
+
+func (w *RiskAcceptanceWorker) sweep(ctx context.Context) {
+	r, err := services.SweepRiskAcceptances(ctx, w.DB, w.LeadDays)
+	if err != nil {
+		log.Error().Err(err).Msg("RiskAcceptance: sweep failed")
+	}
+	if r == nil {
+		return
+	}
+	if r.Expired+r.RenewalsCancelled+r.AlertsResolved+r.AlertsRaised > 0 {
+		log.Info().
+			Int64("expired", r.Expired).
+			Int64("renewals_cancelled", r.RenewalsCancelled).
+			Int64("alerts_resolved", r.AlertsResolved).
+			Int64("alerts_raised", r.AlertsRaised).
+			Msg("RiskAcceptance: sweep complete")
+	}
+}
@@ -0,0 +1,63 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/half-paul/raisin-protect/api/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// RiskAppetiteWorker alerts risk owners when a residual score breaches appetite or tolerance and
+// resolves those alerts once the breach clears.
+type RiskAppetiteWorker struct {
+	DB       *sql.DB
+	Interval time.Duration
+	WorkerID string
+}
+
+// NewRiskAppetiteWorker creates a new risk appetite worker.
+func NewRiskAppetiteWorker(db *sql.DB, interval time.Duration) *RiskAppetiteWorker {
+	return &RiskAppetiteWorker{
+		DB:       db,
+		Interval: interval,
+		WorkerID: fmt.Sprintf("risk-appetite-%s", uuid.New().String()[:8]),
+	}
+}
+
+// Run starts the risk appetite worker loop.
+func (w *RiskAppetiteWorker) Run(ctx context.Context) {
+	log.Info().Str("worker_id", w.WorkerID).Dur("interval", w.Interval).Msg("Risk appetite worker started")
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker_id", w.WorkerID).Msg("Risk appetite worker stopped")
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+		}
+	}
+}
+
+func (w *RiskAppetiteWorker) sweep(ctx context.Context) {
+	r, err := services.SweepRiskAppetite(ctx, w.DB)
+	if err != nil {
+		log.Error().Err(err).Msg("RiskAppetite: sweep failed")
+	}
+	if r == nil {
+		return
+	}
+	if r.AlertsResolved+r.AlertsRaised > 0 {
+		log.Info().
+			Int64("alerts_resolved", r.AlertsResolved).
+			Int64("alerts_raised", r.AlertsRaised).
+			Msg("RiskAppetite: sweep complete")
+	}
+}
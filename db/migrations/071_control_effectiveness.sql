@@ -0,0 +1,71 @@
+-- Migration: 071_control_effectiveness.sql
+-- Description: Computed control effectiveness score + rating with append-only history
+-- Created: 2026-10-18
+-- Feature: Control effectiveness scoring engine
+--
+-- Numbering note: 053–070 are reserved by the Sprint 8/9 schema plans.
+
+-- ============================================================================
+-- CONTROLS: CACHED EFFECTIVENESS (latest computed value, used for list sorting)
+-- ============================================================================
+
+DO $$ BEGIN
+    ALTER TABLE controls ADD COLUMN effectiveness_score NUMERIC(5,2)
+        CHECK (effectiveness_score IS NULL OR (effectiveness_score >= 0 AND effectiveness_score <= 100));
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE controls ADD COLUMN effectiveness_rating control_effectiveness NOT NULL DEFAULT 'not_assessed';
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE controls ADD COLUMN effectiveness_calculated_at TIMESTAMPTZ;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_controls_effectiveness ON controls (org_id, effectiveness_rating);
+CREATE INDEX IF NOT EXISTS idx_controls_effectiveness_stale ON controls (effectiveness_calculated_at NULLS FIRST)
+    WHERE status <> 'deprecated';
+
+COMMENT ON COLUMN controls.effectiveness_score IS 'Latest computed effectiveness (0–100); NULL when not assessed';
+COMMENT ON COLUMN controls.effectiveness_rating IS 'Band for effectiveness_score: effective ≥80, partially_effective ≥50, else ineffective';
+COMMENT ON COLUMN controls.effectiveness_calculated_at IS 'When effectiveness was last computed (worker refreshes daily)';
+
+-- ============================================================================
+-- CONTROL EFFECTIVENESS HISTORY (append-only)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS control_effectiveness_history (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    control_id          UUID NOT NULL REFERENCES controls(id) ON DELETE CASCADE,
+
+    -- Result
+    score               NUMERIC(5,2) CHECK (score IS NULL OR (score >= 0 AND score <= 100)),
+    rating              control_effectiveness NOT NULL,
+    components          JSONB NOT NULL DEFAULT '[]',
+
+    -- Provenance
+    trigger_type        VARCHAR(20) NOT NULL DEFAULT 'scheduled'
+                        CHECK (trigger_type IN ('scheduled', 'manual')),
+    calculated_by       UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    -- Immutable: no updated_at
+    calculated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_control_effectiveness_history_control
+    ON control_effectiveness_history (control_id, calculated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_control_effectiveness_history_org
+    ON control_effectiveness_history (org_id, calculated_at DESC);
+
+COMMENT ON TABLE control_effectiveness_history IS 'Append-only history of computed control effectiveness scores';
+COMMENT ON COLUMN control_effectiveness_history.components IS 'Per-signal breakdown: [{"name","weight","score","samples","detail"}]';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'control.effectiveness_recalculated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,131 @@
+-- Migration: 072_control_attestations.sql
+-- Description: Control ownership attestation campaigns and per-control attestations
+-- Created: 2026-10-18
+-- Feature: Control ownership attestation campaigns
+
+-- ============================================================================
+-- ENUMS
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE attestation_campaign_status AS ENUM (
+        'active',
+        'completed',
+        'cancelled'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+COMMENT ON TYPE attestation_campaign_status IS 'Lifecycle of a control attestation campaign';
+
+DO $$ BEGIN
+    CREATE TYPE control_attestation_status AS ENUM (
+        'pending',
+        'attested',
+        'exception',
+        'cancelled'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+COMMENT ON TYPE control_attestation_status IS 'attested = operating as described; exception = owner reports it is not';
+
+-- Completed attestations are stored as evidence artifacts
+ALTER TYPE evidence_type ADD VALUE IF NOT EXISTS 'attestation';
+
+-- ============================================================================
+-- ATTESTATION CAMPAIGNS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS attestation_campaigns (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+
+    name                    VARCHAR(255) NOT NULL,
+    description             TEXT,
+    status                  attestation_campaign_status NOT NULL DEFAULT 'active',
+    due_date                DATE,
+
+    -- Automatic reminders for pending attestations (NULL = manual only)
+    reminder_interval_days  INT CHECK (reminder_interval_days IS NULL OR reminder_interval_days > 0),
+
+    created_by              UUID REFERENCES users(id) ON DELETE SET NULL,
+    completed_at            TIMESTAMPTZ,
+    cancelled_at            TIMESTAMPTZ,
+
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_attestation_campaigns_org
+    ON attestation_campaigns (org_id, status);
+
+DROP TRIGGER IF EXISTS trg_attestation_campaigns_updated_at ON attestation_campaigns;
+CREATE TRIGGER trg_attestation_campaigns_updated_at
+    BEFORE UPDATE ON attestation_campaigns
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+-- ============================================================================
+-- CONTROL ATTESTATIONS (one per control per campaign)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS control_attestations (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    campaign_id             UUID NOT NULL REFERENCES attestation_campaigns(id) ON DELETE CASCADE,
+    control_id              UUID NOT NULL REFERENCES controls(id) ON DELETE CASCADE,
+
+    -- Owner asked to attest (control owner at campaign creation)
+    attester_id             UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+
+    -- Response
+    status                  control_attestation_status NOT NULL DEFAULT 'pending',
+    statement               TEXT,
+    supporting_artifact_ids UUID[] NOT NULL DEFAULT '{}',
+    attested_by             UUID REFERENCES users(id) ON DELETE SET NULL,
+    attested_at             TIMESTAMPTZ,
+
+    -- Generated evidence record of the attestation
+    evidence_artifact_id    UUID REFERENCES evidence_artifacts(id) ON DELETE SET NULL,
+
+    -- Notification tracking
+    reminder_sent_at        TIMESTAMPTZ,
+    reminder_count          INT NOT NULL DEFAULT 0,
+
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_control_attestation UNIQUE (campaign_id, control_id),
+    -- A response requires a statement and timestamp
+    CONSTRAINT chk_attestation_response CHECK (
+        status NOT IN ('attested', 'exception') OR (statement IS NOT NULL AND attested_at IS NOT NULL)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_control_attestations_campaign
+    ON control_attestations (campaign_id, status);
+
+CREATE INDEX IF NOT EXISTS idx_control_attestations_attester_pending
+    ON control_attestations (attester_id, status)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_control_attestations_control
+    ON control_attestations (control_id, attested_at DESC);
+
+DROP TRIGGER IF EXISTS trg_control_attestations_updated_at ON control_attestations;
+CREATE TRIGGER trg_control_attestations_updated_at
+    BEFORE UPDATE ON control_attestations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE control_attestations IS 'Owner attestation that a control operates as described, per campaign';
+COMMENT ON COLUMN control_attestations.supporting_artifact_ids IS 'Existing evidence artifacts the owner cited with the attestation';
+COMMENT ON COLUMN control_attestations.evidence_artifact_id IS 'Evidence artifact generated from the completed attestation';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'attestation_campaign.created'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'attestation_campaign.cancelled'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'attestation_campaign.completed'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'control_attestation.submitted'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,122 @@
+-- Migration: 073_control_inheritance.sql
+-- Description: Control inheritance from providers or other orgs (shared responsibility model)
+-- Created: 2026-10-18
+-- Feature: Control inheritance
+
+-- ============================================================================
+-- ENUMS
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE control_inheritance_source AS ENUM (
+        'provider',
+        'organization'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+COMMENT ON TYPE control_inheritance_source IS 'provider = external service provider (e.g. AWS); organization = another org in this tenant';
+
+DO $$ BEGIN
+    CREATE TYPE control_responsibility AS ENUM (
+        'customer',
+        'provider',
+        'shared'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+COMMENT ON TYPE control_responsibility IS 'Shared responsibility split for an inherited control';
+
+-- ============================================================================
+-- ORG INHERITANCE GRANTS (source org allows a consumer org to inherit its controls)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS org_inheritance_grants (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    consumer_org_id     UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+
+    notes               TEXT,
+    granted_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at          TIMESTAMPTZ,
+    revoked_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_inheritance_grant_not_self CHECK (org_id <> consumer_org_id)
+);
+
+-- One active grant per org pair
+CREATE UNIQUE INDEX IF NOT EXISTS uq_org_inheritance_grant_active
+    ON org_inheritance_grants (org_id, consumer_org_id)
+    WHERE revoked_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_org_inheritance_grants_consumer
+    ON org_inheritance_grants (consumer_org_id)
+    WHERE revoked_at IS NULL;
+
+DROP TRIGGER IF EXISTS trg_org_inheritance_grants_updated_at ON org_inheritance_grants;
+CREATE TRIGGER trg_org_inheritance_grants_updated_at
+    BEFORE UPDATE ON org_inheritance_grants
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE org_inheritance_grants IS 'Source org (org_id) allows consumer_org_id to inherit its controls and view their status read-only';
+
+-- ============================================================================
+-- CONTROL INHERITANCES (one per inheriting control)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS control_inheritances (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    control_id              UUID NOT NULL REFERENCES controls(id) ON DELETE CASCADE,
+
+    -- Source
+    source_type             control_inheritance_source NOT NULL,
+    provider_name           VARCHAR(255),
+    provider_reference      VARCHAR(1000),
+    source_org_id           UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    source_control_id       UUID REFERENCES controls(id) ON DELETE SET NULL,
+
+    -- Responsibility split
+    responsibility          control_responsibility NOT NULL DEFAULT 'shared',
+    customer_responsibility TEXT,
+
+    created_by              UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_control_inheritance UNIQUE (control_id),
+    CONSTRAINT chk_control_inheritance_source CHECK (
+        (source_type = 'provider' AND provider_name IS NOT NULL AND source_org_id IS NULL) OR
+        (source_type = 'organization' AND source_org_id IS NOT NULL)
+    ),
+    CONSTRAINT chk_control_inheritance_not_self CHECK (source_org_id IS NULL OR source_org_id <> org_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_control_inheritances_org
+    ON control_inheritances (org_id, responsibility);
+
+CREATE INDEX IF NOT EXISTS idx_control_inheritances_source_control
+    ON control_inheritances (source_control_id)
+    WHERE source_control_id IS NOT NULL;
+
+DROP TRIGGER IF EXISTS trg_control_inheritances_updated_at ON control_inheritances;
+CREATE TRIGGER trg_control_inheritances_updated_at
+    BEFORE UPDATE ON control_inheritances
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE control_inheritances IS 'Marks a control as inherited from a provider or another org';
+COMMENT ON COLUMN control_inheritances.provider_reference IS 'Where the provider documents the control (e.g. SOC 2 report, AWS Artifact)';
+COMMENT ON COLUMN control_inheritances.customer_responsibility IS 'What the customer must still do for customer/shared responsibility';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'control.inheritance_set'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'control.inheritance_removed'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'org.inheritance_granted'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'org.inheritance_revoked'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,119 @@
+-- Migration: 074_evidence_collection_jobs.sql
+-- Description: Scheduled evidence collection jobs and their run history
+-- Created: 2026-10-18
+-- Feature: Evidence collection automation
+
+-- ============================================================================
+-- ENUMS
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE evidence_collection_source AS ENUM (
+        'http_json',
+        's3_listing',
+        'agent_upload'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+COMMENT ON TYPE evidence_collection_source IS 'http_json = GET a JSON endpoint; s3_listing = list an S3-compatible bucket; agent_upload = command output pushed by an agent';
+
+DO $$ BEGIN
+    CREATE TYPE evidence_collection_run_status AS ENUM (
+        'success',
+        'failed',
+        'missed'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+COMMENT ON TYPE evidence_collection_run_status IS 'missed = an agent_upload job was not uploaded within its schedule';
+
+-- ============================================================================
+-- EVIDENCE COLLECTION JOBS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS evidence_collection_jobs (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+
+    name                    VARCHAR(255) NOT NULL,
+    description             TEXT,
+    is_active               BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- Source
+    source_type             evidence_collection_source NOT NULL,
+    source_config           JSONB NOT NULL DEFAULT '{}',
+
+    -- Produced artifact
+    artifact_title          VARCHAR(500) NOT NULL,
+    evidence_type           evidence_type NOT NULL DEFAULT 'api_response',
+    freshness_period_days   INT CHECK (freshness_period_days IS NULL OR freshness_period_days > 0),
+    tags                    TEXT[] DEFAULT '{}',
+    control_ids             UUID[] NOT NULL DEFAULT '{}',
+    current_artifact_id     UUID REFERENCES evidence_artifacts(id) ON DELETE SET NULL,
+
+    -- Schedule
+    schedule_interval_min   INT NOT NULL CHECK (schedule_interval_min >= 15),
+    next_run_at             TIMESTAMPTZ,
+    last_run_at             TIMESTAMPTZ,
+    last_run_status         evidence_collection_run_status,
+    consecutive_failures    INT NOT NULL DEFAULT 0,
+
+    created_by              UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_evidence_collection_jobs_org
+    ON evidence_collection_jobs (org_id);
+
+CREATE INDEX IF NOT EXISTS idx_evidence_collection_jobs_due
+    ON evidence_collection_jobs (next_run_at)
+    WHERE is_active = TRUE;
+
+DROP TRIGGER IF EXISTS trg_evidence_collection_jobs_updated_at ON evidence_collection_jobs;
+CREATE TRIGGER trg_evidence_collection_jobs_updated_at
+    BEFORE UPDATE ON evidence_collection_jobs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE evidence_collection_jobs IS 'Scheduled evidence pulls; each run creates a new version of current_artifact_id';
+COMMENT ON COLUMN evidence_collection_jobs.source_config IS 'Source settings (url/headers, endpoint/bucket/prefix/credentials, or expected command)';
+COMMENT ON COLUMN evidence_collection_jobs.control_ids IS 'Controls each new version is linked to';
+
+-- ============================================================================
+-- EVIDENCE COLLECTION RUNS (append-only)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS evidence_collection_runs (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    job_id              UUID NOT NULL REFERENCES evidence_collection_jobs(id) ON DELETE CASCADE,
+
+    status              evidence_collection_run_status NOT NULL,
+    trigger_type        VARCHAR(20) NOT NULL DEFAULT 'scheduled'
+                        CHECK (trigger_type IN ('scheduled', 'manual', 'agent')),
+
+    -- Produced version
+    artifact_id         UUID REFERENCES evidence_artifacts(id) ON DELETE SET NULL,
+    artifact_version    INT,
+    file_size           BIGINT,
+    checksum_sha256     VARCHAR(64),
+
+    error_message       TEXT,
+    triggered_by        UUID REFERENCES users(id) ON DELETE SET NULL,
+    started_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at        TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_evidence_collection_runs_job
+    ON evidence_collection_runs (job_id, started_at DESC);
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence_collection_job.created'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence_collection_job.updated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence_collection_job.deleted'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence_collection_job.run'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,75 @@
+-- Migration: 075_evidence_ingestion_api_keys.sql
+-- Description: Scoped org API keys and idempotent evidence ingestion for CI/CD pipelines
+-- Created: 2026-10-18
+-- Feature: Evidence ingestion API
+
+-- ============================================================================
+-- ORG API KEYS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS org_api_keys (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+
+    name                VARCHAR(255) NOT NULL,
+    key_prefix          VARCHAR(16) NOT NULL,
+    key_hash            VARCHAR(64) NOT NULL,
+    scopes              TEXT[] NOT NULL DEFAULT '{}',
+
+    expires_at          TIMESTAMPTZ,
+    last_used_at        TIMESTAMPTZ,
+    revoked_at          TIMESTAMPTZ,
+    revoked_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    created_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_org_api_key_hash UNIQUE (key_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_api_keys_org
+    ON org_api_keys (org_id, created_at DESC);
+
+DROP TRIGGER IF EXISTS trg_org_api_keys_updated_at ON org_api_keys;
+CREATE TRIGGER trg_org_api_keys_updated_at
+    BEFORE UPDATE ON org_api_keys
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE org_api_keys IS 'Machine credentials scoped to an org; only the SHA-256 of the key is stored';
+COMMENT ON COLUMN org_api_keys.key_prefix IS 'First characters of the key, shown to identify it after creation';
+COMMENT ON COLUMN org_api_keys.scopes IS 'Granted scopes, e.g. evidence:ingest';
+
+-- ============================================================================
+-- EVIDENCE INGESTIONS (idempotency key → evidence version chain)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS evidence_ingestions (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+
+    idempotency_key     VARCHAR(255) NOT NULL,
+    artifact_id         UUID NOT NULL REFERENCES evidence_artifacts(id) ON DELETE CASCADE,
+    api_key_id          UUID REFERENCES org_api_keys(id) ON DELETE SET NULL,
+    ingest_count        INT NOT NULL DEFAULT 1,
+
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_evidence_ingestion_key UNIQUE (org_id, idempotency_key)
+);
+
+DROP TRIGGER IF EXISTS trg_evidence_ingestions_updated_at ON evidence_ingestions;
+CREATE TRIGGER trg_evidence_ingestions_updated_at
+    BEFORE UPDATE ON evidence_ingestions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE evidence_ingestions IS 'Maps a pipeline idempotency key to the root artifact of the version chain it feeds';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'api_key.created'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'api_key.revoked'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence.ingested'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,57 @@
+-- Migration: 076_evidence_integrity.sql
+-- Description: Server-verified checksums, per-chain hash linking and integrity check history
+-- Created: 2026-10-18
+-- Feature: Tamper-evident evidence storage
+
+-- ============================================================================
+-- EVIDENCE ARTIFACTS: hash chain columns
+-- ============================================================================
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN checksum_verified_at TIMESTAMPTZ;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN previous_chain_hash VARCHAR(64);
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN chain_hash VARCHAR(64);
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+COMMENT ON COLUMN evidence_artifacts.checksum_verified_at IS 'When the server hashed the stored object; NULL means the checksum is client-supplied';
+COMMENT ON COLUMN evidence_artifacts.previous_chain_hash IS 'chain_hash of the previous version (empty for version 1)';
+COMMENT ON COLUMN evidence_artifacts.chain_hash IS 'SHA-256 over previous_chain_hash, artifact id, version and checksum_sha256';
+
+-- ============================================================================
+-- EVIDENCE INTEGRITY CHECKS (append-only)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS evidence_integrity_checks (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    artifact_id         UUID NOT NULL REFERENCES evidence_artifacts(id) ON DELETE CASCADE,
+
+    verified            BOOLEAN NOT NULL,
+    versions_checked    INT NOT NULL DEFAULT 0,
+    objects_rehashed    INT NOT NULL DEFAULT 0,
+    details             JSONB NOT NULL DEFAULT '[]',
+
+    checked_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    checked_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_evidence_integrity_checks_artifact
+    ON evidence_integrity_checks (artifact_id, checked_at DESC);
+
+COMMENT ON TABLE evidence_integrity_checks IS 'Results of re-hashing stored evidence objects and verifying the version hash chain';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence.integrity_verified'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence.checksum_mismatch'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
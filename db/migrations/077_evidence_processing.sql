@@ -0,0 +1,71 @@
+-- Migration: 077_evidence_processing.sql
+-- Description: Post-upload content validation and malware scanning state for evidence artifacts
+-- Created: 2026-10-18
+-- Feature: Evidence content validation and malware scanning
+
+-- ============================================================================
+-- ENUM
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE evidence_processing_status AS ENUM ('pending', 'scanning', 'passed', 'not_scanned', 'quarantined', 'failed');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+-- ============================================================================
+-- EVIDENCE ARTIFACTS: processing columns
+-- ============================================================================
+
+-- Existing artifacts start as pending so they are scanned before their next download.
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN processing_status evidence_processing_status NOT NULL DEFAULT 'pending';
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN processing_attempts INT NOT NULL DEFAULT 0;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN processing_started_at TIMESTAMPTZ;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN processed_at TIMESTAMPTZ;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN detected_mime_type VARCHAR(255);
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN scan_engine VARCHAR(50);
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN scan_signature VARCHAR(255);
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN processing_error TEXT;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN quarantine_object_key VARCHAR(1024);
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_evidence_artifacts_processing_queue
+    ON evidence_artifacts (created_at) WHERE processing_status IN ('pending', 'scanning');
+
+COMMENT ON COLUMN evidence_artifacts.processing_status IS 'Content validation / malware scan state; downloads require passed, or not_scanned when no scanner is configured';
+COMMENT ON COLUMN evidence_artifacts.detected_mime_type IS 'MIME type sniffed from the stored object''s magic bytes';
+COMMENT ON COLUMN evidence_artifacts.scan_signature IS 'Malware signature reported by the scanner, if any';
+COMMENT ON COLUMN evidence_artifacts.quarantine_object_key IS 'Object key the file was moved to when quarantined';
@@ -0,0 +1,38 @@
+-- Migration: 078_evidence_contents.sql
+-- Description: Extracted text of evidence files for full-text search
+-- Created: 2026-10-18
+-- Feature: Full-text search inside evidence files
+
+-- ============================================================================
+-- ENUM
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE evidence_extraction_status AS ENUM ('extracted', 'unsupported', 'failed');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+-- ============================================================================
+-- EVIDENCE CONTENTS (one row per artifact version)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS evidence_contents (
+    artifact_id         UUID PRIMARY KEY REFERENCES evidence_artifacts(id) ON DELETE CASCADE,
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+
+    status              evidence_extraction_status NOT NULL,
+    extractor           VARCHAR(20),
+    content             TEXT NOT NULL DEFAULT '',
+    content_length      INT NOT NULL DEFAULT 0,
+    truncated           BOOLEAN NOT NULL DEFAULT FALSE,
+    error               TEXT,
+    content_tsv         TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', content)) STORED,
+
+    extracted_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_evidence_contents_org ON evidence_contents (org_id);
+CREATE INDEX IF NOT EXISTS idx_evidence_contents_search ON evidence_contents USING GIN (content_tsv);
+
+COMMENT ON TABLE evidence_contents IS 'Text extracted from evidence files (PDF, DOCX, XLSX, CSV, JSON, text) for content search';
+COMMENT ON COLUMN evidence_contents.content_length IS 'Length in bytes of the full extracted text before truncation';
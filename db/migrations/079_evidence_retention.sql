@@ -0,0 +1,180 @@
+-- Migration: 079_evidence_retention.sql
+-- Description: Evidence retention rules, purge tracking and legal hold
+-- Created: 2026-10-18
+-- Feature: Evidence retention and legal hold
+
+-- ============================================================================
+-- ENUM
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE evidence_retention_trigger AS ENUM (
+        'created',
+        'collected',
+        'superseded',
+        'expired'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+COMMENT ON TYPE evidence_retention_trigger IS 'Event the retention period counts from: created_at, collection_date, superseded_at or expires_at';
+
+-- ============================================================================
+-- EVIDENCE RETENTION RULES
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS evidence_retention_rules (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+
+    name                VARCHAR(255) NOT NULL,
+    description         TEXT,
+    is_active           BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- Scope (NULL = any)
+    evidence_type       evidence_type,
+    framework_id        UUID REFERENCES frameworks(id) ON DELETE CASCADE,
+
+    -- Period
+    retention_trigger   evidence_retention_trigger NOT NULL DEFAULT 'superseded',
+    retention_days      INT NOT NULL CHECK (retention_days > 0),
+
+    created_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_evidence_retention_rules_org
+    ON evidence_retention_rules (org_id)
+    WHERE is_active = TRUE;
+
+DROP TRIGGER IF EXISTS trg_evidence_retention_rules_updated_at ON evidence_retention_rules;
+CREATE TRIGGER trg_evidence_retention_rules_updated_at
+    BEFORE UPDATE ON evidence_retention_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE evidence_retention_rules IS 'Minimum retention periods for evidence; an artifact is purged only once every matching rule has lapsed';
+COMMENT ON COLUMN evidence_retention_rules.framework_id IS 'Limit to evidence linked (directly or via a control mapping) to this framework';
+
+-- ============================================================================
+-- EVIDENCE ARTIFACTS: retention and legal hold columns
+-- ============================================================================
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN superseded_at TIMESTAMPTZ;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN legal_hold BOOLEAN NOT NULL DEFAULT FALSE;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN legal_hold_reason TEXT;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN legal_hold_by UUID REFERENCES users(id) ON DELETE SET NULL;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN legal_hold_at TIMESTAMPTZ;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN purged_at TIMESTAMPTZ;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN purged_by_rule_id UUID REFERENCES evidence_retention_rules(id) ON DELETE SET NULL;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+-- Existing superseded rows have no exact timestamp; the last update is the best estimate.
+UPDATE evidence_artifacts SET superseded_at = updated_at
+WHERE status = 'superseded' AND superseded_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_evidence_artifacts_legal_hold
+    ON evidence_artifacts (org_id)
+    WHERE legal_hold = TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_evidence_artifacts_purged
+    ON evidence_artifacts (org_id, purged_at)
+    WHERE purged_at IS NOT NULL;
+
+COMMENT ON COLUMN evidence_artifacts.superseded_at IS 'When the artifact became superseded (set by trigger)';
+COMMENT ON COLUMN evidence_artifacts.legal_hold IS 'Set on the root artifact; holds the whole version chain against deletion and purge';
+COMMENT ON COLUMN evidence_artifacts.purged_at IS 'When the stored file was deleted under a retention rule; the record is kept as a tombstone';
+
+-- ============================================================================
+-- TRIGGER: stamp superseded_at
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION set_evidence_superseded_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status = 'superseded' AND OLD.status IS DISTINCT FROM 'superseded' THEN
+        NEW.superseded_at = NOW();
+    ELSIF NEW.status <> 'superseded' THEN
+        NEW.superseded_at = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_evidence_artifacts_superseded_at ON evidence_artifacts;
+CREATE TRIGGER trg_evidence_artifacts_superseded_at
+    BEFORE UPDATE OF status ON evidence_artifacts
+    FOR EACH ROW EXECUTE FUNCTION set_evidence_superseded_at();
+
+-- ============================================================================
+-- VIEW: retention deadline per artifact and matching rule
+-- ============================================================================
+-- retain_until is NULL while the rule's trigger event has not happened yet
+-- (e.g. a "superseded" rule on a current artifact).
+
+CREATE OR REPLACE VIEW evidence_retention_deadlines AS
+SELECT
+    ea.id AS artifact_id,
+    ea.org_id,
+    r.id AS rule_id,
+    r.retention_trigger,
+    r.retention_days,
+    CASE r.retention_trigger
+        WHEN 'created'    THEN ea.created_at
+        WHEN 'collected'  THEN ea.collection_date::timestamptz
+        WHEN 'superseded' THEN ea.superseded_at
+        WHEN 'expired'    THEN ea.expires_at
+    END + make_interval(days => r.retention_days) AS retain_until
+FROM evidence_artifacts ea
+JOIN evidence_retention_rules r
+    ON r.org_id = ea.org_id
+    AND r.is_active = TRUE
+    AND (r.evidence_type IS NULL OR r.evidence_type = ea.evidence_type)
+    AND (r.framework_id IS NULL OR EXISTS (
+        SELECT 1
+        FROM evidence_artifacts chain
+        JOIN evidence_links el ON el.artifact_id = chain.id
+        LEFT JOIN control_mappings cm ON cm.control_id = el.control_id
+        JOIN requirements req ON req.id = COALESCE(el.requirement_id, cm.requirement_id)
+        JOIN framework_versions fv ON fv.id = req.framework_version_id
+        WHERE COALESCE(chain.parent_artifact_id, chain.id) = COALESCE(ea.parent_artifact_id, ea.id)
+          AND fv.framework_id = r.framework_id
+    ));
+
+COMMENT ON VIEW evidence_retention_deadlines IS 'One row per artifact and matching active retention rule; links anywhere in the version chain count for framework scope';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence_retention_rule.created'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence_retention_rule.updated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence_retention_rule.deleted'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence.legal_hold_set'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence.legal_hold_released'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
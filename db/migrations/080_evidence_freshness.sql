@@ -0,0 +1,77 @@
+-- Migration: 080_evidence_freshness.sql
+-- Description: Evidence auto-expiry, renewal tasks and freshness alerts
+-- Created: 2026-10-18
+-- Feature: Evidence freshness automation
+
+-- ============================================================================
+-- ENUM
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE evidence_renewal_status AS ENUM ('open', 'completed', 'cancelled');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+-- ============================================================================
+-- EVIDENCE RENEWAL TASKS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS evidence_renewal_tasks (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    artifact_id         UUID NOT NULL REFERENCES evidence_artifacts(id) ON DELETE CASCADE,
+    control_id          UUID REFERENCES controls(id) ON DELETE SET NULL,
+
+    status              evidence_renewal_status NOT NULL DEFAULT 'open',
+    assigned_to         UUID REFERENCES users(id) ON DELETE SET NULL,
+    due_at              TIMESTAMPTZ NOT NULL,
+
+    -- Completion
+    renewed_artifact_id UUID REFERENCES evidence_artifacts(id) ON DELETE SET NULL,
+    completed_at        TIMESTAMPTZ,
+    notes               TEXT,
+
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- At most one open task per artifact version.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_evidence_renewal_tasks_open
+    ON evidence_renewal_tasks (artifact_id)
+    WHERE status = 'open';
+
+CREATE INDEX IF NOT EXISTS idx_evidence_renewal_tasks_org
+    ON evidence_renewal_tasks (org_id, status, due_at);
+
+CREATE INDEX IF NOT EXISTS idx_evidence_renewal_tasks_assignee
+    ON evidence_renewal_tasks (assigned_to)
+    WHERE status = 'open';
+
+DROP TRIGGER IF EXISTS trg_evidence_renewal_tasks_updated_at ON evidence_renewal_tasks;
+CREATE TRIGGER trg_evidence_renewal_tasks_updated_at
+    BEFORE UPDATE ON evidence_renewal_tasks
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE evidence_renewal_tasks IS 'Requests to collect a fresh version of evidence before (or after) it expires';
+COMMENT ON COLUMN evidence_renewal_tasks.assigned_to IS 'Owner of the first linked control, falling back to the uploader';
+COMMENT ON COLUMN evidence_renewal_tasks.due_at IS 'The artifact''s expires_at when the task was opened';
+
+-- ============================================================================
+-- ALERTS: evidence source
+-- ============================================================================
+
+DO $$ BEGIN
+    ALTER TABLE alerts ADD COLUMN evidence_artifact_id UUID REFERENCES evidence_artifacts(id) ON DELETE CASCADE;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_evidence ON alerts (evidence_artifact_id)
+    WHERE evidence_artifact_id IS NOT NULL;
+
+COMMENT ON COLUMN alerts.evidence_artifact_id IS 'Evidence whose staleness raised this alert (one alert per linked control)';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence_renewal_task.updated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,74 @@
+-- Migration: 081_audit_evidence_exports.sql
+-- Description: Signed ZIP exports of accepted audit evidence
+-- Created: 2026-10-18
+-- Feature: Audit evidence bundle export
+
+-- ============================================================================
+-- ENUM
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE audit_export_status AS ENUM ('pending', 'building', 'completed', 'failed');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+-- ============================================================================
+-- AUDIT EVIDENCE EXPORTS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS audit_evidence_exports (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    audit_id            UUID NOT NULL REFERENCES audits(id) ON DELETE CASCADE,
+
+    status              audit_export_status NOT NULL DEFAULT 'pending',
+    attempts            INT NOT NULL DEFAULT 0,
+    requested_by        UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    -- Result
+    object_key          VARCHAR(1000),
+    file_size           BIGINT,
+    checksum_sha256     VARCHAR(64),
+    manifest_sha256     VARCHAR(64),
+    signature           TEXT,
+    signing_key_id      VARCHAR(64),
+    request_count       INT NOT NULL DEFAULT 0,
+    artifact_count      INT NOT NULL DEFAULT 0,
+    files_included      INT NOT NULL DEFAULT 0,
+    checksum_mismatches INT NOT NULL DEFAULT 0,
+    error_message       TEXT,
+
+    started_at          TIMESTAMPTZ,
+    completed_at        TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_evidence_exports_audit
+    ON audit_evidence_exports (audit_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_audit_evidence_exports_queue
+    ON audit_evidence_exports (created_at)
+    WHERE status IN ('pending', 'building');
+
+-- An audit has at most one export being built at a time
+CREATE UNIQUE INDEX IF NOT EXISTS uq_audit_evidence_exports_in_progress
+    ON audit_evidence_exports (audit_id)
+    WHERE status IN ('pending', 'building');
+
+DROP TRIGGER IF EXISTS trg_audit_evidence_exports_updated_at ON audit_evidence_exports;
+CREATE TRIGGER trg_audit_evidence_exports_updated_at
+    BEFORE UPDATE ON audit_evidence_exports
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE audit_evidence_exports IS 'ZIP bundles of accepted audit evidence with a signed manifest, built by a background worker';
+COMMENT ON COLUMN audit_evidence_exports.signature IS 'Base64 Ed25519 signature over manifest.json (also shipped as manifest.json.sig)';
+COMMENT ON COLUMN audit_evidence_exports.signing_key_id IS 'Fingerprint of the public key that verifies the signature';
+COMMENT ON COLUMN audit_evidence_exports.checksum_mismatches IS 'Files whose SHA-256 at export time differed from the recorded checksum';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'audit.evidence_export_requested'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'audit.evidence_export_downloaded'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,50 @@
+-- Migration: 082_evidence_redaction.sql
+-- Description: Screenshot redaction / annotation and access-restricted originals
+-- Created: 2026-10-18
+-- Feature: Screenshot capture evidence with redaction
+
+-- ============================================================================
+-- ACCESS RESTRICTION ON EVIDENCE ARTIFACTS
+-- ============================================================================
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN access_restricted BOOLEAN NOT NULL DEFAULT FALSE;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE evidence_artifacts ADD COLUMN access_restricted_reason TEXT;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+COMMENT ON COLUMN evidence_artifacts.access_restricted IS 'Original content may contain sensitive data; only CISO / compliance managers can download it';
+
+-- ============================================================================
+-- EVIDENCE REDACTIONS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS evidence_redactions (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    source_artifact_id  UUID NOT NULL REFERENCES evidence_artifacts(id) ON DELETE CASCADE,
+    result_artifact_id  UUID REFERENCES evidence_artifacts(id) ON DELETE SET NULL,
+    regions             JSONB NOT NULL DEFAULT '[]',
+    overlay_lines       TEXT[] NOT NULL DEFAULT '{}',
+    reason              TEXT,
+    created_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_evidence_redactions_source ON evidence_redactions (source_artifact_id);
+CREATE INDEX IF NOT EXISTS idx_evidence_redactions_result ON evidence_redactions (result_artifact_id);
+
+COMMENT ON TABLE evidence_redactions IS 'Server-side redactions of screenshot evidence; each produces a new artifact version';
+COMMENT ON COLUMN evidence_redactions.regions IS 'Redaction rectangles in source pixel coordinates: [{x, y, width, height}]';
+COMMENT ON COLUMN evidence_redactions.overlay_lines IS 'Caption lines stamped onto the result (timestamp, collector, URL)';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence.redacted'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'evidence.restricted_download'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
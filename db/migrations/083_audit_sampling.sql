@@ -0,0 +1,113 @@
+-- Migration: 083_audit_sampling.sql
+-- Description: Populations and samples on audit requests, with evidence tracked per sample item
+-- Created: 2026-10-18
+-- Feature: Audit sampling workflow
+
+-- ============================================================================
+-- ENUM
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE audit_sample_method AS ENUM ('random', 'manual');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+-- ============================================================================
+-- POPULATIONS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS audit_request_populations (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    audit_id            UUID NOT NULL REFERENCES audits(id) ON DELETE CASCADE,
+    request_id          UUID NOT NULL REFERENCES audit_requests(id) ON DELETE CASCADE,
+    name                VARCHAR(255) NOT NULL,
+    source_file_name    VARCHAR(255),
+    key_column          VARCHAR(255),
+    columns             TEXT[] NOT NULL DEFAULT '{}',
+    item_count          INT NOT NULL DEFAULT 0,
+    checksum_sha256     VARCHAR(64) NOT NULL,
+    uploaded_by         UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_audit_request_population UNIQUE (request_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_request_populations_audit ON audit_request_populations (audit_id);
+
+DROP TRIGGER IF EXISTS trg_audit_request_populations_updated_at ON audit_request_populations;
+CREATE TRIGGER trg_audit_request_populations_updated_at
+    BEFORE UPDATE ON audit_request_populations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE audit_request_populations IS 'Population list (e.g. all change tickets in the period) a request is sampled from';
+COMMENT ON COLUMN audit_request_populations.checksum_sha256 IS 'SHA-256 over the item keys in position order, so a recorded random seed can be replayed against the same population';
+
+CREATE TABLE IF NOT EXISTS audit_population_items (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    population_id       UUID NOT NULL REFERENCES audit_request_populations(id) ON DELETE CASCADE,
+    position            INT NOT NULL CHECK (position > 0),
+    item_key            VARCHAR(255) NOT NULL,
+    attributes          JSONB NOT NULL DEFAULT '{}',
+
+    CONSTRAINT uq_audit_population_item_key UNIQUE (population_id, item_key),
+    CONSTRAINT uq_audit_population_item_position UNIQUE (population_id, position)
+);
+
+-- ============================================================================
+-- SAMPLES
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS audit_samples (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    audit_id            UUID NOT NULL REFERENCES audits(id) ON DELETE CASCADE,
+    request_id          UUID NOT NULL REFERENCES audit_requests(id) ON DELETE CASCADE,
+    population_id       UUID NOT NULL REFERENCES audit_request_populations(id) ON DELETE CASCADE,
+    method              audit_sample_method NOT NULL,
+    seed                BIGINT,
+    sample_size         INT NOT NULL CHECK (sample_size > 0),
+    population_size     INT NOT NULL,
+    population_checksum VARCHAR(64) NOT NULL,
+    notes               TEXT,
+    selected_by         UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_audit_sample_request UNIQUE (request_id),
+    CONSTRAINT chk_audit_sample_seed CHECK (method <> 'random' OR seed IS NOT NULL)
+);
+
+COMMENT ON TABLE audit_samples IS 'Sample drawn from a request population; random samples record their seed so the selection can be reproduced';
+
+CREATE TABLE IF NOT EXISTS audit_sample_items (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    sample_id           UUID NOT NULL REFERENCES audit_samples(id) ON DELETE CASCADE,
+    population_item_id  UUID NOT NULL REFERENCES audit_population_items(id) ON DELETE CASCADE,
+    sample_order        INT NOT NULL,
+
+    CONSTRAINT uq_audit_sample_item UNIQUE (sample_id, population_item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_sample_items_sample ON audit_sample_items (sample_id, sample_order);
+
+-- ============================================================================
+-- EVIDENCE PER SAMPLE ITEM
+-- ============================================================================
+
+DO $$ BEGIN
+    ALTER TABLE audit_evidence_links ADD COLUMN sample_item_id UUID REFERENCES audit_sample_items(id) ON DELETE SET NULL;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_audit_evidence_links_sample_item
+    ON audit_evidence_links (sample_item_id) WHERE sample_item_id IS NOT NULL;
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'audit_request.population_uploaded'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'audit_request.sample_selected'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
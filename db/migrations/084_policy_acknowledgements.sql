@@ -0,0 +1,139 @@
+-- Migration: 084_policy_acknowledgements.sql
+-- Description: Workforce acknowledgement campaigns for published policy versions
+-- Created: 2026-10-18
+-- Feature: Policy acknowledgement campaigns
+
+-- ============================================================================
+-- ENUMS
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE policy_ack_campaign_status AS ENUM (
+        'active',
+        'completed',
+        'superseded',
+        'cancelled'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+COMMENT ON TYPE policy_ack_campaign_status IS 'superseded = a later version of the policy was published with its own campaign';
+
+DO $$ BEGIN
+    CREATE TYPE policy_ack_status AS ENUM (
+        'pending',
+        'acknowledged',
+        'superseded',
+        'cancelled'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+-- ============================================================================
+-- CAMPAIGNS (one per published policy version)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS policy_ack_campaigns (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    policy_id               UUID NOT NULL REFERENCES policies(id) ON DELETE CASCADE,
+    policy_version_id       UUID NOT NULL REFERENCES policy_versions(id) ON DELETE CASCADE,
+    version_number          INT NOT NULL,
+
+    -- Audience, resolved to individual acknowledgements when the campaign is created
+    all_users               BOOLEAN NOT NULL DEFAULT FALSE,
+    audience_roles          TEXT[] NOT NULL DEFAULT '{}',
+    audience_user_ids       UUID[] NOT NULL DEFAULT '{}',
+
+    status                  policy_ack_campaign_status NOT NULL DEFAULT 'active',
+    due_date                DATE,
+
+    -- Automatic reminders for pending acknowledgements (NULL = manual only)
+    reminder_interval_days  INT CHECK (reminder_interval_days IS NULL OR reminder_interval_days > 0),
+
+    -- Latest acknowledgement roster stored as evidence for the policy's controls
+    evidence_artifact_id    UUID REFERENCES evidence_artifacts(id) ON DELETE SET NULL,
+
+    created_by              UUID REFERENCES users(id) ON DELETE SET NULL,
+    completed_at            TIMESTAMPTZ,
+    cancelled_at            TIMESTAMPTZ,
+
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_policy_ack_audience CHECK (
+        all_users OR cardinality(audience_roles) > 0 OR cardinality(audience_user_ids) > 0
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_ack_campaigns_policy
+    ON policy_ack_campaigns (policy_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_policy_ack_campaigns_active
+    ON policy_ack_campaigns (org_id)
+    WHERE status = 'active';
+
+DROP TRIGGER IF EXISTS trg_policy_ack_campaigns_updated_at ON policy_ack_campaigns;
+CREATE TRIGGER trg_policy_ack_campaigns_updated_at
+    BEFORE UPDATE ON policy_ack_campaigns
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE policy_ack_campaigns IS 'Workforce "read and understood" attestation for a published policy version';
+
+-- ============================================================================
+-- ACKNOWLEDGEMENTS (one per user per campaign)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS policy_acknowledgements (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    campaign_id             UUID NOT NULL REFERENCES policy_ack_campaigns(id) ON DELETE CASCADE,
+    policy_id               UUID NOT NULL REFERENCES policies(id) ON DELETE CASCADE,
+    policy_version_id       UUID NOT NULL REFERENCES policy_versions(id) ON DELETE CASCADE,
+    user_id                 UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    status                  policy_ack_status NOT NULL DEFAULT 'pending',
+    statement               TEXT,
+    acknowledged_at         TIMESTAMPTZ,
+    ip_address              VARCHAR(45),
+    user_agent              TEXT,
+
+    -- Notification tracking
+    reminder_sent_at        TIMESTAMPTZ,
+    reminder_count          INT NOT NULL DEFAULT 0,
+
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_policy_acknowledgement UNIQUE (campaign_id, user_id),
+    CONSTRAINT chk_policy_ack_response CHECK (
+        status <> 'acknowledged' OR (statement IS NOT NULL AND acknowledged_at IS NOT NULL)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_acknowledgements_campaign
+    ON policy_acknowledgements (campaign_id, status);
+
+CREATE INDEX IF NOT EXISTS idx_policy_acknowledgements_user
+    ON policy_acknowledgements (user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_policy_acknowledgements_user_pending
+    ON policy_acknowledgements (user_id)
+    WHERE status = 'pending';
+
+DROP TRIGGER IF EXISTS trg_policy_acknowledgements_updated_at ON policy_acknowledgements;
+CREATE TRIGGER trg_policy_acknowledgements_updated_at
+    BEFORE UPDATE ON policy_acknowledgements
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE policy_acknowledgements IS 'A user''s acknowledgement of a specific policy version; kept as per-user history';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_ack_campaign.created'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_ack_campaign.cancelled'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_ack_campaign.completed'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_ack_campaign.evidence_generated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy.acknowledged'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
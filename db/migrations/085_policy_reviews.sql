@@ -0,0 +1,97 @@
+-- Migration: 085_policy_reviews.sql
+-- Description: Scheduled policy review tasks, re-approval and overdue escalation
+-- Created: 2026-10-18
+-- Feature: Policy review cycles
+
+-- ============================================================================
+-- ENUM
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE policy_review_status AS ENUM (
+        'open',
+        'in_approval',
+        'revising',
+        'completed',
+        'cancelled'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+COMMENT ON TYPE policy_review_status IS 'in_approval = owner confirmed no changes and sign-offs are pending; revising = owner started a new draft';
+
+-- ============================================================================
+-- POLICY REVIEWS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS policy_reviews (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    policy_id           UUID NOT NULL REFERENCES policies(id) ON DELETE CASCADE,
+
+    status              policy_review_status NOT NULL DEFAULT 'open',
+    outcome             VARCHAR(20) CHECK (outcome IN ('no_changes', 'revised')),
+    assigned_to         UUID REFERENCES users(id) ON DELETE SET NULL,
+    due_at              DATE NOT NULL,
+
+    -- The version created when the owner acted on the review
+    review_version_id   UUID REFERENCES policy_versions(id) ON DELETE SET NULL,
+    started_by          UUID REFERENCES users(id) ON DELETE SET NULL,
+    started_at          TIMESTAMPTZ,
+    notes               TEXT,
+
+    -- Overdue escalation: 1 = overdue (owner alerted), 2 = escalated to the CISO
+    escalation_level    INT NOT NULL DEFAULT 0 CHECK (escalation_level BETWEEN 0 AND 2),
+    escalated_at        TIMESTAMPTZ,
+
+    completed_at        TIMESTAMPTZ,
+
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_policy_review_started CHECK (
+        status NOT IN ('in_approval', 'revising') OR (started_at IS NOT NULL AND outcome IS NOT NULL)
+    )
+);
+
+-- At most one unfinished review per policy.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_policy_reviews_active
+    ON policy_reviews (policy_id)
+    WHERE status IN ('open', 'in_approval', 'revising');
+
+CREATE INDEX IF NOT EXISTS idx_policy_reviews_org
+    ON policy_reviews (org_id, status, due_at);
+
+CREATE INDEX IF NOT EXISTS idx_policy_reviews_assignee
+    ON policy_reviews (assigned_to)
+    WHERE status IN ('open', 'in_approval', 'revising');
+
+DROP TRIGGER IF EXISTS trg_policy_reviews_updated_at ON policy_reviews;
+CREATE TRIGGER trg_policy_reviews_updated_at
+    BEFORE UPDATE ON policy_reviews
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE policy_reviews IS 'Periodic review task opened ahead of policies.next_review_at; completed when the policy is republished';
+COMMENT ON COLUMN policy_reviews.due_at IS 'The policy''s next_review_at when the review was opened';
+
+-- ============================================================================
+-- ALERTS: policy review source
+-- ============================================================================
+
+DO $$ BEGIN
+    ALTER TABLE alerts ADD COLUMN policy_id UUID REFERENCES policies(id) ON DELETE CASCADE;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_policy ON alerts (policy_id)
+    WHERE policy_id IS NOT NULL;
+
+COMMENT ON COLUMN alerts.policy_id IS 'Policy whose overdue review raised this alert (raised on a control the policy covers)';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_review.no_changes'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_review.revision_started'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_review.updated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
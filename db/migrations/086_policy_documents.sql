@@ -0,0 +1,60 @@
+-- Migration: 086_policy_documents.sql
+-- Description: Branded PDF/HTML renditions of policy versions stored as evidence
+-- Created: 2026-10-18
+-- Feature: Policy document rendering
+
+-- ============================================================================
+-- POLICY VERSIONS: effective date
+-- ============================================================================
+
+DO $$ BEGIN
+    ALTER TABLE policy_versions ADD COLUMN published_at TIMESTAMPTZ;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+COMMENT ON COLUMN policy_versions.published_at IS 'When this version was published (its effective date); set once';
+
+-- Only the current version's publish time was tracked before this migration.
+UPDATE policy_versions pv SET published_at = p.published_at
+FROM policies p
+WHERE p.current_version_id = pv.id
+    AND p.published_at IS NOT NULL
+    AND pv.published_at IS NULL;
+
+-- ============================================================================
+-- POLICY DOCUMENTS
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE policy_document_format AS ENUM ('pdf', 'html');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS policy_documents (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    policy_id               UUID NOT NULL REFERENCES policies(id) ON DELETE CASCADE,
+    policy_version_id       UUID NOT NULL REFERENCES policy_versions(id) ON DELETE CASCADE,
+    version_number          INT NOT NULL,
+    format                  policy_document_format NOT NULL,
+
+    -- Each format of a policy is one evidence version chain
+    evidence_artifact_id    UUID NOT NULL REFERENCES evidence_artifacts(id) ON DELETE CASCADE,
+
+    rendered_by             UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_documents_policy
+    ON policy_documents (policy_id, format, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_policy_documents_org
+    ON policy_documents (org_id);
+
+COMMENT ON TABLE policy_documents IS 'Rendered PDF/HTML of a policy version; the file lives in the linked policy_document evidence artifact';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy.document_rendered'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
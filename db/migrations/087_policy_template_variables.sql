@@ -0,0 +1,107 @@
+-- Migration: 087_policy_template_variables.sql
+-- Description: Typed template variables resolved from org settings or supplied at clone time
+-- Created: 2026-10-18
+-- Feature: Policy template variables
+
+-- ============================================================================
+-- TEMPLATE VARIABLES
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE policy_variable_type AS ENUM ('text', 'user', 'duration', 'cadence', 'number');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS policy_template_variables (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    template_id             UUID NOT NULL REFERENCES policies(id) ON DELETE CASCADE,
+    key                     VARCHAR(63) NOT NULL,
+    label                   VARCHAR(255) NOT NULL,
+    var_type                policy_variable_type NOT NULL DEFAULT 'text',
+    description             TEXT,
+    default_value           TEXT,
+    required                BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order              INT NOT NULL DEFAULT 0,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_policy_template_variable UNIQUE (template_id, key),
+    CONSTRAINT chk_policy_template_variable_key CHECK (key ~ '^[a-z][a-z0-9_]*$')
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_template_variables_org
+    ON policy_template_variables (org_id);
+
+DROP TRIGGER IF EXISTS trg_policy_template_variables_updated_at ON policy_template_variables;
+CREATE TRIGGER trg_policy_template_variables_updated_at
+    BEFORE UPDATE ON policy_template_variables
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE policy_template_variables IS 'Variables a template declares; written {{key}} in its content';
+
+-- ============================================================================
+-- POLICY VARIABLE VALUES
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS policy_variable_values (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    policy_id               UUID NOT NULL REFERENCES policies(id) ON DELETE CASCADE,
+    key                     VARCHAR(63) NOT NULL,
+    value                   TEXT NOT NULL,
+    display_value           TEXT NOT NULL,
+    source                  VARCHAR(20) NOT NULL,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_policy_variable_value UNIQUE (policy_id, key),
+    CONSTRAINT chk_policy_variable_source CHECK (source IN ('clone', 'org_setting', 'default'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_variable_values_org_key
+    ON policy_variable_values (org_id, key);
+
+DROP TRIGGER IF EXISTS trg_policy_variable_values_updated_at ON policy_variable_values;
+CREATE TRIGGER trg_policy_variable_values_updated_at
+    BEFORE UPDATE ON policy_variable_values
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE policy_variable_values IS 'Values substituted into a policy cloned from a template';
+COMMENT ON COLUMN policy_variable_values.source IS 'clone = supplied when cloning (kept); org_setting/default = re-resolved on regeneration';
+
+-- ============================================================================
+-- POLICIES: template source
+-- ============================================================================
+
+DO $$ BEGIN
+    ALTER TABLE policies ADD COLUMN template_source_version_id UUID REFERENCES policy_versions(id) ON DELETE SET NULL;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+COMMENT ON COLUMN policies.template_source_version_id IS 'Template version this policy was rendered from; drafts can be regenerated from it';
+
+-- ============================================================================
+-- SEED TEMPLATES: placeholder syntax
+-- ============================================================================
+
+UPDATE policy_versions pv SET content = REPLACE(pv.content, '[Organization Name]', '{{company_name}}')
+FROM policies p
+WHERE p.id = pv.policy_id
+    AND p.is_template = TRUE
+    AND pv.content LIKE '%[Organization Name]%';
+
+INSERT INTO policy_template_variables (org_id, template_id, key, label, var_type, required)
+SELECT p.org_id, p.id, 'company_name', 'Company name', 'text', TRUE
+FROM policies p
+JOIN policy_versions pv ON pv.id = p.current_version_id
+WHERE p.is_template = TRUE
+    AND pv.content LIKE '%{{company_name}}%'
+ON CONFLICT (template_id, key) DO NOTHING;
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_template.variables_updated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy.variables_regenerated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,96 @@
+-- Migration: 088_policy_exceptions.sql
+-- Description: Approved, time-boxed exceptions to published policies with compensating controls
+-- Created: 2026-10-18
+-- Feature: Policy exceptions and waivers
+
+-- ============================================================================
+-- ENUMS
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE policy_exception_status AS ENUM ('requested', 'approved', 'rejected', 'expired', 'revoked');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+-- ============================================================================
+-- POLICY EXCEPTIONS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS policy_exceptions (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    policy_id               UUID NOT NULL REFERENCES policies(id) ON DELETE CASCADE,
+    title                   VARCHAR(255) NOT NULL,
+    justification           TEXT NOT NULL,
+
+    -- What the exception covers: named assets/systems and/or users
+    affected_assets         TEXT[] NOT NULL DEFAULT '{}',
+    affected_user_ids       UUID[] NOT NULL DEFAULT '{}',
+
+    status                  policy_exception_status NOT NULL DEFAULT 'requested',
+    requested_by            UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    -- Designated approver; when NULL any CISO or compliance manager may decide
+    approver_id             UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at              DATE NOT NULL,
+
+    decided_by              UUID REFERENCES users(id) ON DELETE SET NULL,
+    decided_at              TIMESTAMPTZ,
+    decision_comments       TEXT,
+    revoked_by              UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at              TIMESTAMPTZ,
+    revocation_reason       TEXT,
+    expired_at              TIMESTAMPTZ,
+
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_policy_exception_scope CHECK (
+        cardinality(affected_assets) > 0 OR cardinality(affected_user_ids) > 0
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_exceptions_policy
+    ON policy_exceptions (policy_id, status);
+
+CREATE INDEX IF NOT EXISTS idx_policy_exceptions_org_status
+    ON policy_exceptions (org_id, status, expires_at);
+
+CREATE INDEX IF NOT EXISTS idx_policy_exceptions_approved_expiry
+    ON policy_exceptions (expires_at)
+    WHERE status = 'approved';
+
+DROP TRIGGER IF EXISTS trg_policy_exceptions_updated_at ON policy_exceptions;
+CREATE TRIGGER trg_policy_exceptions_updated_at
+    BEFORE UPDATE ON policy_exceptions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE policy_exceptions IS 'Exception register: approved deviations from a policy, each with an expiry date';
+COMMENT ON COLUMN policy_exceptions.expires_at IS 'Last day the exception applies; the sweep marks it expired the day after';
+
+-- ============================================================================
+-- COMPENSATING CONTROLS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS policy_exception_controls (
+    exception_id            UUID NOT NULL REFERENCES policy_exceptions(id) ON DELETE CASCADE,
+    control_id              UUID NOT NULL REFERENCES controls(id) ON DELETE CASCADE,
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (exception_id, control_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_exception_controls_control
+    ON policy_exception_controls (control_id);
+
+COMMENT ON TABLE policy_exception_controls IS 'Controls that compensate for the risk a policy exception accepts';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_exception.requested'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_exception.updated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_exception.approved'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_exception.rejected'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy_exception.revoked'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,13 @@
+-- Migration: 089_policy_import.sql
+-- Description: Audit action for policies imported from DOCX and Markdown documents
+-- Created: 2026-10-18
+-- Feature: Policy import
+
+-- Imported policies are ordinary drafts; the source file, version and section outline are
+-- kept under metadata->'import' on the policy.
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'policy.imported'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,76 @@
+-- Migration: 090_risk_quantification.sql
+-- Description: FAIR-style quantitative risk models with seeded Monte Carlo results
+-- Created: 2026-10-18
+-- Feature: Quantitative risk analysis
+
+-- ============================================================================
+-- ENUMS
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE risk_loss_distribution AS ENUM ('pert', 'lognormal');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+-- ============================================================================
+-- RISK QUANTIFICATIONS
+-- ============================================================================
+
+-- One model per risk. Loss event frequency is events per year; loss magnitude is the cost of a
+-- single event in the model's currency. The stored seed replays the simulation exactly.
+CREATE TABLE IF NOT EXISTS risk_quantifications (
+    id                          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                      UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    risk_id                     UUID NOT NULL REFERENCES risks(id) ON DELETE CASCADE,
+    currency                    CHAR(3) NOT NULL DEFAULT 'USD',
+
+    frequency_min               NUMERIC(10,4) NOT NULL,
+    frequency_most_likely       NUMERIC(10,4) NOT NULL,
+    frequency_max               NUMERIC(10,4) NOT NULL,
+    frequency_distribution      risk_loss_distribution NOT NULL DEFAULT 'pert',
+
+    magnitude_min               NUMERIC(18,2) NOT NULL,
+    magnitude_most_likely       NUMERIC(18,2) NOT NULL,
+    magnitude_max               NUMERIC(18,2) NOT NULL,
+    magnitude_distribution      risk_loss_distribution NOT NULL DEFAULT 'lognormal',
+
+    iterations                  INT NOT NULL DEFAULT 10000,
+    seed                        BIGINT NOT NULL,
+    notes                       TEXT,
+
+    -- Latest simulation
+    annualized_loss_expectancy  NUMERIC(18,2) NOT NULL,
+    results                     JSONB NOT NULL DEFAULT '{}',
+    simulated_at                TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    created_by                  UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_by                  UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at                  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at                  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_risk_quantifications_risk UNIQUE (risk_id),
+    CONSTRAINT chk_risk_quant_frequency CHECK (
+        frequency_min >= 0 AND frequency_min <= frequency_most_likely AND frequency_most_likely <= frequency_max
+    ),
+    CONSTRAINT chk_risk_quant_magnitude CHECK (
+        magnitude_min >= 0 AND magnitude_min <= magnitude_most_likely AND magnitude_most_likely <= magnitude_max
+    ),
+    CONSTRAINT chk_risk_quant_iterations CHECK (iterations BETWEEN 1000 AND 100000)
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_quantifications_org
+    ON risk_quantifications (org_id, annualized_loss_expectancy DESC);
+
+DROP TRIGGER IF EXISTS trg_risk_quantifications_updated_at ON risk_quantifications;
+CREATE TRIGGER trg_risk_quantifications_updated_at
+    BEFORE UPDATE ON risk_quantifications
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE risk_quantifications IS 'FAIR loss model per risk with the summary of its latest Monte Carlo simulation';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'risk.quantified'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'risk.quantification_removed'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,103 @@
+-- Migration: 091_risk_scoring_methodologies.sql
+-- Description: Versioned per-organization risk scoring methodologies (scales, weighted impact dimensions, severity bands)
+-- Created: 2026-10-18
+-- Feature: Configurable risk scoring
+
+-- ============================================================================
+-- RISK SCORING METHODOLOGIES
+-- ============================================================================
+
+-- Each save creates a new immutable version; one version per org is active. Organizations with
+-- no rows score with the built-in 5x5 matrix, recorded on assessments as version 0.
+CREATE TABLE IF NOT EXISTS risk_scoring_methodologies (
+    id                          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                      UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    version                     INT NOT NULL,
+    name                        VARCHAR(255) NOT NULL,
+    description                 TEXT,
+
+    -- [{key, label, score, description}] ordered lowest to highest
+    likelihood_levels           JSONB NOT NULL,
+    impact_levels               JSONB NOT NULL,
+    -- [{key, label, weight}]
+    impact_dimensions           JSONB NOT NULL DEFAULT '[]',
+    -- [{severity, label, min_score}] ordered critical to low
+    severity_bands              JSONB NOT NULL,
+
+    is_active                   BOOLEAN NOT NULL DEFAULT FALSE,
+    activated_at                TIMESTAMPTZ,
+    created_by                  UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at                  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_risk_methodology_version UNIQUE (org_id, version),
+    CONSTRAINT chk_risk_methodology_version CHECK (version >= 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_risk_methodology_active
+    ON risk_scoring_methodologies (org_id) WHERE is_active = TRUE;
+
+COMMENT ON TABLE risk_scoring_methodologies IS 'Versioned risk scoring methodologies; assessments keep the version they were scored with';
+
+-- ============================================================================
+-- ASSESSMENTS RECORD THEIR METHODOLOGY
+-- ============================================================================
+
+ALTER TABLE risk_assessments
+    ADD COLUMN IF NOT EXISTS methodology_id UUID REFERENCES risk_scoring_methodologies(id) ON DELETE RESTRICT,
+    ADD COLUMN IF NOT EXISTS methodology_version INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS impact_ratings JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_risk_assessments_methodology ON risk_assessments (methodology_id)
+    WHERE methodology_id IS NOT NULL;
+
+-- ============================================================================
+-- FREE-FORM SCALE KEYS AND WIDER SCORE RANGES
+-- ============================================================================
+
+-- Level keys come from the methodology rather than the likelihood_level / impact_level enums.
+ALTER TABLE risks
+    ALTER COLUMN inherent_likelihood TYPE VARCHAR(50) USING inherent_likelihood::text,
+    ALTER COLUMN inherent_impact TYPE VARCHAR(50) USING inherent_impact::text,
+    ALTER COLUMN residual_likelihood TYPE VARCHAR(50) USING residual_likelihood::text,
+    ALTER COLUMN residual_impact TYPE VARCHAR(50) USING residual_impact::text;
+
+ALTER TABLE risk_assessments
+    ALTER COLUMN likelihood TYPE VARCHAR(50) USING likelihood::text,
+    ALTER COLUMN impact TYPE VARCHAR(50) USING impact::text,
+    -- Weighted impact dimensions produce fractional impact scores
+    ALTER COLUMN impact_score TYPE NUMERIC(5,2);
+
+ALTER TABLE risk_treatments
+    ALTER COLUMN expected_residual_likelihood TYPE VARCHAR(50) USING expected_residual_likelihood::text,
+    ALTER COLUMN expected_residual_impact TYPE VARCHAR(50) USING expected_residual_impact::text;
+
+-- Scales go up to 10 levels scored 1-10, so overall scores range 1-100.
+ALTER TABLE risk_assessments DROP CONSTRAINT IF EXISTS chk_ra_likelihood_score;
+ALTER TABLE risk_assessments DROP CONSTRAINT IF EXISTS chk_ra_impact_score;
+ALTER TABLE risk_assessments DROP CONSTRAINT IF EXISTS chk_ra_overall_score;
+ALTER TABLE risk_assessments
+    ADD CONSTRAINT chk_ra_likelihood_score CHECK (likelihood_score >= 1 AND likelihood_score <= 10),
+    ADD CONSTRAINT chk_ra_impact_score CHECK (impact_score >= 1 AND impact_score <= 10),
+    ADD CONSTRAINT chk_ra_overall_score CHECK (overall_score >= 1 AND overall_score <= 100);
+
+ALTER TABLE risks DROP CONSTRAINT IF EXISTS chk_inherent_score;
+ALTER TABLE risks DROP CONSTRAINT IF EXISTS chk_residual_score;
+ALTER TABLE risks DROP CONSTRAINT IF EXISTS chk_appetite_threshold;
+ALTER TABLE risks
+    ADD CONSTRAINT chk_inherent_score CHECK (inherent_score IS NULL OR (inherent_score >= 1 AND inherent_score <= 100)),
+    ADD CONSTRAINT chk_residual_score CHECK (residual_score IS NULL OR (residual_score >= 1 AND residual_score <= 100)),
+    ADD CONSTRAINT chk_appetite_threshold CHECK (risk_appetite_threshold IS NULL OR (risk_appetite_threshold >= 1 AND risk_appetite_threshold <= 100));
+
+ALTER TABLE risk_treatments DROP CONSTRAINT IF EXISTS chk_rt_expected_residual_score;
+ALTER TABLE risk_treatments
+    ADD CONSTRAINT chk_rt_expected_residual_score CHECK (
+        expected_residual_score IS NULL OR
+        (expected_residual_score >= 1 AND expected_residual_score <= 100)
+    );
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'risk_methodology.created'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'risk_methodology.activated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
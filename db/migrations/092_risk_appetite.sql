@@ -0,0 +1,110 @@
+-- Migration: 092_risk_appetite.sql
+-- Description: Org and category risk appetite / tolerance thresholds, appetite positions and breach alerts
+-- Created: 2026-10-18
+-- Feature: Risk appetite framework
+
+-- ============================================================================
+-- RISK APPETITE STATEMENTS
+-- ============================================================================
+
+-- One organization-wide statement (category NULL) plus optional per-category overrides.
+-- Appetite is the residual score the org is willing to carry; tolerance is the hard ceiling.
+CREATE TABLE IF NOT EXISTS risk_appetite_statements (
+    id                          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                      UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    category                    risk_category,
+
+    appetite_threshold          NUMERIC(5,2) NOT NULL,
+    tolerance_threshold         NUMERIC(5,2) NOT NULL,
+    statement                   TEXT,
+
+    updated_by                  UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at                  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at                  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_risk_appetite_thresholds CHECK (
+        appetite_threshold >= 1 AND tolerance_threshold <= 100 AND tolerance_threshold >= appetite_threshold
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_risk_appetite_org
+    ON risk_appetite_statements (org_id) WHERE category IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_risk_appetite_category
+    ON risk_appetite_statements (org_id, category) WHERE category IS NOT NULL;
+
+DROP TRIGGER IF EXISTS trg_risk_appetite_statements_updated_at ON risk_appetite_statements;
+CREATE TRIGGER trg_risk_appetite_statements_updated_at
+    BEFORE UPDATE ON risk_appetite_statements
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE risk_appetite_statements IS 'Risk appetite and tolerance per organization (category NULL) or per risk category';
+
+-- ============================================================================
+-- APPETITE POSITIONS
+-- ============================================================================
+
+-- Effective appetite per risk: the risk's own threshold, else its category's, else the org's.
+-- Tolerance comes from the category, else the org, and never sits below the effective appetite.
+CREATE OR REPLACE VIEW risk_appetite_positions AS
+SELECT p.*,
+       CASE
+           WHEN p.appetite_threshold IS NULL THEN 'no_appetite'
+           WHEN p.residual_score IS NULL THEN 'not_assessed'
+           WHEN p.residual_score > p.tolerance_threshold THEN 'above_tolerance'
+           WHEN p.residual_score > p.appetite_threshold THEN 'above_appetite'
+           ELSE 'within_appetite'
+       END AS position
+FROM (
+    SELECT r.id AS risk_id,
+           r.org_id,
+           r.category,
+           r.status,
+           r.residual_score,
+           COALESCE(r.risk_appetite_threshold, cat.appetite_threshold, org.appetite_threshold) AS appetite_threshold,
+           GREATEST(COALESCE(cat.tolerance_threshold, org.tolerance_threshold),
+                    COALESCE(r.risk_appetite_threshold, cat.appetite_threshold, org.appetite_threshold)) AS tolerance_threshold,
+           CASE
+               WHEN r.risk_appetite_threshold IS NOT NULL THEN 'risk'
+               WHEN cat.id IS NOT NULL THEN 'category'
+               WHEN org.id IS NOT NULL THEN 'organization'
+           END AS appetite_source
+    FROM risks r
+    LEFT JOIN risk_appetite_statements cat ON cat.org_id = r.org_id AND cat.category = r.category
+    LEFT JOIN risk_appetite_statements org ON org.org_id = r.org_id AND org.category IS NULL
+    WHERE r.is_template = FALSE
+) p;
+
+COMMENT ON VIEW risk_appetite_positions IS 'Each risk''s effective appetite and tolerance and where its residual score sits against them';
+
+-- ============================================================================
+-- ALERTS: risk appetite source
+-- ============================================================================
+
+DO $$ BEGIN
+    ALTER TABLE alerts ADD COLUMN risk_id UUID REFERENCES risks(id) ON DELETE CASCADE;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_risk ON alerts (risk_id)
+    WHERE risk_id IS NOT NULL;
+
+-- Risk alerts carry the risk's first linked control when it has one; every alert still has a source.
+ALTER TABLE alerts ALTER COLUMN control_id DROP NOT NULL;
+
+DO $$ BEGIN
+    ALTER TABLE alerts
+        ADD CONSTRAINT chk_alerts_source
+        CHECK (control_id IS NOT NULL OR risk_id IS NOT NULL);
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+COMMENT ON COLUMN alerts.risk_id IS 'Risk whose appetite breach raised this alert';
+COMMENT ON COLUMN alerts.control_id IS 'Control the alert is raised on; NULL only for risk alerts on risks without linked controls';
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'risk_appetite.updated'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'risk_appetite.deleted'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,89 @@
+-- Migration: 093_risk_acceptances.sql
+-- Description: Acceptance history per risk, renewal requests and expiry of lapsed acceptances
+-- Created: 2026-10-18
+-- Feature: Risk acceptance expiry and renewal
+
+-- ============================================================================
+-- ENUMS
+-- ============================================================================
+
+DO $$ BEGIN
+    CREATE TYPE risk_acceptance_status AS ENUM (
+        'requested', 'active', 'rejected', 'superseded', 'expired', 'revoked', 'cancelled'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+-- ============================================================================
+-- RISK ACCEPTANCES
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS risk_acceptances (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id                  UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    risk_id                 UUID NOT NULL REFERENCES risks(id) ON DELETE CASCADE,
+    -- The acceptance a renewal extends; NULL for the first acceptance of a risk
+    renewal_of              UUID REFERENCES risk_acceptances(id) ON DELETE SET NULL,
+
+    status                  risk_acceptance_status NOT NULL DEFAULT 'requested',
+    justification           TEXT NOT NULL,
+    expires_at              DATE NOT NULL,
+
+    requested_by            UUID REFERENCES users(id) ON DELETE SET NULL,
+    requested_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_by              UUID REFERENCES users(id) ON DELETE SET NULL,
+    decided_at              TIMESTAMPTZ,
+    decision_comments       TEXT,
+
+    -- Appetite position and residual score when the acceptance was approved
+    appetite_position       VARCHAR(20),
+    residual_score          NUMERIC(5,2),
+
+    ended_by                UUID REFERENCES users(id) ON DELETE SET NULL,
+    ended_at                TIMESTAMPTZ,
+
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_acceptances_risk
+    ON risk_acceptances (risk_id, requested_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_risk_acceptances_org_status
+    ON risk_acceptances (org_id, status, expires_at);
+
+-- A risk has at most one acceptance in force and one renewal awaiting a decision
+CREATE UNIQUE INDEX IF NOT EXISTS uq_risk_acceptances_active
+    ON risk_acceptances (risk_id)
+    WHERE status = 'active';
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_risk_acceptances_requested
+    ON risk_acceptances (risk_id)
+    WHERE status = 'requested';
+
+DROP TRIGGER IF EXISTS trg_risk_acceptances_updated_at ON risk_acceptances;
+CREATE TRIGGER trg_risk_acceptances_updated_at
+    BEFORE UPDATE ON risk_acceptances
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE risk_acceptances IS 'Acceptance history per risk: every acceptance and renewal request with its approval and how it ended';
+COMMENT ON COLUMN risk_acceptances.expires_at IS 'Last day the acceptance applies; the sweep reopens the risk the day after';
+
+-- Seed the history with acceptances recorded before it existed
+INSERT INTO risk_acceptances (org_id, risk_id, status, justification, expires_at,
+    requested_by, requested_at, decided_by, decided_at)
+SELECT r.org_id, r.id, 'active', r.acceptance_justification, r.acceptance_expiry,
+    r.accepted_by, COALESCE(r.accepted_at, r.updated_at), r.accepted_by, COALESCE(r.accepted_at, r.updated_at)
+FROM risks r
+WHERE r.status = 'accepted'
+    AND r.acceptance_justification IS NOT NULL
+    AND r.acceptance_expiry IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM risk_acceptances a WHERE a.risk_id = r.id);
+
+-- ============================================================================
+-- AUDIT ACTIONS
+-- ============================================================================
+
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'risk_acceptance.renewal_requested'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'risk_acceptance.renewed'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'risk_acceptance.renewal_rejected'; EXCEPTION WHEN duplicate_object THEN NULL; END $$;
@@ -0,0 +1,40 @@
+-- Migration: 094_alert_sources.sql
+-- Description: Let alerts be raised on a policy, evidence artifact or risk without a linked control
+-- Created: 2026-10-18
+-- Feature: Alerts for policies, evidence and risks without controls
+
+-- ============================================================================
+-- ALERTS: source columns
+-- ============================================================================
+
+-- Added by 080, 085 and 092; repeated here so the constraint below never depends on them.
+DO $$ BEGIN
+    ALTER TABLE alerts ADD COLUMN policy_id UUID REFERENCES policies(id) ON DELETE CASCADE;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE alerts ADD COLUMN evidence_artifact_id UUID REFERENCES evidence_artifacts(id) ON DELETE CASCADE;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    ALTER TABLE alerts ADD COLUMN risk_id UUID REFERENCES risks(id) ON DELETE CASCADE;
+EXCEPTION WHEN duplicate_column THEN NULL;
+END $$;
+
+ALTER TABLE alerts ALTER COLUMN control_id DROP NOT NULL;
+
+-- ============================================================================
+-- ALERTS: source constraint
+-- ============================================================================
+
+-- Every alert still has something to point at, but that need not be a control.
+ALTER TABLE alerts DROP CONSTRAINT IF EXISTS chk_alerts_source;
+ALTER TABLE alerts
+    ADD CONSTRAINT chk_alerts_source
+    CHECK (num_nonnulls(control_id, risk_id, policy_id, evidence_artifact_id) >= 1);
+
+COMMENT ON COLUMN alerts.control_id IS 'Control the alert is raised on; NULL when the source policy, evidence or risk has no linked control';
+COMMENT ON COLUMN alerts.policy_id IS 'Policy whose overdue review or expiring exception raised this alert';
+COMMENT ON COLUMN alerts.evidence_artifact_id IS 'Evidence whose staleness raised this alert (one alert per linked control, or one unlinked alert)';